@@ -0,0 +1,190 @@
+// Package accounts consulta el perfil público de las cuentas monitoreadas
+// en X, Mastodon y Bluesky (seguidores, seguidos, publicaciones y
+// biografía), para registrarlo a diario como serie de tiempo.
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"go-collector/config"
+	"go-collector/crawler"
+)
+
+// Profile es el estado de una cuenta en el momento de la consulta.
+type Profile struct {
+	Platform  string
+	Handle    string
+	Name      string
+	Bio       string
+	Followers int
+	Following int
+	Posts     int
+}
+
+// Client consulta los perfiles. XToken es el bearer token de la API de X;
+// Mastodon y Bluesky no piden credencial para los perfiles públicos.
+type Client struct {
+	HTTP   *http.Client
+	XToken string
+	// Endpoints de X y de la AppView pública de Bluesky; se pueden
+	// reemplazar para pruebas. El de Mastodon es la instancia de la cuenta.
+	XEndpoint       string
+	BlueskyEndpoint string
+}
+
+func NewClient(xToken string) *Client {
+	return &Client{
+		HTTP:            &http.Client{Timeout: 30 * time.Second},
+		XToken:          xToken,
+		XEndpoint:       "https://api.twitter.com/2/users/by/username/",
+		BlueskyEndpoint: "https://public.api.bsky.app/xrpc/app.bsky.actor.getProfile",
+	}
+}
+
+// Fetch consulta el perfil de la cuenta en su plataforma.
+func (c *Client) Fetch(ctx context.Context, acc config.Account) (*Profile, error) {
+	handle := strings.TrimPrefix(strings.TrimSpace(acc.Handle), "@")
+	var (
+		p   *Profile
+		err error
+	)
+	switch acc.Platform {
+	case "x":
+		p, err = c.x(ctx, handle)
+	case "mastodon":
+		p, err = c.mastodon(ctx, handle)
+	case "bluesky":
+		p, err = c.bluesky(ctx, handle)
+	default:
+		return nil, fmt.Errorf("plataforma desconocida: %s", acc.Platform)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error consultando %s/%s: %w", acc.Platform, acc.Handle, err)
+	}
+	p.Platform, p.Handle = acc.Platform, acc.Handle
+	return p, nil
+}
+
+func (c *Client) x(ctx context.Context, username string) (*Profile, error) {
+	if c.XToken == "" {
+		return nil, fmt.Errorf("falta el bearer token de X (sources.x)")
+	}
+	var resp struct {
+		Data struct {
+			Name          string `json:"name"`
+			Description   string `json:"description"`
+			PublicMetrics struct {
+				Followers int `json:"followers_count"`
+				Following int `json:"following_count"`
+				Tweets    int `json:"tweet_count"`
+			} `json:"public_metrics"`
+		} `json:"data"`
+		Errors []struct {
+			Detail string `json:"detail"`
+		} `json:"errors"`
+	}
+	params := url.Values{}
+	params.Set("user.fields", "name,description,public_metrics")
+	if err := c.get(ctx, c.XEndpoint+url.PathEscape(username)+"?"+params.Encode(), "Bearer "+c.XToken, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("error de la API de X: %s", resp.Errors[0].Detail)
+	}
+	d := resp.Data
+	return &Profile{Name: d.Name, Bio: d.Description, Followers: d.PublicMetrics.Followers,
+		Following: d.PublicMetrics.Following, Posts: d.PublicMetrics.Tweets}, nil
+}
+
+// mastodon consulta la cuenta (usuario@instancia) en su propia instancia.
+func (c *Client) mastodon(ctx context.Context, handle string) (*Profile, error) {
+	user, instance, ok := strings.Cut(handle, "@")
+	if !ok || instance == "" {
+		return nil, fmt.Errorf("handle sin instancia (ej: udea@mastodon.social)")
+	}
+	var resp struct {
+		DisplayName string `json:"display_name"`
+		Note        string `json:"note"` // HTML
+		Followers   int    `json:"followers_count"`
+		Following   int    `json:"following_count"`
+		Statuses    int    `json:"statuses_count"`
+	}
+	params := url.Values{}
+	params.Set("acct", user)
+	if err := c.get(ctx, "https://"+instance+"/api/v1/accounts/lookup?"+params.Encode(), "", &resp); err != nil {
+		return nil, err
+	}
+	return &Profile{Name: resp.DisplayName, Bio: htmlText(resp.Note), Followers: resp.Followers,
+		Following: resp.Following, Posts: resp.Statuses}, nil
+}
+
+func (c *Client) bluesky(ctx context.Context, handle string) (*Profile, error) {
+	var resp struct {
+		DisplayName string `json:"displayName"`
+		Description string `json:"description"`
+		Followers   int    `json:"followersCount"`
+		Follows     int    `json:"followsCount"`
+		Posts       int    `json:"postsCount"`
+	}
+	params := url.Values{}
+	params.Set("actor", handle)
+	if err := c.get(ctx, c.BlueskyEndpoint+"?"+params.Encode(), "", &resp); err != nil {
+		return nil, err
+	}
+	return &Profile{Name: resp.DisplayName, Bio: resp.Description, Followers: resp.Followers,
+		Following: resp.Follows, Posts: resp.Posts}, nil
+}
+
+func (c *Client) get(ctx context.Context, u, auth string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error HTTP %d: %s", resp.StatusCode, crawler.Preview(body))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error parseando JSON: %w. Respuesta recibida:\n%s", err, crawler.Preview(body))
+	}
+	return nil
+}
+
+// htmlText convierte la biografía HTML de Mastodon en texto, un párrafo por
+// línea.
+func htmlText(html string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return html
+	}
+	doc.Find("br").ReplaceWithHtml("\n")
+	var lines []string
+	doc.Find("p").Each(func(_ int, s *goquery.Selection) {
+		lines = append(lines, strings.TrimSpace(s.Text()))
+	})
+	if len(lines) == 0 {
+		return strings.TrimSpace(doc.Text())
+	}
+	return strings.Join(lines, "\n")
+}
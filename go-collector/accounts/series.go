@@ -0,0 +1,50 @@
+package accounts
+
+import (
+	"time"
+
+	"go-collector/storage"
+)
+
+// Series es la evolución diaria de una cuenta.
+type Series struct {
+	Platform  string
+	Handle    string
+	Snapshots []storage.AccountSnapshot // en orden cronológico
+	// BioChanges son los días en que la biografía cambió respecto del
+	// registro anterior.
+	BioChanges []BioChange
+}
+
+// BioChange es un cambio de biografía: From era la del registro anterior.
+type BioChange struct {
+	Day      time.Time
+	From, To string
+}
+
+// Group separa los registros (ordenados por cuenta y día, como los devuelve
+// storage.AccountSnapshots) en una serie por cuenta.
+func Group(snaps []storage.AccountSnapshot) []Series {
+	var out []Series
+	for _, s := range snaps {
+		if n := len(out); n == 0 || out[n-1].Platform != s.Platform || out[n-1].Handle != s.Handle {
+			out = append(out, Series{Platform: s.Platform, Handle: s.Handle})
+		}
+		cur := &out[len(out)-1]
+		if n := len(cur.Snapshots); n > 0 && cur.Snapshots[n-1].Bio != s.Bio {
+			cur.BioChanges = append(cur.BioChanges, BioChange{Day: s.Day, From: cur.Snapshots[n-1].Bio, To: s.Bio})
+		}
+		cur.Snapshots = append(cur.Snapshots, s)
+	}
+	return out
+}
+
+// First y Last son el primer y el último registro de la serie.
+func (s Series) First() storage.AccountSnapshot { return s.Snapshots[0] }
+func (s Series) Last() storage.AccountSnapshot  { return s.Snapshots[len(s.Snapshots)-1] }
+
+// FollowersDelta es cuántos seguidores ganó (o perdió) la cuenta en la serie.
+func (s Series) FollowersDelta() int { return s.Last().Followers - s.First().Followers }
+
+// FollowingDelta es la variación de las cuentas que sigue.
+func (s Series) FollowingDelta() int { return s.Last().Following - s.First().Following }
@@ -0,0 +1,590 @@
+// Package api sirve el corpus por HTTP para otras herramientas: artículos en
+// los formatos de export, el estado del corpus y el historial de rondas.
+// Solo lectura, salvo desactivar y reactivar fuentes: la recolección sigue
+// siendo "collector collect".
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/buildinfo"
+	"go-collector/config"
+	"go-collector/export"
+	"go-collector/grafana"
+	"go-collector/stats"
+	"go-collector/storage"
+	"go-collector/trends"
+)
+
+// contentTypes son los tipos MIME de los formatos de export conocidos; los
+// demás salen como texto.
+var contentTypes = map[string]string{
+	"jsonl": "application/x-ndjson",
+	"json":  "application/json",
+	"csv":   "text/csv; charset=utf-8",
+}
+
+// Server atiende la API.
+type Server struct {
+	Store *storage.Store
+	// Token, si no está vacío, se exige como "Authorization: Bearer <token>".
+	Token string
+	// Stamp va en los encabezados de todas las respuestas, para saber qué
+	// versión y qué configuración las produjeron.
+	Stamp buildinfo.Stamp
+}
+
+// Handler devuelve las rutas:
+//
+//	GET /articles            artículos (format, columns, from, to, source, lang, q, all, limit, offset)
+//	GET /stats               estado del corpus
+//	GET /stats/sources       artículos por fuente (from, to, source, lang, q, limit, offset)
+//	GET /stats/sections      artículos por sección (from, to, source, lang, q, limit, offset)
+//	GET /trends              palabras clave que suben y bajan esta semana (min, limit)
+//	GET /runs                historial de rondas (since, until, status, campaign, limit)
+//	GET /runs/{id}           una ronda con el detalle por fuente
+//	GET /runs/{id}/articles  artículos que trajo la ronda
+//	GET /events              eventos con su cobertura en el corpus (since, until, min, limit)
+//	GET /events/{uri}/articles  artículos del corpus de un evento
+//	GET /version             versión del recolector y hash de la configuración
+//	GET /sources             fuentes desactivadas
+//	POST /sources/{name}/disable  desactiva una fuente (reason, for); requiere token
+//	POST /sources/{name}/enable   reactiva una fuente; requiere token
+//	/grafana/                datasource JSON de Grafana (ver collector grafana)
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "OK")
+	})
+	mux.HandleFunc("GET /articles", s.handleArticles)
+	mux.HandleFunc("GET /stats", s.handleStats)
+	mux.HandleFunc("GET /stats/sources", s.handleStatsBy("source"))
+	mux.HandleFunc("GET /stats/sections", s.handleStatsBy("section"))
+	mux.HandleFunc("GET /trends", s.handleTrends)
+	mux.HandleFunc("GET /runs", s.handleRuns)
+	mux.HandleFunc("GET /runs/{id}", s.handleRun)
+	mux.HandleFunc("GET /runs/{id}/articles", s.handleRunArticles)
+	mux.HandleFunc("GET /events", s.handleEvents)
+	mux.HandleFunc("GET /events/{uri}/articles", s.handleEventArticles)
+	mux.HandleFunc("GET /version", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.Stamp)
+	})
+	mux.HandleFunc("GET /sources", s.handleHolds)
+	mux.HandleFunc("POST /sources/{name}/disable", s.admin(s.handleDisable))
+	mux.HandleFunc("POST /sources/{name}/enable", s.admin(s.handleEnable))
+	// El token ya se verifica acá: el datasource no lo vuelve a pedir.
+	g := &grafana.Server{Store: s.Store, Stamp: s.Stamp}
+	mux.Handle("/grafana/", http.StripPrefix("/grafana", g.Handler()))
+	return s.auth(mux)
+}
+
+func (s *Server) auth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Stamp.SetHeaders(w.Header())
+		if s.Token != "" && !authorized(r, s.Token) {
+			http.Error(w, "no autorizado", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorized compara el encabezado con el token en tiempo constante, para
+// que la demora de la respuesta no revele cuánto del token se acertó.
+func authorized(r *http.Request, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) == 1
+}
+
+// admin protege una ruta que modifica el corpus: sin token configurado
+// cualquiera podría usarla, así que se rechaza.
+func (s *Server) admin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.Token == "" {
+			http.Error(w, "las rutas de administración requieren un token (collector serve --token)", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleArticles(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, err := parseFilter(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	format := q.Get("format")
+	if format == "" {
+		format = "jsonl"
+	}
+	var opts export.Options
+	if cols := q.Get("columns"); cols != "" {
+		opts.Columns = strings.Split(cols, ",")
+	}
+	ew, err := export.New(format, w, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ct, ok := contentTypes[format]
+	if !ok {
+		ct = "text/plain; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", ct)
+	if filter.Limit > 0 {
+		if err := s.setPageHeaders(w, r, filter); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Los artículos salen a medida que se leen, con lo que sus términos de
+	// uso permiten redistribuir: un error a mitad ya no puede cambiar el
+	// código de respuesta, solo cortar la salida.
+	_, err = s.Store.EachShareable(filter, func(a *article.Article) error {
+		if err := r.Context().Err(); err != nil {
+			return err
+		}
+		return ew.Write(a)
+	})
+	if err == nil {
+		err = ew.Close()
+	}
+	if err != nil && r.Context().Err() == nil {
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// parseFilter arma el filtro de artículos de los parámetros de la consulta.
+func parseFilter(q url.Values) (storage.Filter, error) {
+	f := storage.Filter{Language: q.Get("lang"), Query: q.Get("q"), IncludeWithdrawn: q.Get("all") == "1" || q.Get("all") == "true"}
+	var err error
+	if f.From, err = parseDay(q.Get("from"), false); err != nil {
+		return f, err
+	}
+	if f.To, err = parseDay(q.Get("to"), true); err != nil {
+		return f, err
+	}
+	if src := q.Get("source"); src != "" {
+		f.Sources = strings.Split(src, ",")
+	}
+	if l := q.Get("limit"); l != "" {
+		if f.Limit, err = strconv.Atoi(l); err != nil || f.Limit < 0 {
+			return f, fmt.Errorf("limit inválido: %q", l)
+		}
+	}
+	if o := q.Get("offset"); o != "" {
+		if f.Offset, err = strconv.Atoi(o); err != nil || f.Offset < 0 {
+			return f, fmt.Errorf("offset inválido: %q", o)
+		}
+	}
+	return f, nil
+}
+
+// setPageHeaders agrega a una página de artículos el total que cumple el
+// filtro (X-Total-Count) y, si quedan más, el enlace a la siguiente página
+// (Link rel="next"), para recorrer el corpus sin pedirlo entero.
+func (s *Server) setPageHeaders(w http.ResponseWriter, r *http.Request, f storage.Filter) error {
+	counts, err := s.Store.CountFiltered(r.Context(), f, storage.ScanOptions{})
+	if err != nil {
+		return err
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(counts.Total))
+	if next := f.Offset + f.Limit; next < counts.Total {
+		u := *r.URL
+		q := u.Query()
+		q.Set("offset", strconv.Itoa(next))
+		u.RawQuery = q.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, u.RequestURI()))
+	}
+	return nil
+}
+
+// parseDay interpreta una fecha AAAA-MM-DD; con end, el final de ese día.
+func parseDay(v string, end bool) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return t, fmt.Errorf("fecha inválida %q (use AAAA-MM-DD)", v)
+	}
+	if end {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	o, err := stats.Summarize(r.Context(), s.Store, storage.ScanOptions{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, o)
+}
+
+// groupStat son los artículos de una fuente o sección (ver storage.StatsBy).
+type groupStat struct {
+	Source        string     `json:"source,omitempty"`
+	Section       *string    `json:"section,omitempty"`
+	Articles      int        `json:"articles"`
+	Withdrawn     int        `json:"withdrawn"`
+	First         *time.Time `json:"first_published,omitempty"`
+	Last          *time.Time `json:"last_published,omitempty"`
+	LastCollected *time.Time `json:"last_collected,omitempty"`
+}
+
+// handleStatsBy responde los artículos agrupados por column, con los filtros
+// de /articles; limit y offset paginan los grupos. La sección puede ser
+// vacía (artículos sin sección), así que siempre sale en /stats/sections.
+func (s *Server) handleStatsBy(column string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, err := parseFilter(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		groups, err := s.Store.StatsBy(column, f)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		out := make([]groupStat, 0, len(groups))
+		for _, g := range groups {
+			st := groupStat{Articles: g.Articles, Withdrawn: g.Withdrawn}
+			if column == "section" {
+				st.Section = &g.Key
+			} else {
+				st.Source = g.Key
+			}
+			if !g.First.IsZero() {
+				st.First, st.Last = &g.First, &g.Last
+			}
+			if !g.LastCollected.IsZero() {
+				st.LastCollected = &g.LastCollected
+			}
+			out = append(out, st)
+		}
+		writeJSON(w, out)
+	}
+}
+
+// trendList son las palabras clave en tendencia a At (ver collector trends).
+type trendList struct {
+	At      time.Time      `json:"at"`
+	Rising  []trends.Trend `json:"rising"`
+	Falling []trends.Trend `json:"falling"`
+}
+
+func (s *Server) handleTrends(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var opts trends.Options
+	var err error
+	if m := q.Get("min"); m != "" {
+		if opts.MinScore, err = strconv.ParseFloat(m, 64); err != nil || opts.MinScore < 0 {
+			http.Error(w, fmt.Sprintf("min inválido: %q", m), http.StatusBadRequest)
+			return
+		}
+	}
+	if l := q.Get("limit"); l != "" {
+		if opts.Limit, err = strconv.Atoi(l); err != nil || opts.Limit < 0 {
+			http.Error(w, fmt.Sprintf("limit inválido: %q", l), http.StatusBadRequest)
+			return
+		}
+	}
+	terms, err := s.Store.TrendTerms(1)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	out := trendList{At: time.Now().UTC(), Rising: []trends.Trend{}, Falling: []trends.Trend{}}
+	rising, falling := trends.Compute(terms, out.At, opts)
+	out.Rising = append(out.Rising, rising...)
+	out.Falling = append(out.Falling, falling...)
+	writeJSON(w, out)
+}
+
+func (s *Server) handleRuns(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := storage.RunFilter{Status: q.Get("status"), Campaign: q.Get("campaign"), Limit: 50}
+	var err error
+	if f.Since, err = parseDay(q.Get("since"), false); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if f.Until, err = parseDay(q.Get("until"), true); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if l := q.Get("limit"); l != "" {
+		if f.Limit, err = strconv.Atoi(l); err != nil || f.Limit < 0 {
+			http.Error(w, fmt.Sprintf("limit inválido: %q", l), http.StatusBadRequest)
+			return
+		}
+	}
+	runs, err := s.Store.ListRuns(f)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	out := make([]*stats.RunInfo, 0, len(runs))
+	for _, run := range runs {
+		out = append(out, stats.NewRunInfo(run))
+	}
+	writeJSON(w, out)
+}
+
+// runDetail es una ronda con el detalle por fuente.
+type runDetail struct {
+	ID         int64       `json:"id"`
+	Started    time.Time   `json:"started"`
+	Finished   *time.Time  `json:"finished,omitempty"`
+	Campaign   string      `json:"campaign,omitempty"`
+	ConfigHash string      `json:"config_hash"`
+	Version    string      `json:"version,omitempty"`
+	Commit     string      `json:"commit,omitempty"`
+	Status     string      `json:"status"`
+	Error      string      `json:"error,omitempty"`
+	RetryOf    int64       `json:"retry_of,omitempty"`
+	Sources    []runSource `json:"sources"`
+	Failures   []failure   `json:"failures"`
+}
+
+type runSource struct {
+	Source     string `json:"source"`
+	Fetched    int    `json:"fetched"`
+	Stored     int    `json:"stored"`
+	Duplicates int    `json:"duplicates"`
+	Partial    bool   `json:"partial,omitempty"`
+	Error      string `json:"error,omitempty"`
+	BadDates   int    `json:"bad_dates,omitempty"`
+	Skipped    bool   `json:"skipped,omitempty"`
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	run, ok := s.run(w, r)
+	if !ok {
+		return
+	}
+	d := runDetail{
+		ID: run.ID, Started: run.Started, Campaign: run.Campaign,
+		ConfigHash: run.ConfigHash, Version: run.Version, Commit: run.Commit, Status: run.Status, Error: run.Err, RetryOf: run.RetryOf,
+		Sources: []runSource{}, Failures: []failure{},
+	}
+	if !run.Finished.IsZero() {
+		d.Finished = &run.Finished
+	}
+	for _, src := range run.Sources {
+		d.Sources = append(d.Sources, runSource{src.Source, src.Fetched, src.Stored, src.Duplicates, src.Partial, src.Err, src.BadDates, src.Skipped})
+	}
+	failures, err := s.Store.RunFailures(run.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, f := range failures {
+		d.Failures = append(d.Failures, failure{f.Source, f.Item, f.Err, f.RetriedBy})
+	}
+	writeJSON(w, d)
+}
+
+// failure es una consulta que falló en la ronda (ver collector retry-failures).
+type failure struct {
+	Source    string `json:"source"`
+	Item      string `json:"item,omitempty"`
+	Error     string `json:"error"`
+	RetriedBy int64  `json:"retried_by,omitempty"`
+}
+
+// lineage es un artículo traído por una ronda y la consulta que lo devolvió.
+type lineage struct {
+	ArticleID int64  `json:"article_id"`
+	Title     string `json:"title"`
+	Source    string `json:"source"`
+	URL       string `json:"url"`
+	Request   string `json:"request,omitempty"`
+}
+
+func (s *Server) handleRunArticles(w http.ResponseWriter, r *http.Request) {
+	run, ok := s.run(w, r)
+	if !ok {
+		return
+	}
+	items, err := s.Store.RunArticles(run.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	out := make([]lineage, 0, len(items))
+	for _, l := range items {
+		out = append(out, lineage{l.ArticleID, l.Title, l.Source, l.URL, l.Request})
+	}
+	writeJSON(w, out)
+}
+
+// run busca la ronda de la ruta; si no puede, responde el error y devuelve false.
+func (s *Server) run(w http.ResponseWriter, r *http.Request) (*storage.Run, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "id de ronda inválido", http.StatusBadRequest)
+		return nil, false
+	}
+	run, err := s.Store.GetRun(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, fmt.Sprintf("no existe la ronda %d", id), http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return run, true
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := storage.EventFilter{Limit: 50}
+	var err error
+	if f.Since, err = parseDay(q.Get("since"), false); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if f.Until, err = parseDay(q.Get("until"), true); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if m := q.Get("min"); m != "" {
+		if f.MinStored, err = strconv.Atoi(m); err != nil || f.MinStored < 0 {
+			http.Error(w, fmt.Sprintf("min inválido: %q", m), http.StatusBadRequest)
+			return
+		}
+	}
+	if l := q.Get("limit"); l != "" {
+		if f.Limit, err = strconv.Atoi(l); err != nil || f.Limit < 0 {
+			http.Error(w, fmt.Sprintf("limit inválido: %q", l), http.StatusBadRequest)
+			return
+		}
+	}
+	events, err := s.Store.Events(f)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if events == nil {
+		events = []storage.EventCoverage{}
+	}
+	writeJSON(w, events)
+}
+
+func (s *Server) handleEventArticles(w http.ResponseWriter, r *http.Request) {
+	uri := r.PathValue("uri")
+	articles, err := s.Store.EventArticles(uri)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	articles, _ = s.Store.ShareAll(articles)
+	if len(articles) == 0 {
+		http.Error(w, fmt.Sprintf("no hay artículos del evento %s", uri), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, articles)
+}
+
+// hold es una fuente desactivada.
+type hold struct {
+	Source string     `json:"source"`
+	Reason string     `json:"reason,omitempty"`
+	Actor  string     `json:"actor,omitempty"`
+	Since  time.Time  `json:"since"`
+	Until  *time.Time `json:"until,omitempty"`
+}
+
+func newHold(h storage.SourceHold) hold {
+	out := hold{Source: h.Source, Reason: h.Reason, Actor: h.Actor, Since: h.Since}
+	if !h.Until.IsZero() {
+		out.Until = &h.Until
+	}
+	return out
+}
+
+func (s *Server) handleHolds(w http.ResponseWriter, r *http.Request) {
+	holds, err := s.Store.SourceHolds(time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	out := make([]hold, 0, len(holds))
+	for _, h := range holds {
+		out = append(out, newHold(h))
+	}
+	writeJSON(w, out)
+}
+
+// handleDisable desactiva la fuente de la ruta hasta reactivarla o, con for
+// (ej: 2h, 3d), durante ese lapso. reason queda en el informe de las rondas.
+func (s *Server) handleDisable(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if new(config.Sources).Get(name) == nil {
+		http.Error(w, "fuente desconocida: "+name, http.StatusNotFound)
+		return
+	}
+	h := storage.SourceHold{Source: name, Reason: r.FormValue("reason"), Actor: apiActor(r), Since: time.Now()}
+	if v := r.FormValue("for"); v != "" {
+		span, err := config.ParseSpan(v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("for inválido: %v", err), http.StatusBadRequest)
+			return
+		}
+		h.Until = h.Since.Add(span)
+	}
+	if err := s.Store.HoldSource(h); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := s.Store.Audit(storage.AuditEntry{Actor: h.Actor, Action: storage.AuditSourceDisable, Target: name, Detail: h.Describe()}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, newHold(h))
+}
+
+func (s *Server) handleEnable(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	err := s.Store.ReleaseSource(name)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, fmt.Sprintf("la fuente %s no está desactivada", name), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := s.Store.Audit(storage.AuditEntry{Actor: apiActor(r), Action: storage.AuditSourceEnable, Target: name}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiActor identifica en la bitácora a quien usó la API: el token es uno
+// solo, así que se registra la dirección del cliente.
+func apiActor(r *http.Request) string {
+	return "api@" + r.RemoteAddr
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
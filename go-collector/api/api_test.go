@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-collector/article"
+	"go-collector/storage"
+)
+
+// /articles entrega solo lo que los términos de uso permiten redistribuir:
+// nada de los medios excluidos y sin el texto de los que solo se guardan.
+func TestArticlesTerms(t *testing.T) {
+	store, err := storage.Open(t.TempDir() + "/corpus.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	err = store.SyncPublishers([]storage.Publisher{
+		{Kind: "domain", Name: "eltiempo.com", TOS: "restricted", Text: storage.TextStore},
+		{Kind: "domain", Name: "semana.com", TOS: "restricted", Text: storage.TextShare, Exclude: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	published := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	for _, a := range []*article.Article{
+		{Source: "rss", Domain: "www.eltiempo.com", URL: "https://www.eltiempo.com/educacion/matriculas-udea", Title: "La UdeA abre matrículas", Body: "Texto completo de El Tiempo.", Published: published},
+		{Source: "rss", Domain: "www.semana.com", URL: "https://www.semana.com/nacion/paro-udea", Title: "Paro en la UdeA", Body: "Texto completo de Semana.", Published: published},
+		{Source: "rss", Domain: "udea.edu.co", URL: "https://www.udea.edu.co/noticias/calendario", Title: "Calendario académico", Body: "Texto completo de la UdeA.", Published: published},
+	} {
+		if err := store.SaveArticle(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	srv := httptest.NewServer((&Server{Store: store}).Handler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/articles?format=jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("estado %d", resp.StatusCode)
+	}
+	got := map[string]string{}
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		var a article.Article
+		if err := json.Unmarshal(sc.Bytes(), &a); err != nil {
+			t.Fatal(err)
+		}
+		got[a.Domain] = a.Body
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 {
+		t.Errorf("se entregaron %d artículos (%v), se esperaban 2", len(got), got)
+	}
+	if _, ok := got["www.semana.com"]; ok {
+		t.Error("se entregó el artículo de un medio excluido")
+	}
+	if body := got["www.eltiempo.com"]; body != "" {
+		t.Errorf("se entregó el texto de un medio que solo permite guardarlo: %q", body)
+	}
+	if body := got["udea.edu.co"]; body != "Texto completo de la UdeA." {
+		t.Errorf("texto del medio sin términos = %q", body)
+	}
+}
+
+// Con token, la API y la ruta de Grafana exigen exactamente "Bearer <token>".
+func TestAuth(t *testing.T) {
+	store, err := storage.Open(t.TempDir() + "/corpus.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	srv := httptest.NewServer((&Server{Store: store, Token: "secreto"}).Handler())
+	defer srv.Close()
+
+	for _, path := range []string{"/version", "/grafana/"} {
+		for header, want := range map[string]int{
+			"":               http.StatusUnauthorized,
+			"Bearer secretx": http.StatusUnauthorized,
+			"Bearer secret":  http.StatusUnauthorized,
+			"secreto":        http.StatusUnauthorized,
+			"Bearer secreto": http.StatusOK,
+		} {
+			req, _ := http.NewRequest("GET", srv.URL+path, nil)
+			if header != "" {
+				req.Header.Set("Authorization", header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != want {
+				t.Errorf("%s con %q: estado %d, se esperaba %d", path, header, resp.StatusCode, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package article
+
+import (
+	"time"
+
+	"go-collector/extract"
+)
+
+// Motivos por los que no se pudo extraer el texto completo de un artículo.
+const (
+	IssueConsentWall = "consent_wall"
+	IssueEmptyBody   = "empty_body"
+	IssueFetchFailed = "fetch_failed"
+	// IssueDescriptionOnly: no se encontró la nota y Body es solo la
+	// descripción de la página.
+	IssueDescriptionOnly = "description_only"
+)
+
+// Estados posibles de un artículo dentro del corpus.
+const (
+	StatusActive    = "active"
+	StatusWithdrawn = "withdrawn"
+)
+
+// Article es el modelo común en el que se normalizan los resultados de
+// todas las fuentes (GDELT, Guardian, NewsAPI, X, RSS...).
+type Article struct {
+	ID        int64     `json:"id"`
+	Source    string    `json:"source"`
+	URL       string    `json:"url"`
+	Title     string    `json:"title"`
+	Author    string    `json:"author,omitempty"`
+	Domain    string    `json:"domain,omitempty"`
+	Language  string    `json:"language,omitempty"`
+	Section   string    `json:"section,omitempty"`
+	Summary   string    `json:"summary,omitempty"`
+	Body      string    `json:"body,omitempty"`
+	Published time.Time `json:"published"`
+	Collected time.Time `json:"collected"`
+
+	// RawPublished es la fecha tal como la dio la fuente cuando no se pudo
+	// interpretar (ver dates.Normalize); Published queda en cero.
+	RawPublished string `json:"-"`
+
+	// DateIssue indica que la fecha que dio la fuente no podía ser la de
+	// publicación (dates.IssueFuture o dates.IssueBogus): Published es la de
+	// descarga y OriginalPublished la de la fuente. Se guardan aparte
+	// (storage.SetDateIssue).
+	DateIssue         string    `json:"-"`
+	OriginalPublished time.Time `json:"-"`
+
+	// TitleTranslated es el título traducido al inglés que da la fuente para
+	// los artículos en otros idiomas (GDELT con traducción); Title conserva
+	// siempre el original.
+	TitleTranslated string `json:"title_translated,omitempty"`
+
+	// EditionGroup agrupa las ediciones de una misma historia publicadas por el
+	// medio en distintos idiomas o regiones (0 si no está enlazada). El valor es
+	// el ID del primer artículo del grupo.
+	EditionGroup int64 `json:"edition_group,omitempty"`
+
+	// Canonical y Alternates son las ediciones que declara la página del
+	// artículo (<link rel="canonical"> y hreflang -> URL), en las fuentes que la
+	// descargan (sitemap, scrape). No se guardan: sirven para enlazar el
+	// artículo con las ediciones ya guardadas (editions.Linker).
+	Canonical  string            `json:"-"`
+	Alternates map[string]string `json:"-"`
+
+	// Explanation guarda cómo el filtro de relevancia evaluó el artículo, para
+	// documentar en la metodología cómo se seleccionó el corpus.
+	Explanation *Explanation `json:"explanation,omitempty"`
+
+	// Request describe la consulta a la fuente que trajo el artículo (ej: la
+	// URL del feed). No se guarda con el artículo: queda en el linaje de la
+	// ronda que lo recolectó (storage.Lineage).
+	Request string `json:"-"`
+
+	// Media son los archivos que la fuente asocia al artículo (enclosures de
+	// RSS, la imagen social de GDELT). Se guardan como adjuntos pendientes de
+	// descarga (ver collector media).
+	Media []Media `json:"media,omitempty"`
+
+	// Engagement es la interacción que informa la fuente al recolectar (redes
+	// sociales: X, Mastodon, Bluesky, YouTube); nil en las fuentes de noticias. Se
+	// guarda aparte, con la fecha en que se observó (storage.SetEngagement).
+	Engagement *Engagement `json:"engagement,omitempty"`
+
+	// Event es el acontecimiento que cubre el artículo según la fuente, si
+	// agrupa la cobertura (Event Registry); nil en las demás. Se guarda aparte
+	// (storage.SetEvent).
+	Event *Event `json:"event,omitempty"`
+
+	// Annotations son los temas y entidades que la plataforma reconoce en la
+	// publicación, con sus hashtags y menciones (X); Geo es el lugar con que
+	// se etiquetó. Se guardan aparte (storage.SetAnnotations, storage.SetGeo).
+	Annotations []Annotation `json:"annotations,omitempty"`
+	Geo         *Geo         `json:"geo,omitempty"`
+
+	// References son los retweets, citas y respuestas que enlazan la
+	// publicación con otras (X): las suyas propias y los retweets que la
+	// compartieron. Se guardan aparte (storage.AddReferences) y forman la red
+	// de difusión (collector network).
+	References []Reference `json:"references,omitempty"`
+
+	// WARC ubica la captura de la página en el archivo de Common Crawl, para
+	// leerla tal como estaba aunque el medio la cambie o la retire; nil en
+	// las demás fuentes. Se guarda aparte (storage.SetWARCRecord).
+	WARC *WARCRecord `json:"warc,omitempty"`
+
+	// Extraction es cómo se sacó Body de la página, en las fuentes que la
+	// descargan (sitemap, scrape): la estrategia, su evaluación y las que se
+	// probaron. Se guarda aparte (storage.SaveExtraction).
+	Extraction *extract.Result `json:"-"`
+
+	// ExtractionIssue explica por qué Body está vacío (ej: muro de consentimiento).
+	ExtractionIssue string `json:"extraction_issue,omitempty"`
+
+	// Status indica si el artículo sigue publicado o fue retirado por el medio.
+	// Los artículos retirados nunca se borran: se conservan con la fecha en que
+	// se detectó la baja y el motivo.
+	Status          string     `json:"status"`
+	WithdrawnAt     *time.Time `json:"withdrawn_at,omitempty"`
+	WithdrawnReason string     `json:"withdrawn_reason,omitempty"`
+}
+
+// Media es un archivo asociado a un artículo. Type es el tipo MIME que declara
+// la fuente, a menudo vacío o incorrecto: el real se detecta al descargarlo.
+type Media struct {
+	URL  string `json:"url"`
+	Type string `json:"type,omitempty"`
+}
+
+// Engagement son los contadores de interacción de una publicación.
+type Engagement struct {
+	Likes   int `json:"likes"`
+	Reposts int `json:"reposts"`
+	Replies int `json:"replies"`
+	Quotes  int `json:"quotes"`
+	// Views son las reproducciones (solo YouTube).
+	Views int `json:"views,omitempty"`
+}
+
+// Event es un acontecimiento con la cobertura agrupada por la fuente: los
+// artículos de distintos medios e idiomas sobre lo mismo comparten URI.
+// Articles es el total de artículos del evento en la fuente, no solo los que
+// llegaron al corpus.
+type Event struct {
+	URI      string `json:"uri"`
+	Title    string `json:"title,omitempty"`
+	Summary  string `json:"summary,omitempty"`
+	Date     string `json:"date,omitempty"` // AAAA-MM-DD
+	Articles int    `json:"articles,omitempty"`
+}
+
+// Clases de anotación.
+const (
+	AnnotationContext = "context" // tema asignado por la plataforma
+	AnnotationEntity  = "entity"  // entidad nombrada en el texto
+	AnnotationHashtag = "hashtag"
+	AnnotationMention = "mention"
+)
+
+// Annotation es un tema o una entidad de una publicación según la
+// plataforma. En los temas Domain es la clase de tema (ej: "Interests and
+// Hobbies Category") y Name el tema; en las entidades Domain es su tipo
+// (Person, Place, Organization) y Score la confianza.
+type Annotation struct {
+	Kind     string  `json:"kind"`
+	Domain   string  `json:"domain,omitempty"`
+	DomainID string  `json:"domain_id,omitempty"`
+	Name     string  `json:"name"`
+	ID       string  `json:"id,omitempty"`
+	Score    float64 `json:"score,omitempty"`
+}
+
+// Geo es el lugar con que se etiquetó una publicación: el de la plataforma
+// (Place, con su país en ISO 3166-1) y, si las informó, las coordenadas.
+type Geo struct {
+	PlaceID string   `json:"place_id,omitempty"`
+	Place   string   `json:"place,omitempty"`
+	Country string   `json:"country,omitempty"`
+	Lat     *float64 `json:"lat,omitempty"`
+	Lon     *float64 `json:"lon,omitempty"`
+}
+
+// Tipos de referencia entre publicaciones, como los nombra X.
+const (
+	RefRetweeted = "retweeted"
+	RefQuoted    = "quoted"
+	RefReplied   = "replied_to"
+)
+
+// Reference es una publicación (TweetID, de Author) que comparte, cita o
+// responde a otra (RefTweetID, de RefAuthor) en Created. Los autores son
+// nombres de usuario sin @; vacíos si la plataforma no los informó.
+type Reference struct {
+	TweetID    string    `json:"tweet_id"`
+	Author     string    `json:"author,omitempty"`
+	Kind       string    `json:"kind"`
+	RefTweetID string    `json:"ref_tweet_id"`
+	RefAuthor  string    `json:"ref_author,omitempty"`
+	Created    time.Time `json:"created"`
+}
+
+// WARCRecord es un registro del archivo de un rastreo de Common Crawl: la
+// captura está en Filename, desde el byte Offset, con Length bytes
+// (comprimidos).
+type WARCRecord struct {
+	Crawl    string    `json:"crawl"` // ej: CC-MAIN-2024-33
+	Filename string    `json:"filename"`
+	Offset   int64     `json:"offset"`
+	Length   int64     `json:"length"`
+	Digest   string    `json:"digest,omitempty"`
+	Captured time.Time `json:"captured"`
+}
+
+// Explanation son los componentes evaluados por el filtro de relevancia.
+type Explanation struct {
+	Relevant     bool     `json:"relevant"`
+	Score        float64  `json:"score"`
+	MatchedTerms []string `json:"matched_terms"`
+	RuleHits     []string `json:"rule_hits"`
+	Sentiment    *float64 `json:"sentiment"` // nil si no hay léxico para el idioma
+	SourceWeight float64  `json:"source_weight"`
+}
+
+// Withdrawn indica si el artículo fue marcado como retirado.
+func (a *Article) Withdrawn() bool {
+	return a.Status == StatusWithdrawn
+}
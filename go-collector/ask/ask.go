@@ -0,0 +1,152 @@
+package ask
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-collector/article"
+	"go-collector/embed"
+	"go-collector/storage"
+)
+
+// excerptChars limita el texto de cada artículo enviado al modelo.
+const excerptChars = 1200
+
+const systemPrompt = `Eres un asistente de análisis de medios de la Universidad de Antioquia.
+Responde la pregunta usando SOLO la información de los artículos entregados.
+Cita cada afirmación con el identificador del artículo entre corchetes, ej: [#12].
+Si los artículos no permiten responder, dilo explícitamente.
+Responde en el idioma de la pregunta.`
+
+var citationRe = regexp.MustCompile(`\[#(\d+)\]`)
+
+// Answer es la respuesta del modelo con las citas verificadas.
+type Answer struct {
+	Text      string
+	Cited     []*article.Article // artículos citados que sí estaban en el contexto
+	Invalid   []int64            // IDs citados que no estaban en el contexto (alucinaciones)
+	Retrieved []*article.Article // artículos enviados como contexto
+}
+
+// Asker recupera artículos relevantes y consulta al modelo de lenguaje.
+type Asker struct {
+	Store    *storage.Store
+	Embedder embed.Provider // nil = solo búsqueda por palabras clave
+	LLM      *ChatClient
+	K        int
+}
+
+// Ask recupera los K artículos más relevantes (embeddings y palabras clave) y
+// pide al modelo una respuesta con citas a los IDs de los artículos.
+func (a *Asker) Ask(ctx context.Context, question string) (*Answer, error) {
+	docs, err := a.retrieve(ctx, question)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no se encontraron artículos relacionados con la pregunta")
+	}
+
+	text, err := a.LLM.Complete(ctx, systemPrompt, buildPrompt(question, docs))
+	if err != nil {
+		return nil, err
+	}
+
+	ans := &Answer{Text: text, Retrieved: docs}
+	byID := make(map[int64]*article.Article, len(docs))
+	for _, d := range docs {
+		byID[d.ID] = d
+	}
+	seen := make(map[int64]bool)
+	for _, m := range citationRe.FindAllStringSubmatch(text, -1) {
+		id, _ := strconv.ParseInt(m[1], 10, 64)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if d, ok := byID[id]; ok {
+			ans.Cited = append(ans.Cited, d)
+		} else {
+			ans.Invalid = append(ans.Invalid, id)
+		}
+	}
+	return ans, nil
+}
+
+// retrieve combina resultados semánticos y por palabras clave, sin repetidos,
+// intercalándolos para que ambos métodos aporten al contexto.
+func (a *Asker) retrieve(ctx context.Context, question string) ([]*article.Article, error) {
+	var semantic []*article.Article
+	if a.Embedder != nil {
+		results, err := embed.Search(ctx, a.Store, a.Embedder, question, a.K)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			semantic = append(semantic, r.Article)
+		}
+	}
+	keyword, err := a.Store.SearchText(keywords(question), a.K)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*article.Article
+	seen := make(map[int64]bool)
+	for i := 0; len(out) < a.K && (i < len(semantic) || i < len(keyword)); i++ {
+		for _, list := range [][]*article.Article{semantic, keyword} {
+			if i < len(list) && !seen[list[i].ID] && len(out) < a.K {
+				seen[list[i].ID] = true
+				out = append(out, list[i])
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Published.Before(out[j].Published) })
+	return out, nil
+}
+
+func buildPrompt(question string, docs []*article.Article) string {
+	var b strings.Builder
+	b.WriteString("Artículos:\n\n")
+	for _, d := range docs {
+		text := d.Body
+		if text == "" {
+			text = d.Summary
+		}
+		if r := []rune(text); len(r) > excerptChars {
+			text = string(r[:excerptChars]) + "..."
+		}
+		fmt.Fprintf(&b, "[#%d] %s\nFuente: %s | Fecha: %s\n%s\n\n", d.ID, d.Title, d.Source, d.Published.Format("2006-01-02"), text)
+	}
+	fmt.Fprintf(&b, "Pregunta: %s\n", question)
+	return b.String()
+}
+
+// stopwords se quitan de la pregunta para la búsqueda por palabras clave, que
+// exige que aparezcan todas las palabras.
+var stopwords = map[string]bool{
+	"que": true, "qué": true, "de": true, "la": true, "el": true, "los": true, "las": true, "en": true,
+	"y": true, "a": true, "se": true, "del": true, "por": true, "con": true, "para": true, "un": true,
+	"una": true, "cómo": true, "como": true, "cuál": true, "cuáles": true, "sobre": true, "ha": true,
+	"han": true, "es": true, "son": true, "fue": true, "the": true, "what": true, "how": true, "of": true,
+	"in": true, "is": true, "are": true, "about": true, "did": true, "and": true, "to": true,
+}
+
+func keywords(question string) string {
+	var words []string
+	for _, w := range strings.Fields(strings.ToLower(question)) {
+		w = strings.Trim(w, "¿?¡!.,;:\"'()")
+		if len([]rune(w)) > 2 && !stopwords[w] {
+			words = append(words, w)
+		}
+	}
+	// Con demasiadas palabras la búsqueda conjuntiva no devuelve nada.
+	if len(words) > 3 {
+		words = words[:3]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,112 @@
+package ask
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-collector/config"
+)
+
+// ChatClient llama al endpoint /chat/completions de una API compatible con OpenAI.
+type ChatClient struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+	Client  *http.Client
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func NewChatClient(cfg config.LLM) *ChatClient {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	model := cfg.Model
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	return &ChatClient{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		APIKey:  cfg.APIKey,
+		Model:   model,
+		Client: &http.Client{
+			Timeout: 120 * time.Second,
+		},
+	}
+}
+
+// Complete envía el mensaje de sistema y el del usuario y devuelve la respuesta.
+func (c *ChatClient) Complete(ctx context.Context, system, user string) (string, error) {
+	payload, err := json.Marshal(chatRequest{
+		Model: c.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+		Temperature: 0.2,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+
+	var apiResp chatResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return "", fmt.Errorf("error parseando JSON: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg := string(body)
+		if apiResp.Error != nil {
+			msg = apiResp.Error.Message
+		}
+		return "", fmt.Errorf("error HTTP: status code %d: %s", resp.StatusCode, msg)
+	}
+	if len(apiResp.Choices) == 0 {
+		return "", fmt.Errorf("el modelo no devolvió respuesta")
+	}
+	return apiResp.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,247 @@
+// Package backup empaqueta la base de datos y el archivo de páginas crudas en
+// un tar.zst con manifiesto, y los restaura.
+//
+// Un respaldo completo lleva el snapshot de la base y todo el archivo crudo.
+// Uno incremental lleva el snapshot de la base (es un solo archivo y cambia
+// entero) y solo las páginas crudas nuevas desde el respaldo anterior: el
+// archivo crudo se direcciona por hash y nunca se modifica, así que basta con
+// lo agregado. Restaurar un incremental recorre la cadena hasta el completo.
+package backup
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"go-collector/buildinfo"
+	"go-collector/storage"
+)
+
+const (
+	KindFull        = "full"
+	KindIncremental = "incremental"
+
+	manifestName  = "manifest.json"
+	dbEntry       = "corpus.db"
+	archivePrefix = "archive/"
+)
+
+// Manifest describe el contenido de un respaldo.
+type Manifest struct {
+	Name    string    `json:"name"`
+	Kind    string    `json:"kind"`
+	Created time.Time `json:"created"`
+	// Base es el respaldo anterior del que depende un incremental.
+	Base      string          `json:"base,omitempty"`
+	Collector buildinfo.Stamp `json:"collector"`
+	Files     []File          `json:"files"`
+}
+
+// File es una entrada del respaldo con su tamaño y hash para verificarla al restaurar.
+type File struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Options indica qué respaldar y dónde.
+type Options struct {
+	Dir         string // directorio de respaldos
+	ArchiveDir  string // archivo de páginas crudas; vacío si no se usa
+	Incremental bool
+	// Collector identifica el binario y la configuración que hicieron el
+	// respaldo; queda en el manifiesto.
+	Collector buildinfo.Stamp
+}
+
+// Create escribe un respaldo nuevo en opts.Dir y devuelve su manifiesto. El
+// manifiesto va dentro del tar y también al lado, como <nombre>.manifest.json,
+// para encontrar el último respaldo sin descomprimir.
+func Create(store *storage.Store, opts Options, now time.Time) (*Manifest, error) {
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creando directorio de respaldos: %w", err)
+	}
+
+	m := &Manifest{Kind: KindFull, Created: now.UTC(), Collector: opts.Collector}
+	var since time.Time
+	if opts.Incremental {
+		last, err := Latest(opts.Dir)
+		if err != nil {
+			return nil, err
+		}
+		if last == nil {
+			return nil, fmt.Errorf("no hay respaldos previos en %s; haga primero uno completo", opts.Dir)
+		}
+		m.Kind, m.Base, since = KindIncremental, last.Name, last.Created
+	}
+	m.Name = fmt.Sprintf("backup-%s-%s", m.Created.Format("20060102-150405"), m.Kind)
+
+	tmpDir, err := os.MkdirTemp(opts.Dir, ".snapshot-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+	dbSnapshot := filepath.Join(tmpDir, dbEntry)
+	if err := store.Snapshot(dbSnapshot); err != nil {
+		return nil, err
+	}
+
+	// Las páginas crudas a incluir: todas, o las modificadas desde el último respaldo.
+	sources := map[string]string{dbEntry: dbSnapshot}
+	if opts.ArchiveDir != "" {
+		err := filepath.WalkDir(opts.ArchiveDir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() || strings.HasSuffix(path, ".tmp") {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if !since.IsZero() && !info.ModTime().After(since) {
+				return nil
+			}
+			rel, err := filepath.Rel(opts.ArchiveDir, path)
+			if err != nil {
+				return err
+			}
+			sources[archivePrefix+filepath.ToSlash(rel)] = path
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error recorriendo el archivo crudo: %w", err)
+		}
+	}
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f, err := hashFile(sources[name])
+		if err != nil {
+			return nil, err
+		}
+		f.Path = name
+		m.Files = append(m.Files, f)
+	}
+
+	target := filepath.Join(opts.Dir, m.Name+".tar.zst")
+	if err := writeTar(target, m, sources); err != nil {
+		os.Remove(target)
+		return nil, err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(opts.Dir, m.Name+".manifest.json"), data, 0o644); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func writeTar(target string, m *Manifest, sources map[string]string) error {
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("error creando respaldo: %w", err)
+	}
+	defer out.Close()
+	zw, err := zstd.NewWriter(out)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(zw)
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestName, Mode: 0o644, Size: int64(len(data)), ModTime: m.Created}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+
+	for _, f := range m.Files {
+		if err := addFile(tw, f, sources[f.Path], m.Created); err != nil {
+			return fmt.Errorf("error agregando %s al respaldo: %w", f.Path, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return out.Sync()
+}
+
+func addFile(tw *tar.Writer, f File, path string, modTime time.Time) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := tw.WriteHeader(&tar.Header{Name: f.Path, Mode: 0o644, Size: f.Size, ModTime: modTime}); err != nil {
+		return err
+	}
+	_, err = io.CopyN(tw, in, f.Size)
+	return err
+}
+
+func hashFile(path string) (File, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return File{}, err
+	}
+	defer in.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, in)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// List devuelve los manifiestos del directorio de respaldos, del más antiguo al más reciente.
+func List(dir string) ([]*Manifest, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.manifest.json"))
+	if err != nil {
+		return nil, err
+	}
+	var out []*Manifest
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		var m Manifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("manifiesto inválido %s: %w", p, err)
+		}
+		out = append(out, &m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Created.Before(out[j].Created) })
+	return out, nil
+}
+
+// Latest devuelve el respaldo más reciente, o nil si no hay ninguno.
+func Latest(dir string) (*Manifest, error) {
+	all, err := List(dir)
+	if err != nil || len(all) == 0 {
+		return nil, err
+	}
+	return all[len(all)-1], nil
+}
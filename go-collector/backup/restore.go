@@ -0,0 +1,211 @@
+package backup
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// RestoreOptions indica dónde dejar la base y el archivo crudo restaurados.
+type RestoreOptions struct {
+	DBPath     string
+	ArchiveDir string // vacío para no restaurar páginas crudas
+	Force      bool   // sobrescribir la base si ya existe
+}
+
+// Restore restaura el respaldo de path. Si es incremental, restaura primero
+// la cadena de respaldos en los que se basa (deben estar en el mismo
+// directorio). La base queda la del último respaldo; las páginas crudas son
+// la unión de toda la cadena. Cada archivo se verifica contra su hash.
+func Restore(path string, opts RestoreOptions) ([]*Manifest, error) {
+	if _, err := os.Stat(opts.DBPath); err == nil && !opts.Force {
+		return nil, fmt.Errorf("la base %s ya existe; use --force para sobrescribirla", opts.DBPath)
+	}
+
+	chain, err := resolveChain(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDB := opts.DBPath + ".restore"
+	defer os.Remove(tmpDB)
+	for i, file := range chain {
+		// Solo la base del último respaldo importa; las anteriores se omiten.
+		dbTarget := ""
+		if i == len(chain)-1 {
+			dbTarget = tmpDB
+		}
+		if _, err := extract(file, dbTarget, opts.ArchiveDir); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.Rename(tmpDB, opts.DBPath); err != nil {
+		return nil, fmt.Errorf("error dejando la base restaurada en %s: %w", opts.DBPath, err)
+	}
+
+	var manifests []*Manifest
+	for _, file := range chain {
+		m, err := readManifest(file)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, m)
+	}
+	return manifests, nil
+}
+
+// resolveChain sigue Base desde el respaldo pedido hasta el completo y
+// devuelve los archivos en orden de aplicación.
+func resolveChain(path string) ([]string, error) {
+	dir := filepath.Dir(path)
+	var chain []string
+	seen := make(map[string]bool)
+	for current := path; ; {
+		if seen[current] {
+			return nil, fmt.Errorf("cadena de respaldos circular en %s", current)
+		}
+		seen[current] = true
+		m, err := readManifest(current)
+		if err != nil {
+			return nil, err
+		}
+		chain = append([]string{current}, chain...)
+		if m.Kind == KindFull {
+			return chain, nil
+		}
+		if m.Base == "" {
+			return nil, fmt.Errorf("el respaldo incremental %s no indica su base", m.Name)
+		}
+		current = filepath.Join(dir, m.Base+".tar.zst")
+		if _, err := os.Stat(current); err != nil {
+			return nil, fmt.Errorf("falta el respaldo base %s: %w", m.Base, err)
+		}
+	}
+}
+
+func openTar(path string) (*tar.Reader, func(), error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error abriendo respaldo: %w", err)
+	}
+	zr, err := zstd.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("error abriendo respaldo %s: %w", path, err)
+	}
+	return tar.NewReader(zr), func() { zr.Close(); f.Close() }, nil
+}
+
+// readManifest lee el manifiesto, que es la primera entrada del tar.
+func readManifest(path string) (*Manifest, error) {
+	tr, closeFn, err := openTar(path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != manifestName {
+		return nil, fmt.Errorf("respaldo sin manifiesto: %s", path)
+	}
+	var m Manifest
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return nil, fmt.Errorf("manifiesto inválido en %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// extract restaura las entradas del tar verificando cada una contra el manifiesto.
+func extract(path, dbTarget, archiveDir string) (*Manifest, error) {
+	tr, closeFn, err := openTar(path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+
+	var m *Manifest
+	expected := make(map[string]File)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo respaldo %s: %w", path, err)
+		}
+
+		if hdr.Name == manifestName {
+			m = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(m); err != nil {
+				return nil, fmt.Errorf("manifiesto inválido en %s: %w", path, err)
+			}
+			for _, f := range m.Files {
+				expected[f.Path] = f
+			}
+			continue
+		}
+		want, ok := expected[hdr.Name]
+		if m == nil || !ok {
+			return nil, fmt.Errorf("entrada %s no está en el manifiesto de %s", hdr.Name, path)
+		}
+
+		var target string
+		switch {
+		case hdr.Name == dbEntry:
+			target = dbTarget
+		case strings.HasPrefix(hdr.Name, archivePrefix) && archiveDir != "":
+			rel := strings.TrimPrefix(hdr.Name, archivePrefix)
+			if !filepath.IsLocal(rel) {
+				return nil, fmt.Errorf("ruta inválida en el respaldo: %s", hdr.Name)
+			}
+			target = filepath.Join(archiveDir, filepath.FromSlash(rel))
+		}
+		if target == "" {
+			continue
+		}
+		if err := writeVerified(tr, target, want); err != nil {
+			return nil, fmt.Errorf("error restaurando %s de %s: %w", hdr.Name, path, err)
+		}
+		delete(expected, hdr.Name)
+	}
+	if m == nil {
+		return nil, fmt.Errorf("respaldo sin manifiesto: %s", path)
+	}
+	if dbTarget != "" {
+		if _, ok := expected[dbEntry]; ok {
+			return nil, fmt.Errorf("el respaldo %s no contiene la base de datos", path)
+		}
+	}
+	return m, nil
+}
+
+func writeVerified(r io.Reader, target string, want File) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	tmp := target + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(out, h), r)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && (n != want.Size || hex.EncodeToString(h.Sum(nil)) != want.SHA256) {
+		err = fmt.Errorf("el contenido no coincide con el manifiesto (archivo dañado)")
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, target)
+}
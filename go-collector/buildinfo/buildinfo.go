@@ -0,0 +1,146 @@
+// Package buildinfo identifica el código que produjo un artefacto del corpus
+// (exportaciones, manifiestos, reportes, respuestas de la API): la versión y
+// el commit del recolector, junto al hash de la configuración efectiva.
+//
+// La versión y el commit se fijan al compilar:
+//
+//	go build -ldflags "-X go-collector/buildinfo.Version=v1.4.0 -X go-collector/buildinfo.Commit=$(git rev-parse HEAD)" ./cmd/collector
+//
+// Sin ldflags, el commit se toma de la información de control de versiones
+// que go build agrega al compilar dentro del repositorio.
+package buildinfo
+
+import (
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// Fijadas con -ldflags "-X ...".
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+// Info es la versión del recolector en ejecución.
+type Info struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+	// Modified indica que se compiló con cambios sin commit: el commit no
+	// alcanza para reproducir el código.
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version,omitempty"`
+}
+
+var (
+	once    sync.Once
+	current Info
+)
+
+// Read devuelve la versión del recolector en ejecución.
+func Read() Info {
+	once.Do(func() {
+		current = Info{Version: Version, Commit: Commit}
+		bi, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		current.GoVersion = bi.GoVersion
+		var revision string
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				revision = s.Value
+			case "vcs.modified":
+				current.Modified = s.Value == "true"
+			}
+		}
+		if current.Commit == "" {
+			current.Commit = revision
+		} else if revision != "" && !strings.HasPrefix(revision, current.Commit) && !strings.HasPrefix(current.Commit, revision) {
+			// El commit de ldflags manda; el estado de los archivos solo vale
+			// si es el mismo código.
+			current.Modified = false
+		}
+	})
+	return current
+}
+
+// Stamp es la marca de origen de un artefacto: qué versión del recolector y
+// qué configuración lo produjeron.
+type Stamp struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	Modified   bool   `json:"modified,omitempty"`
+	ConfigHash string `json:"config_hash,omitempty"`
+}
+
+// NewStamp arma la marca con la versión en ejecución y el hash de la
+// configuración (config.Config.Hash; vacío si el comando no la usa).
+func NewStamp(configHash string) Stamp {
+	info := Read()
+	return Stamp{Version: info.Version, Commit: info.Commit, Modified: info.Modified, ConfigHash: configHash}
+}
+
+// Recorded rearma la marca de lo que se guardó en otro lado (ej: una ronda
+// del historial), con el commit como lo devuelve Revision.
+func Recorded(version, revision, configHash string) Stamp {
+	commit, modified := strings.CutSuffix(revision, "+")
+	return Stamp{Version: version, Commit: commit, Modified: modified, ConfigHash: configHash}
+}
+
+// Revision es el commit completo, con "+" si se compiló con cambios sin
+// commit.
+func (s Stamp) Revision() string {
+	if s.Commit != "" && s.Modified {
+		return s.Commit + "+"
+	}
+	return s.Commit
+}
+
+// ShortCommit es Revision con el commit abreviado a 12 caracteres.
+func (s Stamp) ShortCommit() string {
+	c := s.Commit
+	if len(c) > 12 {
+		c = c[:12]
+	}
+	if c != "" && s.Modified {
+		c += "+"
+	}
+	return c
+}
+
+// Label es la versión con el commit abreviado, ej: "v1.4.0 (3f2a9c1b7d4e)";
+// vacío si no hay versión (rondas anteriores a que se registrara).
+func (s Stamp) Label() string {
+	if s.Version == "" {
+		return ""
+	}
+	if c := s.ShortCommit(); c != "" {
+		return s.Version + " (" + c + ")"
+	}
+	return s.Version
+}
+
+// String es la marca en una línea, ej: "collector v1.4.0 (3f2a9c1b7d4e)
+// config 72e41010827e9aed".
+func (s Stamp) String() string {
+	out := "collector " + s.Label()
+	if s.ConfigHash != "" {
+		out += " config " + s.ConfigHash
+	}
+	return out
+}
+
+// SetHeaders agrega la marca a los encabezados de una respuesta HTTP
+// (X-Collector-Version, X-Collector-Commit y X-Collector-Config).
+func (s Stamp) SetHeaders(h http.Header) {
+	h.Set("X-Collector-Version", s.Version)
+	if c := s.Revision(); c != "" {
+		h.Set("X-Collector-Commit", c)
+	}
+	if s.ConfigHash != "" {
+		h.Set("X-Collector-Config", s.ConfigHash)
+	}
+}
@@ -0,0 +1,65 @@
+// Package cachestat cuenta los aciertos y fallos de las cachés que comparten
+// varias goroutines (las respuestas HTTP entre campañas, los enlaces
+// acortados), para saber si están sirviendo de algo.
+package cachestat
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Counter acumula los contadores de una caché. El valor cero está listo para
+// usar y sus métodos son seguros desde varias goroutines.
+type Counter struct {
+	hits, misses, shared, evicted atomic.Int64
+}
+
+// Hit cuenta una consulta respondida desde la caché.
+func (c *Counter) Hit() { c.hits.Add(1) }
+
+// Miss cuenta una consulta que tuvo que ir al origen.
+func (c *Counter) Miss() { c.misses.Add(1) }
+
+// Shared cuenta una consulta que esperó la de otra goroutine por la misma
+// clave en vez de repetirla.
+func (c *Counter) Shared() { c.shared.Add(1) }
+
+// Evict cuenta n entradas descartadas por vencidas.
+func (c *Counter) Evict(n int) { c.evicted.Add(int64(n)) }
+
+// Snapshot devuelve los contadores actuales con entries entradas vigentes.
+func (c *Counter) Snapshot(entries int) Stats {
+	return Stats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Shared:  c.shared.Load(),
+		Evicted: c.evicted.Load(),
+		Entries: entries,
+	}
+}
+
+// Stats es una foto de los contadores de una caché.
+type Stats struct {
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	Shared  int64 `json:"shared"`
+	Evicted int64 `json:"evicted"`
+	Entries int   `json:"entries"`
+}
+
+// Lookups es el total de consultas.
+func (s Stats) Lookups() int64 { return s.Hits + s.Misses + s.Shared }
+
+// HitRate es la proporción de consultas que no fueron al origen (aciertos y
+// compartidas); 0 sin consultas.
+func (s Stats) HitRate() float64 {
+	if s.Lookups() == 0 {
+		return 0
+	}
+	return float64(s.Hits+s.Shared) / float64(s.Lookups())
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("%d consultas, %d aciertos, %d compartidas, %d al origen (%.0f%% ahorrado), %d entradas, %d vencidas",
+		s.Lookups(), s.Hits, s.Shared, s.Misses, s.HitRate()*100, s.Entries, s.Evicted)
+}
@@ -0,0 +1,75 @@
+package chart
+
+import (
+	"sort"
+	"time"
+
+	"go-collector/storage"
+)
+
+// VolumeBySource arma la serie diaria de artículos por fuente. Los días sin
+// publicaciones de una fuente quedan en cero para que la línea no los salte.
+func VolumeBySource(stats []storage.DailyStat) []Series {
+	if len(stats) == 0 {
+		return nil
+	}
+	counts := make(map[string]map[time.Time]float64)
+	first, last := stats[0].Day, stats[0].Day
+	for _, st := range stats {
+		if counts[st.Source] == nil {
+			counts[st.Source] = make(map[time.Time]float64)
+		}
+		counts[st.Source][st.Day] += float64(st.Articles)
+		if st.Day.Before(first) {
+			first = st.Day
+		}
+		if st.Day.After(last) {
+			last = st.Day
+		}
+	}
+
+	var series []Series
+	for source, byDay := range counts {
+		s := Series{Name: source}
+		for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+			s.Points = append(s.Points, Point{Time: d, Value: byDay[d]})
+		}
+		series = append(series, s)
+	}
+	sort.Slice(series, func(i, j int) bool { return series[i].Name < series[j].Name })
+	return series
+}
+
+// TotalsBy suma los artículos por la dimensión elegida ("source" o "language"),
+// de mayor a menor. Más de limit categorías se agrupan en "otros".
+func TotalsBy(stats []storage.DailyStat, dimension string, limit int) []Item {
+	totals := make(map[string]float64)
+	for _, st := range stats {
+		key := st.Source
+		if dimension == "language" {
+			key = st.Language
+		}
+		if key == "" {
+			key = "(desconocido)"
+		}
+		totals[key] += float64(st.Articles)
+	}
+	var items []Item
+	for k, v := range totals {
+		items = append(items, Item{Label: k, Value: v})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Value != items[j].Value {
+			return items[i].Value > items[j].Value
+		}
+		return items[i].Label < items[j].Label
+	})
+	if limit > 0 && len(items) > limit {
+		others := Item{Label: "otros"}
+		for _, it := range items[limit-1:] {
+			others.Value += it.Value
+		}
+		items = append(items[:limit-1], others)
+	}
+	return items
+}
@@ -0,0 +1,127 @@
+// Package chart genera gráficos sencillos (series de tiempo, barras y torta)
+// en SVG o PNG, para incluir en reportes sin depender de una herramienta de BI.
+package chart
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"time"
+)
+
+// Chart es un gráfico que se puede escribir en ambos formatos.
+type Chart interface {
+	WriteSVG(w io.Writer) error
+	WritePNG(w io.Writer) error
+}
+
+// Point es un valor en un instante.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series es una línea de una serie de tiempo.
+type Series struct {
+	Name   string
+	Points []Point
+}
+
+// Item es una categoría con su valor, para barras y tortas.
+type Item struct {
+	Label string
+	Value float64
+}
+
+const (
+	defaultWidth  = 720
+	defaultHeight = 360
+	fontSize      = 12
+)
+
+// palette son colores distinguibles; se repiten si hay más categorías.
+var palette = []color.RGBA{
+	{0x1f, 0x77, 0xb4, 0xff},
+	{0xff, 0x7f, 0x0e, 0xff},
+	{0x2c, 0xa0, 0x2c, 0xff},
+	{0xd6, 0x27, 0x28, 0xff},
+	{0x94, 0x67, 0xbd, 0xff},
+	{0x8c, 0x56, 0x4b, 0xff},
+	{0xe3, 0x77, 0xc2, 0xff},
+	{0x7f, 0x7f, 0x7f, 0xff},
+}
+
+var (
+	black = color.RGBA{0x22, 0x22, 0x22, 0xff}
+	grey  = color.RGBA{0xdd, 0xdd, 0xdd, 0xff}
+	white = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+// canvas son las primitivas de dibujo; cada gráfico se dibuja una sola vez
+// sobre ella y el formato de salida depende de la implementación.
+type canvas interface {
+	line(x1, y1, x2, y2 float64, c color.RGBA, width float64)
+	rect(x, y, w, h float64, c color.RGBA)
+	polygon(points [][2]float64, c color.RGBA)
+	text(x, y float64, s string, a anchor)
+}
+
+func size(w, h int) (int, int) {
+	if w <= 0 {
+		w = defaultWidth
+	}
+	if h <= 0 {
+		h = defaultHeight
+	}
+	return w, h
+}
+
+// niceMax redondea el máximo del eje hacia arriba a 1, 2 o 5 por potencia de
+// diez. Los valores son conteos: con menos de 5 el eje llega a 5 para que las
+// marcas sean enteras.
+func niceMax(v float64) float64 {
+	if v <= 5 {
+		return 5
+	}
+	exp := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if v <= m*exp {
+			return m * exp
+		}
+	}
+	return 10 * exp
+}
+
+func formatValue(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// legend dibuja los nombres con su color en la esquina superior derecha.
+func legend(c canvas, width float64, names []string) {
+	y := 30.0
+	for i, name := range names {
+		c.rect(width-150, y-9, 10, 10, palette[i%len(palette)])
+		c.text(width-135, y, truncate(name, 18), anchorStart)
+		y += 16
+	}
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "~"
+}
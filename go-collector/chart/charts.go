@@ -0,0 +1,169 @@
+package chart
+
+import (
+	"io"
+	"math"
+	"time"
+)
+
+// TimeSeries es un gráfico de líneas con una o más series diarias.
+type TimeSeries struct {
+	Title         string
+	Series        []Series
+	Width, Height int
+}
+
+func (t *TimeSeries) WriteSVG(w io.Writer) error { return writeSVG(w, t.Width, t.Height, t.draw) }
+func (t *TimeSeries) WritePNG(w io.Writer) error { return writePNG(w, t.Width, t.Height, t.draw) }
+
+func (t *TimeSeries) draw(c canvas, width, height float64) {
+	c.text(width/2, 20, t.Title, anchorMiddle)
+
+	var first, last time.Time
+	var max float64
+	var names []string
+	for _, s := range t.Series {
+		names = append(names, s.Name)
+		for _, p := range s.Points {
+			if first.IsZero() || p.Time.Before(first) {
+				first = p.Time
+			}
+			if p.Time.After(last) {
+				last = p.Time
+			}
+			max = math.Max(max, p.Value)
+		}
+	}
+	if first.IsZero() {
+		c.text(width/2, height/2, "Sin datos", anchorMiddle)
+		return
+	}
+	max = niceMax(max)
+
+	left, right, top, bottom := 50.0, width-170, 40.0, height-40
+	span := last.Sub(first).Hours()
+	if span == 0 {
+		span = 24
+	}
+	x := func(tm time.Time) float64 { return left + (right-left)*tm.Sub(first).Hours()/span }
+	y := func(v float64) float64 { return bottom - (bottom-top)*v/max }
+
+	axes(c, left, right, top, bottom, max)
+	// Hasta seis fechas en el eje x.
+	days := int(span/24) + 1
+	step := max1(days / 6)
+	for d := 0; d < days; d += step {
+		tm := first.AddDate(0, 0, d)
+		c.text(x(tm), bottom+16, tm.Format("2006-01-02"), anchorMiddle)
+	}
+
+	for i, s := range t.Series {
+		col := palette[i%len(palette)]
+		for j := 1; j < len(s.Points); j++ {
+			a, b := s.Points[j-1], s.Points[j]
+			c.line(x(a.Time), y(a.Value), x(b.Time), y(b.Value), col, 2)
+		}
+		if len(s.Points) == 1 {
+			p := s.Points[0]
+			c.rect(x(p.Time)-2, y(p.Value)-2, 4, 4, col)
+		}
+	}
+	legend(c, width, names)
+}
+
+// Bar es un gráfico de barras verticales.
+type Bar struct {
+	Title         string
+	Items         []Item
+	Width, Height int
+}
+
+func (b *Bar) WriteSVG(w io.Writer) error { return writeSVG(w, b.Width, b.Height, b.draw) }
+func (b *Bar) WritePNG(w io.Writer) error { return writePNG(w, b.Width, b.Height, b.draw) }
+
+func (b *Bar) draw(c canvas, width, height float64) {
+	c.text(width/2, 20, b.Title, anchorMiddle)
+	if len(b.Items) == 0 {
+		c.text(width/2, height/2, "Sin datos", anchorMiddle)
+		return
+	}
+	var max float64
+	for _, it := range b.Items {
+		max = math.Max(max, it.Value)
+	}
+	max = niceMax(max)
+
+	left, right, top, bottom := 50.0, width-20, 40.0, height-40
+	axes(c, left, right, top, bottom, max)
+	slot := (right - left) / float64(len(b.Items))
+	maxLabel := max1(int(slot / 7))
+	for i, it := range b.Items {
+		h := (bottom - top) * it.Value / max
+		x := left + slot*float64(i) + slot*0.15
+		c.rect(x, bottom-h, slot*0.7, h, palette[i%len(palette)])
+		c.text(x+slot*0.35, bottom-h-4, formatValue(it.Value), anchorMiddle)
+		c.text(x+slot*0.35, bottom+16, truncate(it.Label, maxLabel), anchorMiddle)
+	}
+}
+
+// Pie es un gráfico de torta.
+type Pie struct {
+	Title         string
+	Items         []Item
+	Width, Height int
+}
+
+func (p *Pie) WriteSVG(w io.Writer) error { return writeSVG(w, p.Width, p.Height, p.draw) }
+func (p *Pie) WritePNG(w io.Writer) error { return writePNG(w, p.Width, p.Height, p.draw) }
+
+func (p *Pie) draw(c canvas, width, height float64) {
+	c.text(width/2, 20, p.Title, anchorMiddle)
+	var total float64
+	for _, it := range p.Items {
+		total += it.Value
+	}
+	if total <= 0 {
+		c.text(width/2, height/2, "Sin datos", anchorMiddle)
+		return
+	}
+
+	cx, cy := (width-170)/2, (height+20)/2
+	r := math.Min(cx, cy-20) - 10
+	start := -math.Pi / 2
+	var names []string
+	for i, it := range p.Items {
+		sweep := 2 * math.Pi * it.Value / total
+		// Cada porción es un polígono con un vértice por grado aproximadamente.
+		pts := [][2]float64{{cx, cy}}
+		steps := max1(int(sweep * 180 / math.Pi))
+		for s := 0; s <= steps; s++ {
+			a := start + sweep*float64(s)/float64(steps)
+			pts = append(pts, [2]float64{cx + r*math.Cos(a), cy + r*math.Sin(a)})
+		}
+		c.polygon(pts, palette[i%len(palette)])
+		names = append(names, it.Label+" ("+formatValue(100*it.Value/total)+"%)")
+		start += sweep
+	}
+	legend(c, width, names)
+}
+
+// axes dibuja los ejes y cinco líneas de referencia horizontales.
+func axes(c canvas, left, right, top, bottom, max float64) {
+	for i := 0; i <= 5; i++ {
+		v := max * float64(i) / 5
+		y := bottom - (bottom-top)*float64(i)/5
+		if i > 0 {
+			c.line(left, y, right, y, grey, 1)
+		}
+		c.text(left-6, y+4, formatValue(v), anchorEnd)
+	}
+	c.line(left, top, left, bottom, black, 1)
+	c.line(left, bottom, right, bottom, black, 1)
+}
+
+func max1(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
@@ -0,0 +1,111 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// pngCanvas rasteriza las primitivas sin antialiasing; el texto usa la fuente
+// de mapa de bits de x/image, que solo cubre ASCII, así que se quitan las tildes.
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+func writePNG(w io.Writer, width, height int, drawFn func(canvas, float64, float64)) error {
+	width, height = size(width, height)
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(white), image.Point{}, draw.Src)
+	drawFn(&pngCanvas{img: img}, float64(width), float64(height))
+	return png.Encode(w, img)
+}
+
+func (p *pngCanvas) line(x1, y1, x2, y2 float64, c color.RGBA, width float64) {
+	steps := int(math.Max(math.Abs(x2-x1), math.Abs(y2-y1))) + 1
+	half := int(width / 2)
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x, y := int(x1+(x2-x1)*t), int(y1+(y2-y1)*t)
+		for dx := -half; dx <= half; dx++ {
+			for dy := -half; dy <= half; dy++ {
+				p.img.SetRGBA(x+dx, y+dy, c)
+			}
+		}
+	}
+}
+
+func (p *pngCanvas) rect(x, y, w, h float64, c color.RGBA) {
+	r := image.Rect(int(x), int(y), int(math.Round(x+w)), int(math.Round(y+h)))
+	draw.Draw(p.img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// polygon rellena por regla par-impar recorriendo cada fila del rectángulo que lo contiene.
+func (p *pngCanvas) polygon(points [][2]float64, c color.RGBA) {
+	if len(points) < 3 {
+		return
+	}
+	minY, maxY := points[0][1], points[0][1]
+	for _, pt := range points {
+		minY, maxY = math.Min(minY, pt[1]), math.Max(maxY, pt[1])
+	}
+	for y := int(minY); y <= int(maxY); y++ {
+		fy := float64(y) + 0.5
+		var xs []float64
+		for i := range points {
+			a, b := points[i], points[(i+1)%len(points)]
+			if (a[1] <= fy) != (b[1] <= fy) {
+				xs = append(xs, a[0]+(fy-a[1])*(b[0]-a[0])/(b[1]-a[1]))
+			}
+		}
+		for i := 1; i < len(xs); i++ {
+			for j := i; j > 0 && xs[j] < xs[j-1]; j-- {
+				xs[j], xs[j-1] = xs[j-1], xs[j]
+			}
+		}
+		for i := 0; i+1 < len(xs); i += 2 {
+			for x := int(math.Round(xs[i])); x < int(math.Round(xs[i+1])); x++ {
+				p.img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+func (p *pngCanvas) text(x, y float64, s string, a anchor) {
+	s = asciiFold(s)
+	d := &font.Drawer{Dst: p.img, Src: image.NewUniform(black), Face: basicfont.Face7x13}
+	width := d.MeasureString(s).Round()
+	switch a {
+	case anchorMiddle:
+		x -= float64(width) / 2
+	case anchorEnd:
+		x -= float64(width)
+	}
+	d.Dot = fixed.P(int(x), int(y))
+	d.DrawString(s)
+}
+
+func asciiFold(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	out, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII {
+			return '?'
+		}
+		return r
+	}, out)
+}
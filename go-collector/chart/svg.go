@@ -0,0 +1,50 @@
+package chart
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"strings"
+)
+
+type svgCanvas struct {
+	w *bufio.Writer
+}
+
+func writeSVG(w io.Writer, width, height int, draw func(canvas, float64, float64)) error {
+	width, height = size(width, height)
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="%d">`+"\n",
+		width, height, width, height, fontSize)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	draw(&svgCanvas{w: bw}, float64(width), float64(height))
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func (s *svgCanvas) line(x1, y1, x2, y2 float64, c color.RGBA, width float64) {
+	fmt.Fprintf(s.w, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%.1f"/>`+"\n", x1, y1, x2, y2, hex(c), width)
+}
+
+func (s *svgCanvas) rect(x, y, w, h float64, c color.RGBA) {
+	fmt.Fprintf(s.w, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n", x, y, w, h, hex(c))
+}
+
+func (s *svgCanvas) polygon(points [][2]float64, c color.RGBA) {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = fmt.Sprintf("%.1f,%.1f", p[0], p[1])
+	}
+	fmt.Fprintf(s.w, `<polygon points="%s" fill="%s" stroke="white"/>`+"\n", strings.Join(parts, " "), hex(c))
+}
+
+func (s *svgCanvas) text(x, y float64, str string, a anchor) {
+	anchors := [...]string{"start", "middle", "end"}
+	fmt.Fprintf(s.w, `<text x="%.1f" y="%.1f" text-anchor="%s">%s</text>`+"\n", x, y, anchors[a], html.EscapeString(str))
+}
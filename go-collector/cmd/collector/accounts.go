@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-collector/accounts"
+	"go-collector/storage"
+)
+
+// runAccounts registra y consulta el estado de las cuentas monitoreadas
+// (config accounts): snapshot toma el del día y history muestra la serie.
+func runAccounts(args []string) error {
+	if len(args) == 0 || (args[0] != "snapshot" && args[0] != "history") {
+		return fmt.Errorf("uso: collector accounts snapshot|history [opciones]")
+	}
+	if args[0] == "history" {
+		return runAccountsHistory(args[1:])
+	}
+
+	fs := flag.NewFlagSet("accounts snapshot", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	parseFlags(fs, args[1:])
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Accounts) == 0 {
+		return fmt.Errorf("no hay cuentas configuradas (accounts en %s)", *cfgPath)
+	}
+	var token string
+	for _, acc := range cfg.Accounts {
+		if acc.Platform == "x" {
+			if token, err = cfg.Sources.X.Key("x"); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	history, err := store.AccountSnapshots(time.Time{}, today.AddDate(0, 0, -1))
+	if err != nil {
+		return err
+	}
+	prev := make(map[string]storage.AccountSnapshot)
+	for _, s := range accounts.Group(history) {
+		prev[s.Platform+"/"+s.Handle] = s.Last()
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	c := accounts.NewClient(token)
+	failed := 0
+	for _, acc := range cfg.Accounts {
+		if ctx.Err() != nil {
+			break
+		}
+		name := acc.Platform + "/" + acc.Handle
+		p, err := c.Fetch(ctx, acc)
+		if err != nil {
+			failed++
+			fmt.Printf("  %-28s %v\n", name, err)
+			continue
+		}
+		snap := storage.AccountSnapshot{
+			Platform: p.Platform, Handle: p.Handle, Day: today, Name: p.Name, Bio: p.Bio,
+			Followers: p.Followers, Following: p.Following, Posts: p.Posts, Taken: now,
+		}
+		if err := store.SaveAccountSnapshot(snap); err != nil {
+			return err
+		}
+		line := fmt.Sprintf("  %-28s seguidores %d", name, p.Followers)
+		last, ok := prev[name]
+		if ok {
+			line += fmt.Sprintf(" (%+d)", p.Followers-last.Followers)
+		}
+		fmt.Printf("%s  siguiendo %d  publicaciones %d\n", line, p.Following, p.Posts)
+		if ok && last.Bio != p.Bio {
+			fmt.Printf("  %-28s biografía nueva: %s\n", "", oneLine(p.Bio))
+		}
+	}
+	fmt.Printf("\nCuentas: %d registradas, %d con error\n", len(cfg.Accounts)-failed, failed)
+	if failed == len(cfg.Accounts) {
+		return fmt.Errorf("no se pudo consultar ninguna cuenta")
+	}
+	return nil
+}
+
+// runAccountsHistory muestra la serie diaria de cada cuenta, con la
+// variación de seguidores y los cambios de biografía.
+func runAccountsHistory(args []string) error {
+	fs := flag.NewFlagSet("accounts history", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	account := fs.String("account", "", "solo esta cuenta, como plataforma/handle (ej: x/UdeA)")
+	since := fs.String("since", "", "desde esta fecha (AAAA-MM-DD)")
+	until := fs.String("until", "", "hasta esta fecha inclusive (AAAA-MM-DD)")
+	format := fs.String("format", "text", "formato: text o csv")
+	out := fs.String("out", "", "archivo de salida (por defecto, salida estándar)")
+	parseFlags(fs, args)
+	if *format != "text" && *format != "csv" {
+		return fmt.Errorf("formato desconocido: %s (use text o csv)", *format)
+	}
+
+	var from, to time.Time
+	if *since != "" {
+		t, err := time.Parse("2006-01-02", *since)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --since: %w", err)
+		}
+		from = t
+	}
+	if *until != "" {
+		t, err := time.Parse("2006-01-02", *until)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --until: %w", err)
+		}
+		to = t
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	snaps, err := store.AccountSnapshots(from, to)
+	if err != nil {
+		return err
+	}
+	var series []accounts.Series
+	for _, s := range accounts.Group(snaps) {
+		if *account == "" || strings.EqualFold(*account, s.Platform+"/"+s.Handle) {
+			series = append(series, s)
+		}
+	}
+	if len(series) == 0 {
+		return fmt.Errorf("no hay registros de cuentas en el período (ver collector accounts snapshot)")
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if *format == "csv" {
+		return writeAccountsCSV(w, series)
+	}
+	for _, s := range series {
+		fmt.Fprintf(w, "%s/%s: %+d seguidores (%d a %d)\n", s.Platform, s.Handle, s.FollowersDelta(), s.First().Followers, s.Last().Followers)
+		fmt.Fprintf(w, "  %-10s %10s %8s %10s %13s\n", "Día", "Seguidores", "Cambio", "Siguiendo", "Publicaciones")
+		changes := make(map[time.Time]bool)
+		for _, c := range s.BioChanges {
+			changes[c.Day] = true
+		}
+		for i, snap := range s.Snapshots {
+			delta := ""
+			if i > 0 {
+				delta = fmt.Sprintf("%+d", snap.Followers-s.Snapshots[i-1].Followers)
+			}
+			mark := ""
+			if changes[snap.Day] {
+				mark = "  biografía nueva"
+			}
+			fmt.Fprintf(w, "  %-10s %10d %8s %10d %13d%s\n", snap.Day.Format("2006-01-02"), snap.Followers, delta, snap.Following, snap.Posts, mark)
+		}
+		for _, c := range s.BioChanges {
+			fmt.Fprintf(w, "  %s biografía: %q -> %q\n", c.Day.Format("2006-01-02"), oneLine(c.From), oneLine(c.To))
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// writeAccountsCSV escribe una fila por cuenta y día, para graficar la serie.
+func writeAccountsCSV(w io.Writer, series []accounts.Series) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"platform", "handle", "day", "followers", "following", "posts", "name", "bio"})
+	for _, s := range series {
+		for _, snap := range s.Snapshots {
+			cw.Write([]string{snap.Platform, snap.Handle, snap.Day.Format("2006-01-02"),
+				strconv.Itoa(snap.Followers), strconv.Itoa(snap.Following), strconv.Itoa(snap.Posts), snap.Name, snap.Bio})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// oneLine junta las líneas de una biografía para mostrarla en una sola.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
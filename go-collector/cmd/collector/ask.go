@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"go-collector/ask"
+	"go-collector/embed"
+)
+
+// runAsk es experimental: responde preguntas sobre el corpus con un LLM,
+// citando los IDs de los artículos usados.
+func runAsk(args []string) error {
+	fs := flag.NewFlagSet("ask", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	k := fs.Int("k", 8, "cantidad de artículos enviados como contexto")
+	parseFlags(fs, args)
+
+	question := strings.Join(fs.Args(), " ")
+	if question == "" {
+		return fmt.Errorf("uso: collector ask \"pregunta\"")
+	}
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	provider, err := embed.NewProvider(cfg.Embeddings)
+	if err != nil {
+		return err
+	}
+
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	asker := &ask.Asker{Store: store, Embedder: provider, LLM: ask.NewChatClient(cfg.LLM), K: *k}
+	ctx, cancel := signalContext()
+	defer cancel()
+	ans, err := asker.Ask(ctx, question)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s\n", ans.Text)
+	fmt.Println("\nFuentes citadas:")
+	for _, a := range ans.Cited {
+		fmt.Printf("  [#%d] %s (%s, %s)\n        %s\n", a.ID, a.Title, a.Source, a.Published.Format("2006-01-02"), a.URL)
+	}
+	if len(ans.Invalid) > 0 {
+		fmt.Printf("\nAdvertencia: el modelo citó artículos que no estaban en el contexto: %v\n", ans.Invalid)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"go-collector/config"
+	"go-collector/storage"
+)
+
+// actor identifica a quien ejecuta el comando en la bitácora: COLLECTOR_ACTOR
+// si está definida (para tareas programadas), si no usuario@máquina.
+func actor() string {
+	if a := os.Getenv("COLLECTOR_ACTOR"); a != "" {
+		return a
+	}
+	name := "desconocido"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	host, _ := os.Hostname()
+	return name + "@" + host
+}
+
+// audit registra una acción administrativa del usuario actual.
+func audit(store *storage.Store, action, target, detail string) error {
+	return store.Audit(storage.AuditEntry{Actor: actor(), Action: action, Target: target, Detail: detail})
+}
+
+// auditConfig registra un cambio de configuración cuando su hash difiere del
+// último visto por el corpus.
+func auditConfig(store *storage.Store, cfg *config.Config) error {
+	hash := cfg.Hash()
+	last, err := store.LastAudit(storage.AuditConfigChange)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+	if last != nil && last.Target == hash {
+		return nil
+	}
+	detail := "primera configuración registrada"
+	if last != nil {
+		detail = "anterior: " + last.Target
+	}
+	return audit(store, storage.AuditConfigChange, hash, detail)
+}
+
+func runAudit(args []string) error {
+	if len(args) == 0 || args[0] != "list" {
+		return fmt.Errorf("uso: collector audit list [--action ...] [--since AAAA-MM-DD]")
+	}
+	fs := flag.NewFlagSet("audit list", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	action := fs.String("action", "", "solo esta acción (import, restore, config.change, ...)")
+	who := fs.String("actor", "", "solo este actor")
+	since := fs.String("since", "", "desde esta fecha (AAAA-MM-DD)")
+	limit := fs.Int("limit", 50, "cantidad máxima de registros")
+	parseFlags(fs, args[1:])
+
+	filter := storage.AuditFilter{Action: *action, Actor: *who, Limit: *limit}
+	if *since != "" {
+		t, err := time.Parse("2006-01-02", *since)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --since: %w", err)
+		}
+		filter.Since = t
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	entries, err := store.AuditLog(filter)
+	if err != nil {
+		return err
+	}
+	fmt.Println("\n--- BITÁCORA DE AUDITORÍA ---")
+	for _, e := range entries {
+		fmt.Printf("  #%-5d %s  %-20s %-14s %s\n", e.ID, e.At.Local().Format("2006-01-02 15:04:05"), e.Actor, e.Action, e.Target)
+		if e.Detail != "" {
+			fmt.Printf("         %s\n", e.Detail)
+		}
+	}
+	if len(entries) == 0 {
+		fmt.Println("  (sin registros)")
+	}
+	return nil
+}
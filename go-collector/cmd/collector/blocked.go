@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"go-collector/fetch"
+	"go-collector/storage"
+)
+
+// runBlocked muestra los dominios que responden con 429 o 403 una y otra vez:
+// los candidatos a una regla en fetch.domains, un rate_limit más bajo o a
+// dejar de consultarse.
+func runBlocked(args []string) error {
+	fs := flag.NewFlagSet("blocked", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	min := fs.Int("min", 3, "solo dominios con al menos esta cantidad de bloqueos")
+	parseFlags(fs, args)
+	if *min < 1 {
+		return fmt.Errorf("--min debe ser al menos 1")
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	blockers, err := store.ChronicBlockers(*min)
+	if err != nil {
+		return err
+	}
+	fetch.ReportarDominiosBloqueados(blockers)
+	return nil
+}
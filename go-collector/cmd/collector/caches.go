@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"go-collector/fetch"
+	"go-collector/robots"
+)
+
+// Vigencia de las cachés que comparten todos los Collector de un proceso.
+// Los robots.txt cambian poco: el RFC 9309 pide no usar uno de más de un
+// día.
+const (
+	dnsCacheTTL    = 5 * time.Minute
+	robotsCacheTTL = 6 * time.Hour
+)
+
+// sharedCaches son la resolución DNS y los robots.txt de los medios, que
+// comparten todas las fuentes y campañas del proceso y sus rondas.
+type sharedCaches struct {
+	dns    *fetch.DNSCache
+	robots *robots.Cache
+}
+
+func newSharedCaches() sharedCaches {
+	dns := fetch.NewDNSCache(dnsCacheTTL)
+	rb := robots.NewCache(robotsCacheTTL)
+	rb.HTTP.Transport = dns.Transport()
+	return sharedCaches{dns: dns, robots: rb}
+}
+
+// print escribe los contadores de las cachés que se consultaron, cada línea
+// con prefix.
+func (s sharedCaches) print(w io.Writer, prefix string) {
+	if st := s.dns.Stats(); st.Lookups() > 0 {
+		fmt.Fprintf(w, "%sCaché DNS: %s\n", prefix, st)
+	}
+	if st := s.robots.Stats(); st.Lookups() > 0 {
+		fmt.Fprintf(w, "%sCaché robots.txt: %s; %d páginas excluidas\n", prefix, st, s.robots.Blocked())
+	}
+}
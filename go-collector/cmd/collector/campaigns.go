@@ -0,0 +1,352 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go-collector/collect"
+	"go-collector/config"
+	"go-collector/elastic"
+	"go-collector/fetch"
+	"go-collector/kafka"
+	"go-collector/notify"
+	"go-collector/progress"
+	"go-collector/schedule"
+	"go-collector/storage"
+)
+
+// campaignCacheTTL es cuánto se reutiliza una respuesta de las APIs entre
+// campañas: alcanza para una ronda, no para la siguiente.
+const campaignCacheTTL = 10 * time.Minute
+
+type campaignOptions struct {
+	cfgPath   string
+	cfg       *config.Config
+	campaigns []config.Campaign
+	dbPath    string
+	only      string
+	dryRun    bool
+	every     time.Duration
+	progress  progress.Reporter
+}
+
+// campaignRun son los recursos propios de una campaña: su cupo de peticiones
+// (en el transporte del Collector) y su corpus.
+type campaignRun struct {
+	name      string
+	collector *collect.Collector
+	store     *storage.Store // nil en --dry-run
+	index     *elastic.Client
+	kafka     *kafka.Producer
+}
+
+// reportFunc imprime el resumen de una ronda de campaña.
+type reportFunc func(name string, out []byte, err error, took time.Duration)
+
+// selectCampaigns devuelve las campañas pedidas ("all" o nombres separados
+// por coma) en el orden de la configuración.
+func selectCampaigns(cfg *config.Config, list string) ([]config.Campaign, error) {
+	if len(cfg.Campaigns) == 0 {
+		return nil, fmt.Errorf("no hay campañas configuradas")
+	}
+	if list == "all" {
+		return cfg.Campaigns, nil
+	}
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			wanted[name] = true
+		}
+	}
+	var out []config.Campaign
+	for _, c := range cfg.Campaigns {
+		if wanted[c.Name] {
+			out = append(out, c)
+			delete(wanted, c.Name)
+		}
+	}
+	for name := range wanted {
+		return nil, fmt.Errorf("campaña no configurada: %s", name)
+	}
+	return out, nil
+}
+
+// runCampaigns ejecuta las campañas en paralelo. Cada una tiene su propio
+// cupo de peticiones (rate_limit) y su propio corpus (namespace); comparten
+// la caché de respuestas HTTP, así que una consulta repetida entre campañas
+// se hace una sola vez y no gasta cupo. La réplica en Postgres es común.
+//
+// Sin --every ni schedule corre una ronda. En modo daemon cada campaña sigue
+// su propio horario: su schedule si lo tiene (esperando al primer turno), o
+// --every desde el arranque. Una campaña lenta no retrasa a las demás.
+func runCampaigns(ctx context.Context, opts campaignOptions) error {
+	cfg := opts.cfg
+	cache := fetch.NewCache(campaignCacheTTL)
+	shared := newSharedCaches()
+	// Un solo navegador para todas: max_concurrent vale para el proceso.
+	renderer := fetch.NewRenderer(cfg.Fetch.Render)
+	if renderer != nil {
+		defer renderer.Close()
+	}
+
+	var mirror *sql.DB
+	var index *elastic.Client
+	var producer *kafka.Producer
+	if !opts.dryRun {
+		var err error
+		if mirror, err = openMirror(cfg); err != nil {
+			return err
+		}
+		if mirror != nil {
+			defer mirror.Close()
+		}
+		// Las campañas comparten el índice: el _id de cada documento sale de
+		// la URL, no del corpus.
+		if index, err = elastic.NewClient(cfg.Output.Elasticsearch, cfg.Storage.Encryption.AuthorSources); err != nil {
+			return err
+		}
+		if producer, err = kafka.NewProducer(cfg.Output.Kafka, cfg.Storage.Encryption.AuthorSources); err != nil {
+			return err
+		}
+	}
+
+	runs := make([]*campaignRun, len(opts.campaigns))
+	var stores []*storage.Store
+	for i, camp := range opts.campaigns {
+		limit, err := config.ParseRate(camp.RateLimit)
+		if err != nil {
+			return fmt.Errorf("campaña %s: %w", camp.Name, err)
+		}
+		run := &campaignRun{
+			name: camp.Name,
+			collector: &collect.Collector{
+				Transport: fetch.Chain(shared.dns.Transport(), cache.Middleware(), fetch.RateLimit(limit, camp.RateBurst)),
+				Progress:  progress.WithCampaign(opts.progress, camp.Name),
+				Extractor: textExtractor(cfg),
+				Renderer:  renderer,
+				Robots:    shared.robots,
+				Fetch:     cfg.Fetch,
+			},
+		}
+		if !opts.dryRun {
+			store, err := openStore(namespacePath(opts.dbPath, camp.NamespaceOrName()), cfg)
+			if err != nil {
+				return fmt.Errorf("campaña %s: %w", camp.Name, err)
+			}
+			defer store.Close()
+			run.store = store
+			run.index = index
+			run.kafka = producer
+			run.collector.FeedStates = store
+			run.collector.Holds = store
+			run.collector.Backoff = store
+			run.collector.RenderPaths = store
+			if run.collector.Links, err = linkExpander(cfg, store); err != nil {
+				return fmt.Errorf("campaña %s: %w", camp.Name, err)
+			}
+			stores = append(stores, store)
+		}
+		runs[i] = run
+	}
+
+	daemon := opts.every > 0 && !opts.dryRun
+	for _, camp := range opts.campaigns {
+		if camp.Schedule != "" && !opts.dryRun {
+			daemon = true
+		}
+	}
+
+	var mu sync.Mutex
+	report := func(name string, out []byte, err error, took time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Printf("\n=== CAMPAÑA %s (%s) ===\n", name, took.Round(time.Second))
+		os.Stdout.Write(out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  campaña %s: ERROR: %v\n", name, err)
+		}
+		if daemon {
+			// En modo daemon la caché dura lo que el proceso: los contadores
+			// son acumulados.
+			fmt.Printf("  Caché HTTP compartida: %s\n", cache.Stats())
+			shared.print(os.Stdout, "  ")
+		}
+	}
+
+	if !daemon {
+		errs := make([]error, len(runs))
+		outs := make([][]byte, len(runs))
+		took := make([]time.Duration, len(runs))
+		var wg sync.WaitGroup
+		now := time.Now().UTC()
+		for i, run := range runs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				start := time.Now()
+				outs[i], errs[i] = run.once(ctx, cfg, mirror, opts, now)
+				took[i] = time.Since(start)
+			}()
+		}
+		wg.Wait()
+
+		// Cada campaña se informa por separado, en el orden de la configuración.
+		failed := 0
+		for i, run := range runs {
+			report(run.name, outs[i], errs[i], took[i])
+			if errs[i] != nil {
+				failed++
+			}
+		}
+		fmt.Printf("\nCaché HTTP compartida: %s\n", cache.Stats())
+		shared.print(os.Stdout, "")
+		if failed > 0 {
+			return fmt.Errorf("%d de %d campañas fallaron", failed, len(runs))
+		}
+		return nil
+	}
+
+	live := config.NewLive(cfg)
+	watchConfig(opts.cfgPath, live, ctx.Done(), nil, stores...)
+
+	var wg sync.WaitGroup
+	for _, run := range runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run.loop(ctx, live, mirror, opts, report)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// loop corre la campaña según su horario hasta que se cancele ctx. El
+// horario se toma de la configuración vigente en cada vuelta.
+func (r *campaignRun) loop(ctx context.Context, live *config.Live, mirror *sql.DB, opts campaignOptions, report reportFunc) {
+	next := time.Now()
+	if camp, _ := findCampaign(live.Get(), r.name); camp.Schedule != "" {
+		next = r.next(camp, opts.every, next)
+		log.Printf("campaña %s: primera recolección %s", r.name, next.Format("2006-01-02 15:04"))
+	}
+	for {
+		// Sin schedule ni --every la campaña corre una sola ronda y espera
+		// a que se detenga el daemon.
+		var wait <-chan time.Time
+		if !next.IsZero() {
+			wait = time.After(time.Until(next))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-wait:
+		}
+
+		start := time.Now()
+		out, err := r.once(ctx, live.Get(), mirror, opts, start.UTC())
+		report(r.name, out, err, time.Since(start))
+
+		camp, ok := findCampaign(live.Get(), r.name)
+		if !ok {
+			log.Printf("campaña %s: ya no está en la configuración, se detiene", r.name)
+			return
+		}
+		if next = r.next(camp, opts.every, time.Now()); !next.IsZero() {
+			log.Printf("campaña %s: próxima recolección %s", r.name, next.Format("2006-01-02 15:04"))
+		}
+	}
+}
+
+// next calcula la siguiente ronda: por schedule, o por --every. Cero si la
+// campaña no tiene ninguno de los dos.
+func (r *campaignRun) next(camp config.Campaign, every time.Duration, now time.Time) time.Time {
+	if camp.Schedule != "" {
+		if c, err := schedule.Parse(camp.Schedule); err == nil {
+			return c.Next(now)
+		}
+	}
+	if every > 0 {
+		return now.Add(every)
+	}
+	return time.Time{}
+}
+
+// once hace una ronda de la campaña con la configuración cfg y devuelve el
+// resumen, que se imprime junto cuando termina.
+func (r *campaignRun) once(ctx context.Context, cfg *config.Config, mirror *sql.DB, opts campaignOptions, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	camp, ok := findCampaign(cfg, r.name)
+	if !ok {
+		return nil, fmt.Errorf("la campaña ya no está en la configuración")
+	}
+	sources, err := collect.CampaignSources(cfg, camp)
+	if err != nil {
+		return nil, err
+	}
+	var keys map[string]string
+	srcs := &sources
+	if !opts.dryRun {
+		if srcs, keys, err = incremental(r.store, r.name, srcs, opts.only); err != nil {
+			return nil, err
+		}
+	}
+	results := r.collector.Enabled(ctx, srcs, opts.only, now)
+	rules, err := collect.FilterRelevance(results, cfg.Relevance, collect.CampaignQuery(camp))
+	if err != nil {
+		return nil, err
+	}
+	if opts.dryRun {
+		err = printDry(&buf, r.collector, results)
+		return buf.Bytes(), err
+	}
+	notifier, err := notify.New(cfg.Notifications, cfg.Relevance)
+	if err != nil {
+		return nil, err
+	}
+	dst := sink{
+		store:      r.store,
+		mirror:     mirror,
+		index:      r.index,
+		kafka:      r.kafka,
+		dedup:      cfg.Dedup,
+		out:        &buf,
+		campaign:   r.name,
+		configHash: cfg.Hash(),
+		watermarks: keys,
+		sentiment:  cfg.Sentiment.Enabled,
+		notify:     notifier,
+		rules:      rules,
+	}
+	if cfg.Output.JSONL != "" {
+		dst.jsonl = namespacePath(cfg.Output.JSONL, camp.NamespaceOrName())
+	}
+	err = saveResults(r.collector, dst, results, now)
+	return buf.Bytes(), err
+}
+
+func findCampaign(cfg *config.Config, name string) (config.Campaign, bool) {
+	for _, c := range cfg.Campaigns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return config.Campaign{}, false
+}
+
+// namespacePath separa una ruta de salida por campaña: reemplaza {campaign},
+// o agrega "-<namespace>" antes de la extensión (corpus.db -> corpus-udea.db).
+func namespacePath(path, namespace string) string {
+	if strings.Contains(path, "{campaign}") {
+		return strings.ReplaceAll(path, "{campaign}", namespace)
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + namespace + ext
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go-collector/chart"
+	"go-collector/storage"
+)
+
+func runChart(args []string) error {
+	fs := flag.NewFlagSet("chart", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	kind := fs.String("kind", "volume", "gráfico: volume (serie diaria), sources (barras) o languages (torta)")
+	format := fs.String("format", "svg", "formato de salida: svg o png")
+	days := fs.Int("days", 30, "días hacia atrás desde hoy")
+	out := fs.String("out", "", "archivo de salida (por defecto, salida estándar)")
+	parseFlags(fs, args)
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -*days)
+	if err := store.RefreshDailyStats(from); err != nil {
+		return err
+	}
+	stats, err := store.DailyStats(from, to)
+	if err != nil {
+		return err
+	}
+
+	var c chart.Chart
+	switch *kind {
+	case "volume":
+		c = &chart.TimeSeries{Title: "Artículos por día", Series: chart.VolumeBySource(stats)}
+	case "sources":
+		c = &chart.Bar{Title: "Artículos por fuente", Items: chart.TotalsBy(stats, "source", 8)}
+	case "languages":
+		c = &chart.Pie{Title: "Artículos por idioma", Items: chart.TotalsBy(stats, "language", 6)}
+	default:
+		return fmt.Errorf("gráfico desconocido: %s (use volume, sources o languages)", *kind)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "svg":
+		return c.WriteSVG(w)
+	case "png":
+		return c.WritePNG(w)
+	default:
+		return fmt.Errorf("formato desconocido: %s (use svg o png)", *format)
+	}
+}
@@ -0,0 +1,828 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/buildinfo"
+	"go-collector/collect"
+	"go-collector/config"
+	"go-collector/dedup"
+	"go-collector/editions"
+	"go-collector/elastic"
+	"go-collector/fetch"
+	"go-collector/kafka"
+	"go-collector/notify"
+	"go-collector/progress"
+	"go-collector/relevance"
+	"go-collector/schedule"
+	"go-collector/shortlink"
+	"go-collector/storage"
+	"go-collector/trends"
+)
+
+// runCollect consulta las fuentes activadas en la configuración y guarda los
+// artículos en el corpus (y en JSONL si output.jsonl está configurado). Con
+// --every queda corriendo como daemon y recarga la configuración en caliente:
+// fuentes, consultas y feeds nuevos se aplican en la siguiente ronda;
+// --schedule hace lo mismo con un horario cron. Las fuentes con incremental
+// piden en cada ronda solo lo publicado desde la anterior.
+// --progress informa el avance en stderr: json emite un evento por línea para
+// otras herramientas y bar muestra una línea de estado por fuente.
+// --campaign ejecuta campañas en paralelo (ver runCampaigns). --query, --from,
+// --to y --lang reemplazan la configuración de las fuentes para una ronda.
+func runCollect(args []string) error {
+	fs := flag.NewFlagSet("collect", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	dbPath := fs.String("db", "", "ruta de la base de datos del corpus (por defecto output.db o corpus.db)")
+	only := fs.String("source", "", "consultar solo esta fuente, aunque no esté activada")
+	campaigns := fs.String("campaign", "", "ejecutar estas campañas en paralelo, separadas por coma, o all")
+	dryRun := fs.Bool("dry-run", false, "consultar y mostrar conteos sin guardar")
+	every := fs.Duration("every", 0, "daemon: recolectar cada este intervalo (ej: 1h)")
+	cronExpr := fs.String("schedule", "", `daemon: recolectar según esta expresión cron (ej: "0 6 * * *")`)
+	progressMode := fs.String("progress", "auto", "avance en stderr: json, bar, none o auto (bar si es una terminal)")
+	var o sourceOverrides
+	fs.StringVar(&o.query, "query", "", "reemplaza la consulta de la fuente (sintaxis de la fuente)")
+	fs.StringVar(&o.from, "from", "", "reemplaza el inicio del rango: fecha (AAAA-MM-DD) o período (30d)")
+	fs.StringVar(&o.to, "to", "", "reemplaza el fin del rango: fecha (AAAA-MM-DD) o período")
+	fs.StringVar(&o.lang, "lang", "", "reemplaza los idiomas, separados por coma (ej: es,en)")
+	parseFlags(fs, args)
+
+	rep, err := progress.New(*progressMode, os.Stderr)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	var cron *schedule.Cron
+	if *cronExpr != "" {
+		if *every > 0 || *campaigns != "" {
+			return fmt.Errorf("--schedule no se combina con --every ni --campaign (las campañas tienen su propio schedule)")
+		}
+		if cron, err = schedule.Parse(*cronExpr); err != nil {
+			return err
+		}
+	}
+	if *only != "" && cfg.Sources.Get(*only) == nil {
+		return fmt.Errorf("fuente desconocida: %s (use guardian, newsapi, bingnews, eventregistry, mediastack, currents, gdelt, x, rss, googlenews, mastodon, bluesky, youtube, oai, commoncrawl, sitemap, scrape o mock)", *only)
+	}
+	if o.set() {
+		// La configuración recargada o la de cada campaña no llevaría los
+		// reemplazos: solo valen para una ronda con la configuración cargada.
+		if *every > 0 || cron != nil || *campaigns != "" {
+			return fmt.Errorf("--query, --from, --to y --lang no se combinan con --every, --schedule ni --campaign")
+		}
+		if err := o.apply(&cfg.Sources, *only, time.Now().UTC()); err != nil {
+			return err
+		}
+	}
+	if *dbPath == "" {
+		*dbPath = cfg.Output.DB
+	}
+	if *dbPath == "" {
+		*dbPath = "corpus.db"
+	}
+
+	// Ctrl-C corta las consultas en curso; en modo daemon, además, termina.
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	if *campaigns != "" {
+		selected, err := selectCampaigns(cfg, *campaigns)
+		if err != nil {
+			return err
+		}
+		return runCampaigns(ctx, campaignOptions{
+			cfgPath:   *cfgPath,
+			cfg:       cfg,
+			campaigns: selected,
+			dbPath:    *dbPath,
+			only:      *only,
+			dryRun:    *dryRun,
+			every:     *every,
+			progress:  rep,
+		})
+	}
+
+	shared := newSharedCaches()
+	c := &collect.Collector{Transport: shared.dns.Transport(), Progress: rep, Extractor: textExtractor(cfg), Renderer: fetch.NewRenderer(cfg.Fetch.Render), Robots: shared.robots, Fetch: cfg.Fetch}
+	// Una recarga puede reemplazar el navegador: se cierra el vigente.
+	defer func() {
+		if c.Renderer != nil {
+			c.Renderer.Close()
+		}
+	}()
+	if *dryRun {
+		return collectDry(ctx, os.Stdout, c, &cfg.Sources, cfg.Relevance, *only, time.Now().UTC())
+	}
+
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	c.Holds = store
+	c.Backoff = store
+	c.RenderPaths = store
+	if !o.set() {
+		// Con otra consulta o rango el estado de los feeds no vale: se leen
+		// completos.
+		c.FeedStates = store
+	}
+	if c.Links, err = linkExpander(cfg, store); err != nil {
+		return err
+	}
+
+	mirror, err := openMirror(cfg)
+	if err != nil {
+		return err
+	}
+	if mirror != nil {
+		defer mirror.Close()
+	}
+
+	index, err := elastic.NewClient(cfg.Output.Elasticsearch, cfg.Storage.Encryption.AuthorSources)
+	if err != nil {
+		return err
+	}
+
+	producer, err := kafka.NewProducer(cfg.Output.Kafka, cfg.Storage.Encryption.AuthorSources)
+	if err != nil {
+		return err
+	}
+
+	notifier, err := notify.New(cfg.Notifications, cfg.Relevance)
+	if err != nil {
+		return err
+	}
+	dst := sink{store: store, mirror: mirror, index: index, kafka: producer, jsonl: cfg.Output.JSONL, dedup: cfg.Dedup, out: os.Stdout, configHash: cfg.Hash(), sentiment: cfg.Sentiment.Enabled, notify: notifier, relevance: cfg.Relevance}
+	if *every <= 0 && cron == nil {
+		err := collectOnce(ctx, c, dst, &cfg.Sources, *only, time.Now().UTC())
+		shared.print(dst.out, "")
+		return err
+	}
+
+	live := config.NewLive(cfg)
+	watchConfig(*cfgPath, live, ctx.Done(), nil, store)
+
+	// Con --every la primera ronda es inmediata; con --schedule, la del horario.
+	next := time.Now()
+	if cron != nil {
+		next = cron.Next(next)
+		log.Printf("primera recolección: %s", next.Format("2006-01-02 15:04"))
+	}
+	loaded := cfg
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Until(next)):
+		}
+		// Cada ronda toma la configuración vigente completa.
+		start := time.Now()
+		cfg := live.Get()
+		if cfg != loaded {
+			if err := reloadCollector(c, cfg, store); err != nil {
+				log.Printf("error aplicando la configuración recargada: %v", err)
+			}
+			loaded = cfg
+		}
+		dst.jsonl = cfg.Output.JSONL
+		dst.dedup = cfg.Dedup
+		dst.configHash = cfg.Hash()
+		dst.relevance = cfg.Relevance
+		if dst.notify, err = notify.New(cfg.Notifications, cfg.Relevance); err != nil {
+			log.Printf("error en las notificaciones: %v", err)
+		}
+		if err := collectOnce(ctx, c, dst, &cfg.Sources, *only, start.UTC()); err != nil {
+			log.Printf("error en la recolección: %v", err)
+		}
+		// Las cachés duran lo que el proceso: los contadores son acumulados.
+		shared.print(dst.out, "")
+		next = nextRound(cron, *every, start)
+		log.Printf("próxima recolección: %s", next.Format("2006-01-02 15:04"))
+	}
+}
+
+// reloadCollector aplica a c, entre dos rondas, la configuración de
+// descarga recargada: fetch (reglas por dominio, cookies, enfriamiento),
+// las reglas de extract y la expansión de enlaces. El navegador se reemplaza
+// solo si cambió fetch.render, cerrando el anterior. Los cupos de las
+// fuentes y las cachés compartidas (Transport, Robots) se mantienen.
+func reloadCollector(c *collect.Collector, cfg *config.Config, store *storage.Store) error {
+	links, err := linkExpander(cfg, store)
+	if err != nil {
+		return err
+	}
+	c.Links = links
+	c.Extractor = textExtractor(cfg)
+	if !reflect.DeepEqual(c.Fetch.Render, cfg.Fetch.Render) {
+		if c.Renderer != nil {
+			c.Renderer.Close()
+		}
+		c.Renderer = fetch.NewRenderer(cfg.Fetch.Render)
+	}
+	c.SetFetch(cfg.Fetch)
+	return nil
+}
+
+// sourceOverrides reemplazan, para una ronda, la consulta, el rango o los
+// idiomas de las fuentes que se consultan (--source, o las activadas).
+type sourceOverrides struct {
+	query, from, to, lang string
+}
+
+func (o sourceOverrides) set() bool {
+	return o.query != "" || o.from != "" || o.to != "" || o.lang != ""
+}
+
+// apply aplica los reemplazos y valida el rango resultante de cada fuente.
+func (o sourceOverrides) apply(sources *config.Sources, only string, now time.Time) error {
+	for _, n := range sources.Named() {
+		if (only != "" && n.Name != only) || (only == "" && !n.Enabled) {
+			continue
+		}
+		if o.query != "" {
+			n.Query = o.query
+		}
+		if o.from != "" {
+			n.From = o.from
+		}
+		if o.to != "" {
+			n.To = o.to
+		}
+		if o.lang != "" {
+			n.Languages = nil
+			for _, l := range strings.Split(o.lang, ",") {
+				n.Languages = append(n.Languages, strings.TrimSpace(l))
+			}
+		}
+		if _, _, err := n.Range(now); err != nil {
+			return fmt.Errorf("fuente %s: %w", n.Name, err)
+		}
+	}
+	return nil
+}
+
+var errNoSources = errors.New("no hay fuentes activadas: configure sources.<fuente>.enabled o use --source")
+
+// openMirror abre la réplica en Postgres de output.postgres, o nil si no hay.
+func openMirror(cfg *config.Config) (*sql.DB, error) {
+	if cfg.Output.Postgres == "" {
+		return nil, nil
+	}
+	mirror, err := openPostgres(cfg.Output.Postgres)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.EnsurePostgresSchema(mirror); err != nil {
+		mirror.Close()
+		return nil, err
+	}
+	return mirror, nil
+}
+
+// sink es adónde va una ronda de recolección: el corpus, la réplica en
+// Postgres (si mirror no es nil), el índice de Elasticsearch (si index no es
+// nil), JSONL (si jsonl no está vacío) y el resumen.
+// dedup decide si la misma historia de varias fuentes se guarda una vez.
+// campaign y configHash quedan en el historial de rondas.
+type sink struct {
+	store      *storage.Store
+	mirror     *sql.DB
+	jsonl      string
+	dedup      config.Dedup
+	out        io.Writer
+	campaign   string
+	configHash string
+	// watermarks es la clave de consulta de las fuentes incrementales de la
+	// ronda (ver incremental); sus marcas de agua avanzan al guardar.
+	watermarks map[string]string
+	// retryOf es la ronda cuyas fallas se reintentan (ver runRetryFailures).
+	retryOf int64
+	// sentiment puntúa el tono de cada artículo guardado (config sentiment).
+	sentiment bool
+	// relevance son las reglas con que collectOnce filtra los resultados de
+	// la ronda; rules, las activaciones de sus reglas de supresión, que van
+	// al resumen (ver collect.FilterRelevance).
+	relevance config.Relevance
+	rules     []relevance.RuleHit
+	index     *elastic.Client
+	// kafka publica los artículos guardados (config output.kafka); nil si
+	// no hay brokers.
+	kafka *kafka.Producer
+	// notify avisa a los webhooks de los artículos nuevos (config
+	// notifications); nil si no hay notificaciones.
+	notify *notify.Notifier
+	// run, si no es nil, es la ronda ya registrada (StartRun) con que se
+	// guarda; si no, saveResults registra una (ver collector grpc).
+	run *storage.Run
+}
+
+// collectDry consulta, filtra con las reglas de relevancia rel y muestra los
+// conteos sin guardar.
+func collectDry(ctx context.Context, w io.Writer, c *collect.Collector, sources *config.Sources, rel config.Relevance, only string, now time.Time) error {
+	results := c.Enabled(ctx, sources, only, now)
+	if _, err := collect.FilterRelevance(results, rel, collect.SourceQueries(sources)); err != nil {
+		return err
+	}
+	return printDry(w, c, results)
+}
+
+func printDry(w io.Writer, c *collect.Collector, results []collect.Result) error {
+	if len(results) == 0 {
+		return errNoSources
+	}
+	fmt.Fprintln(w, "\n--- RECOLECCIÓN (sin guardar) ---")
+	for _, r := range results {
+		if r.Err == nil && r.Held == nil {
+			progress.Emit(c.Progress, progress.Event{Type: progress.SourceDone, Source: r.Source, Total: len(r.Articles)})
+		}
+		printCollectResult(w, r, 0)
+	}
+	return nil
+}
+
+// collectOnce hace una ronda de recolección, filtra los resultados con las
+// reglas de relevancia de dst y los guarda en dst. Las fuentes incrementales
+// empiezan en su marca de agua.
+func collectOnce(ctx context.Context, c *collect.Collector, dst sink, sources *config.Sources, only string, now time.Time) error {
+	sources, keys, err := incremental(dst.store, dst.campaign, sources, only)
+	if err != nil {
+		return err
+	}
+	dst.watermarks = keys
+	results := c.Enabled(ctx, sources, only, now)
+	if dst.rules, err = collect.FilterRelevance(results, dst.relevance, collect.SourceQueries(sources)); err != nil {
+		return err
+	}
+	return saveResults(c, dst, results, now)
+}
+
+// newRun es el registro en el historial de una ronda de dst que empieza en
+// now.
+func newRun(dst sink, now time.Time) *storage.Run {
+	stamp := buildinfo.NewStamp(dst.configHash)
+	return &storage.Run{Started: now, Campaign: dst.campaign, ConfigHash: dst.configHash, Version: stamp.Version, Commit: stamp.Revision(), RetryOf: dst.retryOf}
+}
+
+// saveResults guarda lo recolectado en una ronda e imprime el resumen. Con
+// dedup activado, los duplicados de artículos ya guardados (del corpus o de
+// esta misma ronda) no se guardan aparte: quedan en su procedencia, y la
+// réplica y el JSONL reciben solo los artículos nuevos. La ronda queda en el
+// historial (collector runs) con lo que trajo cada fuente y, por artículo, la
+// consulta que lo devolvió; now es su inicio. Las fuentes de dst.watermarks
+// que terminaron bien avanzan su marca de agua. Lo que falla queda registrado
+// para collector retry-failures; en un reintento (dst.retryOf), las fallas de
+// las fuentes que esta vez terminaron bien quedan resueltas.
+func saveResults(c *collect.Collector, dst sink, results []collect.Result, now time.Time) (err error) {
+	if len(results) == 0 {
+		return errNoSources
+	}
+	run := dst.run
+	if run == nil {
+		run = newRun(dst, now)
+		if err := dst.store.StartRun(run); err != nil {
+			return err
+		}
+	}
+	failed, partial, skipped := 0, 0, 0
+	defer func() {
+		status, msg := storage.RunOK, ""
+		switch {
+		case err != nil:
+			status, msg = storage.RunFailed, err.Error()
+		case failed > 0 || partial > 0:
+			status = storage.RunPartial
+		}
+		if ferr := dst.store.FinishRun(run.ID, time.Now(), status, msg); ferr != nil && err == nil {
+			err = ferr
+		}
+	}()
+
+	dd, err := dedup.FromConfig(dst.dedup)
+	if err != nil {
+		return err
+	}
+	var fresh []*article.Article // los guardados, para las notificaciones
+	linker, linked := editions.NewLinker(dst.store), 0
+	// El corpus recibe los artículos; lo que solo guarda la base (detalles,
+	// linaje, procedencia) se agrega con cada uno.
+	saver := &collect.Saver{
+		Store: dst.store,
+		Dedup: dd,
+		Saved: func(a *article.Article) error {
+			if a.Explanation != nil {
+				if err := dst.store.SetExplanation(a.ID, a.Explanation); err != nil {
+					return err
+				}
+			}
+			if a.Extraction != nil {
+				x, err := storage.NewExtraction(a.ID, a.Extraction, now)
+				if err != nil {
+					return err
+				}
+				if err := dst.store.SaveExtraction(x); err != nil {
+					return err
+				}
+			}
+			if err := dst.store.AddAttachments(a.ID, a.Media); err != nil {
+				return err
+			}
+			if a.Engagement != nil {
+				if err := dst.store.SetEngagement(a.ID, a.Engagement, now); err != nil {
+					return err
+				}
+			}
+			if a.Event != nil {
+				if err := dst.store.SetEvent(a.ID, a.Event, now); err != nil {
+					return err
+				}
+			}
+			if a.WARC != nil {
+				if err := dst.store.SetWARCRecord(a.ID, a.WARC); err != nil {
+					return err
+				}
+			}
+			if len(a.Annotations) > 0 {
+				if err := dst.store.SetAnnotations(a.ID, a.Annotations); err != nil {
+					return err
+				}
+			}
+			if a.Geo != nil {
+				if err := dst.store.SetGeo(a.ID, a.Geo); err != nil {
+					return err
+				}
+			}
+			if a.DateIssue != "" {
+				if err := dst.store.SetDateIssue(a.ID, a.DateIssue, a.OriginalPublished); err != nil {
+					return err
+				}
+			}
+			if len(a.References) > 0 {
+				if err := dst.store.AddReferences(a.ID, a.References); err != nil {
+					return err
+				}
+			}
+			if a.Canonical != "" || len(a.Alternates) > 0 {
+				n, err := linker.LinkDeclared(a, &editions.Links{Canonical: a.Canonical, Alternates: a.Alternates})
+				if err != nil {
+					return err
+				}
+				linked += n
+			}
+			if dst.sentiment {
+				if err := scoreSentiment(dst.store, a); err != nil {
+					return err
+				}
+			}
+			if _, err := trends.Ingest(dst.store, a); err != nil {
+				return err
+			}
+			if err := dst.store.AddLineage(run.ID, a.ID, a.Source, a.URL, a.Request); err != nil {
+				return err
+			}
+			if dd != nil {
+				if err := dst.store.AddProvenance(a.ID, a.Source, a.URL, now); err != nil {
+					return err
+				}
+			}
+			fresh = append(fresh, a)
+			return nil
+		},
+		Collapsed: func(orig, a *article.Article, _ string) error {
+			if err := addDuplicate(dst.store, orig, a, now); err != nil {
+				return err
+			}
+			return dst.store.AddLineage(run.ID, orig.ID, a.Source, a.URL, a.Request)
+		},
+	}
+	if err := saver.Load(results); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(dst.out, "\n--- RECOLECCIÓN ---")
+	for _, r := range results {
+		if r.Held != nil {
+			// Desactivada a propósito: queda en la ronda como omitida, sin
+			// contar como falla ni registrar nada para reintentar.
+			skipped++
+			printCollectResult(dst.out, r, 0)
+			src := storage.RunSource{Source: r.Source, Skipped: true, Err: r.Held.Describe()}
+			if err := dst.store.AddRunSource(run.ID, src); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := addRequests(dst.store, run.ID, r); err != nil {
+			return err
+		}
+		if r.Partial || len(r.Failed) > 0 {
+			partial++
+		}
+		if r.Err != nil {
+			failed++
+			printCollectResult(dst.out, r, 0)
+			src := storage.RunSource{Source: r.Source, Partial: r.Partial, Err: r.Err.Error()}
+			if err := dst.store.AddRunSource(run.ID, src); err != nil {
+				return err
+			}
+			if err := addFailures(dst.store, run.ID, r); err != nil {
+				return err
+			}
+			continue
+		}
+		saving, err := saver.Save(r)
+		if err != nil {
+			progress.Emit(c.Progress, progress.Event{Type: progress.Error, Source: r.Source, Message: err.Error()})
+			return err
+		}
+		kept, saved, collapsed := saving.Kept, len(saving.Kept), saving.Duplicates()
+		progress.Emit(c.Progress, progress.Event{Type: progress.ArticlesStored, Source: r.Source, Count: saved, Total: len(r.Articles)})
+		if dst.mirror != nil {
+			if err := dst.store.MirrorToPostgres(dst.mirror, kept); err != nil {
+				return err
+			}
+		}
+		// El índice, Kafka y el JSONL reciben solo lo que los términos de uso
+		// permiten redistribuir.
+		shared, _ := dst.store.ShareAll(kept)
+		if dst.index != nil && len(shared) > 0 {
+			// El corpus ya tiene los artículos: lo que no entró al índice se
+			// carga después con collector index.
+			if _, err := dst.index.Index(context.Background(), shared); err != nil {
+				fmt.Fprintf(dst.out, "  Elasticsearch: %v\n", err)
+			}
+		}
+		if dst.kafka != nil && len(shared) > 0 {
+			if _, err := dst.kafka.Publish(context.Background(), shared); err != nil {
+				fmt.Fprintf(dst.out, "  Kafka: %v\n", err)
+			}
+		}
+		if collapsed > 0 {
+			var parts []string
+			for _, name := range dd.Strategies() {
+				if n := saving.Collapsed[name]; n > 0 {
+					parts = append(parts, fmt.Sprintf("%s %d", name, n))
+				}
+			}
+			fmt.Fprintf(dst.out, "  Duplicados: %d colapsados en artículos ya guardados (%s)\n", collapsed, strings.Join(parts, ", "))
+		}
+		if r.BadDates > 0 {
+			fmt.Fprintf(dst.out, "  Fechas: %d sin interpretar, guardados sin fecha\n", r.BadDates)
+		}
+		if r.FixedDates > 0 {
+			fmt.Fprintf(dst.out, "  Fechas: %d futuras o imposibles, guardados con la fecha de descarga\n", r.FixedDates)
+		}
+		if dst.jsonl != "" {
+			path, err := writeJSONL(dst.jsonl, r.Source, now, shared)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(dst.out, "  JSONL: %s\n", path)
+		}
+		src := storage.RunSource{Source: r.Source, Fetched: len(r.Articles), Stored: saved, Duplicates: collapsed, Partial: r.Partial, BadDates: r.BadDates}
+		if err := dst.store.AddRunSource(run.ID, src); err != nil {
+			return err
+		}
+		if err := dst.store.AddFeedDates(run.ID, r.Dates); err != nil {
+			return err
+		}
+		if err := addFailures(dst.store, run.ID, r); err != nil {
+			return err
+		}
+		// El estado de los feeds se guarda recién ahora, con sus artículos
+		// ya en el corpus: si algo falló antes, la próxima ronda los relee.
+		for _, f := range r.Feeds {
+			if err := dst.store.SaveFeedState(f); err != nil {
+				return err
+			}
+		}
+		if len(r.Failed) > 0 {
+			fmt.Fprintf(dst.out, "  Con error: %d feeds (collector retry-failures --run %d)\n", len(r.Failed), run.ID)
+		}
+		if dst.retryOf != 0 && !r.Partial {
+			var still []string
+			for _, f := range r.Failed {
+				still = append(still, f.Item)
+			}
+			if err := dst.store.ResolveFailures(dst.retryOf, r.Source, still, run.ID); err != nil {
+				return err
+			}
+		}
+		if key, ok := dst.watermarks[r.Source]; ok {
+			mark, err := advanceWatermark(dst.store, dst.campaign, key, r, now)
+			if err != nil {
+				return err
+			}
+			if !mark.IsZero() {
+				fmt.Fprintf(dst.out, "  Marca de agua: %s\n", mark.Format("2006-01-02 15:04"))
+			}
+		}
+		progress.Emit(c.Progress, progress.Event{Type: progress.SourceDone, Source: r.Source, Count: saved, Total: len(r.Articles)})
+		printCollectResult(dst.out, r, saved)
+	}
+	if len(fresh) > 0 {
+		// Las ediciones que el medio no declara se buscan entre lo publicado
+		// desde el más antiguo de los recién guardados.
+		from := now
+		for _, a := range fresh {
+			if !a.Published.IsZero() && a.Published.Before(from) {
+				from = a.Published
+			}
+		}
+		n, err := linker.LinkSimilar(from, now)
+		if err != nil {
+			return err
+		}
+		linked += n
+	}
+	if linked > 0 {
+		fmt.Fprintf(dst.out, "Ediciones: %d enlaces nuevos entre ediciones del mismo medio\n", linked)
+	}
+	if dst.notify != nil {
+		if err := dst.notify.Send(context.Background(), dst.store, fresh, dst.out); err != nil {
+			return err
+		}
+	}
+	if dd != nil {
+		fmt.Fprintf(dst.out, "Deduplicación (coincidencias de probados): %s\n", dedup.FormatStats(dd.Stats()))
+	}
+	if len(dst.rules) > 0 {
+		parts := make([]string, len(dst.rules))
+		for i, h := range dst.rules {
+			parts[i] = fmt.Sprintf("%s %d", h.Rule, h.Hits)
+		}
+		fmt.Fprintf(dst.out, "Reglas de supresión (activaciones): %s\n", strings.Join(parts, ", "))
+	}
+	if c.Links != nil {
+		if s := c.Links.Stats(); s.Lookups() > 0 {
+			fmt.Fprintf(dst.out, "Enlaces acortados: %s\n", s)
+		}
+	}
+	if s := c.Visits(); s.Hits > 0 {
+		fmt.Fprintf(dst.out, "Notas ya descargadas por otra fuente en la ronda: %d (%d pedidas)\n", s.Hits, s.Misses)
+	}
+	if failed > 0 && failed+skipped == len(results) {
+		return fmt.Errorf("todas las fuentes fallaron")
+	}
+	return nil
+}
+
+// addFailures registra lo que falló de r: cada feed que no respondió o, si no
+// hay, la consulta completa si la fuente falló o quedó a medias.
+func addFailures(store *storage.Store, runID int64, r collect.Result) error {
+	items := r.Failed
+	if len(items) == 0 {
+		switch {
+		case r.Err != nil:
+			items = []collect.Failure{{Err: r.Err}}
+		case r.Partial:
+			items = []collect.Failure{{Err: collect.ErrStalled}}
+		}
+	}
+	for _, item := range items {
+		err := store.AddRunFailure(storage.RunFailure{
+			RunID: runID, Source: r.Source, Item: item.Item,
+			Query: r.Attempt.Query, Languages: r.Attempt.Languages, From: r.Attempt.From, To: r.Attempt.To,
+			Err: item.Err.Error(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addRequests registra las peticiones de r a cada API (la de la fuente y, si
+// se consultaron, las de sus reemplazos) para el informe de uso.
+func addRequests(store *storage.Store, runID int64, r collect.Result) error {
+	names := make([]string, 0, len(r.Requests))
+	for name := range r.Requests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := store.AddRunRequests(runID, name, r.Requests[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addDuplicate registra a como otra aparición de orig. La aparición de orig se
+// registra también por si se guardó antes de activar la deduplicación. Si a
+// trae su evento, orig pasa a contar en él: es la misma nota.
+func addDuplicate(store *storage.Store, orig, a *article.Article, now time.Time) error {
+	if err := store.AddProvenance(orig.ID, orig.Source, orig.URL, orig.Collected); err != nil {
+		return err
+	}
+	if a.Event != nil {
+		if err := store.SetEvent(orig.ID, a.Event, now); err != nil {
+			return err
+		}
+	}
+	return store.AddProvenance(orig.ID, a.Source, a.URL, now)
+}
+
+// printCollectResult imprime el resumen de una fuente. En la salida estándar
+// los errores van a stderr; en el resumen de una campaña van junto al resto.
+// Si el crawler entró en pánico se agrega el stack para el reporte. Los
+// avisos de la fuente van debajo.
+func printCollectResult(w io.Writer, r collect.Result, saved int) {
+	if r.Err != nil {
+		if w == os.Stdout {
+			w = os.Stderr
+		}
+		fmt.Fprintf(w, "  %-10s ERROR: %v\n", r.Source, r.Err)
+		var p *collect.PanicError
+		if errors.As(r.Err, &p) {
+			fmt.Fprintf(w, "%s\n", indent(string(p.Stack), "    "))
+		}
+		printWarnings(w, r)
+		return
+	}
+	if r.Held != nil {
+		fmt.Fprintf(w, "  %-10s OMITIDA: %s\n", r.Source, r.Held.Describe())
+		return
+	}
+	partial := ""
+	if r.Partial {
+		partial = " (parcial: " + collect.ErrStalled.Error() + ")"
+	}
+	fmt.Fprintf(w, "  %-10s %d artículos | %d guardados%s\n", r.Source, len(r.Articles), saved, partial)
+	printWarnings(w, r)
+}
+
+func printWarnings(w io.Writer, r collect.Result) {
+	for _, msg := range r.Warnings {
+		fmt.Fprintf(w, "    Aviso: %s\n", msg)
+	}
+}
+
+// writeJSONL escribe los artículos de una fuente, uno por línea. La ruta
+// admite {source} y {date}; si el archivo existe se agrega al final.
+func writeJSONL(pattern, source string, now time.Time, articles []*article.Article) (string, error) {
+	path := strings.NewReplacer("{source}", source, "{date}", now.Format("2006-01-02")).Replace(pattern)
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", fmt.Errorf("error creando directorio %s: %w", dir, err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("error abriendo %s: %w", path, err)
+	}
+	enc := json.NewEncoder(f)
+	for _, a := range articles {
+		if err := enc.Encode(a); err != nil {
+			f.Close()
+			return "", fmt.Errorf("error escribiendo %s: %w", path, err)
+		}
+	}
+	return path, f.Close()
+}
+
+// indent antepone prefix a cada línea de s.
+func indent(s, prefix string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
+
+// linkExpander arma el expansor de enlaces acortados de fetch.expand, con el
+// store como caché; nil si está desactivado.
+func linkExpander(cfg *config.Config, store *storage.Store) (*shortlink.Expander, error) {
+	opts := cfg.Fetch.Expand
+	if opts.Disabled {
+		return nil, nil
+	}
+	rate, err := config.ParseRate(opts.RateLimit)
+	if err != nil {
+		return nil, err
+	}
+	e := shortlink.NewExpander(store, opts.Hosts...)
+	if opts.MaxHops > 0 {
+		e.MaxHops = opts.MaxHops
+	}
+	e.Client.Transport = fetch.Chain(http.DefaultTransport, fetch.RateLimit(rate, 1), fetch.Backoff(store, cfg.Fetch.Backoff))
+	return e, nil
+}
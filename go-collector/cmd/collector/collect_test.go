@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-collector/collect"
+	"go-collector/config"
+	"go-collector/fetch"
+	"go-collector/storage"
+)
+
+// closeCounter es un navegador falso que cuenta sus cierres.
+type closeCounter struct{ closed int }
+
+func (r *closeCounter) Render(context.Context, string) (*fetch.Page, error) { return nil, nil }
+func (r *closeCounter) Close() error                                        { r.closed++; return nil }
+
+// Una recarga entre rondas aplica al Collector la configuración de descarga,
+// las reglas de extracción y la expansión de enlaces; el navegador solo se
+// reemplaza si cambió fetch.render, y las cachés compartidas se mantienen.
+func TestReloadCollector(t *testing.T) {
+	store, err := storage.Open(filepath.Join(t.TempDir(), "corpus.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	old := &config.Config{}
+	old.Fetch.Render = config.Render{Command: "chromium"}
+	old.Fetch.Expand.Disabled = true
+	browser := &closeCounter{}
+	shared := newSharedCaches()
+	transport := shared.dns.Transport()
+	c := &collect.Collector{Transport: transport, Robots: shared.robots, Renderer: browser, Extractor: textExtractor(old), Fetch: old.Fetch}
+
+	cfg := &config.Config{}
+	cfg.Fetch = old.Fetch
+	cfg.Fetch.Expand.Disabled = false
+	cfg.Fetch.Domains = map[string]config.DomainRule{"udea.edu.co": {Headers: map[string]string{"Accept-Language": "es-CO"}}}
+	cfg.Fetch.Backoff.Base = 2 * time.Minute
+	cfg.Extract.Domains = map[string]config.ExtractRule{"www.elcolombiano.com": {Body: "div.nota"}}
+	if err := reloadCollector(c, cfg, store); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.Fetch, cfg.Fetch) {
+		t.Errorf("fetch %+v, se esperaba %+v", c.Fetch, cfg.Fetch)
+	}
+	if rule, ok := c.Extractor.Rules["elcolombiano.com"]; !ok || rule.Body != "div.nota" {
+		t.Errorf("reglas de extracción %+v", c.Extractor.Rules)
+	}
+	if c.Links == nil {
+		t.Error("la expansión de enlaces no se activó")
+	}
+	if c.Renderer != browser || browser.closed != 0 {
+		t.Errorf("con el mismo fetch.render se reemplazó el navegador (%d cierres)", browser.closed)
+	}
+	if c.Transport != transport || c.Robots != shared.robots {
+		t.Error("la recarga reemplazó las cachés compartidas")
+	}
+
+	next := &config.Config{}
+	next.Fetch = cfg.Fetch
+	next.Fetch.Render = config.Render{Command: "google-chrome", MaxConcurrent: 1}
+	next.Fetch.Expand.Disabled = true
+	if err := reloadCollector(c, next, store); err != nil {
+		t.Fatal(err)
+	}
+	if browser.closed != 1 {
+		t.Errorf("el navegador anterior se cerró %d veces, se esperaba 1", browser.closed)
+	}
+	if chrome, ok := c.Renderer.(*fetch.Chrome); !ok || chrome.Command != "google-chrome" || chrome.MaxConcurrent != 1 {
+		t.Errorf("navegador %#v, se esperaba el de la configuración nueva", c.Renderer)
+	}
+	if c.Links != nil || len(c.Extractor.Rules) != 0 {
+		t.Errorf("quedaron la expansión de enlaces o las reglas de la configuración anterior")
+	}
+	c.Renderer.Close()
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// completionFlag es una opción tal como la necesitan los scripts.
+type completionFlag struct {
+	name     string
+	usage    string
+	takesArg bool
+}
+
+// completionInfo reúne, por comando, sus acciones y la unión de las opciones
+// de todas sus acciones.
+func completionInfo(cmd *command) []completionFlag {
+	seen := make(map[string]bool)
+	var out []completionFlag
+	sets := commandFlags(cmd)
+	keys := make([]string, 0, len(sets))
+	for k := range sets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sets[k].VisitAll(func(f *flag.Flag) {
+			if seen[f.Name] {
+				return
+			}
+			seen[f.Name] = true
+			_, usage := flag.UnquoteUsage(f)
+			bf, isBool := f.Value.(interface{ IsBoolFlag() bool })
+			out = append(out, completionFlag{name: f.Name, usage: usage, takesArg: !(isBool && bf.IsBoolFlag())})
+		})
+	}
+	return out
+}
+
+// runCompletion escribe el script de autocompletado del shell pedido.
+func runCompletion(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("uso: collector completion bash|zsh|fish")
+	}
+	switch args[0] {
+	case "bash":
+		writeBash(os.Stdout)
+	case "zsh":
+		writeZsh(os.Stdout)
+	case "fish":
+		writeFish(os.Stdout)
+	default:
+		return fmt.Errorf("shell no soportado: %s (use bash, zsh o fish)", args[0])
+	}
+	return nil
+}
+
+func commandNames() []string {
+	names := make([]string, len(commands))
+	for i, c := range commands {
+		names[i] = c.name
+	}
+	return names
+}
+
+func writeBash(w io.Writer) {
+	fmt.Fprintf(w, `# Autocompletado de collector para bash. Generado con "collector completion bash".
+_collector() {
+    local cur prev cmd i
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    cmd=""
+    i=1
+    if [[ "${COMP_WORDS[1]}" == "--profile" ]]; then
+        i=3
+    fi
+    if (( COMP_CWORD == i - 1 )) && [[ "$prev" == "--profile" ]]; then
+        return
+    fi
+    if (( COMP_CWORD == i )); then
+        COMPREPLY=( $(compgen -W "--profile %s" -- "$cur") )
+        return
+    fi
+    cmd="${COMP_WORDS[i]}"
+
+    local actions="" flags="" argflags=""
+    case "$cmd" in
+`, strings.Join(commandNames(), " "))
+	for i := range commands {
+		cmd := &commands[i]
+		flags := completionInfo(cmd)
+		var all, withArg []string
+		for _, f := range flags {
+			all = append(all, "--"+f.name)
+			if f.takesArg {
+				withArg = append(withArg, "--"+f.name)
+			}
+		}
+		if cmd.name == "help" {
+			cmd.actions = commandNames()
+		}
+		fmt.Fprintf(w, "        %s) actions=%q; flags=%q; argflags=%q ;;\n",
+			cmd.name, strings.Join(cmd.actions, " "), strings.Join(all, " "), strings.Join(withArg, " "))
+	}
+	fmt.Fprint(w, `    esac
+
+    # Valor de una opción: se deja el completado de archivos por defecto.
+    if [[ " $argflags " == *" $prev "* ]]; then
+        return
+    fi
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=( $(compgen -W "$flags" -- "$cur") )
+    elif (( COMP_CWORD == i + 1 )) && [[ -n "$actions" ]]; then
+        COMPREPLY=( $(compgen -W "$actions" -- "$cur") )
+    fi
+}
+complete -o default -F _collector collector
+`)
+}
+
+func writeZsh(w io.Writer) {
+	fmt.Fprint(w, `#compdef collector
+# Autocompletado de collector para zsh. Generado con "collector completion zsh".
+_collector() {
+    local -a cmds
+    if [[ "${words[2]}" == "--profile" ]]; then
+        words=("${words[1]}" "${(@)words[4,-1]}")
+        (( CURRENT -= 2 ))
+        (( CURRENT < 2 )) && return
+    fi
+    cmds=(
+`)
+	for _, c := range commands {
+		fmt.Fprintf(w, "        %s\n", zshQuote(c.name+":"+c.summary))
+	}
+	fmt.Fprint(w, `    )
+    if (( CURRENT == 2 )); then
+        _describe 'comando' cmds
+        return
+    fi
+    local cmd="${words[2]}"
+    shift words
+    (( CURRENT-- ))
+    case "$cmd" in
+`)
+	for i := range commands {
+		cmd := &commands[i]
+		specs := []string{}
+		actions := cmd.actions
+		if cmd.name == "help" {
+			actions = commandNames()
+		}
+		if len(actions) > 0 {
+			specs = append(specs, zshQuote("1:acción:("+strings.Join(actions, " ")+")"))
+		}
+		for _, f := range completionInfo(cmd) {
+			spec := "--" + f.name + "[" + zshEscape(f.usage) + "]"
+			if f.takesArg {
+				spec += ":" + f.name + ":_files"
+			}
+			specs = append(specs, zshQuote(spec))
+		}
+		if len(specs) == 0 {
+			fmt.Fprintf(w, "        %s) ;;\n", cmd.name)
+			continue
+		}
+		fmt.Fprintf(w, "        %s) _arguments \\\n            %s ;;\n", cmd.name, strings.Join(specs, " \\\n            "))
+	}
+	fmt.Fprint(w, `    esac
+}
+_collector "$@"
+`)
+}
+
+func writeFish(w io.Writer) {
+	fmt.Fprintln(w, `# Autocompletado de collector para fish. Generado con "collector completion fish".`)
+	fmt.Fprintln(w, "complete -c collector -f")
+	fmt.Fprintln(w, `complete -c collector -n __fish_use_subcommand -l profile -r -d 'perfil de configuración'`)
+	for i := range commands {
+		cmd := &commands[i]
+		fmt.Fprintf(w, "complete -c collector -n __fish_use_subcommand -a %s -d %s\n", cmd.name, fishQuote(cmd.summary))
+		cond := fishQuote("__fish_seen_subcommand_from " + cmd.name)
+		actions := cmd.actions
+		if cmd.name == "help" {
+			actions = commandNames()
+		}
+		if len(actions) > 0 {
+			fmt.Fprintf(w, "complete -c collector -n %s -a %s\n", cond, fishQuote(strings.Join(actions, " ")))
+		}
+		for _, f := range completionInfo(cmd) {
+			line := fmt.Sprintf("complete -c collector -n %s -l %s -d %s", cond, f.name, fishQuote(f.usage))
+			if f.takesArg {
+				// -r: requiere valor; -F: se completa con archivos.
+				line += " -r -F"
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+}
+
+// zshQuote envuelve en comillas simples para zsh.
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// zshEscape escapa los caracteres especiales de una descripción de _arguments.
+func zshEscape(s string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`, ":", `\:`).Replace(s)
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
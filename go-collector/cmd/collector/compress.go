@@ -0,0 +1,411 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"go-collector/compress"
+	"go-collector/config"
+	"go-collector/rawarchive"
+	"go-collector/storage"
+)
+
+// runCompress administra la compresión del corpus (storage.compression):
+// train entrena los diccionarios, apply recomprime lo ya guardado, bench
+// mide cuánto se ahorra y status muestra lo que ocupa hoy.
+func runCompress(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: collector compress train|apply|bench|status [opciones]")
+	}
+	switch args[0] {
+	case "train":
+		return compressTrain(args[1:])
+	case "apply":
+		return compressApply(args[1:])
+	case "bench":
+		return compressBench(args[1:])
+	case "status":
+		return compressStatus(args[1:])
+	default:
+		return fmt.Errorf("acción desconocida: %s (use train, apply, bench o status)", args[0])
+	}
+}
+
+// compressTarget es lo que se comprime: los cuerpos del corpus, el archivo
+// de páginas crudas o ambos.
+type compressTarget struct {
+	cfg     *config.Config
+	store   *storage.Store
+	archive *rawarchive.Archive
+}
+
+// openCompressTarget abre lo que indica target (bodies, raw o all). Con all
+// y sin archivo crudo configurado, solo los cuerpos.
+func openCompressTarget(cfgPath, dbPath, target string) (*compressTarget, error) {
+	if target != "all" && target != "bodies" && target != "raw" {
+		return nil, fmt.Errorf("--target desconocido: %s (use bodies, raw o all)", target)
+	}
+	cfg, err := loadConfig(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+	t := &compressTarget{cfg: cfg}
+	if target != "bodies" {
+		if t.archive, err = openArchive(cfg); err != nil {
+			return nil, err
+		}
+		if t.archive == nil && target == "raw" {
+			return nil, fmt.Errorf("no hay archivo de páginas crudas: configure storage.archive_dir")
+		}
+	}
+	if target != "raw" {
+		if t.store, err = openStore(dbPath, cfg); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (t *compressTarget) Close() {
+	if t.store != nil {
+		t.store.Close()
+	}
+}
+
+func compressTrain(args []string) error {
+	fs := flag.NewFlagSet("compress train", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	target := fs.String("target", "all", "qué entrenar: bodies, raw o all")
+	samples := fs.Int("samples", 2000, "textos o páginas al azar con los que se entrena")
+	size := fs.Int("size", compress.DefaultDictSize>>10, "tamaño máximo del diccionario, en KB")
+	parseFlags(fs, args)
+
+	t, err := openCompressTarget(*cfgPath, *dbPath, *target)
+	if err != nil {
+		return err
+	}
+	defer t.Close()
+	level := t.cfg.Storage.Compression.Level
+
+	if t.store != nil {
+		texts, err := t.store.BodySamples(*samples)
+		if err != nil {
+			return err
+		}
+		d, err := compress.Train(texts, *size<<10, level)
+		if err != nil {
+			return fmt.Errorf("cuerpos: %w", err)
+		}
+		if err := t.store.AddBodyDict(d, len(texts)); err != nil {
+			return err
+		}
+		fmt.Printf("Cuerpos: diccionario %d (%.1f KB) entrenado con %d textos.\n", d.ID, float64(len(d.Data))/1024, len(texts))
+	}
+	if t.archive != nil {
+		pages, err := t.archive.Samples(*samples)
+		if err != nil {
+			return err
+		}
+		d, err := compress.Train(pages, *size<<10, level)
+		if err != nil {
+			return fmt.Errorf("páginas crudas: %w", err)
+		}
+		if err := t.archive.AddDict(d); err != nil {
+			return err
+		}
+		fmt.Printf("Páginas crudas: diccionario %d (%.1f KB) entrenado con %d páginas.\n", d.ID, float64(len(d.Data))/1024, len(pages))
+	}
+	if !t.cfg.Storage.Compression.Enabled {
+		fmt.Println("Active storage.compression para usarlos y recomprima lo guardado con collector compress apply.")
+	} else {
+		fmt.Println("Lo que se guarde desde ahora usa los diccionarios nuevos; collector compress apply recomprime lo anterior.")
+	}
+	return nil
+}
+
+func compressApply(args []string) error {
+	fs := flag.NewFlagSet("compress apply", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	target := fs.String("target", "all", "qué recomprimir: bodies, raw o all")
+	parseFlags(fs, args)
+
+	t, err := openCompressTarget(*cfgPath, *dbPath, *target)
+	if err != nil {
+		return err
+	}
+	defer t.Close()
+	if !t.cfg.Storage.Compression.Enabled {
+		fmt.Println("storage.compression no está activa: lo comprimido vuelve a texto (cuerpos) y a gzip (páginas crudas).")
+	}
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	if t.store != nil {
+		changed, before, after, err := t.store.RecompressBodies(ctx, func(done, total int) {
+			fmt.Printf("\rCuerpos: %d/%d", done, total)
+		})
+		fmt.Println()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Cuerpos: %d reescritos, %s\n", changed, sizeChange(before, after))
+	}
+	if t.archive != nil {
+		changed, before, after, err := t.archive.Recompress(func(done int) {
+			if done%500 == 0 {
+				fmt.Printf("\rPáginas crudas: %d", done)
+			}
+		})
+		fmt.Println()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Páginas crudas: %d reescritas, %s\n", changed, sizeChange(before, after))
+	}
+	return nil
+}
+
+func sizeChange(before, after int64) string {
+	out := fmt.Sprintf("%.1f MB -> %.1f MB", float64(before)/(1<<20), float64(after)/(1<<20))
+	if before > 0 {
+		out += fmt.Sprintf(" (%+.0f%%)", 100*float64(after-before)/float64(before))
+	}
+	return out
+}
+
+// benchResult es la medición de un método sobre las muestras de prueba.
+type benchResult struct {
+	name       string
+	original   int64
+	compressed int64
+	encode     time.Duration
+	decode     time.Duration
+}
+
+// benchMethod es una variante de zstd a medir.
+type benchMethod struct {
+	name string
+	dict *compress.Dict
+}
+
+func (r benchResult) print() {
+	mbps := func(d time.Duration) string {
+		if d <= 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.0f MB/s", float64(r.original)/(1<<20)/d.Seconds())
+	}
+	fmt.Printf("  %-38s %10.1f KB %7.2fx %12s %12s\n", r.name, float64(r.compressed)/1024,
+		float64(r.original)/float64(max(r.compressed, 1)), mbps(r.encode), mbps(r.decode))
+}
+
+func compressBench(args []string) error {
+	fs := flag.NewFlagSet("compress bench", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	target := fs.String("target", "all", "qué medir: bodies, raw o all")
+	samples := fs.Int("samples", 1000, "textos o páginas al azar que se miden (la mitad entrena el diccionario de prueba)")
+	size := fs.Int("size", compress.DefaultDictSize>>10, "tamaño máximo del diccionario de prueba, en KB")
+	parseFlags(fs, args)
+
+	t, err := openCompressTarget(*cfgPath, *dbPath, *target)
+	if err != nil {
+		return err
+	}
+	defer t.Close()
+	level := t.cfg.Storage.Compression.Level
+	if level == "" {
+		level = "default"
+	}
+
+	if t.store != nil {
+		texts, err := t.store.BodySamples(*samples)
+		if err != nil {
+			return err
+		}
+		var current *compress.Dict
+		dicts, err := t.store.BodyDicts()
+		if err != nil {
+			return err
+		}
+		if len(dicts) > 0 {
+			current = &dicts[len(dicts)-1].Dict
+		}
+		if err := bench("CUERPOS", texts, current, *size<<10, level); err != nil {
+			return err
+		}
+	}
+	if t.archive != nil {
+		pages, err := t.archive.Samples(*samples)
+		if err != nil {
+			return err
+		}
+		dicts, err := t.archive.Dicts()
+		if err != nil {
+			return err
+		}
+		var current *compress.Dict
+		if len(dicts) > 0 {
+			current = dicts[len(dicts)-1]
+		}
+		if err := bench("PÁGINAS CRUDAS", pages, current, *size<<10, level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bench mide gzip, zstd sin diccionario y zstd con un diccionario entrenado
+// con la mitad de las muestras sobre la otra mitad (y con el diccionario
+// guardado, si hay), comprimiendo cada muestra por separado como se guardan.
+func bench(title string, samples [][]byte, current *compress.Dict, size int, level string) error {
+	if len(samples) < 20 {
+		fmt.Printf("\n--- %s ---\n  muy pocas muestras (%d) para medir\n", title, len(samples))
+		return nil
+	}
+	train, test := samples[:len(samples)/2], samples[len(samples)/2:]
+	var original int64
+	for _, s := range test {
+		original += int64(len(s))
+	}
+	fmt.Printf("\n--- %s: %d muestras de prueba, %.1f MB, %.1f KB de media ---\n",
+		title, len(test), float64(original)/(1<<20), float64(original)/float64(len(test))/1024)
+	fmt.Printf("  %-38s %13s %8s %12s %12s\n", "método", "comprimido", "razón", "compresión", "lectura")
+
+	var results []benchResult
+	r := benchResult{name: "gzip (archivo crudo actual)", original: original}
+	start := time.Now()
+	gz := make([][]byte, len(test))
+	for i, s := range test {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(s)
+		zw.Close()
+		gz[i] = buf.Bytes()
+		r.compressed += int64(buf.Len())
+	}
+	r.encode = time.Since(start)
+	start = time.Now()
+	for _, c := range gz {
+		zr, err := gzip.NewReader(bytes.NewReader(c))
+		if err != nil {
+			return err
+		}
+		io.Copy(io.Discard, zr)
+	}
+	r.decode = time.Since(start)
+	results = append(results, r)
+
+	methods := []benchMethod{{"zstd " + level + " sin diccionario", nil}}
+	trained, err := compress.Train(train, size, level)
+	if err != nil {
+		fmt.Printf("  (sin diccionario de prueba: %v)\n", err)
+	} else {
+		methods = append(methods, benchMethod{fmt.Sprintf("zstd %s + diccionario (%.0f KB)", level, float64(len(trained.Data))/1024), trained})
+	}
+	if current != nil {
+		methods = append(methods, benchMethod{fmt.Sprintf("zstd %s + diccionario %d", level, current.ID), current})
+	}
+	for _, m := range methods {
+		enc, err := compress.NewEncoder(level, m.dict)
+		if err != nil {
+			return err
+		}
+		r := benchResult{name: m.name, original: original}
+		out := make([][]byte, len(test))
+		start := time.Now()
+		for i, s := range test {
+			out[i] = enc.Encode(s)
+			r.compressed += int64(len(out[i]))
+		}
+		r.encode = time.Since(start)
+		enc.Close()
+		start = time.Now()
+		for i, c := range out {
+			back, err := compress.Decode(c)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(back, test[i]) {
+				return fmt.Errorf("%s: la muestra %d no se recupera igual", m.name, i)
+			}
+		}
+		r.decode = time.Since(start)
+		results = append(results, r)
+	}
+	for _, r := range results {
+		r.print()
+	}
+	return nil
+}
+
+func compressStatus(args []string) error {
+	fs := flag.NewFlagSet("compress status", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	parseFlags(fs, args)
+
+	t, err := openCompressTarget(*cfgPath, *dbPath, "all")
+	if err != nil {
+		return err
+	}
+	defer t.Close()
+	c := t.cfg.Storage.Compression
+	if c.Enabled {
+		level := c.Level
+		if level == "" {
+			level = "default"
+		}
+		fmt.Printf("Compresión: activa (zstd %s)\n", level)
+	} else {
+		fmt.Println("Compresión: inactiva (storage.compression)")
+	}
+
+	b, err := t.store.BodyStorage()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n--- CUERPOS ---\n  %d artículos con cuerpo, %d comprimidos\n", b.Articles, b.Compressed)
+	if b.Stored > 0 {
+		fmt.Printf("  %.1f MB guardados, %.1f MB de texto (%.2fx)\n", float64(b.Stored)/(1<<20), float64(b.Text)/(1<<20), float64(b.Text)/float64(b.Stored))
+	}
+	dicts, err := t.store.BodyDicts()
+	if err != nil {
+		return err
+	}
+	for i, d := range dicts {
+		mark := ""
+		if i == len(dicts)-1 {
+			mark = " (actual)"
+		}
+		fmt.Printf("  diccionario %d: %.1f KB, %d textos, %s%s\n", d.ID, float64(len(d.Data))/1024, d.Samples, d.Created.Local().Format("2006-01-02 15:04"), mark)
+	}
+
+	if t.archive == nil {
+		return nil
+	}
+	u, err := t.archive.Usage()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n--- PÁGINAS CRUDAS ---\n  gzip: %d archivos, %.1f MB\n  zstd: %d archivos, %.1f MB\n",
+		u.Gzip, float64(u.GzipBytes)/(1<<20), u.Zstd, float64(u.ZstdBytes)/(1<<20))
+	adicts, err := t.archive.Dicts()
+	if err != nil {
+		return err
+	}
+	for i, d := range adicts {
+		mark := ""
+		if i == len(adicts)-1 {
+			mark = " (actual)"
+		}
+		fmt.Printf("  diccionario %d: %.1f KB%s\n", d.ID, float64(len(d.Data))/1024, mark)
+	}
+	return nil
+}
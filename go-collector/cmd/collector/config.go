@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+
+	"go-collector/config"
+	"go-collector/encrypt"
+	"go-collector/extract"
+	"go-collector/rawarchive"
+	"go-collector/storage"
+)
+
+// defaultConfigPath es el archivo que se busca si no se indica --config.
+const defaultConfigPath = "config.yaml"
+
+// loadConfig carga la configuración. Si no se indicó un archivo y no existe
+// config.yaml, se usa la configuración vacía (valores por defecto).
+func loadConfig(path string) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil && path == defaultConfigPath && errors.Is(err, fs.ErrNotExist) {
+		return &config.Config{}, nil
+	}
+	return cfg, err
+}
+
+// openStore abre el corpus con el cifrado de campos de la configuración y
+// registra en la auditoría si la configuración cambió desde la última vez.
+func openStore(path string, cfg *config.Config) (*storage.Store, error) {
+	c, err := encrypt.FromConfig(cfg.Storage.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	store, err := storage.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		store.EncryptAuthors(c, cfg.Storage.Encryption.AuthorSources)
+	}
+	if cfg.Storage.Compression.Enabled {
+		if err := store.CompressBodies(cfg.Storage.Compression.Level); err != nil {
+			store.Close()
+			return nil, err
+		}
+	}
+	if err := auditConfig(store, cfg); err != nil {
+		store.Close()
+		return nil, err
+	}
+	if err := store.SyncPublishers(publishers(cfg)); err != nil {
+		store.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// openArchive devuelve el archivo de páginas crudas configurado, o nil si no hay.
+func openArchive(cfg *config.Config) (*rawarchive.Archive, error) {
+	if cfg.Storage.ArchiveDir == "" {
+		return nil, nil
+	}
+	c, err := encrypt.FromConfig(cfg.Storage.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	archive := &rawarchive.Archive{Dir: cfg.Storage.ArchiveDir, Cipher: c}
+	if cfg.Storage.Compression.Enabled {
+		if err := archive.EnableCompression(cfg.Storage.Compression.Level); err != nil {
+			return nil, err
+		}
+	}
+	return archive, nil
+}
+
+// watchConfig recarga la configuración de un daemon cuando cambia el archivo:
+// la nueva se instala en live de una vez, se deja en el log y en la auditoría
+// de cada corpus en stores un resumen de lo que cambió y se llama a onReload
+// (puede ser nil). Si el
+// archivo nuevo es inválido se sigue con la configuración anterior.
+func watchConfig(path string, live *config.Live, stop <-chan struct{}, onReload func(), stores ...*storage.Store) {
+	if _, err := os.Stat(path); err != nil {
+		// Sin archivo (configuración por defecto) no hay nada que vigilar.
+		return
+	}
+	w := &config.Watcher{
+		Path: path,
+		Live: live,
+		OnReload: func(old, cfg *config.Config, changes []string) {
+			log.Printf("configuración recargada desde %s: %s", path, strings.Join(changes, "; "))
+			detail := "recarga en caliente: " + strings.Join(changes, "; ")
+			for _, store := range stores {
+				if err := audit(store, storage.AuditConfigChange, cfg.Hash(), detail); err != nil {
+					log.Printf("error registrando la recarga en la auditoría: %v", err)
+				}
+				if err := store.SyncPublishers(publishers(cfg)); err != nil {
+					log.Printf("error registrando los términos de uso: %v", err)
+				}
+			}
+			if onReload != nil {
+				onReload()
+			}
+		},
+		OnError: func(err error) {
+			log.Printf("configuración nueva ignorada, se mantiene la anterior: %v", err)
+		},
+	}
+	go w.Run(stop)
+}
+
+// publishers convierte los términos de uso de la configuración en los
+// registros de la tabla publishers; text vacío es share.
+func publishers(cfg *config.Config) []storage.Publisher {
+	var out []storage.Publisher
+	for _, p := range cfg.Publishers {
+		sp := storage.Publisher{Kind: "source", Name: p.Source, TOS: p.TOS, Text: p.Text, Exclude: p.Exclude, License: p.License}
+		if p.Domain != "" {
+			sp.Kind, sp.Name = "domain", strings.TrimPrefix(strings.ToLower(p.Domain), "www.")
+		}
+		if sp.Text == "" {
+			sp.Text = storage.TextShare
+		}
+		out = append(out, sp)
+	}
+	return out
+}
+
+// textExtractor arma el extractor de texto completo con las reglas por
+// dominio de la configuración.
+func textExtractor(cfg *config.Config) extract.Extractor {
+	e := extract.Extractor{Rules: map[string]extract.Rule{}}
+	for domain, r := range cfg.Extract.Domains {
+		e.Rules[strings.TrimPrefix(strings.ToLower(domain), "www.")] = extract.Rule{Body: r.Body, Author: r.Author, Date: r.Date}
+	}
+	return e
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+
+	"go-collector/config"
+)
+
+// runConfigCmd maneja "config validate": revisa el archivo sin ejecutar nada,
+// para detectar errores antes de la próxima ejecución programada.
+func runConfigCmd(args []string) error {
+	if len(args) == 0 || args[0] != "validate" {
+		return fmt.Errorf("uso: collector config validate [--config archivo]")
+	}
+	fs := flag.NewFlagSet("config validate", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	parseFlags(fs, args[1:])
+	if fs.NArg() > 0 {
+		*cfgPath = fs.Arg(0)
+	}
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	name := *cfgPath
+	if cfg.Profile != "" {
+		name += " (perfil " + cfg.Profile + ")"
+	}
+	fmt.Printf("%s es válido (%d campañas, %d reportes; hash %s).\n", name, len(cfg.Campaigns), len(cfg.Reports), cfg.Hash())
+
+	// Sin perfil elegido se revisa además cada perfil sobre la base, para no
+	// descubrir un error de prod recién al desplegar.
+	if cfg.Profile != "" {
+		return nil
+	}
+	profiles := make([]string, 0, len(cfg.Profiles))
+	for p := range cfg.Profiles {
+		profiles = append(profiles, p)
+	}
+	sort.Strings(profiles)
+	for _, p := range profiles {
+		pc, err := config.LoadProfile(*cfgPath, p)
+		if err != nil {
+			return fmt.Errorf("perfil %s: %w", p, err)
+		}
+		fmt.Printf("  perfil %-10s válido (hash %s)\n", p, pc.Hash())
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-collector/storage"
+)
+
+// runDates muestra la calidad de las fechas de publicación por feed: cuántos
+// artículos llegaron sin fecha interpretable, con fecha futura o imposible
+// (la época Unix). Los feeds con problemas conviene revisarlos o corregir su
+// zona horaria antes de usar sus fechas en series de tiempo.
+func runDates(args []string) error {
+	fs := flag.NewFlagSet("dates", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	since := fs.String("since", "", "rondas desde esta fecha (AAAA-MM-DD)")
+	source := fs.String("source", "", "solo estas fuentes (separadas por comas)")
+	all := fs.Bool("all", false, "incluir los feeds sin problemas")
+	format := fs.String("format", "text", "formato: text, json o csv")
+	parseFlags(fs, args)
+	if *format != "text" && *format != "json" && *format != "csv" {
+		return fmt.Errorf("formato desconocido: %s (use text, json o csv)", *format)
+	}
+
+	var from time.Time
+	if *since != "" {
+		t, err := time.Parse("2006-01-02", *since)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --since: %w", err)
+		}
+		from = t
+	}
+	var sources []string
+	if *source != "" {
+		sources = strings.Split(*source, ",")
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	quality, err := store.DateQuality(from, sources)
+	if err != nil {
+		return err
+	}
+	var feeds []storage.FeedDates
+	for _, f := range quality {
+		if *all || f.Problems() > 0 {
+			feeds = append(feeds, f)
+		}
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(feeds)
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"source", "feed", "rounds", "items", "unparsed", "future", "bogus", "example"})
+		for _, f := range feeds {
+			w.Write([]string{f.Source, f.Feed, strconv.Itoa(f.Rounds), strconv.Itoa(f.Items),
+				strconv.Itoa(f.Unparsed), strconv.Itoa(f.Future), strconv.Itoa(f.Bogus), f.Example})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	if len(quality) == 0 {
+		fmt.Println("No hay rondas con fechas registradas.")
+		return nil
+	}
+	if len(feeds) == 0 {
+		fmt.Printf("Las fechas de los %d feeds son válidas.\n", len(quality))
+		return nil
+	}
+	fmt.Println("\nCalidad de las fechas de publicación por feed (sin interpretar: guardados sin fecha;")
+	fmt.Println("futuras e imposibles: guardados con la fecha de descarga):")
+	fmt.Printf("\n%-12s %9s %9s %9s %9s %7s  %s\n", "fuente", "artículos", "sin int.", "futuras", "imposib.", "%", "feed")
+	for _, f := range feeds {
+		fmt.Printf("%-12s %9d %9d %9d %9d %6.1f%%  %s\n", f.Source, f.Items, f.Unparsed, f.Future, f.Bogus,
+			100*float64(f.Problems())/float64(max(f.Items, 1)), f.Feed)
+		if f.Example != "" {
+			fmt.Printf("%-12s ej: %s\n", "", f.Example)
+		}
+	}
+	return nil
+}
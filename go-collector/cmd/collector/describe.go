@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"go-collector/stats"
+	"go-collector/storage"
+)
+
+func runDescribe(args []string) error {
+	fs := flag.NewFlagSet("describe", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	format := fs.String("format", "markdown", "formato de la tabla: markdown o latex")
+	parseFlags(fs, args)
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	desc, err := stats.Describe(store)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "markdown", "md":
+		return desc.WriteMarkdown(os.Stdout)
+	case "latex", "tex":
+		return desc.WriteLaTeX(os.Stdout)
+	default:
+		return fmt.Errorf("formato desconocido: %s (use markdown o latex)", *format)
+	}
+}
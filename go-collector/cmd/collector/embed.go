@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"go-collector/embed"
+)
+
+func runEmbed(args []string) error {
+	fs := flag.NewFlagSet("embed", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	dedup := fs.Float64("dedup", 0, "si es > 0, lista pares de artículos con similitud >= este umbral")
+	parseFlags(fs, args)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	provider, err := embed.NewProvider(cfg.Embeddings)
+	if err != nil {
+		return err
+	}
+
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	n, err := embed.NewStage(provider, store, cfg.Embeddings.BatchSize).Run(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Embeddings calculados con %s: %d\n", provider.Name(), n)
+
+	if *dedup > 0 {
+		pairs, err := embed.NearDuplicates(store, provider.Name(), *dedup)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\nPares casi duplicados (similitud >= %.2f): %d\n", *dedup, len(pairs))
+		for i, p := range pairs {
+			fmt.Printf("  %3d. #%d ~ #%d (%.3f)\n", i+1, p.A, p.B, p.Score)
+		}
+	}
+	return nil
+}
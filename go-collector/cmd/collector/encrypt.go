@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"go-collector/encrypt"
+	"go-collector/storage"
+)
+
+// runEncrypt maneja el cifrado en reposo: keygen genera una clave y apply
+// cifra lo que se guardó antes de activarlo.
+func runEncrypt(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: collector encrypt keygen|apply")
+	}
+	switch args[0] {
+	case "keygen":
+		fs := flag.NewFlagSet("encrypt keygen", flag.ExitOnError)
+		parseFlags(fs, args[1:])
+
+		key, err := encrypt.GenerateKey()
+		if err != nil {
+			return err
+		}
+		fmt.Println(key)
+		return nil
+
+	case "apply":
+		fs := flag.NewFlagSet("encrypt apply", flag.ExitOnError)
+		dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+		cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+		parseFlags(fs, args[1:])
+
+		cfg, err := loadConfig(*cfgPath)
+		if err != nil {
+			return err
+		}
+		c, err := encrypt.FromConfig(cfg.Storage.Encryption)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return fmt.Errorf("configure storage.encryption.key_file o key_env antes de cifrar")
+		}
+
+		store, err := openStore(*dbPath, cfg)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		authors, err := store.EncryptExistingAuthors()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Autores cifrados (artículos y referencias): %d\n", authors)
+
+		archive, err := openArchive(cfg)
+		if err != nil {
+			return err
+		}
+		files := 0
+		if archive != nil {
+			if files, err = archive.EncryptAll(); err != nil {
+				return err
+			}
+			fmt.Printf("Páginas crudas cifradas: %d\n", files)
+		}
+		return audit(store, storage.AuditEncrypt, *dbPath, fmt.Sprintf("autores: %d, páginas crudas: %d", authors, files))
+
+	default:
+		return fmt.Errorf("acción desconocida: %s (use keygen o apply)", args[0])
+	}
+}
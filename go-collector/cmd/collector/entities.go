@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"go-collector/entities"
+	"go-collector/storage"
+)
+
+// entityKindNames son los nombres de los tipos de entidad en los informes.
+var entityKindNames = map[string]string{
+	entities.Person:       "Personas",
+	entities.Organization: "Organizaciones",
+	entities.Place:        "Lugares",
+	entities.Keyword:      "Palabras clave",
+}
+
+// runEntities extrae las personas, organizaciones y lugares de los artículos
+// activos, y sus palabras clave frente al resto del corpus. Por defecto solo
+// analiza los que no se analizaron antes; stats muestra luego las entidades
+// en tendencia.
+func runEntities(args []string) error {
+	fs := flag.NewFlagSet("entities", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	all := fs.Bool("all", false, "volver a analizar también los ya analizados")
+	limit := fs.Int("limit", 0, "analizar como máximo esta cantidad de artículos (0: todos)")
+	parseFlags(fs, args)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	articles, err := store.ListActive()
+	if err != nil {
+		return err
+	}
+	done := map[int64]bool{}
+	if !*all {
+		if done, err = store.ArticlesWithEntities(); err != nil {
+			return err
+		}
+	}
+
+	// Las palabras clave se pesan contra todo el corpus, no solo lo pendiente.
+	corpus := entities.NewCorpus()
+	for _, a := range articles {
+		corpus.Add(a.Title + "\n" + a.Summary + "\n" + a.Body)
+	}
+
+	analyzed := 0
+	counts := map[string]int{}
+	for _, a := range articles {
+		if done[a.ID] {
+			continue
+		}
+		if *limit > 0 && analyzed >= *limit {
+			break
+		}
+		found := entities.Extract(a.Title, a.Summary+"\n"+a.Body)
+		found = append(found, corpus.Keywords(a.Title+"\n"+a.Summary+"\n"+a.Body)...)
+		rows := make([]storage.ArticleEntity, len(found))
+		for i, e := range found {
+			rows[i] = storage.ArticleEntity{ArticleID: a.ID, Name: e.Name, Kind: e.Kind, Score: e.Score}
+			counts[e.Kind]++
+		}
+		if err := store.SetEntities(a.ID, rows); err != nil {
+			return err
+		}
+		analyzed++
+	}
+
+	if analyzed == 0 {
+		fmt.Println("No hay artículos pendientes de analizar.")
+		return nil
+	}
+	fmt.Printf("Artículos analizados: %d\n", analyzed)
+	for _, kind := range []string{entities.Person, entities.Organization, entities.Place, entities.Keyword} {
+		fmt.Printf("  %-16s %d\n", entityKindNames[kind], counts[kind])
+	}
+	return nil
+}
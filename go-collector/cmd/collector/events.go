@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-collector/storage"
+)
+
+// runEvents analiza la cobertura por evento: los artículos que una fuente
+// (hoy, Event Registry) agrupa en un mismo acontecimiento. list muestra los
+// eventos con cuántos artículos, medios e idiomas llegaron al corpus; show
+// los artículos de uno.
+func runEvents(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: collector events list [opciones] | show <uri>")
+	}
+	switch args[0] {
+	case "list":
+		return eventsList(args[1:])
+	case "show":
+		return eventsShow(args[1:])
+	default:
+		return fmt.Errorf("acción desconocida: %s (use list o show)", args[0])
+	}
+}
+
+func eventsList(args []string) error {
+	fs := flag.NewFlagSet("events list", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	since := fs.String("since", "", "artículos publicados desde esta fecha (AAAA-MM-DD)")
+	until := fs.String("until", "", "artículos publicados hasta esta fecha inclusive (AAAA-MM-DD)")
+	minStored := fs.Int("min", 1, "solo eventos con al menos esta cantidad de artículos en el corpus")
+	limit := fs.Int("limit", 30, "cantidad máxima de eventos (0: todos)")
+	format := fs.String("format", "text", "formato: text, json o csv")
+	parseFlags(fs, args)
+	if *format != "text" && *format != "json" && *format != "csv" {
+		return fmt.Errorf("formato desconocido: %s (use text, json o csv)", *format)
+	}
+
+	filter := storage.EventFilter{MinStored: *minStored, Limit: *limit}
+	if *since != "" {
+		t, err := time.Parse("2006-01-02", *since)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --since: %w", err)
+		}
+		filter.Since = t
+	}
+	if *until != "" {
+		t, err := time.Parse("2006-01-02", *until)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --until: %w", err)
+		}
+		filter.Until = t.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	events, err := store.Events(filter)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(events)
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"uri", "date", "title", "articles", "stored", "domains", "languages", "first", "last"})
+		for _, e := range events {
+			w.Write([]string{e.URI, e.Date, e.Title, strconv.Itoa(e.Articles), strconv.Itoa(e.Stored),
+				strconv.Itoa(e.Domains), strings.Join(e.Languages, " "), formatEventTime(e.First), formatEventTime(e.Last)})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	if len(events) == 0 {
+		fmt.Println("No hay eventos en el rango. Los traen las fuentes que agrupan artículos (eventregistry).")
+		return nil
+	}
+	fmt.Println("\n--- EVENTOS ---")
+	for _, e := range events {
+		title := e.Title
+		if title == "" {
+			title = "(sin datos del evento)"
+		}
+		date := e.Date
+		if date == "" {
+			date = "-"
+		}
+		fmt.Printf("  %-14s %-10s %s\n", e.URI, date, title)
+		fmt.Printf("  %-14s en el corpus: %d de %d  medios: %d  idiomas: %s  publicados: %s a %s\n", "",
+			e.Stored, e.Articles, e.Domains, strings.Join(e.Languages, ", "), formatEventTime(e.First), formatEventTime(e.Last))
+	}
+	return nil
+}
+
+func eventsShow(args []string) error {
+	fs := flag.NewFlagSet("events show", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	parseFlags(fs, args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("uso: collector events show [--db ruta] <uri>")
+	}
+	uri := fs.Arg(0)
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	events, err := store.Events(storage.EventFilter{URI: uri})
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("no hay artículos del evento %s en el corpus", uri)
+	}
+	articles, err := store.EventArticles(uri)
+	if err != nil {
+		return err
+	}
+	if e := events[0]; e.Title != "" {
+		fmt.Printf("\n%s (%s, %d artículos en la fuente)\n", e.Title, e.Date, e.Articles)
+		if e.Summary != "" {
+			fmt.Println(e.Summary)
+		}
+	}
+	fmt.Printf("\n--- ARTÍCULOS DEL EVENTO %s (%d) ---\n", uri, len(articles))
+	for _, a := range articles {
+		fmt.Printf("  #%-6d %s  %-3s %-24s %s\n", a.ID, formatEventTime(a.Published), a.Language, a.Domain, a.Title)
+	}
+	return nil
+}
+
+// formatEventTime muestra una fecha de publicación, o "-" si no la hay.
+func formatEventTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	"go-collector/collect"
+	"go-collector/config"
+	"go-collector/crawler/gdelt"
+	"go-collector/crawler/guardian"
+	"go-collector/crawler/newsapi"
+	"go-collector/crawler/rss"
+	"go-collector/crawler/x"
+	"go-collector/feeds"
+)
+
+// defaultQuery es la consulta de la exploración original del proyecto.
+const defaultQuery = `"Universidad de Antioquia" OR UdeA`
+
+// runExplore consulta una fuente y muestra estadísticas rápidas, sin guardar
+// nada en el corpus. Reemplaza los main() de exploración de cada crawler.
+func runExplore(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("uso: collector explore <guardian|newsapi|gdelt|x|rss> [opciones]")
+	}
+	source, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("explore "+source, flag.ExitOnError)
+	query := fs.String("query", defaultQuery, "consulta (sintaxis de la fuente)")
+	from := fs.String("from", "", "fecha inicial YYYY-MM-DD (por defecto depende de la fuente)")
+	to := fs.String("to", "", "fecha final YYYY-MM-DD (por defecto, hoy)")
+	lang := fs.String("lang", "es,en", "idiomas ISO 639-1 separados por comas")
+	max := fs.Int("max", 50, "cantidad máxima de resultados")
+	key := fs.String("key", "", "API key o bearer token (por defecto, la variable de entorno de la fuente)")
+	chunk := fs.String("chunk", "", "gdelt: partir el rango en tramos (ej: 7d) para superar el tope de 250 artículos")
+	outlet := fs.String("outlet", "", "rss: medio del catálogo de feeds (en vez de URLs)")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración (credenciales y ediciones de feeds)")
+	parseFlags(fs, args)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	src := cfg.Sources.Get(source)
+	if src == nil {
+		return fmt.Errorf("fuente desconocida: %s (use guardian, newsapi, gdelt, x o rss)", source)
+	}
+	apiKey := *key
+	if apiKey == "" && config.NeedsKey(source) {
+		if apiKey, err = src.Key(source); err != nil {
+			return fmt.Errorf("%w (o use --key)", err)
+		}
+	}
+
+	now := time.Now().UTC()
+	start, end, err := exploreRange(*from, *to, now)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	switch source {
+	case "guardian":
+		if start.IsZero() {
+			start = end.AddDate(-1, 0, 0)
+		}
+		c := guardian.NewCrawler(apiKey)
+		resp, err := c.BuscarArticulos(ctx, *query, start.Format("2006-01-02"), end.Format("2006-01-02"), *max)
+		if err != nil {
+			return err
+		}
+		c.ExplorarDatos(resp)
+
+	case "newsapi":
+		// El plan gratuito de NewsAPI solo cubre los últimos 30 días.
+		if start.IsZero() {
+			start = end.AddDate(0, 0, -30)
+		}
+		c := newsapi.NewCrawler(apiKey)
+		resp, err := c.BuscarArticulos(ctx, *query, *lang, start.Format(time.RFC3339), end.Format(time.RFC3339), *max)
+		if err != nil {
+			return err
+		}
+		c.ExplorarDatos(resp)
+
+	case "gdelt":
+		if start.IsZero() {
+			start = end.AddDate(0, -3, 0)
+		}
+		c := gdelt.NewCrawler()
+		langs := gdelt.Languages(strings.Split(*lang, ","))
+		var resp *gdelt.Response
+		if *chunk != "" {
+			span, err := config.ParseSpan(*chunk)
+			if err != nil {
+				return err
+			}
+			resp, err = c.BuscarPorTramos(ctx, *query, langs, start, end, gdelt.TramosOptions{Tramo: span, Pausa: 5 * time.Second})
+			if err != nil {
+				return err
+			}
+		} else {
+			resp, err = c.BuscarArticulosMultiLang(ctx, *query, langs, start.Format("20060102150405"), end.Format("20060102150405"), *max)
+			if err != nil {
+				return err
+			}
+		}
+		c.ExplorarDatos(resp)
+
+	case "x":
+		start, end = collect.XWindow(start, end, now)
+		q := *query
+		if langs := strings.Split(*lang, ","); len(langs) == 1 && langs[0] != "" {
+			q = fmt.Sprintf("(%s) lang:%s", q, langs[0])
+		}
+		// Más de 100 tweets se piden en varias páginas, siguiendo next_token.
+		pager := x.NewCrawler(apiKey).Paginar(q, *max, start.Format(time.RFC3339), end.Format(time.RFC3339))
+		pager.MaxTweets = *max
+		pager.Reintento = func(page int, wait time.Duration) {
+			fmt.Printf("Aviso: X respondió 429, reintentando la página %d en %s\n", page, wait.Round(time.Second))
+		}
+		resp := &x.Response{}
+		for !pager.Done() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				if len(resp.Data) == 0 {
+					return err
+				}
+				fmt.Printf("Aviso: X se detuvo con %d tweets: %v\n", len(resp.Data), err)
+				break
+			}
+			resp.Data = append(resp.Data, page.Data...)
+			resp.Meta.ResultCount += page.Meta.ResultCount
+		}
+		x.ExplorarDatos(resp)
+
+	case "rss":
+		urls := fs.Args()
+		if len(urls) == 0 && *outlet == "" {
+			urls = src.Feeds
+		}
+		if *outlet != "" {
+			editions, err := feeds.NewResolver(cfg.Feeds).Resolve(*outlet, strings.Split(*lang, ",")[0], "")
+			if err != nil {
+				return err
+			}
+			for _, e := range editions {
+				urls = append(urls, e.URL)
+			}
+		}
+		if len(urls) == 0 {
+			return fmt.Errorf("uso: collector explore rss [--outlet medio] [url ...]")
+		}
+		c := rss.NewCrawler()
+		var list []*gofeed.Feed
+		for _, u := range urls {
+			feed, err := c.LeerFeed(ctx, u)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Aviso: %v\n", err)
+				continue
+			}
+			list = append(list, feed)
+		}
+		rss.ExplorarDatos(list)
+
+	default:
+		return fmt.Errorf("fuente desconocida: %s (use guardian, newsapi, gdelt, x o rss)", source)
+	}
+	return nil
+}
+
+// exploreRange interpreta --from y --to; from queda en cero si no se indicó
+// para que cada fuente aplique su propio rango por defecto.
+func exploreRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	end := now
+	if to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("fecha --to inválida %q: %w", to, err)
+		}
+		end = t.Add(24*time.Hour - time.Second)
+		if end.After(now) {
+			end = now
+		}
+	}
+	var start time.Time
+	if from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("fecha --from inválida %q: %w", from, err)
+		}
+		start = t
+	}
+	return start, end, nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/buildinfo"
+	"go-collector/export"
+	"go-collector/relevance"
+	"go-collector/storage"
+)
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	configPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	format := fs.String("format", "jsonl", "formato: "+strings.Join(export.Formats(), ", "))
+	columns := fs.String("columns", "", "columnas del CSV separadas por coma (por defecto "+strings.Join(export.DefaultColumns, ",")+")")
+	out := fs.String("out", "-", "archivo de salida (- = salida estándar)")
+	from := fs.String("from", "", "publicados desde esta fecha (YYYY-MM-DD)")
+	to := fs.String("to", "", "publicados hasta esta fecha inclusive (YYYY-MM-DD)")
+	sources := fs.String("source", "", "solo estas fuentes, separadas por coma")
+	lang := fs.String("lang", "", "solo este idioma (ej: es)")
+	query := fs.String("query", "", "solo artículos con todas estas palabras")
+	all := fs.Bool("all", false, "incluye los artículos retirados")
+	auditPath := fs.String("audit", "", "escribe además la auditoría de selección (JSONL): cada artículo con la explicación de su puntaje")
+	campaign := fs.String("campaign", "", "con --audit, evalúa con la consulta de esta campaña los artículos guardados sin explicación")
+	parseFlags(fs, args)
+
+	if *columns != "" && *format != "csv" {
+		return fmt.Errorf("--columns solo aplica a --format csv")
+	}
+	if *campaign != "" && *auditPath == "" {
+		return fmt.Errorf("--campaign solo aplica con --audit")
+	}
+	filter := storage.Filter{Language: *lang, Query: *query, IncludeWithdrawn: *all}
+	if *from != "" {
+		t, err := time.Parse("2006-01-02", *from)
+		if err != nil {
+			return fmt.Errorf("--from inválido: %w", err)
+		}
+		filter.From = t
+	}
+	if *to != "" {
+		t, err := time.Parse("2006-01-02", *to)
+		if err != nil {
+			return fmt.Errorf("--to inválido: %w", err)
+		}
+		filter.To = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	if *sources != "" {
+		for _, s := range strings.Split(*sources, ",") {
+			filter.Sources = append(filter.Sources, strings.TrimSpace(s))
+		}
+	}
+	var opts export.Options
+	if *columns != "" {
+		for _, c := range strings.Split(*columns, ",") {
+			opts.Columns = append(opts.Columns, strings.TrimSpace(c))
+		}
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	dst := os.Stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("error creando %s: %w", *out, err)
+		}
+		defer f.Close()
+		dst = f
+	}
+	buf := bufio.NewWriter(dst)
+	w, err := export.New(*format, buf, opts)
+	if err != nil {
+		return err
+	}
+
+	var audit *relevance.Audit
+	var auditBuf *bufio.Writer
+	if *auditPath != "" {
+		var f *relevance.Filter
+		if *campaign != "" {
+			camp, ok := findCampaign(cfg, *campaign)
+			if !ok {
+				return fmt.Errorf("campaña desconocida: %s", *campaign)
+			}
+			if f, err = relevance.NewFilter(camp.Query, cfg.Relevance); err != nil {
+				return err
+			}
+		}
+		af, err := os.Create(*auditPath)
+		if err != nil {
+			return fmt.Errorf("error creando %s: %w", *auditPath, err)
+		}
+		defer af.Close()
+		auditBuf = bufio.NewWriter(af)
+		audit = relevance.NewAudit(auditBuf, f)
+	}
+
+	// La auditoría recibe el artículo completo, con lo que se evaluó; la
+	// exportación, lo que sus términos de uso permiten redistribuir.
+	count := 0
+	var sharing storage.Sharing
+	err = store.EachFiltered(filter, func(a *article.Article) error {
+		shared := sharing.Add(store.Shareable(a))
+		if shared == nil {
+			return nil
+		}
+		if audit != nil {
+			if err := audit.Write(a); err != nil {
+				return err
+			}
+		}
+		count++
+		return w.Write(shared)
+	})
+	excluded, redacted := sharing.Excluded, sharing.Redacted
+	if err != nil {
+		return fmt.Errorf("error exportando: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("error exportando: %w", err)
+	}
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("error escribiendo %s: %w", *out, err)
+	}
+	if auditBuf != nil {
+		if err := auditBuf.Flush(); err != nil {
+			return fmt.Errorf("error escribiendo %s: %w", *auditPath, err)
+		}
+	}
+	if *out != "-" {
+		if err := dst.Close(); err != nil {
+			return fmt.Errorf("error escribiendo %s: %w", *out, err)
+		}
+		var used []string
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "from", "to", "source", "lang", "query", "all":
+				used = append(used, "--"+f.Name+"="+f.Value.String())
+			}
+		})
+		err := export.WriteManifest(&export.Manifest{
+			File:      *out,
+			Format:    *format,
+			Created:   time.Now().UTC(),
+			Articles:  count,
+			Excluded:  excluded,
+			Redacted:  redacted,
+			Filter:    used,
+			Collector: buildinfo.NewStamp(cfg.Hash()),
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d artículos exportados a %s (%s; manifiesto en %s)\n", count, *out, *format, export.ManifestPath(*out))
+	}
+	if excluded+redacted > 0 {
+		fmt.Fprintf(os.Stderr, "Términos de uso: %d artículos excluidos, %d sin texto completo (ver config publishers)\n", excluded, redacted)
+	}
+	return nil
+}
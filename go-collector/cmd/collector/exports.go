@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"go-collector/config"
+	"go-collector/upload"
+)
+
+// runExports maneja las exportaciones programadas: list, run <nombre> y
+// daemon.
+func runExports(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: collector exports list|run <nombre>|daemon")
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("exports "+action, flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	parseFlags(fs, args[1:])
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	live := config.NewLive(cfg)
+	sched := &upload.Scheduler{Store: store, Config: live}
+
+	switch action {
+	case "list":
+		entries, err := sched.Entries(time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Println("\n--- EXPORTACIONES PROGRAMADAS ---")
+		for _, e := range entries {
+			last := "nunca"
+			if !e.LastRun.IsZero() {
+				last = e.LastRun.Local().Format("2006-01-02 15:04")
+			}
+			fmt.Printf("  %-20s %-14s próxima: %s | última: %s | %s\n", e.Export.Name, e.Cron, e.NextRun.Format("2006-01-02 15:04"), last, e.Export.Upload.URL)
+		}
+		return nil
+
+	case "run":
+		if fs.NArg() == 0 {
+			return fmt.Errorf("uso: collector exports run [opciones] <nombre>")
+		}
+		ctx, cancel := signalContext()
+		defer cancel()
+		for _, e := range cfg.Exports {
+			if e.Name == fs.Arg(0) {
+				if err := sched.Run(ctx, e, time.Now()); err != nil {
+					return err
+				}
+				fmt.Printf("Exportación %s subida a %s.\n", e.Name, e.Upload.URL)
+				return nil
+			}
+		}
+		return fmt.Errorf("exportación no configurada: %s", fs.Arg(0))
+
+	case "daemon":
+		ctx, cancel := signalContext()
+		defer cancel()
+		// Horarios, filtros y destinos nuevos se aplican en la siguiente revisión.
+		watchConfig(*cfgPath, live, ctx.Done(), func() {
+			if err := sched.LogEntries(); err != nil {
+				log.Printf("error calculando horarios: %v", err)
+			}
+		}, store)
+		return sched.Daemon(ctx)
+
+	default:
+		return fmt.Errorf("acción desconocida: %s (use list, run o daemon)", action)
+	}
+}
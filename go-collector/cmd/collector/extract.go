@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"go-collector/extract"
+	"go-collector/fetch"
+)
+
+// extractPreview es cuánto del texto extraído se muestra.
+const extractPreview = 600
+
+// runExtract prueba la extracción de texto completo sobre una página y
+// muestra cada estrategia con su puntaje, para entender por qué un medio sale
+// mal y si necesita una regla en extract.domains.
+func runExtract(args []string) error {
+	fs := flag.NewFlagSet("extract", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración (reglas por dominio, cookies)")
+	file := fs.String("file", "", "leer la página de este archivo en vez de descargarla (la URL elige la regla)")
+	format := fs.String("format", "text", "formato: text o json")
+	render := fs.String("render", "auto", "navegador sin interfaz (fetch.render): auto (si la descarga estática no alcanza), always o never")
+	parseFlags(fs, args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("uso: collector extract [opciones] <url>")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("formato desconocido: %s (use text o json)", *format)
+	}
+	if *render != "auto" && *render != "always" && *render != "never" {
+		return fmt.Errorf("--render desconocido: %s (use auto, always o never)", *render)
+	}
+	pageURL := fs.Arg(0)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	renderer := fetch.NewRenderer(cfg.Fetch.Render)
+	if *render == "always" && (renderer == nil || *file != "") {
+		return fmt.Errorf("--render always necesita fetch.render.command en la configuración y no admite --file")
+	}
+	if renderer != nil {
+		defer renderer.Close()
+	}
+	e := textExtractor(cfg)
+	var res *extract.Result
+	if *file != "" {
+		page, err := os.ReadFile(*file)
+		if err != nil {
+			return fmt.Errorf("error leyendo %s: %w", *file, err)
+		}
+		if res, err = e.Extract(pageURL, page); err != nil {
+			return err
+		}
+	} else {
+		ctx, cancel := signalContext()
+		defer cancel()
+		var p *fetch.Page
+		if *render == "always" {
+			p, err = renderer.Render(ctx, pageURL)
+		} else {
+			p, err = fetch.NewFetcherFromConfig(cfg.Fetch, nil).Fetch(ctx, pageURL)
+		}
+		if err != nil {
+			return err
+		}
+		if p.ConsentWall {
+			fmt.Fprintln(os.Stderr, "Aviso: la página es un muro de consentimiento (configure fetch.consent_cookies)")
+		}
+		if res, err = e.Extract(p.URL, p.Body); err != nil {
+			return err
+		}
+		// El mismo criterio que el enriquecimiento para escalar, sin la
+		// memoria por dominio.
+		jsOnly := fetch.JSDependent(p)
+		if *render == "auto" && (jsOnly || res.Quality.Score < extract.MinScore) {
+			why := fmt.Sprintf("puntaje %.3f menor que %.1f", res.Quality.Score, extract.MinScore)
+			if jsOnly {
+				why = "la página depende de JavaScript"
+			}
+			if renderer == nil {
+				fmt.Fprintf(os.Stderr, "Aviso: %s; sin fetch.render.command no se puede renderizar\n", why)
+			} else {
+				fmt.Fprintf(os.Stderr, "Descarga estática insuficiente (%s): renderizando\n", why)
+				rp, err := renderer.Render(ctx, pageURL)
+				if err != nil {
+					return err
+				}
+				rres, err := e.Extract(rp.URL, rp.Body)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Estática %.3f, renderizada %.3f\n", res.Quality.Score, rres.Quality.Score)
+				if rres.Quality.Score > res.Quality.Score {
+					res = rres
+				}
+			}
+		}
+	}
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+
+	if res.Rule != "" {
+		fmt.Printf("Regla: %s\n", res.Rule)
+	}
+	fmt.Println("Estrategias:")
+	for _, a := range res.Attempts {
+		mark := ""
+		if a.Strategy == res.Strategy {
+			mark = "  <- elegida"
+		}
+		fmt.Printf("  %-12s %6d caracteres  puntaje %.3f%s\n", a.Strategy, a.Length, a.Score, mark)
+	}
+	q := res.Quality
+	fmt.Printf("\nCalidad: %.3f (relleno %.0f%%, fecha: %s, autor: %s)\n", q.Score, q.Boilerplate*100, yesNo(q.HasDate), yesNo(q.HasAuthor))
+	if res.Author != "" {
+		fmt.Printf("Autor:   %s\n", res.Author)
+	}
+	if !res.Published.IsZero() {
+		fmt.Printf("Fecha:   %s\n", res.Published.Format("2006-01-02 15:04"))
+	}
+	text := []rune(res.Text)
+	if len(text) > extractPreview {
+		text = append(text[:extractPreview], []rune("…")...)
+	}
+	fmt.Printf("\n%s\n", string(text))
+	return nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "sí"
+	}
+	return "no"
+}
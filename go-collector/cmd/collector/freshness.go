@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-collector/stats"
+	"go-collector/storage"
+)
+
+// runFreshness compara, por fuente, cuándo llegó cada artículo con su fecha de
+// publicación: las fuentes con menor demora son las que conviene consultar
+// más seguido.
+func runFreshness(args []string) error {
+	fs := flag.NewFlagSet("freshness", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	since := fs.String("since", "", "publicados desde esta fecha (AAAA-MM-DD)")
+	until := fs.String("until", "", "publicados hasta esta fecha inclusive (AAAA-MM-DD)")
+	source := fs.String("source", "", "solo estas fuentes (separadas por comas)")
+	format := fs.String("format", "text", "formato: text, json o csv")
+	parseFlags(fs, args)
+	if *format != "text" && *format != "json" && *format != "csv" {
+		return fmt.Errorf("formato desconocido: %s (use text, json o csv)", *format)
+	}
+
+	var from, to time.Time
+	if *since != "" {
+		t, err := time.Parse("2006-01-02", *since)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --since: %w", err)
+		}
+		from = t
+	}
+	if *until != "" {
+		t, err := time.Parse("2006-01-02", *until)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --until: %w", err)
+		}
+		to = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	var sources []string
+	if *source != "" {
+		sources = strings.Split(*source, ",")
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	sightings, err := store.Sightings(from, to, sources)
+	if err != nil {
+		return err
+	}
+	lags := stats.Freshness(sightings)
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(lags)
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"source", "articles", "p25_hours", "median_hours", "p75_hours", "p90_hours", "first", "shared", "ahead"})
+		for _, l := range lags {
+			w.Write([]string{l.Source, strconv.Itoa(l.Articles),
+				strconv.FormatFloat(l.P25, 'f', 2, 64), strconv.FormatFloat(l.Median, 'f', 2, 64),
+				strconv.FormatFloat(l.P75, 'f', 2, 64), strconv.FormatFloat(l.P90, 'f', 2, 64),
+				strconv.Itoa(l.First), strconv.Itoa(l.Shared), strconv.Itoa(l.Ahead)})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	if len(lags) == 0 {
+		fmt.Println("No hay artículos con fecha de publicación en el rango.")
+		return nil
+	}
+	fmt.Println("\nDemora entre la publicación y la llegada al corpus, por fuente (de la más rápida a la más lenta):")
+	fmt.Printf("\n%-12s %9s %9s %9s %9s %9s  %s\n", "fuente", "artículos", "p25", "mediana", "p75", "p90", "primera")
+	for _, l := range lags {
+		first := "-"
+		if l.Shared > 0 {
+			first = fmt.Sprintf("%d de %d compartidos", l.First, l.Shared)
+		}
+		fmt.Printf("%-12s %9d %9s %9s %9s %9s  %s\n", l.Source, l.Articles,
+			lagString(l.P25), lagString(l.Median), lagString(l.P75), lagString(l.P90), first)
+	}
+	for _, l := range lags {
+		if l.Ahead > 0 {
+			fmt.Printf("\nAviso: %s entregó %d artículos antes de su fecha de publicación (zona horaria o fecha sin hora); cuentan con demora cero.\n", l.Source, l.Ahead)
+		}
+	}
+	return nil
+}
+
+// lagString muestra una demora en horas como "45m", "5h10m" o "3d4h".
+func lagString(hours float64) string {
+	d := time.Duration(hours * float64(time.Hour)).Round(time.Minute)
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd%02dh", int(d.Hours())/24, int(d.Hours())%24)
+}
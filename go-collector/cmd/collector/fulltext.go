@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/fetch"
+	"go-collector/fulltext"
+)
+
+// socialSources son las fuentes cuyas URLs son publicaciones y no notas:
+// fulltext las omite salvo que se pidan con --source.
+var socialSources = map[string]bool{"x": true, "mastodon": true, "bluesky": true, "youtube": true}
+
+// runFulltext descarga la página de los artículos guardados sin texto
+// completo (las fuentes que dan solo título y resumen: rss, gdelt, newsapi)
+// y guarda el texto extraído. Los que no se pueden extraer quedan marcados
+// con el motivo (muro de consentimiento, página vacía, error de descarga) y
+// no se reintentan salvo con --retry.
+func runFulltext(args []string) error {
+	fs := flag.NewFlagSet("fulltext", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	sources := fs.String("source", "", "solo estas fuentes, separadas por coma")
+	limit := fs.Int("limit", 0, "descargar como máximo esta cantidad de artículos (0: todos)")
+	retry := fs.Bool("retry", false, "reintentar también los marcados con un problema de extracción")
+	rateFlag := fs.String("rate", "60/1m", `peticiones a los medios por minuto ("60/1m"; 0: sin límite)`)
+	parseFlags(fs, args)
+
+	rate, err := config.ParseRate(*rateFlag)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	archive, err := openArchive(cfg)
+	if err != nil {
+		return err
+	}
+
+	only := make(map[string]bool)
+	if *sources != "" {
+		for _, s := range strings.Split(*sources, ",") {
+			only[strings.TrimSpace(s)] = true
+		}
+	}
+	all, err := store.ArticlesWithoutBody(*retry)
+	if err != nil {
+		return err
+	}
+	var pending []*article.Article
+	for _, a := range all {
+		if (len(only) > 0 && !only[a.Source]) || (len(only) == 0 && socialSources[a.Source]) {
+			continue
+		}
+		pending = append(pending, a)
+	}
+	if *limit > 0 && len(pending) > *limit {
+		pending = pending[:*limit]
+	}
+	if len(pending) == 0 {
+		fmt.Println("No hay artículos sin texto pendientes.")
+		return nil
+	}
+
+	f := fetch.NewFetcherFromConfig(cfg.Fetch, store)
+	f.Client.Transport = fetch.RateLimit(rate, 1)(f.Client.Transport)
+	e := fulltext.NewEnricher(f, store)
+	e.Archive = archive
+	e.Extractor = textExtractor(cfg)
+	if e.Renderer = fetch.NewRenderer(cfg.Fetch.Render); e.Renderer != nil {
+		defer e.Renderer.Close()
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	done := 0
+	issues := make(map[string]int)
+	for _, a := range pending {
+		if err := e.Enrich(ctx, a); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return err
+		}
+		done++
+		if a.ExtractionIssue != "" {
+			issues[a.ExtractionIssue]++
+			fmt.Printf("  #%-6d %-16s %s\n", a.ID, a.ExtractionIssue, a.URL)
+		}
+	}
+
+	failed := 0
+	for _, n := range issues {
+		failed += n
+	}
+	fmt.Printf("\nArtículos: %d con texto, %d sin texto (de %d pendientes)\n", done-failed, failed, len(pending))
+	for _, issue := range slices.Sorted(maps.Keys(issues)) {
+		fmt.Printf("  %-16s %d\n", issue, issues[issue])
+	}
+	if issues[article.IssueConsentWall] > 0 {
+		fmt.Println("Los muros de consentimiento se pueden evitar con fetch.consent_cookies para esos dominios.")
+	}
+	return nil
+}
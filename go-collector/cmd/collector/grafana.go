@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"go-collector/buildinfo"
+	"go-collector/grafana"
+	"go-collector/storage"
+)
+
+func runGrafana(args []string) error {
+	fs := flag.NewFlagSet("grafana", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	addr := fs.String("addr", "127.0.0.1:3030", "dirección donde escuchar")
+	token := fs.String("token", os.Getenv("COLLECTOR_GRAFANA_TOKEN"), "token Bearer exigido a Grafana (opcional)")
+	cfgPath := fs.String("config", defaultConfigPath, "configuración cuyo hash se informa en las respuestas")
+	parseFlags(fs, args)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           (&grafana.Server{Store: store, Token: *token, Stamp: buildinfo.NewStamp(cfg.Hash())}).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Printf("Datasource JSON para Grafana en http://%s\n", *addr)
+	return srv.ListenAndServe()
+}
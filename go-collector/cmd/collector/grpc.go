@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go-collector/collect"
+	"go-collector/elastic"
+	"go-collector/fetch"
+	"go-collector/kafka"
+	"go-collector/notify"
+	"go-collector/rpc"
+	"go-collector/storage"
+)
+
+// runGRPC sirve la API gRPC de rpc/collector.proto: otros servicios inician
+// rondas, siguen su estado y reciben los artículos a medida que se guardan,
+// sin invocar el binario. Cada ronda toma la configuración vigente, como
+// collector collect, y hay una a la vez. Ctrl-C deja de aceptar llamadas,
+// corta la ronda en curso y espera a que termine de registrarse.
+func runGRPC(args []string) error {
+	fs := flag.NewFlagSet("grpc", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	dbPath := fs.String("db", "", "ruta de la base de datos del corpus (por defecto output.db o corpus.db)")
+	addr := fs.String("addr", "127.0.0.1:9090", "dirección donde escuchar")
+	token := fs.String("token", os.Getenv("COLLECTOR_API_TOKEN"), "token Bearer exigido a los clientes (opcional)")
+	parseFlags(fs, args)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	if *dbPath == "" {
+		*dbPath = cfg.Output.DB
+	}
+	if *dbPath == "" {
+		*dbPath = "corpus.db"
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("error escuchando en %s: %w", *addr, err)
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	t := &grpcTrigger{ctx: ctx, cfgPath: *cfgPath, store: store, caches: newSharedCaches()}
+	srv := (&rpc.Server{Store: store, Start: t.start, Token: *token}).NewGRPCServer()
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+
+	fmt.Printf("API gRPC del recolector en %s (TriggerCollection, GetRunStatus, StreamArticles)\n", *addr)
+	err = srv.Serve(lis)
+	t.wg.Wait()
+	return err
+}
+
+// grpcTrigger lanza las rondas que piden los clientes de collector grpc, de
+// a una.
+type grpcTrigger struct {
+	ctx     context.Context
+	cfgPath string
+	store   *storage.Store
+	caches  sharedCaches
+
+	mu      sync.Mutex
+	running bool
+	wg      sync.WaitGroup
+}
+
+// start valida el pedido, registra la ronda y la corre en segundo plano;
+// devuelve su id.
+func (t *grpcTrigger) start(req *rpc.TriggerCollectionRequest) (int64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.running {
+		return 0, rpc.ErrBusy
+	}
+	if t.ctx.Err() != nil {
+		return 0, fmt.Errorf("el servidor se está cerrando")
+	}
+
+	cfg, err := loadConfig(t.cfgPath)
+	if err != nil {
+		return 0, err
+	}
+	if req.Source != "" && cfg.Sources.Get(req.Source) == nil {
+		return 0, fmt.Errorf("%w: fuente desconocida: %s", rpc.ErrInvalid, req.Source)
+	}
+	now := time.Now().UTC()
+	o := sourceOverrides{query: req.Query, from: req.From, to: req.To, lang: strings.Join(req.Languages, ",")}
+	if o.set() {
+		if err := o.apply(&cfg.Sources, req.Source, now); err != nil {
+			return 0, fmt.Errorf("%w: %v", rpc.ErrInvalid, err)
+		}
+	}
+
+	c := &collect.Collector{Transport: t.caches.dns.Transport(), Extractor: textExtractor(cfg), Renderer: fetch.NewRenderer(cfg.Fetch.Render), Robots: t.caches.robots, Fetch: cfg.Fetch, Holds: t.store, Backoff: t.store, RenderPaths: t.store}
+	if !o.set() {
+		c.FeedStates = t.store
+	}
+	if c.Links, err = linkExpander(cfg, t.store); err != nil {
+		return 0, err
+	}
+	index, err := elastic.NewClient(cfg.Output.Elasticsearch, cfg.Storage.Encryption.AuthorSources)
+	if err != nil {
+		return 0, err
+	}
+	producer, err := kafka.NewProducer(cfg.Output.Kafka, cfg.Storage.Encryption.AuthorSources)
+	if err != nil {
+		return 0, err
+	}
+	notifier, err := notify.New(cfg.Notifications, cfg.Relevance)
+	if err != nil {
+		return 0, err
+	}
+	mirror, err := openMirror(cfg)
+	if err != nil {
+		return 0, err
+	}
+	dst := sink{
+		store:      t.store,
+		mirror:     mirror,
+		index:      index,
+		kafka:      producer,
+		jsonl:      cfg.Output.JSONL,
+		dedup:      cfg.Dedup,
+		out:        os.Stdout,
+		campaign:   req.Campaign,
+		configHash: cfg.Hash(),
+		sentiment:  cfg.Sentiment.Enabled,
+		notify:     notifier,
+		relevance:  cfg.Relevance,
+	}
+	// La ronda se registra antes de consultar para que el cliente tenga su
+	// id desde ya.
+	dst.run = newRun(dst, now)
+	if err := t.store.StartRun(dst.run); err != nil {
+		if mirror != nil {
+			mirror.Close()
+		}
+		return 0, err
+	}
+
+	t.running = true
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		err := collectOnce(t.ctx, c, dst, &cfg.Sources, req.Source, now)
+		if err != nil {
+			log.Printf("error en la ronda %d: %v", dst.run.ID, err)
+			// saveResults cierra la ronda; si falló antes de guardar, sigue
+			// abierta.
+			if run, gerr := t.store.GetRun(dst.run.ID); gerr == nil && run.Status == storage.RunRunning {
+				if ferr := t.store.FinishRun(run.ID, time.Now(), storage.RunFailed, err.Error()); ferr != nil {
+					log.Printf("error cerrando la ronda %d: %v", run.ID, ferr)
+				}
+			}
+		}
+		if c.Renderer != nil {
+			c.Renderer.Close()
+		}
+		if mirror != nil {
+			mirror.Close()
+		}
+		t.mu.Lock()
+		t.running = false
+		t.mu.Unlock()
+	}()
+	return dst.run.ID, nil
+}
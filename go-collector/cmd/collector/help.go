@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// probing hace que parseFlags, en vez de parsear, devuelva el FlagSet del
+// comando (ver probeFlags). Así la ayuda y las completions listan las
+// opciones reales sin duplicarlas en una tabla aparte.
+var probing bool
+
+// probed es el valor con el que parseFlags interrumpe el comando al sondear.
+type probed struct{ fs *flag.FlagSet }
+
+// parseFlags parsea las opciones de un comando con la ayuda enriquecida
+// (uso, opciones y ejemplos). Todos los comandos parsean por aquí.
+func parseFlags(fs *flag.FlagSet, args []string) {
+	if probing {
+		panic(probed{fs})
+	}
+	fs.Usage = func() {
+		name := strings.Fields(fs.Name())[0]
+		if cmd := findCommand(name); cmd != nil {
+			printHelp(os.Stderr, cmd)
+		}
+	}
+	fs.Parse(args)
+}
+
+// probeFlags ejecuta el comando hasta que define sus opciones y devuelve el
+// FlagSet, sin efectos: parseFlags lo interrumpe antes de hacer nada. Devuelve
+// nil si el comando no tiene opciones para esa acción.
+func probeFlags(cmd *command, action string) (fs *flag.FlagSet) {
+	probing = true
+	defer func() {
+		probing = false
+		if r := recover(); r != nil {
+			p, ok := r.(probed)
+			if !ok {
+				panic(r)
+			}
+			fs = p.fs
+		}
+	}()
+	var args []string
+	if action != "" {
+		args = []string{action}
+	}
+	cmd.run(args)
+	return nil
+}
+
+// commandFlags devuelve las opciones de cada acción del comando ("" si no
+// tiene acciones). Las fuentes de explore comparten opciones: basta una.
+func commandFlags(cmd *command) map[string]*flag.FlagSet {
+	out := make(map[string]*flag.FlagSet)
+	if cmd.name == "help" || cmd.name == "completion" {
+		// No tienen opciones y recorren la tabla: sondearlos no termina.
+		return out
+	}
+	actions := cmd.actions
+	if len(actions) == 0 || cmd.name == "explore" {
+		actions = []string{firstOr(actions, "")}
+	}
+	for _, a := range actions {
+		if fs := probeFlags(cmd, a); fs != nil {
+			out[a] = fs
+		}
+	}
+	return out
+}
+
+func firstOr(list []string, def string) string {
+	if len(list) > 0 {
+		return list[0]
+	}
+	return def
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// runHelp muestra la ayuda general o la de un comando.
+func runHelp(args []string) error {
+	if len(args) == 0 {
+		usage()
+		return nil
+	}
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		return fmt.Errorf("comando desconocido: %s", args[0])
+	}
+	printHelp(os.Stdout, cmd)
+	return nil
+}
+
+// printHelp escribe el uso, las opciones (por acción si difieren) y los ejemplos.
+func printHelp(w io.Writer, cmd *command) {
+	fmt.Fprintf(w, "collector %s: %s\n\nUso:\n  collector %s %s\n", cmd.name, cmd.summary, cmd.name, cmd.usage)
+
+	sets := commandFlags(cmd)
+	actions := cmd.actions
+	if len(actions) == 0 || cmd.name == "explore" {
+		actions = []string{firstOr(actions, "")}
+	}
+	shared := sameFlags(sets)
+	for i, a := range actions {
+		fs := sets[a]
+		if fs == nil || countFlags(fs) == 0 || (shared && i > 0) {
+			continue
+		}
+		title := "Opciones"
+		if a != "" && !shared {
+			title = "Opciones de " + a
+		}
+		fmt.Fprintf(w, "\n%s:\n", title)
+		printFlags(w, fs)
+	}
+
+	if len(cmd.examples) > 0 {
+		fmt.Fprintln(w, "\nEjemplos:")
+		for _, e := range cmd.examples {
+			if strings.HasPrefix(e, "#") {
+				fmt.Fprintf(w, "\n  %s\n", e)
+			} else {
+				fmt.Fprintf(w, "  $ %s\n", e)
+			}
+		}
+	}
+	fmt.Fprintln(w, "\nOpción global: --profile perfil (o COLLECTOR_PROFILE) antes del comando.")
+}
+
+// printFlags lista las opciones con doble guión, su tipo y valor por defecto.
+func printFlags(w io.Writer, fs *flag.FlagSet) {
+	fs.VisitAll(func(f *flag.Flag) {
+		kind, usage := flag.UnquoteUsage(f)
+		name := "--" + f.Name
+		if kind != "" {
+			name += " " + kind
+		}
+		fmt.Fprintf(w, "  %-22s %s", name, usage)
+		if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "0" && !strings.Contains(usage, "por defecto") {
+			fmt.Fprintf(w, " (por defecto %s)", f.DefValue)
+		}
+		fmt.Fprintln(w)
+	})
+}
+
+// sameFlags indica si todas las acciones aceptan las mismas opciones.
+func sameFlags(sets map[string]*flag.FlagSet) bool {
+	var first string
+	i := 0
+	for _, fs := range sets {
+		var names []string
+		fs.VisitAll(func(f *flag.Flag) { names = append(names, f.Name) })
+		joined := strings.Join(names, ",")
+		if i == 0 {
+			first = joined
+		} else if joined != first {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+func countFlags(fs *flag.FlagSet) int {
+	n := 0
+	fs.VisitAll(func(*flag.Flag) { n++ })
+	return n
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"go-collector/collect"
+	"go-collector/config"
+	"go-collector/schedule"
+	"go-collector/storage"
+)
+
+// watermarkOverlap es cuánto antes de la marca de agua empieza una ronda
+// incremental: cubre artículos que las APIs indexan con retraso. Lo que se
+// vuelve a traer se actualiza por URL, no se duplica.
+const watermarkOverlap = time.Hour
+
+// watermarkSkew es cuánto en el futuro puede estar una fecha de publicación
+// para mover la marca de agua; más allá se toma como un error de la fuente.
+const watermarkSkew = time.Hour
+
+// incremental prepara una ronda: devuelve una copia de sources en la que las
+// fuentes con incremental empiezan en su marca de agua (la configuración
+// vigente no se toca) y la clave de consulta de cada una, para avanzar la
+// marca al guardar (ver advanceWatermark). scope es la campaña, o "".
+func incremental(store *storage.Store, scope string, sources *config.Sources, only string) (*config.Sources, map[string]string, error) {
+	srcs := *sources
+	keys := make(map[string]string)
+	for _, n := range srcs.Named() {
+		if !n.Incremental || (only != "" && n.Name != only) || (only == "" && !n.Enabled) {
+			continue
+		}
+		keys[n.Name] = n.QueryKey()
+		wm, err := store.Watermark(scope, n.Name)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if wm.Query == keys[n.Name] {
+			n.After = wm.Published.Add(-watermarkOverlap)
+		}
+	}
+	return &srcs, keys, nil
+}
+
+// advanceWatermark mueve la marca de agua de la fuente a la publicación más
+// reciente de r. Una ronda con errores o incompleta no la mueve: los artículos
+// que faltaron podrían ser anteriores. Devuelve la marca nueva, o cero si no
+// se movió.
+func advanceWatermark(store *storage.Store, scope, key string, r collect.Result, now time.Time) (time.Time, error) {
+	if r.Err != nil || r.Partial {
+		return time.Time{}, nil
+	}
+	wm := storage.Watermark{Scope: scope, Source: r.Source, Query: key, UpdatedAt: now}
+	for _, a := range r.Articles {
+		if a.URL == "" || a.Published.After(now.Add(watermarkSkew)) {
+			continue
+		}
+		if a.Published.After(wm.Published) {
+			wm.Published, wm.URL = a.Published, a.URL
+		}
+	}
+	if wm.Published.IsZero() {
+		return time.Time{}, nil
+	}
+	moved, err := store.AdvanceWatermark(wm)
+	if err != nil || !moved {
+		return time.Time{}, err
+	}
+	return wm.Published, nil
+}
+
+// nextRound calcula la siguiente ronda del daemon de collect: por la
+// expresión cron, o cada every desde el inicio de la anterior.
+func nextRound(cron *schedule.Cron, every time.Duration, start time.Time) time.Time {
+	if cron != nil {
+		return cron.Next(time.Now())
+	}
+	return start.Add(every)
+}
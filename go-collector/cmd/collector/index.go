@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/elastic"
+	"go-collector/storage"
+)
+
+// runIndex indexa en Elasticsearch/OpenSearch los artículos ya guardados:
+// collect indexa solo los nuevos, así que esto carga el corpus la primera
+// vez o después de --recreate (por ejemplo, al cambiar el mapping).
+func runIndex(args []string) error {
+	fs := flag.NewFlagSet("index", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	from := fs.String("from", "", "publicados desde esta fecha (YYYY-MM-DD)")
+	sources := fs.String("source", "", "solo estas fuentes, separadas por coma")
+	recreate := fs.Bool("recreate", false, "borrar el índice y crearlo de nuevo con el mapping actual")
+	parseFlags(fs, args)
+
+	filter := storage.Filter{}
+	if *from != "" {
+		t, err := time.Parse("2006-01-02", *from)
+		if err != nil {
+			return fmt.Errorf("--from inválido: %w", err)
+		}
+		filter.From = t
+	}
+	if *sources != "" {
+		for _, s := range strings.Split(*sources, ",") {
+			filter.Sources = append(filter.Sources, strings.TrimSpace(s))
+		}
+	}
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	index, err := elastic.NewClient(cfg.Output.Elasticsearch, cfg.Storage.Encryption.AuthorSources)
+	if err != nil {
+		return err
+	}
+	if index == nil {
+		return fmt.Errorf("falta configurar output.elasticsearch.url")
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	if *recreate {
+		if err := index.Delete(ctx); err != nil {
+			return err
+		}
+		fmt.Printf("Índice %s borrado.\n", index.IndexName)
+	}
+
+	// Los artículos se envían de a un lote mientras se recorre el corpus; un
+	// lote con artículos rechazados no detiene a los siguientes.
+	var batch []*article.Article
+	indexed, failed := 0, 0
+	var firstErr error
+	flush := func() error {
+		n, err := index.Index(ctx, batch)
+		indexed += n
+		if err != nil {
+			if n == 0 {
+				// Nada entró: el clúster no responde o rechaza todo.
+				return err
+			}
+			failed += len(batch) - n
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+	_, err = store.EachShareable(filter, func(a *article.Article) error {
+		batch = append(batch, a)
+		if len(batch) < index.Batch {
+			return nil
+		}
+		return flush()
+	})
+	if err == nil && len(batch) > 0 {
+		err = flush()
+	}
+	fmt.Printf("%d artículos indexados en %s.\n", indexed, index.IndexName)
+	if err != nil {
+		return err
+	}
+	if firstErr != nil {
+		return fmt.Errorf("%d artículos sin indexar: %w", failed, firstErr)
+	}
+	return nil
+}
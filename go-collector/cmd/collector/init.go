@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-collector/config"
+	"go-collector/crawler/rss"
+	"go-collector/seed"
+)
+
+// checkConcurrency es cuántos feeds se prueban a la vez con init --check.
+const checkConcurrency = 8
+
+// runInit escribe una configuración inicial con los medios de un paquete
+// incluido (seed): sus feeds en la fuente rss, sus dominios en la fuente
+// sitemap y sus ediciones en feeds.editions para las campañas.
+func runInit(args []string) error {
+	fset := flag.NewFlagSet("init", flag.ExitOnError)
+	cfgPath := fset.String("config", defaultConfigPath, "archivo de configuración a crear")
+	pack := fset.String("seed", "", "paquete de medios: "+strings.Join(seed.Names(), ", "))
+	check := fset.Bool("check", false, "probar cada feed y dejar fuera los que no responden")
+	force := fset.Bool("force", false, "reemplazar el archivo si ya existe")
+	parseFlags(fset, args)
+
+	if *pack == "" {
+		return fmt.Errorf("falta --seed (use %s)", strings.Join(seed.Names(), ", "))
+	}
+	p, err := seed.Load(*pack)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(*cfgPath); err == nil && !*force {
+		return fmt.Errorf("%s ya existe (use --force para reemplazarlo)", *cfgPath)
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error revisando %s: %w", *cfgPath, err)
+	}
+
+	if *check {
+		ctx, cancel := signalContext()
+		defer cancel()
+		if err := checkFeeds(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	data := seedConfig(p)
+	// Lo que se escribe tiene que cargar: un error aquí es del paquete.
+	if _, err := config.Parse(*cfgPath, data); err != nil {
+		return fmt.Errorf("error en el paquete %s: %w", p.Name, err)
+	}
+	if err := os.WriteFile(*cfgPath, data, 0o644); err != nil {
+		return fmt.Errorf("error escribiendo %s: %w", *cfgPath, err)
+	}
+
+	feeds, regional := 0, 0
+	for _, o := range p.Outlets {
+		feeds += len(o.Feeds)
+		if o.Scope == "regional" {
+			regional++
+		}
+	}
+	fmt.Printf("Configuración escrita en %s: %d medios (%d nacionales, %d regionales), %d feeds.\n",
+		*cfgPath, len(p.Outlets), len(p.Outlets)-regional, regional, feeds)
+	fmt.Println("Revise query y from en sources.sitemap y luego: collector collect --every 15m")
+	return nil
+}
+
+// checkFeeds prueba los feeds del paquete y quita de p los que no responden
+// o no son un feed, informando cuáles.
+func checkFeeds(ctx context.Context, p *seed.Pack) error {
+	r := rss.NewCrawler()
+	type result struct {
+		outlet, feed int
+		err          error
+	}
+	var (
+		mu      sync.Mutex
+		results []result
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, checkConcurrency)
+	)
+	for i, o := range p.Outlets {
+		for j, u := range o.Feeds {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				fctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+				defer cancel()
+				_, err := r.Parser.ParseURLWithContext(u, fctx)
+				mu.Lock()
+				results = append(results, result{i, j, err})
+				mu.Unlock()
+			}()
+		}
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].outlet != results[b].outlet {
+			return results[a].outlet < results[b].outlet
+		}
+		return results[a].feed < results[b].feed
+	})
+	failed := make(map[[2]int]bool)
+	for _, res := range results {
+		if res.err != nil {
+			o := p.Outlets[res.outlet]
+			fmt.Printf("  %-24s %s: %v\n", o.Name, o.Feeds[res.feed], res.err)
+			failed[[2]int{res.outlet, res.feed}] = true
+		}
+	}
+	for i := range p.Outlets {
+		var ok []string
+		for j, u := range p.Outlets[i].Feeds {
+			if !failed[[2]int{i, j}] {
+				ok = append(ok, u)
+			}
+		}
+		p.Outlets[i].Feeds = ok
+	}
+	fmt.Printf("Feeds probados: %d, sin respuesta: %d\n", len(results), len(failed))
+	return nil
+}
+
+// seedConfig arma el YAML de la configuración inicial, comentado para que
+// se pueda seguir editando a mano.
+func seedConfig(p *seed.Pack) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Configuración inicial creada con collector init --seed %s (%s).\n", p.Name, p.Description)
+	b.WriteString("# Ver config.example.yaml para el resto de las fuentes y opciones.\n\n")
+	b.WriteString("sources:\n")
+
+	b.WriteString("  # Feeds de los medios, cada uno con su frecuencia según lo que publica.\n")
+	b.WriteString("  rss:\n")
+	var withFeeds []seed.Outlet
+	for _, o := range p.Outlets {
+		if len(o.Feeds) > 0 {
+			withFeeds = append(withFeeds, o)
+		}
+	}
+	fmt.Fprintf(&b, "    enabled: %t\n", len(withFeeds) > 0)
+	if len(withFeeds) > 0 {
+		b.WriteString("    feeds:\n")
+		for _, o := range withFeeds {
+			fmt.Fprintf(&b, "      # %s (%s)\n", o.Name, o.Place())
+			for _, u := range o.Feeds {
+				fmt.Fprintf(&b, "      - %s\n", quote(u))
+			}
+		}
+	}
+	b.WriteString("    adaptive:\n      min: 15m\n      max: 24h\n")
+
+	b.WriteString("  # Sitemaps de noticias de cada medio, descubiertos en su robots.txt: cubre\n")
+	b.WriteString("  # los medios sin feed. Con query, solo se descargan las notas que la mencionan.\n")
+	b.WriteString("  sitemap:\n")
+	b.WriteString("    enabled: true\n")
+	b.WriteString("    # query: '\"Universidad de Antioquia\" OR UdeA'\n")
+	fmt.Fprintf(&b, "    languages: [%s]\n", strings.Join(seedLanguages(p), ", "))
+	b.WriteString("    from: 2d\n")
+	b.WriteString("    domains:\n")
+	var sitemaps []string
+	for _, o := range p.Outlets {
+		fmt.Fprintf(&b, "      - %s  # %s (%s)\n", quote(o.Domain), o.Name, o.Place())
+		sitemaps = append(sitemaps, o.Sitemaps...)
+	}
+	if len(sitemaps) > 0 {
+		b.WriteString("    sitemaps:\n")
+		for _, s := range sitemaps {
+			fmt.Fprintf(&b, "      - %s\n", quote(s))
+		}
+	}
+	b.WriteString("    max_results: 200\n")
+	b.WriteString("    rate_limit: \"1/s\"\n\n")
+
+	b.WriteString("output:\n  db: corpus.db\n\n")
+
+	if len(withFeeds) > 0 {
+		b.WriteString("# Ediciones de los medios para las campañas (campaigns[].outlets).\n")
+		b.WriteString("feeds:\n  editions:\n")
+		for _, o := range withFeeds {
+			fmt.Fprintf(&b, "    %s:\n", o.Key)
+			for _, u := range o.Feeds {
+				fmt.Fprintf(&b, "      - {name: %s, language: %s, region: %s, url: %s}\n",
+					quote(o.Name), quote(o.Language), quote(p.Country), quote(u))
+			}
+		}
+	}
+	return []byte(b.String())
+}
+
+// seedLanguages son los idiomas de los medios del paquete, sin repetir.
+func seedLanguages(p *seed.Pack) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, o := range p.Outlets {
+		if o.Language != "" && !seen[o.Language] {
+			seen[o.Language] = true
+			out = append(out, o.Language)
+		}
+	}
+	return out
+}
+
+// quote escribe s como cadena YAML entre comillas dobles (JSON es YAML
+// válido).
+func quote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
@@ -0,0 +1,374 @@
+//go:build integration
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-collector/article"
+	"go-collector/collect"
+	"go-collector/config"
+	"go-collector/elastic"
+	"go-collector/kafka"
+	"go-collector/notify"
+	"go-collector/storage"
+)
+
+// Las pruebas de integración levantan Postgres, Elasticsearch y Kafka en
+// contenedores desechables, corren el pipeline completo sobre los fixtures y
+// verifican lo guardado en el corpus, lo replicado en Postgres, lo indexado,
+// lo publicado en Kafka y lo emitido a los webhooks de notificaciones.
+// Necesitan docker:
+//
+//	go test -tags integration -run Integration ./cmd/collector
+//
+// Sin docker se omiten.
+
+const (
+	postgresImage      = "postgres:16-alpine"
+	elasticsearchImage = "docker.elastic.co/elasticsearch/elasticsearch:8.15.0"
+	kafkaImage         = "apache/kafka:3.8.0"
+	kafkaTopic         = "corpus-integration"
+)
+
+func TestIntegrationPipeline(t *testing.T) {
+	pgAddr := startContainer(t, postgresImage, "5432/tcp",
+		"-e", "POSTGRES_PASSWORD=collector", "-e", "POSTGRES_DB=corpus")
+	esAddr := startContainer(t, elasticsearchImage, "9200/tcp",
+		"-e", "discovery.type=single-node", "-e", "xpack.security.enabled=false",
+		"-e", "ES_JAVA_OPTS=-Xms512m -Xmx512m")
+	kafkaID, kafkaAddr := startKafka(t)
+
+	var mirror *sql.DB
+	waitFor(t, "Postgres", time.Minute, func() error {
+		var err error
+		mirror, err = openPostgres(fmt.Sprintf("postgres://postgres:collector@%s/corpus?sslmode=disable", pgAddr))
+		return err
+	})
+	defer mirror.Close()
+	if err := storage.EnsurePostgresSchema(mirror); err != nil {
+		t.Fatal(err)
+	}
+
+	esURL := "http://" + esAddr
+	waitFor(t, "Elasticsearch", 3*time.Minute, func() error {
+		resp, err := http.Get(esURL + "/_cluster/health?wait_for_status=yellow&timeout=5s")
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s", resp.Status)
+		}
+		return nil
+	})
+	index, err := elastic.NewClient(config.Elasticsearch{URL: esURL, Index: "corpus-integration"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tres particiones, para que los artículos se repartan entre ellas.
+	waitFor(t, "Kafka", 2*time.Minute, func() error {
+		return exec.Command("docker", "exec", kafkaID, "/opt/kafka/bin/kafka-topics.sh",
+			"--bootstrap-server", "localhost:29092", "--create", "--if-not-exists",
+			"--topic", kafkaTopic, "--partitions", "3").Run()
+	})
+	producer, err := kafka.NewProducer(config.Kafka{Brokers: []string{kafkaAddr}, Topic: kafkaTopic}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hook := &webhook{}
+	srv := httptest.NewServer(hook)
+	defer srv.Close()
+	notifier, err := notify.New([]config.Notification{{Name: "integracion", URL: srv.URL, Format: "json"}}, config.Relevance{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := storage.Open(filepath.Join(t.TempDir(), "corpus.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	sources := &config.Sources{Fixtures: filepath.Join("..", "..", "fixtures")}
+	for _, n := range sources.Named() {
+		if _, err := collect.Fixture(sources.Fixtures, n.Name); err == nil && n.Name != "mock" {
+			n.Enabled = true
+		}
+	}
+	c := &collect.Collector{}
+	now := time.Now().UTC()
+	results := c.Enabled(context.Background(), sources, "", now)
+	dst := sink{store: store, mirror: mirror, index: index, kafka: producer, notify: notifier, out: io.Discard}
+	if err := saveResults(c, dst, results, now); err != nil {
+		t.Fatal(err)
+	}
+
+	// Si una URL se repite entre fuentes, vale la última guardada.
+	titles := make(map[string]string)
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: %v", r.Source, r.Err)
+			continue
+		}
+		for _, a := range r.Articles {
+			if a.URL != "" {
+				titles[a.URL] = a.Title
+			}
+		}
+	}
+	if len(titles) == 0 {
+		t.Fatal("los fixtures no dieron artículos")
+	}
+
+	for u, title := range titles {
+		a, err := store.GetByURL(u)
+		if err != nil {
+			t.Errorf("corpus: %s: %v", u, err)
+			continue
+		}
+		if a.Title != title {
+			t.Errorf("corpus: %s con título %q, se esperaba %q", u, a.Title, title)
+		}
+
+		var mirrored string
+		err = mirror.QueryRow(`SELECT title FROM articles WHERE url = $1`, u).Scan(&mirrored)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			t.Errorf("postgres: falta %s", u)
+		case err != nil:
+			t.Fatalf("error consultando Postgres: %v", err)
+		case mirrored != title:
+			t.Errorf("postgres: %s con título %q, se esperaba %q", u, mirrored, title)
+		}
+
+		var doc struct {
+			Found  bool `json:"found"`
+			Source struct {
+				URL   string `json:"url"`
+				Title string `json:"title"`
+			} `json:"_source"`
+		}
+		if err := getJSON(fmt.Sprintf("%s/%s/_doc/%s", esURL, index.IndexName, elastic.DocID(u)), &doc); err != nil {
+			t.Fatalf("error consultando Elasticsearch: %v", err)
+		}
+		switch {
+		case !doc.Found:
+			t.Errorf("elasticsearch: falta %s", u)
+		case doc.Source.Title != title:
+			t.Errorf("elasticsearch: %s con título %q, se esperaba %q", u, doc.Source.Title, title)
+		}
+	}
+
+	if _, err := http.Post(fmt.Sprintf("%s/%s/_refresh", esURL, index.IndexName), "application/json", nil); err != nil {
+		t.Fatal(err)
+	}
+	var count struct {
+		Count int `json:"count"`
+	}
+	if err := getJSON(fmt.Sprintf("%s/%s/_count", esURL, index.IndexName), &count); err != nil {
+		t.Fatal(err)
+	}
+	if count.Count != len(titles) {
+		t.Errorf("elasticsearch: %d documentos, se esperaban %d", count.Count, len(titles))
+	}
+
+	published := consumeKafka(t, kafkaID)
+	for u, title := range titles {
+		switch value, ok := published[u]; {
+		case !ok:
+			t.Errorf("kafka: falta %s", u)
+		case value.Title != title:
+			t.Errorf("kafka: %s con título %q, se esperaba %q", u, value.Title, title)
+		}
+	}
+	if len(published) != len(titles) {
+		t.Errorf("kafka: %d claves publicadas, se esperaban %d", len(published), len(titles))
+	}
+
+	emitted := hook.urls()
+	for u := range titles {
+		if !emitted[u] {
+			t.Errorf("webhook: no se notificó %s", u)
+		}
+	}
+	if len(emitted) != len(titles) {
+		t.Errorf("webhook: %d artículos notificados, se esperaban %d", len(emitted), len(titles))
+	}
+}
+
+// startContainer levanta la imagen con docker y devuelve la dirección
+// (host:puerto) en que quedó publicado port. El contenedor se borra al
+// terminar la prueba.
+func startContainer(t *testing.T, image, port string, args ...string) string {
+	t.Helper()
+	id := runContainer(t, image, append([]string{"-p", "127.0.0.1::" + port}, args...)...)
+	out, err := exec.Command("docker", "port", id, port).Output()
+	if err != nil {
+		t.Fatalf("docker port %s: %v", image, commandError(err))
+	}
+	addr, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return addr
+}
+
+// runContainer levanta la imagen con docker con los argumentos de docker run
+// args y devuelve el id del contenedor, que se borra al terminar la prueba.
+func runContainer(t *testing.T, image string, args ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("docker no está disponible")
+	}
+	run := append([]string{"run", "-d", "--rm"}, args...)
+	out, err := exec.Command("docker", append(run, image)...).Output()
+	if err != nil {
+		t.Fatalf("docker run %s: %v", image, commandError(err))
+	}
+	id := strings.TrimSpace(string(out))
+	t.Cleanup(func() { exec.Command("docker", "rm", "-f", id).Run() })
+	return id
+}
+
+// startKafka levanta un broker de Kafka en modo KRaft y devuelve el id del
+// contenedor y la dirección para el productor. El broker anuncia esa
+// dirección en la metadata, así que el puerto del host se elige antes de
+// levantarlo; dentro del contenedor las herramientas usan localhost:29092.
+func startKafka(t *testing.T) (id, addr string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr = ln.Addr().String()
+	ln.Close()
+	_, port, _ := net.SplitHostPort(addr)
+	id = runContainer(t, kafkaImage, "-p", addr+":9092",
+		"-e", "KAFKA_NODE_ID=1",
+		"-e", "KAFKA_PROCESS_ROLES=broker,controller",
+		"-e", "KAFKA_LISTENERS=PLAINTEXT://:9092,INTERNAL://:29092,CONTROLLER://:9093",
+		"-e", "KAFKA_ADVERTISED_LISTENERS=PLAINTEXT://"+net.JoinHostPort("127.0.0.1", port)+",INTERNAL://localhost:29092",
+		"-e", "KAFKA_LISTENER_SECURITY_PROTOCOL_MAP=CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT,INTERNAL:PLAINTEXT",
+		"-e", "KAFKA_INTER_BROKER_LISTENER_NAME=INTERNAL",
+		"-e", "KAFKA_CONTROLLER_LISTENER_NAMES=CONTROLLER",
+		"-e", "KAFKA_CONTROLLER_QUORUM_VOTERS=1@localhost:9093",
+		"-e", "KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR=1",
+		"-e", "KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR=1",
+		"-e", "KAFKA_TRANSACTION_STATE_LOG_MIN_ISR=1",
+		"-e", "KAFKA_GROUP_INITIAL_REBALANCE_DELAY_MS=0")
+	return id, addr
+}
+
+// consumeKafka lee desde el principio el tópico de la prueba con el
+// consumidor de consola del contenedor y devuelve el último artículo
+// publicado con cada clave. Los mensajes de una clave están en una sola
+// partición, en orden.
+func consumeKafka(t *testing.T, id string) map[string]article.Article {
+	t.Helper()
+	out, err := exec.Command("docker", "exec", id, "/opt/kafka/bin/kafka-console-consumer.sh",
+		"--bootstrap-server", "localhost:29092", "--topic", kafkaTopic, "--from-beginning",
+		"--property", "print.key=true", "--timeout-ms", "15000").Output()
+	if err != nil {
+		t.Fatalf("kafka-console-consumer: %v", commandError(err))
+	}
+	published := make(map[string]article.Article)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		key, value, ok := strings.Cut(line, "\t")
+		if !ok {
+			t.Fatalf("kafka: mensaje sin clave: %q", line)
+		}
+		var a article.Article
+		if err := json.Unmarshal([]byte(value), &a); err != nil {
+			t.Fatalf("kafka: %s: %v", key, err)
+		}
+		if a.URL != key {
+			t.Errorf("kafka: clave %s para el artículo %s", key, a.URL)
+		}
+		published[key] = a
+	}
+	return published
+}
+
+// commandError agrega al error de un comando lo que escribió en stderr.
+func commandError(err error) error {
+	var exit *exec.ExitError
+	if errors.As(err, &exit) && len(exit.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exit.Stderr)))
+	}
+	return err
+}
+
+// waitFor reintenta ready hasta que no falle o pase timeout.
+func waitFor(t *testing.T, what string, timeout time.Duration, ready func() error) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		err := ready()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s no respondió en %s: %v", what, timeout, err)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func getJSON(url string, v any) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// webhook recibe las notificaciones en formato json y anota las URLs de los
+// artículos notificados.
+type webhook struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func (h *webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Articles []struct {
+			URL string `json:"url"`
+		} `json:"articles"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.seen == nil {
+		h.seen = make(map[string]bool)
+	}
+	for _, a := range payload.Articles {
+		h.seen[a.URL] = true
+	}
+}
+
+func (h *webhook) urls() map[string]bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.seen
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"go-collector/article"
+	"go-collector/storage"
+)
+
+// runLabels importa etiquetas manuales desde un CSV con columnas
+// "id_o_url,etiqueta[,anotador]" (la primera fila puede ser encabezado).
+func runLabels(args []string) error {
+	if len(args) == 0 || args[0] != "import" {
+		return fmt.Errorf("uso: collector labels import --file etiquetas.csv")
+	}
+	fs := flag.NewFlagSet("labels import", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	file := fs.String("file", "", "CSV con id_o_url,etiqueta[,anotador]")
+	parseFlags(fs, args[1:])
+	if *file == "" {
+		return fmt.Errorf("falta --file")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	imported, skipped := 0, 0
+	for line := 1; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", *file, line, err)
+		}
+		if len(rec) < 2 || (line == 1 && strings.EqualFold(rec[1], "label")) {
+			continue
+		}
+
+		id, err := resolveArticle(store, strings.TrimSpace(rec[0]))
+		if err != nil {
+			fmt.Printf("  %s:%d: artículo no encontrado: %s\n", *file, line, rec[0])
+			skipped++
+			continue
+		}
+		annotator := ""
+		if len(rec) > 2 {
+			annotator = strings.TrimSpace(rec[2])
+		}
+		if err := store.SetLabel(id, strings.TrimSpace(rec[1]), annotator); err != nil {
+			return err
+		}
+		imported++
+	}
+	fmt.Printf("Etiquetas importadas: %d | Omitidas: %d\n", imported, skipped)
+	return audit(store, storage.AuditImport, *file, fmt.Sprintf("etiquetas: %d importadas, %d omitidas", imported, skipped))
+}
+
+// resolveArticle acepta un ID numérico o la URL del artículo.
+func resolveArticle(store *storage.Store, ref string) (int64, error) {
+	var (
+		a   *article.Article
+		err error
+	)
+	if id, perr := strconv.ParseInt(ref, 10, 64); perr == nil {
+		a, err = store.GetByID(id)
+	} else {
+		a, err = store.GetByURL(ref)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return a.ID, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"slices"
+
+	"go-collector/collect"
+	"go-collector/lang"
+)
+
+// runLanguage detecta el idioma de los artículos guardados sin él (los
+// recolectados antes de que collect lo detectara) y normaliza a ISO 639-1
+// los códigos de las fuentes (ej: "en-US").
+func runLanguage(args []string) error {
+	fs := flag.NewFlagSet("language", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	retry := fs.Bool("retry", false, "volver a intentar los que quedaron como no determinados (und)")
+	dryRun := fs.Bool("dry-run", false, "mostrar lo que se detectaría sin guardarlo")
+	parseFlags(fs, args)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	pending, err := store.ArticlesWithoutLanguage(*retry)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Println("Todos los artículos tienen idioma.")
+		return nil
+	}
+	detected := map[string]int{}
+	changed := 0
+	for _, a := range pending {
+		code := lang.Normalize(a.Language)
+		if code == "" || (*retry && code == lang.Undetermined) {
+			code = lang.Detect(collect.LanguageSample(a))
+			detected[code]++
+		}
+		if code == a.Language {
+			continue
+		}
+		changed++
+		if *dryRun {
+			fmt.Printf("  #%-6d %-4q -> %-4s %s\n", a.ID, a.Language, code, a.Title)
+			continue
+		}
+		if err := store.SetLanguage(a.ID, code); err != nil {
+			return err
+		}
+	}
+
+	if len(detected) > 0 {
+		fmt.Println("Detectados:")
+		codes := slices.Sorted(maps.Keys(detected))
+		for _, code := range codes {
+			fmt.Printf("  %-4s %d\n", code, detected[code])
+		}
+	}
+	verb := "actualizados"
+	if *dryRun {
+		verb = "se actualizarían"
+	}
+	fmt.Printf("\n%d artículos %s.\n", changed, verb)
+	return nil
+}
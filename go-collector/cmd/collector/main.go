@@ -0,0 +1,672 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// command es un subcomando del recolector. usage, actions y examples
+// alimentan la ayuda ("collector help <comando>") y las completions.
+type command struct {
+	name    string
+	summary string
+	usage   string   // lo que sigue a "collector <nombre>"
+	actions []string // primeras palabras posibles (acciones o fuentes)
+	// examples alterna comentario y línea de comando: {"# qué hace", "collector ..."}.
+	examples []string
+	run      func(args []string) error
+}
+
+// commands se arma en init: help, completion y la ayuda de cada comando
+// recorren la tabla, y declararla con valor inicial sería un ciclo de
+// inicialización.
+var commands []command
+
+func init() {
+	commands = []command{
+		{
+			name: "accounts", summary: "Registra a diario los seguidores y la biografía de las cuentas monitoreadas",
+			usage: "snapshot|history [opciones]", actions: []string{"snapshot", "history"},
+			examples: []string{
+				"# Estado de hoy de las cuentas de accounts (una vez al día, con cron)",
+				"collector accounts snapshot",
+				"# Serie de una cuenta desde octubre, para graficar",
+				"collector accounts history --account x/UdeA --since 2024-10-01 --format csv --out udea.csv",
+			},
+			run: runAccounts,
+		},
+		{
+			name: "ask", summary: "(Experimental) Responde preguntas sobre el corpus con un LLM y citas",
+			usage: `[opciones] "pregunta"`,
+			examples: []string{
+				"# Pregunta con los 8 artículos más parecidos como contexto",
+				`collector ask "¿Qué se dijo del paro estudiantil en octubre?"`,
+			},
+			run: runAsk,
+		},
+		{
+			name: "audit", summary: "Consulta la bitácora de acciones administrativas (audit list)",
+			usage: "list [opciones]", actions: []string{"list"},
+			examples: []string{
+				"# Restauraciones del último mes",
+				"collector audit list --action restore --since 2024-05-01",
+				"# Todo lo que hizo una persona",
+				"collector audit list --actor ana@laboratorio",
+			},
+			run: runAudit,
+		},
+		{
+			name: "backup", summary: "Respalda la base y las páginas crudas en un tar.zst (completo o incremental)",
+			usage: "[opciones]",
+			examples: []string{
+				"# Respaldo completo en ./backups",
+				"collector backup",
+				"# Solo las páginas crudas nuevas desde el último respaldo",
+				"collector backup --incremental",
+				"collector backup --list",
+			},
+			run: runBackup,
+		},
+		{
+			name: "blocked", summary: "Dominios que bloquean las descargas (429/403) de forma recurrente",
+			usage: "[opciones]",
+			examples: []string{
+				"collector blocked",
+				"collector blocked --min 10",
+			},
+			run: runBlocked,
+		},
+		{
+			name: "chart", summary: "Gráficos de volumen por día, fuente o idioma (SVG/PNG)",
+			usage: "[opciones]",
+			examples: []string{
+				"# Volumen diario de los últimos 90 días",
+				"collector chart --kind volume --days 90 --out volumen.svg",
+				"# Torta de idiomas en PNG para una presentación",
+				"collector chart --kind languages --format png --out idiomas.png",
+			},
+			run: runChart,
+		},
+		{
+			name: "collect", summary: "Recolecta de las fuentes activadas en la configuración y guarda en el corpus",
+			usage: "[opciones]",
+			examples: []string{
+				"# Una ronda con las fuentes activadas en config.yaml",
+				"collector collect",
+				"# Solo GDELT, sin guardar, para probar la consulta",
+				"collector collect --source gdelt --dry-run",
+				"# Una consulta puntual sin tocar config.yaml",
+				`collector collect --source guardian --query "Universidad de Antioquia" --from 2024-05-01 --to 2024-05-31`,
+				"# Daemon cada hora (recarga la configuración al cambiar)",
+				"collector collect --every 1h",
+				"# Daemon de lunes a viernes a las 6:00 (con sources.<fuente>.incremental, solo lo nuevo)",
+				`collector collect --schedule "0 6 * * 1-5"`,
+				"# Con las respuestas de prueba, sin credenciales",
+				"collector --profile dev collect",
+				"# Todas las campañas en paralelo, cada una en su corpus",
+				"collector collect --campaign all --every 1h",
+				"# Prueba de carga con artículos sintéticos (sources.mock)",
+				"collector collect --source mock --db carga.db",
+				"# Eventos de avance en JSON para otra herramienta",
+				"collector collect --progress json 2> eventos.jsonl",
+			},
+			run: runCollect,
+		},
+		{
+			name: "completion", summary: "Genera el script de autocompletado para bash, zsh o fish",
+			usage: "bash|zsh|fish", actions: []string{"bash", "zsh", "fish"},
+			examples: []string{
+				"# bash: agregar a ~/.bashrc",
+				`eval "$(collector completion bash)"`,
+				"# zsh: guardar en un directorio de $fpath",
+				"collector completion zsh > ~/.zfunc/_collector",
+				"# fish",
+				"collector completion fish > ~/.config/fish/completions/collector.fish",
+			},
+			run: runCompletion,
+		},
+		{
+			name: "compress", summary: "Compresión zstd del corpus con diccionarios: train, apply, bench, status",
+			usage: "train|apply|bench|status [opciones]", actions: []string{"train", "apply", "bench", "status"},
+			examples: []string{
+				"# Cuánto ocuparían los cuerpos y las páginas crudas con cada método",
+				"collector compress bench",
+				"# Entrenar los diccionarios con el corpus y recomprimir lo guardado",
+				"collector compress train",
+				"collector compress apply",
+				"collector compress status",
+			},
+			run: runCompress,
+		},
+		{
+			name: "config", summary: "Valida el archivo de configuración (config validate)",
+			usage: "validate [archivo]", actions: []string{"validate"},
+			examples: []string{
+				"# Revisa config.yaml y todos sus perfiles",
+				"collector config validate",
+				"# Solo el perfil de producción",
+				"collector --profile prod config validate",
+			},
+			run: runConfigCmd,
+		},
+		{
+			name: "dates", summary: "Calidad de las fechas por feed: sin interpretar, futuras o imposibles (corregidas)",
+			usage: "[opciones]",
+			examples: []string{
+				"collector dates",
+				"collector dates --since 2024-05-01 --source rss",
+				"collector dates --all --format csv > fechas.csv",
+			},
+			run: runDates,
+		},
+		{
+			name: "describe", summary: "Estadísticas del corpus listas para publicación (Markdown/LaTeX)",
+			usage: "[opciones]",
+			examples: []string{
+				"# Tabla para el artículo en LaTeX",
+				"collector describe --format latex > tabla.tex",
+			},
+			run: runDescribe,
+		},
+		{
+			name: "embed", summary: "Calcula embeddings de los artículos y detecta casi duplicados",
+			usage: "[opciones]",
+			examples: []string{
+				"# Calcula los vectores que falten",
+				"collector embed",
+				"# Además lista pares casi duplicados",
+				"collector embed --dedup 0.95",
+			},
+			run: runEmbed,
+		},
+		{
+			name: "encrypt", summary: "Cifrado en reposo: keygen, apply (cifra lo ya guardado)",
+			usage: "keygen|apply [opciones]", actions: []string{"keygen", "apply"},
+			examples: []string{
+				"# Genera una clave y la guarda fuera del repositorio",
+				"collector encrypt keygen > ~/.collector.key",
+				"# Cifra lo guardado antes de activar storage.encryption",
+				"collector encrypt apply",
+			},
+			run: runEncrypt,
+		},
+		{
+			name: "entities", summary: "Extrae personas, organizaciones, lugares y palabras clave de los artículos",
+			usage: "[opciones]",
+			examples: []string{
+				"collector entities",
+				"collector entities --all && collector stats",
+			},
+			run: runEntities,
+		},
+		{
+			name: "events", summary: "Cobertura por evento (fuentes que agrupan artículos, como eventregistry): list, show",
+			usage: "list [opciones] | show <uri>", actions: []string{"list", "show"},
+			examples: []string{
+				"# Eventos de octubre con al menos 3 artículos en el corpus",
+				"collector events list --since 2026-10-01 --min 3",
+				"collector events show spa-3108842",
+				"collector events list --format csv > eventos.csv",
+			},
+			run: runEvents,
+		},
+		{
+			name: "explore", summary: "Consulta una fuente (guardian, newsapi, gdelt, x, rss) y muestra estadísticas",
+			usage: "<guardian|newsapi|gdelt|x|rss> [opciones] [url ...]", actions: []string{"guardian", "newsapi", "gdelt", "x", "rss"},
+			examples: []string{
+				"# GDELT en español e inglés durante 2023",
+				"collector explore gdelt --from 2023-01-01 --to 2023-12-31",
+				"# The Guardian con la clave en la variable de entorno",
+				`GUARDIAN_API_KEY=... collector explore guardian --query "Medellín"`,
+				"# Feeds de un medio del catálogo",
+				"collector explore rss --outlet bbc --lang es",
+			},
+			run: runExplore,
+		},
+		{
+			name: "export", summary: "Exporta el corpus a JSONL, CSV o JSON para pandas, Excel o jq",
+			usage: "[opciones]",
+			examples: []string{
+				"# Todo el corpus activo como JSON Lines",
+				"collector export --out corpus.jsonl",
+				"# Planilla de mayo con las columnas elegidas",
+				"collector export --format csv --from 2024-05-01 --to 2024-05-31 --columns published,source,title,url --out mayo.csv",
+				"# Solo GDELT en español, a jq",
+				"collector export --source gdelt --lang es | jq .title",
+				"# Corpus de la campaña con la auditoría de cómo se seleccionó cada artículo",
+				"collector export --out udea.jsonl --audit udea-auditoria.jsonl --campaign udea",
+			},
+			run: runExport,
+		},
+		{
+			name: "exports", summary: "Exportaciones programadas a SFTP o WebDAV: list, run <nombre>, daemon",
+			usage: "list|run <nombre>|daemon [opciones]", actions: []string{"list", "run", "daemon"},
+			examples: []string{
+				"# Próxima ejecución y última subida de cada exportación",
+				"collector exports list",
+				"# Arma y sube el paquete ahora",
+				"collector exports run archivo-semanal",
+				"collector exports daemon",
+			},
+			run: runExports,
+		},
+		{
+			name: "extract", summary: "Prueba la extracción de texto completo de una página y muestra cada estrategia",
+			usage: "[opciones] <url>",
+			examples: []string{
+				"collector extract https://www.elcolombiano.com/antioquia/nota",
+				"collector extract --file pagina.html https://www.elcolombiano.com/",
+				"collector extract --render always https://www.elcolombiano.com/antioquia/nota",
+			},
+			run: runExtract,
+		},
+		{
+			name: "freshness", summary: "Demora de cada fuente entre la publicación y la llegada al corpus",
+			usage: "[opciones]",
+			examples: []string{
+				"collector freshness",
+				"# Solo el último mes y las fuentes de noticias",
+				"collector freshness --since 2024-05-01 --source gdelt,rss,newsapi",
+				"collector freshness --format csv > demora.csv",
+			},
+			run: runFreshness,
+		},
+		{
+			name: "fulltext", summary: "Descarga el texto completo de los artículos guardados sin él (rss, gdelt, newsapi)",
+			usage: "[opciones]",
+			examples: []string{
+				"collector fulltext",
+				"# De a poco y solo los feeds",
+				"collector fulltext --source rss --limit 200",
+				"# Después de configurar fetch.consent_cookies, los que quedaron tras un muro",
+				"collector fulltext --retry",
+			},
+			run: runFulltext,
+		},
+		{
+			name: "grafana", summary: "Sirve los agregados como datasource JSON de Grafana",
+			usage: "[opciones]",
+			examples: []string{
+				"collector grafana --addr 127.0.0.1:3030 --token secreto",
+			},
+			run: runGrafana,
+		},
+		{
+			name: "grpc", summary: "API gRPC para iniciar rondas, seguir su estado y recibir los artículos en vivo",
+			usage: "[opciones]",
+			examples: []string{
+				"collector grpc --addr 127.0.0.1:9090 --token secreto",
+				"# Con grpcurl: iniciar una ronda de una fuente y seguir sus artículos",
+				`grpcurl -plaintext -H "authorization: Bearer secreto" -proto rpc/collector.proto -d '{"source": "rss", "from": "2d"}' 127.0.0.1:9090 collector.v1.Collector/TriggerCollection`,
+				`grpcurl -plaintext -H "authorization: Bearer secreto" -proto rpc/collector.proto -d '{"run_id": 128}' 127.0.0.1:9090 collector.v1.Collector/StreamArticles`,
+			},
+			run: runGRPC,
+		},
+		{
+			name: "help", summary: "Muestra la ayuda de un comando con ejemplos",
+			usage: "[comando]",
+			examples: []string{
+				"collector help collect",
+			},
+			run: runHelp,
+		},
+		{
+			name: "index", summary: "Indexa el corpus en Elasticsearch/OpenSearch (output.elasticsearch) para Kibana",
+			usage: "[opciones]",
+			examples: []string{
+				"collector index",
+				"# Con el mapping actual, desde cero",
+				"collector index --recreate",
+				"collector index --from 2024-01-01 --source guardian,rss",
+			},
+			run: runIndex,
+		},
+		{
+			name: "init", summary: "Crea una configuración inicial con un paquete de medios incluido",
+			usage: "--seed colombia [opciones]",
+			examples: []string{
+				"# Medios nacionales y regionales de Colombia: feeds, sitemaps y ediciones",
+				"collector init --seed colombia",
+				"# Probar los feeds antes y dejar fuera los que no responden",
+				"collector init --seed colombia --check --config colombia.yaml",
+			},
+			run: runInit,
+		},
+		{
+			name: "labels", summary: "Importa etiquetas manuales desde CSV",
+			usage: "import --file etiquetas.csv", actions: []string{"import"},
+			examples: []string{
+				"# CSV con id_o_url,etiqueta[,anotador]",
+				"collector labels import --file etiquetas.csv",
+			},
+			run: runLabels,
+		},
+		{
+			name: "language", summary: "Detecta el idioma de los artículos guardados sin él",
+			usage: "[opciones]",
+			examples: []string{
+				"collector language --dry-run",
+				"collector language --retry",
+			},
+			run: runLanguage,
+		},
+		{
+			name: "media", summary: "Descarga los adjuntos (enclosures, imágenes) detectando su tipo real",
+			usage: "[opciones]",
+			examples: []string{
+				"collector media",
+				"# De a poco, y volviendo a intentar los que fallaron",
+				"collector media --limit 100 --retry",
+				"# Las fotos de agencia que más medios reutilizaron",
+				"collector media --shared 20",
+			},
+			run: runMedia,
+		},
+		{
+			name: "merge-campaigns", summary: "Combina campañas solapadas en el corpus de otra, con deduplicación e informe",
+			usage: "--into <campaña> <campaña>...",
+			examples: []string{
+				"# Ver qué pasaría sin tocar nada",
+				"collector merge-campaigns --dry-run --into udea udea-paro",
+				"# Combinar dos campañas en una nueva y guardar el informe",
+				"collector merge-campaigns --into udea-todo --report combinacion.md udea udea-paro",
+			},
+			run: runMergeCampaigns,
+		},
+		{
+			name: "migrate-store", summary: "Copia el corpus SQLite a Postgres y verifica los conteos",
+			usage: "[opciones]",
+			examples: []string{
+				"COLLECTOR_POSTGRES_DSN=postgres://collector@db/corpus collector migrate-store",
+				"# Sin el contenido de las páginas crudas",
+				"collector migrate-store --postgres postgres://collector@db/corpus --skip-raw",
+			},
+			run: runMigrateStore,
+		},
+		{
+			name: "network", summary: "Exporta la red de retweets, citas y respuestas de X (CSV o GraphML)",
+			usage: "[opciones]",
+			examples: []string{
+				"collector network --format graphml --out red.graphml",
+				"# Solo la cascada de los tweets de una historia, entre tweets",
+				"collector network --group 12 --nodes tweets --out historia.csv",
+			},
+			run: runNetwork,
+		},
+		{
+			name: "notifications", summary: "Avisos a webhooks que no llegaron: failed, resend",
+			usage: "failed [--all] | resend [--id N]", actions: []string{"failed", "resend"},
+			examples: []string{
+				"collector notifications failed",
+				"# Después de arreglar la URL del webhook en la configuración",
+				"collector notifications resend",
+			},
+			run: runNotifications,
+		},
+		{
+			name: "report", summary: "Reportes programados: list, run <nombre>, daemon",
+			usage: "list|run <nombre>|daemon [opciones]", actions: []string{"list", "run", "daemon"},
+			examples: []string{
+				"# Próxima ejecución de cada reporte",
+				"collector report list",
+				"# Genera y envía un reporte ahora",
+				"collector report run resumen-semanal",
+				"collector report daemon",
+			},
+			run: runReport,
+		},
+		{
+			name: "restore", summary: "Restaura un respaldo, incluida su cadena de incrementales",
+			usage: "--from respaldo.tar.zst [opciones]",
+			examples: []string{
+				"collector restore --from backups/corpus-20240501-120000.tar.zst --db restaurado.db",
+			},
+			run: runRestore,
+		},
+		{
+			name: "retry-failures", summary: "Reintenta solo lo que falló en una ronda, con su consulta y rango originales",
+			usage: "--run <id> [opciones]",
+			examples: []string{
+				"# Ver qué falló y reintentarlo",
+				"collector runs show 128",
+				"collector retry-failures --run 128",
+				"# Una ronda de campaña, en el corpus de la campaña",
+				"collector retry-failures --db corpus-udea.db --run 42",
+			},
+			run: runRetryFailures,
+		},
+		{
+			name: "revisit", summary: "Vuelve a descargar las notas que probablemente cambiaron y registra si su texto cambió",
+			usage: "[opciones]",
+			examples: []string{
+				"# Qué toca revisitar y por qué (edad, dominio, lastmod del sitemap)",
+				"collector revisit --dry-run",
+				"# Cada 6 horas, con cron",
+				"collector revisit --limit 500",
+			},
+			run: runRevisit,
+		},
+		{
+			name: "runs", summary: "Historial de rondas de recolección: list, show <id>, article <id|url>",
+			usage: "list [opciones] | show <id> [--articles] | article <id|url>", actions: []string{"list", "show", "article"},
+			examples: []string{
+				"# ¿Qué pasó el martes?",
+				"collector runs list --since 2024-05-14 --until 2024-05-14",
+				"# Rondas con fuentes caídas o cortadas",
+				"collector runs list --status partial",
+				"# Qué trajo una ronda, agrupado por consulta",
+				"collector runs show --articles 128",
+				"# Qué ronda y qué consulta trajeron un artículo",
+				"collector runs article 4512",
+			},
+			run: runRuns,
+		},
+		{
+			name: "search", summary: "Busca artículos por palabras clave o por similitud de embeddings",
+			usage: `[opciones] "consulta"`,
+			examples: []string{
+				`collector search "paro estudiantil"`,
+				"# Por significado, no por palabras exactas (con un modelo: embeddings.provider openai)",
+				`collector search --semantic -k 20 "financiación de universidades públicas"`,
+			},
+			run: runSearch,
+		},
+		{
+			name: "selfcheck", summary: "Corre el pipeline sobre los fixtures y verifica corpus, JSONL y réplica",
+			usage: "[opciones]",
+			examples: []string{
+				"# Corpus y JSONL en un directorio temporal",
+				"collector selfcheck",
+				"# Las reglas de extracción por dominio contra fixtures/extract",
+				"collector selfcheck --config config.example.yaml",
+				"# Aceptar un cambio intencional en lo que se extrae",
+				"collector selfcheck --config config.example.yaml --update",
+				"# También la réplica, en un Postgres desechable",
+				"docker run -d --rm -p 5432:5432 -e POSTGRES_PASSWORD=x postgres:16",
+				"collector selfcheck --postgres postgres://postgres:x@localhost/postgres",
+			},
+			run: runSelfcheck,
+		},
+		{
+			name: "sentiment", summary: "Tono de la cobertura: score puntúa los artículos, report lo agrega en el tiempo",
+			usage: "score [opciones] | report [opciones]", actions: []string{"score", "report"},
+			examples: []string{
+				"# Puntuar lo recolectado antes de activar sentiment.enabled",
+				"collector sentiment score",
+				"# Tono semanal de la cobertura del último semestre",
+				"collector sentiment report --since 2024-01-01 --by week",
+				"collector sentiment report --by month --format csv > tono.csv",
+			},
+			run: runSentiment,
+		},
+		{
+			name: "serve", summary: "API HTTP: artículos (JSONL/CSV/JSON), stats, rondas y desactivación de fuentes",
+			usage: "[opciones]",
+			examples: []string{
+				"collector serve --addr 127.0.0.1:8080",
+				"# Artículos de mayo en CSV",
+				`curl "http://127.0.0.1:8080/articles?format=csv&from=2024-05-01&to=2024-05-31"`,
+				"curl http://127.0.0.1:8080/stats",
+				"# De a 100 artículos: la siguiente página va en el encabezado Link",
+				`curl -i "http://127.0.0.1:8080/articles?format=json&source=eltiempo&limit=100&offset=200"`,
+				`curl "http://127.0.0.1:8080/stats/sections?source=eltiempo&from=2024-05-01"`,
+				"curl http://127.0.0.1:8080/runs/128/articles",
+				"# Desactivar una fuente (requiere --token)",
+				`curl -X POST -H "Authorization: Bearer $COLLECTOR_API_TOKEN" "http://127.0.0.1:8080/sources/newsapi/disable?reason=mantenimiento&for=6h"`,
+			},
+			run: runServe,
+		},
+		{
+			name: "sources", summary: "Desactiva y reactiva fuentes sin editar la configuración: list, disable, enable",
+			usage: "list | disable [--reason texto] [--for lapso] <fuente> | enable <fuente>", actions: []string{"list", "disable", "enable"},
+			examples: []string{
+				"# La API de NewsAPI está en mantenimiento hasta la tarde",
+				`collector sources disable --reason "mantenimiento anunciado" --for 6h newsapi`,
+				"collector sources list",
+				"collector sources enable newsapi",
+			},
+			run: runSources,
+		},
+		{
+			name: "split", summary: "Exporta train/dev/test estratificado por fuente y etiqueta",
+			usage: "[opciones]",
+			examples: []string{
+				"collector split --out splits --ratios 0.7,0.15,0.15 --seed 7",
+			},
+			run: runSplit,
+		},
+		{
+			name: "stats", summary: "Estado del corpus de un vistazo: totales, fuentes, idiomas y última ronda",
+			usage: "[opciones]",
+			examples: []string{
+				"collector stats",
+				"collector stats --format json | jq .by_source",
+			},
+			run: runStats,
+		},
+		{
+			name: "timeline", summary: "Cronología de una historia o entidad con picos de volumen (HTML/JSON)",
+			usage: "[opciones]",
+			examples: []string{
+				"collector timeline --entity UdeA --out udea.html",
+				"collector timeline --group 42 --format json",
+			},
+			run: runTimeline,
+		},
+		{
+			name: "trends", summary: "Palabras clave que más suben y más bajan esta semana frente al último mes",
+			usage: "[opciones]",
+			examples: []string{
+				"collector trends",
+				"collector trends --limit 30 --min 5 --format json",
+				"# Después de un merge, o para dejar fuera los artículos retirados",
+				"collector trends --rebuild",
+			},
+			run: runTrends,
+		},
+		{
+			name: "unfurl", summary: "Datos de los videos de YouTube y Vimeo enlazados en los artículos",
+			usage: "[opciones]",
+			examples: []string{
+				"collector unfurl",
+				"collector unfurl --retry --limit 50",
+			},
+			run: runUnfurl,
+		},
+		{
+			name: "usage", summary: "Peticiones a cada API por mes, costo estimado de los planes pagos y artículos por petición",
+			usage: "[opciones]",
+			examples: []string{
+				"collector usage",
+				"# El último año, para el presupuesto",
+				"collector usage --months 12",
+				"collector usage --since 2024-01 --until 2024-06 --format csv > uso.csv",
+			},
+			run: runUsage,
+		},
+		{
+			name: "version", summary: "Versión y commit del recolector",
+			usage: "[opciones]",
+			examples: []string{
+				"collector version",
+				"collector version --format json",
+			},
+			run: runVersion,
+		},
+		{
+			name: "wayback", summary: "Capturas de los artículos en el Internet Archive y pedidos de Save Page Now",
+			usage: "[opciones]",
+			examples: []string{
+				"collector wayback",
+				"# Pedir la captura de los que no están archivados",
+				"collector wayback --save --limit 100",
+				"collector wayback --recheck",
+			},
+			run: runWayback,
+		},
+		{
+			name: "withdrawn", summary: "Artículos retirados por los medios: check revisa las URLs, list muestra los marcados",
+			usage: "check [opciones] | list [opciones]", actions: []string{"check", "list"},
+			examples: []string{
+				"# Revisar lo publicado este año (404, 410, redirección a la portada, aviso de retiro)",
+				"collector withdrawn check --since 2026-01-01",
+				"collector withdrawn check --source rss,sitemap --limit 500",
+				"collector withdrawn list",
+			},
+			run: runWithdrawn,
+		},
+	}
+}
+
+func main() {
+	// --profile antes del comando elige el perfil de configuración para
+	// cualquier subcomando (equivale a COLLECTOR_PROFILE).
+	if len(os.Args) > 2 && os.Args[1] == "--profile" {
+		os.Setenv("COLLECTOR_PROFILE", os.Args[2])
+		os.Args = append(os.Args[:1], os.Args[3:]...)
+	} else if len(os.Args) > 1 && strings.HasPrefix(os.Args[1], "--profile=") {
+		os.Setenv("COLLECTOR_PROFILE", strings.TrimPrefix(os.Args[1], "--profile="))
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		usage()
+		return
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "\n--- [ERROR FATAL] ---\nError: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Comando desconocido: %s\n\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Println("Uso: collector [--profile perfil] <comando> [opciones]\n\nComandos:")
+	// La columna de nombres se ajusta al más largo (ej: merge-campaigns).
+	width := 0
+	for _, cmd := range commands {
+		width = max(width, len(cmd.name))
+	}
+	for _, cmd := range commands {
+		fmt.Printf("  %-*s %s\n", width, cmd.name, cmd.summary)
+	}
+	fmt.Println("\nUse \"collector help <comando>\" para ver las opciones y ejemplos de cada comando.")
+}
+
+// signalContext devuelve un contexto que se cancela con Ctrl-C o SIGTERM, para
+// que los comandos corten las peticiones en curso en vez de esperar a que terminen.
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
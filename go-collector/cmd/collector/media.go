@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-collector/fetch"
+	"go-collector/media"
+	"go-collector/storage"
+)
+
+// runMedia descarga los adjuntos pendientes (enclosures de RSS, imágenes
+// sociales de GDELT) al archivo crudo. El tipo de cada uno se detecta en sus
+// primeros bytes y lo que no es multimedia se rechaza sin bajarlo completo.
+// De las imágenes se guarda una sola copia: las fotos de agencia que publican
+// decenas de medios, con otro tamaño o compresión, se reconocen por su hash
+// perceptual y reutilizan la ya guardada.
+func runMedia(args []string) error {
+	fs := flag.NewFlagSet("media", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	limit := fs.Int("limit", 0, "descargar como máximo esta cantidad (0: todos los pendientes)")
+	retry := fs.Bool("retry", false, "reintentar también los que fallaron o se rechazaron")
+	maxSize := fs.Int64("max-size", media.DefaultMaxSize>>20, "tamaño máximo de un archivo, en MB")
+	distance := fs.Int("distance", media.DefaultDistance, "bits de diferencia máximos entre hashes perceptuales para reutilizar una imagen ya guardada (-1: solo copias idénticas)")
+	shared := fs.Int("shared", 0, "en vez de descargar, listar las N imágenes usadas por más artículos")
+	parseFlags(fs, args)
+
+	if *shared > 0 {
+		return printSharedImages(*dbPath, *shared)
+	}
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	archive, err := openArchive(cfg)
+	if err != nil {
+		return err
+	}
+	if archive == nil {
+		return fmt.Errorf("los adjuntos se guardan en el archivo crudo: configure storage.archive_dir")
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	pending, err := store.PendingAttachments(*limit, *retry)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Println("No hay adjuntos pendientes.")
+		return nil
+	}
+
+	d := media.NewDownloader()
+	d.MaxSize = *maxSize << 20
+	d.Client.Transport = fetch.Chain(http.DefaultTransport, fetch.DomainRules(cfg.Fetch.Domains), fetch.Backoff(store, cfg.Fetch.Backoff))
+
+	var catalog media.Catalog
+	if *distance >= 0 {
+		hashes, err := store.ImageHashes()
+		if err != nil {
+			return err
+		}
+		for sha, hex := range hashes {
+			if h, err := strconv.ParseUint(hex, 16, 64); err == nil {
+				catalog.Add(h, sha)
+			}
+		}
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	saved, reused, rejected, failed := 0, 0, 0, 0
+	for _, att := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		att.Fetched = time.Now()
+		f, err := d.Get(ctx, att.URL, att.DeclaredType)
+		if ctx.Err() != nil {
+			// Cancelado: el adjunto sigue pendiente.
+			break
+		}
+		switch {
+		case errors.Is(err, media.ErrRejected):
+			rejected++
+			att.Type, att.Size, att.SHA256, att.Err = "", 0, "", err.Error()
+		case err != nil:
+			failed++
+			att.Type, att.Size, att.SHA256, att.Err = "", 0, "", err.Error()
+		default:
+			saved++
+			att.Type, att.Size, att.Err = f.Type, int64(len(f.Data)), ""
+			att.PHash, att.Shared = "", false
+			var hash uint64
+			if *distance >= 0 && strings.HasPrefix(f.Type, "image/") {
+				if hash, err = media.PHash(f.Data); err == nil {
+					att.PHash = fmt.Sprintf("%016x", hash)
+					if sha, d, ok := catalog.Nearest(hash, *distance); ok {
+						reused++
+						att.SHA256, att.Shared = sha, true
+						fmt.Printf("  #%-6d %s\n          misma imagen que %s (%d bits de diferencia)\n", att.ArticleID, att.URL, sha[:12], d)
+						break // sin otra copia en el archivo
+					}
+				}
+			}
+			if att.SHA256, err = archive.Put(f.Data); err != nil {
+				return err
+			}
+			if att.PHash != "" {
+				catalog.Add(hash, att.SHA256)
+			}
+		}
+		if err := store.UpdateAttachment(att); err != nil {
+			return err
+		}
+		if att.Err != "" {
+			fmt.Printf("  #%-6d %s\n          %s\n", att.ArticleID, att.URL, att.Err)
+		} else if att.DeclaredType != "" && att.DeclaredType != att.Type {
+			fmt.Printf("  #%-6d %s\n          declarado %s, es %s\n", att.ArticleID, att.URL, att.DeclaredType, att.Type)
+		}
+	}
+	fmt.Printf("\nAdjuntos: %d guardados (%d con una imagen ya guardada), %d rechazados, %d con error (de %d pendientes)\n",
+		saved, reused, rejected, failed, len(pending))
+	return nil
+}
+
+// printSharedImages lista las imágenes guardadas una vez y usadas por más
+// artículos.
+func printSharedImages(dbPath string, limit int) error {
+	store, err := storage.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	images, err := store.SharedImages(limit)
+	if err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		fmt.Println("No hay imágenes usadas por más de un artículo.")
+		return nil
+	}
+	fmt.Printf("%-64s %9s %5s\n", "sha256", "artículos", "urls")
+	for _, im := range images {
+		fmt.Printf("%-64s %9d %5d\n", im.SHA256, im.Articles, im.URLs)
+	}
+	return nil
+}
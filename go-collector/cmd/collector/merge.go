@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-collector/config"
+	"go-collector/dedup"
+	"go-collector/merge"
+	"go-collector/storage"
+)
+
+// runMergeCampaigns combina el corpus de una o más campañas en el de otra
+// (--into), deduplicando contra lo que ya tiene. Antes de escribir deja una
+// copia del destino junto a él; con --dry-run trabaja sobre una copia
+// temporal y solo muestra el informe.
+func runMergeCampaigns(args []string) error {
+	fs := flag.NewFlagSet("merge-campaigns", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	dbPath := fs.String("db", "", "ruta base de los corpus (por defecto output.db o corpus.db)")
+	into := fs.String("into", "", "campaña de destino (puede ser una de las combinadas o una nueva)")
+	dryRun := fs.Bool("dry-run", false, "mostrar el informe sin modificar el destino")
+	reportPath := fs.String("report", "", "escribir el informe en Markdown en este archivo (por defecto, en pantalla)")
+	parseFlags(fs, args)
+
+	if *into == "" || fs.NArg() == 0 {
+		return fmt.Errorf("uso: collector merge-campaigns --into <campaña> <campaña>...")
+	}
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	if *dbPath == "" {
+		*dbPath = cfg.Output.DB
+	}
+	if *dbPath == "" {
+		*dbPath = "corpus.db"
+	}
+	dd, err := dedup.FromConfig(cfg.Dedup)
+	if err != nil {
+		return err
+	}
+	if dd == nil {
+		// Combinar sin deduplicar duplicaría las historias en común.
+		dd = dedup.New()
+	}
+
+	dstPath := campaignPath(cfg, *dbPath, *into)
+	var sources []string
+	for _, name := range fs.Args() {
+		if name == *into {
+			continue
+		}
+		path := campaignPath(cfg, *dbPath, name)
+		if path == dstPath {
+			return fmt.Errorf("la campaña %s comparte el corpus de %s (%s)", name, *into, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("campaña %s: no se encuentra su corpus %s", name, path)
+		}
+		sources = append(sources, name)
+	}
+	if len(sources) == 0 {
+		return fmt.Errorf("no hay campañas para combinar en %s", *into)
+	}
+
+	now := time.Now()
+	workPath := dstPath
+	if *dryRun {
+		dir, err := os.MkdirTemp("", "collector-merge-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		workPath = filepath.Join(dir, "corpus.db")
+	}
+	if err := snapshotTarget(cfg, dstPath, workPath, now); err != nil {
+		return err
+	}
+
+	dst, err := openStore(workPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+	if err := merge.Index(dd, dst); err != nil {
+		return err
+	}
+
+	rep := &merge.Report{Into: *into, At: now, DryRun: *dryRun}
+	for _, name := range sources {
+		path := campaignPath(cfg, *dbPath, name)
+		src, err := openStore(path, cfg)
+		if err != nil {
+			return fmt.Errorf("campaña %s: %w", name, err)
+		}
+		r, err := merge.Into(dst, src, dd, name, *into)
+		src.Close()
+		if err != nil {
+			return fmt.Errorf("campaña %s: %w (el destino quedó a medias: restaure la copia previa)", name, err)
+		}
+		r.Path = path
+		rep.Sources = append(rep.Sources, r)
+	}
+	rep.Dedup = dd.Stats()
+
+	if !*dryRun {
+		var parts []string
+		for _, r := range rep.Sources {
+			parts = append(parts, fmt.Sprintf("%s: %d nuevos, %d colapsados", r.Campaign, r.Added, len(r.Collapsed)))
+		}
+		if err := audit(dst, storage.AuditMerge, *into, strings.Join(parts, "; ")); err != nil {
+			return err
+		}
+	}
+
+	if *reportPath == "" {
+		if err := rep.WriteMarkdown(os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		f, err := os.Create(*reportPath)
+		if err != nil {
+			return fmt.Errorf("error creando %s: %w", *reportPath, err)
+		}
+		if err := rep.WriteMarkdown(f); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("Informe: %s\n", *reportPath)
+	}
+	if !*dryRun {
+		fmt.Printf("\nCombinado en %s. Los corpus de origen no se tocaron; quite esas campañas de la configuración para que no sigan recolectando por separado.\n", dstPath)
+	}
+	return nil
+}
+
+// campaignPath es el corpus de una campaña: el de su namespace si está en la
+// configuración, o el de su nombre si ya se quitó.
+func campaignPath(cfg *config.Config, dbPath, name string) string {
+	if camp, ok := findCampaign(cfg, name); ok {
+		name = camp.NamespaceOrName()
+	}
+	return namespacePath(dbPath, name)
+}
+
+// snapshotTarget copia el corpus de destino antes de combinar: a work si es
+// una simulación, o junto al destino (<destino>.pre-merge-<fecha>) si no. Un
+// destino que todavía no existe no se copia.
+func snapshotTarget(cfg *config.Config, dstPath, work string, now time.Time) error {
+	if _, err := os.Stat(dstPath); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if work == dstPath {
+		work = strings.TrimSuffix(dstPath, filepath.Ext(dstPath)) + ".pre-merge-" + now.Format("20060102-150405") + filepath.Ext(dstPath)
+		defer fmt.Printf("Copia previa del destino: %s\n", work)
+	}
+	store, err := openStore(dstPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	return store.Snapshot(work)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go-collector/article"
+	"go-collector/network"
+	"go-collector/storage"
+)
+
+// runNetwork exporta la red de retweets, citas y respuestas de X como lista
+// de aristas con fecha. Con --group, --entity o --article se limita a la
+// cascada de los tweets de esa historia; sin ellos exporta toda la red.
+func runNetwork(args []string) error {
+	fs := flag.NewFlagSet("network", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	group := fs.Int64("group", 0, "ID del grupo de ediciones (historia) cuya difusión exportar")
+	entity := fs.String("entity", "", "entidad o texto a buscar (alternativa a --group)")
+	articleID := fs.Int64("article", 0, "ID del artículo (tweet) cuya difusión exportar")
+	since := fs.String("since", "", "referencias hechas desde esta fecha (AAAA-MM-DD)")
+	until := fs.String("until", "", "referencias hechas hasta esta fecha inclusive (AAAA-MM-DD)")
+	nodes := fs.String("nodes", network.Accounts, "nodos de la red: accounts (cuentas) o tweets")
+	format := fs.String("format", "csv", "formato de salida: csv o graphml")
+	out := fs.String("out", "", "archivo de salida (por defecto, salida estándar)")
+	parseFlags(fs, args)
+
+	selected := 0
+	for _, set := range []bool{*group != 0, *entity != "", *articleID != 0} {
+		if set {
+			selected++
+		}
+	}
+	if selected > 1 {
+		return fmt.Errorf("indique solo uno de --group, --entity o --article")
+	}
+	if *format != "csv" && *format != "graphml" {
+		return fmt.Errorf("formato desconocido: %s (use csv o graphml)", *format)
+	}
+
+	var from, to time.Time
+	if *since != "" {
+		t, err := time.Parse("2006-01-02", *since)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --since: %w", err)
+		}
+		from = t
+	}
+	if *until != "" {
+		t, err := time.Parse("2006-01-02", *until)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --until: %w", err)
+		}
+		to = t.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	refs, err := store.References(from, to)
+	if err != nil {
+		return err
+	}
+
+	title := "todo el corpus"
+	if selected == 1 {
+		var articles []*article.Article
+		switch {
+		case *group != 0:
+			articles, err = store.ListByEditionGroup(*group)
+			title = fmt.Sprintf("historia #%d", *group)
+		case *entity != "":
+			articles, err = store.SearchText(*entity, 100000)
+			title = *entity
+		default:
+			var a *article.Article
+			a, err = store.GetByID(*articleID)
+			if a != nil {
+				articles = append(articles, a)
+			}
+			title = fmt.Sprintf("artículo #%d", *articleID)
+		}
+		if err != nil {
+			return err
+		}
+		var seeds []string
+		for _, a := range articles {
+			if id := network.TweetID(a.URL); a.Source == "x" && id != "" {
+				seeds = append(seeds, id)
+			}
+		}
+		if len(seeds) == 0 {
+			return fmt.Errorf("no hay publicaciones de X para %s", title)
+		}
+		refs = network.Cascade(refs, seeds)
+	}
+
+	edges, err := network.Edges(refs, *nodes)
+	if err != nil {
+		return err
+	}
+	if len(edges) == 0 {
+		return fmt.Errorf("no hay retweets, citas ni respuestas para %s", title)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if *format == "graphml" {
+		err = network.WriteGraphML(w, edges)
+	} else {
+		err = network.WriteCSV(w, edges)
+	}
+	if err != nil {
+		return err
+	}
+	if *out != "" {
+		fmt.Printf("Red de difusión de %s: %d aristas -> %s\n", title, len(edges), *out)
+	}
+	return nil
+}
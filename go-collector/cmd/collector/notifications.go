@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"go-collector/notify"
+	"go-collector/storage"
+)
+
+// runNotifications atiende los avisos a webhooks (config notifications) que
+// no llegaron después de sus reintentos: failed los lista y resend los
+// vuelve a enviar, tal como salieron, a la URL configurada hoy.
+func runNotifications(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: collector notifications failed [--all] | resend [--id N]")
+	}
+	switch args[0] {
+	case "failed":
+		return notificationsFailed(args[1:])
+	case "resend":
+		return notificationsResend(args[1:])
+	default:
+		return fmt.Errorf("acción desconocida: %s (use failed o resend)", args[0])
+	}
+}
+
+func notificationsFailed(args []string) error {
+	fs := flag.NewFlagSet("notifications failed", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	all := fs.Bool("all", false, "incluir los ya reenviados")
+	parseFlags(fs, args)
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	failures, err := store.NotificationFailures(*all)
+	if err != nil {
+		return err
+	}
+	if len(failures) == 0 {
+		fmt.Println("No hay notificaciones sin enviar.")
+		return nil
+	}
+	fmt.Println("\n--- NOTIFICACIONES SIN ENVIAR ---")
+	for _, f := range failures {
+		state := "pendiente"
+		if !f.Resent.IsZero() {
+			state = "reenviada " + f.Resent.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Printf("  %4d  %s  %-20s %3d artículos  %s\n", f.ID, f.Failed.Local().Format("2006-01-02 15:04"), f.Notification, f.Articles, state)
+		fmt.Printf("        %s\n", f.Err)
+	}
+	return nil
+}
+
+func notificationsResend(args []string) error {
+	fs := flag.NewFlagSet("notifications resend", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	id := fs.Int64("id", 0, "reenviar solo este mensaje (por defecto, todos los pendientes)")
+	parseFlags(fs, args)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	notifier, err := notify.New(cfg.Notifications, cfg.Relevance)
+	if err != nil {
+		return err
+	}
+	if notifier == nil {
+		return fmt.Errorf("no hay notificaciones en la configuración")
+	}
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	failures, err := store.NotificationFailures(false)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := signalContext()
+	defer cancel()
+	sent, failed := 0, 0
+	for _, f := range failures {
+		if *id != 0 && f.ID != *id {
+			continue
+		}
+		if err := notifier.Resend(ctx, store, f); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			fmt.Printf("  %4d  %s: %v\n", f.ID, f.Notification, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  %4d  %s: enviada (%d artículos)\n", f.ID, f.Notification, f.Articles)
+		sent++
+	}
+	if *id != 0 && sent+failed == 0 {
+		return fmt.Errorf("no hay una notificación pendiente con id %d", *id)
+	}
+	fmt.Printf("Reenviadas: %d, con error: %d\n", sent, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d notificaciones siguen sin enviar", failed)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go-collector/config"
+	"go-collector/report"
+	"go-collector/storage"
+)
+
+// runReport maneja los reportes programados: list, run <nombre> y daemon.
+func runReport(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: collector report list|run <nombre>|daemon")
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("report "+action, flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	workers := fs.Int("workers", 0, "bloques del corpus que se leen a la vez en los reportes summary (0: uno por CPU)")
+	parseFlags(fs, args[1:])
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	live := config.NewLive(cfg)
+	sched := &report.Scheduler{Store: store, Config: live, Scan: storage.ScanOptions{Workers: *workers}}
+
+	switch action {
+	case "list":
+		entries, err := sched.Entries(time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Println("\n--- REPORTES PROGRAMADOS ---")
+		for _, e := range entries {
+			last := "nunca"
+			if !e.LastRun.IsZero() {
+				last = e.LastRun.Local().Format("2006-01-02 15:04")
+			}
+			fmt.Printf("  %-20s %-14s próxima: %s | última: %s\n", e.Report.Name, e.Cron, e.NextRun.Format("2006-01-02 15:04"), last)
+		}
+		return nil
+
+	case "run":
+		if fs.NArg() == 0 {
+			return fmt.Errorf("uso: collector report run [opciones] <nombre>")
+		}
+		sched.Scan = scanOptions(*workers)
+		for _, r := range cfg.Reports {
+			if r.Name == fs.Arg(0) {
+				if err := sched.Run(r, time.Now()); err != nil {
+					return err
+				}
+				fmt.Printf("Reporte %s generado y entregado.\n", r.Name)
+				return nil
+			}
+		}
+		return fmt.Errorf("reporte no configurado: %s", fs.Arg(0))
+
+	case "daemon":
+		stop := make(chan struct{})
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sig
+			close(stop)
+		}()
+		// Horarios, filtros y destinos nuevos se aplican en la siguiente revisión.
+		watchConfig(*cfgPath, live, stop, func() {
+			if err := sched.LogEntries(); err != nil {
+				log.Printf("error calculando horarios: %v", err)
+			}
+		}, store)
+		return sched.Daemon(stop)
+
+	default:
+		return fmt.Errorf("acción desconocida: %s (use list, run o daemon)", action)
+	}
+}
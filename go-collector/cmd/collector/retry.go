@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"go-collector/collect"
+	"go-collector/config"
+	"go-collector/elastic"
+	"go-collector/fetch"
+	"go-collector/kafka"
+	"go-collector/notify"
+	"go-collector/progress"
+	"go-collector/storage"
+)
+
+// runRetryFailures repite solo lo que falló en una ronda (ver collector runs
+// show), con la consulta, los idiomas y el rango que tenía entonces; las
+// credenciales, cupos y demás opciones de cada fuente son las de la
+// configuración actual. Los feeds RSS y las instancias de Mastodon se
+// reintentan uno por uno. El reintento es una ronda nueva vinculada a la
+// original, y lo que vuelva a fallar se puede reintentar desde ella.
+func runRetryFailures(args []string) error {
+	fs := flag.NewFlagSet("retry-failures", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	dbPath := fs.String("db", "", "ruta de la base de datos del corpus (por defecto output.db o corpus.db)")
+	runID := fs.Int64("run", 0, "ronda cuyas fallas se reintentan")
+	progressMode := fs.String("progress", "auto", "avance en stderr: json, bar, none o auto (bar si es una terminal)")
+	parseFlags(fs, args)
+
+	if *runID <= 0 {
+		return fmt.Errorf("uso: collector retry-failures --run <id>")
+	}
+	rep, err := progress.New(*progressMode, os.Stderr)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	if *dbPath == "" {
+		*dbPath = cfg.Output.DB
+	}
+	if *dbPath == "" {
+		*dbPath = "corpus.db"
+	}
+
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	run, err := store.GetRun(*runID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("no existe la ronda %d en %s", *runID, *dbPath)
+	}
+	if err != nil {
+		return err
+	}
+	failures, err := store.RunFailures(run.ID)
+	if err != nil {
+		return err
+	}
+	var pending []storage.RunFailure
+	for _, f := range failures {
+		if f.RetriedBy == 0 {
+			pending = append(pending, f)
+		}
+	}
+	if len(pending) == 0 {
+		fmt.Printf("La ronda %d no tiene fallas pendientes.\n", run.ID)
+		return nil
+	}
+
+	// Las rondas de una campaña se reintentan con las fuentes de la campaña.
+	base := cfg.Sources
+	camp, inCampaign := findCampaign(cfg, run.Campaign)
+	if run.Campaign != "" {
+		if !inCampaign {
+			return fmt.Errorf("la ronda %d es de la campaña %s, que ya no está en la configuración", run.ID, run.Campaign)
+		}
+		if base, err = collect.CampaignSources(cfg, camp); err != nil {
+			return err
+		}
+	}
+
+	mirror, err := openMirror(cfg)
+	if err != nil {
+		return err
+	}
+	if mirror != nil {
+		defer mirror.Close()
+	}
+	index, err := elastic.NewClient(cfg.Output.Elasticsearch, cfg.Storage.Encryption.AuthorSources)
+	if err != nil {
+		return err
+	}
+	producer, err := kafka.NewProducer(cfg.Output.Kafka, cfg.Storage.Encryption.AuthorSources)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	shared := newSharedCaches()
+	c := &collect.Collector{Transport: shared.dns.Transport(), Progress: rep, Extractor: textExtractor(cfg), Renderer: fetch.NewRenderer(cfg.Fetch.Render), Robots: shared.robots, Fetch: cfg.Fetch, Backoff: store, RenderPaths: store}
+	if c.Renderer != nil {
+		defer c.Renderer.Close()
+	}
+	if c.Links, err = linkExpander(cfg, store); err != nil {
+		return err
+	}
+	var results []collect.Result
+	for i := 0; i < len(pending); {
+		// pending viene ordenado por fuente: cada fuente se consulta una vez,
+		// con el fin de rango de la ronda original.
+		j := i
+		for j < len(pending) && pending[j].Source == pending[i].Source {
+			j++
+		}
+		sources := retrySources(base, pending[i:j])
+		fmt.Printf("Reintentando %s (%d fallas de la ronda %d)\n", pending[i].Source, j-i, run.ID)
+		results = append(results, c.Enabled(ctx, &sources, pending[i].Source, pending[i].To)...)
+		i = j
+	}
+	queries := collect.SourceQueries(&base)
+	if inCampaign {
+		queries = collect.CampaignQuery(camp)
+	}
+	rules, err := collect.FilterRelevance(results, cfg.Relevance, queries)
+	if err != nil {
+		return err
+	}
+
+	notifier, err := notify.New(cfg.Notifications, cfg.Relevance)
+	if err != nil {
+		return err
+	}
+	dst := sink{
+		store:      store,
+		mirror:     mirror,
+		index:      index,
+		kafka:      producer,
+		jsonl:      cfg.Output.JSONL,
+		dedup:      cfg.Dedup,
+		out:        os.Stdout,
+		campaign:   run.Campaign,
+		configHash: cfg.Hash(),
+		retryOf:    run.ID,
+		sentiment:  cfg.Sentiment.Enabled,
+		notify:     notifier,
+		rules:      rules,
+	}
+	if inCampaign && dst.jsonl != "" {
+		dst.jsonl = namespacePath(dst.jsonl, camp.NamespaceOrName())
+	}
+	err = saveResults(c, dst, results, time.Now().UTC())
+	shared.print(dst.out, "")
+	return err
+}
+
+// retrySources es base con solo la fuente de failures activada y la consulta,
+// los idiomas y el comienzo del rango de la ronda original; el fin lo fija el
+// now con que se consulta. Si fallaron feeds RSS o instancias de Mastodon
+// puntuales, solo se consultan esos.
+func retrySources(base config.Sources, failures []storage.RunFailure) config.Sources {
+	sources := base
+	f := failures[0]
+	for _, n := range sources.Named() {
+		n.Enabled = n.Name == f.Source
+	}
+	src := sources.Get(f.Source)
+	src.Query, src.Languages = f.Query, f.Languages
+	src.From, src.To, src.Incremental, src.After = "", "", false, f.From
+	var items []string
+	for _, f := range failures {
+		if f.Item == "" {
+			return sources
+		}
+		items = append(items, f.Item)
+	}
+	if f.Source == "mastodon" {
+		// Las fallas puntuales de Mastodon son instancias.
+		var instances []config.Instance
+		for _, inst := range src.Instances {
+			if slices.Contains(items, inst.URL) {
+				instances = append(instances, inst)
+			}
+		}
+		src.Instances = instances
+		return sources
+	}
+	src.Feeds = items
+	return sources
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-collector/config"
+	"go-collector/fetch"
+	"go-collector/revisit"
+	"go-collector/sitemap"
+)
+
+// runRevisit vuelve a descargar los artículos que probablemente cambiaron
+// (historias en desarrollo) y registra si su texto cambió. Cuándo toca cada
+// uno lo decide revisit.Scheduler: la edad de la nota, la frecuencia con que
+// cambian las de su dominio y el lastmod de los sitemaps de la fuente
+// sitemap (sources.sitemap: domains y sitemaps), que se leen antes de
+// planificar. Con --dry-run solo muestra el plan.
+func runRevisit(args []string) error {
+	fs := flag.NewFlagSet("revisit", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	dryRun := fs.Bool("dry-run", false, "mostrar las revisitas pendientes sin descargar")
+	limit := fs.Int("limit", 0, "revisitar como máximo esta cantidad de artículos (0: todos los pendientes)")
+	noSitemaps := fs.Bool("no-sitemaps", false, "planificar sin leer los sitemaps (solo por edad y dominio)")
+	rateFlag := fs.String("rate", "60/1m", `peticiones a los medios por minuto ("60/1m"; 0: sin límite)`)
+	parseFlags(fs, args)
+
+	rate, err := config.ParseRate(*rateFlag)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	transport := fetch.Chain(http.DefaultTransport,
+		fetch.DomainRules(cfg.Fetch.Domains), fetch.RateLimit(rate, 1), fetch.Backoff(store, cfg.Fetch.Backoff))
+	s := revisit.NewScheduler(store)
+	s.Client.Transport = transport
+	s.Extractor = textExtractor(cfg)
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	now := time.Now().UTC()
+
+	var entries []sitemap.Entry
+	if src := cfg.Sources.Sitemap; !*noSitemaps && (len(src.Domains) > 0 || len(src.Sitemaps) > 0) {
+		sm := sitemap.NewClient()
+		sm.HTTP.Transport = transport
+		maps := src.Sitemaps
+		for _, domain := range src.Domains {
+			found, err := sm.Discover(ctx, domain)
+			if err != nil {
+				return err
+			}
+			maps = append(maps, found...)
+		}
+		// Lo anterior a MaxAge no se revisita aunque el sitemap lo cambie.
+		since := now.Add(-s.Policy.MaxAge)
+		for _, u := range maps {
+			found, err := sm.FetchSince(ctx, u, since)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				fmt.Printf("  Sitemap %s: %v\n", u, err)
+				continue
+			}
+			entries = append(entries, found...)
+		}
+		fmt.Printf("Sitemaps: %d leídos, %d entradas\n", len(maps), len(entries))
+	}
+
+	plan, err := s.Plan(now, entries)
+	if err != nil {
+		return err
+	}
+	pending := len(plan)
+	if *limit > 0 && len(plan) > *limit {
+		plan = plan[:*limit]
+	}
+	if len(plan) == 0 {
+		fmt.Println("No hay revisitas pendientes.")
+		return nil
+	}
+	if *dryRun {
+		fmt.Println("\n--- REVISITAS PENDIENTES ---")
+		for _, r := range plan {
+			fmt.Printf("  #%-6d %s  %s\n          %s\n", r.Article.ID, r.Due.Local().Format("2006-01-02 15:04"), r.Article.URL, r.Reason)
+		}
+		fmt.Printf("\nPendientes: %d\n", pending)
+		return nil
+	}
+
+	changed, err := s.Run(ctx, plan)
+	fmt.Printf("\nRevisitados: %d de %d pendientes | Con cambios en el texto: %d\n", len(plan), pending, changed)
+	if err != nil && ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go-collector/article"
+	"go-collector/buildinfo"
+	"go-collector/storage"
+)
+
+// runRuns consulta el historial de rondas de recolección: list muestra las
+// últimas con sus totales, show el detalle por fuente de una de ellas (y con
+// --articles, qué artículos trajo) y article las rondas que trajeron un
+// artículo, con la consulta que lo devolvió.
+func runRuns(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: collector runs list [opciones] | show <id> [--articles] | article <id|url>")
+	}
+	switch args[0] {
+	case "list":
+		return runsList(args[1:])
+	case "show":
+		return runsShow(args[1:])
+	case "article":
+		return runsArticle(args[1:])
+	default:
+		return fmt.Errorf("acción desconocida: %s (use list, show o article)", args[0])
+	}
+}
+
+func runsList(args []string) error {
+	fs := flag.NewFlagSet("runs list", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	since := fs.String("since", "", "desde esta fecha (AAAA-MM-DD)")
+	until := fs.String("until", "", "hasta esta fecha inclusive (AAAA-MM-DD)")
+	status := fs.String("status", "", "solo este estado (ok, partial, failed, running)")
+	campaign := fs.String("campaign", "", "solo esta campaña")
+	limit := fs.Int("limit", 30, "cantidad máxima de rondas")
+	parseFlags(fs, args)
+
+	filter := storage.RunFilter{Status: *status, Campaign: *campaign, Limit: *limit}
+	if *since != "" {
+		t, err := time.Parse("2006-01-02", *since)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --since: %w", err)
+		}
+		filter.Since = t
+	}
+	if *until != "" {
+		t, err := time.Parse("2006-01-02", *until)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --until: %w", err)
+		}
+		filter.Until = t.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	runs, err := store.ListRuns(filter)
+	if err != nil {
+		return err
+	}
+	fmt.Println("\n--- RONDAS DE RECOLECCIÓN ---")
+	for _, r := range runs {
+		name := r.Campaign
+		if name == "" {
+			name = "-"
+		}
+		fmt.Printf("  #%-5d %s  %-8s %-9s %-14s fuentes: %d (%d con error)  traídos: %d  guardados: %d\n",
+			r.ID, r.Started.Local().Format("2006-01-02 15:04"), formatRunDuration(&r.Run), r.Status, name,
+			r.Sources, r.Failed, r.Fetched, r.Stored)
+		if r.RetryOf != 0 {
+			fmt.Printf("         reintento de #%d\n", r.RetryOf)
+		}
+		if r.Skipped > 0 {
+			fmt.Printf("         %d fuentes omitidas (desactivadas)\n", r.Skipped)
+		}
+		if r.Err != "" {
+			fmt.Printf("         %s\n", r.Err)
+		}
+	}
+	if len(runs) == 0 {
+		fmt.Println("  (sin rondas)")
+	}
+	return nil
+}
+
+func runsShow(args []string) error {
+	fs := flag.NewFlagSet("runs show", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	articles := fs.Bool("articles", false, "lista los artículos que trajo la ronda")
+	parseFlags(fs, args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("uso: collector runs show <id> [--articles]")
+	}
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("id de ronda inválido: %s", fs.Arg(0))
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	r, err := store.GetRun(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("no existe la ronda %d", id)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n--- RONDA #%d ---\n", r.ID)
+	fmt.Printf("  Inicio:        %s\n", r.Started.Local().Format("2006-01-02 15:04:05"))
+	if r.Finished.IsZero() {
+		fmt.Println("  Fin:           (sin terminar: el proceso se interrumpió o sigue corriendo)")
+	} else {
+		fmt.Printf("  Fin:           %s (%s)\n", r.Finished.Local().Format("2006-01-02 15:04:05"), formatRunDuration(r))
+	}
+	if r.Campaign != "" {
+		fmt.Printf("  Campaña:       %s\n", r.Campaign)
+	}
+	if r.RetryOf != 0 {
+		fmt.Printf("  Reintento de:  #%d\n", r.RetryOf)
+	}
+	fmt.Printf("  Configuración: %s\n", r.ConfigHash)
+	if v := buildinfo.Recorded(r.Version, r.Commit, "").Label(); v != "" {
+		fmt.Printf("  Recolector:    %s\n", v)
+	}
+	fmt.Printf("  Estado:        %s\n", r.Status)
+	if r.Err != "" {
+		fmt.Printf("  Error:         %s\n", r.Err)
+	}
+	fmt.Println("\n  Fuente        Traídos  Guardados  Duplicados")
+	for _, src := range r.Sources {
+		fmt.Printf("  %-12s %8d %10d %11d", src.Source, src.Fetched, src.Stored, src.Duplicates)
+		if src.Partial {
+			fmt.Print("  (parcial)")
+		}
+		if src.Skipped {
+			fmt.Print("  (omitida)")
+		}
+		fmt.Println()
+		if src.Skipped {
+			fmt.Printf("    %s\n", src.Err)
+		} else if src.Err != "" {
+			fmt.Printf("    error: %s\n", src.Err)
+		}
+		if src.BadDates > 0 {
+			fmt.Printf("    %d artículos con fecha sin interpretar (guardados sin fecha)\n", src.BadDates)
+		}
+	}
+	if len(r.Sources) == 0 {
+		fmt.Println("  (sin fuentes registradas)")
+	}
+
+	failures, err := store.RunFailures(r.ID)
+	if err != nil {
+		return err
+	}
+	if len(failures) > 0 {
+		pending := 0
+		fmt.Println("\n  Fallas:")
+		for _, f := range failures {
+			item := f.Item
+			if item == "" {
+				item = "(consulta completa)"
+			}
+			fmt.Printf("    %-10s %s\n      %s\n", f.Source, item, f.Err)
+			if f.RetriedBy != 0 {
+				fmt.Printf("      reintentada con éxito en la ronda #%d\n", f.RetriedBy)
+			} else {
+				pending++
+			}
+		}
+		if pending > 0 {
+			fmt.Printf("\n  %d pendientes: collector retry-failures --run %d\n", pending, r.ID)
+		}
+	}
+	if !*articles {
+		return nil
+	}
+
+	lineage, err := store.RunArticles(r.ID)
+	if err != nil {
+		return err
+	}
+	fmt.Println("\n  Artículos:")
+	request := ""
+	for _, l := range lineage {
+		if l.Request != request {
+			request = l.Request
+			fmt.Printf("  [%s]\n", request)
+		}
+		fmt.Printf("    #%-6d %s\n            %s\n", l.ArticleID, l.Title, l.URL)
+	}
+	if len(lineage) == 0 {
+		fmt.Println("    (ninguno)")
+	}
+	return nil
+}
+
+func runsArticle(args []string) error {
+	fs := flag.NewFlagSet("runs article", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	parseFlags(fs, args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("uso: collector runs article <id|url>")
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var a *article.Article
+	if id, perr := strconv.ParseInt(fs.Arg(0), 10, 64); perr == nil {
+		a, err = store.GetByID(id)
+	} else {
+		a, err = store.GetByURL(fs.Arg(0))
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("no existe el artículo %s", fs.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+	lineage, err := store.ArticleRuns(a.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n--- LINAJE DEL ARTÍCULO #%d ---\n", a.ID)
+	fmt.Printf("  %s\n  %s\n\n", a.Title, a.URL)
+	for _, l := range lineage {
+		fmt.Printf("  ronda #%-5d %s  %-10s %s\n", l.RunID, l.RunStarted.Local().Format("2006-01-02 15:04"), l.Source, l.URL)
+		if l.Request != "" {
+			fmt.Printf("               consulta: %s\n", l.Request)
+		}
+	}
+	if len(lineage) == 0 {
+		fmt.Println("  (sin rondas registradas: se guardó antes del historial de rondas)")
+	}
+	return nil
+}
+
+// formatRunDuration muestra la duración de una ronda, o "-" si no terminó.
+func formatRunDuration(r *storage.Run) string {
+	if r.Finished.IsZero() {
+		return "-"
+	}
+	return r.Duration().Round(time.Second).String()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"go-collector/article"
+	"go-collector/embed"
+	"go-collector/storage"
+)
+
+func runSearch(args []string) error {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	semantic := fs.Bool("semantic", false, "búsqueda por similitud de embeddings en vez de palabras clave (por significado solo con un modelo: embeddings.provider openai)")
+	k := fs.Int("k", 10, "cantidad de resultados")
+	parseFlags(fs, args)
+
+	text := strings.Join(fs.Args(), " ")
+	if text == "" {
+		return fmt.Errorf("uso: collector search [--semantic] \"consulta\"")
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if !*semantic {
+		articles, err := store.SearchText(text, *k)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Resultados por palabras clave para %q: %d\n", text, len(articles))
+		for i, a := range articles {
+			printResult(i+1, a, "")
+		}
+		return nil
+	}
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	provider, err := embed.NewProvider(cfg.Embeddings)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := signalContext()
+	defer cancel()
+	results, err := embed.Search(ctx, store, provider, text, *k)
+	if err != nil {
+		return err
+	}
+	kind := "semánticos"
+	if !embed.Semantic(provider) {
+		// Con hash, "huelga" no encuentra "paro": se comparan palabras.
+		kind = "por similitud léxica, no semántica"
+	}
+	fmt.Printf("Resultados %s (%s) para %q: %d\n", kind, provider.Name(), text, len(results))
+	for i, r := range results {
+		printResult(i+1, r.Article, fmt.Sprintf("%.3f", r.Score))
+	}
+	return nil
+}
+
+func printResult(rank int, a *article.Article, score string) {
+	if score != "" {
+		score = " [" + score + "]"
+	}
+	fmt.Printf("\n  %d.%s #%d %s\n", rank, score, a.ID, a.Title)
+	fmt.Printf("      Fuente: %s | Publicado: %s\n", a.Source, a.Published.Format("2006-01-02 15:04"))
+	fmt.Printf("      URL: %s\n", a.URL)
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go-collector/collect"
+	"go-collector/config"
+	"go-collector/corpus"
+	"go-collector/storage"
+)
+
+// runSelfcheck corre el pipeline completo sobre las respuestas guardadas
+// (fixtures) en un directorio temporal y verifica lo que quedó en cada
+// destino: el corpus, los JSONL y, si se indica --postgres, la réplica. Antes
+// verifica las reglas de extracción de la configuración. La normalización de
+// cada fuente contra sus golden la cubre TestGolden (go test ./collect). Sirve
+// para comprobar de punta a punta un cambio grande sin credenciales ni cuota.
+func runSelfcheck(args []string) error {
+	fs := flag.NewFlagSet("selfcheck", flag.ExitOnError)
+	fixtures := fs.String("fixtures", "fixtures", "directorio con las respuestas guardadas (<fuente>.json; rss/*.xml)")
+	dsn := fs.String("postgres", os.Getenv("COLLECTOR_POSTGRES_DSN"), "verificar también la réplica en esta base Postgres (ej: la de un contenedor desechable)")
+	keep := fs.Bool("keep", false, "no borrar el directorio temporal al terminar")
+	golden := fs.String("golden", "", "directorio de los archivos golden (por defecto <fixtures>/golden)")
+	update := fs.Bool("update", false, "reescribir los golden de extracción con lo que se extrae ahora")
+	cfgPath := fs.String("config", "", "configuración cuyas reglas de extracción se verifican (por defecto "+defaultConfigPath+" si existe)")
+	parseFlags(fs, args)
+
+	if _, err := os.Stat(*fixtures); err != nil {
+		return fmt.Errorf("directorio de fixtures: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "collector-selfcheck-")
+	if err != nil {
+		return err
+	}
+	if *keep {
+		fmt.Printf("Directorio temporal: %s\n", dir)
+	} else {
+		defer os.RemoveAll(dir)
+	}
+
+	if *golden == "" {
+		*golden = filepath.Join(*fixtures, "golden")
+	}
+
+	problems, err := checkExtractRules(*cfgPath, *fixtures, *golden, *update)
+	if err != nil {
+		return err
+	}
+
+	store, err := storage.Open(filepath.Join(dir, "corpus.db"))
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	dst := sink{store: store, jsonl: filepath.Join(dir, "{source}.jsonl"), out: os.Stdout}
+	if *dsn != "" {
+		if dst.mirror, err = openPostgres(*dsn); err != nil {
+			return err
+		}
+		defer dst.mirror.Close()
+		if err := storage.EnsurePostgresSchema(dst.mirror); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	// Se activan las fuentes que tienen respuesta guardada.
+	sources := &config.Sources{Fixtures: *fixtures}
+	for _, n := range sources.Named() {
+		if _, err := collect.Fixture(*fixtures, n.Name); err == nil && n.Name != "mock" {
+			n.Enabled = true
+		}
+	}
+	c := &collect.Collector{}
+	now := time.Now().UTC()
+	results := c.Enabled(ctx, sources, "", now)
+	if err := saveResults(c, dst, results, now); err != nil {
+		return err
+	}
+
+	// Lo esperado se calcula aparte, releyendo los fixtures: si una URL se
+	// repite, vale la última que se guardó (mismo orden que saveResults).
+	type want struct{ source, title string }
+	expected := make(map[string]want)
+	var urls []string
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		articles, err := collect.Fixture(*fixtures, r.Source)
+		if err != nil {
+			return err
+		}
+		for _, a := range articles {
+			if a.URL == "" {
+				continue
+			}
+			if _, ok := expected[a.URL]; !ok {
+				urls = append(urls, a.URL)
+			}
+			expected[a.URL] = want{a.Source, a.Title}
+		}
+	}
+
+	fmt.Println("\n--- VERIFICACIÓN ---")
+	for _, r := range results {
+		if r.Err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", r.Source, r.Err))
+			continue
+		}
+		lines, err := countLines(filepath.Join(dir, r.Source+".jsonl"))
+		if err != nil {
+			return err
+		}
+		mark := "OK"
+		if lines != len(r.Articles) {
+			mark = "DIFERENTE"
+			problems = append(problems, fmt.Sprintf("%s: %d artículos pero %d líneas en JSONL", r.Source, len(r.Articles), lines))
+		}
+		fmt.Printf("  %-10s JSONL: %4d líneas  %s\n", r.Source, lines, mark)
+	}
+
+	stored, mirrored := 0, 0
+	for _, u := range urls {
+		w := expected[u]
+		a, err := store.GetByURL(u)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("corpus: %s: %v", u, err))
+			continue
+		}
+		if a.Source != w.source || a.Title != w.title {
+			problems = append(problems, fmt.Sprintf("corpus: %s guardado como %s %q, se esperaba %s %q", u, a.Source, a.Title, w.source, w.title))
+			continue
+		}
+		stored++
+		if dst.mirror == nil {
+			continue
+		}
+		var title string
+		err = dst.mirror.QueryRow(`SELECT title FROM articles WHERE url = $1`, u).Scan(&title)
+		if errors.Is(err, sql.ErrNoRows) {
+			problems = append(problems, fmt.Sprintf("postgres: falta %s", u))
+			continue
+		} else if err != nil {
+			return fmt.Errorf("error consultando Postgres: %w", err)
+		}
+		if title != w.title {
+			problems = append(problems, fmt.Sprintf("postgres: %s con título %q, se esperaba %q", u, title, w.title))
+			continue
+		}
+		mirrored++
+	}
+	fmt.Printf("  %-10s %4d de %d URLs\n", "corpus", stored, len(urls))
+	if dst.mirror != nil {
+		fmt.Printf("  %-10s %4d de %d URLs\n", "postgres", mirrored, len(urls))
+	}
+
+	memProblems, err := checkMemory(store, results)
+	if err != nil {
+		return err
+	}
+	mark := "OK"
+	if len(memProblems) > 0 {
+		mark = "DIFERENTE"
+	}
+	fmt.Printf("  %-10s resumen y consultas iguales al corpus  %s\n", "memoria", mark)
+	problems = append(problems, memProblems...)
+
+	if len(problems) > 0 {
+		fmt.Fprintln(os.Stderr, "\nProblemas:")
+		for _, p := range problems {
+			fmt.Fprintf(os.Stderr, "  - %s\n", p)
+		}
+		return fmt.Errorf("la verificación encontró %d problemas", len(problems))
+	}
+	fmt.Println("\nTodo coincide.")
+	return nil
+}
+
+// checkMemory guarda lo recolectado también en un corpus en memoria
+// (corpus.Memory) y verifica que su resumen y sus consultas coincidan con los
+// del corpus SQLite, para que lo que se pruebe contra la memoria valga para
+// la base.
+func checkMemory(store *storage.Store, results []collect.Result) ([]string, error) {
+	mem := corpus.NewMemory()
+	for _, r := range results {
+		for _, a := range r.Articles {
+			if a.URL == "" {
+				continue
+			}
+			c := *a
+			if err := mem.SaveArticle(&c); err != nil {
+				return nil, err
+			}
+		}
+	}
+	var problems []string
+	want, err := store.Summary()
+	if err != nil {
+		return nil, err
+	}
+	got, _ := mem.Summary()
+	if got.Total != want.Total || !maps.Equal(got.BySource, want.BySource) || !maps.Equal(got.ByLanguage, want.ByLanguage) ||
+		!got.First.Equal(want.First) || !got.Last.Equal(want.Last) || got.ExtractionOK != want.ExtractionOK {
+		problems = append(problems, fmt.Sprintf("memoria: resumen %d artículos %v, el corpus %d %v", got.Total, got.BySource, want.Total, want.BySource))
+	}
+	for _, f := range []storage.Filter{{}, {Sources: []string{"rss"}}, {Language: "es"}, {Query: "universidad"}} {
+		want, err := store.ListFiltered(f)
+		if err != nil {
+			return nil, err
+		}
+		got, _ := mem.ListFiltered(f)
+		if len(got) != len(want) {
+			problems = append(problems, fmt.Sprintf("memoria: %+v da %d artículos, el corpus %d", f, len(got), len(want)))
+		}
+	}
+	return problems, nil
+}
+
+// checkExtractRules verifica cada regla de extracción por dominio de la
+// configuración contra su página de prueba (<fixtures>/extract/<dominio>.html)
+// y el golden de lo que se espera extraer. Una regla sin página es un
+// problema; sin configuración no hay nada que verificar.
+func checkExtractRules(cfgPath, fixtures, golden string, update bool) ([]string, error) {
+	if cfgPath == "" {
+		if _, err := os.Stat(defaultConfigPath); err != nil {
+			return nil, nil
+		}
+		cfgPath = defaultConfigPath
+	}
+	cfg, err := loadConfig(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Extract.Domains) == 0 {
+		return nil, nil
+	}
+
+	fmt.Println("\n--- EXTRACCIÓN ---")
+	var problems []string
+	e := textExtractor(cfg)
+	for _, domain := range slices.Sorted(maps.Keys(cfg.Extract.Domains)) {
+		page, err := os.ReadFile(filepath.Join(fixtures, "extract", domain+".html"))
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("  %-24s SIN PÁGINA\n", domain)
+			problems = append(problems, fmt.Sprintf("%s: la regla no tiene página de prueba en %s", domain, filepath.Join(fixtures, "extract", domain+".html")))
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		res, err := e.Extract("https://"+domain+"/", page)
+		if err != nil {
+			return nil, err
+		}
+		if want := strings.TrimPrefix(strings.ToLower(domain), "www."); res.Rule != want {
+			problems = append(problems, fmt.Sprintf("%s: se aplicó la regla %q", domain, res.Rule))
+		}
+		mark, problem, err := checkGolden(filepath.Join(golden, "extract", domain+".json"), res, update)
+		if err != nil {
+			return nil, err
+		}
+		if problem != "" {
+			problems = append(problems, fmt.Sprintf("%s: %s", domain, problem))
+		}
+		fmt.Printf("  %-24s %s\n", domain, mark)
+	}
+	return problems, nil
+}
+
+// checkGolden compara lo obtenido (una extracción) con el archivo golden, o lo reescribe si update es true. Devuelve la marca
+// para el resumen y, si no coinciden, la primera línea distinta.
+func checkGolden(path string, v any, update bool) (mark, problem string, err error) {
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+	got = append(got, '\n')
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return "", "", fmt.Errorf("error creando directorio %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			return "", "", fmt.Errorf("error escribiendo %s: %w", path, err)
+		}
+		return "ACTUALIZADO " + path, "", nil
+	}
+	want, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "SIN GOLDEN", fmt.Sprintf("no existe %s (use --update)", path), nil
+	} else if err != nil {
+		return "", "", err
+	}
+	if bytes.Equal(got, want) {
+		return "OK", "", nil
+	}
+	gotLines, wantLines := strings.Split(string(got), "\n"), strings.Split(string(want), "\n")
+	for i := 0; ; i++ {
+		var g, w string
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if g != w {
+			return "DIFERENTE", fmt.Sprintf("%s:%d: se obtuvo %q, se esperaba %q", path, i+1, strings.TrimSpace(g), strings.TrimSpace(w)), nil
+		}
+	}
+}
+
+// countLines cuenta las líneas de un archivo; 0 si no existe.
+func countLines(path string) (int, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	n := 0
+	for sc.Scan() {
+		n++
+	}
+	return n, sc.Err()
+}
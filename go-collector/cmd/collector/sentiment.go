@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/sentiment"
+	"go-collector/storage"
+)
+
+// runSentiment maneja el tono de la cobertura: score puntúa los artículos
+// guardados (los que la etapa de collect no puntuó) y report muestra el
+// tono promedio por día, semana o mes.
+func runSentiment(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: collector sentiment score [opciones] | report [opciones]")
+	}
+	switch args[0] {
+	case "score":
+		return sentimentScore(args[1:])
+	case "report":
+		return sentimentReport(args[1:])
+	default:
+		return fmt.Errorf("acción desconocida: %s (use score o report)", args[0])
+	}
+}
+
+// scoreSentiment puntúa y guarda el tono del artículo si hay léxico para su
+// idioma.
+func scoreSentiment(store *storage.Store, a *article.Article) error {
+	s, ok := sentiment.Article(a)
+	if !ok {
+		return nil
+	}
+	return store.SetSentiment(storage.Sentiment{
+		ArticleID: a.ID, Score: s.Value, Positive: s.Positive, Negative: s.Negative,
+		Lexicon: s.Lexicon, Scored: time.Now(),
+	})
+}
+
+func sentimentScore(args []string) error {
+	fs := flag.NewFlagSet("sentiment score", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	all := fs.Bool("all", false, "volver a puntuar también los ya puntuados (ej: tras cambiar el léxico)")
+	parseFlags(fs, args)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	articles, err := store.ListActive()
+	if err != nil {
+		return err
+	}
+	done := map[int64]bool{}
+	if !*all {
+		if done, err = store.ArticlesWithSentiment(); err != nil {
+			return err
+		}
+	}
+	scored, unsupported := 0, map[string]int{}
+	for _, a := range articles {
+		if done[a.ID] {
+			continue
+		}
+		if !sentiment.Supported(a.Language) {
+			unsupported[a.Language]++
+			continue
+		}
+		if err := scoreSentiment(store, a); err != nil {
+			return err
+		}
+		scored++
+	}
+	fmt.Printf("Artículos puntuados: %d\n", scored)
+	for _, lang := range slices.Sorted(maps.Keys(unsupported)) {
+		name := lang
+		if name == "" {
+			name = "(desconocido)"
+		}
+		fmt.Printf("  sin léxico para %s: %d\n", name, unsupported[lang])
+	}
+	return nil
+}
+
+func sentimentReport(args []string) error {
+	fs := flag.NewFlagSet("sentiment report", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	by := fs.String("by", sentiment.ByWeek, "período: day, week o month")
+	since := fs.String("since", "", "desde esta fecha (AAAA-MM-DD)")
+	until := fs.String("until", "", "hasta esta fecha inclusive (AAAA-MM-DD)")
+	source := fs.String("source", "", "solo estas fuentes (separadas por comas)")
+	format := fs.String("format", "text", "formato: text, json o csv")
+	parseFlags(fs, args)
+	if *format != "text" && *format != "json" && *format != "csv" {
+		return fmt.Errorf("formato desconocido: %s (use text, json o csv)", *format)
+	}
+
+	var from, to time.Time
+	if *since != "" {
+		t, err := time.Parse("2006-01-02", *since)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --since: %w", err)
+		}
+		from = t
+	}
+	if *until != "" {
+		t, err := time.Parse("2006-01-02", *until)
+		if err != nil {
+			return fmt.Errorf("fecha inválida en --until: %w", err)
+		}
+		to = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	var sources []string
+	if *source != "" {
+		sources = strings.Split(*source, ",")
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	points, err := store.SentimentPoints(from, to, sources)
+	if err != nil {
+		return err
+	}
+	series, err := sentiment.Series(points, *by)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(series)
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"period", "articles", "mean", "positive", "negative", "neutral"})
+		for _, p := range series {
+			w.Write([]string{p.Period, strconv.Itoa(p.Articles), strconv.FormatFloat(p.Mean, 'f', 3, 64),
+				strconv.Itoa(p.Positive), strconv.Itoa(p.Negative), strconv.Itoa(p.Neutral)})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	if len(series) == 0 {
+		fmt.Println("No hay artículos puntuados (use collector sentiment score o sentiment.enabled).")
+		return nil
+	}
+	fmt.Printf("\n%-10s %9s %7s %6s %6s %6s  %s\n", "período", "artículos", "tono", "pos", "neg", "neutro", "")
+	for _, p := range series {
+		fmt.Printf("%-10s %9d %+7.3f %6d %6d %6d  %s\n", p.Period, p.Articles, p.Mean, p.Positive, p.Negative, p.Neutral, bar(p.Mean))
+	}
+	return nil
+}
+
+// bar dibuja el tono promedio como una barra hacia la izquierda (negativo) o
+// la derecha (positivo).
+func bar(v float64) string {
+	const width = 10
+	n := int(v*width + 0.5*sign(v))
+	switch {
+	case n > 0:
+		return strings.Repeat(" ", width) + "|" + strings.Repeat("+", n)
+	case n < 0:
+		return strings.Repeat(" ", width+n) + strings.Repeat("-", -n) + "|"
+	}
+	return strings.Repeat(" ", width) + "|"
+}
+
+func sign(v float64) float64 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"go-collector/api"
+	"go-collector/buildinfo"
+	"go-collector/storage"
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	addr := fs.String("addr", "127.0.0.1:8080", "dirección donde escuchar")
+	token := fs.String("token", os.Getenv("COLLECTOR_API_TOKEN"), "token Bearer exigido a los clientes (opcional)")
+	cfgPath := fs.String("config", defaultConfigPath, "configuración cuyo hash se informa en las respuestas")
+	parseFlags(fs, args)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           (&api.Server{Store: store, Token: *token, Stamp: buildinfo.NewStamp(cfg.Hash())}).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Printf("API del corpus en http://%s (artículos, stats, rondas; Grafana en /grafana/)\n", *addr)
+	return srv.ListenAndServe()
+}
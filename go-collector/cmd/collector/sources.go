@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"go-collector/config"
+	"go-collector/storage"
+)
+
+// runSources desactiva y reactiva fuentes sin tocar la configuración (una API
+// en mantenimiento, una credencial revocada): las rondas omiten las
+// desactivadas y lo dejan registrado. list muestra el estado de cada fuente.
+func runSources(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: collector sources list | disable [--reason texto] [--for lapso] <fuente> | enable <fuente>")
+	}
+	switch args[0] {
+	case "list":
+		return sourcesList(args[1:])
+	case "disable":
+		return sourcesDisable(args[1:])
+	case "enable":
+		return sourcesEnable(args[1:])
+	default:
+		return fmt.Errorf("acción desconocida: %s (use list, disable o enable)", args[0])
+	}
+}
+
+func sourcesList(args []string) error {
+	fs := flag.NewFlagSet("sources list", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	parseFlags(fs, args)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	holds, err := store.SourceHolds(time.Now())
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]storage.SourceHold)
+	for _, h := range holds {
+		byName[h.Source] = h
+	}
+	fmt.Println("\n--- FUENTES ---")
+	for _, n := range cfg.Sources.Named() {
+		state := "activa"
+		if !n.Enabled {
+			state = "apagada en la configuración"
+		}
+		if h, ok := byName[n.Name]; ok {
+			state = h.Describe()
+			if h.Actor != "" {
+				state += ", por " + h.Actor
+			}
+		}
+		fmt.Printf("  %-10s %s\n", n.Name, state)
+	}
+
+	// Los términos registrados en el corpus (config publishers, al abrirlo
+	// para escribir), que son los que aplican las exportaciones.
+	if terms := store.Publishers(); len(terms) > 0 {
+		fmt.Println("\n--- TÉRMINOS DE USO ---")
+		for _, p := range terms {
+			text := map[string]string{
+				storage.TextShare: "texto redistribuible",
+				storage.TextStore: "texto solo para análisis",
+				storage.TextNone:  "texto no se guarda",
+			}[p.Text]
+			if p.Exclude {
+				text += ", no se exporta"
+			}
+			tos := p.TOS
+			if tos == "" {
+				tos = "-"
+			}
+			fmt.Printf("  %-6s %-22s %-10s %s", p.Kind, p.Name, tos, text)
+			if p.License != "" {
+				fmt.Printf(" (%s)", p.License)
+			}
+			fmt.Printf(" desde %s\n", p.Updated.Local().Format("2006-01-02"))
+		}
+	}
+	return nil
+}
+
+func sourcesDisable(args []string) error {
+	fs := flag.NewFlagSet("sources disable", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	reason := fs.String("reason", "", "motivo, que queda en el informe de cada ronda")
+	span := fs.String("for", "", "reactivarla sola pasado este lapso (ej: 6h, 3d); vacío: hasta enable")
+	parseFlags(fs, args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("uso: collector sources disable [--reason texto] [--for lapso] <fuente>")
+	}
+	name := fs.Arg(0)
+	if new(config.Sources).Get(name) == nil {
+		return fmt.Errorf("fuente desconocida: %s", name)
+	}
+
+	h := storage.SourceHold{Source: name, Reason: *reason, Actor: actor(), Since: time.Now()}
+	if *span != "" {
+		d, err := config.ParseSpan(*span)
+		if err != nil {
+			return fmt.Errorf("lapso inválido en --for: %w", err)
+		}
+		h.Until = h.Since.Add(d)
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	if err := store.HoldSource(h); err != nil {
+		return err
+	}
+	if err := audit(store, storage.AuditSourceDisable, name, h.Describe()); err != nil {
+		return err
+	}
+	fmt.Printf("Fuente %s %s\n", name, h.Describe())
+	return nil
+}
+
+func sourcesEnable(args []string) error {
+	fs := flag.NewFlagSet("sources enable", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	parseFlags(fs, args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("uso: collector sources enable <fuente>")
+	}
+	name := fs.Arg(0)
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	err = store.ReleaseSource(name)
+	if errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("la fuente %s no está desactivada", name)
+	}
+	if err != nil {
+		return err
+	}
+	if err := audit(store, storage.AuditSourceEnable, name, ""); err != nil {
+		return err
+	}
+	fmt.Printf("Fuente %s reactivada: la próxima ronda la consulta\n", name)
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-collector/buildinfo"
+	"go-collector/split"
+	"go-collector/storage"
+)
+
+func runSplit(args []string) error {
+	fs := flag.NewFlagSet("split", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	out := fs.String("out", "splits", "directorio de salida")
+	seed := fs.Int64("seed", 42, "semilla de la partición (fija = reproducible)")
+	ratiosFlag := fs.String("ratios", "0.8,0.1,0.1", "proporciones train,dev,test")
+	cfgPath := fs.String("config", defaultConfigPath, "configuración cuyo hash queda en el manifiesto")
+	parseFlags(fs, args)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+
+	ratios, err := parseRatios(*ratiosFlag)
+	if err != nil {
+		return err
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	m, err := split.Export(store, *out, split.Options{
+		Ratios:    ratios,
+		Seed:      *seed,
+		Collector: buildinfo.NewStamp(cfg.Hash()),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Partición escrita en %s (semilla %d)\n", *out, m.Seed)
+	for _, name := range split.Names {
+		fmt.Printf("  %-5s %d artículos\n", name, m.Counts[name])
+	}
+	if m.Excluded+m.Redacted > 0 {
+		fmt.Printf("Términos de uso: %d artículos excluidos, %d sin texto completo (ver config publishers)\n", m.Excluded, m.Redacted)
+	}
+	return nil
+}
+
+func parseRatios(s string) ([3]float64, error) {
+	var r [3]float64
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return r, fmt.Errorf("--ratios debe tener tres valores separados por coma: %q", s)
+	}
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return r, fmt.Errorf("--ratios: valor inválido %q", p)
+		}
+		r[i] = v
+	}
+	return r, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go-collector/entities"
+	"go-collector/stats"
+	"go-collector/storage"
+)
+
+// runStats muestra el estado del corpus de un vistazo; para tablas listas
+// para publicar está describe.
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	format := fs.String("format", "text", "formato: text o json")
+	workers := fs.Int("workers", 0, "bloques del corpus que se leen a la vez (0: uno por CPU)")
+	parseFlags(fs, args)
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("formato desconocido: %s (use text o json)", *format)
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	ctx, stop := signalContext()
+	defer stop()
+	o, err := stats.Summarize(ctx, store, scanOptions(*workers))
+	if err != nil {
+		return err
+	}
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(o)
+	}
+
+	fmt.Println("\n--- CORPUS ---")
+	fmt.Printf("  Artículos:  %d (%d retirados)\n", o.Total, o.Withdrawn)
+	if o.First != nil {
+		fmt.Printf("  Cobertura:  %s – %s\n", o.First.Format("2006-01-02"), o.Last.Format("2006-01-02"))
+	}
+	fmt.Println("\n  Por fuente:")
+	for _, kv := range o.Sources() {
+		fmt.Printf("    %-14s %8d\n", kv.Key, kv.Value)
+	}
+	fmt.Println("\n  Por idioma:")
+	for _, kv := range o.Languages() {
+		lang := kv.Key
+		if lang == "" {
+			lang = "(desconocido)"
+		}
+		fmt.Printf("    %-14s %8d\n", lang, kv.Value)
+	}
+	if len(o.Trending) > 0 {
+		fmt.Printf("\n  En tendencia (%d días hasta %s):\n", int(stats.TrendWindow/(24*time.Hour)), o.Last.Format("2006-01-02"))
+		for _, kind := range entities.Kinds {
+			if len(o.Trending[kind]) == 0 {
+				continue
+			}
+			fmt.Printf("    %s:\n", entityKindNames[kind])
+			for _, t := range o.Trending[kind] {
+				fmt.Printf("      %-30s %5d  (antes %d)\n", t.Name, t.Articles, t.Previous)
+			}
+		}
+	}
+	if r := o.LastRun; r != nil {
+		fmt.Printf("\n  Última ronda: #%d %s  %s  traídos: %d  guardados: %d",
+			r.ID, r.Started.Local().Format("2006-01-02 15:04"), r.Status, r.Fetched, r.Stored)
+		if r.Failed > 0 {
+			fmt.Printf("  (%d fuentes con error)", r.Failed)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// scanOptions arma las opciones de un recorrido del corpus por bloques con
+// workers lectores; el progreso va a stderr, para no mezclarse con la salida,
+// y solo si el corpus ocupa más de un bloque.
+func scanOptions(workers int) storage.ScanOptions {
+	return storage.ScanOptions{
+		Workers: workers,
+		Progress: func(done, total int) {
+			if total < 2 {
+				return
+			}
+			fmt.Fprintf(os.Stderr, "\r  Leídos %d/%d bloques", done, total)
+			if done == total {
+				fmt.Fprintln(os.Stderr)
+			}
+		},
+	}
+}
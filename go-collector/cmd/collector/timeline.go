@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"go-collector/article"
+	"go-collector/buildinfo"
+	"go-collector/storage"
+	"go-collector/timeline"
+)
+
+func runTimeline(args []string) error {
+	fs := flag.NewFlagSet("timeline", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	group := fs.Int64("group", 0, "ID del grupo de ediciones (historia) a ordenar")
+	entity := fs.String("entity", "", "entidad o texto a buscar (alternativa a --group)")
+	format := fs.String("format", "html", "formato de salida: html o json")
+	out := fs.String("out", "", "archivo de salida (por defecto, salida estándar)")
+	cfgPath := fs.String("config", defaultConfigPath, "configuración cuyo hash se anota en la salida")
+	parseFlags(fs, args)
+
+	if (*group == 0) == (*entity == "") {
+		return fmt.Errorf("indique --group o --entity (solo uno)")
+	}
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var (
+		articles []*article.Article
+		title    string
+	)
+	if *group != 0 {
+		articles, err = store.ListByEditionGroup(*group)
+		title = fmt.Sprintf("historia #%d", *group)
+	} else {
+		articles, err = store.SearchText(*entity, 100000)
+		title = *entity
+	}
+	if err != nil {
+		return err
+	}
+
+	t := timeline.Build(title, articles)
+	if len(t.Items) == 0 {
+		return fmt.Errorf("no hay publicaciones con fecha para %s", title)
+	}
+	stamp := buildinfo.NewStamp(cfg.Hash())
+	t.Collector = &stamp
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "html":
+		err = t.WriteHTML(w)
+	case "json":
+		err = t.WriteJSON(w)
+	default:
+		return fmt.Errorf("formato desconocido: %s (use html o json)", *format)
+	}
+	if err != nil {
+		return err
+	}
+	if *out != "" {
+		fmt.Printf("Línea de tiempo de %s: %d publicaciones, %d picos -> %s\n", title, len(t.Items), len(t.Spikes()), *out)
+	}
+	return nil
+}
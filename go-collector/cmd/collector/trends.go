@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go-collector/storage"
+	"go-collector/trends"
+)
+
+// rebuildBatch es cuántos artículos se leen por consulta al recalcular.
+const rebuildBatch = 1000
+
+// runTrends muestra las palabras clave que más suben y más bajan en la
+// última semana frente al último mes. Los puntajes se actualizan al guardar
+// cada artículo en collect; --rebuild los recalcula desde los artículos
+// activos (después de un merge, o para dejar fuera los retirados).
+func runTrends(args []string) error {
+	fs := flag.NewFlagSet("trends", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	limit := fs.Int("limit", 15, "términos por lista")
+	minScore := fs.Float64("min", 3, "apariciones ponderadas mínimas para entrar en una lista")
+	format := fs.String("format", "text", "formato: text o json")
+	rebuild := fs.Bool("rebuild", false, "recalcular los puntajes desde los artículos activos")
+	parseFlags(fs, args)
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("formato desconocido: %s (use text o json)", *format)
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if *rebuild {
+		n, err := rebuildTrends(store)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Tendencias recalculadas con %d artículos.\n", n)
+	}
+
+	terms, err := store.TrendTerms(1)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	rising, falling := trends.Compute(terms, now, trends.Options{MinScore: *minScore, Limit: *limit})
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			At      time.Time      `json:"at"`
+			Rising  []trends.Trend `json:"rising"`
+			Falling []trends.Trend `json:"falling"`
+		}{now, append([]trends.Trend{}, rising...), append([]trends.Trend{}, falling...)})
+	}
+
+	if len(terms) == 0 {
+		fmt.Println("No hay tendencias: se calculan al guardar artículos (o con --rebuild).")
+		return nil
+	}
+	printTrends("SUBEN", rising)
+	printTrends("BAJAN", falling)
+	return nil
+}
+
+// rebuildTrends borra los puntajes y vuelve a sumar los artículos activos
+// en orden de publicación.
+func rebuildTrends(store *storage.Store) (int, error) {
+	if err := store.ResetTrends(); err != nil {
+		return 0, err
+	}
+	n := 0
+	for {
+		// De a páginas: mientras se recorren las filas la conexión está
+		// ocupada y no se puede escribir.
+		page, err := store.ListFiltered(storage.Filter{Limit: rebuildBatch, Offset: n})
+		if err != nil {
+			return n, err
+		}
+		for _, a := range page {
+			if _, err := trends.Ingest(store, a); err != nil {
+				return n, err
+			}
+		}
+		n += len(page)
+		if len(page) < rebuildBatch {
+			return n, nil
+		}
+	}
+}
+
+func printTrends(title string, list []trends.Trend) {
+	fmt.Printf("\n--- %s (última semana frente al último mes, apariciones por día) ---\n", title)
+	if len(list) == 0 {
+		fmt.Println("  (ninguno)")
+		return
+	}
+	fmt.Printf("  %-24s %8s %8s %8s %9s\n", "término", "semana", "mes", "cambio", "artículos")
+	for _, t := range list {
+		fmt.Printf("  %-24s %8.2f %8.2f %7.2fx %9d\n", t.Term, t.Week, t.Baseline, t.Change, t.Articles)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-collector/fetch"
+	"go-collector/storage"
+	"go-collector/unfurl"
+)
+
+// runUnfurl busca enlaces a YouTube y Vimeo en los artículos (incluidos los
+// t.co ya expandidos de X) y guarda título, canal, miniatura y duración de
+// cada video según oEmbed. Los videos ya consultados no se repiten.
+func runUnfurl(args []string) error {
+	fs := flag.NewFlagSet("unfurl", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	limit := fs.Int("limit", 0, "consultar como máximo esta cantidad de videos (0: todos)")
+	retry := fs.Bool("retry", false, "reintentar también los que fallaron")
+	parseFlags(fs, args)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	articles, err := store.ArticlesMentioning("youtu", "vimeo.com")
+	if err != nil {
+		return err
+	}
+	var pending []storage.RelatedMedia
+	for _, a := range articles {
+		known, err := store.RelatedMediaOf(a.ID)
+		if err != nil {
+			return err
+		}
+		done := map[string]bool{}
+		for _, m := range known {
+			done[m.URL] = m.Err == "" || !*retry
+		}
+		for _, l := range unfurl.Find(a.URL, a.Summary, a.Body) {
+			if !done[l.URL] {
+				pending = append(pending, storage.RelatedMedia{ArticleID: a.ID, URL: l.URL, Provider: l.Provider, VideoID: l.ID})
+			}
+		}
+	}
+	if *limit > 0 && len(pending) > *limit {
+		pending = pending[:*limit]
+	}
+	if len(pending) == 0 {
+		fmt.Println("No hay videos pendientes.")
+		return nil
+	}
+
+	c := unfurl.NewClient()
+	c.HTTP.Transport = fetch.Chain(http.DefaultTransport, fetch.DomainRules(cfg.Fetch.Domains), fetch.Backoff(store, cfg.Fetch.Backoff))
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	saved, failed := 0, 0
+	for _, m := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		v, err := c.Unfurl(ctx, unfurl.Link{URL: m.URL, Provider: m.Provider, ID: m.VideoID})
+		if ctx.Err() != nil {
+			// Cancelado: el video sigue pendiente.
+			break
+		}
+		m.Fetched = time.Now()
+		if err != nil {
+			failed++
+			m.Err = err.Error()
+			fmt.Printf("  #%-6d %s\n          %s\n", m.ArticleID, m.URL, m.Err)
+		} else {
+			saved++
+			m.Title, m.Channel, m.ChannelURL, m.Thumbnail, m.Duration = v.Title, v.Channel, v.ChannelURL, v.Thumbnail, v.Duration
+		}
+		if err := store.SaveRelatedMedia(m); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("\nVideos: %d consultados, %d con error (de %d pendientes)\n", saved, failed, len(pending))
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"go-collector/stats"
+	"go-collector/storage"
+)
+
+// runUsage informa, por mes y fuente, las peticiones a cada API, el costo
+// estimado con la tarifa configurada y cuántos artículos rinde cada
+// petición: los números para decidir qué planes pagos conviene mantener.
+func runUsage(args []string) error {
+	fs := flag.NewFlagSet("usage", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración (tarifas en sources.<fuente>.cost)")
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	months := fs.Int("months", 6, "últimos meses, incluido el actual (si no se indica --since)")
+	since := fs.String("since", "", "desde este mes (AAAA-MM)")
+	until := fs.String("until", "", "hasta este mes inclusive (AAAA-MM)")
+	format := fs.String("format", "text", "formato: text, json o csv")
+	parseFlags(fs, args)
+	if *format != "text" && *format != "json" && *format != "csv" {
+		return fmt.Errorf("formato desconocido: %s (use text, json o csv)", *format)
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1-*months, 0)
+	var to time.Time
+	if *since != "" {
+		t, err := time.Parse("2006-01", *since)
+		if err != nil {
+			return fmt.Errorf("mes inválido en --since: %w", err)
+		}
+		from = t
+	}
+	if *until != "" {
+		t, err := time.Parse("2006-01", *until)
+		if err != nil {
+			return fmt.Errorf("mes inválido en --until: %w", err)
+		}
+		to = t.AddDate(0, 1, 0)
+	}
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	usage, err := store.MonthlyUsage(from, to)
+	if err != nil {
+		return err
+	}
+	costs := stats.Costs(usage, &cfg.Sources)
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(costs)
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"month", "source", "rounds", "requests", "fetched", "stored", "articles_per_request", "cost", "currency", "cost_per_stored"})
+		for _, c := range costs {
+			w.Write([]string{c.Month, c.Source, strconv.Itoa(c.Rounds), strconv.Itoa(c.Requests),
+				strconv.Itoa(c.Fetched), strconv.Itoa(c.Stored), strconv.FormatFloat(c.PerRequest, 'f', 2, 64),
+				optionalFloat(c.Cost), c.Currency, optionalFloat(c.PerStored)})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	if len(costs) == 0 {
+		fmt.Println("No hay rondas en el rango.")
+		return nil
+	}
+	fmt.Println("\n--- USO DE LAS APIS POR MES ---")
+	month := ""
+	totals := make(map[string]float64)
+	flush := func() {
+		units := make([]string, 0, len(totals))
+		for unit := range totals {
+			units = append(units, unit)
+		}
+		sort.Strings(units)
+		for _, unit := range units {
+			fmt.Printf("  %-10s %66s\n", "total", fmt.Sprintf("%.2f %s", totals[unit], unit))
+		}
+		clear(totals)
+	}
+	for _, c := range costs {
+		if c.Month != month {
+			flush()
+			month = c.Month
+			fmt.Printf("\n%s\n  %-10s %7s %11s %9s %10s %9s %14s  %s\n", month,
+				"fuente", "rondas", "peticiones", "traídos", "guardados", "art/pet.", "costo", "por guardado")
+		}
+		cost, perStored := "-", "-"
+		if c.Cost != nil {
+			cost = fmt.Sprintf("%.2f %s", *c.Cost, c.Currency)
+			totals[c.Currency] += *c.Cost
+		}
+		if c.PerStored != nil {
+			perStored = fmt.Sprintf("%.4f %s", *c.PerStored, c.Currency)
+		}
+		perRequest := "-"
+		if c.Requests > 0 {
+			perRequest = strconv.FormatFloat(c.PerRequest, 'f', 1, 64)
+		}
+		fmt.Printf("  %-10s %7d %11d %9d %10d %9s %14s  %s", c.Source, c.Rounds, c.Requests, c.Fetched, c.Stored, perRequest, cost, perStored)
+		if c.Included > 0 {
+			fmt.Printf("  (%d%% de %d incluidas)", c.Requests*100/c.Included, c.Included)
+		}
+		fmt.Println()
+	}
+	flush()
+	fmt.Println("\nSin costo: fuentes gratuitas o sin tarifa en sources.<fuente>.cost. Las rondas anteriores a que se contaran las peticiones no las informan.")
+	return nil
+}
+
+// optionalFloat muestra un número opcional para CSV: vacío si no hay.
+func optionalFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 4, 64)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"go-collector/buildinfo"
+)
+
+// runVersion muestra la versión del recolector y el hash de la configuración,
+// la misma marca que llevan las exportaciones, los manifiestos y las
+// respuestas de la API.
+func runVersion(args []string) error {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	format := fs.String("format", "text", "formato: text o json")
+	parseFlags(fs, args)
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	info := buildinfo.Read()
+	stamp := buildinfo.NewStamp(cfg.Hash())
+
+	switch *format {
+	case "text":
+		fmt.Printf("Versión:       %s\n", info.Version)
+		if c := stamp.Revision(); c != "" {
+			fmt.Printf("Commit:        %s\n", c)
+		} else {
+			fmt.Println("Commit:        (desconocido)")
+		}
+		if info.GoVersion != "" {
+			fmt.Printf("Go:            %s\n", info.GoVersion)
+		}
+		fmt.Printf("Configuración: %s\n", stamp.ConfigHash)
+		return nil
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			buildinfo.Stamp
+			GoVersion string `json:"go_version,omitempty"`
+		}{stamp, info.GoVersion})
+	default:
+		return fmt.Errorf("formato desconocido: %s (use text o json)", *format)
+	}
+}
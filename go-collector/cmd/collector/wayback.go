@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-collector/config"
+	"go-collector/fetch"
+	"go-collector/storage"
+	"go-collector/wayback"
+)
+
+// runWayback consulta en el Internet Archive si cada artículo del corpus
+// (incluidos los retirados) tiene una captura y guarda la más reciente. Con
+// --save pide capturar los que no la tienen, una sola vez por artículo. Los
+// artículos ya consultados no se repiten salvo con --retry o --recheck.
+func runWayback(args []string) error {
+	fs := flag.NewFlagSet("wayback", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	limit := fs.Int("limit", 0, "consultar como máximo esta cantidad de artículos (0: todos)")
+	save := fs.Bool("save", false, "pedir con Save Page Now la captura de los que no están archivados")
+	retry := fs.Bool("retry", false, "reintentar también los que fallaron")
+	recheck := fs.Bool("recheck", false, "volver a consultar todos (para registrar capturas nuevas)")
+	rateFlag := fs.String("rate", "15/1m", `peticiones al archivo por minuto ("15/1m"; 0: sin límite)`)
+	parseFlags(fs, args)
+
+	rate, err := config.ParseRate(*rateFlag)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	checks, err := store.ArchiveChecks()
+	if err != nil {
+		return err
+	}
+	articles, err := store.ListFiltered(storage.Filter{IncludeWithdrawn: true})
+	if err != nil {
+		return err
+	}
+	var pending []storage.ArchiveCheck
+	for _, a := range articles {
+		prev, checked := checks[a.ID]
+		switch {
+		case !checked, *recheck,
+			*retry && prev.Err != "",
+			*save && prev.Err == "" && !prev.Archived && prev.SaveRequested.IsZero():
+			pending = append(pending, storage.ArchiveCheck{ArticleID: a.ID, URL: a.URL, SaveRequested: prev.SaveRequested})
+		}
+	}
+	if *limit > 0 && len(pending) > *limit {
+		pending = pending[:*limit]
+	}
+	if len(pending) == 0 {
+		fmt.Println("No hay artículos pendientes.")
+		return nil
+	}
+
+	c := wayback.NewClient()
+	c.HTTP.Transport = fetch.Chain(http.DefaultTransport, fetch.RateLimit(rate, 1), fetch.Backoff(store, cfg.Fetch.Backoff))
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	archived, missing, requested, failed := 0, 0, 0, 0
+	for _, check := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		snap, err := c.Latest(ctx, check.URL)
+		if err == nil && snap == nil && *save && check.SaveRequested.IsZero() {
+			snap, err = c.Save(ctx, check.URL)
+			if err == nil {
+				check.SaveRequested = time.Now()
+				requested++
+			}
+		}
+		if ctx.Err() != nil {
+			// Cancelado: el artículo sigue pendiente.
+			break
+		}
+		check.Checked = time.Now()
+		switch {
+		case err != nil:
+			failed++
+			check.Err = err.Error()
+			fmt.Printf("  #%-6d %s\n          %s\n", check.ArticleID, check.URL, check.Err)
+		case snap != nil:
+			archived++
+			check.Archived, check.SnapshotURL, check.SnapshotAt = true, snap.URL, snap.Timestamp
+		default:
+			missing++
+		}
+		if err := store.SaveArchiveCheck(check); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("\nArtículos: %d archivados, %d sin captura, %d con error (de %d pendientes)\n", archived, missing, failed, len(pending))
+	if requested > 0 {
+		fmt.Printf("Capturas pedidas con Save Page Now: %d (las que siguen en curso aparecen con --recheck)\n", requested)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/fetch"
+	"go-collector/storage"
+	"go-collector/tombstone"
+)
+
+// runWithdrawn revisa si los medios retiraron los artículos del corpus
+// (check) y lista los ya marcados (list). Los retirados no se borran: quedan
+// con la fecha en que se detectó la baja y el motivo.
+func runWithdrawn(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: collector withdrawn check [opciones] | list [opciones]")
+	}
+	switch args[0] {
+	case "check":
+		return withdrawnCheck(args[1:])
+	case "list":
+		return withdrawnList(args[1:])
+	default:
+		return fmt.Errorf("acción desconocida: %s (use check o list)", args[0])
+	}
+}
+
+// withdrawnCheck vuelve a pedir la URL de cada artículo activo y marca como
+// retirados los que responden 404 o 410, redirigen a la portada o muestran
+// un aviso de retiro. Los errores de red y los 5xx no cuentan como retiro.
+func withdrawnCheck(args []string) error {
+	fs := flag.NewFlagSet("withdrawn check", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	cfgPath := fs.String("config", defaultConfigPath, "archivo de configuración")
+	sources := fs.String("source", "", "revisar solo estas fuentes, separadas por coma")
+	since := fs.String("since", "", "revisar solo los publicados desde esta fecha (AAAA-MM-DD)")
+	limit := fs.Int("limit", 0, "revisar como máximo esta cantidad de artículos (0: todos)")
+	rateFlag := fs.String("rate", "60/1m", `peticiones a los medios por minuto ("60/1m"; 0: sin límite)`)
+	parseFlags(fs, args)
+
+	rate, err := config.ParseRate(*rateFlag)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		return err
+	}
+	f := storage.Filter{Limit: *limit}
+	if *sources != "" {
+		f.Sources = strings.Split(*sources, ",")
+	}
+	if *since != "" {
+		if f.From, err = time.Parse("2006-01-02", *since); err != nil {
+			return fmt.Errorf("fecha inválida en --since: %w", err)
+		}
+	}
+	store, err := openStore(*dbPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	articles, err := store.ListFiltered(f)
+	if err != nil {
+		return err
+	}
+	if len(articles) == 0 {
+		fmt.Println("No hay artículos para revisar.")
+		return nil
+	}
+
+	c := tombstone.NewChecker(store)
+	c.Client.Transport = fetch.Chain(http.DefaultTransport,
+		fetch.DomainRules(cfg.Fetch.Domains), fetch.RateLimit(rate, 1), fetch.Backoff(store, cfg.Fetch.Backoff))
+	ctx, cancel := signalContext()
+	defer cancel()
+	results, err := c.CheckArticles(ctx, articles)
+	tombstone.ExplorarRetirados(results)
+	if err != nil && ctx.Err() != nil {
+		fmt.Printf("Cancelado: quedaron %d artículos sin revisar.\n", len(articles)-len(results))
+		return nil
+	}
+	return err
+}
+
+// withdrawnList muestra los artículos retirados, del más reciente al más
+// antiguo según la fecha de detección.
+func withdrawnList(args []string) error {
+	fs := flag.NewFlagSet("withdrawn list", flag.ExitOnError)
+	dbPath := fs.String("db", "corpus.db", "ruta de la base de datos del corpus")
+	sources := fs.String("source", "", "solo estas fuentes, separadas por coma")
+	parseFlags(fs, args)
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	f := storage.Filter{IncludeWithdrawn: true}
+	if *sources != "" {
+		f.Sources = strings.Split(*sources, ",")
+	}
+	all, err := store.ListFiltered(f)
+	if err != nil {
+		return err
+	}
+	var withdrawn []*article.Article
+	for _, a := range all {
+		if a.Withdrawn() {
+			withdrawn = append(withdrawn, a)
+		}
+	}
+	if len(withdrawn) == 0 {
+		fmt.Println("No hay artículos retirados.")
+		return nil
+	}
+	slices.SortStableFunc(withdrawn, func(a, b *article.Article) int {
+		return b.WithdrawnAt.Compare(*a.WithdrawnAt)
+	})
+	fmt.Println("\n--- ARTÍCULOS RETIRADOS ---")
+	for _, a := range withdrawn {
+		fmt.Printf("  #%-6d %s  %-18s %s\n", a.ID, a.WithdrawnAt.Format("2006-01-02"), a.WithdrawnReason, a.URL)
+		if title := strings.TrimSpace(a.Title); title != "" {
+			fmt.Printf("          %s\n", title)
+		}
+	}
+	fmt.Printf("\nTotal: %d\n", len(withdrawn))
+	return nil
+}
@@ -0,0 +1,138 @@
+package collect
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-collector/config"
+	"go-collector/storage"
+)
+
+// api responde las peticiones del Collector según el host, sin red, y cuenta
+// las que recibe cada uno.
+type api struct {
+	mu      sync.Mutex
+	calls   map[string]int
+	respond func(host string, call int) *http.Response
+}
+
+func (a *api) RoundTrip(req *http.Request) (*http.Response, error) {
+	a.mu.Lock()
+	a.calls[req.URL.Host]++
+	n := a.calls[req.URL.Host]
+	a.mu.Unlock()
+	resp := a.respond(req.URL.Host, n)
+	resp.Request = req
+	return resp, nil
+}
+
+func (a *api) count(host string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.calls[host]
+}
+
+func reply(status int, header http.Header, body string) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "application/json")
+	return &http.Response{StatusCode: status, Header: header, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func openBackoff(t *testing.T) *storage.Store {
+	t.Helper()
+	store, err := storage.Open(t.TempDir() + "/corpus.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// Con el enfriamiento por dominio activado, un 429 de X se sigue
+// reintentando en la misma página, como hace el paginador.
+func TestBackoffKeepsXRetry(t *testing.T) {
+	a := &api{calls: map[string]int{}, respond: func(_ string, call int) *http.Response {
+		if call == 1 {
+			reset := http.Header{"X-Rate-Limit-Reset": {strconv.FormatInt(time.Now().Unix(), 10)}}
+			return reply(http.StatusTooManyRequests, reset, `{"title":"Too Many Requests","status":429}`)
+		}
+		return reply(http.StatusOK, nil, `{"data":[{"id":"1","text":"La UdeA abre matrículas","author_id":"7","created_at":"2026-03-10T14:00:00.000Z"}],
+			"includes":{"users":[{"id":"7","username":"udea"}]},"meta":{"result_count":1}}`)
+	}}
+	c := &Collector{Transport: a, Backoff: openBackoff(t)}
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	src := &config.Source{Enabled: true, Query: "UdeA", APIKey: "clave"}
+
+	articles, err := c.Source(context.Background(), "x", src, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 1 {
+		t.Errorf("%d tweets, se esperaba 1", len(articles))
+	}
+	if n := a.count("api.twitter.com"); n != 2 {
+		t.Errorf("%d peticiones a X, se esperaban 2 (el 429 y el reintento)", n)
+	}
+}
+
+// Con el enfriamiento activado, una cuota agotada de NewsAPI sigue pasando a
+// la fuente de reemplazo, también en la ronda siguiente: el 429 de una API no
+// deja al dominio en enfriamiento.
+func TestBackoffKeepsFallback(t *testing.T) {
+	a := &api{calls: map[string]int{}, respond: func(string, int) *http.Response {
+		return reply(http.StatusTooManyRequests, http.Header{"Retry-After": {"3600"}},
+			`{"status":"error","code":"rateLimited","message":"You have made too many requests recently."}`)
+	}}
+	store := openBackoff(t)
+	sources := &config.Sources{
+		NewsAPI: config.Source{Enabled: true, Query: "UdeA", APIKey: "clave", Fallback: config.Chain{"mock"}},
+		Mock:    config.Source{MaxResults: 3},
+	}
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	for round := 1; round <= 2; round++ {
+		c := &Collector{Transport: a, Backoff: store}
+		results := c.Enabled(context.Background(), sources, "newsapi", now)
+		if len(results) != 1 {
+			t.Fatalf("ronda %d: %d resultados, se esperaba 1", round, len(results))
+		}
+		r := results[0]
+		if r.Err != nil || len(r.Articles) != 3 {
+			t.Errorf("ronda %d: %d artículos y error %v, se esperaban los 3 del reemplazo", round, len(r.Articles), r.Err)
+		}
+	}
+	if n := a.count("newsapi.org"); n != 2 {
+		t.Errorf("%d peticiones a NewsAPI, se esperaba una por ronda", n)
+	}
+	if _, err := store.GetBackoff("newsapi.org"); err != storage.ErrNotFound {
+		t.Errorf("NewsAPI quedó con enfriamiento: %v", err)
+	}
+}
+
+// Las páginas de los medios sí respetan el enfriamiento entre rondas.
+func TestBackoffPages(t *testing.T) {
+	a := &api{calls: map[string]int{}, respond: func(string, int) *http.Response {
+		return reply(http.StatusTooManyRequests, nil, "")
+	}}
+	store := openBackoff(t)
+	for range 2 {
+		c := &Collector{Transport: a, Backoff: store}
+		client := &http.Client{}
+		c.usePages(func(next http.RoundTripper) http.RoundTripper { return next }, client) // sin cupo
+		resp, err := client.Get("https://www.eltiempo.com/sitemap.xml")
+		if err == nil {
+			resp.Body.Close()
+		}
+	}
+	if n := a.count("www.eltiempo.com"); n != 1 {
+		t.Errorf("%d peticiones al medio, se esperaba 1 (la segunda cae en el enfriamiento)", n)
+	}
+}
@@ -0,0 +1,70 @@
+package collect
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler/bingnews"
+)
+
+// defaultBingMax es cuántas noticias se piden como máximo por idioma si
+// max_results no lo indica.
+const defaultBingMax = 100
+
+// bingNews recorre la búsqueda de noticias de Bing. Con region, cada idioma
+// es un mercado (es-CO) y una búsqueda aparte; sin ella, una sola búsqueda
+// en el mercado que deduzca Bing. Bing solo filtra por antigüedad (día,
+// semana o mes): el rango exacto se aplica al recibir.
+func (c *Collector) bingNews(ctx context.Context, b *bingnews.Crawler, src *config.Source, languages []string, from, to, now time.Time, pageSize int, fetched func(int)) ([]*article.Article, error) {
+	max := src.MaxResults
+	if max == 0 {
+		max = defaultBingMax
+	}
+	markets := []string{""}
+	if src.Region != "" {
+		markets = markets[:0]
+		for _, lang := range languages {
+			markets = append(markets, strings.ToLower(lang)+"-"+strings.ToUpper(src.Region))
+		}
+	}
+
+	var out []*article.Article
+	seen := make(map[string]bool)
+	for _, mkt := range markets {
+		opts := bingnews.SearchOptions{Market: mkt, Freshness: bingnews.FreshnessFor(from, now)}
+		for opts.Offset < max {
+			opts.Count = min(pageSize, max-opts.Offset)
+			resp, err := b.Search(ctx, src.Query, opts)
+			if err != nil && len(out) == 0 {
+				return nil, err
+			}
+			if err != nil {
+				// Lo ya recibido es válido: se guarda y se avisa del corte.
+				warn(ctx, "Bing se detuvo con %d noticias: %v", len(out), err)
+				return out, nil
+			}
+			kept := 0
+			for _, a := range resp.Normalize() {
+				if seen[a.URL] || !inRange(a.Published, from, to) {
+					continue
+				}
+				seen[a.URL] = true
+				a.Request = "bingnews " + src.Query
+				if mkt != "" {
+					a.Request += " (" + mkt + ")"
+				}
+				out = append(out, a)
+				kept++
+			}
+			fetched(kept)
+			opts.Offset += len(resp.Value)
+			if len(resp.Value) == 0 || opts.Offset >= resp.TotalEstimatedMatches {
+				break
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,66 @@
+package collect
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler/bluesky"
+	"go-collector/query"
+)
+
+// defaultBlueskyMax es cuántas publicaciones se piden como máximo por término
+// si max_results no lo indica.
+const defaultBlueskyMax = 500
+
+// blueskyPosts hace una búsqueda por término de la consulta (searchPosts no
+// admite OR), recorriendo las páginas con el cursor hasta el máximo. Una
+// publicación que coincide con varios términos se conserva una vez. Con un
+// solo idioma configurado se filtra en la búsqueda; con varios, al recibir.
+func (c *Collector) blueskyPosts(ctx context.Context, b *bluesky.Crawler, src *config.Source, from, to time.Time, pageSize int, fetched func(int)) ([]*article.Article, error) {
+	max := src.MaxResults
+	if max == 0 {
+		max = defaultBlueskyMax
+	}
+	opts := bluesky.SearchOptions{Since: from, Until: to}
+	if len(src.Languages) == 1 {
+		opts.Lang = src.Languages[0]
+	}
+
+	var out []*article.Article
+	seen := make(map[string]bool)
+	for _, term := range query.Alternatives(src.Query) {
+		opts.Cursor = ""
+		for n := 0; n < max; {
+			opts.Limit = min(pageSize, max-n)
+			resp, err := b.Search(ctx, term, opts)
+			if errors.Is(err, bluesky.ErrAuth) || (err != nil && len(out) == 0) {
+				return nil, err
+			}
+			if err != nil {
+				// Lo ya recibido es válido: se guarda y se avisa del corte.
+				warn(ctx, "Bluesky se detuvo con %d publicaciones: %v", len(out), err)
+				return out, nil
+			}
+			n += len(resp.Posts)
+			kept := 0
+			for _, a := range resp.Normalize() {
+				if seen[a.URL] || !wantLanguage(a, src.Languages) || !inRange(a.Published, from, to) {
+					continue
+				}
+				seen[a.URL] = true
+				a.Request = "bluesky " + term
+				out = append(out, a)
+				kept++
+			}
+			fetched(kept)
+			if resp.Cursor == "" || len(resp.Posts) == 0 {
+				break
+			}
+			opts.Cursor = resp.Cursor
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,74 @@
+package collect
+
+import (
+	"strings"
+
+	"go-collector/config"
+	"go-collector/feeds"
+	"go-collector/query"
+)
+
+// CampaignSources arma la configuración de fuentes de una campaña: activa las
+// fuentes de la campaña (o las activadas en sources si no indica ninguna),
+// compila su consulta en la sintaxis de cada fuente y aplica su idioma y su
+// rango. RSS usa los feeds de los medios de la campaña, si los tiene. Lo
+// demás (claves, page_size, chunk, max_results) se hereda de sources.
+func CampaignSources(cfg *config.Config, camp config.Campaign) (config.Sources, error) {
+	sources := cfg.Sources
+	selected := make(map[string]bool)
+	for _, name := range camp.Sources {
+		selected[name] = true
+	}
+	for _, n := range sources.Named() {
+		if len(camp.Sources) == 0 {
+			selected[n.Name] = n.Enabled
+		}
+		n.Enabled = selected[n.Name]
+		if !n.Enabled {
+			continue
+		}
+		switch n.Name {
+		case "x":
+			n.Query = query.CompileX(camp.Query)
+		case "gdelt":
+			n.Query = query.CompileGDELT(camp.Query)
+		case "mastodon":
+			n.Query = query.CompileMastodon(camp.Query).String()
+		case "bluesky":
+			n.Query = strings.Join(query.CompileBluesky(camp.Query), " OR ")
+		case "youtube":
+			n.Query = query.CompileYouTube(camp.Query)
+			if len(camp.Region) == 2 {
+				n.Region = camp.Region
+			}
+		case "googlenews", "bingnews":
+			n.Query = query.CompileNews(camp.Query)
+			if len(camp.Region) == 2 {
+				n.Region = camp.Region
+			}
+		case "rss":
+			if len(camp.Outlets) > 0 {
+				editions, err := feeds.NewResolver(cfg.Feeds).ResolveCampaign(camp)
+				if err != nil {
+					return config.Sources{}, err
+				}
+				n.Feeds = nil
+				for _, e := range editions {
+					n.Feeds = append(n.Feeds, e.URL)
+				}
+			}
+		default:
+			n.Query = query.CompileNews(camp.Query)
+		}
+		if camp.Language != "" {
+			n.Languages = []string{camp.Language}
+		}
+		if camp.From != "" {
+			n.From = camp.From
+		}
+		if camp.To != "" {
+			n.To = camp.To
+		}
+	}
+	return sources, nil
+}
@@ -0,0 +1,824 @@
+// Package collect consulta las fuentes activadas en la configuración y
+// normaliza sus resultados al modelo común del corpus.
+package collect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler"
+	"go-collector/crawler/bingnews"
+	"go-collector/crawler/bluesky"
+	"go-collector/crawler/currents"
+	"go-collector/crawler/eventregistry"
+	"go-collector/crawler/gdelt"
+	"go-collector/crawler/guardian"
+	"go-collector/crawler/mediastack"
+	"go-collector/crawler/mock"
+	"go-collector/crawler/newsapi"
+	"go-collector/crawler/rss"
+	"go-collector/crawler/x"
+	"go-collector/crawler/youtube"
+	"go-collector/dates"
+	"go-collector/extract"
+	"go-collector/fetch"
+	"go-collector/progress"
+	"go-collector/robots"
+	"go-collector/shortlink"
+	"go-collector/storage"
+)
+
+// Valores por defecto de cada fuente cuando la configuración no los indica.
+const (
+	defaultPageSize = 50
+	defaultGDELT    = 250
+	// gdeltPause separa las consultas por tramos, como pide GDELT.
+	gdeltPause = 5 * time.Second
+	// feedConcurrency es cuántos feeds RSS se leen a la vez.
+	feedConcurrency = 8
+)
+
+// Result es lo recolectado de una fuente. Partial indica que el watchdog la
+// canceló por no avanzar: Articles es lo recibido hasta entonces. Failed son
+// las partes que fallaron sin que fallara la fuente (feeds RSS) y Attempt la
+// consulta que se hizo, para reintentarla. Feeds es el estado nuevo de los
+// feeds leídos (ver Collector.FeedStates), que se guarda con los artículos.
+// BadDates cuenta los artículos cuya fecha no se pudo interpretar. Held es
+// la desactivación por la que la fuente se omitió sin consultarla (ver
+// Collector.Holds). Requests son las peticiones hechas a la API de cada
+// fuente: la propia y, si se consultaron, las de reemplazo.
+type Result struct {
+	Source   string
+	Articles []*article.Article
+	Err      error
+	Partial  bool
+	Failed   []Failure
+	Attempt  Attempt
+	Feeds    []*storage.FeedState
+	BadDates int
+	Held     *storage.SourceHold
+	Requests map[string]int
+
+	// FixedDates cuenta los artículos cuya fecha no podía ser la de
+	// publicación y se reemplazó por la de descarga; Dates es la calidad de
+	// las fechas de cada feed o consulta (ver checkDates).
+	FixedDates int
+	Dates      []storage.FeedDates
+	// Warnings son los avisos de la consulta que no la hicieron fallar (ej:
+	// la API cortó y se guarda lo recibido); los imprime el comando.
+	Warnings []string
+}
+
+// Failure es una parte de una fuente que falló: un feed RSS o una instancia
+// de Mastodon.
+type Failure struct {
+	Item string
+	Err  error
+}
+
+// Attempt es la consulta efectiva a una fuente en una ronda.
+type Attempt struct {
+	Query     string
+	Languages []string
+	From, To  time.Time // From en cero: el rango por defecto de la fuente
+}
+
+// Collector consulta las fuentes. El valor cero sirve: clientes HTTP por
+// defecto y sin informe de avance. Un mismo Collector puede usarse en varias
+// rondas: los cupos de rate_limit de cada fuente se mantienen entre ellas.
+type Collector struct {
+	// Transport, si no es nil, reemplaza el transporte de los clientes HTTP
+	// de los crawlers (ej: con el cupo de peticiones de una campaña).
+	Transport http.RoundTripper
+	// Progress recibe el inicio de cada fuente, sus páginas y sus errores.
+	Progress progress.Reporter
+	// FeedStates, si no es nil, da el estado de cada feed RSS de la ronda
+	// anterior: los feeds se piden condicionalmente (ETag, Last-Modified) y
+	// solo se procesan las entradas posteriores a las ya vistas.
+	FeedStates FeedStates
+	// Links, si no es nil, expande los enlaces acortados de los artículos
+	// (URL, resumen y texto) antes de entregarlos.
+	Links *shortlink.Expander
+	// Holds, si no es nil, da las fuentes desactivadas temporalmente: se
+	// omiten sin consultarlas y su Result lo indica en Held.
+	Holds Holds
+	// Extractor saca el texto de las páginas que descarga la fuente sitemap,
+	// con las reglas por dominio (config extract.domains); el valor cero usa
+	// solo la extracción genérica.
+	Extractor extract.Extractor
+	// Renderer, si no es nil, vuelve a descargar con el navegador las
+	// páginas de las fuentes sitemap y scrape cuyo texto no alcanza; lo
+	// comparten todos los Collector de un proceso para acotar los
+	// navegadores abiertos.
+	Renderer fetch.Renderer
+	// RenderPaths, si no es nil, recuerda por dominio si las páginas de las
+	// fuentes sitemap y scrape necesitan el navegador (ver fetch.Escalate).
+	RenderPaths fetch.RenderPaths
+	// Fetch es la configuración de descarga de páginas: sus reglas por
+	// dominio (encabezados y cookies) se aplican a todas las peticiones, y con
+	// sus consent_cookies se reintentan las páginas de las fuentes sitemap y
+	// scrape que respondieron con un muro de cookies.
+	Fetch config.Fetch
+	// Backoff, si no es nil, guarda el enfriamiento de los dominios que
+	// respondieron 429 o 403: mientras dure, las páginas de los medios que
+	// descargan sitemap y scrape fallan sin pedirse (ver fetch.Backoff). Las
+	// APIs no lo usan.
+	Backoff fetch.BackoffStore
+	// Robots, si no es nil, da el robots.txt de cada medio: las fuentes
+	// sitemap y scrape no piden las páginas que excluye. Como Renderer, lo
+	// comparten todos los Collector de un proceso.
+	Robots *robots.Cache
+
+	mu      sync.Mutex
+	limits  map[string]fetch.Middleware
+	backoff fetch.Middleware
+	visited *crawler.Visited // el de la última ronda
+}
+
+// FeedStates lee el estado guardado de los feeds RSS (storage.Store).
+type FeedStates interface {
+	GetFeedState(url string) (*storage.FeedState, error)
+}
+
+// Holds lee las desactivaciones vigentes de las fuentes (storage.Store).
+type Holds interface {
+	SourceHold(source string, now time.Time) (*storage.SourceHold, error)
+}
+
+// use arma el transporte del cliente de un crawler: el del Collector, con las
+// reglas de fetch.domains, el cupo de peticiones de la fuente y su conteo
+// (ver counted). Las APIs no pasan por el enfriamiento por dominio: sus
+// cuotas ya tienen su manejo (los reintentos del paginador de X, las fuentes
+// de reemplazo), que un enfriamiento persistido anularía.
+func (c *Collector) use(limit fetch.Middleware, client *http.Client) {
+	client.Transport = c.transport(limit, false)
+}
+
+// usePages es use para los crawlers que descargan páginas de los medios
+// (sitemap, scrape): además, sus dominios en enfriamiento no se piden.
+func (c *Collector) usePages(limit fetch.Middleware, client *http.Client) {
+	client.Transport = c.transport(limit, true)
+}
+
+func (c *Collector) transport(limit fetch.Middleware, pages bool) http.RoundTripper {
+	base := c.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	mws := []fetch.Middleware{fetch.DomainRules(c.Fetch.Domains)}
+	if pages && c.Backoff != nil {
+		// Un solo middleware para todas las fuentes: serializa las
+		// actualizaciones del estado de cada dominio.
+		c.mu.Lock()
+		if c.backoff == nil {
+			c.backoff = fetch.Backoff(c.Backoff, c.Fetch.Backoff)
+		}
+		mws = append(mws, c.backoff)
+		c.mu.Unlock()
+	}
+	return fetch.Chain(base, append(mws, limit, counted)...)
+}
+
+// SetFetch reemplaza, entre rondas, la configuración de descarga (ver
+// Fetch) por una recargada; si cambió su enfriamiento, el de los dominios se
+// arma de nuevo con él en la próxima ronda.
+func (c *Collector) SetFetch(f config.Fetch) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !reflect.DeepEqual(c.Fetch.Backoff, f.Backoff) {
+		c.backoff = nil
+	}
+	c.Fetch = f
+}
+
+// limit devuelve el cupo de la fuente, compartido por todas sus consultas
+// (páginas, tramos y feeds) mientras no cambie su rate_limit.
+func (c *Collector) limit(name string, src *config.Source) (fetch.Middleware, error) {
+	r, err := src.Limit()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := fmt.Sprintf("%s/%d/%s/%d", name, r.N, r.Per, src.RateBurst)
+	if c.limits == nil {
+		c.limits = make(map[string]fetch.Middleware)
+	}
+	if _, ok := c.limits[key]; !ok {
+		c.limits[key] = fetch.RateLimit(r, src.RateBurst)
+	}
+	return c.limits[key], nil
+}
+
+// Source consulta una fuente con su configuración. now fija el fin del rango
+// cuando to no está configurado.
+func (c *Collector) Source(ctx context.Context, name string, src *config.Source, now time.Time) ([]*article.Article, error) {
+	from, to, err := src.Range(now)
+	if err != nil {
+		return nil, fmt.Errorf("fuente %s: %w", name, err)
+	}
+	key, err := src.Key(name)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := c.limit(name, src)
+	if err != nil {
+		return nil, fmt.Errorf("fuente %s: %w", name, err)
+	}
+	ctx = withAPI(ctx, name)
+	pageSize := src.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	languages := src.Languages
+	if len(languages) == 0 {
+		languages = []string{"es", "en"}
+	}
+	var mu sync.Mutex
+	page, total := 0, 0
+	fetched := func(n int) {
+		mu.Lock()
+		defer mu.Unlock()
+		page++
+		total += n
+		touch(ctx)
+		progress.Emit(c.Progress, progress.Event{Type: progress.PageFetched, Source: name, Page: page, Count: n, Total: total})
+	}
+
+	switch name {
+	case "guardian":
+		if from.IsZero() {
+			from = to.AddDate(-1, 0, 0)
+		}
+		g := guardian.NewCrawler(key)
+		c.use(limit, g.Client)
+		resp, err := g.BuscarArticulos(ctx, src.Query, from.Format("2006-01-02"), to.Format("2006-01-02"), pageSize)
+		if err != nil {
+			return nil, err
+		}
+		out := resp.Normalize()
+		fetched(len(out))
+		return out, nil
+
+	case "newsapi":
+		// El plan gratuito de NewsAPI solo cubre los últimos 30 días.
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -30)
+		}
+		n := newsapi.NewCrawler(key)
+		c.use(limit, n.Client)
+		pager := n.Paginar(src.Query, strings.Join(languages, ","), from.Format(time.RFC3339), to.Format(time.RFC3339), pageSize)
+		if src.MaxResults > 0 {
+			pager.MaxResults = src.MaxResults
+		}
+		var out []*article.Article
+		for !pager.Done() {
+			resp, err := pager.NextPage(ctx)
+			if err != nil && ctx.Err() != nil && len(out) > 0 {
+				// Cancelada (ej: por el watchdog): se guarda lo recibido.
+				break
+			}
+			if errors.Is(err, newsapi.ErrPlanLimit) || (errors.Is(err, newsapi.ErrRateLimited) && len(out) > 0) {
+				// Lo ya recibido es válido: se guarda y se avisa del tope.
+				warn(ctx, "NewsAPI cortó en %d de %d resultados: %v", pager.Fetched(), pager.Total(), err)
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			articles := resp.Normalize()
+			fetched(len(articles))
+			out = append(out, articles...)
+		}
+		return out, nil
+
+	case "gdelt":
+		if from.IsZero() {
+			from = to.AddDate(0, -3, 0)
+		}
+		if src.PageSize == 0 {
+			pageSize = defaultGDELT
+		}
+		chunk, err := src.ChunkSpan()
+		if err != nil {
+			return nil, err
+		}
+		g := gdelt.NewCrawler()
+		g.Translate = src.Translate
+		c.use(limit, g.Client)
+		if chunk == 0 {
+			resp, err := g.BuscarArticulosMultiLang(ctx, src.Query, gdelt.Languages(languages), from.Format("20060102150405"), to.Format("20060102150405"), pageSize)
+			if err != nil {
+				return nil, err
+			}
+			out := resp.Normalize()
+			fetched(len(out))
+			return out, nil
+		}
+		resp, err := g.BuscarPorTramos(ctx, src.Query, gdelt.Languages(languages), from, to, gdelt.TramosOptions{
+			Tramo: chunk,
+			Pausa: gdeltPause,
+			Progreso: func(desde, hasta time.Time, recibidos int) {
+				fetched(recibidos)
+			},
+		})
+		if err != nil {
+			if len(resp.Articles) == 0 {
+				return nil, err
+			}
+			warn(ctx, "GDELT se detuvo con %d artículos: %v", len(resp.Articles), err)
+		}
+		return resp.Normalize(), nil
+
+	case "x":
+		from, to = XWindow(from, to, now)
+		query := src.Query
+		if len(src.Languages) == 1 {
+			query = fmt.Sprintf("(%s) lang:%s", query, src.Languages[0])
+		}
+		xc := x.NewCrawler(key)
+		xc.Retweets = src.Retweets
+		c.use(limit, xc.Client)
+		pager := xc.Paginar(query, pageSize, from.Format(time.RFC3339), to.Format(time.RFC3339))
+		if src.MaxResults > 0 {
+			pager.MaxTweets = src.MaxResults
+		}
+		pager.Reintento = func(page int, wait time.Duration) {
+			warn(ctx, "X respondió 429, se reintentó la página %d tras %s", page, wait.Round(time.Second))
+		}
+		var out []*article.Article
+		for !pager.Done() {
+			resp, err := pager.NextPage(ctx)
+			if err != nil {
+				if len(out) == 0 {
+					return nil, err
+				}
+				// Lo ya recibido es válido: se guarda y se avisa del corte.
+				warn(ctx, "X se detuvo con %d tweets: %v", pager.Fetched(), err)
+				break
+			}
+			articles := resp.Normalize()
+			fetched(len(articles))
+			for _, a := range articles {
+				if onTopic(a, src.Topics) {
+					out = append(out, a)
+				}
+			}
+		}
+		return out, nil
+
+	case "rss":
+		// Los feeds se leen en paralelo; el resultado conserva el orden de la
+		// configuración.
+		r := rss.NewCrawler()
+		c.use(limit, r.Client)
+		var pace *pacing
+		if src.Adaptive != nil && c.FeedStates != nil {
+			lo, hi, err := src.Adaptive.Bounds()
+			if err != nil {
+				return nil, fmt.Errorf("adaptive: %w", err)
+			}
+			pace = &pacing{min: lo, max: hi}
+		}
+		perFeed := make([][]*article.Article, len(src.Feeds))
+		errs := make([]error, len(src.Feeds))
+		var g errgroup.Group
+		g.SetLimit(feedConcurrency)
+		for i, u := range src.Feeds {
+			g.Go(func() error {
+				// Un feed que hace fallar al parser no se lleva a los demás.
+				defer recoverSource(name+" "+u, &errs[i])
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					return nil
+				}
+				articles, err := c.pollFeed(ctx, name, r, u, pace)
+				if err != nil {
+					errs[i] = err
+					return nil
+				}
+				for _, a := range articles {
+					if inRange(a.Published, from, to) {
+						a.Request = "rss " + u
+						perFeed[i] = append(perFeed[i], a)
+					}
+				}
+				fetched(len(perFeed[i]))
+				return nil
+			})
+		}
+		g.Wait()
+		var out []*article.Article
+		var failed []string
+		for i := range src.Feeds {
+			out = append(out, perFeed[i]...)
+			var p *PanicError
+			if errors.As(errs[i], &p) {
+				c.emitPanic(name, p)
+			}
+			if errs[i] != nil {
+				failed = append(failed, errs[i].Error())
+				fail(ctx, src.Feeds[i], errs[i])
+			}
+		}
+		if len(failed) == len(src.Feeds) && len(failed) > 0 {
+			return nil, fmt.Errorf("ningún feed respondió: %s", strings.Join(failed, "; "))
+		}
+		return out, nil
+
+	case "bingnews":
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -7)
+		}
+		b := bingnews.NewCrawler(key)
+		c.use(limit, b.Client)
+		return c.bingNews(ctx, b, src, languages, from, to, now, min(pageSize, bingnews.MaxCount), fetched)
+
+	case "eventregistry":
+		if from.IsZero() {
+			from = to.AddDate(0, -1, 0)
+		}
+		e := eventregistry.NewCrawler(key)
+		c.use(limit, e.Client)
+		return c.eventRegistry(ctx, name, e, src, languages, from, to, min(pageSize, eventregistry.MaxCount), fetched)
+
+	case "mediastack":
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -7)
+		}
+		m := mediastack.NewCrawler(key)
+		c.use(limit, m.Client)
+		return c.mediastackNews(ctx, m, src, languages, from, to, min(pageSize, mediastack.MaxLimit), fetched)
+
+	case "currents":
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -7)
+		}
+		cr := currents.NewCrawler(key)
+		c.use(limit, cr.Client)
+		return c.currentsNews(ctx, cr, src, languages, from, to, min(pageSize, currents.MaxPageSize), fetched)
+
+	case "googlenews":
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -7)
+		}
+		return c.googleNews(ctx, name, limit, src, languages, from, to, fetched)
+
+	case "mastodon":
+		return c.mastodonPosts(ctx, name, src, from, to, pageSize, fetched)
+
+	case "bluesky":
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -7)
+		}
+		b := bluesky.NewCrawler(src.Handle, key)
+		c.use(limit, b.Client)
+		return c.blueskyPosts(ctx, b, src, from, to, min(pageSize, bluesky.MaxLimit), fetched)
+
+	case "youtube":
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -7)
+		}
+		y := youtube.NewCrawler(key)
+		c.use(limit, y.Client)
+		return c.youtubeVideos(ctx, y, src, from, to, min(pageSize, youtube.MaxResults), fetched)
+
+	case "oai":
+		if from.IsZero() {
+			from = to.AddDate(0, -1, 0)
+		}
+		return c.oaiRecords(ctx, name, limit, src, from, to, fetched)
+
+	case "commoncrawl":
+		if from.IsZero() {
+			from = to.AddDate(-1, 0, 0)
+		}
+		return c.commonCrawlCaptures(ctx, limit, src, from, to, fetched)
+
+	case "sitemap":
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -7)
+		}
+		return c.sitemapEntries(ctx, limit, src, from, to, fetched)
+
+	case "scrape":
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -7)
+		}
+		return c.scrapeSites(ctx, limit, src, from, to, fetched)
+
+	case "mock":
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -30)
+		}
+		count := src.MaxResults
+		if count == 0 {
+			count = mock.DefaultCount
+		}
+		g := mock.NewGenerator(src.Seed)
+		start := time.Now()
+		out := make([]*article.Article, 0, count)
+		for len(out) < count {
+			n := min(pageSize, count-len(out))
+			if src.Rate > 0 {
+				// El lote sale cuando, a ese ritmo, estaría completo.
+				ready := start.Add(time.Duration(float64(len(out)+n) / src.Rate * float64(time.Second)))
+				select {
+				case <-ctx.Done():
+					return out, ctx.Err()
+				case <-time.After(time.Until(ready)):
+				}
+			} else if err := ctx.Err(); err != nil {
+				return out, err
+			}
+			page := g.Generar(n, from, to)
+			fetched(len(page))
+			out = append(out, page...)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("fuente desconocida: %s", name)
+}
+
+// pollFeed lee un feed. Con FeedStates lo pide condicionalmente y devuelve
+// solo las entradas posteriores a las de la ronda anterior (o sin fecha: el
+// corpus las actualiza por URL); el estado nuevo queda en el registro de la
+// consulta (ver polled). Con pace, un feed al que aún no le toca no se lee:
+// se informa a Progress como FeedDeferred de la fuente name.
+func (c *Collector) pollFeed(ctx context.Context, name string, r *rss.Crawler, u string, pace *pacing) ([]*article.Article, error) {
+	if c.FeedStates == nil {
+		feed, err := r.LeerFeed(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		return rss.Normalize(feed), nil
+	}
+	prev, err := c.FeedStates.GetFeedState(u)
+	if errors.Is(err, storage.ErrNotFound) {
+		prev = nil
+	} else if err != nil {
+		return nil, err
+	}
+	if pace != nil && !pace.due(prev, time.Now()) {
+		progress.Emit(c.Progress, progress.Event{Type: progress.FeedDeferred, Source: name, Message: fmt.Sprintf("feed %s: próxima lectura %s", u, prev.Next.Local().Format("2006-01-02 15:04"))})
+		return nil, nil
+	}
+	feed, state, err := r.Poll(ctx, u, prev)
+	if err != nil {
+		return nil, err
+	}
+	defer polled(ctx, state)
+	var out []*article.Article
+	if feed != nil { // si no, 304: el feed no cambió desde la ronda anterior
+		for _, a := range rss.Normalize(feed) {
+			if a.Published.After(state.Newest) {
+				state.Newest = a.Published
+			}
+			if prev != nil && !prev.Newest.IsZero() && !a.Published.IsZero() && !a.Published.After(prev.Newest) {
+				continue
+			}
+			out = append(out, a)
+		}
+	}
+	if pace != nil {
+		pace.schedule(prev, state, len(out))
+	}
+	return out, nil
+}
+
+// XWindow ajusta el rango a la búsqueda reciente de X: cubre solo 7 días y
+// exige que end_time sea al menos 10 s anterior a la consulta.
+func XWindow(from, to, now time.Time) (time.Time, time.Time) {
+	if limit := now.Add(-time.Minute); to.After(limit) {
+		to = limit
+	}
+	if earliest := to.Add(-7*24*time.Hour + time.Minute); from.IsZero() || from.Before(earliest) {
+		from = earliest
+	}
+	return from, to
+}
+
+func inRange(t, from, to time.Time) bool {
+	if t.IsZero() || dates.Check(t, time.Now()) != "" {
+		// Sin fecha, o con una que no puede ser la de publicación y que
+		// checkDates reemplaza: no hay con qué descartarlo.
+		return true
+	}
+	return (from.IsZero() || !t.Before(from)) && !t.After(to)
+}
+
+// Stream consulta en paralelo todas las fuentes activadas (o solo only, si
+// no está vacío) y envía cada resultado al canal apenas termina; el canal se
+// cierra cuando terminan todas. El error de una fuente no detiene las demás:
+// queda en su Result. Si ctx se cancela, las consultas en curso se cortan y
+// las fuentes que faltan no se consultan. Con sources.fixtures configurado se
+// leen las respuestas de prueba.
+func (c *Collector) Stream(ctx context.Context, sources *config.Sources, only string, now time.Time) <-chan Result {
+	ctx = c.round(ctx)
+	out := make(chan Result)
+	var g errgroup.Group
+	for _, n := range sources.Named() {
+		if only != "" {
+			if n.Name != only {
+				continue
+			}
+		} else if !n.Enabled {
+			continue
+		}
+		g.Go(func() error {
+			out <- c.run(ctx, sources, n, now)
+			return nil
+		})
+	}
+	go func() {
+		g.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Enabled es Stream esperando a todas las fuentes; los resultados quedan en
+// el orden fijo de las fuentes.
+func (c *Collector) Enabled(ctx context.Context, sources *config.Sources, only string, now time.Time) []Result {
+	byName := make(map[string]Result)
+	for r := range c.Stream(ctx, sources, only, now) {
+		byName[r.Source] = r
+	}
+	var results []Result
+	for _, n := range sources.Named() {
+		if r, ok := byName[n.Name]; ok {
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// run consulta una fuente informando su avance.
+func (c *Collector) run(ctx context.Context, sources *config.Sources, n config.NamedSource, now time.Time) Result {
+	if err := ctx.Err(); err != nil {
+		return Result{Source: n.Name, Err: err}
+	}
+	if h := c.held(n.Name, now); h != nil {
+		progress.Emit(c.Progress, progress.Event{Type: progress.SourceSkipped, Source: n.Name, Message: h.Describe()})
+		return Result{Source: n.Name, Held: h}
+	}
+	progress.Emit(c.Progress, progress.Event{Type: progress.SourceStarted, Source: n.Name})
+	attempt := Attempt{Query: n.Query, Languages: n.Languages, To: now}
+	if from, to, err := n.Range(now); err == nil {
+		attempt.From, attempt.To = from, to
+	}
+	stall, err := n.StallSpan()
+	if err != nil {
+		return Result{Source: n.Name, Err: fmt.Errorf("fuente %s: %w", n.Name, err), Attempt: attempt}
+	}
+	wctx, stop := watch(ctx, stall)
+	wctx, rec := recording(wctx)
+	articles, err := c.fetch(wctx, sources, n, now)
+	stalled := errors.Is(context.Cause(wctx), ErrStalled)
+	stop()
+	if stalled {
+		// La fuente se da por terminada con lo que alcanzó a traer.
+		progress.Emit(c.Progress, progress.Event{Type: progress.Error, Source: n.Name, Message: fmt.Sprintf("sin páginas nuevas en %s: se cancela", stall)})
+		if len(articles) > 0 {
+			r := rec.result(Result{Source: n.Name, Articles: articles, Partial: true, Attempt: attempt})
+			c.expandLinks(ctx, &r)
+			c.countBadDates(&r)
+			c.checkDates(&r, time.Now().UTC())
+			return r
+		}
+		return Result{Source: n.Name, Err: fmt.Errorf("%w: sin páginas nuevas en %s", ErrStalled, stall), Partial: true, Attempt: attempt, Requests: rec.result(Result{}).Requests}
+	}
+	var p *PanicError
+	if errors.As(err, &p) {
+		c.emitPanic(n.Name, p)
+	} else if err != nil {
+		progress.Emit(c.Progress, progress.Event{Type: progress.Error, Source: n.Name, Message: err.Error()})
+	}
+	r := rec.result(Result{Source: n.Name, Articles: articles, Err: err, Attempt: attempt})
+	c.expandLinks(ctx, &r)
+	c.countBadDates(&r)
+	c.checkDates(&r, time.Now().UTC())
+	return r
+}
+
+// held devuelve la desactivación vigente de la fuente, o nil. Si no se puede
+// leer, se avisa y la fuente se consulta: una falla de la base no debe dejar
+// la ronda sin fuentes.
+func (c *Collector) held(name string, now time.Time) *storage.SourceHold {
+	if c.Holds == nil {
+		return nil
+	}
+	h, err := c.Holds.SourceHold(name, now)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			progress.Emit(c.Progress, progress.Event{Type: progress.Error, Source: name, Message: err.Error()})
+		}
+		return nil
+	}
+	return h
+}
+
+// countBadDates cuenta los artículos sin fecha interpretable y avisa con un
+// ejemplo, para reconocer un formato nuevo de la fuente.
+func (c *Collector) countBadDates(r *Result) {
+	example := ""
+	for _, a := range r.Articles {
+		if a.RawPublished != "" {
+			r.BadDates++
+			if example == "" {
+				example = a.RawPublished
+			}
+		}
+	}
+	if r.BadDates > 0 {
+		progress.Emit(c.Progress, progress.Event{Type: progress.Error, Source: r.Source,
+			Message: fmt.Sprintf("%d fechas sin interpretar (ej: %q): se guardan sin fecha", r.BadDates, example)})
+	}
+}
+
+// checkDates reemplaza por fetched, el momento de la descarga, las fechas
+// de publicación que no pueden serlo (futuras o en cero, ver dates.Check),
+// para que no desplacen las series de tiempo; el artículo queda marcado con
+// la fecha original. Arma además la calidad de las fechas por feed (la
+// consulta de cada artículo) y avisa con un ejemplo.
+func (c *Collector) checkDates(r *Result, fetched time.Time) {
+	byFeed := make(map[string]int)
+	example := ""
+	for _, a := range r.Articles {
+		i, ok := byFeed[a.Request]
+		if !ok {
+			i = len(r.Dates)
+			byFeed[a.Request] = i
+			r.Dates = append(r.Dates, storage.FeedDates{Source: r.Source, Feed: a.Request})
+		}
+		f := &r.Dates[i]
+		f.Items++
+		if a.RawPublished != "" {
+			f.Unparsed++
+			if f.Example == "" {
+				f.Example = a.RawPublished
+			}
+			continue
+		}
+		issue := dates.Check(a.Published, fetched)
+		if issue == "" {
+			continue
+		}
+		switch issue {
+		case dates.IssueFuture:
+			f.Future++
+		case dates.IssueBogus:
+			f.Bogus++
+		}
+		raw := a.Published.Format(time.RFC3339)
+		if f.Example == "" {
+			f.Example = raw
+		}
+		if example == "" {
+			example = raw
+		}
+		a.DateIssue, a.OriginalPublished, a.Published = issue, a.Published, fetched
+		r.FixedDates++
+	}
+	if r.FixedDates > 0 {
+		progress.Emit(c.Progress, progress.Event{Type: progress.Error, Source: r.Source,
+			Message: fmt.Sprintf("%d fechas futuras o imposibles (ej: %s): se guardan con la fecha de descarga", r.FixedDates, example)})
+	}
+}
+
+// fetch trae los artículos de la fuente, cada uno con la consulta que lo
+// trajo (ver Request); un pánico del crawler vuelve como *PanicError en vez
+// de terminar el proceso.
+func (c *Collector) fetch(ctx context.Context, sources *config.Sources, n config.NamedSource, now time.Time) (articles []*article.Article, err error) {
+	defer recoverSource(n.Name, &err)
+	if sources.Fixtures != "" && n.Name != "mock" {
+		articles, err = Fixture(sources.Fixtures, n.Name)
+		if err == nil {
+			tagRequest(articles, "fixture "+n.Name)
+			detectLanguage(articles)
+			progress.Emit(c.Progress, progress.Event{Type: progress.PageFetched, Source: n.Name, Page: 1, Count: len(articles), Total: len(articles)})
+		}
+		return articles, err
+	}
+	articles, err = c.Source(ctx, n.Name, n.Source, now)
+	tagRequest(articles, Request(n.Name, n.Source, now))
+	if len(n.Fallback) > 0 && needsFallback(n.FallbackOn, articles, err) {
+		cause := err
+		if cause == nil {
+			cause = errNoResults
+		}
+		articles, err = c.fallback(ctx, sources, n, now, cause)
+	}
+	detectLanguage(articles)
+	return articles, err
+}
@@ -0,0 +1,108 @@
+package collect
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler/commoncrawl"
+	"go-collector/fetch"
+	"go-collector/query"
+)
+
+// defaultCommonCrawlMax es cuántas capturas se leen como máximo si
+// max_results no lo indica: cada una es un pedido al archivo.
+const defaultCommonCrawlMax = 200
+
+// commonCrawlCaptures busca en el índice de Common Crawl las capturas de los
+// dominios de la fuente hechas en el rango, del rastreo más reciente al más
+// antiguo, y lee de cada una su registro WARC para tener el título y la
+// fecha de la página. El índice no busca por contenido: una captura entra si
+// su URL lleva alguno de los términos de la consulta escrito como en las
+// direcciones de las notas (universidad-de-antioquia); sin consulta, todas.
+// De una URL capturada en varios rastreos queda la más reciente. Una captura
+// que no se puede leer se omite y queda en Result.Failed.
+func (c *Collector) commonCrawlCaptures(ctx context.Context, limit fetch.Middleware, src *config.Source, from, to time.Time, fetched func(int)) ([]*article.Article, error) {
+	max := src.MaxResults
+	if max == 0 {
+		max = defaultCommonCrawlMax
+	}
+	var slugs []string
+	for _, term := range query.Alternatives(src.Query) {
+		if s := commoncrawl.Slug(strings.Trim(term, `"`)); s != "" {
+			slugs = append(slugs, s)
+		}
+	}
+
+	cc := commoncrawl.NewCrawler()
+	c.use(limit, cc.Client)
+	all, err := cc.Collections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	colls := commoncrawl.Between(all, from, to)
+	if len(colls) == 0 {
+		return nil, fmt.Errorf("ningún rastreo de Common Crawl cubre el rango %s a %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+
+	var out []*article.Article
+	seen := make(map[string]bool)
+	opts := commoncrawl.SearchOptions{From: from, To: to}
+	for _, coll := range colls {
+		for _, domain := range src.Domains {
+			pages, err := cc.Pages(ctx, coll, domain, opts)
+			if err != nil {
+				return out, err
+			}
+			for opts.Page = 0; opts.Page < pages; opts.Page++ {
+				records, err := cc.Search(ctx, coll, domain, opts)
+				if err != nil {
+					return out, err
+				}
+				for _, r := range records {
+					if seen[r.URL] || !inURL(r.URL, slugs) {
+						continue
+					}
+					seen[r.URL] = true
+					page, err := cc.Fetch(ctx, r)
+					if err != nil {
+						if ctx.Err() != nil {
+							return out, ctx.Err()
+						}
+						fail(ctx, r.URL, err)
+						continue
+					}
+					fetched(1)
+					a := r.Normalize(page)
+					if !wantLanguage(a, src.Languages) {
+						continue
+					}
+					a.Request = "commoncrawl " + coll.ID + " " + domain
+					out = append(out, a)
+					if len(out) >= max {
+						return out, nil
+					}
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// inURL indica si la URL lleva alguno de los términos (ya en forma de slug);
+// sin términos, todas sirven.
+func inURL(u string, slugs []string) bool {
+	if len(slugs) == 0 {
+		return true
+	}
+	u = strings.ToLower(u)
+	for _, s := range slugs {
+		if strings.Contains(u, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package collect
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler/currents"
+	"go-collector/query"
+)
+
+// defaultCurrentsMax es cuántas noticias se piden como máximo por término e
+// idioma si max_results no lo indica.
+const defaultCurrentsMax = 200
+
+// currentsNews hace una búsqueda por término de la consulta e idioma
+// (Currents no admite OR ni varios idiomas a la vez), recorriendo las
+// páginas hasta el máximo. Una noticia que coincide con varias búsquedas se
+// conserva una vez.
+func (c *Collector) currentsNews(ctx context.Context, cr *currents.Crawler, src *config.Source, languages []string, from, to time.Time, pageSize int, fetched func(int)) ([]*article.Article, error) {
+	max := src.MaxResults
+	if max == 0 {
+		max = defaultCurrentsMax
+	}
+	var out []*article.Article
+	seen := make(map[string]bool)
+	for _, term := range query.Alternatives(src.Query) {
+		term = strings.Trim(term, `"`)
+		for _, lang := range languages {
+			opts := currents.SearchOptions{Language: lang, From: from, To: to, Page: 1}
+			for n := 0; n < max; opts.Page++ {
+				opts.Count = min(pageSize, max-n)
+				resp, err := cr.Search(ctx, term, opts)
+				if err != nil && len(out) == 0 {
+					return nil, err
+				}
+				if err != nil {
+					// Lo ya recibido es válido: se guarda y se avisa del corte.
+					warn(ctx, "Currents se detuvo con %d noticias: %v", len(out), err)
+					return out, nil
+				}
+				kept := 0
+				for _, a := range resp.Normalize() {
+					if seen[a.URL] || !inRange(a.Published, from, to) {
+						continue
+					}
+					seen[a.URL] = true
+					a.Request = "currents " + term + " (" + lang + ")"
+					out = append(out, a)
+					kept++
+				}
+				fetched(kept)
+				n += len(resp.News)
+				// Currents no informa el total: una página incompleta es la última.
+				if len(resp.News) < opts.Count {
+					break
+				}
+			}
+		}
+	}
+	return out, nil
+}
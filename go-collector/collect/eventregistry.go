@@ -0,0 +1,75 @@
+package collect
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler/eventregistry"
+	"go-collector/progress"
+	"go-collector/query"
+)
+
+// defaultEventRegistryMax es cuántos artículos se piden como máximo si
+// max_results no lo indica: cada página consume un token del plan.
+const defaultEventRegistryMax = 200
+
+// eventRegistry recorre la búsqueda de artículos de Event Registry y pide
+// después los datos de sus eventos. La consulta admite " OR " entre términos
+// (cada uno palabra o frase). Si los eventos no llegan, los artículos se
+// guardan igual, con la URI de su evento.
+func (c *Collector) eventRegistry(ctx context.Context, name string, e *eventregistry.Crawler, src *config.Source, languages []string, from, to time.Time, pageSize int, fetched func(int)) ([]*article.Article, error) {
+	max := src.MaxResults
+	if max == 0 {
+		max = defaultEventRegistryMax
+	}
+	var keywords []string
+	for _, term := range query.Alternatives(src.Query) {
+		keywords = append(keywords, strings.Trim(term, `"`))
+	}
+
+	resp := &eventregistry.Response{}
+	opts := eventregistry.SearchOptions{Languages: languages, From: from, To: to, Page: 1}
+	for n := 0; n < max; opts.Page++ {
+		opts.Count = min(pageSize, max-n)
+		page, err := e.Search(ctx, keywords, opts)
+		if err != nil && n == 0 {
+			return nil, err
+		}
+		if err != nil {
+			// Lo ya recibido es válido: se guarda y se avisa del corte.
+			warn(ctx, "Event Registry se detuvo con %d artículos: %v", n, err)
+			break
+		}
+		resp.Articles.Results = append(resp.Articles.Results, page.Articles.Results...)
+		fetched(len(page.Articles.Results))
+		n += len(page.Articles.Results)
+		if len(page.Articles.Results) == 0 || opts.Page >= page.Articles.Pages {
+			break
+		}
+	}
+
+	uris := resp.EventURIs()
+	for i := 0; i < len(uris) && ctx.Err() == nil; i += eventregistry.MaxEvents {
+		events, err := e.Events(ctx, uris[i:min(i+eventregistry.MaxEvents, len(uris))])
+		if err != nil {
+			progress.Emit(c.Progress, progress.Event{Type: progress.Error, Source: name,
+				Message: fmt.Sprintf("datos de %d eventos sin recibir: quedan solo sus URIs: %v", len(uris)-i, err)})
+			break
+		}
+		resp.Events.Results = append(resp.Events.Results, events.Events.Results...)
+	}
+
+	// Event Registry filtra por día: el rango exacto se aplica acá.
+	var out []*article.Article
+	for _, a := range resp.Normalize() {
+		if inRange(a.Published, from, to) {
+			a.Request = "eventregistry " + strings.Join(keywords, " OR ")
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
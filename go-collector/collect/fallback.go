@@ -0,0 +1,83 @@
+package collect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler/bingnews"
+	"go-collector/crawler/currents"
+	"go-collector/crawler/eventregistry"
+	"go-collector/crawler/mediastack"
+	"go-collector/crawler/newsapi"
+	"go-collector/crawler/youtube"
+	"go-collector/progress"
+)
+
+// errNoResults es la causa del reemplazo de una fuente que respondió sin
+// resultados (fallback_on: empty).
+var errNoResults = errors.New("sin resultados")
+
+// quotaExhausted indica que la fuente falló por agotar su cuota, el caso en
+// que se consulta su fuente de reemplazo (fallback).
+func quotaExhausted(err error) bool {
+	return errors.Is(err, newsapi.ErrRateLimited) || errors.Is(err, youtube.ErrQuota) || errors.Is(err, bingnews.ErrQuota) ||
+		errors.Is(err, eventregistry.ErrQuota) || errors.Is(err, mediastack.ErrQuota) || errors.Is(err, currents.ErrQuota)
+}
+
+// needsFallback indica si, según on (sources.<fuente>.fallback_on), lo que
+// devolvió una fuente obliga a pasar a su reemplazo.
+func needsFallback(on string, articles []*article.Article, err error) bool {
+	if len(articles) > 0 {
+		return false
+	}
+	switch on {
+	case config.FallbackOnEmpty:
+		return true
+	case config.FallbackOnError:
+		return err != nil
+	default:
+		return err != nil && quotaExhausted(err)
+	}
+}
+
+// fallback recorre la cadena de reemplazos de n hasta que uno sirva: cada
+// uno se consulta con la consulta, los idiomas y el rango de n, y aporta su
+// credencial, su región y sus cupos. cause es lo que le pasó a n, que se
+// informa junto con lo de cada reemplazo que tampoco sirvió. Si ninguno
+// falló (solo no trajeron nada), no hay error.
+func (c *Collector) fallback(ctx context.Context, sources *config.Sources, n config.NamedSource, now time.Time, cause error) ([]*article.Article, error) {
+	failed := !errors.Is(cause, errNoResults)
+	for _, name := range n.Fallback {
+		base := sources.Get(name)
+		if base == nil || ctx.Err() != nil {
+			break
+		}
+		fb := *base
+		fb.Query, fb.Languages = n.Query, n.Languages
+		fb.From, fb.To, fb.After = n.From, n.To, n.After
+		progress.Emit(c.Progress, progress.Event{Type: progress.Error, Source: n.Name,
+			Message: fmt.Sprintf("%v: se consulta %s en su lugar", cause, name)})
+		articles, err := c.Source(ctx, name, &fb, now)
+		tagRequest(articles, Request(name, &fb, now))
+		if !needsFallback(n.FallbackOn, articles, err) {
+			if err != nil {
+				return articles, fmt.Errorf("%v; reemplazo %s: %w", cause, name, err)
+			}
+			return articles, nil
+		}
+		if err == nil {
+			err = errNoResults
+		} else {
+			failed = true
+		}
+		cause = fmt.Errorf("%v; reemplazo %s: %w", cause, name, err)
+	}
+	if !failed {
+		return nil, nil
+	}
+	return nil, cause
+}
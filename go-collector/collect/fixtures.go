@@ -0,0 +1,217 @@
+package collect
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+
+	"go-collector/article"
+	"go-collector/crawler/bingnews"
+	"go-collector/crawler/bluesky"
+	"go-collector/crawler/commoncrawl"
+	"go-collector/crawler/currents"
+	"go-collector/crawler/eventregistry"
+	"go-collector/crawler/gdelt"
+	"go-collector/crawler/googlenews"
+	"go-collector/crawler/guardian"
+	"go-collector/crawler/mastodon"
+	"go-collector/crawler/mediastack"
+	"go-collector/crawler/newsapi"
+	"go-collector/crawler/oai"
+	"go-collector/crawler/rss"
+	"go-collector/crawler/scrape"
+	"go-collector/crawler/x"
+	"go-collector/crawler/youtube"
+	"go-collector/extract"
+	"go-collector/sitemap"
+)
+
+// Fixture lee la respuesta guardada de una fuente en dir y la normaliza igual
+// que una respuesta real. Las APIs se leen de <dir>/<fuente>.json (el cuerpo
+// tal como lo devuelve la API), los feeds de <dir>/rss/*.xml, el de Google
+// News de <dir>/googlenews.xml, la cosecha OAI-PMH de <dir>/oai.xml (una
+// página de ListRecords) y el índice de Common Crawl de
+// <dir>/commoncrawl.json, con la página de cada captura en
+// <dir>/commoncrawl/<digest>.html, y el sitemap de noticias de
+// <dir>/sitemap.xml, con cada nota en <dir>/sitemap/<último tramo de la
+// URL>.html; las notas de scrape son <dir>/scrape/*.html, con su URL en
+// <link rel="canonical"> y la extracción genérica. No se filtra por fecha:
+// las respuestas guardadas suelen ser antiguas.
+func Fixture(dir, name string) ([]*article.Article, error) {
+	if name == "rss" {
+		files, err := filepath.Glob(filepath.Join(dir, "rss", "*.xml"))
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no hay feeds de prueba en %s", filepath.Join(dir, "rss"))
+		}
+		parser := gofeed.NewParser()
+		var out []*article.Article
+		for _, path := range files {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, fmt.Errorf("error abriendo feed de prueba: %w", err)
+			}
+			feed, err := parser.Parse(f)
+			f.Close()
+			if err != nil {
+				return nil, fmt.Errorf("error leyendo feed de prueba %s: %w", path, err)
+			}
+			out = append(out, rss.Normalize(feed)...)
+		}
+		return out, nil
+	}
+
+	if name == "googlenews" {
+		path := filepath.Join(dir, "googlenews.xml")
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo respuesta de prueba: %w", err)
+		}
+		defer f.Close()
+		parser := gofeed.NewParser()
+		parser.RSSTranslator = googlenews.Translator{}
+		feed, err := parser.Parse(f)
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo feed de prueba %s: %w", path, err)
+		}
+		return googlenews.Normalize(feed), nil
+	}
+
+	if name == "oai" {
+		path := filepath.Join(dir, "oai.xml")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo respuesta de prueba: %w", err)
+		}
+		var resp oai.Response
+		if err := xml.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("error parseando %s: %w", path, err)
+		}
+		return resp.Normalize(), nil
+	}
+
+	if name == "commoncrawl" {
+		path := filepath.Join(dir, "commoncrawl.json")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo respuesta de prueba: %w", err)
+		}
+		records, err := commoncrawl.ParseIndex(data)
+		if err != nil {
+			return nil, fmt.Errorf("error parseando %s: %w", path, err)
+		}
+		out := make([]*article.Article, 0, len(records))
+		for _, r := range records {
+			page, err := os.ReadFile(filepath.Join(dir, "commoncrawl", r.Digest+".html"))
+			if err != nil {
+				return nil, fmt.Errorf("error leyendo página de prueba: %w", err)
+			}
+			out = append(out, r.Normalize(page))
+		}
+		return out, nil
+	}
+
+	if name == "sitemap" {
+		path := filepath.Join(dir, "sitemap.xml")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo respuesta de prueba: %w", err)
+		}
+		var set sitemap.URLSet
+		if err := xml.Unmarshal(data, &set); err != nil {
+			return nil, fmt.Errorf("error parseando %s: %w", path, err)
+		}
+		var e extract.Extractor
+		out := make([]*article.Article, 0, len(set.URLs))
+		for _, entry := range set.URLs {
+			loc := strings.TrimSpace(entry.Loc)
+			page, err := os.ReadFile(filepath.Join(dir, "sitemap", loc[strings.LastIndex(loc, "/")+1:]+".html"))
+			if err != nil {
+				return nil, fmt.Errorf("error leyendo página de prueba: %w", err)
+			}
+			res, err := e.Extract(loc, page)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, entry.Normalize(page, res))
+		}
+		return out, nil
+	}
+
+	if name == "scrape" {
+		files, err := filepath.Glob(filepath.Join(dir, "scrape", "*.html"))
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no hay páginas de prueba en %s", filepath.Join(dir, "scrape"))
+		}
+		var e extract.Extractor
+		var out []*article.Article
+		for _, path := range files {
+			page, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("error leyendo página de prueba: %w", err)
+			}
+			doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+			if err != nil {
+				return nil, fmt.Errorf("error parseando %s: %w", path, err)
+			}
+			loc := doc.Find(`link[rel="canonical"]`).AttrOr("href", "")
+			res, err := e.Extract(loc, page)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, scrape.Normalize(scrape.Site{}, loc, doc, res))
+		}
+		return out, nil
+	}
+
+	path := filepath.Join(dir, name+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta de prueba: %w", err)
+	}
+	var normalizer interface {
+		Normalize() []*article.Article
+	}
+	switch name {
+	case "guardian":
+		normalizer = &guardian.Response{}
+	case "newsapi":
+		normalizer = &newsapi.Response{}
+	case "bingnews":
+		normalizer = &bingnews.Response{}
+	case "eventregistry":
+		normalizer = &eventregistry.Response{}
+	case "mediastack":
+		normalizer = &mediastack.Response{}
+	case "currents":
+		normalizer = &currents.Response{}
+	case "gdelt":
+		normalizer = &gdelt.Response{}
+	case "x":
+		normalizer = &x.Response{}
+	case "mastodon":
+		normalizer = &mastodon.Response{}
+	case "bluesky":
+		normalizer = &bluesky.Response{}
+	case "youtube":
+		normalizer = &youtube.Response{}
+	default:
+		return nil, fmt.Errorf("fuente desconocida: %s", name)
+	}
+	if err := json.Unmarshal(data, normalizer); err != nil {
+		return nil, fmt.Errorf("error parseando %s: %w", path, err)
+	}
+	return normalizer.Normalize(), nil
+}
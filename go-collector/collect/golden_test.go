@@ -0,0 +1,75 @@
+package collect
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-collector/config"
+)
+
+var update = flag.Bool("update", false, "reescribir los archivos golden con la normalización actual")
+
+// fixtures es el directorio de las respuestas guardadas; los golden están en
+// fixtures/golden/<fuente>.json.
+var fixtures = filepath.Join("..", "fixtures")
+
+// TestGolden compara la normalización de cada fuente sobre su respuesta
+// guardada con el JSON esperado: protege el mapeo de fechas, autores,
+// adjuntos e interacción. Con -update reescribe los golden:
+//
+//	go test ./collect -run Golden -update
+func TestGolden(t *testing.T) {
+	for _, n := range (&config.Sources{}).Named() {
+		if n.Name == "mock" {
+			continue
+		}
+		t.Run(n.Name, func(t *testing.T) {
+			path := filepath.Join(fixtures, "golden", n.Name+".json")
+			articles, err := Fixture(fixtures, n.Name)
+			if err != nil {
+				if _, statErr := os.Stat(path); errors.Is(statErr, os.ErrNotExist) {
+					t.Skipf("sin respuesta guardada: %v", err)
+				}
+				t.Fatal(err)
+			}
+			got, err := json.MarshalIndent(articles, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			if *update {
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (use -update)", err)
+			}
+			if bytes.Equal(got, want) {
+				return
+			}
+			gotLines, wantLines := strings.Split(string(got), "\n"), strings.Split(string(want), "\n")
+			for i := 0; i < max(len(gotLines), len(wantLines)); i++ {
+				var g, w string
+				if i < len(gotLines) {
+					g = gotLines[i]
+				}
+				if i < len(wantLines) {
+					w = wantLines[i]
+				}
+				if g != w {
+					t.Fatalf("%s:%d: se obtuvo %q, se esperaba %q", path, i+1, strings.TrimSpace(g), strings.TrimSpace(w))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package collect
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler"
+	"go-collector/crawler/googlenews"
+	"go-collector/fetch"
+	"go-collector/progress"
+	"go-collector/shortlink"
+)
+
+// googleNews lee el feed de búsqueda de Google News de cada idioma en la
+// edición del país de la fuente y resuelve los enlaces de Google a la URL del
+// medio. Cada feed trae como máximo 100 entradas, sin páginas siguientes.
+func (c *Collector) googleNews(ctx context.Context, name string, limit fetch.Middleware, src *config.Source, languages []string, from, to time.Time, fetched func(int)) ([]*article.Article, error) {
+	r := googlenews.NewCrawler()
+	c.use(limit, r.Client)
+	var cache shortlink.Cache
+	if c.Links != nil {
+		cache = c.Links.Cache
+	}
+	res := googlenews.NewResolver(cache)
+	c.use(limit, res.Client)
+
+	var out []*article.Article
+	seen := make(map[string]bool)
+	for _, lang := range languages {
+		u := googlenews.SearchURL(src.Query, googlenews.NewEdition(lang, src.Region), from, to)
+		feed, err := r.LeerFeed(ctx, u)
+		if err != nil {
+			if len(out) == 0 {
+				return nil, err
+			}
+			warn(ctx, "Google News se detuvo con %d artículos: %v", len(out), err)
+			break
+		}
+		kept := 0
+		for _, a := range googlenews.Normalize(feed) {
+			if seen[a.URL] || !inRange(a.Published, from, to) {
+				continue
+			}
+			seen[a.URL] = true
+			a.Request = "googlenews " + u
+			out = append(out, a)
+			kept++
+		}
+		fetched(kept)
+	}
+
+	// Lo que no se resuelve queda con el enlace de Google (y el dominio del
+	// medio): la ronda no se pierde por un enlace.
+	failed := 0
+	for _, a := range out {
+		if ctx.Err() != nil {
+			break
+		}
+		if !googlenews.IsLink(a.URL) {
+			continue
+		}
+		target, err := res.Resolve(ctx, a.URL)
+		if err != nil {
+			failed++
+			continue
+		}
+		a.URL, a.Domain = target, crawler.Domain(target)
+	}
+	if failed > 0 && ctx.Err() == nil {
+		progress.Emit(c.Progress, progress.Event{Type: progress.Error, Source: name,
+			Message: fmt.Sprintf("%d enlaces de Google News sin resolver: quedan como estaban", failed)})
+	}
+	return out, nil
+}
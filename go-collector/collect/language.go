@@ -0,0 +1,30 @@
+package collect
+
+import (
+	"go-collector/article"
+	"go-collector/lang"
+)
+
+// sampleSize es cuánto del cuerpo se usa para detectar el idioma: el
+// titular y el resumen suelen bastar y el resto solo cuesta tiempo.
+const sampleSize = 2000
+
+// detectLanguage completa el idioma de los artículos que la fuente no
+// etiquetó (NewsAPI, X, feeds sin <language>) y normaliza el resto a ISO
+// 639-1, para filtrar por idioma igual en todas las fuentes.
+func detectLanguage(articles []*article.Article) {
+	for _, a := range articles {
+		if a.Language = lang.Normalize(a.Language); a.Language == "" {
+			a.Language = lang.Detect(LanguageSample(a))
+		}
+	}
+}
+
+// LanguageSample es el texto del artículo con el que se detecta su idioma.
+func LanguageSample(a *article.Article) string {
+	body := a.Body
+	if len(body) > sampleSize {
+		body = body[:sampleSize]
+	}
+	return a.Title + "\n" + a.Summary + "\n" + body
+}
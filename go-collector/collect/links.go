@@ -0,0 +1,41 @@
+package collect
+
+import (
+	"context"
+	"fmt"
+
+	"go-collector/crawler"
+	"go-collector/progress"
+)
+
+// expandLinks reemplaza los enlaces acortados de los artículos por su
+// destino: la URL (y con ella el dominio) cuando el artículo mismo es un
+// enlace acortado, y los enlaces del resumen y el texto. Lo que no se pudo
+// expandir queda como estaba y se avisa con la cantidad.
+func (c *Collector) expandLinks(ctx context.Context, r *Result) {
+	if c.Links == nil {
+		return
+	}
+	failed := 0
+	for _, a := range r.Articles {
+		if ctx.Err() != nil {
+			return
+		}
+		if c.Links.IsShort(a.URL) {
+			if target, err := c.Links.Expand(ctx, a.URL); err == nil {
+				a.URL, a.Domain = target, crawler.Domain(target)
+			} else {
+				failed++
+			}
+		}
+		var n int
+		a.Summary, n = c.Links.ExpandText(ctx, a.Summary)
+		failed += n
+		a.Body, n = c.Links.ExpandText(ctx, a.Body)
+		failed += n
+	}
+	if failed > 0 && ctx.Err() == nil {
+		progress.Emit(c.Progress, progress.Event{Type: progress.Error, Source: r.Source,
+			Message: fmt.Sprintf("%d enlaces acortados sin expandir: quedan como estaban", failed)})
+	}
+}
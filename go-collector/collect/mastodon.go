@@ -0,0 +1,232 @@
+package collect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler/mastodon"
+	"go-collector/query"
+)
+
+// defaultMastodonMax es cuántas publicaciones se piden como máximo por
+// consulta (hashtag o búsqueda) a cada instancia si max_results no lo indica:
+// los hashtags populares tienen miles.
+const defaultMastodonMax = 400
+
+// mastodonPosts consulta todas las instancias de la fuente en paralelo, cada
+// una con su cupo. Los hashtags de la consulta se piden a todas y el texto
+// completo solo a las que tienen token. Una publicación federada llega por
+// varias instancias: se conserva la primera según el orden de la
+// configuración. Una instancia que falla no se lleva a las demás: queda en
+// Result.Failed.
+func (c *Collector) mastodonPosts(ctx context.Context, name string, src *config.Source, from, to time.Time, pageSize int, fetched func(int)) ([]*article.Article, error) {
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -7)
+	}
+	pageSize = min(pageSize, mastodon.MaxLimit)
+	max := src.MaxResults
+	if max == 0 {
+		max = defaultMastodonMax
+	}
+	q := query.ParseMastodon(src.Query)
+
+	perInstance := make([][]*article.Article, len(src.Instances))
+	errs := make([]error, len(src.Instances))
+	var g errgroup.Group
+	for i, inst := range src.Instances {
+		g.Go(func() error {
+			defer recoverSource(name+" "+inst.URL, &errs[i])
+			limit, err := c.limit(name+" "+inst.URL, instanceSource(src, inst))
+			if err != nil {
+				errs[i] = err
+				return nil
+			}
+			m := mastodon.NewCrawler(inst.URL, inst.Credential())
+			c.use(limit, m.Client)
+			p := &instancePoll{m: m, from: from, to: to, pageSize: pageSize, max: max, fetched: fetched}
+			perInstance[i], errs[i] = p.run(ctx, q)
+			return nil
+		})
+	}
+	g.Wait()
+
+	var out []*article.Article
+	var failed []string
+	seen := make(map[string]bool)
+	for i, inst := range src.Instances {
+		for _, a := range perInstance[i] {
+			if seen[a.URL] || !wantLanguage(a, src.Languages) {
+				continue
+			}
+			seen[a.URL] = true
+			out = append(out, a)
+		}
+		var p *PanicError
+		if errors.As(errs[i], &p) {
+			c.emitPanic(name, p)
+		}
+		if errs[i] != nil {
+			failed = append(failed, errs[i].Error())
+			fail(ctx, inst.URL, errs[i])
+		}
+	}
+	if len(failed) == len(src.Instances) && len(failed) > 0 {
+		return nil, fmt.Errorf("ninguna instancia respondió: %s", strings.Join(failed, "; "))
+	}
+	return out, nil
+}
+
+// instanceSource es la configuración de cupo de una instancia: la suya, la
+// de la fuente o la de Mastodon por defecto.
+func instanceSource(src *config.Source, inst config.Instance) *config.Source {
+	s := &config.Source{RateLimit: inst.RateLimit, RateBurst: inst.RateBurst}
+	if s.RateLimit == "" {
+		s.RateLimit, s.RateBurst = src.RateLimit, src.RateBurst
+	}
+	if s.RateLimit == "" {
+		s.RateLimit = config.DefaultInstanceRate
+	}
+	return s
+}
+
+// wantLanguage indica si el artículo está en alguno de los idiomas pedidos.
+// Sin idiomas configurados, o sin idioma declarado, se conserva: el detector
+// de idioma lo completa después.
+func wantLanguage(a *article.Article, languages []string) bool {
+	return len(languages) == 0 || a.Language == "" || slices.Contains(languages, a.Language)
+}
+
+// onTopic indica si la plataforma clasificó el artículo en alguno de los
+// temas (por el tema o por su clase, sin distinguir mayúsculas). Sin temas
+// configurados, todos sirven.
+func onTopic(a *article.Article, topics []string) bool {
+	if len(topics) == 0 {
+		return true
+	}
+	for _, an := range a.Annotations {
+		if an.Kind != article.AnnotationContext {
+			continue
+		}
+		for _, t := range topics {
+			if strings.EqualFold(an.Name, t) || strings.EqualFold(an.Domain, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// instancePoll recorre las consultas en una instancia.
+type instancePoll struct {
+	m        *mastodon.Crawler
+	from, to time.Time
+	pageSize int
+	max      int
+	fetched  func(int)
+}
+
+func (p *instancePoll) run(ctx context.Context, q query.MastodonQuery) ([]*article.Article, error) {
+	var out []*article.Article
+	for _, tag := range q.Hashtags {
+		articles, err := p.hashtag(ctx, tag)
+		out = append(out, articles...)
+		if err != nil {
+			return out, err
+		}
+	}
+	for _, term := range q.FullText {
+		articles, err := p.search(ctx, term)
+		if errors.Is(err, mastodon.ErrSearchUnavailable) {
+			// No es una falla: la instancia solo sirve para hashtags.
+			log.Printf("mastodon %s: búsqueda de texto completo no disponible, solo hashtags", p.m.BaseURL)
+			break
+		}
+		out = append(out, articles...)
+		if err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+// hashtag recorre la timeline del hashtag hacia atrás hasta pasar el inicio
+// del rango o llegar al máximo.
+func (p *instancePoll) hashtag(ctx context.Context, tag string) ([]*article.Article, error) {
+	var out []*article.Article
+	maxID := ""
+	for n := 0; n < p.max; {
+		resp, err := p.m.Hashtag(ctx, tag, min(p.pageSize, p.max-n), maxID)
+		if err != nil {
+			return out, fmt.Errorf("#%s: %w", tag, err)
+		}
+		if len(resp.Statuses) == 0 {
+			break
+		}
+		n += len(resp.Statuses)
+		maxID = resp.Statuses[len(resp.Statuses)-1].ID
+		kept, older := p.keep(resp, p.m.BaseURL+" #"+tag)
+		p.fetched(len(kept))
+		out = append(out, kept...)
+		if older || len(resp.Statuses) < p.pageSize {
+			break
+		}
+	}
+	return out, nil
+}
+
+// search recorre la búsqueda de texto completo. Sus resultados no vienen en
+// orden cronológico: se filtran por rango sin cortar antes. Si una página no
+// trae nada nuevo (instancias que ignoran offset), se deja de pedir.
+func (p *instancePoll) search(ctx context.Context, term string) ([]*article.Article, error) {
+	var out []*article.Article
+	seen := make(map[string]bool)
+	for offset := 0; offset < p.max; {
+		resp, err := p.m.Search(ctx, term, min(p.pageSize, p.max-offset), offset)
+		if err != nil {
+			if errors.Is(err, mastodon.ErrSearchUnavailable) {
+				return out, err
+			}
+			return out, fmt.Errorf("%s: %w", term, err)
+		}
+		offset += len(resp.Statuses)
+		fresh := 0
+		for _, s := range resp.Statuses {
+			if !seen[s.ID] {
+				seen[s.ID] = true
+				fresh++
+			}
+		}
+		kept, _ := p.keep(resp, p.m.BaseURL+" "+term)
+		p.fetched(len(kept))
+		out = append(out, kept...)
+		if fresh == 0 || len(resp.Statuses) < p.pageSize {
+			break
+		}
+	}
+	return out, nil
+}
+
+// keep normaliza la página y deja lo que está en el rango; older indica que
+// alguna publicación es anterior al inicio.
+func (p *instancePoll) keep(resp *mastodon.Response, request string) (kept []*article.Article, older bool) {
+	for _, a := range resp.Normalize() {
+		if !a.Published.IsZero() && a.Published.Before(p.from) {
+			older = true
+			continue
+		}
+		if inRange(a.Published, p.from, p.to) {
+			a.Request = "mastodon " + request
+			kept = append(kept, a)
+		}
+	}
+	return kept, older
+}
@@ -0,0 +1,61 @@
+package collect
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler/mediastack"
+	"go-collector/query"
+)
+
+// defaultMediastackMax es cuántas noticias se piden como máximo por término
+// si max_results no lo indica: el plan gratuito cubre 500 peticiones al mes.
+const defaultMediastackMax = 100
+
+// mediastackNews hace una búsqueda por término de la consulta (Mediastack no
+// admite OR), con todos los idiomas a la vez, recorriendo las páginas hasta
+// el máximo. Una noticia que coincide con varios términos se conserva una
+// vez.
+func (c *Collector) mediastackNews(ctx context.Context, m *mediastack.Crawler, src *config.Source, languages []string, from, to time.Time, pageSize int, fetched func(int)) ([]*article.Article, error) {
+	max := src.MaxResults
+	if max == 0 {
+		max = defaultMediastackMax
+	}
+	var out []*article.Article
+	seen := make(map[string]bool)
+	for _, term := range query.Alternatives(src.Query) {
+		term = strings.Trim(term, `"`)
+		opts := mediastack.SearchOptions{Languages: languages, From: from, To: to}
+		for opts.Offset < max {
+			opts.Count = min(pageSize, max-opts.Offset)
+			resp, err := m.Search(ctx, term, opts)
+			if err != nil && len(out) == 0 {
+				return nil, err
+			}
+			if err != nil {
+				// Lo ya recibido es válido: se guarda y se avisa del corte.
+				warn(ctx, "Mediastack se detuvo con %d noticias: %v", len(out), err)
+				return out, nil
+			}
+			kept := 0
+			for _, a := range resp.Normalize() {
+				if seen[a.URL] || !inRange(a.Published, from, to) {
+					continue
+				}
+				seen[a.URL] = true
+				a.Request = "mediastack " + term
+				out = append(out, a)
+				kept++
+			}
+			fetched(kept)
+			opts.Offset += len(resp.Data)
+			if len(resp.Data) == 0 || opts.Offset >= resp.Pagination.Total {
+				break
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,139 @@
+package collect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler"
+	"go-collector/crawler/oai"
+	"go-collector/fetch"
+	"go-collector/query"
+)
+
+// defaultOAIMax es cuántos registros se cosechan como máximo de cada
+// repositorio si max_results no lo indica: un repositorio entero puede tener
+// cientos de miles.
+const defaultOAIMax = 1000
+
+// oaiRecords cosecha los repositorios de la fuente en paralelo, todos con el
+// cupo de la fuente, y de cada uno sus sets en orden. El rango se aplica a la
+// fecha de modificación de los registros: lo publicado hace años pero
+// catalogado en el rango entra igual. Un registro que está en varios sets se
+// conserva una vez. Un repositorio que falla no se lleva a los demás: queda
+// en Result.Failed.
+func (c *Collector) oaiRecords(ctx context.Context, name string, limit fetch.Middleware, src *config.Source, from, to time.Time, fetched func(int)) ([]*article.Article, error) {
+	max := src.MaxResults
+	if max == 0 {
+		max = defaultOAIMax
+	}
+	var terms []string
+	for _, term := range query.Alternatives(src.Query) {
+		terms = append(terms, strings.ToLower(strings.Trim(term, `"`)))
+	}
+
+	perRepo := make([][]*article.Article, len(src.Repositories))
+	errs := make([]error, len(src.Repositories))
+	var g errgroup.Group
+	for i, repo := range src.Repositories {
+		g.Go(func() error {
+			defer recoverSource(name+" "+repo.URL, &errs[i])
+			o := oai.NewCrawler(repo.URL)
+			c.use(limit, o.Client)
+			sets := repo.Sets
+			if len(sets) == 0 {
+				sets = []string{""}
+			}
+			seen := make(map[string]bool)
+			for _, set := range sets {
+				articles, err := harvest(ctx, o, oai.ListOptions{Set: set, From: from, Until: to}, max-len(perRepo[i]), fetched)
+				if err != nil && len(articles) == 0 {
+					errs[i] = err
+					return nil
+				}
+				if err != nil {
+					// Lo ya recibido es válido: se guarda y se avisa del corte.
+					warn(ctx, "%s se detuvo con %d registros: %v", repo.URL, len(articles), err)
+				}
+				for _, a := range articles {
+					if seen[a.URL] || !wantLanguage(a, src.Languages) || !mentions(a, terms) {
+						continue
+					}
+					seen[a.URL] = true
+					// El medio es el repositorio, no el resolvedor del enlace
+					// (hdl.handle.net, doi.org).
+					a.Domain = crawler.Domain(repo.URL)
+					a.Request = "oai " + repo.URL
+					if set != "" {
+						a.Request += " set=" + set
+					}
+					perRepo[i] = append(perRepo[i], a)
+				}
+				if len(perRepo[i]) >= max {
+					break
+				}
+			}
+			return nil
+		})
+	}
+	g.Wait()
+
+	var out []*article.Article
+	var failed []string
+	for i, repo := range src.Repositories {
+		out = append(out, perRepo[i]...)
+		var p *PanicError
+		if errors.As(errs[i], &p) {
+			c.emitPanic(name, p)
+		}
+		if errs[i] != nil {
+			failed = append(failed, errs[i].Error())
+			fail(ctx, repo.URL, errs[i])
+		}
+	}
+	if len(failed) == len(src.Repositories) && len(failed) > 0 {
+		return nil, fmt.Errorf("ningún repositorio respondió: %s", strings.Join(failed, "; "))
+	}
+	return out, nil
+}
+
+// harvest recorre con el resumptionToken las páginas de una cosecha hasta
+// agotarla o juntar max registros. Si una página falla devuelve lo recibido
+// hasta ahí junto con el error.
+func harvest(ctx context.Context, o *oai.Crawler, opts oai.ListOptions, max int, fetched func(int)) ([]*article.Article, error) {
+	var out []*article.Article
+	resp, err := o.ListRecords(ctx, opts)
+	for err == nil {
+		articles := resp.Normalize()
+		fetched(len(articles))
+		out = append(out, articles...)
+		token := resp.ListRecords.ResumptionToken.Token
+		if token == "" || len(out) >= max || len(resp.ListRecords.Records) == 0 {
+			return out, nil
+		}
+		resp, err = o.Resume(ctx, strings.TrimSpace(token))
+	}
+	return out, err
+}
+
+// mentions indica si el registro menciona alguno de los términos (en
+// minúsculas) en el título o el resumen; sin términos, todos sirven. OAI-PMH
+// no busca: la consulta se aplica al recibir.
+func mentions(a *article.Article, terms []string) bool {
+	if len(terms) == 0 {
+		return true
+	}
+	text := strings.ToLower(a.Title + "\n" + a.Summary)
+	for _, t := range terms {
+		if strings.Contains(text, t) {
+			return true
+		}
+	}
+	return false
+}
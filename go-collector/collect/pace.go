@@ -0,0 +1,49 @@
+package collect
+
+import (
+	"math"
+	"time"
+
+	"go-collector/storage"
+)
+
+// paceHalfLife es la vida media del ritmo de un feed: lo que publicó hace una
+// semana pesa la mitad que lo de hoy.
+const paceHalfLife = 7 * 24 * time.Hour
+
+// pacing es la frecuencia adaptativa de los feeds (sources.rss.adaptive):
+// cada uno se lee cuando, a su ritmo reciente, se espera una entrada nueva,
+// entre min y max.
+type pacing struct {
+	min, max time.Duration
+}
+
+// due indica si al feed, con estado prev (nil si nunca se leyó), le toca
+// leerse en now. Tiene un margen de un décimo del mínimo: una ronda de
+// --every igual al mínimo no lo saltea por los segundos que tardó en
+// leerse la vez anterior.
+func (p *pacing) due(prev *storage.FeedState, now time.Time) bool {
+	return prev == nil || prev.Next.Sub(now) <= p.min/10
+}
+
+// schedule actualiza el ritmo del feed en state, recién leído, con las n
+// entradas nuevas desde la lectura anterior (prev), y fija cuándo leerlo de
+// nuevo. La primera vez no hay con qué medir: se vuelve a leer al mínimo. El
+// lapso a lo sumo se duplica de una lectura a la siguiente, para no perder
+// entradas de un feed que estuvo quieto un tiempo y vuelve a publicar.
+func (p *pacing) schedule(prev, state *storage.FeedState, n int) {
+	next := p.min
+	if prev != nil && !prev.Polled.IsZero() {
+		elapsed := max(state.Polled.Sub(prev.Polled), 0)
+		k := math.Exp2(-float64(elapsed) / float64(paceHalfLife))
+		state.YieldItems = prev.YieldItems*k + float64(n)
+		state.YieldHours = prev.YieldHours*k + elapsed.Hours()
+
+		next = p.max
+		if state.YieldItems > 0 {
+			next = time.Duration(state.YieldHours / state.YieldItems * float64(time.Hour))
+		}
+		next = min(next, 2*max(elapsed, p.min))
+	}
+	state.Next = state.Polled.Add(min(max(next, p.min), p.max))
+}
@@ -0,0 +1,71 @@
+package collect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-collector/config"
+	"go-collector/progress"
+	"go-collector/storage"
+)
+
+// feedStates da el mismo estado para todos los feeds.
+type feedStates struct{ state storage.FeedState }
+
+func (f feedStates) GetFeedState(u string) (*storage.FeedState, error) {
+	s := f.state
+	s.URL = u
+	return &s, nil
+}
+
+// failTransport falla la prueba si se pide algo.
+type failTransport struct{ t *testing.T }
+
+func (f failTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.t.Errorf("se pidió %s", req.URL)
+	return nil, errors.New("sin red")
+}
+
+// events anota los eventos de avance.
+type events struct {
+	mu  sync.Mutex
+	got []progress.Event
+}
+
+func (e *events) Emit(ev progress.Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.got = append(e.got, ev)
+}
+
+// Un feed al que aún no le toca no se pide y su próxima lectura se informa
+// como evento de avance, no en la salida estándar.
+func TestDeferredFeedReported(t *testing.T) {
+	now := time.Now()
+	rep := &events{}
+	c := &Collector{
+		Transport:  failTransport{t},
+		Progress:   rep,
+		FeedStates: feedStates{storage.FeedState{Polled: now.Add(-time.Hour), Next: now.Add(3 * time.Hour)}},
+	}
+	const feed = "https://www.udea.edu.co/feed"
+	src := &config.Source{Feeds: []string{feed}, Adaptive: &config.Adaptive{}}
+	articles, err := c.Source(context.Background(), "rss", src, now.UTC())
+	if err != nil || len(articles) != 0 {
+		t.Fatalf("Source = %d artículos, %v; se esperaba ninguno", len(articles), err)
+	}
+	var deferred []progress.Event
+	for _, ev := range rep.got {
+		if ev.Type == progress.FeedDeferred {
+			deferred = append(deferred, ev)
+		}
+	}
+	if len(deferred) != 1 || deferred[0].Source != "rss" || !strings.Contains(deferred[0].Message, feed) {
+		t.Errorf("eventos %+v, se esperaba uno %s del feed", rep.got, progress.FeedDeferred)
+	}
+}
@@ -0,0 +1,33 @@
+package collect
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"go-collector/progress"
+)
+
+// PanicError es un pánico recuperado en el crawler de una fuente: la fuente
+// falla con este error y las demás siguen.
+type PanicError struct {
+	Source string
+	Value  any
+	Stack  []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("pánico en %s: %v", e.Source, e.Value)
+}
+
+// recoverSource, usada con defer, convierte un pánico en un *PanicError en err.
+func recoverSource(source string, err *error) {
+	if v := recover(); v != nil {
+		*err = &PanicError{Source: source, Value: v, Stack: debug.Stack()}
+	}
+}
+
+// emitPanic informa el pánico con su stack, para que las herramientas que
+// leen el avance puedan alertar.
+func (c *Collector) emitPanic(source string, p *PanicError) {
+	progress.Emit(c.Progress, progress.Event{Type: progress.Panic, Source: source, Message: p.Error(), Stack: string(p.Stack)})
+}
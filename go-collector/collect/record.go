@@ -0,0 +1,93 @@
+package collect
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"go-collector/fetch"
+	"go-collector/storage"
+)
+
+// record junta lo que una consulta a una fuente informa además de sus
+// artículos: las partes que fallaron (ver fail), el estado de los feeds
+// leídos (ver polled), las peticiones a cada API (ver counted) y sus avisos
+// (ver warn).
+type record struct {
+	mu       sync.Mutex
+	failed   []Failure
+	feeds    []*storage.FeedState
+	requests map[string]int
+	warnings []string
+}
+
+type recordKey struct{}
+
+type apiKey struct{}
+
+// withAPI marca ctx con la fuente cuya API se consulta: sus peticiones se
+// cuentan a su nombre, también cuando se consulta como reemplazo de otra.
+func withAPI(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, apiKey{}, name)
+}
+
+// counted cuenta cada petición que sale, si su contexto lo pide, a nombre de
+// la fuente marcada con withAPI. Va después del cupo: las que esperan turno y
+// se cancelan no cuentan.
+func counted(next http.RoundTripper) http.RoundTripper {
+	return fetch.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		ctx := req.Context()
+		name, _ := ctx.Value(apiKey{}).(string)
+		if rec, ok := ctx.Value(recordKey{}).(*record); ok && name != "" {
+			rec.mu.Lock()
+			if rec.requests == nil {
+				rec.requests = make(map[string]int)
+			}
+			rec.requests[name]++
+			rec.mu.Unlock()
+		}
+		return next.RoundTrip(req)
+	})
+}
+
+// recording devuelve un contexto en el que fail y polled registran en rec.
+func recording(ctx context.Context) (context.Context, *record) {
+	rec := &record{}
+	return context.WithValue(ctx, recordKey{}, rec), rec
+}
+
+// fail registra, si ctx lo pide, que item falló sin que fallara la fuente.
+func fail(ctx context.Context, item string, err error) {
+	if rec, ok := ctx.Value(recordKey{}).(*record); ok {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.failed = append(rec.failed, Failure{Item: item, Err: err})
+	}
+}
+
+// polled registra, si ctx lo pide, el estado nuevo de un feed.
+func polled(ctx context.Context, state *storage.FeedState) {
+	if rec, ok := ctx.Value(recordKey{}).(*record); ok {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.feeds = append(rec.feeds, state)
+	}
+}
+
+// warn registra, si ctx lo pide, un aviso de la fuente.
+func warn(ctx context.Context, format string, args ...any) {
+	if rec, ok := ctx.Value(recordKey{}).(*record); ok {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.warnings = append(rec.warnings, fmt.Sprintf(format, args...))
+	}
+}
+
+// result completa r con lo registrado.
+func (rec *record) result(r Result) Result {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	r.Failed, r.Feeds, r.Requests, r.Warnings = rec.failed, rec.feeds, rec.requests, rec.warnings
+	return r
+}
@@ -0,0 +1,40 @@
+package collect
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-collector/config"
+)
+
+// Los avisos de una fuente (acá, el reintento de X tras un 429) quedan en su
+// Result para que los imprima el comando.
+func TestWarningsInResult(t *testing.T) {
+	a := &api{calls: map[string]int{}, respond: func(_ string, call int) *http.Response {
+		if call == 1 {
+			reset := http.Header{"X-Rate-Limit-Reset": {strconv.FormatInt(time.Now().Unix(), 10)}}
+			return reply(http.StatusTooManyRequests, reset, `{"title":"Too Many Requests","status":429}`)
+		}
+		return reply(http.StatusOK, nil, `{"data":[{"id":"1","text":"La UdeA abre matrículas","author_id":"7","created_at":"2026-03-10T14:00:00.000Z"}],
+			"includes":{"users":[{"id":"7","username":"udea"}]},"meta":{"result_count":1}}`)
+	}}
+	c := &Collector{Transport: a}
+	sources := &config.Sources{X: config.Source{Enabled: true, Query: "UdeA", APIKey: "clave"}}
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	results := c.Enabled(context.Background(), sources, "x", now)
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("resultados %+v", results)
+	}
+	r := results[0]
+	if len(r.Articles) != 1 {
+		t.Errorf("%d tweets, se esperaba 1", len(r.Articles))
+	}
+	if len(r.Warnings) != 1 || !strings.Contains(r.Warnings[0], "429") {
+		t.Errorf("avisos %q, se esperaba el del reintento", r.Warnings)
+	}
+}
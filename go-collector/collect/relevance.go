@@ -0,0 +1,86 @@
+package collect
+
+import (
+	"strings"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/query"
+	"go-collector/relevance"
+)
+
+// Queries devuelve la consulta con que se evalúa la relevancia de los
+// artículos de cada fuente.
+type Queries func(source string) config.Query
+
+// CampaignQuery evalúa todas las fuentes con la consulta de la campaña.
+func CampaignQuery(camp config.Campaign) Queries {
+	return func(string) config.Query { return camp.Query }
+}
+
+// SourceQueries evalúa cada fuente con las alternativas de su propia
+// consulta (sources.<fuente>.query); una fuente sin consulta no tiene
+// términos.
+func SourceQueries(sources *config.Sources) Queries {
+	return func(source string) config.Query {
+		var q config.Query
+		if src := sources.Get(source); src != nil {
+			for _, term := range query.Alternatives(src.Query) {
+				q.Terms = append(q.Terms, strings.Trim(term, `"`))
+			}
+		}
+		return q
+	}
+}
+
+// FilterRelevance aplica las reglas de relevancia (config relevance) a los
+// artículos de todas las fuentes que respondieron: descarta los que las
+// reglas de supresión descartan (un pattern que se activa, o todas las
+// menciones en un contexto negativo) y, de RSS, también los que no mencionan
+// la consulta: los feeds traen todo lo que publica el medio, a diferencia de
+// las APIs, que ya buscan por la consulta. Los artículos de una fuente con
+// términos guardan la explicación de su puntaje. Devuelve las activaciones
+// de cada regla, para el resumen de la ronda.
+func FilterRelevance(results []Result, cfg config.Relevance, queries Queries) ([]relevance.RuleHit, error) {
+	filters := make(map[string]*relevance.Filter)
+	hits := make([]relevance.RuleHit, 0, len(cfg.Suppress))
+	for i, r := range results {
+		if r.Err != nil || r.Held != nil {
+			continue
+		}
+		q := queries(r.Source)
+		key := strings.Join(q.Terms, "\x00") + "\x01" + strings.Join(q.Aliases, "\x00")
+		f := filters[key]
+		if f == nil {
+			var err error
+			if f, err = relevance.NewFilter(q, cfg); err != nil {
+				return nil, err
+			}
+			filters[key] = f
+		}
+		var kept []*article.Article
+		for _, a := range r.Articles {
+			exp := f.Explain(a)
+			suppressed := !exp.Relevant && len(exp.RuleHits) > 0
+			if suppressed || (r.Source == "rss" && f.HasTerms() && !exp.Relevant) {
+				continue
+			}
+			if f.HasTerms() {
+				a.Explanation = exp
+			}
+			kept = append(kept, a)
+		}
+		results[i].Articles = kept
+	}
+	// Todos los filtros tienen las reglas de cfg, en el mismo orden.
+	for _, f := range filters {
+		for i, h := range f.RuleHits() {
+			if i == len(hits) {
+				hits = append(hits, h)
+			} else {
+				hits[i].Hits += h.Hits
+			}
+		}
+	}
+	return hits, nil
+}
@@ -0,0 +1,63 @@
+package collect
+
+import (
+	"testing"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/relevance"
+)
+
+// Las reglas de supresión descartan artículos de cualquier fuente; de RSS,
+// además, los que no mencionan la consulta. Las activaciones se cuentan por
+// regla, en el orden de la configuración, y cada artículo guarda la
+// explicación de su puntaje, con el tono si hay léxico para su idioma.
+func TestFilterRelevance(t *testing.T) {
+	cfg := config.Relevance{Suppress: []config.SuppressRule{
+		{Name: "udea_futbol", Term: "UdeA", Context: []string{"gol", "partido"}},
+		{Name: "horoscopo", Pattern: `(?i)horóscopo`},
+	}}
+	results := []Result{
+		{Source: "gdelt", Articles: []*article.Article{
+			nota("gdelt", "https://www.eltiempo.com/a", "La UdeA abre matrículas para el segundo semestre"),
+			nota("gdelt", "https://www.eltiempo.com/b", "Gol de la UdeA en el partido del domingo"),
+			nota("gdelt", "https://www.eltiempo.com/c", "Resultados del torneo universitario"),
+		}},
+		{Source: "rss", Articles: []*article.Article{
+			nota("rss", "https://www.elcolombiano.com/a", "Estudiantes de la UdeA votan un paro"),
+			nota("rss", "https://www.elcolombiano.com/b", "El clima en Medellín"),
+			nota("rss", "https://www.elcolombiano.com/c", "Horóscopo de la UdeA"),
+		}},
+	}
+
+	results[0].Articles[0].Language = "es"
+	hits, err := FilterRelevance(results, cfg, CampaignQuery(config.Campaign{Query: config.Query{Terms: []string{"UdeA"}}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept := map[string]bool{}
+	for _, r := range results {
+		for _, a := range r.Articles {
+			kept[a.URL] = true
+			if a.Explanation == nil {
+				t.Errorf("%s sin explicación del puntaje", a.URL)
+			}
+		}
+	}
+	want := []string{"https://www.eltiempo.com/a", "https://www.eltiempo.com/c", "https://www.elcolombiano.com/a"}
+	if len(kept) != len(want) {
+		t.Errorf("quedaron %v, se esperaban %v", kept, want)
+	}
+	for _, u := range want {
+		if !kept[u] {
+			t.Errorf("se descartó %s", u)
+		}
+	}
+	if exp := results[0].Articles[0].Explanation; exp == nil || exp.Sentiment == nil {
+		t.Error("la explicación de un artículo en español no trae el tono")
+	}
+	wantHits := []relevance.RuleHit{{Rule: "udea_futbol", Hits: 1}, {Rule: "horoscopo", Hits: 1}}
+	if len(hits) != len(wantHits) || hits[0] != wantHits[0] || hits[1] != wantHits[1] {
+		t.Errorf("activaciones %v, se esperaban %v", hits, wantHits)
+	}
+}
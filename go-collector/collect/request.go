@@ -0,0 +1,40 @@
+package collect
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+)
+
+// Request describe la consulta de una fuente para el linaje de sus artículos:
+// consulta, idiomas y rango efectivos de la ronda. Junto con el hash de la
+// configuración alcanza para repetirla. Los feeds RSS se describen por su URL,
+// artículo por artículo.
+func Request(name string, src *config.Source, now time.Time) string {
+	parts := []string{name}
+	if src.Query != "" {
+		parts = append(parts, fmt.Sprintf("query=%q", src.Query))
+	}
+	if len(src.Languages) > 0 {
+		parts = append(parts, "languages="+strings.Join(src.Languages, ","))
+	}
+	if from, to, err := src.Range(now); err == nil {
+		if !from.IsZero() {
+			parts = append(parts, "from="+from.UTC().Format(time.RFC3339))
+		}
+		parts = append(parts, "to="+to.UTC().Format(time.RFC3339))
+	}
+	return strings.Join(parts, " ")
+}
+
+// tagRequest completa la consulta de los artículos que no la traen.
+func tagRequest(articles []*article.Article, request string) {
+	for _, a := range articles {
+		if a.Request == "" {
+			a.Request = request
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package collect
+
+import (
+	"go-collector/article"
+	"go-collector/corpus"
+	"go-collector/dedup"
+)
+
+// Saver guarda en el corpus los artículos de las fuentes que respondieron.
+// Solo depende de corpus.Store: lo que únicamente guarda la base (adjuntos,
+// interacción, linaje, procedencia...) lo agregan Saved y Collapsed.
+type Saver struct {
+	Store corpus.Store
+	// Dedup, si no es nil, colapsa en el artículo ya guardado la misma
+	// historia con otra URL en vez de guardarla de nuevo (ver Load).
+	Dedup *dedup.Deduper
+	// Saved, si no es nil, se llama con cada artículo recién guardado (ya
+	// con su ID).
+	Saved func(a *article.Article) error
+	// Collapsed, si no es nil, se llama con cada artículo colapsado en orig
+	// y la estrategia que encontró el duplicado.
+	Collapsed func(orig, a *article.Article, strategy string) error
+}
+
+// Saving es lo que Save hizo con los artículos de una fuente.
+type Saving struct {
+	// Kept son los artículos guardados, en el orden en que llegaron.
+	Kept []*article.Article
+	// Collapsed cuenta los colapsados por estrategia de deduplicación.
+	Collapsed map[string]int
+}
+
+// Duplicates es cuántos artículos se colapsaron.
+func (s *Saving) Duplicates() int {
+	n := 0
+	for _, c := range s.Collapsed {
+		n += c
+	}
+	return n
+}
+
+// Load prepara la deduplicación con los artículos del corpus publicados
+// cerca de los de results. Sin Dedup no hace nada.
+func (s *Saver) Load(results []Result) error {
+	if s.Dedup == nil {
+		return nil
+	}
+	var all []*article.Article
+	for _, r := range results {
+		all = append(all, r.Articles...)
+	}
+	return s.Dedup.Load(s.Store, all)
+}
+
+// Save guarda los artículos de r que tienen URL. Con Dedup, los que ya
+// están en el corpus con otra URL se colapsan y los guardados se agregan al
+// índice, para detectar los duplicados entre las fuentes de la ronda. Se
+// corta en el primer error, con lo guardado hasta ahí.
+func (s *Saver) Save(r Result) (*Saving, error) {
+	out := &Saving{Collapsed: make(map[string]int)}
+	for _, a := range r.Articles {
+		if a.URL == "" {
+			continue
+		}
+		if s.Dedup != nil {
+			if orig, strategy := s.Dedup.MatchBy(a); orig != nil && orig.URL != a.URL {
+				if s.Collapsed != nil {
+					if err := s.Collapsed(orig, a, strategy); err != nil {
+						return out, err
+					}
+				}
+				out.Collapsed[strategy]++
+				continue
+			}
+		}
+		if err := s.Store.SaveArticle(a); err != nil {
+			return out, err
+		}
+		if s.Saved != nil {
+			if err := s.Saved(a); err != nil {
+				return out, err
+			}
+		}
+		if s.Dedup != nil {
+			s.Dedup.Add(a)
+		}
+		out.Kept = append(out.Kept, a)
+	}
+	return out, nil
+}
@@ -0,0 +1,140 @@
+package collect
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-collector/article"
+	"go-collector/corpus"
+	"go-collector/dedup"
+	"go-collector/storage"
+)
+
+var published = time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+
+func nota(source, url, title string) *article.Article {
+	return &article.Article{Source: source, URL: url, Title: title, Published: published}
+}
+
+// Save guarda en el corpus los artículos con URL, avisa cada uno a Saved ya
+// con su ID, y guardarlos de nuevo no los repite.
+func TestSaverSaves(t *testing.T) {
+	store := corpus.NewMemory()
+	var seen []int64
+	s := &Saver{Store: store, Saved: func(a *article.Article) error {
+		seen = append(seen, a.ID)
+		return nil
+	}}
+	r := Result{Source: "gdelt", Articles: []*article.Article{
+		nota("gdelt", "https://www.eltiempo.com/educacion/matriculas-udea", "La UdeA abre matrículas para el segundo semestre"),
+		nota("gdelt", "", "Sin URL no se guarda"),
+		nota("gdelt", "https://www.elcolombiano.com/antioquia/paro-udea", "Estudiantes de la UdeA votan un paro indefinido"),
+	}}
+
+	saving, err := s.Save(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saving.Kept) != 2 || saving.Duplicates() != 0 {
+		t.Fatalf("guardados %d y colapsados %d, se esperaban 2 y 0", len(saving.Kept), saving.Duplicates())
+	}
+	if len(seen) != 2 || seen[0] == 0 || seen[1] == 0 {
+		t.Errorf("Saved recibió los IDs %v, se esperaban dos IDs asignados", seen)
+	}
+	got, err := store.GetByURL("https://www.elcolombiano.com/antioquia/paro-udea")
+	if err != nil || got.ID != saving.Kept[1].ID {
+		t.Errorf("GetByURL = %+v, %v", got, err)
+	}
+
+	if _, err := s.Save(r); err != nil {
+		t.Fatal(err)
+	}
+	all, err := store.ListFiltered(storage.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Errorf("%d artículos en el corpus tras guardar dos veces, se esperaban 2", len(all))
+	}
+}
+
+// Con deduplicación, la misma historia con otra URL se colapsa en la ya
+// guardada, tanto si estaba en el corpus como si llegó antes en la ronda.
+func TestSaverCollapses(t *testing.T) {
+	store := corpus.NewMemory()
+	old := nota("rss", "https://www.eltiempo.com/educacion/matriculas-udea?utm_source=rss", "La UdeA abre matrículas para el segundo semestre")
+	if err := store.SaveArticle(old); err != nil {
+		t.Fatal(err)
+	}
+
+	type collapse struct{ orig, dup, strategy string }
+	var collapsed []collapse
+	s := &Saver{Store: store, Dedup: dedup.New(), Collapsed: func(orig, a *article.Article, strategy string) error {
+		collapsed = append(collapsed, collapse{orig.URL, a.URL, strategy})
+		return nil
+	}}
+	results := []Result{
+		{Source: "gdelt", Articles: []*article.Article{
+			nota("gdelt", "https://eltiempo.com/educacion/matriculas-udea/", "UdeA: matrículas abiertas"),
+			nota("gdelt", "https://www.elcolombiano.com/antioquia/paro-estudiantes-udea", "Estudiantes de la Universidad de Antioquia votan un paro indefinido"),
+		}},
+		{Source: "newsapi", Articles: []*article.Article{
+			nota("newsapi", "https://www.elespectador.com/educacion/paro-udea", "Estudiantes de la Universidad de Antioquia votan un paro indefinido"),
+		}},
+	}
+	if err := s.Load(results); err != nil {
+		t.Fatal(err)
+	}
+
+	var kept []*article.Article
+	for _, r := range results {
+		saving, err := s.Save(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		kept = append(kept, saving.Kept...)
+	}
+	if len(kept) != 1 || kept[0].Source != "gdelt" {
+		t.Fatalf("se guardaron %d artículos, se esperaba solo la nota de El Colombiano", len(kept))
+	}
+	want := []collapse{
+		{old.URL, "https://eltiempo.com/educacion/matriculas-udea/", dedup.StrategyCanonical},
+		{kept[0].URL, "https://www.elespectador.com/educacion/paro-udea", dedup.StrategySimHash},
+	}
+	if len(collapsed) != len(want) {
+		t.Fatalf("colapsados %v, se esperaban %v", collapsed, want)
+	}
+	for i := range want {
+		if collapsed[i] != want[i] {
+			t.Errorf("colapso %d = %v, se esperaba %v", i, collapsed[i], want[i])
+		}
+	}
+	all, err := store.ListFiltered(storage.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Errorf("%d artículos en el corpus, se esperaban 2", len(all))
+	}
+}
+
+// Un error de Saved corta el guardado con lo hecho hasta ahí.
+func TestSaverStopsOnError(t *testing.T) {
+	store := corpus.NewMemory()
+	fail := errors.New("sin espacio")
+	s := &Saver{Store: store, Saved: func(*article.Article) error { return fail }}
+	saving, err := s.Save(Result{Source: "gdelt", Articles: []*article.Article{
+		nota("gdelt", "https://www.eltiempo.com/a", "Primera nota del lote de prueba"),
+		nota("gdelt", "https://www.eltiempo.com/b", "Segunda nota del lote de prueba"),
+	}})
+	if !errors.Is(err, fail) {
+		t.Fatalf("error %v, se esperaba %v", err, fail)
+	}
+	if len(saving.Kept) != 0 {
+		t.Errorf("%d artículos guardados según Save, se esperaban 0", len(saving.Kept))
+	}
+	if _, err := store.GetByURL("https://www.eltiempo.com/b"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("la segunda nota se guardó tras el error: %v", err)
+	}
+}
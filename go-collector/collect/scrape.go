@@ -0,0 +1,123 @@
+package collect
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler/scrape"
+	"go-collector/extract"
+	"go-collector/fetch"
+	"go-collector/query"
+)
+
+// Valores por defecto de cada sitio de la fuente scrape.
+const (
+	defaultScrapeDepth = 2
+	defaultScrapeDelay = 2 * time.Second
+	defaultScrapePages = 200
+)
+
+// scrapeSites recorre los sitios de la fuente, uno tras otro, y conserva las
+// notas publicadas en el rango (o sin fecha) que mencionan la consulta en el
+// título, el resumen o el cuerpo; sin consulta, todas. max_results limita el
+// total de notas. Una página o un sitio que no se pueden leer se omiten y
+// quedan en Result.Failed.
+func (c *Collector) scrapeSites(ctx context.Context, limit fetch.Middleware, src *config.Source, from, to time.Time, fetched func(int)) ([]*article.Article, error) {
+	var terms []string
+	for _, term := range query.Alternatives(src.Query) {
+		terms = append(terms, strings.ToLower(strings.Trim(term, `"`)))
+	}
+	sc := scrape.NewCrawler()
+	sc.Renderer = c.Renderer
+	sc.RenderPaths = c.RenderPaths
+	sc.Robots = c.Robots
+	sc.Visited = visitedIn(ctx)
+	sc.Fetcher.ConsentCookies = c.Fetch.ConsentCookies
+	c.usePages(limit, sc.Fetcher.Client)
+
+	var out []*article.Article
+	var failed []string
+	for _, cs := range src.Sites {
+		site, err := scrapeSite(cs)
+		if err != nil {
+			return out, fmt.Errorf("sitio %s: %w", cs.Name, err)
+		}
+		err = sc.Crawl(ctx, site, func(a *article.Article) bool {
+			fetched(1)
+			if inRange(a.Published, from, to) && wantLanguage(a, src.Languages) && mentionsBody(a, terms) {
+				a.Request = "scrape " + cs.Name
+				out = append(out, a)
+			}
+			return src.MaxResults <= 0 || len(out) < src.MaxResults
+		}, func(pageURL string, err error) {
+			fail(ctx, pageURL, err)
+		})
+		if ctx.Err() != nil {
+			return out, ctx.Err()
+		}
+		if err != nil {
+			failed = append(failed, err.Error())
+			fail(ctx, cs.Name, err)
+		}
+		if src.MaxResults > 0 && len(out) >= src.MaxResults {
+			break
+		}
+	}
+	if len(failed) == len(src.Sites) && len(failed) > 0 {
+		return nil, fmt.Errorf("ningún sitio respondió: %s", strings.Join(failed, "; "))
+	}
+	return out, nil
+}
+
+// scrapeSite arma el sitio a recorrer desde la configuración, con los
+// valores por defecto.
+func scrapeSite(cs config.Site) (scrape.Site, error) {
+	site := scrape.Site{
+		Name: cs.Name, Start: cs.Start, Title: cs.Title,
+		Rule:  extract.Rule{Body: cs.Body, Author: cs.Author, Date: cs.Date},
+		Depth: cs.Depth, AnyHost: cs.AnyHost, Delay: defaultScrapeDelay, MaxPages: cs.MaxPages,
+	}
+	if site.Depth == 0 {
+		site.Depth = defaultScrapeDepth
+	}
+	if site.MaxPages == 0 {
+		site.MaxPages = defaultScrapePages
+	}
+	if cs.Delay != "" {
+		d, err := time.ParseDuration(cs.Delay)
+		if err != nil {
+			return site, fmt.Errorf("delay inválido: %w", err)
+		}
+		site.Delay = d
+	}
+	var err error
+	if site.Links, err = regexp.Compile(cs.Links); err != nil {
+		return site, fmt.Errorf("links inválido: %w", err)
+	}
+	if cs.Follow != "" {
+		if site.Follow, err = regexp.Compile(cs.Follow); err != nil {
+			return site, fmt.Errorf("follow inválido: %w", err)
+		}
+	}
+	return site, nil
+}
+
+// mentionsBody es mentions mirando también el cuerpo: la fuente scrape
+// descarga la nota completa.
+func mentionsBody(a *article.Article, terms []string) bool {
+	if len(terms) == 0 {
+		return true
+	}
+	text := strings.ToLower(a.Title + "\n" + a.Summary + "\n" + a.Body)
+	for _, t := range terms {
+		if strings.Contains(text, t) {
+			return true
+		}
+	}
+	return false
+}
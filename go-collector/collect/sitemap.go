@@ -0,0 +1,168 @@
+package collect
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler/commoncrawl"
+	"go-collector/extract"
+	"go-collector/fetch"
+	"go-collector/query"
+	"go-collector/sitemap"
+)
+
+// defaultSitemapMax es cuántas notas se descargan como máximo si
+// max_results no lo indica: cada una es un pedido al medio.
+const defaultSitemapMax = 200
+
+// sitemapEntries lee los sitemaps de los medios de la fuente (los que
+// declara el robots.txt de cada dominio y los configurados en sitemaps) y
+// descarga el texto completo de las notas publicadas en el rango que
+// mencionan la consulta. El sitemap no trae el texto: la consulta se compara
+// con el título de la extensión de noticias, sus palabras clave y la URL
+// (universidad-de-antioquia); sin consulta, todas sirven. Un sitemap o una
+// página que no se pueden leer se omiten y quedan en Result.Failed; las
+// que excluye el robots.txt (ver Collector.Robots) o que otra fuente ya
+// descargó en la ronda se omiten sin pedirlas.
+func (c *Collector) sitemapEntries(ctx context.Context, limit fetch.Middleware, src *config.Source, from, to time.Time, fetched func(int)) ([]*article.Article, error) {
+	max := src.MaxResults
+	if max == 0 {
+		max = defaultSitemapMax
+	}
+	var terms, slugs []string
+	for _, term := range query.Alternatives(src.Query) {
+		term = strings.Trim(term, `"`)
+		terms = append(terms, strings.ToLower(term))
+		if s := commoncrawl.Slug(term); s != "" {
+			slugs = append(slugs, s)
+		}
+	}
+
+	sm := sitemap.NewClient()
+	sm.Robots = c.Robots
+	c.usePages(limit, sm.HTTP)
+	pages := fetch.NewFetcher()
+	pages.ConsentCookies = c.Fetch.ConsentCookies
+	c.usePages(limit, pages.Client)
+	var maps []string
+	for _, domain := range src.Domains {
+		found, err := sm.Discover(ctx, domain)
+		if err != nil {
+			return nil, err
+		}
+		maps = append(maps, found...)
+	}
+	maps = append(maps, src.Sitemaps...)
+
+	var out []*article.Article
+	var failed []string
+	visited := visitedIn(ctx)
+	for _, u := range maps {
+		entries, err := sm.FetchSince(ctx, u, from)
+		if err != nil {
+			if ctx.Err() != nil {
+				return out, ctx.Err()
+			}
+			failed = append(failed, err.Error())
+			fail(ctx, u, err)
+			continue
+		}
+		for _, e := range entries {
+			published := e.Published()
+			if published.IsZero() {
+				published = e.Modified()
+			}
+			loc := strings.TrimSpace(e.Loc)
+			if !inRange(published, from, to) || !inEntry(e, terms, slugs) {
+				continue
+			}
+			if c.Robots != nil {
+				ok, err := c.Robots.Allowed(ctx, loc)
+				if err != nil && ctx.Err() != nil {
+					return out, ctx.Err()
+				}
+				if !ok {
+					continue
+				}
+			}
+			if !visited.Add(loc) {
+				continue
+			}
+			a, err := c.sitemapPage(ctx, pages, loc, e)
+			if err != nil {
+				if ctx.Err() != nil {
+					return out, ctx.Err()
+				}
+				fail(ctx, loc, err)
+				continue
+			}
+			fetched(1)
+			if !wantLanguage(a, src.Languages) {
+				continue
+			}
+			a.Request = "sitemap " + u
+			out = append(out, a)
+			if len(out) >= max {
+				return out, nil
+			}
+		}
+	}
+	if len(failed) == len(maps) && len(failed) > 0 {
+		return nil, fmt.Errorf("ningún sitemap respondió: %s", strings.Join(failed, "; "))
+	}
+	return out, nil
+}
+
+// sitemapPage descarga la nota de la entrada y extrae su texto; si no
+// alcanza, la renderiza con c.Renderer (ver fetch.Escalate). Detrás de un
+// muro de consentimiento (aun con las cookies configuradas) la nota queda
+// con lo que da el sitemap, sin cuerpo y marcada con
+// article.IssueConsentWall.
+func (c *Collector) sitemapPage(ctx context.Context, pages *fetch.Fetcher, loc string, e sitemap.Entry) (*article.Article, error) {
+	page, err := pages.Fetch(ctx, loc)
+	if err != nil {
+		return nil, err
+	}
+	if page.ConsentWall {
+		a := e.Normalize(nil, &extract.Result{})
+		a.ExtractionIssue = article.IssueConsentWall
+		return a, nil
+	}
+	if page.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error HTTP: status code %d en %s", page.StatusCode, loc)
+	}
+	res, err := c.Extractor.Extract(loc, page.Body)
+	if err != nil {
+		return nil, err
+	}
+	body, res, err := fetch.Escalate(ctx, c.Renderer, c.RenderPaths, c.Extractor, loc, page.Body, res)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Printf("render %s: %v", loc, err)
+	}
+	return e.Normalize(body, res), nil
+}
+
+// inEntry indica si la entrada menciona alguno de los términos en su título
+// o palabras clave (en minúsculas) o en la URL (ya en forma de slug); sin
+// términos, todas sirven.
+func inEntry(e sitemap.Entry, terms, slugs []string) bool {
+	if len(terms) == 0 {
+		return true
+	}
+	text := strings.ToLower(e.News.Title + "\n" + e.News.Keywords)
+	for _, t := range terms {
+		if strings.Contains(text, t) {
+			return true
+		}
+	}
+	return inURL(e.Loc, slugs)
+}
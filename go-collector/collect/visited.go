@@ -0,0 +1,44 @@
+package collect
+
+import (
+	"context"
+
+	"go-collector/cachestat"
+	"go-collector/crawler"
+)
+
+type visitedKey struct{}
+
+// round devuelve ctx con un conjunto de páginas visitadas nuevo para la
+// ronda: las fuentes sitemap y scrape, que corren en paralelo, lo comparten
+// para no descargar dos veces la misma nota (queda en la fuente que la
+// descargó primero).
+func (c *Collector) round(ctx context.Context) context.Context {
+	v := crawler.NewVisited()
+	c.mu.Lock()
+	c.visited = v
+	c.mu.Unlock()
+	return context.WithValue(ctx, visitedKey{}, v)
+}
+
+// visitedIn devuelve el conjunto de la ronda de ctx, o uno propio si la
+// fuente se consulta fuera de Stream.
+func visitedIn(ctx context.Context) *crawler.Visited {
+	if v, ok := ctx.Value(visitedKey{}).(*crawler.Visited); ok {
+		return v
+	}
+	return crawler.NewVisited()
+}
+
+// Visits devuelve, de la última ronda, las notas de los medios pedidas
+// (fallos) y las que se omitieron porque otra fuente ya las había pedido
+// (aciertos).
+func (c *Collector) Visits() cachestat.Stats {
+	c.mu.Lock()
+	v := c.visited
+	c.mu.Unlock()
+	if v == nil {
+		return cachestat.Stats{}
+	}
+	return v.Stats()
+}
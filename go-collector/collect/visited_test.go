@@ -0,0 +1,97 @@
+package collect
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-collector/config"
+	"go-collector/robots"
+)
+
+// site sirve un medio sin red y cuenta las peticiones de cada ruta.
+type site struct {
+	mu    sync.Mutex
+	calls map[string]int
+	pages map[string]string
+}
+
+func (s *site) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	s.calls[req.URL.Path]++
+	s.mu.Unlock()
+	body, ok := s.pages[req.URL.Path]
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusNotFound
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func (s *site) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[path]
+}
+
+// Las fuentes sitemap y scrape, en paralelo en la misma ronda, descargan
+// una sola vez la nota que ambas encuentran, y ninguna pide la que excluye
+// el robots.txt.
+func TestVisitedAcrossSources(t *testing.T) {
+	now := time.Now().UTC()
+	published := now.Add(-time.Hour).Format(time.RFC3339)
+	note := `<html><head><title>La UdeA abre convocatoria</title>
+<meta property="article:published_time" content="` + published + `"></head>
+<body><article><p>` + strings.Repeat("La Universidad de Antioquia abre una convocatoria. ", 20) + `</p></article></body></html>`
+	s := &site{calls: map[string]int{}, pages: map[string]string{
+		"/robots.txt": "User-agent: *\nDisallow: /privado/\n",
+		"/sitemap.xml": `<?xml version="1.0"?><urlset xmlns:news="http://www.google.com/schemas/sitemap-news/0.9">
+<url><loc>https://www.udea.edu.co/noticias/nota-1</loc><news:news><news:publication_date>` + published + `</news:publication_date><news:title>La UdeA abre convocatoria</news:title></news:news></url>
+<url><loc>https://www.udea.edu.co/privado/nota-2</loc><news:news><news:publication_date>` + published + `</news:publication_date><news:title>Interna</news:title></news:news></url>
+</urlset>`,
+		"/":                `<html><body><a href="/noticias/nota-1">Nota</a> <a href="/privado/nota-2">Interna</a></body></html>`,
+		"/noticias/nota-1": note,
+		"/privado/nota-2":  note,
+	}}
+
+	rb := robots.NewCache(time.Minute)
+	rb.HTTP.Transport = s
+	c := &Collector{Transport: s, Robots: rb}
+	var sources config.Sources
+	sources.Sitemap = config.Source{Enabled: true, Sitemaps: []string{"https://www.udea.edu.co/sitemap.xml"}}
+	sources.Scrape = config.Source{Enabled: true, Sites: []config.Site{{
+		Name: "udea", Start: []string{"https://www.udea.edu.co/"}, Links: `/(noticias|privado)/`, Delay: "1ms",
+	}}}
+
+	total := 0
+	for _, r := range c.Enabled(context.Background(), &sources, "", now) {
+		if r.Err != nil {
+			t.Errorf("%s: %v", r.Source, r.Err)
+		}
+		total += len(r.Articles)
+	}
+	if total != 1 {
+		t.Errorf("%d artículos, se esperaba 1", total)
+	}
+	if n := s.count("/noticias/nota-1"); n != 1 {
+		t.Errorf("%d descargas de la nota, se esperaba 1", n)
+	}
+	if n := s.count("/privado/nota-2"); n != 0 {
+		t.Errorf("%d descargas de la nota excluida por robots.txt, se esperaba 0", n)
+	}
+	if n := s.count("/robots.txt"); n != 1 {
+		t.Errorf("%d descargas del robots.txt, se esperaba 1", n)
+	}
+	if v := c.Visits(); v.Hits != 1 {
+		t.Errorf("visitas %+v, se esperaba 1 nota omitida por ya descargada", v)
+	}
+}
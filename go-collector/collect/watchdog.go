@@ -0,0 +1,66 @@
+package collect
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrStalled indica que el watchdog canceló una fuente porque no recibió
+// páginas nuevas durante su stall_timeout.
+var ErrStalled = errors.New("la fuente dejó de avanzar")
+
+// watchdog vigila que una fuente siga recibiendo páginas.
+type watchdog struct {
+	last atomic.Int64 // UnixNano de la última página
+}
+
+type watchdogKey struct{}
+
+func (w *watchdog) touch() {
+	w.last.Store(time.Now().UnixNano())
+}
+
+// touch avisa al watchdog de ctx, si hay, que la fuente avanzó.
+func touch(ctx context.Context) {
+	if w, ok := ctx.Value(watchdogKey{}).(*watchdog); ok {
+		w.touch()
+	}
+}
+
+// watch devuelve un contexto que se cancela con causa ErrStalled si pasa
+// timeout sin que la fuente reciba una página (ver touch). stop libera el
+// watchdog; timeout <= 0 no vigila.
+func watch(ctx context.Context, timeout time.Duration) (wctx context.Context, stop func()) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	w := &watchdog{}
+	w.touch()
+	ctx = context.WithValue(ctx, watchdogKey{}, w)
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(max(timeout/10, 10*time.Millisecond))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if time.Since(time.Unix(0, w.last.Load())) > timeout {
+					cancel(ErrStalled)
+					return
+				}
+			}
+		}
+	}()
+	return ctx, func() {
+		close(done)
+		cancel(nil)
+	}
+}
@@ -0,0 +1,89 @@
+package collect
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler/youtube"
+	"go-collector/query"
+)
+
+// defaultYouTubeMax es cuántos videos se piden como máximo por idioma si
+// max_results no lo indica: cada página de búsqueda cuesta 100 unidades de
+// la cuota diaria.
+const defaultYouTubeMax = 200
+
+// youtubeVideos recorre la búsqueda de videos y pide los datos y contadores
+// de cada página. La consulta admite " OR " o "|" entre términos. El idioma
+// de la búsqueda solo prioriza resultados, así que con varios idiomas se hace
+// una búsqueda por idioma y se filtra al recibir por el idioma declarado del
+// video (sin declarar se conserva).
+func (c *Collector) youtubeVideos(ctx context.Context, y *youtube.Crawler, src *config.Source, from, to time.Time, pageSize int, fetched func(int)) ([]*article.Article, error) {
+	max := src.MaxResults
+	if max == 0 {
+		max = defaultYouTubeMax
+	}
+	q := strings.Join(query.Alternatives(src.Query), "|")
+	languages := src.Languages
+	if len(languages) == 0 {
+		languages = []string{""}
+	}
+
+	var out []*article.Article
+	seen := make(map[string]bool)
+	for _, lang := range languages {
+		opts := youtube.SearchOptions{Since: from, Until: to, Region: src.Region, Language: lang}
+		for n := 0; n < max; {
+			opts.MaxResults = min(pageSize, max-n)
+			page, err := y.Search(ctx, q, opts)
+			var resp *youtube.Response
+			if err == nil {
+				var ids []string
+				for _, id := range page.IDs() {
+					if !seen[id] {
+						seen[id] = true
+						ids = append(ids, id)
+					}
+				}
+				resp = &youtube.Response{}
+				if len(ids) > 0 {
+					resp, err = y.Videos(ctx, ids)
+				}
+			}
+			if err != nil && len(out) == 0 {
+				return nil, err
+			}
+			if err != nil {
+				// Lo ya recibido es válido: se guarda y se avisa del corte.
+				warn(ctx, "YouTube se detuvo con %d videos: %v", len(out), err)
+				if errors.Is(err, youtube.ErrQuota) {
+					warn(ctx, "la cuota de YouTube se renueva a medianoche (hora del Pacífico); baje max_results o consulte menos idiomas")
+				}
+				return out, nil
+			}
+			n += len(page.Items)
+			kept := 0
+			for _, a := range resp.Normalize() {
+				if !wantLanguage(a, src.Languages) || !inRange(a.Published, from, to) {
+					continue
+				}
+				a.Request = "youtube " + q
+				if lang != "" {
+					a.Request += " (" + lang + ")"
+				}
+				out = append(out, a)
+				kept++
+			}
+			fetched(kept)
+			if page.NextPageToken == "" || len(page.Items) == 0 {
+				break
+			}
+			opts.PageToken = page.NextPageToken
+		}
+	}
+	return out, nil
+}
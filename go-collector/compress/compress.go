@@ -0,0 +1,146 @@
+// Package compress comprime con zstd el texto que más ocupa en el corpus (el
+// cuerpo de los artículos y las páginas crudas) usando diccionarios
+// entrenados con el propio corpus: los textos de un mismo medio repiten
+// menús, avisos legales y fórmulas, y un diccionario con esos fragmentos
+// reduce mucho lo que ocupa cada uno por separado.
+//
+// Cada diccionario tiene un id que queda en los datos comprimidos con él, de
+// modo que entrenar uno nuevo no impide leer lo guardado con los anteriores:
+// basta con registrarlos todos (Register) antes de leer.
+package compress
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// magic son los primeros bytes de un frame zstd. Un texto UTF-8 nunca
+// empieza así (0xB5 es un byte de continuación).
+var magic = []byte{0x28, 0xB5, 0x2F, 0xFD}
+
+// IsCompressed indica si data es un frame zstd.
+func IsCompressed(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// DictID devuelve el id del diccionario con el que se comprimió el frame
+// data (0 si ninguno); ok es false si data no es un frame zstd.
+func DictID(data []byte) (id uint32, ok bool) {
+	var h zstd.Header
+	if !IsCompressed(data) || h.Decode(data) != nil {
+		return 0, false
+	}
+	return h.DictionaryID, true
+}
+
+// Dict es un diccionario zstd entrenado (ver Train).
+type Dict struct {
+	ID   uint32
+	Data []byte
+}
+
+var (
+	mu      sync.Mutex
+	dicts   = map[uint32][]byte{}
+	decoder *zstd.Decoder
+)
+
+// Register hace que Decode pueda leer lo comprimido con d. Los ids se
+// derivan del contenido, así que registrar diccionarios de varios corpus en
+// el mismo proceso no los confunde.
+func Register(d *Dict) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := dicts[d.ID]; ok {
+		return
+	}
+	dicts[d.ID] = d.Data
+	// El descompresor anterior puede estar en uso: se arma otro con todos.
+	decoder = nil
+}
+
+func currentDecoder() (*zstd.Decoder, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if decoder != nil {
+		return decoder, nil
+	}
+	all := make([][]byte, 0, len(dicts))
+	for _, d := range dicts {
+		all = append(all, d)
+	}
+	d, err := zstd.NewReader(nil, zstd.WithDecoderDicts(all...), zstd.WithDecoderConcurrency(0))
+	if err != nil {
+		return nil, fmt.Errorf("error preparando el descompresor: %w", err)
+	}
+	decoder = d
+	return d, nil
+}
+
+// Decode descomprime data si es un frame zstd; si no, lo devuelve tal cual.
+func Decode(data []byte) ([]byte, error) {
+	if !IsCompressed(data) {
+		return data, nil
+	}
+	d, err := currentDecoder()
+	if err != nil {
+		return nil, err
+	}
+	out, err := d.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error descomprimiendo: %w", err)
+	}
+	return out, nil
+}
+
+// Levels son los niveles de compresión aceptados, del más rápido al que más
+// comprime.
+var Levels = map[string]zstd.EncoderLevel{
+	"fastest": zstd.SpeedFastest,
+	"default": zstd.SpeedDefault,
+	"better":  zstd.SpeedBetterCompression,
+	"best":    zstd.SpeedBestCompression,
+}
+
+// Encoder comprime con un nivel y, si tiene, un diccionario. Se puede usar
+// desde varios goroutines.
+type Encoder struct {
+	enc *zstd.Encoder
+	// Dict es el diccionario con el que comprime; nil si ninguno.
+	Dict *Dict
+}
+
+// NewEncoder prepara un compresor del nivel indicado ("" es default) con d,
+// que puede ser nil. d queda registrado para Decode.
+func NewEncoder(level string, d *Dict) (*Encoder, error) {
+	if level == "" {
+		level = "default"
+	}
+	l, ok := Levels[level]
+	if !ok {
+		return nil, fmt.Errorf("nivel de compresión desconocido %q (use fastest, default, better o best)", level)
+	}
+	opts := []zstd.EOption{zstd.WithEncoderLevel(l)}
+	if d != nil {
+		Register(d)
+		opts = append(opts, zstd.WithEncoderDict(d.Data))
+	}
+	enc, err := zstd.NewWriter(nil, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error preparando el compresor: %w", err)
+	}
+	return &Encoder{enc: enc, Dict: d}, nil
+}
+
+// Encode comprime data.
+func (e *Encoder) Encode(data []byte) []byte {
+	return e.enc.EncodeAll(data, make([]byte, 0, len(data)/3))
+}
+
+// Close libera el compresor.
+func (e *Encoder) Close() error {
+	return e.enc.Close()
+}
@@ -0,0 +1,169 @@
+package compress
+
+import (
+	"container/heap"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// DefaultDictSize es el tamaño de diccionario que usa zstd por defecto.
+	DefaultDictSize = 110 << 10
+	// segmentSize es el largo de los fragmentos que se eligen para el
+	// diccionario.
+	segmentSize = 128
+	// dmer es el largo de las secuencias con las que se mide cuánto se
+	// repite un fragmento.
+	dmer = 8
+	// hashBits es el tamaño de las tablas de secuencias: a partir de unos
+	// millones de secuencias distintas, las colisiones no cambian la
+	// elección.
+	hashBits = 22
+	// maxTrainBytes acota lo que se lee de las muestras (zstd recomienda
+	// unas 100 veces el tamaño del diccionario).
+	maxTrainBytes = 16 << 20
+)
+
+// Train arma un diccionario de a lo sumo size bytes con las muestras: elige
+// los fragmentos cuyas secuencias aparecen en más muestras distintas, sin
+// contar dos veces lo que ya cubre otro fragmento elegido (una versión
+// simple del algoritmo COVER de zstd), y los deja con el más útil al final,
+// donde zstd los alcanza con desplazamientos más cortos. level es el nivel
+// con el que se va a comprimir, al que se ajustan las tablas de entropía.
+func Train(samples [][]byte, size int, level string) (*Dict, error) {
+	if size <= 0 {
+		size = DefaultDictSize
+	}
+	if level == "" {
+		level = "default"
+	}
+	l, ok := Levels[level]
+	if !ok {
+		return nil, fmt.Errorf("nivel de compresión desconocido %q (use fastest, default, better o best)", level)
+	}
+	total := 0
+	var used [][]byte
+	for _, s := range samples {
+		if len(s) < segmentSize || total >= maxTrainBytes {
+			continue
+		}
+		used = append(used, s)
+		total += len(s)
+	}
+	if len(used) < 10 || total < 4*size {
+		return nil, fmt.Errorf("muy pocas muestras para entrenar un diccionario: %d textos, %d bytes (se necesitan al menos 10 y %d bytes)", len(used), total, 4*size)
+	}
+
+	// En cuántas muestras aparece cada secuencia.
+	const mask = 1<<hashBits - 1
+	freq := make([]uint16, 1<<hashBits)
+	stamp := make([]uint32, 1<<hashBits)
+	for i, s := range used {
+		for j := 0; j+dmer <= len(s); j++ {
+			h := hashDmer(s[j:j+dmer]) & mask
+			if stamp[h] != uint32(i+1) {
+				stamp[h] = uint32(i + 1)
+				if freq[h] < 1<<16-1 {
+					freq[h]++
+				}
+			}
+		}
+	}
+
+	covered := make([]bool, 1<<hashBits)
+	score := func(seg []byte) int {
+		n := 0
+		for j := 0; j+dmer <= len(seg); j++ {
+			h := hashDmer(seg[j:j+dmer]) & mask
+			// Lo que aparece en una sola muestra no ayuda a las demás.
+			if !covered[h] && freq[h] > 1 {
+				n += int(freq[h])
+			}
+		}
+		return n
+	}
+	var candidates segments
+	for _, s := range used {
+		for j := 0; j+segmentSize <= len(s); j += segmentSize {
+			seg := s[j : j+segmentSize]
+			if n := score(seg); n > 0 {
+				candidates = append(candidates, segment{seg, n})
+			}
+		}
+	}
+	heap.Init(&candidates)
+
+	// Elección codiciosa: el puntaje de un candidato solo baja a medida que
+	// se cubren secuencias, así que se recalcula al sacarlo y, si sigue
+	// siendo el mejor, se elige.
+	var chosen [][]byte
+	length := 0
+	for candidates.Len() > 0 && length+segmentSize <= size {
+		best := heap.Pop(&candidates).(segment)
+		n := score(best.data)
+		if n == 0 {
+			continue
+		}
+		if candidates.Len() > 0 && n < candidates[0].score {
+			best.score = n
+			heap.Push(&candidates, best)
+			continue
+		}
+		for j := 0; j+dmer <= len(best.data); j++ {
+			covered[hashDmer(best.data[j:j+dmer])&mask] = true
+		}
+		chosen = append(chosen, best.data)
+		length += len(best.data)
+	}
+	if length < dmer {
+		return nil, fmt.Errorf("las muestras no tienen fragmentos en común para un diccionario")
+	}
+	history := make([]byte, 0, length)
+	for i := len(chosen) - 1; i >= 0; i-- {
+		history = append(history, chosen[i]...)
+	}
+
+	h := fnv.New32a()
+	h.Write(history)
+	// Los ids 1 a 32767 están reservados para diccionarios registrados en
+	// zstd; el resto se elige a partir del contenido.
+	id := 32768 + h.Sum32()%(1<<31-32768)
+	data, err := zstd.BuildDict(zstd.BuildDictOptions{
+		ID: id, Contents: used, History: history, Offsets: [3]int{1, 4, 8}, Level: l,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error armando el diccionario: %w", err)
+	}
+	return &Dict{ID: id, Data: data}, nil
+}
+
+func hashDmer(b []byte) uint32 {
+	// FNV-1a inline: se llama una vez por byte de las muestras.
+	h := uint32(2166136261)
+	for _, c := range b {
+		h ^= uint32(c)
+		h *= 16777619
+	}
+	return h
+}
+
+type segment struct {
+	data  []byte
+	score int
+}
+
+// segments es un heap de candidatos, el de mayor puntaje primero.
+type segments []segment
+
+func (s segments) Len() int           { return len(s) }
+func (s segments) Less(i, j int) bool { return s[i].score > s[j].score }
+func (s segments) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s *segments) Push(x any)        { *s = append(*s, x.(segment)) }
+func (s *segments) Pop() any {
+	old := *s
+	x := old[len(old)-1]
+	*s = old[:len(old)-1]
+	return x
+}
@@ -1,15 +0,0 @@
-package main
-
-// config.go
-// Configuración y estructuras comunes del proyecto.
-
-// TODO: Definir estructuras de configuración y cargar desde archivo/entorno
-
-type Config struct {
-    // Añadir campos según necesidad
-}
-
-func loadConfig() (*Config, error) {
-    // placeholder
-    return &Config{}, nil
-}
@@ -0,0 +1,640 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// config.go
+// Configuración del recolector, cargada desde un archivo YAML.
+
+// Config es la raíz del archivo de configuración.
+type Config struct {
+	Sources    Sources    `yaml:"sources"`
+	Output     Output     `yaml:"output"`
+	Storage    Storage    `yaml:"storage"`
+	Fetch      Fetch      `yaml:"fetch"`
+	Extract    Extract    `yaml:"extract"`
+	Feeds      Feeds      `yaml:"feeds"`
+	Campaigns  []Campaign `yaml:"campaigns"`
+	Relevance  Relevance  `yaml:"relevance"`
+	Dedup      Dedup      `yaml:"dedup"`
+	Embeddings Embeddings `yaml:"embeddings"`
+	Sentiment  Sentiment  `yaml:"sentiment"`
+	LLM        LLM        `yaml:"llm"`
+
+	// Accounts son las cuentas institucionales cuyos seguidores y biografía
+	// se registran a diario (collector accounts snapshot).
+	Accounts []Account `yaml:"accounts"`
+
+	// Reports se programan aparte de la recolección: cada reporte tiene su
+	// propio horario, plantilla, filtros y destinos.
+	Reports []Report `yaml:"reports"`
+	SMTP    SMTP     `yaml:"smtp"`
+
+	// Exports son los paquetes del corpus que se suben con su propio horario
+	// al servidor de otro equipo (collector exports); avisan por smtp.
+	Exports []Export `yaml:"exports"`
+
+	// Notifications avisan a webhooks de los artículos nuevos de cada ronda
+	// que cumplen sus reglas.
+	Notifications []Notification `yaml:"notifications"`
+
+	// Publishers son los términos de uso de las fuentes y medios: si el texto
+	// completo se puede guardar y redistribuir. Se registran en la tabla
+	// publishers del corpus y las exportaciones los aplican.
+	Publishers []Publisher `yaml:"publishers"`
+
+	// Profiles son capas que se aplican sobre la configuración base al
+	// elegir un perfil (COLLECTOR_PROFILE o --profile), ej: dev con fuentes
+	// de prueba y SQLite, prod con Postgres y credenciales reales. Una capa
+	// solo indica lo que cambia: las secciones y mapas se combinan clave por
+	// clave, las listas y los valores simples se reemplazan, y null borra.
+	Profiles map[string]*Config `yaml:"profiles,omitempty"`
+	// Profile es el perfil aplicado ("" si ninguno).
+	Profile string `yaml:"-"`
+}
+
+// Storage indica dónde se guarda el corpus.
+type Storage struct {
+	// ArchiveDir es el directorio de páginas crudas; vacío desactiva el archivo.
+	ArchiveDir  string      `yaml:"archive_dir"`
+	Encryption  Encryption  `yaml:"encryption"`
+	Compression Compression `yaml:"compression"`
+}
+
+// Compression activa zstd para el cuerpo de los artículos y las páginas
+// crudas nuevas, con el diccionario entrenado con collector compress train
+// si lo hay. Lo ya guardado se recomprime con collector compress apply.
+type Compression struct {
+	Enabled bool   `yaml:"enabled"`
+	Level   string `yaml:"level"` // fastest, default (por defecto), better o best
+}
+
+// Encryption activa el cifrado en reposo. Con una clave configurada se cifran
+// las páginas crudas nuevas y el autor de los artículos de AuthorSources.
+type Encryption struct {
+	KeyFile string `yaml:"key_file"` // archivo con la clave en base64
+	KeyEnv  string `yaml:"key_env"`  // o variable de entorno con la clave
+	// AuthorSources son las fuentes cuyo autor es un dato personal (ej: x).
+	AuthorSources []string `yaml:"author_sources"`
+}
+
+// Report define un reporte o resumen programado.
+type Report struct {
+	Name     string `yaml:"name"`
+	Schedule string `yaml:"schedule"` // expresión cron, ej: "0 7 * * *" o "@monthly"
+	Template string `yaml:"template"` // digest o summary
+	Format   string `yaml:"format"`   // html, markdown o pdf
+	// Period es la ventana hacia atrás desde la ejecución: duración de Go
+	// ("24h"), días ("7d") o meses ("1mo").
+	Period       string              `yaml:"period"`
+	Filters      ReportFilters       `yaml:"filters"`
+	Destinations []ReportDestination `yaml:"destinations"`
+}
+
+// PeriodStart devuelve el inicio de la ventana del reporte que termina en now.
+func (r Report) PeriodStart(now time.Time) (time.Time, error) {
+	return periodStart(r.Period, now)
+}
+
+// periodStart resta a now un período: duración de Go ("24h"), días ("7d") o
+// meses ("1mo"). El período vacío equivale a un día.
+func periodStart(period string, now time.Time) (time.Time, error) {
+	switch {
+	case period == "":
+		return now.Add(-24 * time.Hour), nil
+	case strings.HasSuffix(period, "mo"):
+		n, err := strconv.Atoi(strings.TrimSuffix(period, "mo"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("período inválido %q", period)
+		}
+		return now.AddDate(0, -n, 0), nil
+	case strings.HasSuffix(period, "d"):
+		n, err := strconv.Atoi(strings.TrimSuffix(period, "d"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("período inválido %q", period)
+		}
+		return now.AddDate(0, 0, -n), nil
+	default:
+		d, err := time.ParseDuration(period)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("período inválido %q (use 24h, 7d o 1mo)", period)
+		}
+		return now.Add(-d), nil
+	}
+}
+
+// ReportFilters restringe los artículos incluidos en el reporte.
+type ReportFilters struct {
+	Sources  []string `yaml:"sources"`
+	Language string   `yaml:"language"`
+	Query    string   `yaml:"query"`
+}
+
+// ReportDestination es a dónde se envía el reporte generado.
+type ReportDestination struct {
+	Type string   `yaml:"type"` // file o email
+	Path string   `yaml:"path"` // file: admite {name} y {date}
+	To   []string `yaml:"to"`   // email
+}
+
+// Export define una exportación programada: un paquete zip con los artículos
+// en Format y su manifiesto, subido a Upload.
+type Export struct {
+	Name     string `yaml:"name"`
+	Schedule string `yaml:"schedule"` // expresión cron, ej: "0 3 * * 1"
+	Format   string `yaml:"format"`   // jsonl (por defecto), json o csv
+	// Period es la ventana hacia atrás desde la ejecución, como en los
+	// reportes; vacío exporta el corpus completo.
+	Period  string        `yaml:"period"`
+	Filters ReportFilters `yaml:"filters"`
+	Upload  Upload        `yaml:"upload"`
+	// Notify son las direcciones a las que se avisa por correo cada subida
+	// exitosa.
+	Notify []string `yaml:"notify"`
+}
+
+// PeriodStart devuelve el inicio de la ventana de la exportación que
+// termina en now; cero si exporta el corpus completo.
+func (e Export) PeriodStart(now time.Time) (time.Time, error) {
+	if e.Period == "" {
+		return time.Time{}, nil
+	}
+	return periodStart(e.Period, now)
+}
+
+// Notification envía a un webhook (Slack, Discord, Teams u otro servicio)
+// los artículos que guarda cada ronda y cumplen todas sus reglas
+// configuradas: Keywords, Sources y el umbral de tono.
+type Notification struct {
+	Name string `yaml:"name"`
+	// URL es la del webhook; como suele llevar el secreto, puede leerse en
+	// cambio de la variable URLEnv. En telegram es la de la Bot API (por
+	// defecto https://api.telegram.org, o la de un servidor propio).
+	URL    string `yaml:"url"`
+	URLEnv string `yaml:"url_env"`
+	// Format es el cuerpo que espera el servicio: slack, discord, teams,
+	// telegram o json (por defecto: los artículos completos, para otros
+	// sistemas).
+	Format string `yaml:"format"`
+	// ChatID es el canal o grupo de Telegram (@canal o su id numérico, ej:
+	// -1001234567890), donde el bot tiene que poder publicar. El token del
+	// bot se lee de TokenEnv (por defecto TELEGRAM_BOT_TOKEN).
+	ChatID   string `yaml:"chat_id"`
+	TokenEnv string `yaml:"token_env"`
+	// Template es la línea de cada artículo en el mensaje, una plantilla de
+	// text/template sobre notify.Item (ej: "{{.Title}} ({{.Source}})");
+	// vacío, el título enlazado con la fuente y el tono. En telegram es HTML
+	// de Telegram (<b>, <i>, <a href>) y se usa html/template, que escapa
+	// los campos del artículo. No vale para json.
+	Template string `yaml:"template"`
+	// Keywords deja los artículos que mencionan alguna de estas palabras o
+	// frases en el título, el resumen o el cuerpo (sin distinguir mayúsculas
+	// ni tildes).
+	Keywords []string `yaml:"keywords"`
+	Sources  []string `yaml:"sources"`
+	// MinSentiment y MaxSentiment acotan el tono, de -1 a 1 (ej:
+	// max_sentiment: -0.3 avisa solo de las notas negativas). Un artículo en
+	// un idioma sin léxico no tiene tono y no pasa un umbral.
+	MinSentiment *float64 `yaml:"min_sentiment"`
+	MaxSentiment *float64 `yaml:"max_sentiment"`
+	// MinScore deja solo los artículos prioritarios: los que llegan a este
+	// puntaje de relevancia, el peso de la fuente (relevance.source_weights)
+	// por la cantidad de términos mencionados fuera de los contextos
+	// suprimidos. Vale el puntaje de la campaña y, si el artículo no lo
+	// trae, el que dan las palabras clave de la notificación; sin ninguno
+	// de los dos no pasa.
+	MinScore *float64 `yaml:"min_score"`
+	// Batch es cuántos artículos van como máximo en un mensaje (por defecto
+	// 20); con 1, cada artículo es un aviso aparte.
+	Batch int `yaml:"batch"`
+	// RateLimit es el cupo de mensajes, con la sintaxis de las fuentes (ej:
+	// "20/m"); los que no entran esperan su turno. En telegram, por defecto
+	// 20/m, el límite de Telegram para un canal o grupo.
+	RateLimit string `yaml:"rate_limit"`
+	// Retries es cuántas veces se reintenta un envío fallido (por defecto 3);
+	// si ninguno llega, el mensaje queda para collector notifications resend.
+	Retries int `yaml:"retries"`
+}
+
+// defaultTelegramAPI es la Bot API de Telegram.
+const defaultTelegramAPI = "https://api.telegram.org"
+
+// WebhookURL devuelve URL o, si está vacía, el valor de URLEnv. En telegram
+// es el método sendMessage del bot, con el token de TokenEnv; vacía si falta
+// el token.
+func (n Notification) WebhookURL() string {
+	if n.Format == "telegram" {
+		token := os.Getenv(n.BotTokenEnv())
+		if token == "" {
+			return ""
+		}
+		base := n.URL
+		if base == "" {
+			base = defaultTelegramAPI
+		}
+		return strings.TrimSuffix(base, "/") + "/bot" + token + "/sendMessage"
+	}
+	if n.URL == "" && n.URLEnv != "" {
+		return os.Getenv(n.URLEnv)
+	}
+	return n.URL
+}
+
+// BotTokenEnv es la variable con el token del bot de Telegram.
+func (n Notification) BotTokenEnv() string {
+	if n.TokenEnv != "" {
+		return n.TokenEnv
+	}
+	return "TELEGRAM_BOT_TOKEN"
+}
+
+// Publisher son los términos de uso de una fuente (Source, ej: x) o de un
+// medio (Domain, ej: eltiempo.com, con sus subdominios). Si varios alcanzan
+// a un artículo rige el más restrictivo.
+type Publisher struct {
+	Source string `yaml:"source"`
+	Domain string `yaml:"domain"`
+	// TOS es la categoría de los términos: open (licencia abierta), research
+	// (permiten el uso académico), api (los de una API comercial) o
+	// restricted (todos los derechos reservados).
+	TOS string `yaml:"tos"`
+	// Text es lo que se permite con el texto completo: share (guardarlo y
+	// redistribuirlo, por defecto), store (guardarlo para el análisis; las
+	// exportaciones lo omiten) o none (no guardarlo).
+	Text string `yaml:"text"`
+	// Exclude deja los artículos fuera de las exportaciones (ej: X solo
+	// permite redistribuir los ids de los tweets).
+	Exclude bool `yaml:"exclude"`
+	// License es la licencia o la URL de los términos, para el registro.
+	License string `yaml:"license"`
+}
+
+// Upload es el directorio remoto donde se deja el paquete.
+type Upload struct {
+	// URL es sftp://usuario@host[:puerto]/directorio o la URL https del
+	// directorio WebDAV.
+	URL string `yaml:"url"`
+	// KeyFile es la clave privada de SFTP; sin ella se usan las del agente
+	// y ~/.ssh. El host debe estar en known_hosts.
+	KeyFile string `yaml:"key_file"`
+	// Username y PasswordEnv (la variable con la contraseña) autentican en
+	// WebDAV.
+	Username    string `yaml:"username"`
+	PasswordEnv string `yaml:"password_env"`
+	// Retries es cuántas veces se reintenta una subida fallida (por defecto 3).
+	Retries int `yaml:"retries"`
+}
+
+// SMTP configura el servidor de correo para los destinos de tipo email.
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// LLM configura el modelo de lenguaje (API compatible con OpenAI) usado por el
+// comando experimental "ask".
+type LLM struct {
+	BaseURL string `yaml:"base_url"`
+	APIKey  string `yaml:"api_key"`
+	Model   string `yaml:"model"`
+}
+
+// Embeddings configura el proveedor de vectores de los artículos.
+type Embeddings struct {
+	// Provider: "openai" (un modelo de embeddings detrás de cualquier API
+	// compatible con OpenAI, incluidos servidores locales que sirven modelos
+	// ONNX/GGUF, como Ollama o text-embeddings-inference: búsqueda por
+	// significado) o "hash" (por defecto; feature hashing de palabras, sin
+	// red: similitud léxica, no semántica). "local" es el nombre anterior
+	// de hash.
+	Provider  string `yaml:"provider"`
+	BaseURL   string `yaml:"base_url"`
+	APIKey    string `yaml:"api_key"`
+	Model     string `yaml:"model"`
+	BatchSize int    `yaml:"batch_size"`
+}
+
+// Relevance configura el filtro de relevancia aplicado a los artículos recolectados.
+type Relevance struct {
+	// Suppress son reglas de contexto negativo para descartar falsos positivos.
+	Suppress []SuppressRule `yaml:"suppress"`
+
+	// SourceWeights pondera el puntaje según la confiabilidad de la fuente
+	// (fuente -> peso). Las fuentes no listadas pesan 1.
+	SourceWeights map[string]float64 `yaml:"source_weights"`
+}
+
+// Dedup colapsa la misma historia traída por varias fuentes en un solo
+// registro: se reconoce por la URL normalizada (sin utm_*, www, subdominio
+// móvil ni barra final) o por el título casi igual (SimHash) publicado en
+// fechas cercanas. Las demás apariciones quedan como procedencia del artículo.
+type Dedup struct {
+	Enabled bool `yaml:"enabled"`
+	// Strategies son las estrategias que se prueban, en orden, hasta que una
+	// encuentra el artículo ya guardado: url (la URL exacta), canonical (la
+	// URL normalizada), content_hash (el mismo texto completo) y simhash (el
+	// título casi igual). Un estudio que necesita cuidar las variantes deja
+	// solo las estrictas. Por defecto canonical y simhash.
+	Strategies []string `yaml:"strategies"`
+	// MaxDistance es la distancia máxima entre las huellas de dos títulos, en
+	// bits de 64 (por defecto 3).
+	MaxDistance int `yaml:"max_distance"`
+	// Window es la diferencia máxima de publicación, ej: "48h" o "2d" (por
+	// defecto 48h).
+	Window string `yaml:"window"`
+}
+
+// DedupStrategies son los nombres válidos en Dedup.Strategies, de la más
+// estricta a la más laxa.
+var DedupStrategies = []string{"url", "canonical", "content_hash", "simhash"}
+
+// SuppressRule descarta menciones en un contexto que indica otro significado.
+// Con Term y Context: se ignora cada mención de Term que tenga alguna palabra de
+// Context a menos de Window palabras. Con Pattern: se descarta todo artículo
+// cuyo texto coincida con la expresión regular.
+type SuppressRule struct {
+	Name    string   `yaml:"name"`
+	Term    string   `yaml:"term"`
+	Context []string `yaml:"context"`
+	Window  int      `yaml:"window"`
+	Pattern string   `yaml:"pattern"`
+}
+
+// Account es una cuenta monitoreada. Handle es el usuario en X (UdeA), el
+// usuario con su instancia en Mastodon (udea@mastodon.social) o el handle en
+// Bluesky (udea.bsky.social).
+type Account struct {
+	Platform string `yaml:"platform"` // x, mastodon o bluesky
+	Handle   string `yaml:"handle"`
+}
+
+// Campaign es una campaña de monitoreo: qué buscar, en qué idioma/región y en qué medios.
+type Campaign struct {
+	Name     string   `yaml:"name"`
+	Language string   `yaml:"language"` // ISO 639-1, ej: "es"
+	Region   string   `yaml:"region"`   // ISO 3166-1 alfa-2 o región del medio, ej: "co", "latam"
+	Outlets  []string `yaml:"outlets"`  // medios globales del catálogo de feeds, ej: bbc, nyt, guardian
+	Query    Query    `yaml:"query"`
+
+	// Ejecución con "collect --campaign". Cada campaña corre en paralelo con
+	// su propio cupo de peticiones y su propio corpus, para que una carga
+	// histórica pesada no frene el monitoreo diario.
+
+	// Sources son las fuentes a consultar (por defecto, las activadas en
+	// sources). La consulta se arma con Query; el resto de la configuración
+	// de cada fuente (clave, page_size, chunk...) se toma de sources.
+	Sources []string `yaml:"sources"`
+	// From y To reemplazan el rango de las fuentes (mismo formato: fecha o período).
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+	// Schedule es la expresión cron de la campaña en modo daemon; sin ella
+	// se usa el intervalo de --every.
+	Schedule string `yaml:"schedule"`
+	// RateLimit es el cupo de peticiones de la campaña a las APIs, con el
+	// formato de sources.<fuente>.rate_limit ("30", "450/15m"); vacío, sin
+	// límite. Se suma al de cada fuente.
+	RateLimit string `yaml:"rate_limit"`
+	// RateBurst es cuántas peticiones pueden salir seguidas (por defecto 1).
+	RateBurst int `yaml:"rate_burst"`
+	// Namespace separa el corpus de la campaña: reemplaza {campaign} en
+	// output.db y output.jsonl, o se agrega al nombre del archivo. Por
+	// defecto es Name.
+	Namespace string `yaml:"namespace"`
+}
+
+// NamespaceOrName devuelve el espacio de almacenamiento de la campaña.
+func (c Campaign) NamespaceOrName() string {
+	if c.Namespace != "" {
+		return c.Namespace
+	}
+	return c.Name
+}
+
+// Query describe qué buscar. Terms son las frases exactas; Aliases son nombres
+// alternativos (siglas, nombres cortos) de los que se generan hashtags y
+// variantes sin espacios para las fuentes sociales.
+type Query struct {
+	Terms   []string `yaml:"terms"`
+	Aliases []string `yaml:"aliases"`
+}
+
+// Feeds permite agregar o reemplazar ediciones regionales del catálogo incluido.
+type Feeds struct {
+	// Editions: medio -> ediciones. Si un medio aparece aquí, sus ediciones
+	// reemplazan por completo las del catálogo incluido.
+	Editions map[string][]FeedEdition `yaml:"editions"`
+}
+
+// FeedEdition es una edición (idioma/región) del feed de un medio.
+type FeedEdition struct {
+	Name     string `yaml:"name"`
+	Language string `yaml:"language"`
+	Region   string `yaml:"region"`
+	URL      string `yaml:"url"`
+}
+
+// Fetch agrupa las opciones del descargador de páginas.
+type Fetch struct {
+	// ConsentCookies son las cookies de consentimiento a enviar por dominio
+	// (dominio -> nombre -> valor) cuando un medio responde con un muro de cookies.
+	ConsentCookies map[string]map[string]string `yaml:"consent_cookies"`
+
+	// Domains define encabezados y cookies extra que se envían siempre a un
+	// dominio (y sus subdominios), ej: Accept-Language o la cookie que evita el
+	// chequeo anti-bots de los sitios institucionales.
+	Domains map[string]DomainRule `yaml:"domains"`
+
+	// Backoff controla el enfriamiento de dominios que respondieron 429/403.
+	Backoff Backoff `yaml:"backoff"`
+
+	// Expand controla la expansión de los enlaces acortados (t.co, bit.ly).
+	Expand Expand `yaml:"expand"`
+
+	// Render configura el navegador sin interfaz al que se escala cuando la
+	// descarga estática no alcanza.
+	Render Render `yaml:"render"`
+}
+
+// Render define el navegador sin interfaz (Chrome o Chromium) con que se
+// descargan las páginas que necesitan JavaScript. Solo se usa cuando la
+// descarga estática extrae poco o la página depende de scripts; sin Command
+// no se renderiza nunca.
+type Render struct {
+	// Command es el ejecutable del navegador (ej: chromium, google-chrome).
+	Command string `yaml:"command"`
+	// Args son banderas que se agregan a las del navegador (ej:
+	// --proxy-server=socks5://127.0.0.1:9050).
+	Args []string `yaml:"args"`
+	// Timeout es cuánto se espera cada página (por defecto 30s).
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxConcurrent es cuántos navegadores corren a la vez como máximo
+	// (por defecto 2); las demás páginas esperan su turno.
+	MaxConcurrent int `yaml:"max_concurrent"`
+}
+
+// Expand define cómo se expanden los enlaces acortados de los artículos
+// durante la recolección: se siguen sus redirecciones hasta salir del
+// acortador y el destino queda guardado para no volver a pedirlo.
+type Expand struct {
+	Disabled bool `yaml:"disabled"`
+	// Hosts se agregan a los acortadores conocidos (ej: el de un medio).
+	Hosts []string `yaml:"hosts"`
+	// MaxHops es cuántas redirecciones se siguen como máximo (por defecto 5).
+	MaxHops int `yaml:"max_hops"`
+	// RateLimit es el cupo de peticiones a los acortadores, con la sintaxis
+	// de las fuentes (ej: "5/s"); vacío no limita.
+	RateLimit string `yaml:"rate_limit"`
+}
+
+// Sentiment activa la etapa que puntúa el tono de cada artículo al
+// guardarlo (léxicos en español e inglés; ver collector sentiment).
+type Sentiment struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// Extract agrupa las reglas de extracción del texto completo.
+type Extract struct {
+	// Domains reemplaza la extracción genérica en los medios donde falla
+	// (dominio -> regla). Aplica también a los subdominios.
+	Domains map[string]ExtractRule `yaml:"domains"`
+}
+
+// ExtractRule son los selectores CSS del cuerpo, el autor y la fecha de un
+// dominio; los vacíos usan la extracción genérica. Cada regla debería tener
+// su página de prueba en <fixtures>/extract/<dominio>.html (ver selfcheck).
+type ExtractRule struct {
+	Body   string `yaml:"body"`
+	Author string `yaml:"author"`
+	Date   string `yaml:"date"`
+}
+
+// Backoff define el enfriamiento exponencial por dominio: cada bloqueo
+// consecutivo duplica la espera, desde Base hasta Max.
+type Backoff struct {
+	Base time.Duration `yaml:"base"`
+	Max  time.Duration `yaml:"max"`
+}
+
+// DomainRule son los encabezados y cookies a aplicar a un dominio.
+type DomainRule struct {
+	Headers map[string]string `yaml:"headers"`
+	Cookies map[string]string `yaml:"cookies"`
+}
+
+// Load lee, parsea y valida el archivo de configuración. Las claves
+// desconocidas y los tipos incorrectos son errores (un error de tipeo no debe
+// desactivar en silencio una opción); el error es un *ValidationError con
+// todos los problemas y sus líneas.
+func Load(path string) (*Config, error) {
+	return LoadProfile(path, os.Getenv("COLLECTOR_PROFILE"))
+}
+
+// LoadProfile es Load con un perfil explícito ("" para solo la base).
+func LoadProfile(path, profile string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo configuración: %w", err)
+	}
+	return parse(path, data, profile, os.Getenv)
+}
+
+// Parse es Load sobre un contenido ya leído, sin perfil ni variables de
+// entorno; name se usa en los mensajes.
+func Parse(name string, data []byte) (*Config, error) {
+	return parse(name, data, "", nil)
+}
+
+// parse decodifica, aplica el perfil y valida; con getenv se aplican después
+// las variables COLLECTOR_* (ver ApplyEnv), de modo que se valida la
+// configuración efectiva.
+func parse(name string, data []byte, profile string, getenv func(string) string) (*Config, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		// Error de sintaxis: no hay árbol que revisar.
+		return nil, &ValidationError{File: name, Problems: decodeErrors(err)}
+	}
+
+	// Con claves desconocidas o tipos incorrectos yaml.v3 igual decodifica el
+	// resto, así que se reportan junto con los problemas semánticos. Las
+	// capas de los perfiles se revisan aquí también, con sus líneas.
+	var cfg Config
+	var problems []Problem
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && err != io.EOF {
+		problems = decodeErrors(err)
+	}
+	if profile != "" {
+		problems = append(problems, cfg.applyProfile(&root, profile)...)
+	}
+	if getenv != nil {
+		if err := cfg.ApplyEnv(getenv); err != nil {
+			problems = append(problems, Problem{Field: "entorno", Message: err.Error()})
+		}
+	}
+	problems = append(problems, cfg.validate(locator{root: &root})...)
+	if len(problems) > 0 {
+		sort.SliceStable(problems, func(i, j int) bool { return problems[i].Line < problems[j].Line })
+		return nil, &ValidationError{File: name, Problems: problems}
+	}
+	return &cfg, nil
+}
+
+// applyProfile decodifica la capa del perfil sobre la configuración ya
+// cargada: yaml.v3 completa structs y mapas existentes y reemplaza listas y
+// valores simples, que es justo la semántica de capa.
+func (c *Config) applyProfile(root *yaml.Node, profile string) []Problem {
+	loc := locator{root: root}
+	if _, ok := c.Profiles[profile]; !ok {
+		names := make([]string, 0, len(c.Profiles))
+		for n := range c.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		msg := fmt.Sprintf("perfil desconocido %q", profile)
+		if len(names) > 0 {
+			msg += " (disponibles: " + strings.Join(names, ", ") + ")"
+		}
+		return []Problem{{Line: loc.line("profiles"), Field: "profiles", Message: msg}}
+	}
+	layer := loc.node("profiles", profile)
+	if layer == nil || layer.Kind != yaml.MappingNode {
+		// Perfil vacío (profiles: {dev: }): no cambia nada.
+		c.Profile = profile
+		return nil
+	}
+	if err := layer.Decode(c); err != nil {
+		return decodeErrors(err)
+	}
+	c.Profile = profile
+	return nil
+}
+
+// Hash identifica el contenido efectivo de la configuración (sin comentarios
+// ni formato): dos archivos que cargan lo mismo tienen el mismo hash.
+func (c *Config) Hash() string {
+	// Las capas de perfil no aplicadas no cambian el contenido efectivo.
+	effective := *c
+	effective.Profiles = nil
+	data, err := yaml.Marshal(&effective)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
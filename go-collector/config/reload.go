@@ -0,0 +1,238 @@
+package config
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Live guarda la configuración vigente de un proceso de larga duración. Los
+// lectores toman una instantánea con Get y la usan completa; la recarga la
+// reemplaza de una vez con Swap, así nadie ve una configuración a medias.
+type Live struct {
+	p atomic.Pointer[Config]
+}
+
+// NewLive crea el contenedor con la configuración inicial.
+func NewLive(cfg *Config) *Live {
+	l := &Live{}
+	l.p.Store(cfg)
+	return l
+}
+
+// Get devuelve la configuración vigente. No debe modificarse.
+func (l *Live) Get() *Config {
+	return l.p.Load()
+}
+
+// Swap instala cfg y devuelve la anterior.
+func (l *Live) Swap(cfg *Config) *Config {
+	return l.p.Swap(cfg)
+}
+
+// Watcher revisa periódicamente el archivo de configuración y, cuando su
+// contenido cambia y es válido, lo instala en Live. Se sondea el archivo en
+// vez de usar notificaciones del sistema para soportar editores que
+// reemplazan el archivo y volúmenes montados.
+type Watcher struct {
+	Path     string
+	Live     *Live
+	Interval time.Duration // por defecto 5 s
+
+	// OnReload se llama después de cada cambio aplicado, con el resumen de Diff.
+	OnReload func(old, cfg *Config, changes []string)
+	// OnError se llama si el archivo cambió pero no se pudo cargar; se
+	// mantiene la configuración anterior.
+	OnError func(err error)
+
+	sum [sha256.Size]byte
+}
+
+// Run sondea el archivo hasta que stop se cierre.
+func (w *Watcher) Run(stop <-chan struct{}) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	if data, err := os.ReadFile(w.Path); err == nil {
+		w.sum = sha256.Sum256(data)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			w.Check()
+		}
+	}
+}
+
+// Check relee el archivo una vez y aplica los cambios. Devuelve true si se
+// instaló una configuración nueva.
+func (w *Watcher) Check() bool {
+	data, err := os.ReadFile(w.Path)
+	if err != nil {
+		w.fail(fmt.Errorf("error leyendo configuración: %w", err))
+		return false
+	}
+	sum := sha256.Sum256(data)
+	if sum == w.sum {
+		return false
+	}
+	w.sum = sum
+
+	// Se mantiene el perfil con el que arrancó el proceso.
+	cfg, err := parse(w.Path, data, w.Live.Get().Profile, os.Getenv)
+	if err != nil {
+		w.fail(err)
+		return false
+	}
+	old := w.Live.Get()
+	changes := Diff(old, cfg)
+	if len(changes) == 0 {
+		// Solo cambiaron comentarios o formato.
+		return false
+	}
+	w.Live.Swap(cfg)
+	if w.OnReload != nil {
+		w.OnReload(old, cfg, changes)
+	}
+	return true
+}
+
+func (w *Watcher) fail(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+}
+
+// Diff resume en frases cortas qué cambió entre dos configuraciones: fuentes,
+// campañas, feeds, filtros y reportes con detalle; el resto por sección. Los
+// cambios de storage se marcan porque requieren reiniciar el proceso.
+func Diff(old, cfg *Config) []string {
+	var out []string
+
+	for i, n := range cfg.Sources.Named() {
+		o := old.Sources.Named()[i]
+		if reflect.DeepEqual(*o.Source, *n.Source) {
+			continue
+		}
+		switch {
+		case o.Enabled && !n.Enabled:
+			out = append(out, "fuente "+n.Name+" desactivada")
+		case !o.Enabled && n.Enabled:
+			out = append(out, "fuente "+n.Name+" activada")
+		default:
+			out = append(out, "fuente "+n.Name+": "+strings.Join(changedFields(*o.Source, *n.Source), ", "))
+		}
+	}
+
+	if old.Sources.Fixtures != cfg.Sources.Fixtures {
+		out = append(out, fmt.Sprintf("fixtures: %q -> %q", old.Sources.Fixtures, cfg.Sources.Fixtures))
+	}
+
+	out = append(out, diffNamed("campaña", campaignsByName(old.Campaigns), campaignsByName(cfg.Campaigns))...)
+	out = append(out, diffNamed("reporte", reportsByName(old.Reports), reportsByName(cfg.Reports))...)
+	out = append(out, diffNamed("exportación", exportsByName(old.Exports), exportsByName(cfg.Exports))...)
+
+	sections := []struct {
+		name     string
+		old, new any
+	}{
+		{"feeds", old.Feeds, cfg.Feeds},
+		{"relevance (filtros)", old.Relevance, cfg.Relevance},
+		{"dedup", old.Dedup, cfg.Dedup},
+		{"fetch", old.Fetch, cfg.Fetch},
+		{"output", old.Output, cfg.Output},
+		{"embeddings", old.Embeddings, cfg.Embeddings},
+		{"llm", old.LLM, cfg.LLM},
+		{"smtp", old.SMTP, cfg.SMTP},
+		{"publishers (términos de uso)", old.Publishers, cfg.Publishers},
+	}
+	for _, s := range sections {
+		if !reflect.DeepEqual(s.old, s.new) {
+			out = append(out, s.name+" modificado")
+		}
+	}
+	if !reflect.DeepEqual(old.Storage, cfg.Storage) {
+		out = append(out, "storage modificado (requiere reiniciar para aplicarse)")
+	}
+	return out
+}
+
+// changedFields lista los campos yaml distintos entre dos valores del mismo struct.
+func changedFields(a, b any) []string {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	var out []string
+	for i := 0; i < va.NumField(); i++ {
+		if reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			continue
+		}
+		name := strings.Split(va.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if name == "api_key" {
+			name = "api_key (credencial)"
+		}
+		out = append(out, name)
+	}
+	return out
+}
+
+// diffNamed compara listas de elementos con nombre: agregados, quitados y modificados.
+func diffNamed[T any](kind string, old, cur map[string]T) []string {
+	var out []string
+	for _, name := range sortedKeys(cur) {
+		o, ok := old[name]
+		switch {
+		case !ok:
+			out = append(out, "agregado: "+kind+" "+name)
+		case !reflect.DeepEqual(o, cur[name]):
+			out = append(out, kind+" "+name+": "+strings.Join(changedFields(o, cur[name]), ", "))
+		}
+	}
+	for _, name := range sortedKeys(old) {
+		if _, ok := cur[name]; !ok {
+			out = append(out, "eliminado: "+kind+" "+name)
+		}
+	}
+	return out
+}
+
+func campaignsByName(list []Campaign) map[string]Campaign {
+	m := make(map[string]Campaign, len(list))
+	for _, c := range list {
+		m[c.Name] = c
+	}
+	return m
+}
+
+func reportsByName(list []Report) map[string]Report {
+	m := make(map[string]Report, len(list))
+	for _, r := range list {
+		m[r.Name] = r
+	}
+	return m
+}
+
+func exportsByName(list []Export) map[string]Export {
+	m := make(map[string]Export, len(list))
+	for _, e := range list {
+		m[e.Name] = e
+	}
+	return m
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
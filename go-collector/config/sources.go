@@ -0,0 +1,640 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Sources son las fuentes que consulta "collector collect". Cada una se
+// activa por separado; las credenciales pueden venir del archivo o, mejor,
+// de variables de entorno (ver ApplyEnv y Source.Key).
+type Sources struct {
+	// Fixtures activa el modo de prueba: en vez de llamar a las APIs se leen
+	// respuestas guardadas de este directorio (<fuente>.json; rss/*.xml).
+	// Sirve para desarrollar sin credenciales ni cuota.
+	Fixtures string `yaml:"fixtures"`
+
+	Guardian Source `yaml:"guardian"`
+	NewsAPI  Source `yaml:"newsapi"`
+	// BingNews es la búsqueda de noticias de Bing; suele quedar desactivada
+	// como fallback de newsapi.
+	BingNews Source `yaml:"bingnews"`
+	// EventRegistry es la búsqueda de artículos de Event Registry
+	// (NewsAPI.ai); cada artículo llega con el evento que lo agrupa.
+	EventRegistry Source `yaml:"eventregistry"`
+	// Mediastack y Currents son agregadores de noticias; suelen quedar
+	// desactivados, como eslabones de la cadena de reemplazo (fallback).
+	Mediastack Source `yaml:"mediastack"`
+	Currents   Source `yaml:"currents"`
+	GDELT      Source `yaml:"gdelt"`
+	X          Source `yaml:"x"`
+	RSS        Source `yaml:"rss"`
+	// GoogleNews lee el feed de búsqueda de Google News de la edición de
+	// cada idioma en el país de region.
+	GoogleNews Source `yaml:"googlenews"`
+	Mastodon   Source `yaml:"mastodon"`
+	Bluesky    Source `yaml:"bluesky"`
+	YouTube    Source `yaml:"youtube"`
+	// OAI cosecha repositorios institucionales y archivos por OAI-PMH; query,
+	// si la hay, filtra los registros al recibirlos.
+	OAI Source `yaml:"oai"`
+	// CommonCrawl busca en el índice de Common Crawl las capturas de unos
+	// dominios, para cargas históricas más allá de la ventana de las APIs.
+	CommonCrawl Source `yaml:"commoncrawl"`
+	// Sitemap lee los sitemaps de noticias de unos medios y descarga el
+	// texto completo de las notas del rango que mencionan la consulta; cubre
+	// los medios sin una API utilizable.
+	Sitemap Source `yaml:"sitemap"`
+	// Scrape recorre los sitios descritos con selectores en sites, para
+	// agregar medios sin API ni sitemap sin escribir código.
+	Scrape Source `yaml:"scrape"`
+	// Mock genera artículos sintéticos para pruebas de carga; max_results es
+	// la cantidad por corrida y page_size el tamaño de cada lote.
+	Mock Source `yaml:"mock"`
+}
+
+// Source configura una fuente.
+type Source struct {
+	Enabled bool `yaml:"enabled"`
+
+	// APIKey es la credencial (API key o bearer token). Si está vacía se lee
+	// de la variable APIKeyEnv, o de la variable por defecto de la fuente.
+	APIKey    string `yaml:"api_key"`
+	APIKeyEnv string `yaml:"api_key_env"`
+
+	// Query usa la sintaxis de la fuente, ej: `"Universidad de Antioquia" OR UdeA`.
+	Query     string   `yaml:"query"`
+	Languages []string `yaml:"languages"` // ISO 639-1, ej: [es, en]
+
+	// From y To delimitan el rango: fecha (2006-01-02) o período hacia atrás
+	// desde hoy ("30d", "1mo", "72h"). To vacío es ahora.
+	From     string `yaml:"from"`
+	To       string `yaml:"to"`
+	PageSize int    `yaml:"page_size"`
+	// Incremental hace que cada ronda empiece donde terminó la anterior (la
+	// publicación más reciente que trajo la fuente, con un margen) en vez de
+	// en From, que vale para la primera ronda. Se reinicia si cambia Query o
+	// Languages.
+	Incremental bool `yaml:"incremental"`
+	// After es el inicio de la ronda incremental; no se configura: lo fija el
+	// recolector a partir de la ronda anterior.
+	After time.Time `yaml:"-"`
+	// Chunk parte el rango en sub-rangos consultados por separado ("7d",
+	// "24h"); para GDELT, que devuelve como máximo 250 artículos por consulta.
+	Chunk string `yaml:"chunk"`
+	// Translate pide también los títulos traducidos al inglés de los
+	// artículos en otros idiomas (solo gdelt); el original se conserva en
+	// title y la traducción en title_translated.
+	Translate bool `yaml:"translate"`
+	// StallTimeout cancela la fuente si pasa este lapso sin recibir una
+	// página ("2m"); lo ya recibido se guarda y la corrida queda parcial.
+	// Vacío, sin límite. Debe superar las esperas por cupo de la API.
+	StallTimeout string `yaml:"stall_timeout"`
+	// MaxResults es el total máximo por corrida recorriendo páginas (0: el
+	// tope de la fuente, ej: 100 en el plan gratuito de NewsAPI, 500 en X).
+	MaxResults int `yaml:"max_results"`
+
+	// RateLimit es el cupo de peticiones a la fuente: un número solo es por
+	// minuto ("30") y si no, peticiones por lapso ("1/s", "450/15m"); vacío o
+	// 0, sin límite. Las fuentes se consultan en paralelo: cada una respeta el
+	// suyo, compartido por todas las páginas y tramos de la corrida.
+	RateLimit string `yaml:"rate_limit"`
+	// RateBurst es cuántas peticiones pueden salir seguidas mientras sobre
+	// cupo (por defecto 1: siempre espaciadas).
+	RateBurst int `yaml:"rate_burst"`
+
+	// Feeds son las URLs de los feeds (solo rss).
+	Feeds []string `yaml:"feeds"`
+	// Adaptive ajusta cada cuánto se lee cada feed a lo que publica (solo
+	// rss); sin él, cada ronda los lee todos.
+	Adaptive *Adaptive `yaml:"adaptive"`
+
+	// Instances son las instancias que se consultan (solo mastodon).
+	Instances []Instance `yaml:"instances"`
+
+	// Repositories son los repositorios que se cosechan (solo oai).
+	Repositories []Repository `yaml:"repositories"`
+
+	// Domains son los medios cuyas capturas se buscan, con sus subdominios
+	// (commoncrawl), o cuyos sitemaps se leen según su robots.txt (sitemap),
+	// ej: [eltiempo.com, larepublica.co].
+	Domains []string `yaml:"domains"`
+	// Sitemaps son sitemaps que se leen además de los de Domains, para los
+	// medios que no los declaran en su robots.txt (solo sitemap).
+	Sitemaps []string `yaml:"sitemaps"`
+
+	// Sites son los sitios que se recorren (solo scrape).
+	Sites []Site `yaml:"sites"`
+
+	// Topics deja solo las publicaciones que la plataforma clasificó en
+	// alguno de estos temas (solo x: context_annotations). Cada uno se
+	// compara, sin distinguir mayúsculas, con el tema y con su clase (ej:
+	// [Education, "Higher education"]). Vacío, no filtra.
+	Topics []string `yaml:"topics"`
+	// Retweets incluye los retweets en la búsqueda (solo x): no se guardan
+	// como artículos sino como aristas de la red de difusión del tweet
+	// original (collector network). Cada retweet consume cuota de lectura.
+	Retweets bool `yaml:"retweets"`
+
+	// Handle es la cuenta con que se inicia sesión (solo bluesky, ej:
+	// udea.bsky.social); la credencial es una contraseña de aplicación.
+	Handle string `yaml:"handle"`
+
+	// Region es el país de la búsqueda (ISO 3166-1 alfa-2, ej: co): en
+	// youtube restringe a los videos disponibles en él, en googlenews elige
+	// la edición y en bingnews, con cada idioma, el mercado (es-CO).
+	Region string `yaml:"region"`
+
+	// Fallback es la fuente que se consulta en lugar de esta, con su misma
+	// consulta, idiomas y rango, cuando esta agota su cuota sin traer nada
+	// (ej: bingnews para newsapi). La de reemplazo aporta su credencial y su
+	// región, y no necesita estar activada. Con una lista (fallback:
+	// [bingnews, mediastack, currents]) es una cadena: cada una se consulta
+	// solo si la anterior tampoco sirvió.
+	Fallback Chain `yaml:"fallback"`
+	// FallbackOn es cuándo se pasa al reemplazo: "quota" (por defecto) si la
+	// fuente agota su cuota sin traer nada, "error" ante cualquier error sin
+	// resultados y "empty" además si no trae nada aunque no falle. Vale
+	// igual para cada eslabón de la cadena.
+	FallbackOn string `yaml:"fallback_on"`
+
+	// Cost es la tarifa del plan contratado, para estimar el gasto de cada
+	// mes (collector usage); sin ella la fuente cuenta como gratuita.
+	Cost *Cost `yaml:"cost"`
+
+	// Rate son los artículos por segundo que entrega mock (0: sin pausa) y
+	// Seed su semilla (0: al azar; otra fija la secuencia generada).
+	Rate float64 `yaml:"rate"`
+	Seed uint64  `yaml:"seed"`
+}
+
+// Chain es una lista de fuentes que en YAML también puede escribirse como
+// una sola (fallback: bingnews).
+type Chain []string
+
+func (c *Chain) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		*c = nil
+		if n.Value != "" {
+			*c = Chain{n.Value}
+		}
+		return nil
+	}
+	var names []string
+	if err := n.Decode(&names); err != nil {
+		return err
+	}
+	*c = names
+	return nil
+}
+
+// Valores de Source.FallbackOn.
+const (
+	FallbackOnQuota = "quota"
+	FallbackOnError = "error"
+	FallbackOnEmpty = "empty"
+)
+
+// Instance es una instancia de Mastodon (o compatible). Los hashtags de la
+// consulta se piden a todas; la búsqueda de texto completo solo a las que
+// tienen token y la habilitan.
+type Instance struct {
+	URL string `yaml:"url"` // ej: https://mastodon.social
+	// Token es el token de acceso de una cuenta en la instancia; mejor en la
+	// variable que indica TokenEnv.
+	Token    string `yaml:"token"`
+	TokenEnv string `yaml:"token_env"`
+	// RateLimit es el cupo de peticiones a la instancia, con la sintaxis de
+	// las fuentes; vacío usa el rate_limit de la fuente o, si tampoco hay,
+	// DefaultInstanceRate.
+	RateLimit string `yaml:"rate_limit"`
+	RateBurst int    `yaml:"rate_burst"`
+}
+
+// Site describe un sitio para la fuente scrape: desde qué páginas empezar,
+// qué enlaces son notas y dónde están sus datos. Los selectores son CSS; los
+// vacíos usan la extracción genérica (ver extract.domains).
+type Site struct {
+	Name  string   `yaml:"name"`
+	Start []string `yaml:"start"` // páginas de inicio, ej: la portada o una sección
+	// Links es la expresión regular de las URLs de las notas y Follow la de
+	// las páginas que solo se recorren para llegar a ellas (secciones,
+	// paginación); sin Follow se siguen solo los enlaces a notas.
+	Links  string `yaml:"links"`
+	Follow string `yaml:"follow"`
+
+	Title  string `yaml:"title"`
+	Body   string `yaml:"body"`
+	Date   string `yaml:"date"`
+	Author string `yaml:"author"`
+
+	// Depth es cuántos enlaces se siguen desde start (por defecto 2).
+	Depth int `yaml:"depth"`
+	// AnyHost sigue también los enlaces a otros hosts; por defecto solo a
+	// los de las páginas de start.
+	AnyHost bool `yaml:"any_host"`
+	// Delay es la pausa entre dos páginas del sitio (por defecto "2s").
+	Delay string `yaml:"delay"`
+	// MaxPages es cuántas páginas se descargan como máximo por corrida (por
+	// defecto 200).
+	MaxPages int `yaml:"max_pages"`
+}
+
+// Repository es un repositorio OAI-PMH. Sets limita la cosecha a esas
+// colecciones (setSpec); vacío, se cosecha el repositorio entero.
+type Repository struct {
+	URL  string   `yaml:"url"` // ej: https://bibliotecadigital.udea.edu.co/oai/request
+	Sets []string `yaml:"sets"`
+}
+
+// DefaultInstanceRate es el cupo por defecto de Mastodon para peticiones
+// desde una misma IP.
+const DefaultInstanceRate = "300/5m"
+
+// Credential devuelve el token de la instancia ("" si no tiene).
+func (i Instance) Credential() string {
+	if i.Token != "" {
+		return i.Token
+	}
+	if i.TokenEnv != "" {
+		return os.Getenv(i.TokenEnv)
+	}
+	return ""
+}
+
+// Cost es la tarifa de una API paga: Monthly es el cargo fijo del plan, que
+// cubre Included peticiones al mes, y PerThousand lo que cuesta cada mil
+// peticiones por encima de ellas (un plan solo por uso tiene Monthly e
+// Included en 0). Currency es la moneda de los montos (por defecto USD).
+type Cost struct {
+	Monthly     float64 `yaml:"monthly"`
+	Included    int     `yaml:"included"`
+	PerThousand float64 `yaml:"per_1000"`
+	Currency    string  `yaml:"currency"`
+}
+
+// Estimate es el costo de un mes con requests peticiones.
+func (c *Cost) Estimate(requests int) float64 {
+	extra := max(0, requests-c.Included)
+	return c.Monthly + float64(extra)/1000*c.PerThousand
+}
+
+// Unit es la moneda de los montos.
+func (c *Cost) Unit() string {
+	if c.Currency == "" {
+		return "USD"
+	}
+	return strings.ToUpper(c.Currency)
+}
+
+// NamedSource es una fuente con su nombre de configuración.
+type NamedSource struct {
+	Name string
+	*Source
+}
+
+// defaultKeyEnv es la variable de entorno de la credencial de cada fuente.
+var defaultKeyEnv = map[string]string{
+	"guardian":      "GUARDIAN_API_KEY",
+	"newsapi":       "NEWSAPI_KEY",
+	"bingnews":      "BING_SEARCH_KEY",
+	"eventregistry": "EVENTREGISTRY_API_KEY",
+	"mediastack":    "MEDIASTACK_ACCESS_KEY",
+	"currents":      "CURRENTS_API_KEY",
+	"x":             "X_BEARER_TOKEN",
+	"bluesky":       "BLUESKY_APP_PASSWORD",
+	"youtube":       "YOUTUBE_API_KEY",
+}
+
+// Named devuelve las fuentes en orden fijo.
+func (s *Sources) Named() []NamedSource {
+	return []NamedSource{
+		{"guardian", &s.Guardian},
+		{"newsapi", &s.NewsAPI},
+		{"bingnews", &s.BingNews},
+		{"eventregistry", &s.EventRegistry},
+		{"mediastack", &s.Mediastack},
+		{"currents", &s.Currents},
+		{"gdelt", &s.GDELT},
+		{"x", &s.X},
+		{"rss", &s.RSS},
+		{"googlenews", &s.GoogleNews},
+		{"mastodon", &s.Mastodon},
+		{"bluesky", &s.Bluesky},
+		{"youtube", &s.YouTube},
+		{"oai", &s.OAI},
+		{"commoncrawl", &s.CommonCrawl},
+		{"sitemap", &s.Sitemap},
+		{"scrape", &s.Scrape},
+		{"mock", &s.Mock},
+	}
+}
+
+// Get devuelve la fuente con ese nombre, o nil si no existe.
+func (s *Sources) Get(name string) *Source {
+	for _, n := range s.Named() {
+		if n.Name == name {
+			return n.Source
+		}
+	}
+	return nil
+}
+
+// NeedsKey indica si la fuente requiere credencial.
+func NeedsKey(name string) bool {
+	_, ok := defaultKeyEnv[name]
+	return ok
+}
+
+// KeyEnv es la variable de entorno de la que se lee la credencial.
+func (s *Source) KeyEnv(name string) string {
+	if s.APIKeyEnv != "" {
+		return s.APIKeyEnv
+	}
+	return defaultKeyEnv[name]
+}
+
+// Key resuelve la credencial de la fuente: api_key o la variable de entorno.
+func (s *Source) Key(name string) (string, error) {
+	if s.APIKey != "" {
+		return s.APIKey, nil
+	}
+	env := s.KeyEnv(name)
+	if env == "" {
+		return "", nil
+	}
+	if v := os.Getenv(env); v != "" {
+		return v, nil
+	}
+	return "", fmt.Errorf("la fuente %s requiere credencial: defina %s o sources.%s.api_key", name, env, name)
+}
+
+// QueryKey identifica la consulta de la fuente (consulta e idiomas) para las
+// rondas incrementales: si cambia, la marca de agua guardada no vale.
+func (s *Source) QueryKey() string {
+	return s.Query + "|" + strings.Join(s.Languages, ",")
+}
+
+// Range devuelve el rango de fechas de la fuente; from queda en cero si no se
+// configuró, para que cada fuente aplique su propio rango por defecto. En una
+// ronda incremental from es After, si es posterior.
+func (s *Source) Range(now time.Time) (from, to time.Time, err error) {
+	to = now
+	if s.To != "" {
+		if to, err = parseBound(s.To, now, true); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("to inválido: %w", err)
+		}
+	}
+	if s.From != "" {
+		if from, err = parseBound(s.From, now, false); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("from inválido: %w", err)
+		}
+	}
+	if !from.IsZero() && from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from (%s) es posterior a to (%s)", s.From, s.To)
+	}
+	if s.After.After(from) && s.After.Before(to) {
+		from = s.After
+	}
+	return from, to, nil
+}
+
+// parseBound interpreta una fecha o un período relativo a now. Una fecha usada
+// como fin incluye el día completo (sin pasar de now).
+func parseBound(v string, now time.Time, end bool) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		if end {
+			t = t.Add(24*time.Hour - time.Second)
+			if t.After(now) {
+				t = now
+			}
+		}
+		return t, nil
+	}
+	t, err := periodStart(v, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q no es una fecha (2006-01-02) ni un período (30d, 1mo, 72h)", v)
+	}
+	return t, nil
+}
+
+// StallSpan devuelve la duración de StallTimeout (0 si no está configurado).
+func (s *Source) StallSpan() (time.Duration, error) {
+	if s.StallTimeout == "" {
+		return 0, nil
+	}
+	return ParseSpan(s.StallTimeout)
+}
+
+// ChunkSpan devuelve la duración de Chunk (0 si no está configurado).
+func (s *Source) ChunkSpan() (time.Duration, error) {
+	if s.Chunk == "" {
+		return 0, nil
+	}
+	return ParseSpan(s.Chunk)
+}
+
+// Adaptive es la frecuencia adaptativa de los feeds: cada uno se vuelve a
+// leer cuando, a su ritmo reciente de entradas nuevas, se espera una, pero
+// nunca antes de Min ni después de Max ("15m", "24h", "2d"). Las rondas que
+// llegan antes lo saltean, así que se recolecta con --every o --schedule
+// cada Min o menos.
+type Adaptive struct {
+	Min string `yaml:"min"` // por defecto 15m
+	Max string `yaml:"max"` // por defecto 24h
+}
+
+// Bounds devuelve los límites de la frecuencia.
+func (a *Adaptive) Bounds() (lo, hi time.Duration, err error) {
+	lo, hi = 15*time.Minute, 24*time.Hour
+	if a.Min != "" {
+		if lo, err = ParseSpan(a.Min); err != nil {
+			return 0, 0, err
+		}
+	}
+	if a.Max != "" {
+		if hi, err = ParseSpan(a.Max); err != nil {
+			return 0, 0, err
+		}
+	}
+	if lo > hi {
+		return 0, 0, fmt.Errorf("min (%s) es mayor que max (%s)", lo, hi)
+	}
+	return lo, hi, nil
+}
+
+// ParseSpan interpreta un lapso como duración de Go ("72h") o en días ("7d").
+func ParseSpan(v string) (time.Duration, error) {
+	if n, ok := strings.CutSuffix(v, "d"); ok {
+		days, err := strconv.Atoi(n)
+		if err == nil && days > 0 {
+			return time.Duration(days) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(v); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("lapso inválido %q (use 7d o 24h)", v)
+}
+
+// Rate es un cupo de peticiones: N cada Per. El valor cero no limita.
+type Rate struct {
+	N   int
+	Per time.Duration
+}
+
+// Limited indica si el cupo limita algo.
+func (r Rate) Limited() bool {
+	return r.N > 0 && r.Per > 0
+}
+
+// ParseRate interpreta un cupo: "30" son peticiones por minuto y "1/s",
+// "450/15m" o "1000/1d" peticiones por lapso. "" y "0" no limitan.
+func ParseRate(v string) (Rate, error) {
+	v = strings.TrimSpace(v)
+	if v == "" || v == "0" {
+		return Rate{}, nil
+	}
+	count, per, found := strings.Cut(v, "/")
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || n <= 0 {
+		return Rate{}, fmt.Errorf("cupo inválido %q (use 30, 1/s o 450/15m)", v)
+	}
+	if !found {
+		return Rate{N: n, Per: time.Minute}, nil
+	}
+	per = strings.TrimSpace(per)
+	if strings.HasSuffix(per, "min") {
+		per = strings.TrimSuffix(per, "in")
+	}
+	if per != "" && (per[0] < '0' || per[0] > '9') {
+		per = "1" + per
+	}
+	d, err := ParseSpan(per)
+	if err != nil {
+		return Rate{}, fmt.Errorf("cupo inválido %q (use 30, 1/s o 450/15m)", v)
+	}
+	return Rate{N: n, Per: d}, nil
+}
+
+// Limit devuelve el cupo de RateLimit.
+func (s *Source) Limit() (Rate, error) {
+	return ParseRate(s.RateLimit)
+}
+
+// Output indica a dónde se escriben los artículos recolectados.
+type Output struct {
+	// DB es la base del corpus (por defecto corpus.db).
+	DB string `yaml:"db"`
+	// JSONL escribe además cada corrida en un archivo; admite {source} y {date}.
+	JSONL string `yaml:"jsonl"`
+	// Postgres replica los artículos recolectados en una base compartida
+	// (mismo esquema que migrate-store). Mejor en COLLECTOR_OUTPUT_POSTGRES.
+	Postgres string `yaml:"postgres"`
+	// Elasticsearch indexa además los artículos recolectados en un clúster
+	// Elasticsearch u OpenSearch (ver collector index).
+	Elasticsearch Elasticsearch `yaml:"elasticsearch"`
+	// Kafka publica además en un tópico los artículos que guarda cada ronda.
+	Kafka Kafka `yaml:"kafka"`
+}
+
+// Elasticsearch es el clúster donde se indexan los artículos. Sin URL no se
+// indexa.
+type Elasticsearch struct {
+	// URL del clúster, ej: https://localhost:9200. COLLECTOR_OUTPUT_ELASTICSEARCH
+	// la reemplaza.
+	URL   string `yaml:"url"`
+	Index string `yaml:"index"` // por defecto collector-articles
+	// Username y PasswordEnv (la variable con la contraseña), o APIKeyEnv
+	// (la variable con una API key), autentican en el clúster.
+	Username    string `yaml:"username"`
+	PasswordEnv string `yaml:"password_env"`
+	APIKeyEnv   string `yaml:"api_key_env"`
+	// BatchSize es cuántos artículos van en cada pedido _bulk (por defecto 500).
+	BatchSize int `yaml:"batch_size"`
+}
+
+// Kafka es el tópico donde se publican los artículos guardados. Sin brokers
+// no se publica.
+type Kafka struct {
+	// Brokers son los host:puerto de arranque del clúster.
+	// COLLECTOR_OUTPUT_KAFKA (separados por comas) los reemplaza.
+	Brokers []string `yaml:"brokers"`
+	Topic   string   `yaml:"topic"` // por defecto collector-articles
+}
+
+// ApplyEnv aplica las variables COLLECTOR_<FUENTE>_<CAMPO> sobre la
+// configuración, ej: COLLECTOR_GUARDIAN_ENABLED=true,
+// COLLECTOR_NEWSAPI_API_KEY, COLLECTOR_GDELT_QUERY, COLLECTOR_X_PAGE_SIZE,
+// COLLECTOR_RSS_FEEDS y COLLECTOR_MASTODON_INSTANCES (separadas por comas). Además COLLECTOR_FIXTURES,
+// COLLECTOR_OUTPUT_DB, COLLECTOR_OUTPUT_JSONL, COLLECTOR_OUTPUT_POSTGRES,
+// COLLECTOR_OUTPUT_ELASTICSEARCH y COLLECTOR_OUTPUT_KAFKA.
+// Así se pueden cambiar credenciales y consultas sin editar el archivo ni
+// recompilar.
+func (c *Config) ApplyEnv(getenv func(string) string) error {
+	for _, n := range c.Sources.Named() {
+		prefix := "COLLECTOR_" + strings.ToUpper(n.Name) + "_"
+		if v := getenv(prefix + "ENABLED"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("%sENABLED: valor booleano inválido %q", prefix, v)
+			}
+			n.Enabled = b
+		}
+		if v := getenv(prefix + "PAGE_SIZE"); v != "" {
+			size, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("%sPAGE_SIZE: número inválido %q", prefix, v)
+			}
+			n.PageSize = size
+		}
+		setString(&n.APIKey, getenv(prefix+"API_KEY"))
+		setString(&n.Query, getenv(prefix+"QUERY"))
+		setString(&n.Handle, getenv(prefix+"HANDLE"))
+		setString(&n.Region, getenv(prefix+"REGION"))
+		setList((*[]string)(&n.Fallback), getenv(prefix+"FALLBACK"))
+		setString(&n.FallbackOn, getenv(prefix+"FALLBACK_ON"))
+		setString(&n.From, getenv(prefix+"FROM"))
+		setString(&n.To, getenv(prefix+"TO"))
+		setList(&n.Languages, getenv(prefix+"LANGUAGES"))
+		setList(&n.Feeds, getenv(prefix+"FEEDS"))
+		if v := getenv(prefix + "INSTANCES"); v != "" {
+			var urls []string
+			setList(&urls, v)
+			n.Instances = nil
+			for _, u := range urls {
+				n.Instances = append(n.Instances, Instance{URL: u})
+			}
+		}
+	}
+	setString(&c.Sources.Fixtures, getenv("COLLECTOR_FIXTURES"))
+	setString(&c.Output.DB, getenv("COLLECTOR_OUTPUT_DB"))
+	setString(&c.Output.Postgres, getenv("COLLECTOR_OUTPUT_POSTGRES"))
+	setString(&c.Output.JSONL, getenv("COLLECTOR_OUTPUT_JSONL"))
+	setString(&c.Output.Elasticsearch.URL, getenv("COLLECTOR_OUTPUT_ELASTICSEARCH"))
+	setList(&c.Output.Kafka.Brokers, getenv("COLLECTOR_OUTPUT_KAFKA"))
+	return nil
+}
+
+func setString(dst *string, v string) {
+	if v != "" {
+		*dst = v
+	}
+}
+
+func setList(dst *[]string, v string) {
+	if v == "" {
+		return
+	}
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	*dst = out
+}
@@ -0,0 +1,782 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"go-collector/extract"
+	"go-collector/schedule"
+)
+
+// Problem es un error de configuración con su ubicación en el archivo.
+type Problem struct {
+	Line    int
+	Field   string
+	Message string
+}
+
+// ValidationError agrupa todos los problemas encontrados, para corregirlos de
+// una vez en lugar de uno por ejecución.
+type ValidationError struct {
+	File     string
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	if len(e.Problems) == 1 {
+		b.WriteString("configuración inválida (1 problema):")
+	} else {
+		fmt.Fprintf(&b, "configuración inválida (%d problemas):", len(e.Problems))
+	}
+	for _, p := range e.Problems {
+		b.WriteString("\n  ")
+		b.WriteString(e.File)
+		if p.Line > 0 {
+			fmt.Fprintf(&b, ":%d", p.Line)
+		}
+		if p.Field != "" {
+			b.WriteString(": " + p.Field)
+		}
+		b.WriteString(": " + p.Message)
+	}
+	return b.String()
+}
+
+// lineRe extrae el número de línea de los errores de yaml.v3 ("line 12: ...").
+var lineRe = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// regionRe es un código de país ISO 3166-1 alfa-2.
+var regionRe = regexp.MustCompile(`^[A-Za-z]{2}$`)
+
+// decodeErrors convierte los errores de claves desconocidas y tipos de yaml.v3
+// en problemas con línea.
+func decodeErrors(err error) []Problem {
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		msg := strings.TrimPrefix(err.Error(), "yaml: ")
+		if m := lineRe.FindStringSubmatch(msg); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			return []Problem{{Line: line, Message: m[2]}}
+		}
+		return []Problem{{Message: msg}}
+	}
+	var out []Problem
+	for _, e := range typeErr.Errors {
+		p := Problem{Message: e}
+		if m := lineRe.FindStringSubmatch(e); m != nil {
+			p.Line, _ = strconv.Atoi(m[1])
+			p.Message = m[2]
+		}
+		if strings.Contains(p.Message, "not found in type") {
+			// "field foo not found in type config.Fetch" -> clave desconocida.
+			fields := strings.Fields(p.Message)
+			if len(fields) > 1 {
+				p.Message = fmt.Sprintf("clave desconocida %q", fields[1])
+			}
+		} else if strings.HasPrefix(p.Message, "cannot unmarshal") {
+			p.Message = "tipo incorrecto: " + p.Message
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// locator ubica rutas de la configuración en el árbol YAML para reportar la línea.
+type locator struct {
+	root *yaml.Node
+}
+
+// node devuelve el nodo de la ruta (solo claves string), o nil si no existe.
+func (l locator) node(path ...string) *yaml.Node {
+	n := l.root
+	if n == nil {
+		return nil
+	}
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+	for _, key := range path {
+		if n.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value == key {
+				next = n.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		n = next
+	}
+	return n
+}
+
+// line devuelve la línea del nodo más profundo que existe en la ruta (claves
+// string, índices int); 0 si no hay documento.
+func (l locator) line(path ...any) int {
+	n := l.root
+	if n == nil {
+		return 0
+	}
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+	line := n.Line
+	for _, step := range path {
+		var next *yaml.Node
+		switch key := step.(type) {
+		case string:
+			if n.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(n.Content); i += 2 {
+					if n.Content[i].Value == key {
+						next = n.Content[i+1]
+						line = n.Content[i].Line
+						break
+					}
+				}
+			}
+		case int:
+			if n.Kind == yaml.SequenceNode && key < len(n.Content) {
+				next = n.Content[key]
+				line = next.Line
+			}
+		}
+		if next == nil {
+			return line
+		}
+		n = next
+	}
+	return line
+}
+
+// validator acumula problemas semánticos.
+type validator struct {
+	loc      locator
+	problems []Problem
+}
+
+func (v *validator) add(msg string, field string, path ...any) {
+	v.problems = append(v.problems, Problem{Line: v.loc.line(path...), Field: field, Message: msg})
+}
+
+// site revisa un sitio de la fuente scrape; path es su ubicación en el
+// archivo.
+func (v *validator) site(s Site, field string, path ...any) {
+	at := func(key string) []any { return append(slices.Clip(path), key) }
+	if s.Name == "" {
+		v.add("falta name", field, path...)
+	}
+	if len(s.Start) == 0 {
+		v.add("falta start (las páginas de inicio)", field+".start", at("start")...)
+	}
+	for i, u := range s.Start {
+		if pu, err := url.Parse(u); err != nil || (pu.Scheme != "http" && pu.Scheme != "https") || pu.Host == "" {
+			v.add(fmt.Sprintf("url inválida %q (ej: https://www.eltiempo.com/colombia/medellin)", u),
+				fmt.Sprintf("%s.start[%d]", field, i), append(at("start"), i)...)
+		}
+	}
+	if s.Links == "" {
+		v.add("falta links (la expresión regular de las URLs de las notas)", field+".links", path...)
+	}
+	for _, re := range [][2]string{{"links", s.Links}, {"follow", s.Follow}} {
+		if _, err := regexp.Compile(re[1]); err != nil {
+			v.add("expresión regular inválida: "+err.Error(), field+"."+re[0], at(re[0])...)
+		}
+	}
+	for _, sel := range [][2]string{{"title", s.Title}, {"body", s.Body}, {"date", s.Date}, {"author", s.Author}} {
+		if err := extract.CheckSelector(sel[1]); err != nil {
+			v.add(fmt.Sprintf("selector inválido %q: %v", sel[1], err), field+"."+sel[0], at(sel[0])...)
+		}
+	}
+	if s.Depth < 0 {
+		v.add("depth no puede ser negativo", field+".depth", at("depth")...)
+	}
+	if s.MaxPages < 0 {
+		v.add("max_pages no puede ser negativo", field+".max_pages", at("max_pages")...)
+	}
+	if s.Delay != "" {
+		if d, err := time.ParseDuration(s.Delay); err != nil || d < 0 {
+			v.add(fmt.Sprintf("delay inválido %q (ej: 2s)", s.Delay), field+".delay", at("delay")...)
+		}
+	}
+}
+
+// maxPageSize es el máximo de resultados por consulta que admite cada API.
+var maxPageSize = map[string]int{
+	"guardian":      200,
+	"newsapi":       100,
+	"bingnews":      100,
+	"eventregistry": 100,
+	"mediastack":    100,
+	"currents":      200,
+	"gdelt":         250,
+	"x":             100,
+	"mastodon":      40,
+	"bluesky":       100,
+	"youtube":       50,
+}
+
+// validate revisa las reglas que el tipo de los campos no alcanza a expresar.
+func (c *Config) validate(loc locator) []Problem {
+	v := &validator{loc: loc}
+
+	if c.Sources.Fixtures != "" {
+		if _, err := os.Stat(c.Sources.Fixtures); err != nil {
+			v.add("no se puede leer el directorio de prueba: "+err.Error(), "sources.fixtures", "sources", "fixtures")
+		}
+	}
+	for _, n := range c.Sources.Named() {
+		if !n.Enabled {
+			continue
+		}
+		field := "sources." + n.Name
+		if n.Name == "rss" {
+			if len(n.Feeds) == 0 {
+				v.add("la fuente rss requiere feeds", field+".feeds", "sources", n.Name)
+			}
+		} else if n.Query == "" && n.Name != "mock" && n.Name != "oai" && n.Name != "commoncrawl" && n.Name != "sitemap" && n.Name != "scrape" {
+			v.add("falta query", field+".query", "sources", n.Name)
+		}
+		if n.Name == "bluesky" && n.Handle == "" {
+			v.add("la fuente bluesky requiere handle (la cuenta de la contraseña de aplicación)", field+".handle", "sources", n.Name)
+		}
+		chain := make(map[string]bool)
+		for _, fb := range n.Fallback {
+			switch {
+			case c.Sources.Get(fb) == nil || fb == "mock":
+				v.add(fmt.Sprintf("fuente de reemplazo desconocida: %s", fb), field+".fallback", "sources", n.Name, "fallback")
+			case fb == n.Name:
+				v.add("una fuente no puede ser su propio reemplazo", field+".fallback", "sources", n.Name, "fallback")
+			case chain[fb]:
+				v.add(fmt.Sprintf("reemplazo repetido en la cadena: %s", fb), field+".fallback", "sources", n.Name, "fallback")
+			}
+			chain[fb] = true
+		}
+		switch n.FallbackOn {
+		case "", FallbackOnQuota, FallbackOnError, FallbackOnEmpty:
+		default:
+			v.add(fmt.Sprintf("fallback_on desconocido %q (use quota, error o empty)", n.FallbackOn), field+".fallback_on", "sources", n.Name, "fallback_on")
+		}
+		if n.Name == "googlenews" && n.Region == "" {
+			v.add("la fuente googlenews requiere region (el país de la edición, ej: co)", field+".region", "sources", n.Name)
+		}
+		if n.Region != "" && !regionRe.MatchString(n.Region) {
+			v.add(fmt.Sprintf("region inválida %q (código de país de dos letras, ej: co)", n.Region), field+".region", "sources", n.Name, "region")
+		}
+		if n.Name == "mastodon" {
+			if len(n.Instances) == 0 {
+				v.add("la fuente mastodon requiere instances", field+".instances", "sources", n.Name)
+			}
+			for i, inst := range n.Instances {
+				f := fmt.Sprintf("%s.instances[%d]", field, i)
+				if u, err := url.Parse(inst.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					v.add(fmt.Sprintf("url inválida %q (ej: https://mastodon.social)", inst.URL), f+".url", "sources", n.Name, "instances", i, "url")
+				}
+				if _, err := ParseRate(inst.RateLimit); err != nil {
+					v.add(err.Error(), f+".rate_limit", "sources", n.Name, "instances", i, "rate_limit")
+				}
+			}
+		}
+		if n.Name == "oai" {
+			if len(n.Repositories) == 0 {
+				v.add("la fuente oai requiere repositories", field+".repositories", "sources", n.Name)
+			}
+			for i, repo := range n.Repositories {
+				if u, err := url.Parse(repo.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					v.add(fmt.Sprintf("url inválida %q (ej: https://repositorio.example.edu/oai/request)", repo.URL),
+						fmt.Sprintf("%s.repositories[%d].url", field, i), "sources", n.Name, "repositories", i, "url")
+				}
+			}
+		}
+		if n.Name == "commoncrawl" && len(n.Domains) == 0 {
+			v.add("la fuente commoncrawl requiere domains", field+".domains", "sources", n.Name)
+		}
+		if n.Name == "sitemap" {
+			if len(n.Domains) == 0 && len(n.Sitemaps) == 0 {
+				v.add("la fuente sitemap requiere domains o sitemaps", field+".domains", "sources", n.Name)
+			}
+			for i, sm := range n.Sitemaps {
+				if u, err := url.Parse(sm); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					v.add(fmt.Sprintf("url inválida %q (ej: https://www.eltiempo.com/sitemap-news.xml)", sm),
+						fmt.Sprintf("%s.sitemaps[%d]", field, i), "sources", n.Name, "sitemaps", i)
+				}
+			}
+		}
+		if n.Name == "scrape" {
+			if len(n.Sites) == 0 {
+				v.add("la fuente scrape requiere sites", field+".sites", "sources", n.Name)
+			}
+			for i, site := range n.Sites {
+				v.site(site, fmt.Sprintf("%s.sites[%d]", field, i), "sources", n.Name, "sites", i)
+			}
+		}
+		if n.Name == "commoncrawl" || n.Name == "sitemap" {
+			for i, d := range n.Domains {
+				if d == "" || strings.ContainsAny(d, "/:*") {
+					v.add(fmt.Sprintf("dominio inválido %q (sin esquema ni ruta, ej: eltiempo.com)", d),
+						fmt.Sprintf("%s.domains[%d]", field, i), "sources", n.Name, "domains", i)
+				}
+			}
+		}
+		if n.Rate < 0 {
+			v.add("rate no puede ser negativo", field+".rate", "sources", n.Name, "rate")
+		}
+		if n.PageSize < 0 {
+			v.add("page_size no puede ser negativo", field+".page_size", "sources", n.Name, "page_size")
+		} else if limit := maxPageSize[n.Name]; limit > 0 && n.PageSize > limit {
+			v.add(fmt.Sprintf("page_size máximo de %s: %d", n.Name, limit), field+".page_size", "sources", n.Name, "page_size")
+		}
+		if _, err := n.ChunkSpan(); err != nil {
+			v.add(err.Error(), field+".chunk", "sources", n.Name, "chunk")
+		}
+		if n.Adaptive != nil {
+			if n.Name != "rss" {
+				v.add("adaptive solo vale para la fuente rss", field+".adaptive", "sources", n.Name, "adaptive")
+			} else if _, _, err := n.Adaptive.Bounds(); err != nil {
+				v.add(err.Error(), field+".adaptive", "sources", n.Name, "adaptive")
+			}
+		}
+		if _, err := n.StallSpan(); err != nil {
+			v.add(err.Error(), field+".stall_timeout", "sources", n.Name, "stall_timeout")
+		}
+		if _, err := n.Limit(); err != nil {
+			v.add(err.Error(), field+".rate_limit", "sources", n.Name, "rate_limit")
+		}
+		if n.RateBurst < 0 {
+			v.add("rate_burst no puede ser negativo", field+".rate_burst", "sources", n.Name, "rate_burst")
+		}
+		if n.MaxResults < 0 {
+			v.add("max_results no puede ser negativo", field+".max_results", "sources", n.Name, "max_results")
+		}
+		if n.Cost != nil && (n.Cost.Monthly < 0 || n.Cost.Included < 0 || n.Cost.PerThousand < 0) {
+			v.add("los montos y peticiones de cost no pueden ser negativos", field+".cost", "sources", n.Name, "cost")
+		}
+		now := time.Now()
+		from, errFrom := parseBound(n.From, now, false)
+		if n.From != "" && errFrom != nil {
+			v.add(errFrom.Error(), field+".from", "sources", n.Name, "from")
+		}
+		to, errTo := parseBound(n.To, now, true)
+		if n.To != "" && errTo != nil {
+			v.add(errTo.Error(), field+".to", "sources", n.Name, "to")
+		}
+		if n.From != "" && n.To != "" && errFrom == nil && errTo == nil && from.After(to) {
+			v.add("from es posterior a to", field+".from", "sources", n.Name, "from")
+		}
+	}
+
+	for name, p := range c.Profiles {
+		if p != nil && len(p.Profiles) > 0 {
+			v.add("un perfil no puede definir otros perfiles", "profiles."+name, "profiles", name, "profiles")
+		}
+	}
+
+	if c.Fetch.Backoff.Max > 0 && c.Fetch.Backoff.Base > c.Fetch.Backoff.Max {
+		v.add("base no puede ser mayor que max", "fetch.backoff", "fetch", "backoff")
+	}
+	for domain, r := range c.Extract.Domains {
+		if r.Body == "" && r.Author == "" && r.Date == "" {
+			v.add("la regla no tiene selectores", "extract.domains."+domain, "extract", "domains", domain)
+		}
+		if err := (extract.Rule{Body: r.Body, Author: r.Author, Date: r.Date}).Check(); err != nil {
+			v.add(err.Error(), "extract.domains."+domain, "extract", "domains", domain)
+		}
+	}
+	if c.Fetch.Expand.MaxHops < 0 {
+		v.add("max_hops no puede ser negativo", "fetch.expand.max_hops", "fetch", "expand", "max_hops")
+	}
+	if _, err := ParseRate(c.Fetch.Expand.RateLimit); err != nil {
+		v.add(err.Error(), "fetch.expand.rate_limit", "fetch", "expand", "rate_limit")
+	}
+	if c.Fetch.Render.Timeout < 0 {
+		v.add("timeout no puede ser negativo", "fetch.render.timeout", "fetch", "render", "timeout")
+	}
+	if c.Fetch.Render.MaxConcurrent < 0 {
+		v.add("max_concurrent no puede ser negativo", "fetch.render.max_concurrent", "fetch", "render", "max_concurrent")
+	}
+	if c.Fetch.Render.Command == "" && len(c.Fetch.Render.Args) > 0 {
+		v.add("args sin command", "fetch.render.args", "fetch", "render", "args")
+	}
+
+	for region, editions := range c.Feeds.Editions {
+		for i, e := range editions {
+			if e.URL == "" {
+				v.add("falta url", fmt.Sprintf("feeds.editions.%s[%d]", region, i), "feeds", "editions", region, i)
+			}
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, camp := range c.Campaigns {
+		field := fmt.Sprintf("campaigns[%d]", i)
+		if camp.Name == "" {
+			v.add("falta name", field, "campaigns", i)
+		} else if names[camp.Name] {
+			v.add(fmt.Sprintf("campaña duplicada %q", camp.Name), field+".name", "campaigns", i, "name")
+		}
+		names[camp.Name] = true
+		if len(camp.Query.Terms) == 0 {
+			v.add("la campaña no tiene términos de búsqueda", field+".query.terms", "campaigns", i, "query")
+		}
+		for j, name := range camp.Sources {
+			if c.Sources.Get(name) == nil {
+				v.add(fmt.Sprintf("fuente desconocida %q", name), fmt.Sprintf("%s.sources[%d]", field, j), "campaigns", i, "sources", j)
+			}
+		}
+		now := time.Now()
+		from, errFrom := parseBound(camp.From, now, false)
+		if camp.From != "" && errFrom != nil {
+			v.add(errFrom.Error(), field+".from", "campaigns", i, "from")
+		}
+		to, errTo := parseBound(camp.To, now, true)
+		if camp.To != "" && errTo != nil {
+			v.add(errTo.Error(), field+".to", "campaigns", i, "to")
+		}
+		if camp.From != "" && camp.To != "" && errFrom == nil && errTo == nil && from.After(to) {
+			v.add("from es posterior a to", field+".from", "campaigns", i, "from")
+		}
+		if camp.Schedule != "" {
+			if _, err := schedule.Parse(camp.Schedule); err != nil {
+				v.add(err.Error(), field+".schedule", "campaigns", i, "schedule")
+			}
+		}
+		if _, err := ParseRate(camp.RateLimit); err != nil {
+			v.add(err.Error(), field+".rate_limit", "campaigns", i, "rate_limit")
+		}
+		if camp.RateBurst < 0 {
+			v.add("rate_burst no puede ser negativo", field+".rate_burst", "campaigns", i, "rate_burst")
+		}
+		if ns := camp.NamespaceOrName(); strings.ContainsAny(ns, `/\ `) {
+			v.add("namespace no puede tener espacios ni separadores de ruta", field+".namespace", "campaigns", i, "namespace")
+		}
+	}
+
+	accounts := make(map[Account]bool)
+	for i, acc := range c.Accounts {
+		field := fmt.Sprintf("accounts[%d]", i)
+		switch acc.Platform {
+		case "x", "bluesky":
+		case "mastodon":
+			if _, instance, _ := strings.Cut(acc.Handle, "@"); instance == "" {
+				v.add(fmt.Sprintf("handle de mastodon sin instancia %q (ej: udea@mastodon.social)", acc.Handle), field+".handle", "accounts", i, "handle")
+			}
+		default:
+			v.add(fmt.Sprintf("plataforma desconocida %q (use x, mastodon o bluesky)", acc.Platform), field+".platform", "accounts", i, "platform")
+		}
+		if acc.Handle == "" {
+			v.add("falta handle", field, "accounts", i)
+		} else if accounts[acc] {
+			v.add(fmt.Sprintf("cuenta duplicada %s/%s", acc.Platform, acc.Handle), field+".handle", "accounts", i, "handle")
+		}
+		accounts[acc] = true
+	}
+
+	for i, r := range c.Relevance.Suppress {
+		field := fmt.Sprintf("relevance.suppress[%d]", i)
+		if r.Name == "" {
+			v.add("falta name", field, "relevance", "suppress", i)
+		}
+		if r.Term == "" && r.Pattern == "" {
+			v.add("indique term o pattern", field, "relevance", "suppress", i)
+		}
+		if r.Pattern != "" {
+			if _, err := regexp.Compile(r.Pattern); err != nil {
+				v.add("expresión regular inválida: "+err.Error(), field+".pattern", "relevance", "suppress", i, "pattern")
+			}
+		}
+		if r.Window < 0 {
+			v.add("window no puede ser negativo", field+".window", "relevance", "suppress", i, "window")
+		}
+	}
+
+	seenStrategy := make(map[string]bool)
+	for i, name := range c.Dedup.Strategies {
+		field := fmt.Sprintf("dedup.strategies[%d]", i)
+		switch {
+		case !slices.Contains(DedupStrategies, name):
+			v.add(fmt.Sprintf("estrategia desconocida %q (válidas: %s)", name, strings.Join(DedupStrategies, ", ")), field, "dedup", "strategies", i)
+		case seenStrategy[name]:
+			v.add(fmt.Sprintf("estrategia %q repetida", name), field, "dedup", "strategies", i)
+		}
+		seenStrategy[name] = true
+	}
+	if c.Dedup.MaxDistance < 0 || c.Dedup.MaxDistance > 64 {
+		v.add("max_distance debe estar entre 0 y 64", "dedup.max_distance", "dedup", "max_distance")
+	}
+	if c.Dedup.Window != "" {
+		if _, err := ParseSpan(c.Dedup.Window); err != nil {
+			v.add(err.Error(), "dedup.window", "dedup", "window")
+		}
+	}
+
+	switch c.Embeddings.Provider {
+	case "", "hash", "local":
+	case "openai":
+		if c.Embeddings.Model == "" {
+			v.add("el proveedor openai requiere model", "embeddings.model", "embeddings")
+		}
+	default:
+		v.add(fmt.Sprintf("proveedor desconocido %q (use hash u openai)", c.Embeddings.Provider), "embeddings.provider", "embeddings", "provider")
+	}
+	if c.LLM.BaseURL != "" && c.LLM.Model == "" {
+		v.add("falta model", "llm.model", "llm")
+	}
+
+	if es := c.Output.Elasticsearch; es.URL != "" {
+		at := func(key string) []any { return []any{"output", "elasticsearch", key} }
+		if u, err := url.Parse(es.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.add(fmt.Sprintf("URL inválida %q (ej: https://localhost:9200)", es.URL), "output.elasticsearch.url", at("url")...)
+		}
+		if es.BatchSize < 0 {
+			v.add("batch_size no puede ser negativo", "output.elasticsearch.batch_size", at("batch_size")...)
+		}
+		if es.PasswordEnv != "" && es.Username == "" {
+			v.add("password_env sin username", "output.elasticsearch.password_env", at("password_env")...)
+		}
+		if es.PasswordEnv != "" && es.APIKeyEnv != "" {
+			v.add("use password_env o api_key_env, no ambos", "output.elasticsearch", "output", "elasticsearch")
+		}
+	}
+
+	if k := c.Output.Kafka; len(k.Brokers) > 0 {
+		for i, b := range k.Brokers {
+			if host, port, err := net.SplitHostPort(b); err != nil || host == "" || port == "" {
+				v.add(fmt.Sprintf("broker inválido %q (ej: localhost:9092)", b), "output.kafka.brokers", "output", "kafka", "brokers", i)
+			}
+		}
+		if !validTopic(k.Topic) {
+			v.add(fmt.Sprintf("tópico inválido %q (letras, números, '.', '_' y '-', hasta 249)", k.Topic), "output.kafka.topic", "output", "kafka", "topic")
+		}
+	}
+
+	enc := c.Storage.Encryption
+	if enc.KeyFile != "" && enc.KeyEnv == "" {
+		if _, err := os.Stat(enc.KeyFile); err != nil {
+			v.add("no se puede leer el archivo de clave: "+err.Error(), "storage.encryption.key_file", "storage", "encryption", "key_file")
+		}
+	}
+
+	switch c.Storage.Compression.Level {
+	case "", "fastest", "default", "better", "best":
+	default:
+		v.add(fmt.Sprintf("nivel desconocido %q (use fastest, default, better o best)", c.Storage.Compression.Level),
+			"storage.compression.level", "storage", "compression", "level")
+	}
+
+	reports := make(map[string]bool)
+	usesEmail := false
+	for i, r := range c.Reports {
+		field := fmt.Sprintf("reports[%d]", i)
+		if r.Name == "" {
+			v.add("falta name", field, "reports", i)
+		} else if reports[r.Name] {
+			v.add(fmt.Sprintf("reporte duplicado %q", r.Name), field+".name", "reports", i, "name")
+		}
+		reports[r.Name] = true
+		if _, err := schedule.Parse(r.Schedule); err != nil {
+			v.add(err.Error(), field+".schedule", "reports", i, "schedule")
+		}
+		switch r.Template {
+		case "", "digest", "summary":
+		default:
+			v.add(fmt.Sprintf("plantilla desconocida %q (use digest o summary)", r.Template), field+".template", "reports", i, "template")
+		}
+		switch r.Format {
+		case "", "html", "markdown", "md", "pdf":
+		default:
+			v.add(fmt.Sprintf("formato desconocido %q (use html, markdown o pdf)", r.Format), field+".format", "reports", i, "format")
+		}
+		if _, err := r.PeriodStart(time.Now()); err != nil {
+			v.add(err.Error(), field+".period", "reports", i, "period")
+		}
+		if len(r.Destinations) == 0 {
+			v.add("el reporte no tiene destinos", field+".destinations", "reports", i)
+		}
+		for j, d := range r.Destinations {
+			dfield := fmt.Sprintf("%s.destinations[%d]", field, j)
+			switch d.Type {
+			case "file":
+			case "email":
+				usesEmail = true
+				if len(d.To) == 0 {
+					v.add("el destino email requiere to", dfield+".to", "reports", i, "destinations", j)
+				}
+			default:
+				v.add(fmt.Sprintf("tipo de destino desconocido %q (use file o email)", d.Type), dfield+".type", "reports", i, "destinations", j, "type")
+			}
+		}
+	}
+	if usesEmail && (c.SMTP.Host == "" || c.SMTP.From == "") {
+		v.add("hay reportes por email: configure smtp.host y smtp.from", "smtp", "smtp")
+	}
+
+	exports := make(map[string]bool)
+	notifies := false
+	for i, e := range c.Exports {
+		field := fmt.Sprintf("exports[%d]", i)
+		if e.Name == "" {
+			v.add("falta name", field, "exports", i)
+		} else if exports[e.Name] {
+			v.add(fmt.Sprintf("exportación duplicada %q", e.Name), field+".name", "exports", i, "name")
+		}
+		exports[e.Name] = true
+		if _, err := schedule.Parse(e.Schedule); err != nil {
+			v.add(err.Error(), field+".schedule", "exports", i, "schedule")
+		}
+		switch e.Format {
+		case "", "jsonl", "json", "csv":
+		default:
+			v.add(fmt.Sprintf("formato desconocido %q (use jsonl, json o csv)", e.Format), field+".format", "exports", i, "format")
+		}
+		if _, err := e.PeriodStart(time.Now()); err != nil {
+			v.add(err.Error(), field+".period", "exports", i, "period")
+		}
+		u, err := url.Parse(e.Upload.URL)
+		switch {
+		case e.Upload.URL == "":
+			v.add("falta upload.url", field+".upload.url", "exports", i, "upload")
+		case err != nil || u.Host == "":
+			v.add(fmt.Sprintf("URL inválida %q", e.Upload.URL), field+".upload.url", "exports", i, "upload", "url")
+		case u.Scheme == "sftp":
+			if u.User == nil {
+				v.add("la URL sftp requiere usuario (sftp://usuario@host/directorio)", field+".upload.url", "exports", i, "upload", "url")
+			}
+			if e.Upload.Username != "" || e.Upload.PasswordEnv != "" {
+				v.add("SFTP se autentica con clave (key_file), no con username ni password_env", field+".upload", "exports", i, "upload")
+			}
+		case u.Scheme == "https" || u.Scheme == "http":
+			if e.Upload.KeyFile != "" {
+				v.add("key_file es solo para SFTP", field+".upload.key_file", "exports", i, "upload", "key_file")
+			}
+			if e.Upload.PasswordEnv != "" && e.Upload.Username == "" {
+				v.add("password_env sin username", field+".upload.password_env", "exports", i, "upload", "password_env")
+			}
+		default:
+			v.add(fmt.Sprintf("esquema desconocido %q (use sftp o https)", u.Scheme), field+".upload.url", "exports", i, "upload", "url")
+		}
+		if e.Upload.Retries < 0 {
+			v.add("retries no puede ser negativo", field+".upload.retries", "exports", i, "upload", "retries")
+		}
+		if len(e.Notify) > 0 {
+			notifies = true
+		}
+	}
+	if notifies && (c.SMTP.Host == "" || c.SMTP.From == "") {
+		v.add("hay exportaciones con notify: configure smtp.host y smtp.from", "smtp", "smtp")
+	}
+
+	notifications := make(map[string]bool)
+	for i, n := range c.Notifications {
+		field := fmt.Sprintf("notifications[%d]", i)
+		if n.Name == "" {
+			v.add("falta name", field, "notifications", i)
+		} else if notifications[n.Name] {
+			v.add(fmt.Sprintf("notificación duplicada %q", n.Name), field+".name", "notifications", i, "name")
+		}
+		notifications[n.Name] = true
+		if n.URL == "" && n.URLEnv == "" && n.Format != "telegram" {
+			v.add("falta url o url_env", field+".url", "notifications", i)
+		} else if u, err := url.Parse(n.URL); n.URL != "" && (err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+			v.add(fmt.Sprintf("URL inválida %q", n.URL), field+".url", "notifications", i, "url")
+		}
+		switch n.Format {
+		case "", "json", "slack", "discord", "teams", "telegram":
+		default:
+			v.add(fmt.Sprintf("formato desconocido %q (use slack, discord, teams, telegram o json)", n.Format), field+".format", "notifications", i, "format")
+		}
+		if n.Format == "telegram" {
+			if n.ChatID == "" {
+				v.add("la notificación telegram requiere chat_id", field+".chat_id", "notifications", i)
+			}
+			if n.URLEnv != "" {
+				v.add("url_env no vale para telegram (el token va en token_env)", field+".url_env", "notifications", i, "url_env")
+			}
+		} else if n.ChatID != "" || n.TokenEnv != "" {
+			v.add("chat_id y token_env solo valen para telegram", field+".chat_id", "notifications", i)
+		}
+		if n.Template != "" {
+			if _, err := template.New(n.Name).Parse(n.Template); err != nil {
+				v.add(fmt.Sprintf("plantilla inválida: %v", err), field+".template", "notifications", i, "template")
+			}
+		}
+		for j, src := range n.Sources {
+			if c.Sources.Get(src) == nil {
+				v.add(fmt.Sprintf("fuente desconocida %q", src), fmt.Sprintf("%s.sources[%d]", field, j), "notifications", i, "sources", j)
+			}
+		}
+		if n.MinSentiment != nil && n.MaxSentiment != nil && *n.MinSentiment > *n.MaxSentiment {
+			v.add("min_sentiment es mayor que max_sentiment", field+".min_sentiment", "notifications", i, "min_sentiment")
+		}
+		if n.Retries < 0 {
+			v.add("retries no puede ser negativo", field+".retries", "notifications", i, "retries")
+		}
+		if n.Batch < 0 {
+			v.add("batch no puede ser negativo", field+".batch", "notifications", i, "batch")
+		}
+		if _, err := ParseRate(n.RateLimit); err != nil {
+			v.add(err.Error(), field+".rate_limit", "notifications", i, "rate_limit")
+		}
+	}
+
+	terms := make(map[string]bool)
+	for i, p := range c.Publishers {
+		field := fmt.Sprintf("publishers[%d]", i)
+		name := p.Source
+		switch {
+		case (p.Source == "") == (p.Domain == ""):
+			v.add("indique source o domain (uno de los dos)", field, "publishers", i)
+		case p.Source != "" && c.Sources.Get(p.Source) == nil:
+			v.add(fmt.Sprintf("fuente desconocida %q", p.Source), field+".source", "publishers", i, "source")
+		case p.Domain != "":
+			name = strings.ToLower(p.Domain)
+			if strings.Contains(p.Domain, "/") || !strings.Contains(p.Domain, ".") {
+				v.add(fmt.Sprintf("dominio inválido %q (ej: eltiempo.com)", p.Domain), field+".domain", "publishers", i, "domain")
+			}
+		}
+		if terms[name] {
+			v.add(fmt.Sprintf("términos duplicados para %q", name), field, "publishers", i)
+		}
+		terms[name] = true
+		switch p.TOS {
+		case "", "open", "research", "api", "restricted":
+		default:
+			v.add(fmt.Sprintf("categoría desconocida %q (use open, research, api o restricted)", p.TOS), field+".tos", "publishers", i, "tos")
+		}
+		switch p.Text {
+		case "", "share", "store", "none":
+		default:
+			v.add(fmt.Sprintf("valor desconocido %q (use share, store o none)", p.Text), field+".text", "publishers", i, "text")
+		}
+	}
+
+	return v.problems
+}
+
+// validTopic dice si Kafka acepta el nombre de tópico; vacío es el de por
+// defecto.
+func validTopic(name string) bool {
+	if len(name) > 249 || name == "." || name == ".." {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
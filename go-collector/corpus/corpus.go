@@ -0,0 +1,26 @@
+// Package corpus define lo mínimo que necesita quien guarda y lee artículos
+// del corpus, para no depender de la base: Store lo cumplen storage.Store
+// (SQLite) y Memory, un corpus en memoria para pruebas y herramientas que no
+// deben tocar la base.
+package corpus
+
+import (
+	"go-collector/article"
+	"go-collector/storage"
+)
+
+// Store guarda y consulta artículos. Los métodos tienen los nombres y la
+// semántica de storage.Store: guardar (SaveArticle), buscar por URL
+// (GetByURL, storage.ErrNotFound si no está), consultar (ListFiltered) y
+// resumir (Summary).
+type Store interface {
+	SaveArticle(a *article.Article) error
+	GetByURL(url string) (*article.Article, error)
+	ListFiltered(f storage.Filter) ([]*article.Article, error)
+	Summary() (*storage.CorpusSummary, error)
+}
+
+var (
+	_ Store = (*storage.Store)(nil)
+	_ Store = (*Memory)(nil)
+)
@@ -0,0 +1,175 @@
+package corpus
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-collector/article"
+	"go-collector/storage"
+)
+
+// Memory es un corpus en memoria con las reglas de storage.Store: la URL
+// identifica al artículo, guardarlo de nuevo actualiza sus metadatos sin
+// borrar el cuerpo ni el título traducido ya guardados, y los filtros y el
+// resumen cuentan igual. No aplica cifrado ni términos de uso. Es seguro
+// para uso concurrente.
+type Memory struct {
+	mu       sync.RWMutex
+	articles []*article.Article // en orden de alta; el ID es la posición + 1
+	byURL    map[string]*article.Article
+}
+
+// NewMemory crea un corpus en memoria vacío.
+func NewMemory() *Memory {
+	return &Memory{byURL: make(map[string]*article.Article)}
+}
+
+// SaveArticle inserta el artículo o actualiza sus metadatos si la URL ya
+// existe, y completa su ID, fecha de recolección y estado como storage.Store.
+func (m *Memory) SaveArticle(a *article.Article) error {
+	if a.Collected.IsZero() {
+		a.Collected = time.Now().UTC()
+	}
+	if a.Status == "" {
+		a.Status = article.StatusActive
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old, ok := m.byURL[a.URL]
+	if !ok {
+		saved := *a
+		saved.ID = int64(len(m.articles) + 1)
+		m.articles = append(m.articles, &saved)
+		m.byURL[a.URL] = &saved
+		a.ID = saved.ID
+		return nil
+	}
+	old.Title, old.Author, old.Domain, old.Language = a.Title, a.Author, a.Domain, a.Language
+	old.Section, old.Summary = a.Section, a.Summary
+	if a.TitleTranslated != "" {
+		old.TitleTranslated = a.TitleTranslated
+	}
+	if a.Body != "" {
+		old.Body = a.Body
+	}
+	// Una fecha corregida es la de descarga: se conserva la primera.
+	if a.DateIssue == "" || old.Published.IsZero() {
+		old.Published = a.Published
+	}
+	a.ID, a.Published = old.ID, old.Published
+	return nil
+}
+
+// GetByURL busca un artículo por su URL exacta; storage.ErrNotFound si no
+// está. Devuelve una copia.
+func (m *Memory) GetByURL(url string) (*article.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, ok := m.byURL[url]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	out := *a
+	return &out, nil
+}
+
+// ListFiltered devuelve copias de los artículos que cumplen el filtro,
+// ordenados por publicación.
+func (m *Memory) ListFiltered(f storage.Filter) ([]*article.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*article.Article
+	for _, a := range m.articles {
+		if match(a, f) {
+			c := *a
+			out = append(out, &c)
+		}
+	}
+	// Como en SQLite, sin fecha va primero (la cadena vacía).
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Published.Before(out[j].Published) })
+	if f.Offset > 0 {
+		out = out[min(f.Offset, len(out)):]
+	}
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[:f.Limit]
+	}
+	return out, nil
+}
+
+// match aplica el filtro como la consulta de storage.Store.EachFiltered: con
+// From, los artículos sin fecha quedan fuera; con solo To, adentro.
+func match(a *article.Article, f storage.Filter) bool {
+	if !f.IncludeWithdrawn && a.Status != article.StatusActive {
+		return false
+	}
+	if !f.From.IsZero() && (a.Published.IsZero() || a.Published.Before(f.From)) {
+		return false
+	}
+	if !f.To.IsZero() && a.Published.After(f.To) {
+		return false
+	}
+	if len(f.Sources) > 0 && !slices.Contains(f.Sources, a.Source) {
+		return false
+	}
+	if f.Language != "" && a.Language != f.Language {
+		return false
+	}
+	text := strings.ToLower(a.Title + "\n" + a.Summary + "\n" + a.Body)
+	for _, word := range strings.Fields(strings.ToLower(f.Query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// Summary calcula los agregados del corpus como storage.Store.Summary.
+func (m *Memory) Summary() (*storage.CorpusSummary, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sum := &storage.CorpusSummary{BySource: make(map[string]int), ByLanguage: make(map[string]int)}
+	words, withBody := 0, 0
+	for _, a := range m.articles {
+		sum.Total++
+		if a.Status == article.StatusWithdrawn {
+			sum.Withdrawn++
+		}
+		if !a.Published.IsZero() {
+			if sum.First.IsZero() || a.Published.Before(sum.First) {
+				sum.First = a.Published
+			}
+			if a.Published.After(sum.Last) {
+				sum.Last = a.Published
+			}
+		}
+		if a.EditionGroup != 0 && a.EditionGroup != a.ID {
+			sum.Duplicates++
+		}
+		if a.Body != "" || a.ExtractionIssue != "" {
+			sum.ExtractionAttempts++
+		}
+		if a.Body != "" {
+			sum.ExtractionOK++
+			// Palabras aproximadas, como en SQLite: espacios + 1.
+			words += strings.Count(strings.Trim(a.Body, " "), " ") + 1
+			withBody++
+		}
+		sum.BySource[orUnknown(a.Source)]++
+		sum.ByLanguage[orUnknown(a.Language)]++
+	}
+	if withBody > 0 {
+		sum.MeanWords = float64(words) / float64(withBody)
+	}
+	return sum, nil
+}
+
+func orUnknown(key string) string {
+	if key == "" {
+		return "(desconocido)"
+	}
+	return key
+}
@@ -0,0 +1,212 @@
+// Package bingnews consulta la búsqueda de noticias de Bing (Bing News
+// Search v7). Sirve de reemplazo de NewsAPI cuando se agota su cuota: busca
+// en los mismos medios con una sintaxis de consulta compatible (frases entre
+// comillas, OR).
+package bingnews
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+)
+
+// MaxCount es el máximo de resultados por página.
+const MaxCount = 100
+
+// Freshness es la antigüedad máxima de los resultados; Bing no admite un
+// rango de fechas arbitrario.
+const (
+	FreshnessDay   = "Day"
+	FreshnessWeek  = "Week"
+	FreshnessMonth = "Month"
+)
+
+// FreshnessFor es la menor antigüedad que cubre desde from hasta now; "" si
+// from es anterior a un mes (o cero): sin filtro.
+func FreshnessFor(from, now time.Time) string {
+	if from.IsZero() {
+		return ""
+	}
+	switch age := now.Sub(from); {
+	case age <= 24*time.Hour:
+		return FreshnessDay
+	case age <= 7*24*time.Hour:
+		return FreshnessWeek
+	case age <= 31*24*time.Hour:
+		return FreshnessMonth
+	}
+	return ""
+}
+
+// ErrQuota indica que se agotó el cupo de llamadas de la suscripción (403
+// "Out of call volume quota") o el de peticiones por segundo (429).
+var ErrQuota = errors.New("se agotó la cuota de la API de Bing")
+
+// Response es una página de resultados.
+type Response struct {
+	TotalEstimatedMatches int       `json:"totalEstimatedMatches"`
+	Value                 []Article `json:"value"`
+}
+
+// Article es una noticia. Provider son los medios que la publicaron (casi
+// siempre uno).
+type Article struct {
+	Name          string `json:"name"`
+	URL           string `json:"url"`
+	Description   string `json:"description"`
+	DatePublished string `json:"datePublished"`
+	Category      string `json:"category"`
+	Provider      []struct {
+		Name string `json:"name"`
+	} `json:"provider"`
+	Image struct {
+		Thumbnail struct {
+			ContentURL string `json:"contentUrl"`
+		} `json:"thumbnail"`
+	} `json:"image"`
+}
+
+// APIError es una respuesta de error de la API.
+type APIError struct {
+	HTTPStatus int
+	Code       string // ej: InvalidAuthorization, RateLimitExceeded
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error de Bing (HTTP %d, %s): %s", e.HTTPStatus, e.Code, e.Message)
+}
+
+// Is permite errors.Is(err, ErrQuota).
+func (e *APIError) Is(target error) bool {
+	return target == ErrQuota && (e.HTTPStatus == http.StatusTooManyRequests ||
+		(e.HTTPStatus == http.StatusForbidden && strings.Contains(strings.ToLower(e.Message), "quota")))
+}
+
+type Crawler struct {
+	BaseURL string
+	Client  *http.Client
+	APIKey  string
+}
+
+func NewCrawler(apiKey string) *Crawler {
+	return &Crawler{
+		BaseURL: "https://api.bing.microsoft.com/v7.0/news/search",
+		Client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+		APIKey: apiKey,
+	}
+}
+
+// SearchOptions acota la búsqueda. Market es el mercado (idioma-país, ej:
+// es-CO) del que salen los resultados; vacío lo deduce Bing de la petición.
+type SearchOptions struct {
+	Market    string
+	Freshness string
+	Count     int
+	Offset    int
+}
+
+// Search pide una página de noticias que mencionan q, de la más reciente
+// hacia atrás. La siguiente se pide con Offset más los resultados recibidos.
+func (b *Crawler) Search(ctx context.Context, q string, opts SearchOptions) (*Response, error) {
+	params := url.Values{}
+	params.Set("q", q)
+	params.Set("sortBy", "Date")
+	params.Set("textFormat", "Raw")
+	params.Set("count", strconv.Itoa(opts.Count))
+	if opts.Offset > 0 {
+		params.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	if opts.Market != "" {
+		params.Set("mkt", opts.Market)
+	}
+	if opts.Freshness != "" {
+		params.Set("freshness", opts.Freshness)
+	}
+	fullURL := fmt.Sprintf("%s?%s", b.BaseURL, params.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Ocp-Apim-Subscription-Key", b.APIKey)
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// La API responde {"error": {...}} o, desde el gateway,
+		// {"errors": [{...}]}.
+		var apiErr struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+			Errors []struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		json.Unmarshal(body, &apiErr)
+		e := &APIError{HTTPStatus: resp.StatusCode, Code: apiErr.Error.Code, Message: apiErr.Error.Message}
+		if len(apiErr.Errors) > 0 {
+			e.Code, e.Message = apiErr.Errors[0].Code, apiErr.Errors[0].Message
+		}
+		if e.Message == "" {
+			e.Message = crawler.Preview(body)
+		}
+		return nil, e
+	}
+	var out Response
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("error parseando JSON: %w. Respuesta recibida:\n%s", err, crawler.Preview(body))
+	}
+	return &out, nil
+}
+
+// Normalize convierte los resultados al modelo común del corpus: el medio
+// (provider) va como autor, porque Bing no informa el del artículo, y la
+// miniatura queda como adjunto. Bing no informa el idioma de cada noticia.
+func (r *Response) Normalize() []*article.Article {
+	out := make([]*article.Article, 0, len(r.Value))
+	for _, v := range r.Value {
+		a := &article.Article{
+			Source:  "bingnews",
+			URL:     v.URL,
+			Title:   v.Name,
+			Domain:  crawler.Domain(v.URL),
+			Section: v.Category,
+			Summary: v.Description,
+		}
+		if len(v.Provider) > 0 {
+			a.Author = v.Provider[0].Name
+		}
+		if thumb := v.Image.Thumbnail.ContentURL; thumb != "" {
+			a.Media = append(a.Media, article.Media{URL: thumb})
+		}
+		a.Published, a.RawPublished = dates.Normalize(v.DatePublished)
+		out = append(out, a)
+	}
+	return out
+}
@@ -0,0 +1,315 @@
+// Package bluesky consulta la búsqueda de publicaciones de Bluesky
+// (app.bsky.feed.searchPosts, AT Protocol) con una contraseña de aplicación.
+package bluesky
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+)
+
+// MaxLimit es el máximo de publicaciones por página que admite searchPosts.
+const MaxLimit = 100
+
+// DefaultService es el servidor donde se inicia sesión y se busca.
+const DefaultService = "https://bsky.social"
+
+// ErrAuth indica que la sesión no se pudo iniciar (usuario o contraseña de
+// aplicación incorrectos).
+var ErrAuth = errors.New("bluesky rechazó las credenciales")
+
+// Response es una página de searchPosts.
+type Response struct {
+	Cursor    string `json:"cursor"`
+	HitsTotal int    `json:"hitsTotal"`
+	Posts     []Post `json:"posts"`
+}
+
+// Post es una publicación con sus contadores de interacción.
+type Post struct {
+	URI         string `json:"uri"` // at://<did>/app.bsky.feed.post/<rkey>
+	Author      Author `json:"author"`
+	Record      Record `json:"record"`
+	Embed       *Embed `json:"embed"`
+	ReplyCount  int    `json:"replyCount"`
+	RepostCount int    `json:"repostCount"`
+	LikeCount   int    `json:"likeCount"`
+	QuoteCount  int    `json:"quoteCount"`
+	IndexedAt   string `json:"indexedAt"`
+}
+
+type Author struct {
+	DID         string `json:"did"`
+	Handle      string `json:"handle"`
+	DisplayName string `json:"displayName"`
+}
+
+// Record es el contenido de la publicación tal como lo escribió el autor.
+type Record struct {
+	Text      string   `json:"text"`
+	CreatedAt string   `json:"createdAt"`
+	Langs     []string `json:"langs"`
+	Facets    []Facet  `json:"facets"`
+}
+
+// Facet marca un tramo del texto (en bytes UTF-8): un enlace, una mención o
+// un hashtag. Los enlaces largos se muestran recortados en el texto; el
+// completo está aquí.
+type Facet struct {
+	Index struct {
+		ByteStart int `json:"byteStart"`
+		ByteEnd   int `json:"byteEnd"`
+	} `json:"index"`
+	Features []struct {
+		Type string `json:"$type"`
+		URI  string `json:"uri"`
+	} `json:"features"`
+}
+
+// Embed es lo que acompaña la publicación: la vista previa de un enlace o
+// imágenes.
+type Embed struct {
+	Type     string `json:"$type"`
+	External *struct {
+		URI   string `json:"uri"`
+		Title string `json:"title"`
+	} `json:"external"`
+	Images []struct {
+		Fullsize string `json:"fullsize"`
+	} `json:"images"`
+}
+
+// APIError es una respuesta de error de la API (XRPC).
+type APIError struct {
+	HTTPStatus int
+	Code       string // ej: ExpiredToken, RateLimitExceeded
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error HTTP: status code %d. Respuesta de Bluesky:\n%s", e.HTTPStatus, e.Body)
+}
+
+type Crawler struct {
+	BaseURL    string
+	Client     *http.Client
+	Identifier string // handle (ej: udea.bsky.social) o correo de la cuenta
+	// Password es una contraseña de aplicación (Configuración > Contraseñas
+	// de aplicación), no la de la cuenta.
+	Password string
+
+	mu     sync.Mutex
+	access string
+}
+
+func NewCrawler(identifier, password string) *Crawler {
+	return &Crawler{
+		BaseURL: DefaultService,
+		Client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+		Identifier: identifier,
+		Password:   password,
+	}
+}
+
+// SearchOptions acota la búsqueda. Since y Until en cero no limitan; Lang
+// vacío busca en todos los idiomas.
+type SearchOptions struct {
+	Since, Until time.Time
+	Lang         string
+	Limit        int
+	Cursor       string
+}
+
+// Search pide una página de la búsqueda, de la más reciente hacia atrás. La
+// siguiente se pide con el Cursor de la respuesta (vacío: no hay más). La
+// sesión se inicia en la primera consulta y se renueva si vence.
+func (b *Crawler) Search(ctx context.Context, q string, opts SearchOptions) (*Response, error) {
+	params := url.Values{}
+	params.Set("q", q)
+	params.Set("sort", "latest")
+	params.Set("limit", strconv.Itoa(opts.Limit))
+	if !opts.Since.IsZero() {
+		params.Set("since", opts.Since.UTC().Format(time.RFC3339))
+	}
+	if !opts.Until.IsZero() {
+		params.Set("until", opts.Until.UTC().Format(time.RFC3339))
+	}
+	if opts.Lang != "" {
+		params.Set("lang", opts.Lang)
+	}
+	if opts.Cursor != "" {
+		params.Set("cursor", opts.Cursor)
+	}
+
+	var resp Response
+	err := b.get(ctx, "app.bsky.feed.searchPosts", params, &resp)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Code == "ExpiredToken" {
+		b.mu.Lock()
+		b.access = ""
+		b.mu.Unlock()
+		err = b.get(ctx, "app.bsky.feed.searchPosts", params, &resp)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// login inicia la sesión si no hay una y devuelve el token de acceso.
+func (b *Crawler) login(ctx context.Context) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.access != "" {
+		return b.access, nil
+	}
+	payload, err := json.Marshal(map[string]string{"identifier": b.Identifier, "password": b.Password})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", b.BaseURL+"/xrpc/com.atproto.server.createSession", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	var session struct {
+		AccessJwt string `json:"accessJwt"`
+	}
+	if err := b.do(req, &session); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.HTTPStatus == http.StatusUnauthorized {
+			return "", fmt.Errorf("%w (%s): %v", ErrAuth, b.Identifier, err)
+		}
+		return "", fmt.Errorf("error iniciando sesión en Bluesky: %w", err)
+	}
+	b.access = session.AccessJwt
+	return b.access, nil
+}
+
+func (b *Crawler) get(ctx context.Context, method string, params url.Values, v any) error {
+	token, err := b.login(ctx)
+	if err != nil {
+		return err
+	}
+	fullURL := fmt.Sprintf("%s/xrpc/%s?%s", b.BaseURL, method, params.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	return b.do(req, v)
+}
+
+func (b *Crawler) do(req *http.Request, v any) error {
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var xrpc struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(body, &xrpc)
+		return &APIError{HTTPStatus: resp.StatusCode, Code: xrpc.Error, Body: crawler.Preview(body)}
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error parseando JSON: %w. Respuesta recibida:\n%s", err, crawler.Preview(body))
+	}
+	return nil
+}
+
+// Link es la URL pública de la publicación en bsky.app.
+func (p Post) Link() string {
+	rkey := p.URI[strings.LastIndexByte(p.URI, '/')+1:]
+	profile := p.Author.Handle
+	if profile == "" || profile == "handle.invalid" {
+		profile = p.Author.DID
+	}
+	return "https://bsky.app/profile/" + profile + "/post/" + rkey
+}
+
+// ExpandedText es el texto con los enlaces recortados reemplazados por la URL
+// completa de sus facets.
+func (p Post) ExpandedText() string {
+	text := p.Record.Text
+	// De atrás hacia adelante para que los índices sigan valiendo.
+	for i := len(p.Record.Facets) - 1; i >= 0; i-- {
+		f := p.Record.Facets[i]
+		start, end := f.Index.ByteStart, f.Index.ByteEnd
+		if start < 0 || end > len(text) || start >= end {
+			continue
+		}
+		for _, feat := range f.Features {
+			if feat.Type == "app.bsky.richtext.facet#link" && feat.URI != "" {
+				text = text[:start] + feat.URI + text[end:]
+				break
+			}
+		}
+	}
+	return text
+}
+
+// Normalize convierte las publicaciones al modelo común del corpus. Como en
+// X, el título es el texto recortado y el texto completo va en Body, con los
+// enlaces completos; el autor es el handle y la interacción va en
+// Engagement.
+func (r *Response) Normalize() []*article.Article {
+	out := make([]*article.Article, 0, len(r.Posts))
+	for _, p := range r.Posts {
+		text := p.ExpandedText()
+		title := []rune(strings.Join(strings.Fields(text), " "))
+		if len(title) > 120 {
+			title = append(title[:117], []rune("...")...)
+		}
+		a := &article.Article{
+			Source: "bluesky",
+			URL:    p.Link(),
+			Title:  string(title),
+			Author: p.Author.Handle,
+			Domain: "bsky.app",
+			Body:   text,
+			Engagement: &article.Engagement{
+				Likes: p.LikeCount, Reposts: p.RepostCount, Replies: p.ReplyCount, Quotes: p.QuoteCount,
+			},
+		}
+		if len(p.Record.Langs) > 0 {
+			a.Language = p.Record.Langs[0]
+		}
+		if e := p.Embed; e != nil {
+			if e.External != nil {
+				a.Summary = e.External.Title
+			}
+			for _, img := range e.Images {
+				if img.Fullsize != "" {
+					a.Media = append(a.Media, article.Media{URL: img.Fullsize})
+				}
+			}
+		}
+		a.Published, a.RawPublished = dates.Normalize(p.Record.CreatedAt)
+		out = append(out, a)
+	}
+	return out
+}
@@ -0,0 +1,397 @@
+// Package commoncrawl consulta el índice de Common Crawl: las capturas de un
+// dominio en cada rastreo mensual, con la ubicación de su registro WARC
+// (archivo, desplazamiento y largo) para leer la página tal como se capturó.
+// Cubre años hacia atrás, mucho más que la ventana de las APIs de noticias.
+// El índice busca por URL, no por contenido: la consulta se compara con la
+// dirección de cada captura (las notas llevan el título en ella).
+package commoncrawl
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+)
+
+// timestampLayout es el formato de las marcas de tiempo del índice (UTC).
+const timestampLayout = "20060102150405"
+
+// maxPageSize limita la página leída de un registro WARC.
+const maxPageSize = 5 << 20
+
+// Collection es un rastreo (ej: CC-MAIN-2024-33) con su índice. From y To
+// son el período del rastreo; los índices antiguos no los informan.
+type Collection struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	CDXAPI string `json:"cdx-api"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// crawlIDRe reconoce el año y la semana en el identificador de un rastreo.
+var crawlIDRe = regexp.MustCompile(`^CC-MAIN-(\d{4})-(\d{2})$`)
+
+// Span devuelve el período del rastreo. Si el índice no lo informa se estima
+// por el identificador, que es el año y la semana ISO en que empezó (los
+// rastreos duran unas dos semanas); ok es false si tampoco se puede.
+func (c Collection) Span() (from, to time.Time, ok bool) {
+	from, errFrom := time.Parse(time.RFC3339, c.From)
+	to, errTo := time.Parse(time.RFC3339, c.To)
+	if errFrom == nil && errTo == nil {
+		return from, to, true
+	}
+	m := crawlIDRe.FindStringSubmatch(c.ID)
+	if m == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	year, _ := strconv.Atoi(m[1])
+	week, _ := strconv.Atoi(m[2])
+	// El 4 de enero cae siempre en la semana ISO 1.
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -(int(jan4.Weekday())+6)%7)
+	from = monday.AddDate(0, 0, 7*(week-1))
+	return from, from.AddDate(0, 0, 14), true
+}
+
+// Between devuelve los rastreos (del más reciente al más antiguo, como los
+// lista el índice) cuyo período se cruza con [from, to].
+func Between(colls []Collection, from, to time.Time) []Collection {
+	var out []Collection
+	for _, c := range colls {
+		start, end, ok := c.Span()
+		if !ok || (!to.IsZero() && start.After(to)) || (!from.IsZero() && end.Before(from)) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// Record es una captura en el índice. Los números llegan como texto.
+type Record struct {
+	URLKey       string `json:"urlkey"`
+	Timestamp    string `json:"timestamp"`
+	URL          string `json:"url"`
+	MIME         string `json:"mime"`
+	MIMEDetected string `json:"mime-detected"`
+	Status       string `json:"status"`
+	Digest       string `json:"digest"`
+	Length       string `json:"length"`
+	Offset       string `json:"offset"`
+	Filename     string `json:"filename"`
+	Languages    string `json:"languages"` // ISO 639-3 separados por coma, ej: spa,eng
+	Encoding     string `json:"encoding"`
+}
+
+// Captured es la fecha de la captura.
+func (r Record) Captured() time.Time {
+	t, _ := time.Parse(timestampLayout, r.Timestamp)
+	return t
+}
+
+// Crawl es el rastreo de la captura, que encabeza la ruta de su archivo
+// (crawl-data/CC-MAIN-2024-33/segments/...).
+func (r Record) Crawl() string {
+	parts := strings.Split(r.Filename, "/")
+	if len(parts) > 1 && parts[0] == "crawl-data" {
+		return parts[1]
+	}
+	return ""
+}
+
+// WARC es la ubicación del registro en el archivo de Common Crawl.
+func (r Record) WARC() *article.WARCRecord {
+	offset, _ := strconv.ParseInt(r.Offset, 10, 64)
+	length, _ := strconv.ParseInt(r.Length, 10, 64)
+	return &article.WARCRecord{
+		Crawl: r.Crawl(), Filename: r.Filename, Offset: offset, Length: length,
+		Digest: r.Digest, Captured: r.Captured(),
+	}
+}
+
+type Crawler struct {
+	// IndexURL es el servidor del índice y DataURL el de los archivos WARC.
+	IndexURL string
+	DataURL  string
+	Client   *http.Client
+}
+
+func NewCrawler() *Crawler {
+	return &Crawler{
+		IndexURL: "https://index.commoncrawl.org/",
+		DataURL:  "https://data.commoncrawl.org/",
+		Client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}
+}
+
+// Collections lista los rastreos, del más reciente al más antiguo.
+func (c *Crawler) Collections(ctx context.Context) ([]Collection, error) {
+	body, err := c.get(ctx, c.IndexURL+"collinfo.json", "")
+	if err != nil {
+		return nil, err
+	}
+	var out []Collection
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("error parseando JSON: %w. Respuesta recibida:\n%s", err, crawler.Preview(body))
+	}
+	return out, nil
+}
+
+// SearchOptions acota la búsqueda al rango de captura [From, To] y elige la
+// página del índice (desde 0; ver Pages).
+type SearchOptions struct {
+	From, To time.Time
+	Page     int
+}
+
+// Pages devuelve cuántas páginas tiene el resultado de domain en el rastreo.
+func (c *Crawler) Pages(ctx context.Context, coll Collection, domain string, opts SearchOptions) (int, error) {
+	params := searchParams(domain, opts)
+	params.Set("showNumPages", "true")
+	body, err := c.get(ctx, coll.CDXAPI+"?"+params.Encode(), "")
+	if err != nil {
+		return 0, err
+	}
+	if body == nil {
+		return 0, nil
+	}
+	var out struct {
+		Pages int `json:"pages"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return 0, fmt.Errorf("error parseando JSON: %w. Respuesta recibida:\n%s", err, crawler.Preview(body))
+	}
+	return out.Pages, nil
+}
+
+// Search pide una página de capturas de domain (ej: eltiempo.com, con sus
+// subdominios) que respondieron 200 con HTML.
+func (c *Crawler) Search(ctx context.Context, coll Collection, domain string, opts SearchOptions) ([]Record, error) {
+	params := searchParams(domain, opts)
+	params.Set("page", strconv.Itoa(opts.Page))
+	body, err := c.get(ctx, coll.CDXAPI+"?"+params.Encode(), "")
+	if err != nil {
+		return nil, err
+	}
+	return ParseIndex(body)
+}
+
+func searchParams(domain string, opts SearchOptions) url.Values {
+	params := url.Values{}
+	params.Set("url", domain)
+	params.Set("matchType", "domain")
+	params.Set("output", "json")
+	params.Add("filter", "=status:200")
+	params.Add("filter", "~mime:html")
+	if !opts.From.IsZero() {
+		params.Set("from", opts.From.UTC().Format(timestampLayout))
+	}
+	if !opts.To.IsZero() {
+		params.Set("to", opts.To.UTC().Format(timestampLayout))
+	}
+	return params
+}
+
+// ParseIndex lee una respuesta del índice: una captura JSON por línea.
+func ParseIndex(data []byte) ([]Record, error) {
+	var out []Record
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(line, &r); err != nil {
+			return nil, fmt.Errorf("error parseando JSON: %w. Respuesta recibida:\n%s", err, crawler.Preview(line))
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// Fetch lee el registro WARC de la captura (solo sus bytes, con un pedido
+// por rango) y devuelve el HTML de la página capturada.
+func (c *Crawler) Fetch(ctx context.Context, r Record) ([]byte, error) {
+	w := r.WARC()
+	if w.Filename == "" || w.Length <= 0 {
+		return nil, fmt.Errorf("la captura de %s no tiene registro WARC", r.URL)
+	}
+	body, err := c.get(ctx, c.DataURL+w.Filename, fmt.Sprintf("bytes=%d-%d", w.Offset, w.Offset+w.Length-1))
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, fmt.Errorf("no se encontró el registro WARC de %s", r.URL)
+	}
+	return ReadWARC(body)
+}
+
+// ReadWARC extrae el cuerpo de la respuesta HTTP de un registro WARC
+// comprimido (un miembro gzip con los encabezados WARC, los de HTTP y la
+// página).
+func ReadWARC(record []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(record))
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo registro WARC: %w", err)
+	}
+	defer gz.Close()
+	br := bufio.NewReader(gz)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo registro WARC: %w", err)
+		}
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+	}
+	// Common Crawl guarda la página ya sin transfer-encoding ni compresión
+	// (renombra esos encabezados): el cuerpo se lee tal cual.
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo la respuesta HTTP del registro WARC: %w", err)
+	}
+	defer resp.Body.Close()
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil && len(page) == 0 {
+		return nil, fmt.Errorf("error leyendo la página del registro WARC: %w", err)
+	}
+	return page, nil
+}
+
+// get descarga u; un 404 (el índice responde así cuando no hay capturas)
+// devuelve nil sin error. rangeHeader, si no está vacío, pide solo esos bytes.
+func (c *Crawler) get(ctx context.Context, u, rangeHeader string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+		return body, nil
+	case http.StatusNotFound:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("error de Common Crawl (HTTP %d): %s", resp.StatusCode, crawler.Preview(body))
+}
+
+// Normalize convierte la captura al modelo común del corpus con los datos de
+// la página (título, descripción, autor, fecha e idioma de sus metadatos).
+// Sin fecha de publicación en la página queda la de la captura, que es
+// posterior. page puede ser nil: quedan solo los datos del índice.
+func (r Record) Normalize(page []byte) *article.Article {
+	a := &article.Article{
+		Source:   "commoncrawl",
+		URL:      r.URL,
+		Domain:   crawler.Domain(r.URL),
+		Language: language(strings.Split(r.Languages, ",")[0]),
+		WARC:     r.WARC(),
+	}
+	var published string
+	if page != nil {
+		if doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page)); err == nil {
+			a.Title = first(meta(doc, "og:title"), strings.TrimSpace(doc.Find("title").First().Text()))
+			a.Summary = first(meta(doc, "og:description"), meta(doc, "description"))
+			a.Author = meta(doc, "author")
+			published = first(meta(doc, "article:published_time"), meta(doc, "datePublished"),
+				doc.Find(`[itemprop="datePublished"]`).First().AttrOr("content", ""))
+			if lang := language(doc.Find("html").AttrOr("lang", "")); lang != "" {
+				a.Language = lang
+			}
+		}
+	}
+	if published != "" {
+		a.Published, a.RawPublished = dates.Normalize(published)
+	}
+	if a.Published.IsZero() && a.RawPublished == "" {
+		a.Published = r.Captured()
+	}
+	return a
+}
+
+// meta devuelve el contenido de la etiqueta <meta> con ese name o property.
+func meta(doc *goquery.Document, name string) string {
+	sel := doc.Find(fmt.Sprintf(`meta[property=%q], meta[name=%q], meta[itemprop=%q]`, name, name, name)).First()
+	return strings.TrimSpace(sel.AttrOr("content", ""))
+}
+
+// languages traduce los códigos de tres letras (ISO 639-3) que detecta Common
+// Crawl para los idiomas más comunes.
+var languages = map[string]string{
+	"spa": "es", "eng": "en", "por": "pt", "fra": "fr", "deu": "de", "ita": "it", "cat": "ca",
+}
+
+// language normaliza un idioma a ISO 639-1 ("es-CO", "spa"); vacío si no lo
+// reconoce.
+func language(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(s, "_-"); i > 0 {
+		s = s[:i]
+	}
+	if l, ok := languages[s]; ok {
+		return l
+	}
+	if len(s) == 2 {
+		return s
+	}
+	return ""
+}
+
+// Slug escribe un término como aparece en las URLs de las notas: en
+// minúsculas, sin tildes y con guiones ("Universidad de Antioquia" ->
+// universidad-de-antioquia).
+func Slug(term string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	if out, _, err := transform.String(t, term); err == nil {
+		term = out
+	}
+	fields := strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, "-")
+}
+
+// first devuelve el primer valor no vacío.
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+// Package crawler reúne lo común a los clientes de cada fuente (guardian,
+// newsapi, bingnews, eventregistry, mediastack, currents, gdelt, x, rss,
+// googlenews, mastodon, bluesky, youtube, oai, commoncrawl, scrape), que
+// viven en sus propios subpaquetes.
+package crawler
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UserAgent identifica al recolector ante las APIs y los medios.
+const UserAgent = "EthicalCrawler/1.0 (StudentResearch)"
+
+// Sleep espera d o hasta que se cancele ctx; en ese caso devuelve ctx.Err().
+// Las pausas entre páginas o tramos la usan para no demorar una cancelación.
+func Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// KeyValue es una estructura auxiliar para ordenar mapas.
+type KeyValue struct {
+	Key   string
+	Value int
+}
+
+// TopN devuelve las n claves con más ocurrencias, de mayor a menor.
+func TopN(m map[string]int, n int) []KeyValue {
+	var kvList []KeyValue
+	for k, v := range m {
+		kvList = append(kvList, KeyValue{k, v})
+	}
+
+	sort.Slice(kvList, func(i, j int) bool {
+		if kvList[i].Value != kvList[j].Value {
+			return kvList[i].Value > kvList[j].Value
+		}
+		return kvList[i].Key < kvList[j].Key
+	})
+
+	if n > len(kvList) {
+		n = len(kvList)
+	}
+	return kvList[:n]
+}
+
+// Preview recorta el cuerpo de una respuesta para incluirlo en un error.
+func Preview(body []byte) string {
+	preview := string(body)
+	if len(preview) > 500 {
+		preview = preview[:500] + "..."
+	}
+	return preview
+}
+
+// Domain devuelve el host de una URL en minúsculas y sin "www.".
+func Domain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
@@ -0,0 +1,171 @@
+// Package currents consulta la búsqueda de noticias de Currents API. Como
+// Mediastack, no admite OR (se hace una búsqueda por término) y filtra un
+// solo idioma por búsqueda.
+package currents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+)
+
+// MaxPageSize es el máximo de resultados por página.
+const MaxPageSize = 200
+
+// ErrQuota indica que se agotaron las peticiones diarias del plan (HTTP 429).
+var ErrQuota = errors.New("se agotó la cuota de Currents API")
+
+// Response es una página de resultados.
+type Response struct {
+	Status string    `json:"status"`
+	News   []Article `json:"news"`
+	Page   int       `json:"page"`
+}
+
+// Article es una noticia. Image es "None" cuando no tiene.
+type Article struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	URL         string   `json:"url"`
+	Author      string   `json:"author"`
+	Image       string   `json:"image"`
+	Language    string   `json:"language"`
+	Category    []string `json:"category"`
+	Published   string   `json:"published"`
+}
+
+// APIError es una respuesta de error de la API.
+type APIError struct {
+	HTTPStatus int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error de Currents API (HTTP %d): %s", e.HTTPStatus, e.Message)
+}
+
+// Is permite errors.Is(err, ErrQuota).
+func (e *APIError) Is(target error) bool {
+	return target == ErrQuota && e.HTTPStatus == http.StatusTooManyRequests
+}
+
+type Crawler struct {
+	BaseURL string
+	Client  *http.Client
+	APIKey  string
+}
+
+func NewCrawler(apiKey string) *Crawler {
+	return &Crawler{
+		BaseURL: "https://api.currentsapi.services/v1/search",
+		Client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+		APIKey: apiKey,
+	}
+}
+
+// SearchOptions acota la búsqueda. Language es un solo idioma (ISO 639-1);
+// vacío, todos.
+type SearchOptions struct {
+	Language string
+	From, To time.Time
+	Count    int
+	Page     int // desde 1
+}
+
+// Search pide una página de noticias que mencionan q (una palabra o una
+// frase), de la más reciente hacia atrás.
+func (c *Crawler) Search(ctx context.Context, q string, opts SearchOptions) (*Response, error) {
+	params := url.Values{}
+	params.Set("keywords", q)
+	params.Set("page_size", strconv.Itoa(opts.Count))
+	params.Set("page_number", strconv.Itoa(max(opts.Page, 1)))
+	if opts.Language != "" {
+		params.Set("language", opts.Language)
+	}
+	if !opts.From.IsZero() {
+		params.Set("start_date", opts.From.UTC().Format(time.RFC3339))
+	}
+	if !opts.To.IsZero() {
+		params.Set("end_date", opts.To.UTC().Format(time.RFC3339))
+	}
+	fullURL := fmt.Sprintf("%s?%s", c.BaseURL, params.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.APIKey)
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// El mensaje llega en "msg" o en "message", según el error.
+		var apiErr struct {
+			Msg     string `json:"msg"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(body, &apiErr)
+		e := &APIError{HTTPStatus: resp.StatusCode, Message: apiErr.Msg}
+		if e.Message == "" {
+			e.Message = apiErr.Message
+		}
+		if e.Message == "" {
+			e.Message = crawler.Preview(body)
+		}
+		return nil, e
+	}
+	var out Response
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("error parseando JSON: %w. Respuesta recibida:\n%s", err, crawler.Preview(body))
+	}
+	return &out, nil
+}
+
+// Normalize convierte los resultados al modelo común del corpus; la imagen
+// queda como adjunto y las categorías, como sección.
+func (r *Response) Normalize() []*article.Article {
+	out := make([]*article.Article, 0, len(r.News))
+	for _, v := range r.News {
+		a := &article.Article{
+			Source:   "currents",
+			URL:      v.URL,
+			Title:    strings.TrimSpace(v.Title),
+			Domain:   crawler.Domain(v.URL),
+			Language: v.Language,
+			Section:  strings.Join(v.Category, ", "),
+			Summary:  v.Description,
+		}
+		if v.Author != "None" {
+			a.Author = v.Author
+		}
+		if v.Image != "" && v.Image != "None" {
+			a.Media = append(a.Media, article.Media{URL: v.Image})
+		}
+		a.Published, a.RawPublished = dates.Normalize(v.Published)
+		out = append(out, a)
+	}
+	return out
+}
@@ -0,0 +1,311 @@
+// Package eventregistry consulta la búsqueda de artículos de Event Registry
+// (NewsAPI.ai). Además de cada artículo, Event Registry agrupa la cobertura
+// de un mismo acontecimiento en eventos: los artículos traen la URI de su
+// evento y los datos del evento (título, resumen, total de artículos) se
+// piden aparte.
+package eventregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+)
+
+// MaxCount es el máximo de artículos por página; MaxEvents el de eventos por
+// consulta.
+const (
+	MaxCount  = 100
+	MaxEvents = 50
+)
+
+// ErrQuota indica que se agotaron los tokens del plan (cada página de
+// resultados consume uno).
+var ErrQuota = errors.New("se agotaron los tokens de Event Registry")
+
+// languages traduce ISO 639-1 a los códigos de tres letras de Event Registry.
+var languages = map[string]string{
+	"es": "spa", "en": "eng", "pt": "por", "fr": "fra", "de": "deu", "it": "ita",
+	"ca": "cat", "nl": "nld", "ru": "rus", "zh": "zho", "ar": "ara", "ja": "jpn",
+}
+
+// Language devuelve el código de Event Registry de un idioma ISO 639-1 (el
+// mismo valor si no lo conoce).
+func Language(iso string) string {
+	if l, ok := languages[strings.ToLower(iso)]; ok {
+		return l
+	}
+	return iso
+}
+
+// iso devuelve el código ISO 639-1 de un idioma de Event Registry, o "".
+func iso(lang string) string {
+	for k, v := range languages {
+		if v == lang {
+			return k
+		}
+	}
+	return ""
+}
+
+// Response junta las respuestas de las dos consultas: Articles, de la
+// búsqueda de artículos, y Events, de los eventos de esos artículos.
+type Response struct {
+	Articles struct {
+		Results      []Article `json:"results"`
+		TotalResults int       `json:"totalResults"`
+		Page         int       `json:"page"`
+		Pages        int       `json:"pages"`
+	} `json:"articles"`
+	Events struct {
+		Results []Event `json:"results"`
+	} `json:"events"`
+}
+
+// Article es un artículo. Lang es el idioma en tres letras; EventURI, vacío
+// si Event Registry no lo agrupó en un evento.
+type Article struct {
+	URI         string `json:"uri"`
+	Lang        string `json:"lang"`
+	IsDuplicate bool   `json:"isDuplicate"`
+	DateTime    string `json:"dateTime"`
+	DateTimePub string `json:"dateTimePub"`
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Body        string `json:"body"`
+	Source      struct {
+		URI   string `json:"uri"`
+		Title string `json:"title"`
+	} `json:"source"`
+	Authors []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	Image    string `json:"image"`
+	EventURI string `json:"eventUri"`
+}
+
+// Event es un evento. Title y Summary vienen por idioma (tres letras).
+type Event struct {
+	URI               string            `json:"uri"`
+	EventDate         string            `json:"eventDate"`
+	TotalArticleCount int               `json:"totalArticleCount"`
+	Title             map[string]string `json:"title"`
+	Summary           map[string]string `json:"summary"`
+}
+
+// APIError es una respuesta de error de la API.
+type APIError struct {
+	HTTPStatus int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error de Event Registry (HTTP %d): %s", e.HTTPStatus, e.Message)
+}
+
+// Is permite errors.Is(err, ErrQuota).
+func (e *APIError) Is(target error) bool {
+	return target == ErrQuota && (e.HTTPStatus == http.StatusPaymentRequired || e.HTTPStatus == http.StatusTooManyRequests ||
+		strings.Contains(strings.ToLower(e.Message), "tokens"))
+}
+
+type Crawler struct {
+	BaseURL string
+	Client  *http.Client
+	APIKey  string
+}
+
+func NewCrawler(apiKey string) *Crawler {
+	return &Crawler{
+		BaseURL: "https://eventregistry.org/api/v1",
+		Client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		APIKey: apiKey,
+	}
+}
+
+// SearchOptions acota la búsqueda. Event Registry filtra por día: el rango
+// exacto se aplica al recibir.
+type SearchOptions struct {
+	Languages []string // ISO 639-1
+	From, To  time.Time
+	Count     int
+	Page      int // desde 1
+}
+
+// Search pide una página de artículos que mencionan alguno de keywords (cada
+// uno, palabra o frase), del más reciente hacia atrás.
+func (e *Crawler) Search(ctx context.Context, keywords []string, opts SearchOptions) (*Response, error) {
+	params := map[string]any{
+		"action":                 "getArticles",
+		"resultType":             "articles",
+		"keyword":                keywords,
+		"keywordOper":            "or",
+		"articlesPage":           max(opts.Page, 1),
+		"articlesCount":          opts.Count,
+		"articlesSortBy":         "date",
+		"includeArticleEventUri": true,
+		"includeArticleImage":    true,
+	}
+	if len(opts.Languages) > 0 {
+		var langs []string
+		for _, l := range opts.Languages {
+			langs = append(langs, Language(l))
+		}
+		params["lang"] = langs
+	}
+	if !opts.From.IsZero() {
+		params["dateStart"] = opts.From.UTC().Format("2006-01-02")
+	}
+	if !opts.To.IsZero() {
+		params["dateEnd"] = opts.To.UTC().Format("2006-01-02")
+	}
+	var out Response
+	if err := e.post(ctx, "/article/getArticles", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Events pide los datos de los eventos (como máximo MaxEvents).
+func (e *Crawler) Events(ctx context.Context, uris []string) (*Response, error) {
+	params := map[string]any{
+		"action":                    "getEvents",
+		"resultType":                "events",
+		"eventUriList":              strings.Join(uris, ","),
+		"eventsCount":               len(uris),
+		"includeEventSummary":       true,
+		"includeEventArticleCounts": true,
+	}
+	var out Response
+	if err := e.post(ctx, "/event/getEvents", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// post envía la consulta como JSON, con la credencial en el cuerpo como pide
+// la API, y decodifica la respuesta en out.
+func (e *Crawler) post(ctx context.Context, path string, params map[string]any, out any) error {
+	params["apiKey"] = e.APIKey
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", e.BaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	// Los errores llegan como {"error": "..."}, a veces con HTTP 200.
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+	json.Unmarshal(body, &apiErr)
+	if resp.StatusCode != http.StatusOK || apiErr.Error != "" {
+		msg := apiErr.Error
+		if msg == "" {
+			msg = crawler.Preview(body)
+		}
+		return &APIError{HTTPStatus: resp.StatusCode, Message: msg}
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error parseando JSON: %w. Respuesta recibida:\n%s", err, crawler.Preview(body))
+	}
+	return nil
+}
+
+// EventURIs son las URIs de los eventos de los artículos, sin repetir.
+func (r *Response) EventURIs() []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, a := range r.Articles.Results {
+		if a.EventURI != "" && !seen[a.EventURI] {
+			seen[a.EventURI] = true
+			out = append(out, a.EventURI)
+		}
+	}
+	return out
+}
+
+// Normalize convierte los artículos al modelo común del corpus, cada uno con
+// su evento. De un evento sin datos (no se pidieron o no llegaron) queda
+// solo la URI; el título y el resumen van en el idioma del artículo o, si no
+// lo hay, en inglés.
+func (r *Response) Normalize() []*article.Article {
+	events := make(map[string]Event, len(r.Events.Results))
+	for _, ev := range r.Events.Results {
+		events[ev.URI] = ev
+	}
+	out := make([]*article.Article, 0, len(r.Articles.Results))
+	for _, v := range r.Articles.Results {
+		a := &article.Article{
+			Source:   "eventregistry",
+			URL:      v.URL,
+			Title:    v.Title,
+			Domain:   crawler.Domain(v.URL),
+			Language: iso(v.Lang),
+			Body:     v.Body,
+		}
+		var authors []string
+		for _, au := range v.Authors {
+			authors = append(authors, au.Name)
+		}
+		a.Author = strings.Join(authors, ", ")
+		if v.Image != "" {
+			a.Media = append(a.Media, article.Media{URL: v.Image})
+		}
+		a.Published, a.RawPublished = dates.Normalize(v.DateTimePub, v.DateTime)
+		if v.EventURI != "" {
+			a.Event = &article.Event{URI: v.EventURI}
+			if ev, ok := events[v.EventURI]; ok {
+				a.Event.Title = localized(ev.Title, v.Lang)
+				a.Event.Summary = localized(ev.Summary, v.Lang)
+				a.Event.Date, a.Event.Articles = ev.EventDate, ev.TotalArticleCount
+			}
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// localized elige el texto en lang, si no en inglés y si no el primero en
+// orden de idioma.
+func localized(texts map[string]string, lang string) string {
+	if t := texts[lang]; t != "" {
+		return t
+	}
+	if t := texts["eng"]; t != "" {
+		return t
+	}
+	first := ""
+	for l, t := range texts {
+		if t != "" && (first == "" || l < first) {
+			first = l
+		}
+	}
+	return texts[first]
+}
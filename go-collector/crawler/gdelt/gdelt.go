@@ -0,0 +1,311 @@
+// Package gdelt consulta la API DOC 2.0 de GDELT en modo artlist.
+package gdelt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+)
+
+type Response struct {
+	Articles []Article `json:"articles"`
+}
+
+// Article es un artículo. Title es el título en el idioma del artículo;
+// TitleTranslated, su traducción al inglés, solo llega si se pidió
+// (Crawler.Translate) y el artículo no está en inglés.
+type Article struct {
+	URL             string `json:"url"`
+	URLMobile       string `json:"urlmobile"`
+	Title           string `json:"title"`
+	TitleTranslated string `json:"title_translated"`
+	SeenDate        string `json:"seendate"`
+	SocialImg       string `json:"socialimage"`
+	Domain          string `json:"domain"`
+	Language        string `json:"language"`
+	SourceCountry   string `json:"sourcecountry"`
+}
+
+type Crawler struct {
+	BaseURL string
+	Client  *http.Client
+	// Translate pide a GDELT la traducción automática de los resultados
+	// (trans=googtrans): los títulos que no están en inglés llegan también
+	// traducidos, sin perder el original.
+	Translate bool
+}
+
+func NewCrawler() *Crawler {
+	return &Crawler{
+		BaseURL: "https://api.gdeltproject.org/api/v2/doc/doc",
+		Client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// BuscarArticulosMultiLang realiza una búsqueda en GDELT, permitiendo múltiples idiomas.
+func (g *Crawler) BuscarArticulosMultiLang(ctx context.Context, queryRaw string, idiomas []string, fechaInicio, fechaFin string, maxRecords int) (*Response, error) {
+
+	// 1. Construir el filtro de idiomas: (sourceLang:spanish OR sourceLang:english)
+	langFilters := make([]string, len(idiomas))
+	for i, lang := range idiomas {
+		langFilters[i] = fmt.Sprintf("sourceLang:%s", lang)
+	}
+
+	langSegment := strings.Join(langFilters, " OR ")
+
+	finalQuery := fmt.Sprintf(`(%s) AND (%s)`, queryRaw, langSegment)
+
+	// 3. Construir URL con parámetros
+	params := url.Values{}
+	params.Add("query", finalQuery)
+	params.Add("mode", "artlist")
+	params.Add("maxrecords", fmt.Sprintf("%d", maxRecords))
+	params.Add("format", "json")
+	params.Add("startdatetime", fechaInicio)
+	params.Add("enddatetime", fechaFin)
+	if g.Translate {
+		params.Add("trans", "googtrans")
+	}
+
+	fullURL := fmt.Sprintf("%s?%s", g.BaseURL, params.Encode())
+
+	fmt.Printf("Consultando GDELT...\nQuery: %s\nRango: %s - %s\n", finalQuery, fechaInicio, fechaFin)
+
+	// 4. Crear request con User-Agent
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+
+	// 5. Realizar petición
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 6. Leer respuesta
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error HTTP: status code %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	// 7. Parsear JSON (con debug de errores)
+	var gdeltResp Response
+	if err := json.Unmarshal(body, &gdeltResp); err != nil {
+		return nil, fmt.Errorf("error parseando JSON: %w. \nRespuesta recibida (Inicio):\n%s", err, crawler.Preview(body))
+	}
+
+	return &gdeltResp, nil
+}
+
+func (g *Crawler) ExplorarDatos(response *Response) {
+	if response == nil || len(response.Articles) == 0 {
+		fmt.Println("\n--- EXPLORACIÓN DE DATOS ---")
+		fmt.Println("No se encontraron artículos que coincidan con la búsqueda y los filtros.")
+		return
+	}
+
+	fmt.Println("\n--- EXPLORACIÓN DE DATOS - GDELT ---")
+	fmt.Printf("Total de artículos: %d\n\n", len(response.Articles))
+
+	// Contadores
+	dominios := make(map[string]int)
+	idiomas := make(map[string]int)
+	paises := make(map[string]int)
+
+	for _, art := range response.Articles {
+		dominios[art.Domain]++
+		idiomas[art.Language]++
+		paises[art.SourceCountry]++
+	}
+
+	// Mostrar top 10 dominios
+	fmt.Println("Top 10 Dominios:")
+	topDominios := crawler.TopN(dominios, 10)
+	for i, item := range topDominios {
+		fmt.Printf("  %2d. %-30s (%d artículos)\n", i+1, item.Key, item.Value)
+	}
+
+	// Mostrar idiomas
+	fmt.Println("\nDistribución por Idioma:")
+	for _, item := range crawler.TopN(idiomas, len(idiomas)) {
+		fmt.Printf("  %s: %d\n", item.Key, item.Value)
+	}
+
+	// Mostrar primeros 5 artículos
+	fmt.Println("\nPrimeros 5 Artículos de Muestra:")
+	for i, art := range response.Articles {
+		if i >= 5 {
+			break
+		}
+		fmt.Printf("\n  %d. Título: %s\n", i+1, art.Title)
+		fmt.Printf("      Dominio: %s | Idioma: %s | País: %s\n", art.Domain, art.Language, art.SourceCountry)
+		fmt.Printf("      Fecha: %s\n", art.SeenDate)
+		fmt.Printf("      URL: %s\n", art.URL)
+	}
+}
+
+// languageNames son los nombres de idioma que acepta sourceLang (ISO 639-1 -> GDELT).
+var languageNames = map[string]string{
+	"es": "spanish",
+	"en": "english",
+	"pt": "portuguese",
+	"fr": "french",
+	"de": "german",
+	"it": "italian",
+}
+
+// Languages traduce códigos ISO 639-1 a los nombres de sourceLang; los que no
+// conoce se pasan tal cual.
+func Languages(codes []string) []string {
+	var out []string
+	for _, code := range codes {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if code == "" {
+			continue
+		}
+		if name, ok := languageNames[code]; ok {
+			out = append(out, name)
+		} else {
+			out = append(out, code)
+		}
+	}
+	return out
+}
+
+// languageCode es la inversa de Languages para el campo language de la respuesta.
+func languageCode(name string) string {
+	name = strings.ToLower(name)
+	for code, n := range languageNames {
+		if n == name {
+			return code
+		}
+	}
+	return name
+}
+
+// Normalize convierte los resultados al modelo común del corpus. El título
+// traducido se conserva aparte solo si difiere del original.
+func (r *Response) Normalize() []*article.Article {
+	out := make([]*article.Article, 0, len(r.Articles))
+	for _, a := range r.Articles {
+		domain := a.Domain
+		if domain == "" {
+			domain = crawler.Domain(a.URL)
+		}
+		art := &article.Article{
+			Source:   "gdelt",
+			URL:      a.URL,
+			Title:    a.Title,
+			Domain:   strings.TrimPrefix(strings.ToLower(domain), "www."),
+			Language: languageCode(a.Language),
+		}
+		if t := strings.TrimSpace(a.TitleTranslated); t != "" && t != strings.TrimSpace(a.Title) {
+			art.TitleTranslated = t
+		}
+		// seendate viene como 20231005T120000Z.
+		art.Published, art.RawPublished = dates.Normalize(a.SeenDate)
+		if a.SocialImg != "" {
+			// GDELT no informa el tipo de la imagen.
+			art.Media = []article.Media{{URL: a.SocialImg}}
+		}
+		out = append(out, art)
+	}
+	return out
+}
+
+// gdeltTime es el formato de startdatetime/enddatetime.
+const gdeltTime = "20060102150405"
+
+// MaxRecords es el máximo de artículos que GDELT devuelve por consulta.
+const MaxRecords = 250
+
+// TramosOptions ajusta BuscarPorTramos.
+type TramosOptions struct {
+	// Tramo es el largo de cada sub-rango (por defecto 7 días).
+	Tramo time.Duration
+	// MinTramo es el largo mínimo al subdividir un tramo que llegó al tope
+	// de 250 artículos (por defecto 1 hora).
+	MinTramo time.Duration
+	// Pausa entre consultas; GDELT pide no más de una cada 5 segundos.
+	Pausa time.Duration
+	// Progreso, si no es nil, se llama después de cada consulta.
+	Progreso func(desde, hasta time.Time, recibidos int)
+}
+
+// BuscarPorTramos parte el rango en sub-rangos, consulta cada uno en orden y
+// une los resultados sin repetir URLs. Un tramo que devuelve el máximo de
+// registros seguramente quedó truncado: se divide a la mitad y se vuelve a
+// consultar, hasta MinTramo. Si una consulta falla se devuelve lo reunido
+// hasta ese momento junto con el error.
+func (g *Crawler) BuscarPorTramos(ctx context.Context, queryRaw string, idiomas []string, desde, hasta time.Time, opts TramosOptions) (*Response, error) {
+	if opts.Tramo <= 0 {
+		opts.Tramo = 7 * 24 * time.Hour
+	}
+	if opts.MinTramo <= 0 {
+		opts.MinTramo = time.Hour
+	}
+
+	merged := &Response{}
+	seen := make(map[string]bool)
+	first := true
+	var consultar func(desde, hasta time.Time) error
+	consultar = func(desde, hasta time.Time) error {
+		if !first && opts.Pausa > 0 {
+			if err := crawler.Sleep(ctx, opts.Pausa); err != nil {
+				return err
+			}
+		}
+		first = false
+		resp, err := g.BuscarArticulosMultiLang(ctx, queryRaw, idiomas, desde.UTC().Format(gdeltTime), hasta.UTC().Format(gdeltTime), MaxRecords)
+		if err != nil {
+			return fmt.Errorf("tramo %s a %s: %w", desde.Format("2006-01-02 15:04"), hasta.Format("2006-01-02 15:04"), err)
+		}
+		if opts.Progreso != nil {
+			opts.Progreso(desde, hasta, len(resp.Articles))
+		}
+		if len(resp.Articles) >= MaxRecords && hasta.Sub(desde) > opts.MinTramo {
+			mitad := desde.Add(hasta.Sub(desde) / 2).Truncate(time.Second)
+			if err := consultar(desde, mitad); err != nil {
+				return err
+			}
+			return consultar(mitad.Add(time.Second), hasta)
+		}
+		for _, a := range resp.Articles {
+			if seen[a.URL] {
+				continue
+			}
+			seen[a.URL] = true
+			merged.Articles = append(merged.Articles, a)
+		}
+		return nil
+	}
+
+	for inicio := desde; !inicio.After(hasta); inicio = inicio.Add(opts.Tramo) {
+		fin := inicio.Add(opts.Tramo - time.Second)
+		if fin.After(hasta) {
+			fin = hasta
+		}
+		if err := consultar(inicio, fin); err != nil {
+			return merged, err
+		}
+	}
+	return merged, nil
+}
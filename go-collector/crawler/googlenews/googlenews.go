@@ -0,0 +1,384 @@
+// Package googlenews arma los feeds RSS de búsqueda de Google News
+// (news.google.com/rss/search) a partir de una consulta, un idioma y una
+// región, y resuelve sus enlaces, que apuntan a Google, a la URL del medio.
+//
+// Los feeds se leen con el mismo lector que los demás (crawler/rss); lo propio
+// de Google News es conservar el medio de cada entrada (<source>), quitarlo
+// del título y reemplazar el enlace de Google por el del artículo.
+package googlenews
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	rssfeed "github.com/mmcdole/gofeed/rss"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/crawler/rss"
+	"go-collector/shortlink"
+	"go-collector/storage"
+)
+
+// BaseURL es la dirección de Google News.
+const BaseURL = "https://news.google.com"
+
+// retryAfter es cuánto se recuerda un enlace que no se pudo resolver antes de
+// volver a intentarlo.
+const retryAfter = 24 * time.Hour
+
+// ErrUnresolved indica que Google no entregó la URL del artículo.
+var ErrUnresolved = errors.New("Google News no entregó la URL del artículo")
+
+// Edition es la edición de Google News de un idioma y un país: hl, gl y ceid
+// en la URL del feed.
+type Edition struct {
+	Language string // hl, ej: es-419
+	Country  string // gl, ej: CO
+}
+
+// NewEdition elige la edición para un idioma (ISO 639-1) y un país (ISO
+// 3166-1 alfa-2). Google publica el español de América como es-419 y el
+// inglés, el portugués y el chino por país (en-US, pt-BR); los demás idiomas
+// van sin región.
+func NewEdition(language, country string) Edition {
+	language, country = strings.ToLower(language), strings.ToUpper(country)
+	hl := language
+	switch language {
+	case "es":
+		if country != "ES" {
+			hl = "es-419"
+		}
+	case "en", "pt", "zh":
+		hl = language + "-" + country
+	}
+	return Edition{Language: hl, Country: country}
+}
+
+// CEID es el identificador de la edición, ej: "CO:es-419".
+func (e Edition) CEID() string {
+	return e.Country + ":" + e.Language
+}
+
+// SearchURL es el feed de búsqueda de q en la edición. from y to (en cero no
+// limitan) van como los operadores after: y before: de la búsqueda, que
+// trabajan por día: before es exclusivo, así que se pide hasta el día
+// siguiente a to.
+func SearchURL(q string, ed Edition, from, to time.Time) string {
+	if !from.IsZero() {
+		q += " after:" + from.UTC().Format("2006-01-02")
+	}
+	if !to.IsZero() {
+		q += " before:" + to.UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	}
+	params := url.Values{}
+	params.Set("q", q)
+	params.Set("hl", ed.Language)
+	params.Set("gl", ed.Country)
+	params.Set("ceid", ed.CEID())
+	return BaseURL + "/rss/search?" + params.Encode()
+}
+
+// NewCrawler crea el lector de feeds de RSS con Translator.
+func NewCrawler() *rss.Crawler {
+	r := rss.NewCrawler()
+	r.Parser.RSSTranslator = Translator{}
+	return r
+}
+
+// Translator traduce los feeds RSS como el de gofeed, pero conserva el medio
+// de cada entrada (el <source> que el genérico descarta) en Custom["source"]
+// y Custom["source_url"].
+type Translator struct{}
+
+func (Translator) Translate(feed any) (*gofeed.Feed, error) {
+	out, err := (&gofeed.DefaultRSSTranslator{}).Translate(feed)
+	if err != nil {
+		return nil, err
+	}
+	orig, ok := feed.(*rssfeed.Feed)
+	if !ok || len(orig.Items) != len(out.Items) {
+		return out, nil
+	}
+	for i, it := range orig.Items {
+		if it.Source == nil {
+			continue
+		}
+		if out.Items[i].Custom == nil {
+			out.Items[i].Custom = make(map[string]string)
+		}
+		out.Items[i].Custom["source"] = strings.TrimSpace(it.Source.Title)
+		out.Items[i].Custom["source_url"] = strings.TrimSpace(it.Source.URL)
+	}
+	return out, nil
+}
+
+// Normalize convierte las entradas de un feed de Google News al modelo común
+// del corpus. El título pierde el " - Medio" que agrega Google y el resumen
+// (enlaces de vuelta a Google) se descarta. Los enlaces que se pueden
+// decodificar sin red pasan a la URL del medio; los demás quedan con el de
+// Google, con el dominio del medio, hasta que los resuelva un Resolver.
+func Normalize(feed *gofeed.Feed) []*article.Article {
+	out := rss.Normalize(feed)
+	for i, a := range out {
+		item := feed.Items[i]
+		a.Source = "googlenews"
+		a.Summary = ""
+		publisher, publisherURL := item.Custom["source"], item.Custom["source_url"]
+		if publisher != "" {
+			a.Title = strings.TrimSuffix(a.Title, " - "+publisher)
+		}
+		if target, ok := Decode(a.URL); ok {
+			a.URL = target
+			a.Domain = crawler.Domain(target)
+		} else if publisherURL != "" {
+			a.Domain = crawler.Domain(publisherURL)
+		}
+	}
+	return out
+}
+
+// IsLink indica si el enlace es de un artículo de Google News.
+func IsLink(raw string) bool {
+	return articleID(raw) != ""
+}
+
+// articleID es el identificador del artículo en un enlace de Google News
+// (.../rss/articles/<id>?oc=5 o .../articles/<id>), o "" si no lo es.
+func articleID(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() != "news.google.com" {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	switch parts[len(parts)-2] {
+	case "articles", "read":
+		return parts[len(parts)-1]
+	}
+	return ""
+}
+
+// Decode obtiene sin red la URL del medio de un enlace de Google News. El
+// identificador es un mensaje protobuf en base64 que en los enlaces antiguos
+// lleva la URL; los actuales llevan otro identificador y hay que pedirla (ver
+// Resolver).
+func Decode(raw string) (string, bool) {
+	id := articleID(raw)
+	if id == "" {
+		return "", false
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(id, "="))
+	if err != nil {
+		return "", false
+	}
+	data, ok := bytes.CutPrefix(data, []byte{0x08, 0x13, 0x22})
+	if !ok {
+		return "", false
+	}
+	n, size := binary.Uvarint(data)
+	if size <= 0 || uint64(len(data)-size) < n {
+		return "", false
+	}
+	target := string(data[size : size+int(n)])
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		return "", false
+	}
+	return target, true
+}
+
+// Resolver obtiene la URL del medio de los enlaces de Google News: los
+// antiguos se decodifican (Decode) y para los actuales se pide la página del
+// artículo, que trae una firma, y con ella la URL a la API interna de Google
+// News. Cada enlace se pide una sola vez por Resolver; con Cache, una sola
+// vez entre rondas.
+type Resolver struct {
+	BaseURL string
+	Client  *http.Client
+	// Cache, si no es nil, guarda las resoluciones entre rondas (la misma
+	// tabla que los enlaces acortados).
+	Cache shortlink.Cache
+
+	mu   sync.Mutex
+	seen map[string]string
+}
+
+func NewResolver(cache shortlink.Cache) *Resolver {
+	return &Resolver{
+		BaseURL: BaseURL,
+		Client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+		Cache: cache,
+		seen:  map[string]string{},
+	}
+}
+
+// Resolve devuelve la URL del medio del enlace; uno que no es de Google News
+// se devuelve tal cual.
+func (r *Resolver) Resolve(ctx context.Context, raw string) (string, error) {
+	id := articleID(raw)
+	if id == "" {
+		return raw, nil
+	}
+	if target, ok := Decode(raw); ok {
+		return target, nil
+	}
+	r.mu.Lock()
+	target, ok := r.seen[id]
+	r.mu.Unlock()
+	if ok {
+		return target, nil
+	}
+
+	key := r.BaseURL + "/articles/" + id
+	if r.Cache != nil {
+		l, err := r.Cache.GetShortLink(key)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return "", err
+		}
+		if l != nil && (l.Err == "" || time.Since(l.Resolved) < retryAfter) {
+			if l.Err != "" {
+				return "", errors.New(l.Err)
+			}
+			r.remember(id, l.Target)
+			return l.Target, nil
+		}
+	}
+
+	target, err := r.fetch(ctx, id)
+	if ctx.Err() != nil {
+		// Cancelado: no es una falla del enlace, no se recuerda.
+		return "", err
+	}
+	if r.Cache != nil {
+		l := &storage.ShortLink{URL: key, Target: target, Resolved: time.Now()}
+		if err != nil {
+			l.Err = err.Error()
+		}
+		if serr := r.Cache.SaveShortLink(l); serr != nil {
+			return "", serr
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	r.remember(id, target)
+	return target, nil
+}
+
+func (r *Resolver) remember(id, target string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seen[id] = target
+}
+
+var (
+	signatureRe = regexp.MustCompile(`data-n-a-sg="([^"]+)"`)
+	timestampRe = regexp.MustCompile(`data-n-a-ts="([0-9]+)"`)
+)
+
+// fetch pide la firma del artículo a su página y con ella la URL.
+func (r *Resolver) fetch(ctx context.Context, id string) (string, error) {
+	page, err := r.do(ctx, "GET", r.BaseURL+"/rss/articles/"+id, nil)
+	if err != nil {
+		return "", err
+	}
+	sig, ts := signatureRe.FindSubmatch(page), timestampRe.FindSubmatch(page)
+	if sig == nil || ts == nil {
+		return "", fmt.Errorf("%w: la página del artículo no trae la firma", ErrUnresolved)
+	}
+	stamp, err := strconv.ParseInt(string(ts[1]), 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("%w: marca de tiempo inválida %q", ErrUnresolved, ts[1])
+	}
+
+	req, err := json.Marshal([]any{"garturlreq", []any{
+		[]any{"X", "X", []any{"X", "X"}, nil, nil, 1, 1, "US:en", nil, 1, nil, nil, nil, nil, nil, 0, 1},
+		"X", "X", 1, []any{1, 1, 1}, 1, 1, nil, 0, 0, nil, 0,
+	}, id, stamp, string(sig[1])})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal([][][]any{{{"Fbv4je", string(req), nil, "generic"}}})
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{"f.req": {string(payload)}}
+	body, err := r.do(ctx, "POST", r.BaseURL+"/_/DotsSplashUi/data/batchexecute", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	return parseBatch(body)
+}
+
+// parseBatch extrae la URL de la respuesta de batchexecute: un prefijo
+// contra XSSI (")]}'") y un arreglo de filas cuya tercera columna es, a su
+// vez, JSON: ["garturlres","<url>",...].
+func parseBatch(body []byte) (string, error) {
+	_, rest, ok := bytes.Cut(body, []byte("\n\n"))
+	if !ok {
+		return "", fmt.Errorf("%w: respuesta inesperada:\n%s", ErrUnresolved, crawler.Preview(body))
+	}
+	var rows [][]any
+	if err := json.NewDecoder(bytes.NewReader(rest)).Decode(&rows); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnresolved, err)
+	}
+	for _, row := range rows {
+		if len(row) < 3 || row[0] != "wrb.fr" {
+			continue
+		}
+		inner, ok := row[2].(string)
+		if !ok {
+			continue
+		}
+		var res []any
+		if err := json.Unmarshal([]byte(inner), &res); err != nil || len(res) < 2 {
+			continue
+		}
+		if target, ok := res[1].(string); ok && strings.HasPrefix(target, "http") {
+			return target, nil
+		}
+	}
+	return "", fmt.Errorf("%w: respuesta inesperada:\n%s", ErrUnresolved, crawler.Preview(body))
+}
+
+func (r *Resolver) do(ctx context.Context, method, u string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=UTF-8")
+	}
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error HTTP: status code %d. Respuesta de Google News:\n%s", resp.StatusCode, crawler.Preview(data))
+	}
+	return data, nil
+}
@@ -0,0 +1,180 @@
+// Package guardian consulta la API de contenidos de The Guardian.
+package guardian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+)
+
+// Response mapea el objeto 'response' de la API
+type Response struct {
+	Response struct {
+		Status      string    `json:"status"`
+		Total       int       `json:"total"`
+		PageSize    int       `json:"pageSize"`
+		CurrentPage int       `json:"currentPage"`
+		Pages       int       `json:"pages"`
+		Results     []Article `json:"results"`
+	} `json:"response"`
+}
+
+// Article mapea los campos relevantes
+type Article struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	SectionName        string `json:"sectionName"`
+	WebTitle           string `json:"webTitle"`
+	WebUrl             string `json:"webUrl"`
+	WebPublicationDate string `json:"webPublicationDate"`
+}
+
+// Crawler encapsula la lógica de conexión
+type Crawler struct {
+	BaseURL string
+	Client  *http.Client
+	APIKey  string
+}
+
+func NewCrawler(apiKey string) *Crawler {
+	return &Crawler{
+		BaseURL: "https://content.guardianapis.com/search",
+		Client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+		APIKey: apiKey,
+	}
+}
+
+// BuscarArticulos realiza una búsqueda en The Guardian API.
+// La API usa formato ISO 8601 para fechas.
+func (g *Crawler) BuscarArticulos(ctx context.Context, queryRaw string, fechaInicio, fechaFin string, pageSize int) (*Response, error) {
+
+	// 1. Construir la Query: No necesita el operador AND/OR de idioma,
+	// pero sí la expansión de términos.
+	// La query aquí se mantiene simple para el parámetro 'q'.
+	finalQuery := strings.ReplaceAll(queryRaw, `OR`, `|`)
+	finalQuery = strings.ReplaceAll(finalQuery, `"`, ``)
+
+	// 2. Construir URL con parámetros
+	params := url.Values{}
+	params.Add("api-key", g.APIKey)
+	params.Add("q", finalQuery)
+	// Solo buscamos artículos (no secciones, tags, etc.)
+	params.Add("type", "article")
+	params.Add("page-size", fmt.Sprintf("%d", pageSize))
+
+	// Fechas en formato ISO 8601 (YYYY-MM-DD)
+	params.Add("from-date", fechaInicio)
+	params.Add("to-date", fechaFin)
+
+	// Filtro de idioma/sección (Guardian no tiene filtro de idioma nativo como NewsAPI)
+	// Sin embargo, podemos filtrar por secciones o tags relacionados con Colombia.
+
+	fullURL := fmt.Sprintf("%s?%s", g.BaseURL, params.Encode())
+
+	fmt.Printf("Consultando The Guardian...\nQuery: %s\nRango: %s a %s\n",
+		finalQuery, fechaInicio, fechaFin)
+
+	// 3. Realizar petición (no se requiere User-Agent especial para esta API)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 4. Leer respuesta
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error HTTP: status code %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	// 5. Parsear JSON
+	var apiResp Response
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("error parseando JSON: %w. Respuesta recibida (Inicio):\n%s", err, crawler.Preview(body))
+	}
+
+	// Comprobación de status dentro del cuerpo (específico de The Guardian)
+	if apiResp.Response.Status != "ok" {
+		return nil, fmt.Errorf("error de Guardian API (Status: %s).", apiResp.Response.Status)
+	}
+
+	return &apiResp, nil
+}
+
+// ExplorarDatos muestra estadísticas básicas
+func (g *Crawler) ExplorarDatos(response *Response) {
+	respData := response.Response
+	if len(respData.Results) == 0 {
+		fmt.Println("\n--- EXPLORACIÓN DE DATOS ---")
+		fmt.Printf("No se encontraron artículos. Total de resultados reportados: %d\n", respData.Total)
+		return
+	}
+
+	fmt.Println("\n--- EXPLORACIÓN DE DATOS - THE GUARDIAN ---")
+	fmt.Printf("Total de artículos encontrados (en el archivo): %d\n", respData.Total)
+	fmt.Printf("Artículos recuperados (página): %d\n\n", len(respData.Results))
+
+	// Contador de secciones
+	secciones := make(map[string]int)
+
+	for _, art := range respData.Results {
+		secciones[art.SectionName]++
+	}
+
+	// Mostrar top 5 secciones
+	fmt.Println("Top 5 Secciones:")
+	topSecciones := crawler.TopN(secciones, 5)
+	for i, item := range topSecciones {
+		fmt.Printf("  %2d. %-20s (%d artículos)\n", i+1, item.Key, item.Value)
+	}
+
+	// Mostrar primeros 5 artículos
+	fmt.Println("\nPrimeros 5 Artículos de Muestra:")
+	for i, art := range respData.Results {
+		if i >= 5 {
+			break
+		}
+		fmt.Printf("\n  %d. Título: %s\n", i+1, art.WebTitle)
+		fmt.Printf("      Sección: %s\n", art.SectionName)
+		fmt.Printf("      Publicado: %s\n", art.WebPublicationDate)
+		fmt.Printf("      URL: %s\n", art.WebUrl)
+	}
+}
+
+// Normalize convierte los resultados al modelo común del corpus. The Guardian
+// publica en inglés.
+func (r *Response) Normalize() []*article.Article {
+	out := make([]*article.Article, 0, len(r.Response.Results))
+	for _, a := range r.Response.Results {
+		art := &article.Article{
+			Source:   "guardian",
+			URL:      a.WebUrl,
+			Title:    a.WebTitle,
+			Domain:   crawler.Domain(a.WebUrl),
+			Language: "en",
+			Section:  a.SectionName,
+		}
+		art.Published, art.RawPublished = dates.Normalize(a.WebPublicationDate)
+		out = append(out, art)
+	}
+	return out
+}
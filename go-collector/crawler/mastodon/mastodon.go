@@ -0,0 +1,287 @@
+// Package mastodon consulta instancias de Mastodon (y demás servidores del
+// Fediverso que implementan su API REST): las timelines públicas de hashtag,
+// que responde cualquier instancia, y la búsqueda de texto completo, que solo
+// responden con token las instancias que la habilitan.
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+)
+
+// MaxLimit es el máximo de estados por página que admite la API.
+const MaxLimit = 40
+
+// ErrSearchUnavailable indica que la instancia no permite la búsqueda de texto
+// completo (sin token, o deshabilitada).
+var ErrSearchUnavailable = errors.New("la instancia no permite buscar texto completo")
+
+// Response es la forma de /api/v2/search; las timelines de hashtag, que
+// devuelven solo la lista, se envuelven igual.
+type Response struct {
+	Statuses []Status `json:"statuses"`
+}
+
+// Status es una publicación.
+type Status struct {
+	ID               string       `json:"id"`
+	CreatedAt        string       `json:"created_at"`
+	URI              string       `json:"uri"` // identificador federado, igual en todas las instancias
+	URL              string       `json:"url"` // página pública en la instancia de origen
+	Content          string       `json:"content"`
+	SpoilerText      string       `json:"spoiler_text"`
+	Language         string       `json:"language"`
+	Account          Account      `json:"account"`
+	RepliesCount     int          `json:"replies_count"`
+	ReblogsCount     int          `json:"reblogs_count"`
+	FavouritesCount  int          `json:"favourites_count"`
+	Tags             []Tag        `json:"tags"`
+	MediaAttachments []Attachment `json:"media_attachments"`
+	Card             *Card        `json:"card"`
+}
+
+// Account es el autor de una publicación; Acct incluye la instancia si no es
+// la consultada (usuario@instancia).
+type Account struct {
+	Acct        string `json:"acct"`
+	DisplayName string `json:"display_name"`
+	URL         string `json:"url"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Attachment es un archivo adjunto; su tipo (image, video, gifv, audio) no
+// es MIME: el real se detecta al descargarlo.
+type Attachment struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Card es la vista previa del enlace que comparte la publicación.
+type Card struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+// APIError es una respuesta de error de la instancia.
+type APIError struct {
+	Instance   string
+	HTTPStatus int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error HTTP: status code %d. Respuesta de %s:\n%s", e.HTTPStatus, e.Instance, e.Body)
+}
+
+type Crawler struct {
+	BaseURL string // la instancia, ej: https://mastodon.social
+	Client  *http.Client
+	// Token es el token de acceso de una cuenta en la instancia; sin él solo
+	// se consultan hashtags.
+	Token string
+}
+
+func NewCrawler(instance, token string) *Crawler {
+	return &Crawler{
+		BaseURL: strings.TrimSuffix(instance, "/"),
+		Client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+		Token: token,
+	}
+}
+
+// Hashtag pide una página de la timeline pública del hashtag (sin '#'), de
+// la más reciente hacia atrás: maxID vacío es la primera y la siguiente
+// empieza antes del ID del último estado recibido.
+func (m *Crawler) Hashtag(ctx context.Context, tag string, limit int, maxID string) (*Response, error) {
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(limit))
+	if maxID != "" {
+		params.Set("max_id", maxID)
+	}
+	var statuses []Status
+	if err := m.get(ctx, "/api/v1/timelines/tag/"+url.PathEscape(tag), params, &statuses); err != nil {
+		return nil, err
+	}
+	return &Response{Statuses: statuses}, nil
+}
+
+// Search pide una página de la búsqueda de texto completo desde offset.
+// Devuelve ErrSearchUnavailable sin token o si la instancia la rechaza.
+func (m *Crawler) Search(ctx context.Context, q string, limit, offset int) (*Response, error) {
+	if m.Token == "" {
+		return nil, ErrSearchUnavailable
+	}
+	params := url.Values{}
+	params.Set("q", q)
+	params.Set("type", "statuses")
+	params.Set("limit", strconv.Itoa(limit))
+	if offset > 0 {
+		params.Set("offset", strconv.Itoa(offset))
+	}
+	var resp Response
+	err := m.get(ctx, "/api/v2/search", params, &resp)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.HTTPStatus == http.StatusUnauthorized || apiErr.HTTPStatus == http.StatusForbidden) {
+		return nil, fmt.Errorf("%w: %v", ErrSearchUnavailable, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (m *Crawler) get(ctx context.Context, path string, params url.Values, v any) error {
+	fullURL := fmt.Sprintf("%s%s?%s", m.BaseURL, path, params.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	req.Header.Set("Accept", "application/json")
+	if m.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+m.Token)
+	}
+
+	resp, err := m.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{Instance: m.BaseURL, HTTPStatus: resp.StatusCode, Body: crawler.Preview(body)}
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error parseando JSON: %w. Respuesta recibida:\n%s", err, crawler.Preview(body))
+	}
+	return nil
+}
+
+// Text es el texto plano de la publicación (el contenido viene en HTML), con
+// la advertencia de contenido delante si la tiene.
+func (s Status) Text() string {
+	text := htmlText(s.Content)
+	if s.SpoilerText != "" {
+		text = strings.TrimSpace(s.SpoilerText + "\n\n" + text)
+	}
+	return text
+}
+
+// htmlText convierte el HTML de una publicación en texto: un párrafo por
+// <p> y los <br> como saltos de línea.
+func htmlText(html string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return strings.TrimSpace(html)
+	}
+	doc.Find("br").ReplaceWithHtml("\n")
+	var parts []string
+	doc.Find("p").Each(func(_ int, p *goquery.Selection) {
+		if t := cleanLines(p.Text()); t != "" {
+			parts = append(parts, t)
+		}
+	})
+	if len(parts) == 0 {
+		return cleanLines(doc.Text())
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// cleanLines colapsa los espacios de cada línea y quita las vacías.
+func cleanLines(s string) string {
+	var lines []string
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.Join(strings.Fields(l), " "); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Link es la URL pública de la publicación: la de su instancia de origen o,
+// si no la tiene, su URI federado.
+func (s Status) Link() string {
+	if s.URL != "" {
+		return s.URL
+	}
+	return s.URI
+}
+
+// Normalize convierte las publicaciones al modelo común del corpus. Como en
+// X, el título es el texto recortado y el texto completo va en Body; el
+// dominio es la instancia de origen, el autor la cuenta completa
+// (usuario@instancia) y los favoritos, impulsos y respuestas van en
+// Engagement.
+func (r *Response) Normalize() []*article.Article {
+	out := make([]*article.Article, 0, len(r.Statuses))
+	for _, s := range r.Statuses {
+		text := s.Text()
+		title := []rune(strings.Join(strings.Fields(text), " "))
+		if len(title) > 120 {
+			title = append(title[:117], []rune("...")...)
+		}
+		link := s.Link()
+		a := &article.Article{
+			Source:   "mastodon",
+			URL:      link,
+			Title:    string(title),
+			Author:   account(s.Account, link),
+			Domain:   crawler.Domain(link),
+			Language: s.Language,
+			Body:     text,
+			Engagement: &article.Engagement{
+				Likes: s.FavouritesCount, Reposts: s.ReblogsCount, Replies: s.RepliesCount,
+			},
+		}
+		if s.Card != nil {
+			// El enlace compartido ya está en el texto; su título, no.
+			a.Summary = s.Card.Title
+		}
+		for _, m := range s.MediaAttachments {
+			if m.URL != "" {
+				a.Media = append(a.Media, article.Media{URL: m.URL})
+			}
+		}
+		a.Published, a.RawPublished = dates.Normalize(s.CreatedAt)
+		out = append(out, a)
+	}
+	return out
+}
+
+// account es la cuenta completa del autor: Acct no incluye la instancia
+// cuando es la misma que se consultó, así que se toma de la URL.
+func account(a Account, link string) string {
+	if a.Acct == "" || strings.Contains(a.Acct, "@") {
+		return a.Acct
+	}
+	if host := crawler.Domain(a.URL); host != "" {
+		return a.Acct + "@" + host
+	}
+	if host := crawler.Domain(link); host != "" {
+		return a.Acct + "@" + host
+	}
+	return a.Acct
+}
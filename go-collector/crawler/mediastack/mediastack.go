@@ -0,0 +1,180 @@
+// Package mediastack consulta la búsqueda de noticias de Mediastack, un
+// agregador de miles de medios. Su búsqueda no admite OR: se hace una por
+// término de la consulta.
+package mediastack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+)
+
+// MaxLimit es el máximo de resultados por página.
+const MaxLimit = 100
+
+// ErrQuota indica que se agotaron las peticiones del plan en el mes
+// (usage_limit_reached) o el cupo por segundo (rate_limit_reached).
+var ErrQuota = errors.New("se agotó la cuota de Mediastack")
+
+// Response es una página de resultados.
+type Response struct {
+	Pagination struct {
+		Limit  int `json:"limit"`
+		Offset int `json:"offset"`
+		Count  int `json:"count"`
+		Total  int `json:"total"`
+	} `json:"pagination"`
+	Data []Article `json:"data"`
+}
+
+// Article es una noticia. Source es el nombre del medio.
+type Article struct {
+	Author      string `json:"author"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Source      string `json:"source"`
+	Image       string `json:"image"`
+	Category    string `json:"category"`
+	Language    string `json:"language"`
+	Country     string `json:"country"`
+	PublishedAt string `json:"published_at"`
+}
+
+// APIError es una respuesta de error de la API.
+type APIError struct {
+	HTTPStatus int
+	Code       string // ej: invalid_access_key, usage_limit_reached
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error de Mediastack (HTTP %d, %s): %s", e.HTTPStatus, e.Code, e.Message)
+}
+
+// Is permite errors.Is(err, ErrQuota).
+func (e *APIError) Is(target error) bool {
+	return target == ErrQuota && (e.Code == "usage_limit_reached" || e.Code == "rate_limit_reached")
+}
+
+type Crawler struct {
+	BaseURL string
+	Client  *http.Client
+	APIKey  string
+}
+
+func NewCrawler(apiKey string) *Crawler {
+	return &Crawler{
+		BaseURL: "https://api.mediastack.com/v1/news",
+		Client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+		APIKey: apiKey,
+	}
+}
+
+// SearchOptions acota la búsqueda. Mediastack filtra por día: el rango
+// exacto se aplica al recibir.
+type SearchOptions struct {
+	Languages []string // ISO 639-1
+	From, To  time.Time
+	Count     int
+	Offset    int
+}
+
+// Search pide una página de noticias que mencionan q (una palabra o una
+// frase), de la más reciente hacia atrás. La siguiente se pide con Offset
+// más los resultados recibidos.
+func (m *Crawler) Search(ctx context.Context, q string, opts SearchOptions) (*Response, error) {
+	params := url.Values{}
+	params.Set("access_key", m.APIKey)
+	params.Set("keywords", q)
+	params.Set("sort", "published_desc")
+	params.Set("limit", strconv.Itoa(opts.Count))
+	if opts.Offset > 0 {
+		params.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	if len(opts.Languages) > 0 {
+		params.Set("languages", strings.Join(opts.Languages, ","))
+	}
+	if !opts.From.IsZero() {
+		to := opts.To
+		if to.IsZero() {
+			to = time.Now()
+		}
+		params.Set("date", opts.From.UTC().Format("2006-01-02")+","+to.UTC().Format("2006-01-02"))
+	}
+	fullURL := fmt.Sprintf("%s?%s", m.BaseURL, params.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := m.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	// Los errores llegan como {"error": {"code": ..., "message": ...}}.
+	var apiErr struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	json.Unmarshal(body, &apiErr)
+	if resp.StatusCode != http.StatusOK || apiErr.Error.Code != "" {
+		e := &APIError{HTTPStatus: resp.StatusCode, Code: apiErr.Error.Code, Message: apiErr.Error.Message}
+		if e.Message == "" {
+			e.Message = crawler.Preview(body)
+		}
+		return nil, e
+	}
+	var out Response
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("error parseando JSON: %w. Respuesta recibida:\n%s", err, crawler.Preview(body))
+	}
+	return &out, nil
+}
+
+// Normalize convierte los resultados al modelo común del corpus; la imagen
+// queda como adjunto.
+func (r *Response) Normalize() []*article.Article {
+	out := make([]*article.Article, 0, len(r.Data))
+	for _, v := range r.Data {
+		a := &article.Article{
+			Source:   "mediastack",
+			URL:      v.URL,
+			Title:    strings.TrimSpace(v.Title),
+			Author:   v.Author,
+			Domain:   crawler.Domain(v.URL),
+			Language: v.Language,
+			Section:  v.Category,
+			Summary:  v.Description,
+		}
+		if v.Image != "" {
+			a.Media = append(a.Media, article.Media{URL: v.Image})
+		}
+		a.Published, a.RawPublished = dates.Normalize(v.PublishedAt)
+		out = append(out, a)
+	}
+	return out
+}
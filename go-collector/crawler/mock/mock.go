@@ -0,0 +1,208 @@
+// Package mock genera artículos sintéticos para pruebas de carga: permite
+// medir la recolección, el almacenamiento y las salidas con cientos de miles
+// de artículos sin tocar las APIs reales. Las distribuciones imitan el corpus
+// real: pocos medios concentran la mayoría de las notas, la publicación sigue
+// el horario del día, el largo del texto es asimétrico y algunas URLs se
+// repiten entre consultas.
+package mock
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+	"unicode"
+
+	"go-collector/article"
+)
+
+// DefaultCount es la cantidad de artículos por corrida si no se indica otra.
+const DefaultCount = 1000
+
+// Proporciones de los casos que el pipeline debe manejar.
+const (
+	duplicateRate = 0.02 // URL ya generada (el medio actualizó la nota)
+	noBodyRate    = 0.15 // solo resumen, como devuelven varias APIs
+	noAuthorRate  = 0.30
+	mentionRate   = 0.60 // menciona la consulta en título o resumen
+)
+
+type outlet struct {
+	domain   string
+	language string
+}
+
+// outlets en orden de volumen: se eligen con una distribución de Zipf.
+var outlets = []outlet{
+	{"www.eltiempo.com", "es"}, {"www.elcolombiano.com", "es"}, {"www.elespectador.com", "es"},
+	{"www.semana.com", "es"}, {"www.udea.edu.co", "es"}, {"www.bbc.com", "es"},
+	{"www.infobae.com", "es"}, {"www.theguardian.com", "en"}, {"www.nytimes.com", "en"},
+	{"www.eluniversal.com.co", "es"}, {"www.portafolio.co", "es"}, {"www.larepublica.co", "es"},
+	{"www.reuters.com", "en"}, {"apnews.com", "en"}, {"www.elpais.com", "es"},
+	{"www.lasillavacia.com", "es"}, {"www.caracol.com.co", "es"}, {"www.rcnradio.com", "es"},
+	{"www.pulzo.com", "es"}, {"www.minuto30.com", "es"}, {"www.teleantioquia.co", "es"},
+	{"www.folha.uol.com.br", "pt"}, {"www.aljazeera.com", "en"}, {"www.france24.com", "es"},
+}
+
+var sections = []string{"educacion", "colombia", "medellin", "ciencia", "politica", "salud", "economia", "cultura", "deportes", "opinion"}
+
+var authors = []string{
+	"María Fernanda Restrepo", "Juan Camilo Gómez", "Ana María Vélez", "Carlos Andrés Ospina",
+	"Laura Cristina Zapata", "Santiago Arango", "Daniela Muñoz", "Andrés Felipe Cardona",
+	"Redacción", "Colprensa", "EFE", "AFP",
+}
+
+var mentions = map[string][]string{
+	"es": {"Universidad de Antioquia", "UdeA", "la Universidad de Antioquia"},
+	"en": {"University of Antioquia", "Universidad de Antioquia"},
+	"pt": {"Universidade de Antioquia", "Universidad de Antioquia"},
+}
+
+var words = map[string][]string{
+	"es": strings.Fields("estudiantes profesores investigación rectoría consejo superior presupuesto " +
+		"matrícula sede Medellín campus ciencia laboratorio vacuna paro asamblea gobierno ministerio " +
+		"educación pública reforma proyecto convocatoria becas región Antioquia salud hospital " +
+		"estudio resultados nuevo anuncio crisis acuerdo comunidad académica programa doctorado"),
+	"en": strings.Fields("students faculty research university board budget campus science " +
+		"laboratory vaccine strike government ministry public education reform project grant " +
+		"region health hospital study results new announcement crisis agreement academic program"),
+	"pt": strings.Fields("estudantes professores pesquisa universidade orçamento campus ciência " +
+		"laboratório vacina greve governo ministério educação pública reforma projeto região saúde"),
+}
+
+// Generator produce artículos sintéticos reproducibles: con la misma semilla
+// genera la misma secuencia.
+type Generator struct {
+	rnd  *rand.Rand
+	zipf *rand.Zipf
+	seq  int
+	urls []string
+}
+
+// NewGenerator crea un generador; seed 0 usa una semilla al azar.
+func NewGenerator(seed uint64) *Generator {
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	r := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	return &Generator{
+		rnd:  r,
+		zipf: rand.NewZipf(r, 1.2, 1, uint64(len(outlets)-1)),
+	}
+}
+
+// Generar devuelve n artículos publicados entre desde y hasta.
+func (g *Generator) Generar(n int, desde, hasta time.Time) []*article.Article {
+	out := make([]*article.Article, 0, n)
+	for i := 0; i < n; i++ {
+		out = append(out, g.articulo(desde, hasta))
+	}
+	return out
+}
+
+func (g *Generator) articulo(desde, hasta time.Time) *article.Article {
+	o := outlets[g.zipf.Uint64()]
+	section := sections[g.rnd.IntN(len(sections))]
+	published := g.fecha(desde, hasta)
+
+	mention := ""
+	if g.rnd.Float64() < mentionRate {
+		list := mentions[o.language]
+		mention = list[g.rnd.IntN(len(list))]
+	}
+	title := g.frase(o.language, 6+g.rnd.IntN(10), mention)
+	summary := g.parrafo(o.language, 1+g.rnd.IntN(3), mention)
+
+	var body string
+	if g.rnd.Float64() >= noBodyRate {
+		// Largo log-normal: la mayoría de notas son cortas, unas pocas muy largas.
+		paragraphs := int(math.Round(math.Exp(g.rnd.NormFloat64()*0.6 + 1.6)))
+		parts := make([]string, max(paragraphs, 1))
+		for i := range parts {
+			parts[i] = g.parrafo(o.language, 3+g.rnd.IntN(4), "")
+		}
+		body = strings.Join(parts, "\n\n")
+	}
+
+	var author string
+	if g.rnd.Float64() >= noAuthorRate {
+		author = authors[g.rnd.IntN(len(authors))]
+	}
+
+	g.seq++
+	url := fmt.Sprintf("https://%s/%s/%s/%s-%d", o.domain, section, published.Format("2006/01/02"), slug(title), g.seq)
+	if len(g.urls) > 0 && g.rnd.Float64() < duplicateRate {
+		url = g.urls[g.rnd.IntN(len(g.urls))]
+	} else if len(g.urls) < 10000 {
+		g.urls = append(g.urls, url)
+	}
+
+	return &article.Article{
+		Source:    "mock",
+		URL:       url,
+		Title:     title,
+		Author:    author,
+		Domain:    o.domain,
+		Language:  o.language,
+		Section:   section,
+		Summary:   summary,
+		Body:      body,
+		Published: published,
+	}
+}
+
+// fecha elige un momento del rango con más publicaciones en horario diurno
+// (7 a 22 h, hora de Colombia) que de madrugada.
+func (g *Generator) fecha(desde, hasta time.Time) time.Time {
+	span := hasta.Sub(desde)
+	if span <= 0 {
+		return hasta.UTC()
+	}
+	for {
+		t := desde.Add(time.Duration(g.rnd.Int64N(int64(span))))
+		hour := t.Add(-5 * time.Hour).Hour()
+		if (hour >= 7 && hour < 22) || g.rnd.Float64() < 0.2 {
+			return t.UTC().Truncate(time.Second)
+		}
+	}
+}
+
+func (g *Generator) frase(language string, n int, mention string) string {
+	list := words[language]
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = list[g.rnd.IntN(len(list))]
+	}
+	if mention != "" {
+		parts[g.rnd.IntN(n)] = mention
+	}
+	r := []rune(strings.Join(parts, " "))
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func (g *Generator) parrafo(language string, sentences int, mention string) string {
+	parts := make([]string, sentences)
+	for i := range parts {
+		m := ""
+		if i == 0 {
+			m = mention
+		}
+		parts[i] = g.frase(language, 8+g.rnd.IntN(14), m) + "."
+	}
+	return strings.Join(parts, " ")
+}
+
+func slug(title string) string {
+	fields := strings.Fields(strings.ToLower(title))
+	if len(fields) > 6 {
+		fields = fields[:6]
+	}
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return -1
+	}, strings.Join(fields, "-"))
+}
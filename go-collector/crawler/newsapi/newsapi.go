@@ -0,0 +1,189 @@
+// Package newsapi consulta el endpoint /v2/everything de NewsAPI.
+package newsapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+)
+
+// Response mapea la respuesta principal de NewsAPI
+type Response struct {
+	Status       string    `json:"status"`
+	TotalResults int       `json:"totalResults"`
+	Articles     []Article `json:"articles"`
+
+	// Code y Message vienen solo cuando Status es "error".
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Article mapea los campos relevantes de cada artículo
+type Article struct {
+	Source struct {
+		Name string `json:"name"`
+	} `json:"source"`
+	Author      string `json:"author"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	PublishedAt string `json:"publishedAt"`
+	Content     string `json:"content"`
+}
+
+// Crawler encapsula la lógica de conexión
+type Crawler struct {
+	BaseURL string
+	Client  *http.Client
+	APIKey  string
+}
+
+func NewCrawler(apiKey string) *Crawler {
+	return &Crawler{
+		BaseURL: "https://newsapi.org/v2/everything",
+		Client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+		APIKey: apiKey,
+	}
+}
+
+// BuscarArticulos realiza una búsqueda en NewsAPI.
+// NewsAPI no usa "sourceLang", sino el parámetro "language" con códigos ISO 639-1 de dos letras.
+// Los idiomas se pasan como una cadena de dos letras separadas por comas (ej: "es,en").
+func (n *Crawler) BuscarArticulos(ctx context.Context, queryRaw, idiomasCSV, fechaInicio, fechaFin string, pageSize int) (*Response, error) {
+	return n.BuscarPagina(ctx, queryRaw, idiomasCSV, fechaInicio, fechaFin, pageSize, 1)
+}
+
+// BuscarPagina es BuscarArticulos para una página concreta (desde 1). Para
+// recorrer todos los resultados use Paginar.
+func (n *Crawler) BuscarPagina(ctx context.Context, queryRaw, idiomasCSV, fechaInicio, fechaFin string, pageSize, page int) (*Response, error) {
+
+	// 1. La Query: NewsAPI soporta operadores AND/OR y frases entre comillas,
+	// así que se envía tal cual, sin la sintaxis especial de GDELT.
+	finalQuery := queryRaw
+
+	// 2. Construir URL con parámetros
+	params := url.Values{}
+	params.Add("q", finalQuery)
+	params.Add("language", idiomasCSV) // "es,en"
+	params.Add("sortBy", "publishedAt")
+	params.Add("pageSize", fmt.Sprintf("%d", pageSize))
+	if page > 1 {
+		params.Add("page", fmt.Sprintf("%d", page))
+	}
+
+	// Fechas deben estar en formato ISO 8601 (YYYY-MM-DDTHH:MM:SSZ)
+	params.Add("from", fechaInicio)
+	params.Add("to", fechaFin)
+
+	fullURL := fmt.Sprintf("%s?%s", n.BaseURL, params.Encode())
+
+	if page > 1 {
+		fmt.Printf("Consultando NewsAPI (página %d)...\n", page)
+	} else {
+		fmt.Printf("Consultando NewsAPI...\nQuery: %s\nIdiomas: %s\nRango: %s a %s\n",
+			finalQuery, idiomasCSV, fechaInicio, fechaFin)
+	}
+
+	// 3. Crear request con API Key en el Header (es la forma preferida)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Agregar la API Key y User-Agent
+	req.Header.Set("X-Api-Key", n.APIKey)
+	req.Header.Set("User-Agent", "EthicalCrawlerNews/1.0")
+
+	// 4. Realizar petición
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 5. Leer respuesta
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+
+	// 6. Parsear JSON
+	var apiResp Response
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("error parseando JSON: %w. \nRespuesta recibida (Inicio):\n%s", err, crawler.Preview(body))
+	}
+
+	// NewsAPI devuelve el status en el cuerpo, no solo en el HTTP status code
+	if apiResp.Status != "ok" {
+		// En caso de error de API (ej: API Key inválida, límite del plan)
+		return nil, &APIError{HTTPStatus: resp.StatusCode, Code: apiResp.Code, Message: apiResp.Message}
+	}
+
+	return &apiResp, nil
+}
+
+// ExplorarDatos muestra estadísticas básicas
+func (n *Crawler) ExplorarDatos(response *Response) {
+	if response == nil || len(response.Articles) == 0 {
+		fmt.Println("\n--- EXPLORACIÓN DE DATOS ---")
+		fmt.Println("No se encontraron artículos que coincidan con la búsqueda.")
+		return
+	}
+
+	fmt.Println("\n--- EXPLORACIÓN DE DATOS - NEWSAPI ---")
+	fmt.Printf("Total de artículos encontrados: %d\n\n", response.TotalResults)
+	fmt.Printf("Artículos recuperados (página): %d\n\n", len(response.Articles))
+
+	// Contador de fuentes (Sources)
+	fuentes := make(map[string]int)
+
+	for _, art := range response.Articles {
+		fuentes[art.Source.Name]++
+	}
+
+	// Mostrar top 10 fuentes
+	fmt.Println("Top 10 Fuentes:")
+	topFuentes := crawler.TopN(fuentes, 10)
+	for i, item := range topFuentes {
+		fmt.Printf("  %2d. %-30s (%d artículos)\n", i+1, item.Key, item.Value)
+	}
+
+	// Mostrar primeros 5 artículos
+	fmt.Println("\nPrimeros 5 Artículos de Muestra:")
+	for i, art := range response.Articles {
+		if i >= 5 {
+			break
+		}
+		fmt.Printf("\n  %d. Título: %s\n", i+1, art.Title)
+		fmt.Printf("      Fuente: %s | Autor: %s\n", art.Source.Name, art.Author)
+		fmt.Printf("      Publicado: %s\n", art.PublishedAt)
+		fmt.Printf("      URL: %s\n", art.URL)
+	}
+}
+
+// Normalize convierte los resultados al modelo común del corpus. NewsAPI no
+// informa el idioma de cada artículo; se deja vacío.
+func (r *Response) Normalize() []*article.Article {
+	out := make([]*article.Article, 0, len(r.Articles))
+	for _, a := range r.Articles {
+		art := &article.Article{
+			Source:  "newsapi",
+			URL:     a.URL,
+			Title:   a.Title,
+			Author:  a.Author,
+			Domain:  crawler.Domain(a.URL),
+			Summary: a.Content,
+		}
+		art.Published, art.RawPublished = dates.Normalize(a.PublishedAt)
+		out = append(out, art)
+	}
+	return out
+}
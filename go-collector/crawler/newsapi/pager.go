@@ -0,0 +1,122 @@
+package newsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// FreeTierMaxResults es el tope de resultados por búsqueda del plan gratuito
+// (developer): pedir más allá de la página que lo alcanza devuelve
+// maximumResultsReached.
+const FreeTierMaxResults = 100
+
+// Errores de plan o cuota de NewsAPI; se comparan con errors.Is sobre un *APIError.
+var (
+	// ErrPlanLimit: se alcanzó el máximo de resultados del plan para la búsqueda.
+	ErrPlanLimit = errors.New("límite de resultados del plan de NewsAPI")
+	// ErrRateLimited: se agotaron las peticiones del período (100 diarias en el plan gratuito).
+	ErrRateLimited = errors.New("cuota de peticiones de NewsAPI agotada")
+)
+
+// APIError es un error devuelto por NewsAPI en el cuerpo de la respuesta
+// (status "error" con code y message).
+type APIError struct {
+	HTTPStatus int
+	Code       string // ej: maximumResultsReached, rateLimited, apiKeyInvalid
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error de NewsAPI (HTTP %d, %s): %s", e.HTTPStatus, e.Code, e.Message)
+}
+
+// Is permite errors.Is(err, ErrPlanLimit) y errors.Is(err, ErrRateLimited).
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrPlanLimit:
+		return e.Code == "maximumResultsReached"
+	case ErrRateLimited:
+		return e.Code == "rateLimited"
+	}
+	return false
+}
+
+// Pager recorre las páginas de una búsqueda sin pasarse del tope de
+// resultados: no pide una página que el plan rechazaría.
+//
+//	p := c.Paginar(q, "es,en", desde, hasta, 100)
+//	for !p.Done() {
+//		resp, err := p.NextPage(ctx)
+//		...
+//	}
+type Pager struct {
+	crawler                  *Crawler
+	query, idiomas, from, to string
+	pageSize                 int
+
+	// MaxResults es el total máximo a recorrer (por defecto FreeTierMaxResults).
+	MaxResults int
+
+	page    int
+	fetched int
+	total   int
+	done    bool
+}
+
+// Paginar prepara el recorrido de una búsqueda; la primera petición se hace
+// en el primer NextPage.
+func (n *Crawler) Paginar(queryRaw, idiomasCSV, fechaInicio, fechaFin string, pageSize int) *Pager {
+	return &Pager{
+		crawler: n, query: queryRaw, idiomas: idiomasCSV, from: fechaInicio, to: fechaFin,
+		pageSize: pageSize, MaxResults: FreeTierMaxResults, total: -1,
+	}
+}
+
+// Done indica que no quedan páginas (o que la última petición falló).
+func (p *Pager) Done() bool {
+	return p.done
+}
+
+// Total es el totalResults informado por NewsAPI (-1 antes de la primera página).
+func (p *Pager) Total() int {
+	return p.total
+}
+
+// Fetched es la cantidad de artículos recibidos hasta ahora.
+func (p *Pager) Fetched() int {
+	return p.fetched
+}
+
+// NextPage pide la página siguiente. Después del último resultado disponible
+// (o del tope MaxResults) Done pasa a true. Un error termina el recorrido; si
+// es por el plan, errors.Is(err, ErrPlanLimit) o ErrRateLimited.
+func (p *Pager) NextPage(ctx context.Context) (*Response, error) {
+	if p.done {
+		return nil, fmt.Errorf("paginación de NewsAPI terminada")
+	}
+	size := p.pageSize
+	if p.MaxResults > 0 && size > p.MaxResults {
+		size = p.MaxResults
+	}
+	p.page++
+	resp, err := p.crawler.BuscarPagina(ctx, p.query, p.idiomas, p.from, p.to, size, p.page)
+	if err != nil {
+		p.done = true
+		return nil, err
+	}
+	p.total = resp.TotalResults
+	if p.MaxResults > 0 && p.fetched+len(resp.Articles) > p.MaxResults {
+		resp.Articles = resp.Articles[:p.MaxResults-p.fetched]
+	}
+	p.fetched += len(resp.Articles)
+
+	limit := p.total
+	if p.MaxResults > 0 && p.MaxResults < limit {
+		limit = p.MaxResults
+	}
+	if len(resp.Articles) == 0 || p.fetched >= limit {
+		p.done = true
+	}
+	return resp, nil
+}
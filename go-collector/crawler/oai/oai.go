@@ -0,0 +1,298 @@
+// Package oai cosecha repositorios institucionales y archivos por OAI-PMH
+// (Open Archives Initiative Protocol for Metadata Harvesting): pide los
+// registros con ListRecords en Dublin Core simple (oai_dc), recorre las
+// páginas con el resumptionToken y convierte cada registro en un artículo.
+package oai
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+)
+
+// Prefix es el formato de metadatos que se pide: Dublin Core simple, el que
+// todo repositorio OAI-PMH está obligado a ofrecer.
+const Prefix = "oai_dc"
+
+// Response es la respuesta de ListRecords. Error trae el código de error
+// del protocolo (ej: noRecordsMatch, badResumptionToken).
+type Response struct {
+	ResponseDate string `xml:"responseDate"`
+	Error        *Error `xml:"error"`
+	ListRecords  struct {
+		Records         []Record        `xml:"record"`
+		ResumptionToken ResumptionToken `xml:"resumptionToken"`
+	} `xml:"ListRecords"`
+}
+
+// Error es un error del protocolo.
+type Error struct {
+	Code    string `xml:"code,attr"`
+	Message string `xml:",chardata"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("error de OAI-PMH (%s): %s", e.Code, strings.TrimSpace(e.Message))
+}
+
+// ResumptionToken continúa una lista incompleta; vacío en la última página.
+// CompleteListSize y Cursor son opcionales en el protocolo.
+type ResumptionToken struct {
+	Token            string `xml:",chardata"`
+	CompleteListSize int    `xml:"completeListSize,attr"`
+	Cursor           int    `xml:"cursor,attr"`
+}
+
+// Record es un registro: el encabezado (Status "deleted" si se retiró del
+// repositorio, y entonces no trae metadatos) y su Dublin Core.
+type Record struct {
+	Header struct {
+		Status     string   `xml:"status,attr"`
+		Identifier string   `xml:"identifier"`
+		Datestamp  string   `xml:"datestamp"`
+		SetSpec    []string `xml:"setSpec"`
+	} `xml:"header"`
+	DC DublinCore `xml:"metadata>dc"`
+}
+
+// DublinCore son los quince elementos de Dublin Core simple; todos pueden
+// repetirse.
+type DublinCore struct {
+	Title       []string `xml:"title"`
+	Creator     []string `xml:"creator"`
+	Subject     []string `xml:"subject"`
+	Description []string `xml:"description"`
+	Publisher   []string `xml:"publisher"`
+	Contributor []string `xml:"contributor"`
+	Date        []string `xml:"date"`
+	Type        []string `xml:"type"`
+	Format      []string `xml:"format"`
+	Identifier  []string `xml:"identifier"`
+	Source      []string `xml:"source"`
+	Language    []string `xml:"language"`
+	Relation    []string `xml:"relation"`
+	Coverage    []string `xml:"coverage"`
+	Rights      []string `xml:"rights"`
+}
+
+type Crawler struct {
+	// BaseURL es la dirección OAI-PMH del repositorio, ej:
+	// https://bibliotecadigital.udea.edu.co/oai/request.
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewCrawler(baseURL string) *Crawler {
+	return &Crawler{
+		BaseURL: baseURL,
+		Client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}
+}
+
+// ListOptions acota la cosecha. From y Until se comparan con la fecha de
+// modificación del registro (datestamp), no con la de publicación; se envían
+// con granularidad de día, la que todo repositorio admite.
+type ListOptions struct {
+	Set         string
+	From, Until time.Time
+}
+
+// ListRecords pide la primera página de registros.
+func (o *Crawler) ListRecords(ctx context.Context, opts ListOptions) (*Response, error) {
+	params := url.Values{}
+	params.Set("verb", "ListRecords")
+	params.Set("metadataPrefix", Prefix)
+	if opts.Set != "" {
+		params.Set("set", opts.Set)
+	}
+	if !opts.From.IsZero() {
+		params.Set("from", opts.From.UTC().Format("2006-01-02"))
+	}
+	if !opts.Until.IsZero() {
+		params.Set("until", opts.Until.UTC().Format("2006-01-02"))
+	}
+	return o.get(ctx, params)
+}
+
+// Resume pide la página siguiente a la de token. El protocolo no admite
+// otros argumentos junto al token.
+func (o *Crawler) Resume(ctx context.Context, token string) (*Response, error) {
+	params := url.Values{}
+	params.Set("verb", "ListRecords")
+	params.Set("resumptionToken", token)
+	return o.get(ctx, params)
+}
+
+func (o *Crawler) get(ctx context.Context, params url.Values) (*Response, error) {
+	reqURL := o.BaseURL + "?" + params.Encode()
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	req.Header.Set("Accept", "text/xml, application/xml")
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error del repositorio %s (HTTP %d): %s", o.BaseURL, resp.StatusCode, crawler.Preview(body))
+	}
+	var out Response
+	if err := xml.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("error parseando XML: %w. Respuesta recibida:\n%s", err, crawler.Preview(body))
+	}
+	// noRecordsMatch no es una falla: el rango o el set no tienen registros.
+	if out.Error != nil && out.Error.Code != "noRecordsMatch" {
+		return nil, out.Error
+	}
+	return &out, nil
+}
+
+// Normalize convierte los registros al modelo común del corpus. Los retirados
+// (deleted) y los que no tienen una dirección web (un identificador http o un
+// DOI) se descartan.
+func (r *Response) Normalize() []*article.Article {
+	out := make([]*article.Article, 0, len(r.ListRecords.Records))
+	for _, rec := range r.ListRecords.Records {
+		if rec.Header.Status == "deleted" {
+			continue
+		}
+		dc := rec.DC
+		link := webURL(dc.Identifier)
+		if link == "" {
+			continue
+		}
+		a := &article.Article{
+			Source:   "oai",
+			URL:      link,
+			Title:    first(dc.Title),
+			Author:   strings.Join(trimmed(dc.Creator), "; "),
+			Domain:   crawler.Domain(link),
+			Language: Language(first(dc.Language)),
+			Section:  strings.Join(trimmed(dc.Type), ", "),
+			Summary:  strings.Join(trimmed(dc.Description), "\n\n"),
+		}
+		a.Published, a.RawPublished = issued(dc.Date)
+		if a.Published.IsZero() && a.RawPublished == "" {
+			a.Published, a.RawPublished = dates.Normalize(rec.Header.Datestamp)
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// doiRe reconoce un DOI sin prefijo de URL (10.xxxx/...).
+var doiRe = regexp.MustCompile(`^(?i:doi:\s*)?(10\.\d{4,9}/\S+)$`)
+
+// webURL elige la dirección del registro entre sus identificadores: el
+// primero http(s) (suele ser el handle del repositorio) o, si no hay, la del
+// DOI.
+func webURL(identifiers []string) string {
+	for _, id := range identifiers {
+		id = strings.TrimSpace(id)
+		if strings.HasPrefix(id, "http://") || strings.HasPrefix(id, "https://") {
+			return id
+		}
+	}
+	for _, id := range identifiers {
+		if m := doiRe.FindStringSubmatch(strings.TrimSpace(id)); m != nil {
+			return "https://doi.org/" + m[1]
+		}
+	}
+	return ""
+}
+
+// partialDate reconoce las fechas incompletas de Dublin Core (año, o año y
+// mes), que dates no interpreta.
+var partialDate = regexp.MustCompile(`^\d{4}(-\d{2})?$`)
+
+// issued elige la fecha de publicación entre las de dc:date. Los repositorios
+// (DSpace, por ejemplo) suelen incluir también las fechas de ingreso y de
+// disponibilidad, con hora; la de publicación es la que no la tiene, a menudo
+// solo el año, que se toma como el 1 de enero.
+func issued(values []string) (time.Time, string) {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if partialDate.MatchString(v) {
+			layout := "2006-01"
+			if len(v) == 4 {
+				layout = "2006"
+			}
+			t, err := time.Parse(layout, v)
+			if err == nil {
+				return t, ""
+			}
+		}
+		if len(v) == len("2006-01-02") {
+			if t, err := time.Parse("2006-01-02", v); err == nil {
+				return t, ""
+			}
+		}
+	}
+	return dates.Normalize(values...)
+}
+
+// languages traduce los códigos de tres letras (ISO 639-2, bibliográficos y
+// terminológicos) de los idiomas más comunes en los repositorios.
+var languages = map[string]string{
+	"spa": "es", "eng": "en", "por": "pt", "fra": "fr", "fre": "fr",
+	"deu": "de", "ger": "de", "ita": "it", "cat": "ca",
+}
+
+// Language normaliza el idioma de un registro a ISO 639-1: admite "es",
+// "es_ES", "es-CO", "spa"; lo que no reconoce queda vacío y lo completa el
+// detector de idioma.
+func Language(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(s, "_-"); i > 0 {
+		s = s[:i]
+	}
+	if l, ok := languages[s]; ok {
+		return l
+	}
+	if len(s) == 2 {
+		return s
+	}
+	return ""
+}
+
+// first devuelve el primer valor no vacío.
+func first(values []string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// trimmed devuelve los valores no vacíos, sin espacios alrededor.
+func trimmed(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
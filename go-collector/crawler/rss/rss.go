@@ -0,0 +1,166 @@
+// Package rss lee feeds RSS/Atom de los medios con gofeed.
+package rss
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+	"go-collector/storage"
+)
+
+// Crawler descarga y parsea feeds.
+type Crawler struct {
+	Client *http.Client
+	Parser *gofeed.Parser
+}
+
+func NewCrawler() *Crawler {
+	client := &http.Client{
+		Timeout: 20 * time.Second,
+	}
+	parser := gofeed.NewParser()
+	parser.Client = client
+	parser.UserAgent = crawler.UserAgent
+	return &Crawler{Client: client, Parser: parser}
+}
+
+// LeerFeed descarga y parsea un feed.
+func (r *Crawler) LeerFeed(ctx context.Context, feedURL string) (*gofeed.Feed, error) {
+	fmt.Printf("Consultando feed %s...\n", feedURL)
+	feed, err := r.Parser.ParseURLWithContext(feedURL, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo feed %s: %w", feedURL, err)
+	}
+	return feed, nil
+}
+
+// Poll lee un feed con una petición condicional: con el ETag y Last-Modified
+// de prev (nil la primera vez) el medio puede responder 304 sin mandar el
+// feed, y entonces feed es nil. state son los validadores nuevos, para la
+// próxima consulta; su Newest lo completa quien procesa las entradas.
+func (r *Crawler) Poll(ctx context.Context, feedURL string, prev *storage.FeedState) (feed *gofeed.Feed, state *storage.FeedState, err error) {
+	fmt.Printf("Consultando feed %s...\n", feedURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error leyendo feed %s: %w", feedURL, err)
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	state = &storage.FeedState{URL: feedURL, Polled: time.Now()}
+	if prev != nil {
+		if prev.ETag != "" {
+			req.Header.Set("If-None-Match", prev.ETag)
+		}
+		if prev.LastModified != "" {
+			req.Header.Set("If-Modified-Since", prev.LastModified)
+		}
+		state.ETag, state.LastModified, state.Newest = prev.ETag, prev.LastModified, prev.Newest
+	}
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error leyendo feed %s: %w", feedURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, state, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, fmt.Errorf("error leyendo feed %s: %w", feedURL, gofeed.HTTPError{StatusCode: resp.StatusCode, Status: resp.Status})
+	}
+	if feed, err = r.Parser.Parse(resp.Body); err != nil {
+		return nil, nil, fmt.Errorf("error leyendo feed %s: %w", feedURL, err)
+	}
+	state.ETag, state.LastModified = resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	return feed, state, nil
+}
+
+// ExplorarDatos muestra estadísticas básicas de los feeds leídos.
+func ExplorarDatos(feeds []*gofeed.Feed) {
+	total := 0
+	categorias := make(map[string]int)
+	for _, f := range feeds {
+		total += len(f.Items)
+		for _, item := range f.Items {
+			for _, c := range item.Categories {
+				categorias[c]++
+			}
+		}
+	}
+	if total == 0 {
+		fmt.Println("\n--- EXPLORACIÓN DE DATOS RSS ---")
+		fmt.Println("Los feeds no tienen entradas.")
+		return
+	}
+
+	fmt.Println("\n--- EXPLORACIÓN DE DATOS - RSS ---")
+	fmt.Printf("Feeds leídos: %d | Entradas: %d\n\n", len(feeds), total)
+
+	if len(categorias) > 0 {
+		fmt.Println("Top 10 Categorías:")
+		for i, item := range crawler.TopN(categorias, 10) {
+			fmt.Printf("  %2d. %-30s (%d entradas)\n", i+1, item.Key, item.Value)
+		}
+	}
+
+	fmt.Println("\nPrimeras 5 Entradas de Muestra:")
+	shown := 0
+	for _, f := range feeds {
+		for _, item := range f.Items {
+			if shown >= 5 {
+				return
+			}
+			shown++
+			fmt.Printf("\n  %d. Título: %s\n", shown, item.Title)
+			fmt.Printf("      Feed: %s\n", f.Title)
+			if item.PublishedParsed != nil {
+				fmt.Printf("      Publicado: %s\n", item.PublishedParsed.Format("2006-01-02 15:04"))
+			}
+			fmt.Printf("      URL: %s\n", item.Link)
+		}
+	}
+}
+
+// Normalize convierte las entradas de un feed al modelo común del corpus.
+func Normalize(feed *gofeed.Feed) []*article.Article {
+	out := make([]*article.Article, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		a := &article.Article{
+			Source:   "rss",
+			URL:      item.Link,
+			Title:    item.Title,
+			Domain:   crawler.Domain(item.Link),
+			Language: strings.ToLower(strings.SplitN(feed.Language, "-", 2)[0]),
+			Summary:  item.Description,
+		}
+		if item.Author != nil {
+			a.Author = item.Author.Name
+		}
+		if len(item.Categories) > 0 {
+			a.Section = item.Categories[0]
+		}
+		// gofeed ya interpreta los formatos habituales; los que no reconoce
+		// se intentan con los de las demás fuentes.
+		switch {
+		case item.PublishedParsed != nil:
+			a.Published = item.PublishedParsed.UTC()
+		case item.UpdatedParsed != nil:
+			a.Published = item.UpdatedParsed.UTC()
+		default:
+			a.Published, a.RawPublished = dates.Normalize(item.Published, item.Updated)
+		}
+		for _, enc := range item.Enclosures {
+			if enc.URL != "" {
+				a.Media = append(a.Media, article.Media{URL: enc.URL, Type: enc.Type})
+			}
+		}
+		out = append(out, a)
+	}
+	return out
+}
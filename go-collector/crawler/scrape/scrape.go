@@ -0,0 +1,277 @@
+// Package scrape recorre sitios sin API ni sitemap descritos con selectores
+// CSS en la configuración: desde unas páginas de inicio sigue los enlaces
+// hasta una profundidad, reconoce las notas por su URL y extrae de cada una
+// el título, el cuerpo, la fecha y el autor.
+package scrape
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/editions"
+	"go-collector/extract"
+	"go-collector/fetch"
+	"go-collector/robots"
+)
+
+// Site es un sitio a recorrer. Links reconoce las URLs de las notas y
+// Follow las de las páginas que solo se recorren para encontrarlas
+// (secciones, paginación); nil no sigue ninguna más allá de Start.
+type Site struct {
+	Name   string
+	Start  []string
+	Links  *regexp.Regexp
+	Follow *regexp.Regexp
+	// Title es el selector del título; Rule, los del cuerpo, la fecha y el
+	// autor. Vacíos usan la extracción genérica.
+	Title string
+	Rule  extract.Rule
+	// Depth es cuántos enlaces se siguen desde Start (1: solo las notas
+	// enlazadas en ellas).
+	Depth int
+	// AnyHost sigue también los enlaces a otros hosts; si no, solo a los de
+	// las páginas de Start.
+	AnyHost bool
+	// Delay es la pausa entre dos páginas del sitio.
+	Delay time.Duration
+	// MaxPages es cuántas páginas se descargan como máximo en un recorrido.
+	MaxPages int
+}
+
+type Crawler struct {
+	// Fetcher descarga las páginas; si un medio responde con un muro de
+	// consentimiento, reintenta con sus ConsentCookies.
+	Fetcher *fetch.Fetcher
+	// Renderer, si no es nil, vuelve a descargar con el navegador las notas
+	// cuyo texto no alcanza (ver fetch.Escalate); los enlaces se siguen
+	// siempre desde la página estática.
+	Renderer fetch.Renderer
+	// RenderPaths, si no es nil, recuerda por dominio si hace falta el
+	// navegador (ver fetch.Escalate).
+	RenderPaths fetch.RenderPaths
+	// Robots, si no es nil, omite las páginas que el robots.txt del sitio
+	// excluye.
+	Robots *robots.Cache
+	// Visited, si no es nil, son las notas ya descargadas en la ronda (por
+	// este u otro recorrido): no se vuelven a pedir.
+	Visited *crawler.Visited
+}
+
+func NewCrawler() *Crawler {
+	f := fetch.NewFetcher()
+	f.Client.Timeout = 30 * time.Second
+	return &Crawler{Fetcher: f}
+}
+
+// Crawl recorre el sitio en anchura desde Start y entrega cada nota a found
+// a medida que la encuentra; si found devuelve false el recorrido termina.
+// Una página que no se puede leer se informa a failed y se omite; el error
+// es el de la cancelación, o el de la primera página si ninguna respondió.
+// Las páginas que excluye el robots.txt (con Robots) y las notas ya
+// visitadas (con Visited) se omiten sin contar para MaxPages.
+func (c *Crawler) Crawl(ctx context.Context, site Site, found func(*article.Article) bool, failed func(pageURL string, err error)) error {
+	type page struct {
+		url   string
+		depth int
+	}
+	hosts := make(map[string]bool)
+	var queue []page
+	seen := make(map[string]bool)
+	for _, u := range site.Start {
+		hosts[crawler.Domain(u)] = true
+		queue = append(queue, page{u, 0})
+		seen[u] = true
+	}
+	e := extract.Extractor{}
+	if site.Rule != (extract.Rule{}) {
+		e.Rules = make(map[string]extract.Rule)
+		for h := range hosts {
+			e.Rules[h] = site.Rule
+		}
+	}
+
+	var firstErr error
+	responded := false
+	fetched := 0
+	for len(queue) > 0 && (site.MaxPages <= 0 || fetched < site.MaxPages) {
+		p := queue[0]
+		queue = queue[1:]
+		if c.Robots != nil {
+			ok, err := c.Robots.Allowed(ctx, p.url)
+			if err != nil && ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !ok {
+				continue
+			}
+		}
+		if c.Visited != nil && site.Links.MatchString(p.url) && !c.Visited.Add(p.url) {
+			continue
+		}
+		if fetched > 0 && site.Delay > 0 {
+			if err := crawler.Sleep(ctx, site.Delay); err != nil {
+				return err
+			}
+		}
+		fetched++
+		body, final, err := c.get(ctx, p.url)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed(p.url, err)
+			continue
+		}
+		responded = true
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+		if err != nil {
+			failed(p.url, fmt.Errorf("error parseando HTML: %w", err))
+			continue
+		}
+		if site.Links.MatchString(p.url) {
+			if !c.article(ctx, site, e, final, body, doc, found, failed) {
+				return nil
+			}
+		}
+		if p.depth >= site.Depth {
+			continue
+		}
+		doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+			u := resolve(final, s.AttrOr("href", ""))
+			if u == "" || seen[u] || (!site.AnyHost && !hosts[crawler.Domain(u)]) {
+				return
+			}
+			if site.Links.MatchString(u) || (site.Follow != nil && site.Follow.MatchString(u)) {
+				seen[u] = true
+				queue = append(queue, page{u, p.depth + 1})
+			}
+		})
+	}
+	if !responded {
+		return firstErr
+	}
+	return nil
+}
+
+// article extrae la nota de la página y la entrega a found; devuelve lo que
+// devuelva found (true si la página no se pudo extraer).
+func (c *Crawler) article(ctx context.Context, site Site, e extract.Extractor, pageURL string, body []byte, doc *goquery.Document,
+	found func(*article.Article) bool, failed func(pageURL string, err error)) bool {
+	res, err := e.Extract(pageURL, body)
+	if err != nil {
+		failed(pageURL, err)
+		return true
+	}
+	static := res
+	rendered, res, err := fetch.Escalate(ctx, c.Renderer, c.RenderPaths, e, pageURL, body, res)
+	if err != nil && ctx.Err() == nil {
+		log.Printf("render %s: %v", pageURL, err)
+	}
+	if res != static {
+		// El título y los metadatos también salen de la página renderizada.
+		if rdoc, err := goquery.NewDocumentFromReader(bytes.NewReader(rendered)); err == nil {
+			doc = rdoc
+		}
+	}
+	return found(Normalize(site, pageURL, doc, res))
+}
+
+// Normalize convierte la nota al modelo común del corpus con lo extraído
+// (res) y el título del selector del sitio o, sin él, de los metadatos.
+func Normalize(site Site, pageURL string, doc *goquery.Document, res *extract.Result) *article.Article {
+	a := &article.Article{
+		Source:    "scrape",
+		URL:       pageURL,
+		Domain:    crawler.Domain(pageURL),
+		Author:    res.Author,
+		Body:      res.Text,
+		Published: res.Published,
+		Summary:   strings.TrimSpace(doc.Find(`meta[property="og:description"], meta[name="description"]`).First().AttrOr("content", "")),
+		Language:  language(doc.Find("html").AttrOr("lang", "")),
+	}
+	links := editions.DocumentLinks(pageURL, doc)
+	a.Canonical, a.Alternates = links.Canonical, links.Alternates
+	if site.Title != "" {
+		a.Title = strings.Join(strings.Fields(doc.Find(site.Title).First().Text()), " ")
+	}
+	if a.Title == "" {
+		a.Title = strings.TrimSpace(doc.Find(`meta[property="og:title"]`).First().AttrOr("content", ""))
+	}
+	if a.Title == "" {
+		a.Title = strings.TrimSpace(doc.Find("title").First().Text())
+	}
+	if res.Strategy != "" {
+		a.Extraction = res
+	}
+	switch {
+	case res.Text == "":
+		a.ExtractionIssue = article.IssueEmptyBody
+	case res.Strategy == extract.StrategyDescription:
+		a.ExtractionIssue = article.IssueDescriptionOnly
+	}
+	return a
+}
+
+// ErrConsentWall indica que el medio respondió con un muro de consentimiento
+// de cookies aun después de enviar las cookies configuradas: no hay nota ni
+// enlaces que seguir.
+var ErrConsentWall = errors.New("muro de consentimiento de cookies")
+
+// get descarga una página HTML; final es su URL tras las redirecciones.
+func (c *Crawler) get(ctx context.Context, pageURL string) (body []byte, final string, err error) {
+	page, err := c.Fetcher.Fetch(ctx, pageURL)
+	if err != nil {
+		return nil, "", err
+	}
+	if page.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("error HTTP: status code %d en %s", page.StatusCode, pageURL)
+	}
+	if ct := page.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return nil, "", fmt.Errorf("no es una página HTML (%s): %s", ct, pageURL)
+	}
+	if page.ConsentWall {
+		return nil, "", fmt.Errorf("%w en %s", ErrConsentWall, pageURL)
+	}
+	return page.Body, page.URL, nil
+}
+
+// resolve devuelve el enlace como URL absoluta http(s) sin fragmento, o "".
+func resolve(base, href string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+	u, err := b.Parse(strings.TrimSpace(href))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	u.Fragment = ""
+	return u.String()
+}
+
+// language reduce un idioma a ISO 639-1 ("es-CO" -> es).
+func language(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(s, "_-"); i > 0 {
+		s = s[:i]
+	}
+	if len(s) != 2 {
+		return ""
+	}
+	return s
+}
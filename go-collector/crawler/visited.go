@@ -0,0 +1,68 @@
+package crawler
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"go-collector/cachestat"
+)
+
+// visitedShards es en cuántas partes con su propio candado se reparte el
+// conjunto, para que las fuentes en paralelo no se esperen entre sí.
+const visitedShards = 16
+
+// Visited es el conjunto de URLs ya descargadas en una ronda, que comparten
+// las fuentes que recorren páginas de los medios para no pedir dos veces la
+// misma nota. Es seguro desde varias goroutines.
+type Visited struct {
+	shards [visitedShards]visitedShard
+	stats  cachestat.Counter
+}
+
+type visitedShard struct {
+	mu   sync.Mutex
+	urls map[string]struct{}
+}
+
+// NewVisited crea un conjunto vacío. Cuenta cada URL repetida como acierto
+// y cada nueva como fallo.
+func NewVisited() *Visited {
+	return &Visited{}
+}
+
+// Add marca u como visitada y devuelve true si no lo estaba: solo quien la
+// agrega primero debe descargarla.
+func (v *Visited) Add(u string) bool {
+	h := fnv.New32a()
+	h.Write([]byte(u))
+	s := &v.shards[h.Sum32()%visitedShards]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.urls[u]; ok {
+		v.stats.Hit()
+		return false
+	}
+	if s.urls == nil {
+		s.urls = make(map[string]struct{})
+	}
+	s.urls[u] = struct{}{}
+	v.stats.Miss()
+	return true
+}
+
+// Len es cuántas URLs tiene el conjunto.
+func (v *Visited) Len() int {
+	n := 0
+	for i := range v.shards {
+		s := &v.shards[i]
+		s.mu.Lock()
+		n += len(s.urls)
+		s.mu.Unlock()
+	}
+	return n
+}
+
+// Stats devuelve los contadores con las URLs del conjunto como entradas.
+func (v *Visited) Stats() cachestat.Stats {
+	return v.stats.Snapshot(v.Len())
+}
@@ -0,0 +1,37 @@
+package crawler
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Muchas goroutines agregan las mismas URLs: cada una se agrega una sola
+// vez y las repeticiones cuentan como aciertos.
+func TestVisitedConcurrent(t *testing.T) {
+	v := NewVisited()
+
+	const urls, workers = 500, 8
+	var added atomic.Int64
+	var wg sync.WaitGroup
+	for g := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range urls {
+				if v.Add(fmt.Sprintf("https://www.udea.edu.co/nota/%d", (i+g*7)%urls)) {
+					added.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := added.Load(); n != urls {
+		t.Errorf("%d URLs agregadas, se esperaban %d", n, urls)
+	}
+	if s := v.Stats(); s.Misses != urls || s.Hits != (workers-1)*urls || s.Entries != urls {
+		t.Errorf("estadísticas %+v, se esperaban %d nuevas, %d repetidas y %d entradas", s, urls, (workers-1)*urls, urls)
+	}
+}
@@ -0,0 +1,177 @@
+package x
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-collector/crawler"
+)
+
+// Límites de la búsqueda reciente de X.
+const (
+	// MinPageSize y MaxPageSize son los valores que acepta max_results.
+	MinPageSize = 10
+	MaxPageSize = 100
+	// DefaultMaxTweets es el tope por corrida si no se indica otro: la cuota
+	// mensual de lectura de los planes básicos se agota rápido.
+	DefaultMaxTweets = 500
+	// DefaultPausa separa las páginas: la búsqueda reciente admite 450
+	// peticiones cada 15 minutos con token de aplicación (una cada 2 s).
+	DefaultPausa = 2 * time.Second
+	// maxReintentos es cuántas veces se reintenta una página tras un 429.
+	maxReintentos = 3
+	// maxEspera limita la espera hasta el reinicio de la ventana de X.
+	maxEspera = 15 * time.Minute
+)
+
+// ErrRateLimited: X respondió 429 y se agotaron los reintentos.
+var ErrRateLimited = errors.New("límite de peticiones de X agotado")
+
+// APIError es una respuesta de error de la API de X.
+type APIError struct {
+	HTTPStatus int
+	Body       string
+	// Reset es cuándo se reinicia la ventana de peticiones (cero si X no lo informó).
+	Reset time.Time
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error HTTP: status code %d. Respuesta de X:\n%s", e.HTTPStatus, e.Body)
+}
+
+// Is permite errors.Is(err, ErrRateLimited).
+func (e *APIError) Is(target error) bool {
+	return target == ErrRateLimited && e.HTTPStatus == http.StatusTooManyRequests
+}
+
+// rateLimitReset lee x-rate-limit-reset (segundos Unix).
+func rateLimitReset(h http.Header) time.Time {
+	secs, err := strconv.ParseInt(h.Get("x-rate-limit-reset"), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0)
+}
+
+// Pager recorre las páginas de una búsqueda siguiendo next_token, hasta
+// MaxTweets o hasta que X no devuelva más, con una pausa entre páginas. Ante
+// un 429 espera (hasta el reinicio de la ventana, si X lo informa, o el
+// doble de la pausa cada vez) y reintenta la misma página.
+//
+//	p := c.Paginar(q, 100, desde, hasta)
+//	for !p.Done() {
+//		resp, err := p.NextPage(ctx)
+//		...
+//	}
+type Pager struct {
+	crawler           *Crawler
+	query, start, end string
+	pageSize          int
+
+	// MaxTweets es el total máximo a recorrer (por defecto DefaultMaxTweets;
+	// 0 no limita).
+	MaxTweets int
+	// Pausa es la espera entre páginas (por defecto DefaultPausa).
+	Pausa time.Duration
+	// Reintento, si no es nil, recibe cada reintento tras un 429: la página
+	// y cuánto se espera antes de pedirla de nuevo.
+	Reintento func(pagina int, espera time.Duration)
+
+	nextToken string
+	page      int
+	fetched   int
+	done      bool
+}
+
+// Paginar prepara el recorrido de una búsqueda; la primera petición se hace
+// en el primer NextPage.
+func (x *Crawler) Paginar(queryRaw string, pageSize int, startTime, endTime string) *Pager {
+	return &Pager{
+		crawler: x, query: queryRaw, start: startTime, end: endTime,
+		pageSize: pageSize, MaxTweets: DefaultMaxTweets, Pausa: DefaultPausa,
+	}
+}
+
+// Done indica que no quedan páginas (o que la última petición falló).
+func (p *Pager) Done() bool {
+	return p.done
+}
+
+// Fetched es la cantidad de tweets recibidos hasta ahora.
+func (p *Pager) Fetched() int {
+	return p.fetched
+}
+
+// NextPage pide la página siguiente. Un error termina el recorrido; si es por
+// el límite de peticiones, errors.Is(err, ErrRateLimited). Las esperas entre
+// páginas y reintentos se cortan si se cancela ctx.
+func (p *Pager) NextPage(ctx context.Context) (*Response, error) {
+	if p.done {
+		return nil, fmt.Errorf("paginación de X terminada")
+	}
+	size := p.pageSize
+	if size <= 0 || size > MaxPageSize {
+		size = MaxPageSize
+	}
+	if rest := p.MaxTweets - p.fetched; p.MaxTweets > 0 && size > rest {
+		size = rest
+	}
+	if size < MinPageSize {
+		size = MinPageSize
+	}
+	if p.page > 0 && p.Pausa > 0 {
+		if err := crawler.Sleep(ctx, p.Pausa); err != nil {
+			p.done = true
+			return nil, err
+		}
+	}
+	p.page++
+
+	espera := p.Pausa
+	for intento := 0; ; intento++ {
+		resp, err := p.crawler.BuscarPagina(ctx, p.query, size, p.start, p.end, p.nextToken)
+		if err == nil {
+			return p.recibir(resp), nil
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !errors.Is(err, ErrRateLimited) || intento == maxReintentos {
+			p.done = true
+			return nil, err
+		}
+		if espera <= 0 {
+			espera = DefaultPausa
+		}
+		espera *= 2
+		if !apiErr.Reset.IsZero() {
+			espera = time.Until(apiErr.Reset) + time.Second
+		}
+		if espera > maxEspera {
+			p.done = true
+			return nil, err
+		}
+		if p.Reintento != nil {
+			p.Reintento(p.page, espera)
+		}
+		if err := crawler.Sleep(ctx, espera); err != nil {
+			p.done = true
+			return nil, err
+		}
+	}
+}
+
+// recibir cuenta la página, la recorta al tope y guarda el cursor.
+func (p *Pager) recibir(resp *Response) *Response {
+	if p.MaxTweets > 0 && p.fetched+len(resp.Data) > p.MaxTweets {
+		resp.Data = resp.Data[:p.MaxTweets-p.fetched]
+	}
+	p.fetched += len(resp.Data)
+	p.nextToken = resp.Meta.NextToken
+	if p.nextToken == "" || len(resp.Data) == 0 || (p.MaxTweets > 0 && p.fetched >= p.MaxTweets) {
+		p.done = true
+	}
+	return resp
+}
@@ -0,0 +1,405 @@
+// Package x consulta la búsqueda reciente (últimos 7 días) de la API v2 de X.
+package x
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+)
+
+type Response struct {
+	Data     []Tweet  `json:"data"`
+	Includes Includes `json:"includes"`
+	Meta     Meta     `json:"meta"`
+}
+
+// Includes son los objetos a los que remiten los tweets de la página: sus
+// autores, los tweets que retuitean, citan o responden, y los lugares de
+// geo.place_id.
+type Includes struct {
+	Users  []User  `json:"users"`
+	Tweets []Tweet `json:"tweets"`
+	Places []Place `json:"places"`
+}
+
+// User es la cuenta de un autor.
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// Place es un lugar de X (una ciudad, un barrio, un punto de interés).
+type Place struct {
+	ID          string `json:"id"`
+	FullName    string `json:"full_name"`
+	CountryCode string `json:"country_code"`
+}
+
+// Estructura para capturar las métricas de interacción
+type PublicMetrics struct {
+	RetweetCount int `json:"retweet_count"`
+	LikeCount    int `json:"like_count"`
+	ReplyCount   int `json:"reply_count"`
+	QuoteCount   int `json:"quote_count"`
+}
+
+// Tweet incluye las métricas de interacción
+type Tweet struct {
+	ID            string        `json:"id"`
+	AuthorID      string        `json:"author_id"`
+	Text          string        `json:"text"`
+	CreatedAt     string        `json:"created_at"`
+	PublicMetrics PublicMetrics `json:"public_metrics"`
+	Entities      Entities      `json:"entities"`
+	// ReferencedTweets son los tweets que este retuitea, cita o responde.
+	ReferencedTweets []ReferencedTweet `json:"referenced_tweets"`
+	// Geo solo llega si el autor etiquetó el tweet con un lugar (o, muy
+	// rara vez, con sus coordenadas).
+	Geo                *Geo                `json:"geo"`
+	ContextAnnotations []ContextAnnotation `json:"context_annotations"`
+}
+
+// ReferencedTweet es un tweet retuiteado, citado o respondido; Type es
+// retweeted, quoted o replied_to.
+type ReferencedTweet struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// Entities son los enlaces del tweet (X los acorta todos con t.co), sus
+// hashtags y menciones, y las entidades que X reconoce en el texto.
+type Entities struct {
+	URLs []struct {
+		URL         string `json:"url"`
+		ExpandedURL string `json:"expanded_url"`
+	} `json:"urls"`
+	Hashtags []struct {
+		Tag string `json:"tag"`
+	} `json:"hashtags"`
+	Mentions []struct {
+		Username string `json:"username"`
+	} `json:"mentions"`
+	// Annotations son personas, lugares, organizaciones y productos
+	// nombrados en el texto; Type es la clase y Probability la confianza.
+	Annotations []struct {
+		Type           string  `json:"type"`
+		NormalizedText string  `json:"normalized_text"`
+		Probability    float64 `json:"probability"`
+	} `json:"annotations"`
+}
+
+// Geo es la ubicación del tweet; Coordinates es [longitud, latitud].
+type Geo struct {
+	PlaceID     string `json:"place_id"`
+	Coordinates *struct {
+		Coordinates []float64 `json:"coordinates"`
+	} `json:"coordinates"`
+}
+
+// ContextAnnotation es un tema que X asigna al tweet: el dominio (la clase
+// de tema, ej: "Interests and Hobbies Category") y la entidad dentro de él.
+type ContextAnnotation struct {
+	Domain struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"domain"`
+	Entity struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"entity"`
+}
+
+type Meta struct {
+	NewestID    string `json:"newest_id"`
+	OldestID    string `json:"oldest_id"`
+	ResultCount int    `json:"result_count"`
+	NextToken   string `json:"next_token"`
+}
+
+type Crawler struct {
+	BaseURL     string
+	Client      *http.Client
+	BearerToken string
+	// Retweets incluye los retweets en la búsqueda para la red de difusión
+	// (ver Normalize); por defecto se excluyen.
+	Retweets bool
+}
+
+func NewCrawler(bearerToken string) *Crawler {
+	return &Crawler{
+		BaseURL: "https://api.twitter.com/2/tweets/search/recent",
+		Client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+		BearerToken: bearerToken,
+	}
+}
+
+// BuscarTweets busca tweets originales (sin retweets, salvo con Retweets). Los filtros de idioma
+// o términos adicionales van en queryRaw, ej: `("Universidad de Antioquia" OR UdeA) lang:es`.
+// Devuelve solo la primera página; para recorrer más de maxResults (100 como
+// máximo por petición) usar Paginar.
+func (x *Crawler) BuscarTweets(ctx context.Context, queryRaw string, maxResults int, startTime, endTime string) (*Response, error) {
+	return x.BuscarPagina(ctx, queryRaw, maxResults, startTime, endTime, "")
+}
+
+// BuscarPagina pide la página de la búsqueda que indica nextToken (vacío para
+// la primera). El token de la siguiente viene en Meta.NextToken.
+func (x *Crawler) BuscarPagina(ctx context.Context, queryRaw string, maxResults int, startTime, endTime, nextToken string) (*Response, error) {
+
+	finalQuery := fmt.Sprintf(`(%s) -is:retweet`, queryRaw)
+	if x.Retweets {
+		finalQuery = queryRaw
+	}
+
+	// 1. Construir URL con parámetros
+	params := url.Values{}
+	params.Add("query", finalQuery)
+	// Incluir 'public_metrics' para obtener el conteo de retweets,
+	// 'entities' para los enlaces expandidos, hashtags y entidades, y
+	// 'geo' y 'context_annotations' para el lugar y los temas según X
+	// 'referenced_tweets' y 'author_id', con sus expansiones, arman la red
+	// de retweets, citas y respuestas
+	params.Add("tweet.fields", "created_at,public_metrics,entities,geo,context_annotations,referenced_tweets,author_id")
+	params.Add("expansions", "geo.place_id,author_id,referenced_tweets.id,referenced_tweets.id.author_id")
+	params.Add("user.fields", "username")
+	params.Add("place.fields", "full_name,country_code")
+	params.Add("max_results", fmt.Sprintf("%d", maxResults))
+
+	// Parámetros de tiempo
+	params.Add("start_time", startTime)
+	params.Add("end_time", endTime)
+	if nextToken != "" {
+		params.Add("next_token", nextToken)
+	}
+
+	fullURL := fmt.Sprintf("%s?%s", x.BaseURL, params.Encode())
+
+	if nextToken == "" {
+		fmt.Printf("Consultando X (Reciente)...\nQuery: %s\nRango: %s a %s\n", finalQuery, startTime, endTime)
+	}
+
+	// 2. Crear request y añadir Bearer Token
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+x.BearerToken)
+	req.Header.Set("User-Agent", "EthicalXCrawler/1.0 (StudentResearch)")
+
+	// 3. Realizar petición y manejo de errores
+	resp, err := x.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{HTTPStatus: resp.StatusCode, Body: string(body), Reset: rateLimitReset(resp.Header)}
+	}
+
+	var apiResp Response
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("error parseando JSON: %w. Respuesta recibida:\n%s", err, crawler.Preview(body))
+	}
+
+	return &apiResp, nil
+}
+
+func ExplorarDatos(response *Response) {
+	if response == nil || response.Meta.ResultCount == 0 {
+		fmt.Println("\n--- EXPLORACIÓN DE DATOS X ---")
+		fmt.Println("No se encontraron tweets que coincidan con la búsqueda.")
+		return
+	}
+
+	fmt.Println("\n--- EXPLORACIÓN DE DATOS - X (Últimos 7 Días) ---")
+	fmt.Printf("Total de tweets encontrados: %d\n", response.Meta.ResultCount)
+	fmt.Printf("Tweets recuperados: %d\n\n", len(response.Data))
+
+	// Mostrar los primeros 5 tweets
+	fmt.Println("Primeros 5 Tweets de Muestra:")
+	for i, tweet := range response.Data {
+		if i >= 5 {
+			break
+		}
+		fmt.Printf("\n  %d. ID: %s\n", i+1, tweet.ID)
+		fmt.Printf("      Fecha: %s\n", tweet.CreatedAt)
+		fmt.Printf("      Compartidos/Retweets: %d\n", tweet.PublicMetrics.RetweetCount)
+		fmt.Printf("      Likes: %d | Respuestas: %d\n", tweet.PublicMetrics.LikeCount, tweet.PublicMetrics.ReplyCount)
+		fmt.Printf("      Texto: %s\n", tweet.Text)
+	}
+}
+
+// ExpandedText es el texto del tweet con los enlaces t.co expandidos.
+func (t Tweet) ExpandedText() string {
+	text := t.Text
+	for _, u := range t.Entities.URLs {
+		if u.URL != "" && u.ExpandedURL != "" {
+			text = strings.ReplaceAll(text, u.URL, u.ExpandedURL)
+		}
+	}
+	return text
+}
+
+// Normalize convierte los tweets al modelo común del corpus. El título es el
+// texto recortado; el texto completo va en Body, con los enlaces t.co
+// reemplazados por la URL a la que apuntan, y las métricas públicas en
+// Engagement. Los temas y entidades de X, los hashtags y las menciones van
+// en Annotations, el lugar en Geo y las citas y respuestas en References.
+// Un retweet no es un artículo: aporta el tweet original (de includes), con
+// el retweet entre sus References; los retweets de un mismo original en la
+// página se juntan en él.
+func (r *Response) Normalize() []*article.Article {
+	inc := r.Includes.index()
+	out := make([]*article.Article, 0, len(r.Data))
+	originals := make(map[string]*article.Article)
+	for _, t := range r.Data {
+		if ref, ok := t.reference(article.RefRetweeted); ok {
+			orig, found := inc.tweets[ref.ID]
+			if !found {
+				continue
+			}
+			a, seen := originals[orig.ID]
+			if !seen {
+				a = orig.article(inc)
+				originals[orig.ID] = a
+				out = append(out, a)
+			}
+			a.References = append(a.References, inc.edge(t, ref))
+			continue
+		}
+		a := t.article(inc)
+		for _, ref := range t.ReferencedTweets {
+			a.References = append(a.References, inc.edge(t, ref))
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// article convierte el tweet en artículo.
+func (t Tweet) article(inc includes) *article.Article {
+	title := []rune(strings.Join(strings.Fields(t.Text), " "))
+	if len(title) > 120 {
+		title = append(title[:117], []rune("...")...)
+	}
+	a := &article.Article{
+		Source: "x",
+		URL:    "https://x.com/i/web/status/" + t.ID,
+		Title:  string(title),
+		Author: inc.users[t.AuthorID],
+		Domain: "x.com",
+		Body:   t.ExpandedText(),
+		Engagement: &article.Engagement{
+			Likes: t.PublicMetrics.LikeCount, Reposts: t.PublicMetrics.RetweetCount,
+			Replies: t.PublicMetrics.ReplyCount, Quotes: t.PublicMetrics.QuoteCount,
+		},
+	}
+	a.Published, a.RawPublished = dates.Normalize(t.CreatedAt)
+	a.Annotations = t.annotations()
+	if t.Geo != nil {
+		a.Geo = t.Geo.location(inc.places)
+	}
+	return a
+}
+
+// reference devuelve la referencia del tipo pedido, si el tweet la tiene.
+func (t Tweet) reference(kind string) (ReferencedTweet, bool) {
+	for _, ref := range t.ReferencedTweets {
+		if ref.Type == kind {
+			return ref, true
+		}
+	}
+	return ReferencedTweet{}, false
+}
+
+// includes indexa los objetos de Includes por ID; de los usuarios guarda el
+// nombre de usuario.
+type includes struct {
+	users  map[string]string
+	tweets map[string]Tweet
+	places map[string]Place
+}
+
+func (i Includes) index() includes {
+	out := includes{users: make(map[string]string), tweets: make(map[string]Tweet), places: make(map[string]Place)}
+	for _, u := range i.Users {
+		out.users[u.ID] = u.Username
+	}
+	for _, t := range i.Tweets {
+		out.tweets[t.ID] = t
+	}
+	for _, p := range i.Places {
+		out.places[p.ID] = p
+	}
+	return out
+}
+
+// edge es la referencia de t al tweet ref.
+func (inc includes) edge(t Tweet, ref ReferencedTweet) article.Reference {
+	e := article.Reference{TweetID: t.ID, Author: inc.users[t.AuthorID], Kind: ref.Type, RefTweetID: ref.ID}
+	if orig, ok := inc.tweets[ref.ID]; ok {
+		e.RefAuthor = inc.users[orig.AuthorID]
+	}
+	e.Created, _ = dates.Normalize(t.CreatedAt)
+	return e
+}
+
+// annotations reúne los temas, las entidades, los hashtags y las menciones
+// del tweet, sin repetir.
+func (t Tweet) annotations() []article.Annotation {
+	var out []article.Annotation
+	seen := make(map[article.Annotation]bool)
+	add := func(a article.Annotation) {
+		key := a
+		key.Score = 0
+		if a.Name != "" && !seen[key] {
+			seen[key] = true
+			out = append(out, a)
+		}
+	}
+	for _, c := range t.ContextAnnotations {
+		add(article.Annotation{Kind: article.AnnotationContext, Domain: c.Domain.Name, DomainID: c.Domain.ID, Name: c.Entity.Name, ID: c.Entity.ID})
+	}
+	for _, e := range t.Entities.Annotations {
+		add(article.Annotation{Kind: article.AnnotationEntity, Domain: e.Type, Name: e.NormalizedText, Score: e.Probability})
+	}
+	for _, h := range t.Entities.Hashtags {
+		add(article.Annotation{Kind: article.AnnotationHashtag, Name: h.Tag})
+	}
+	for _, m := range t.Entities.Mentions {
+		add(article.Annotation{Kind: article.AnnotationMention, Name: m.Username})
+	}
+	return out
+}
+
+// location completa el lugar del tweet con los datos de includes.places.
+func (g *Geo) location(places map[string]Place) *article.Geo {
+	out := &article.Geo{PlaceID: g.PlaceID}
+	if p, ok := places[g.PlaceID]; ok {
+		out.Place, out.Country = p.FullName, p.CountryCode
+	}
+	if g.Coordinates != nil && len(g.Coordinates.Coordinates) == 2 {
+		lon, lat := g.Coordinates.Coordinates[0], g.Coordinates.Coordinates[1]
+		out.Lat, out.Lon = &lat, &lon
+	}
+	if out.PlaceID == "" && out.Lat == nil {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,277 @@
+// Package youtube consulta la API de datos de YouTube (v3): la búsqueda de
+// videos (search.list), que encuentra los que mencionan la consulta en el
+// título o la descripción, y los datos de cada video (videos.list), que traen
+// el canal y los contadores de vistas y me gusta.
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/dates"
+)
+
+// MaxResults es el máximo de resultados por página de search.list y de IDs
+// por consulta a videos.list.
+const MaxResults = 50
+
+// ErrQuota indica que se agotó la cuota diaria de la clave (cada búsqueda
+// cuesta 100 unidades de las 10.000 por defecto; se renueva a medianoche,
+// hora del Pacífico).
+var ErrQuota = errors.New("se agotó la cuota diaria de la API de YouTube")
+
+// SearchResponse es una página de search.list; de cada resultado solo se usa
+// el ID: los datos del video se piden a videos.list.
+type SearchResponse struct {
+	NextPageToken string `json:"nextPageToken"`
+	PageInfo      struct {
+		TotalResults int `json:"totalResults"`
+	} `json:"pageInfo"`
+	Items []struct {
+		ID struct {
+			VideoID string `json:"videoId"`
+		} `json:"id"`
+	} `json:"items"`
+}
+
+// Response es la respuesta de videos.list.
+type Response struct {
+	Items []Video `json:"items"`
+}
+
+// Video es un video con sus datos y contadores. La API entrega los
+// contadores como texto y omite los que el canal oculta.
+type Video struct {
+	ID         string  `json:"id"`
+	Snippet    Snippet `json:"snippet"`
+	Statistics struct {
+		ViewCount    string `json:"viewCount"`
+		LikeCount    string `json:"likeCount"`
+		CommentCount string `json:"commentCount"`
+	} `json:"statistics"`
+}
+
+type Snippet struct {
+	PublishedAt  string   `json:"publishedAt"`
+	ChannelID    string   `json:"channelId"`
+	ChannelTitle string   `json:"channelTitle"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	Tags         []string `json:"tags"`
+	// DefaultLanguage es el idioma del título y la descripción, y
+	// DefaultAudioLanguage el del audio; muchos canales no los declaran.
+	DefaultLanguage      string `json:"defaultLanguage"`
+	DefaultAudioLanguage string `json:"defaultAudioLanguage"`
+	Thumbnails           map[string]struct {
+		URL string `json:"url"`
+	} `json:"thumbnails"`
+}
+
+// APIError es una respuesta de error de la API.
+type APIError struct {
+	HTTPStatus int
+	Reason     string // ej: quotaExceeded, keyInvalid
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error HTTP: status code %d. Respuesta de YouTube:\n%s", e.HTTPStatus, e.Body)
+}
+
+type Crawler struct {
+	BaseURL string
+	Client  *http.Client
+	APIKey  string
+}
+
+func NewCrawler(apiKey string) *Crawler {
+	return &Crawler{
+		BaseURL: "https://www.googleapis.com/youtube/v3",
+		Client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+		APIKey: apiKey,
+	}
+}
+
+// SearchOptions acota la búsqueda. Since y Until en cero no limitan; Region
+// (ISO 3166-1 alfa-2) restringe a los videos disponibles en ese país y
+// Language (ISO 639-1) prioriza los de ese idioma, sin excluir los demás.
+type SearchOptions struct {
+	Since, Until time.Time
+	Region       string
+	Language     string
+	MaxResults   int
+	PageToken    string
+}
+
+// Search pide una página de videos que mencionan q, del más reciente hacia
+// atrás. La siguiente se pide con el NextPageToken de la respuesta (vacío: no
+// hay más).
+func (y *Crawler) Search(ctx context.Context, q string, opts SearchOptions) (*SearchResponse, error) {
+	params := url.Values{}
+	params.Set("part", "id")
+	params.Set("type", "video")
+	params.Set("order", "date")
+	params.Set("q", q)
+	params.Set("maxResults", strconv.Itoa(opts.MaxResults))
+	if !opts.Since.IsZero() {
+		params.Set("publishedAfter", opts.Since.UTC().Format(time.RFC3339))
+	}
+	if !opts.Until.IsZero() {
+		params.Set("publishedBefore", opts.Until.UTC().Format(time.RFC3339))
+	}
+	if opts.Region != "" {
+		params.Set("regionCode", strings.ToUpper(opts.Region))
+	}
+	if opts.Language != "" {
+		params.Set("relevanceLanguage", opts.Language)
+	}
+	if opts.PageToken != "" {
+		params.Set("pageToken", opts.PageToken)
+	}
+	var resp SearchResponse
+	if err := y.get(ctx, "/search", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Videos pide los datos y contadores de hasta MaxResults videos. Los que ya
+// no existen (borrados o privados) no vienen en la respuesta.
+func (y *Crawler) Videos(ctx context.Context, ids []string) (*Response, error) {
+	params := url.Values{}
+	params.Set("part", "snippet,statistics")
+	params.Set("id", strings.Join(ids, ","))
+	var resp Response
+	if err := y.get(ctx, "/videos", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (y *Crawler) get(ctx context.Context, path string, params url.Values, v any) error {
+	params.Set("key", y.APIKey)
+	fullURL := fmt.Sprintf("%s%s?%s", y.BaseURL, path, params.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := y.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Errors []struct {
+					Reason string `json:"reason"`
+				} `json:"errors"`
+			} `json:"error"`
+		}
+		json.Unmarshal(body, &apiErr)
+		e := &APIError{HTTPStatus: resp.StatusCode, Body: crawler.Preview(body)}
+		if len(apiErr.Error.Errors) > 0 {
+			e.Reason = apiErr.Error.Errors[0].Reason
+		}
+		if e.Reason == "quotaExceeded" || e.Reason == "dailyLimitExceeded" {
+			return fmt.Errorf("%w: %v", ErrQuota, e)
+		}
+		return e
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error parseando JSON: %w. Respuesta recibida:\n%s", err, crawler.Preview(body))
+	}
+	return nil
+}
+
+// IDs devuelve los IDs de los videos de la página.
+func (r *SearchResponse) IDs() []string {
+	out := make([]string, 0, len(r.Items))
+	for _, it := range r.Items {
+		if it.ID.VideoID != "" {
+			out = append(out, it.ID.VideoID)
+		}
+	}
+	return out
+}
+
+// Link es la URL canónica del video (la misma que reconoce unfurl).
+func (v Video) Link() string {
+	return "https://www.youtube.com/watch?v=" + v.ID
+}
+
+// Language es el idioma declarado del video en ISO 639-1 (sin la región:
+// "es-419" es "es"), o "" si el canal no lo declara.
+func (v Video) Language() string {
+	lang := v.Snippet.DefaultLanguage
+	if lang == "" {
+		lang = v.Snippet.DefaultAudioLanguage
+	}
+	base, _, _ := strings.Cut(lang, "-")
+	return strings.ToLower(base)
+}
+
+// thumbnail es la miniatura de mayor resolución disponible.
+func (v Video) thumbnail() string {
+	for _, size := range []string{"maxres", "standard", "high", "medium", "default"} {
+		if t, ok := v.Snippet.Thumbnails[size]; ok && t.URL != "" {
+			return t.URL
+		}
+	}
+	return ""
+}
+
+// Normalize convierte los videos al modelo común del corpus: el autor es el
+// canal, el texto la descripción y las vistas, me gusta y comentarios van en
+// Engagement. La miniatura queda como adjunto.
+func (r *Response) Normalize() []*article.Article {
+	out := make([]*article.Article, 0, len(r.Items))
+	for _, v := range r.Items {
+		a := &article.Article{
+			Source:   "youtube",
+			URL:      v.Link(),
+			Title:    v.Snippet.Title,
+			Author:   v.Snippet.ChannelTitle,
+			Domain:   "youtube.com",
+			Language: v.Language(),
+			Body:     v.Snippet.Description,
+			Engagement: &article.Engagement{
+				Views:   count(v.Statistics.ViewCount),
+				Likes:   count(v.Statistics.LikeCount),
+				Replies: count(v.Statistics.CommentCount),
+			},
+		}
+		if thumb := v.thumbnail(); thumb != "" {
+			a.Media = append(a.Media, article.Media{URL: thumb})
+		}
+		a.Published, a.RawPublished = dates.Normalize(v.Snippet.PublishedAt)
+		out = append(out, a)
+	}
+	return out
+}
+
+// count interpreta un contador; los ocultos (ausentes) cuentan como 0.
+func count(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
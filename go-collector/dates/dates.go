@@ -0,0 +1,109 @@
+// Package dates interpreta las fechas de publicación de las fuentes, cada una
+// con su formato (RFC 3339 en Guardian, NewsAPI y X; 20060102T150405Z en
+// GDELT; RFC 1123 y variantes en RSS), y las normaliza a UTC. Una fecha que no
+// se puede interpretar no hace fallar la respuesta entera: el artículo queda
+// sin fecha y con el texto original (article.Article.RawPublished).
+package dates
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnparsed indica que la fecha no tiene ninguno de los formatos conocidos.
+var ErrUnparsed = errors.New("fecha en formato desconocido")
+
+// layouts son los formatos conocidos, del más al menos común. Los que no
+// indican zona se toman como UTC.
+var layouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"20060102T150405Z",
+	"20060102150405",
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC850,
+	time.ANSIC,
+	"2006-01-02",
+}
+
+// Parse interpreta s en cualquiera de los formatos conocidos, o como segundos
+// (o milisegundos) Unix, y la devuelve en UTC.
+func Parse(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, ErrUnparsed
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		switch len(s) {
+		case 10:
+			return time.Unix(n, 0).UTC(), nil
+		case 13:
+			return time.UnixMilli(n).UTC(), nil
+		}
+	}
+	return time.Time{}, ErrUnparsed
+}
+
+// Normalize devuelve la primera de values que se pueda interpretar, en orden
+// de preferencia (ej: publicación y, si no, actualización). Si ninguna se
+// puede, devuelve la fecha cero y el primer valor no vacío, para dejarlo
+// registrado; si todas están vacías, la fuente no informó fecha y raw es "".
+func Normalize(values ...string) (t time.Time, raw string) {
+	for _, v := range values {
+		if parsed, err := Parse(v); err == nil {
+			return parsed, ""
+		}
+		if raw == "" {
+			raw = strings.TrimSpace(v)
+		}
+	}
+	return time.Time{}, raw
+}
+
+// Motivos por los que una fecha interpretada no es creíble (ver Check).
+const (
+	IssueFuture = "future" // posterior a la descarga
+	IssueBogus  = "bogus"  // el cero de un sistema: la época Unix o antes de 1900
+)
+
+// FutureTolerance es cuánto puede adelantarse una fecha a la descarga sin
+// considerarse futura, por relojes desfasados.
+const FutureTolerance = time.Hour
+
+// epoch es el 1970-01-01 de las fechas en cero de la época Unix.
+var epoch = time.Unix(0, 0).UTC()
+
+// Check revisa una fecha ya interpretada contra el momento de la descarga:
+// devuelve IssueFuture o IssueBogus si no puede ser la de publicación, o ""
+// si es creíble (o está en cero). La época Unix se acepta con un día de
+// diferencia, por la zona horaria con que la escribió el feed.
+func Check(t, fetched time.Time) string {
+	switch {
+	case t.IsZero():
+		return ""
+	case t.After(fetched.Add(FutureTolerance)):
+		return IssueFuture
+	case t.Year() < 1900, t.Sub(epoch).Abs() <= 24*time.Hour:
+		return IssueBogus
+	}
+	return ""
+}
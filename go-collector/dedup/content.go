@@ -0,0 +1,24 @@
+package dedup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// minContentRunes es el largo mínimo del texto para compararlo por huella:
+// los textos cortos (resúmenes, publicaciones de redes) se repiten entre
+// historias distintas.
+const minContentRunes = 280
+
+// ContentHash devuelve la huella del texto completo de un artículo, sin
+// distinguir mayúsculas, tildes ni espacios, o "" si el texto es demasiado
+// corto para compararlo.
+func ContentHash(body string) string {
+	text := strings.Join(strings.Fields(fold(body)), " ")
+	if len([]rune(text)) < minContentRunes {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
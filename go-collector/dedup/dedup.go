@@ -0,0 +1,275 @@
+// Package dedup detecta la misma historia traída por varias fuentes (GDELT,
+// Guardian, NewsAPI y los feeds suelen repetir las mismas notas) para
+// guardarla una sola vez, con las demás apariciones como procedencia.
+package dedup
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/corpus"
+	"go-collector/storage"
+)
+
+// Valores por defecto de config.Dedup.
+const (
+	DefaultMaxDistance = 3
+	DefaultWindow      = 48 * time.Hour
+)
+
+// Estrategias de config.Dedup.Strategies.
+const (
+	StrategyURL         = "url"          // la URL tal cual
+	StrategyCanonical   = "canonical"    // la URL normalizada (ver NormalizeURL)
+	StrategyContentHash = "content_hash" // el mismo texto completo
+	StrategySimHash     = "simhash"      // el título casi igual, en fechas cercanas
+)
+
+// DefaultStrategies es el orden que se usa si la configuración no indica otro.
+var DefaultStrategies = []string{StrategyCanonical, StrategySimHash}
+
+// Deduper es un índice de artículos ya guardados, consultado con una cadena
+// de estrategias en orden: gana la primera que encuentra una coincidencia.
+// No es seguro para uso concurrente.
+type Deduper struct {
+	// MaxDistance es la distancia máxima entre huellas de títulos (bits de 64).
+	MaxDistance int
+	// Window es la diferencia máxima de publicación entre dos apariciones.
+	Window time.Duration
+
+	strategies []string
+	counts     map[string]*StrategyStats
+
+	byExact   map[string]*article.Article
+	byURL     map[string]*article.Article
+	byContent map[string]*article.Article
+	titles    []titled
+}
+
+type titled struct {
+	hash uint64
+	a    *article.Article
+}
+
+// StrategyStats cuenta cuántos artículos llegaron a probar una estrategia
+// (no coincidieron con las anteriores) y en cuántos encontró el duplicado.
+type StrategyStats struct {
+	Name    string
+	Checked int
+	Matched int
+}
+
+// New crea un índice vacío con los valores y las estrategias por defecto.
+func New() *Deduper {
+	d, _ := NewWithStrategies(DefaultStrategies)
+	return d
+}
+
+// NewWithStrategies crea un índice vacío que prueba las estrategias en el
+// orden dado.
+func NewWithStrategies(strategies []string) (*Deduper, error) {
+	d := &Deduper{MaxDistance: DefaultMaxDistance, Window: DefaultWindow, counts: make(map[string]*StrategyStats)}
+	for _, name := range strategies {
+		if !slices.Contains(config.DedupStrategies, name) {
+			return nil, fmt.Errorf("estrategia de deduplicación desconocida: %q", name)
+		}
+		if d.counts[name] != nil {
+			continue
+		}
+		d.strategies = append(d.strategies, name)
+		d.counts[name] = &StrategyStats{Name: name}
+	}
+	if len(d.strategies) == 0 {
+		return nil, fmt.Errorf("no hay estrategias de deduplicación")
+	}
+	return d, nil
+}
+
+// FromConfig crea el índice configurado, o nil si la deduplicación no está
+// activada.
+func FromConfig(cfg config.Dedup) (*Deduper, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	strategies := cfg.Strategies
+	if len(strategies) == 0 {
+		strategies = DefaultStrategies
+	}
+	d, err := NewWithStrategies(strategies)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxDistance > 0 {
+		d.MaxDistance = cfg.MaxDistance
+	}
+	if cfg.Window != "" {
+		w, err := config.ParseSpan(cfg.Window)
+		if err != nil {
+			return nil, err
+		}
+		d.Window = w
+	}
+	return d, nil
+}
+
+// Strategies devuelve las estrategias en el orden en que se prueban.
+func (d *Deduper) Strategies() []string {
+	return d.strategies
+}
+
+// Stats devuelve lo que probó y encontró cada estrategia desde que se creó el
+// índice, en el orden en que se prueban.
+func (d *Deduper) Stats() []StrategyStats {
+	out := make([]StrategyStats, 0, len(d.strategies))
+	for _, name := range d.strategies {
+		out = append(out, *d.counts[name])
+	}
+	return out
+}
+
+// FormatStats resume las estadísticas para la salida de los comandos, ej:
+// "canonical 3 de 120, simhash 1 de 117".
+func FormatStats(stats []StrategyStats) string {
+	parts := make([]string, len(stats))
+	for i, s := range stats {
+		parts[i] = fmt.Sprintf("%s %d de %d", s.Name, s.Matched, s.Checked)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Load reemplaza el índice por los artículos del corpus publicados cerca de
+// los de articles (Window antes y después), que es donde pueden estar sus
+// duplicados.
+func (d *Deduper) Load(store corpus.Store, articles []*article.Article) error {
+	d.byExact, d.byURL, d.byContent, d.titles = nil, nil, nil, nil
+	var from, to time.Time
+	for _, a := range articles {
+		if a.Published.IsZero() {
+			continue
+		}
+		if from.IsZero() || a.Published.Before(from) {
+			from = a.Published
+		}
+		if a.Published.After(to) {
+			to = a.Published
+		}
+	}
+	if from.IsZero() {
+		return nil
+	}
+	existing, err := store.ListFiltered(storage.Filter{From: from.Add(-d.Window), To: to.Add(d.Window)})
+	if err != nil {
+		return err
+	}
+	for _, a := range existing {
+		d.Add(a)
+	}
+	return nil
+}
+
+// Add agrega un artículo guardado al índice. Solo se calculan las claves de
+// las estrategias activas.
+func (d *Deduper) Add(a *article.Article) {
+	for _, name := range d.strategies {
+		switch name {
+		case StrategyURL:
+			addKey(&d.byExact, a.URL, a)
+		case StrategyCanonical:
+			addKey(&d.byURL, NormalizeURL(a.URL), a)
+		case StrategyContentHash:
+			if key := ContentHash(a.Body); key != "" {
+				addKey(&d.byContent, key, a)
+			}
+		case StrategySimHash:
+			if tokens := words(a.Title); len(tokens) >= minTitleWords {
+				d.titles = append(d.titles, titled{simHash(tokens), a})
+			}
+		}
+	}
+}
+
+// addKey guarda a bajo key si no hay ya otro artículo: el primero guardado
+// es el original.
+func addKey(m *map[string]*article.Article, key string, a *article.Article) {
+	if *m == nil {
+		*m = make(map[string]*article.Article)
+	}
+	if _, ok := (*m)[key]; !ok {
+		(*m)[key] = a
+	}
+}
+
+// Match devuelve el artículo del índice que es la misma historia que a, o
+// nil si no hay ninguno (ver MatchBy).
+func (d *Deduper) Match(a *article.Article) *article.Article {
+	m, _ := d.MatchBy(a)
+	return m
+}
+
+// MatchBy prueba las estrategias en orden y devuelve el primer artículo del
+// índice que coincide con a y el nombre de la estrategia que lo encontró.
+// Con simhash es el título más parecido dentro de MaxDistance y publicado a
+// menos de Window.
+func (d *Deduper) MatchBy(a *article.Article) (*article.Article, string) {
+	for _, name := range d.strategies {
+		st := d.counts[name]
+		st.Checked++
+		if m := d.matchOne(name, a); m != nil {
+			st.Matched++
+			return m, name
+		}
+	}
+	return nil, ""
+}
+
+func (d *Deduper) matchOne(strategy string, a *article.Article) *article.Article {
+	switch strategy {
+	case StrategyURL:
+		return d.byExact[a.URL]
+	case StrategyCanonical:
+		return d.byURL[NormalizeURL(a.URL)]
+	case StrategyContentHash:
+		if key := ContentHash(a.Body); key != "" {
+			return d.byContent[key]
+		}
+		return nil
+	case StrategySimHash:
+		return d.matchTitle(a)
+	}
+	return nil
+}
+
+// matchTitle busca el título más parecido dentro de MaxDistance y publicado
+// a menos de Window.
+func (d *Deduper) matchTitle(a *article.Article) *article.Article {
+	tokens := words(a.Title)
+	if len(tokens) < minTitleWords {
+		return nil
+	}
+	hash := simHash(tokens)
+	var best *article.Article
+	bestDist := d.MaxDistance + 1
+	for _, t := range d.titles {
+		if !near(a.Published, t.a.Published, d.Window) {
+			continue
+		}
+		if dist := Distance(hash, t.hash); dist < bestDist {
+			best, bestDist = t.a, dist
+		}
+	}
+	return best
+}
+
+// near indica si dos fechas de publicación están a menos de window; una fecha
+// desconocida no descarta la coincidencia.
+func near(a, b time.Time, window time.Duration) bool {
+	if a.IsZero() || b.IsZero() {
+		return true
+	}
+	diff := a.Sub(b)
+	return diff <= window && diff >= -window
+}
@@ -0,0 +1,99 @@
+package dedup
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// minTitleWords es cuántas palabras significativas necesita un título para
+// compararlo por SimHash: los títulos muy cortos coinciden por azar.
+const minTitleWords = 4
+
+// SimHash calcula la huella de 64 bits de un título: títulos casi iguales
+// (otra puntuación, una palabra cambiada, el nombre del medio al final) dan
+// huellas a pocos bits de distancia. Las características son las palabras y
+// los pares de palabras consecutivas.
+func SimHash(title string) uint64 {
+	return simHash(words(title))
+}
+
+func simHash(tokens []string) uint64 {
+	var weights [64]int
+	add := func(feature string) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		for i := range weights {
+			if sum&(1<<i) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+	for i, t := range tokens {
+		add(t)
+		if i > 0 {
+			add(tokens[i-1] + " " + t)
+		}
+	}
+	var out uint64
+	for i, w := range weights {
+		if w > 0 {
+			out |= 1 << i
+		}
+	}
+	return out
+}
+
+// Distance es la cantidad de bits distintos entre dos huellas.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// words devuelve las palabras significativas del título, en minúscula y sin
+// tildes: se descartan las de menos de 3 letras (artículos, preposiciones) y
+// el nombre del medio que algunas fuentes agregan al final ("... - El Tiempo").
+func words(title string) []string {
+	var out []string
+	for _, w := range strings.FieldsFunc(fold(stripOutlet(title)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) >= 3 || unicode.IsDigit([]rune(w)[0]) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// stripOutlet quita el último tramo tras " - " o " | " si es corto (el nombre
+// del medio) y lo que queda es un título completo.
+func stripOutlet(title string) string {
+	for _, sep := range []string{" | ", " - ", " — "} {
+		i := strings.LastIndex(title, sep)
+		if i < 0 {
+			continue
+		}
+		head, tail := title[:i], title[i+len(sep):]
+		if len(strings.Fields(tail)) <= 4 && len(strings.Fields(head)) >= minTitleWords {
+			return head
+		}
+	}
+	return title
+}
+
+// fold pasa a minúscula y quita tildes ("Bogotá" y "Bogota" son la misma palabra).
+func fold(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	out, _, err := transform.String(t, s)
+	if err != nil {
+		return strings.ToLower(s)
+	}
+	return strings.ToLower(out)
+}
@@ -0,0 +1,69 @@
+package dedup
+
+import (
+	"net/url"
+	"strings"
+)
+
+// hostPrefixes son los subdominios de versión móvil o AMP y www: apuntan a la
+// misma página que el dominio principal.
+var hostPrefixes = []string{"www.", "m.", "mobile.", "amp."}
+
+// trackingParams son parámetros de seguimiento que no cambian la página
+// (además de todos los utm_*).
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "igshid": true,
+	"mc_cid": true, "mc_eid": true, "ocid": true, "cmpid": true, "smid": true,
+	"ref": true, "ref_src": true, "_ga": true,
+}
+
+// NormalizeURL devuelve la clave con la que se comparan URLs: sin esquema,
+// www ni subdominios móviles, sin parámetros de seguimiento (utm_*, fbclid...),
+// fragmento, barra final ni sufijo /amp, y con los parámetros restantes
+// ordenados. No es una URL navegable; si no se puede interpretar se devuelve
+// tal cual.
+func NormalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || !validHost(u) {
+		return raw
+	}
+	host := strings.ToLower(u.Hostname())
+	for trimmed := true; trimmed; {
+		trimmed = false
+		for _, p := range hostPrefixes {
+			if rest, ok := strings.CutPrefix(host, p); ok && strings.Contains(rest, ".") {
+				host, trimmed = rest, true
+			}
+		}
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6
+	}
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+
+	path := strings.TrimRight(u.EscapedPath(), "/")
+	for strings.HasSuffix(path, "/amp") {
+		path = strings.TrimRight(strings.TrimSuffix(path, "/amp"), "/")
+	}
+
+	q := u.Query()
+	for k := range q {
+		if trackingParams[strings.ToLower(k)] || strings.HasPrefix(strings.ToLower(k), "utm_") {
+			q.Del(k)
+		}
+	}
+	out := host + path
+	if len(q) > 0 {
+		out += "?" + q.Encode()
+	}
+	return out
+}
+
+// validHost indica si u tiene un host que se pueda usar como clave: no vacío
+// y, si tiene ':', una IPv6 entre corchetes (ej: no "//::" de una URL rota).
+func validHost(u *url.URL) bool {
+	host := u.Hostname()
+	return host != "" && (!strings.Contains(host, ":") || strings.HasPrefix(u.Host, "["))
+}
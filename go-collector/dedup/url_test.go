@@ -0,0 +1,55 @@
+package dedup
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// FuzzNormalizeURL busca URLs (como las que llegan mal formadas de GDELT)
+// con las que NormalizeURL entra en pánico, deja parámetros de seguimiento o
+// da una clave que cambia al normalizarla de nuevo.
+//
+//	go test ./dedup -fuzz FuzzNormalizeURL
+func FuzzNormalizeURL(f *testing.F) {
+	for _, s := range []string{
+		"https://www.eltiempo.com/colombia/medellin/nota-123?utm_source=twitter&id=4#comentarios",
+		"http://m.elcolombiano.com/antioquia/nota/amp/",
+		"https://amp.www.semana.com:443/nacion/articulo/x/amp/amp",
+		"https://example.com:8080/a?b=1&a=2&FBCLID=x",
+		"//sin-esquema.com/nota",
+		"https://[::1]:8080/a",
+		"http://%41.com/%zz",
+		"https://www./",
+		"no es una url",
+		"",
+	} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, raw string) {
+		key := NormalizeURL(raw)
+		u, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil || !validHost(u) {
+			if key != raw {
+				t.Fatalf("NormalizeURL(%q) = %q, se esperaba la entrada sin cambios", raw, key)
+			}
+			return
+		}
+		if again := NormalizeURL("https://" + key); again != key {
+			t.Fatalf("NormalizeURL(%q) = %q, pero normalizada otra vez da %q", raw, key, again)
+		}
+		path, query, _ := strings.Cut(key, "?")
+		if strings.HasSuffix(path, "/") || strings.HasSuffix(path, "/amp") {
+			t.Fatalf("NormalizeURL(%q) = %q: quedó la barra final o /amp", raw, key)
+		}
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			t.Fatalf("NormalizeURL(%q) = %q: parámetros inválidos: %v", raw, key, err)
+		}
+		for k := range values {
+			if k := strings.ToLower(k); trackingParams[k] || strings.HasPrefix(k, "utm_") {
+				t.Fatalf("NormalizeURL(%q) = %q: quedó el parámetro de seguimiento %s", raw, key, k)
+			}
+		}
+	})
+}
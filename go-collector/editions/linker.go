@@ -0,0 +1,158 @@
+package editions
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/storage"
+)
+
+// Linker enlaza las ediciones de una misma historia (ej: BBC Mundo y BBC News,
+// Guardian US y UK) para que no se cuenten como historias distintas.
+type Linker struct {
+	Store *storage.Store
+
+	// Window es la diferencia máxima de publicación entre dos ediciones.
+	Window time.Duration
+	// Threshold es la similitud mínima de anclas para enlazar sin hreflang.
+	Threshold float64
+}
+
+func NewLinker(store *storage.Store) *Linker {
+	return &Linker{
+		Store:     store,
+		Window:    48 * time.Hour,
+		Threshold: 0.5,
+	}
+}
+
+// LinkDeclared enlaza el artículo con las ediciones que el medio declara en
+// hreflang/canonical y que ya están en el corpus. Devuelve cuántas enlazó.
+func (l *Linker) LinkDeclared(a *article.Article, links *Links) (int, error) {
+	group := []*article.Article{a}
+	for _, u := range links.URLs() {
+		if u == a.URL {
+			continue
+		}
+		other, err := l.Store.GetByURL(u)
+		if err == storage.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		group = append(group, other)
+	}
+	if len(group) == 1 {
+		return 0, nil
+	}
+	return len(group) - 1, l.merge(group)
+}
+
+// LinkSimilar busca, entre los artículos publicados en [from, to], ediciones del
+// mismo medio en otro idioma con alta similitud de anclas (nombres, siglas y
+// cifras del título y resumen), para los medios que no declaran hreflang.
+func (l *Linker) LinkSimilar(from, to time.Time) (int, error) {
+	articles, err := l.Store.ListPublishedBetween(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	// Solo se comparan artículos del mismo medio: sin agruparlos antes, cada
+	// ronda compararía todos los pares de la ventana.
+	byOutlet := make(map[string][]*article.Article)
+	var outlets []string
+	for _, a := range articles {
+		o := outlet(a)
+		if o == "" {
+			continue
+		}
+		if _, ok := byOutlet[o]; !ok {
+			outlets = append(outlets, o)
+		}
+		byOutlet[o] = append(byOutlet[o], a)
+	}
+
+	linked := 0
+	for _, o := range outlets {
+		same := byOutlet[o]
+		for i, a := range same {
+			for _, b := range same[i+1:] {
+				if b.Published.Sub(a.Published) > l.Window {
+					break // ordenados por fecha: los siguientes están aún más lejos
+				}
+				if a.Language == b.Language {
+					continue
+				}
+				if a.EditionGroup != 0 && a.EditionGroup == b.EditionGroup {
+					continue
+				}
+				if Similarity(a.Title+". "+a.Summary, b.Title+". "+b.Summary) < l.Threshold {
+					continue
+				}
+				if err := l.merge([]*article.Article{a, b}); err != nil {
+					return linked, err
+				}
+				linked++
+			}
+		}
+	}
+	return linked, nil
+}
+
+// merge une los artículos (y los grupos a los que ya pertenecían) en un solo
+// grupo identificado por el menor ID.
+func (l *Linker) merge(group []*article.Article) error {
+	target := int64(0)
+	for _, a := range group {
+		for _, id := range []int64{a.ID, a.EditionGroup} {
+			if id != 0 && (target == 0 || id < target) {
+				target = id
+			}
+		}
+	}
+
+	var ids []int64
+	for _, a := range group {
+		if a.EditionGroup != 0 && a.EditionGroup != target {
+			// Los miembros del grupo anterior también pasan al nuevo.
+			if err := l.Store.ReassignEditionGroup(a.EditionGroup, target); err != nil {
+				return err
+			}
+		}
+		a.EditionGroup = target
+		ids = append(ids, a.ID)
+	}
+	if err := l.Store.SetEditionGroup(target, ids...); err != nil {
+		return fmt.Errorf("error enlazando ediciones: %w", err)
+	}
+	return nil
+}
+
+// outlet devuelve el medio del artículo según su dominio registrable: bbc.com,
+// bbc.co.uk y mundo.bbc.com son el mismo medio.
+func outlet(a *article.Article) string {
+	host := a.Domain
+	if host == "" {
+		if u, err := url.Parse(a.URL); err == nil {
+			host = u.Hostname()
+		}
+	}
+	parts := strings.Split(strings.TrimPrefix(strings.ToLower(host), "www."), ".")
+	if len(parts) < 2 {
+		return host
+	}
+	// El nombre del medio es la etiqueta antes del sufijo público (com, co.uk, com.co...).
+	if len(parts) >= 3 && secondLevelSuffixes[parts[len(parts)-2]] && len(parts[len(parts)-1]) == 2 {
+		return parts[len(parts)-3]
+	}
+	return parts[len(parts)-2]
+}
+
+// secondLevelSuffixes son los segundos niveles de los dominios de país (bbc.co.uk, eltiempo.com.co).
+var secondLevelSuffixes = map[string]bool{
+	"co": true, "com": true, "org": true, "net": true, "gov": true, "edu": true, "ac": true,
+}
@@ -0,0 +1,71 @@
+package editions
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Links son las relaciones entre ediciones declaradas en el <head> de la página.
+type Links struct {
+	Canonical  string
+	Alternates map[string]string // hreflang -> URL (ej: "es" -> BBC Mundo)
+}
+
+// ParseLinks lee <link rel="canonical"> y <link rel="alternate" hreflang="...">.
+// Las URLs relativas se resuelven contra pageURL.
+func ParseLinks(pageURL string, html []byte) (*Links, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("error parseando HTML: %w", err)
+	}
+	return DocumentLinks(pageURL, doc), nil
+}
+
+// DocumentLinks es ParseLinks sobre una página ya parseada.
+func DocumentLinks(pageURL string, doc *goquery.Document) *Links {
+	base, _ := url.Parse(pageURL)
+
+	links := &Links{Alternates: make(map[string]string)}
+	doc.Find("link[rel]").Each(func(_ int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		if href == "" {
+			return
+		}
+		href = resolve(base, href)
+
+		rel := strings.ToLower(s.AttrOr("rel", ""))
+		switch {
+		case rel == "canonical":
+			links.Canonical = href
+		case rel == "alternate":
+			if lang := strings.ToLower(s.AttrOr("hreflang", "")); lang != "" && lang != "x-default" {
+				links.Alternates[lang] = href
+			}
+		}
+	})
+	return links
+}
+
+// URLs devuelve todas las URLs relacionadas (canónica y alternativas).
+func (l *Links) URLs() []string {
+	var out []string
+	if l.Canonical != "" {
+		out = append(out, l.Canonical)
+	}
+	for _, u := range l.Alternates {
+		out = append(out, u)
+	}
+	return out
+}
+
+func resolve(base *url.URL, href string) string {
+	ref, err := url.Parse(href)
+	if err != nil || base == nil {
+		return href
+	}
+	return base.ResolveReference(ref).String()
+}
@@ -0,0 +1,74 @@
+package editions
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Similarity compara dos textos que pueden estar en idiomas distintos. En vez de
+// comparar palabras (que cambian con la traducción) compara las "anclas" que
+// sobreviven a la traducción: nombres propios, siglas y cifras.
+// Devuelve el índice de Jaccard entre ambos conjuntos (0 a 1).
+func Similarity(a, b string) float64 {
+	sa, sb := anchors(a), anchors(b)
+	if len(sa) == 0 || len(sb) == 0 {
+		return 0
+	}
+	inter := 0
+	for k := range sa {
+		if sb[k] {
+			inter++
+		}
+	}
+	union := len(sa) + len(sb) - inter
+	return float64(inter) / float64(union)
+}
+
+// anchors extrae nombres propios, siglas y números, sin tildes y en minúscula.
+// La primera palabra de cada oración se ignora porque siempre va en mayúscula.
+func anchors(text string) map[string]bool {
+	out := make(map[string]bool)
+	startOfSentence := true
+	for _, word := range strings.Fields(text) {
+		clean := strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		endsSentence := strings.HasSuffix(word, ".") || strings.HasSuffix(word, ":")
+		if clean == "" {
+			continue
+		}
+
+		first := []rune(clean)[0]
+		switch {
+		case unicode.IsDigit(first):
+			out[clean] = true
+		case unicode.IsUpper(first) && (!startOfSentence || isAcronym(clean)):
+			if len([]rune(clean)) > 1 {
+				out[fold(clean)] = true
+			}
+		}
+		startOfSentence = endsSentence
+	}
+	return out
+}
+
+func isAcronym(word string) bool {
+	for _, r := range word {
+		if unicode.IsLower(r) {
+			return false
+		}
+	}
+	return len(word) > 1
+}
+
+// fold pasa a minúscula y quita tildes ("Bogotá" y "Bogota" son la misma ancla).
+func fold(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	out, _, err := transform.String(t, s)
+	if err != nil {
+		return strings.ToLower(s)
+	}
+	return strings.ToLower(out)
+}
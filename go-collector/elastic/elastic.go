@@ -0,0 +1,334 @@
+// Package elastic indexa los artículos del corpus en Elasticsearch u
+// OpenSearch para explorarlos con Kibana u OpenSearch Dashboards. Usa la API
+// HTTP (_bulk), común a ambos, sin cliente oficial.
+//
+// Cada artículo es un documento cuyo _id sale de su URL: reindexar reemplaza
+// el documento en vez de duplicarlo, y varios corpus (campañas) pueden
+// compartir el índice.
+package elastic
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler"
+)
+
+const (
+	// DefaultIndex es el índice si la configuración no lo define.
+	DefaultIndex = "collector-articles"
+	// defaultBatch es cuántos artículos van en cada pedido _bulk.
+	defaultBatch = 500
+)
+
+// mapping del índice: keyword para lo que se filtra y agrega en los
+// tableros (dominio, sección, fuente, idioma), text para lo que se busca
+// (título, resumen, cuerpo) y date para las fechas. El título guarda además
+// su valor exacto en title.raw para las tablas de los tableros.
+const mapping = `{
+  "mappings": {
+    "properties": {
+      "id":               {"type": "long"},
+      "url":              {"type": "keyword", "ignore_above": 2048},
+      "source":           {"type": "keyword"},
+      "domain":           {"type": "keyword"},
+      "section":          {"type": "keyword"},
+      "language":         {"type": "keyword"},
+      "status":           {"type": "keyword"},
+      "extraction_issue": {"type": "keyword"},
+      "author":           {"type": "keyword", "ignore_above": 256},
+      "title":            {"type": "text", "fields": {"raw": {"type": "keyword", "ignore_above": 512}}},
+      "title_translated": {"type": "text"},
+      "summary":          {"type": "text"},
+      "body":             {"type": "text"},
+      "published":        {"type": "date"},
+      "collected":        {"type": "date"}
+    }
+  }
+}`
+
+// Client indexa en un índice de un clúster.
+type Client struct {
+	HTTP      *http.Client
+	URL       string // URL del clúster, ej: https://localhost:9200
+	IndexName string
+	Username  string
+	Password  string
+	// APIKey, si no está vacía, reemplaza a usuario y contraseña
+	// (Authorization: ApiKey).
+	APIKey string
+	Batch  int
+	// NoAuthor son las fuentes cuyo autor es un dato personal
+	// (storage.encryption.author_sources): no se indexa.
+	NoAuthor map[string]bool
+
+	mu    sync.Mutex
+	ready bool // el índice existe
+}
+
+// NewClient arma el cliente de la configuración, o nil si no hay clúster
+// configurado. Las credenciales se leen de las variables indicadas.
+func NewClient(cfg config.Elasticsearch, privateAuthors []string) (*Client, error) {
+	if cfg.URL == "" {
+		return nil, nil
+	}
+	c := &Client{
+		HTTP:      &http.Client{Timeout: 2 * time.Minute},
+		URL:       strings.TrimSuffix(cfg.URL, "/"),
+		IndexName: cfg.Index,
+		Username:  cfg.Username,
+		Batch:     cfg.BatchSize,
+		NoAuthor:  make(map[string]bool),
+	}
+	if c.IndexName == "" {
+		c.IndexName = DefaultIndex
+	}
+	if c.Batch <= 0 {
+		c.Batch = defaultBatch
+	}
+	for _, src := range privateAuthors {
+		c.NoAuthor[src] = true
+	}
+	if cfg.PasswordEnv != "" {
+		if c.Password = os.Getenv(cfg.PasswordEnv); c.Password == "" {
+			return nil, fmt.Errorf("elasticsearch requiere credencial: defina %s", cfg.PasswordEnv)
+		}
+	}
+	if cfg.APIKeyEnv != "" {
+		if c.APIKey = os.Getenv(cfg.APIKeyEnv); c.APIKey == "" {
+			return nil, fmt.Errorf("elasticsearch requiere credencial: defina %s", cfg.APIKeyEnv)
+		}
+	}
+	return c, nil
+}
+
+// document es un artículo como se indexa.
+type document struct {
+	ID              int64      `json:"id"`
+	URL             string     `json:"url"`
+	Source          string     `json:"source"`
+	Domain          string     `json:"domain,omitempty"`
+	Section         string     `json:"section,omitempty"`
+	Language        string     `json:"language,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	ExtractionIssue string     `json:"extraction_issue,omitempty"`
+	Author          string     `json:"author,omitempty"`
+	Title           string     `json:"title"`
+	TitleTranslated string     `json:"title_translated,omitempty"`
+	Summary         string     `json:"summary,omitempty"`
+	Body            string     `json:"body,omitempty"`
+	Published       *time.Time `json:"published,omitempty"`
+	Collected       *time.Time `json:"collected,omitempty"`
+}
+
+func (c *Client) document(a *article.Article) document {
+	d := document{
+		ID: a.ID, URL: a.URL, Source: a.Source, Domain: a.Domain, Section: a.Section,
+		Language: a.Language, Status: a.Status, ExtractionIssue: a.ExtractionIssue,
+		Title: a.Title, TitleTranslated: a.TitleTranslated, Summary: a.Summary, Body: a.Body,
+	}
+	if !c.NoAuthor[a.Source] {
+		d.Author = a.Author
+	}
+	if !a.Published.IsZero() {
+		t := a.Published.UTC()
+		d.Published = &t
+	}
+	if !a.Collected.IsZero() {
+		t := a.Collected.UTC()
+		d.Collected = &t
+	}
+	return d
+}
+
+// DocID es el _id del artículo en el índice: el hash de su URL, que puede
+// superar el largo máximo de un _id.
+func DocID(articleURL string) string {
+	sum := sha256.Sum256([]byte(articleURL))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Ensure crea el índice con el mapping si no existe. Index lo llama hasta
+// que lo logra; después no vuelve a consultar.
+func (c *Client) Ensure(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ready {
+		return nil
+	}
+	if err := c.ensure(ctx); err != nil {
+		return err
+	}
+	c.ready = true
+	return nil
+}
+
+func (c *Client) ensure(ctx context.Context) error {
+	resp, err := c.do(ctx, "HEAD", "/"+url.PathEscape(c.IndexName), "", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+	default:
+		return fmt.Errorf("error HTTP: status code %d consultando el índice %s", resp.StatusCode, c.IndexName)
+	}
+	resp, err = c.do(ctx, "PUT", "/"+url.PathEscape(c.IndexName), "application/json", strings.NewReader(mapping))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// Otro proceso pudo crearlo entre medio.
+		if body := readError(resp); !strings.Contains(body, "resource_already_exists_exception") {
+			return fmt.Errorf("error creando el índice %s: status code %d: %s", c.IndexName, resp.StatusCode, body)
+		}
+	}
+	return nil
+}
+
+// Delete borra el índice (para recrearlo con el mapping actual). Que no
+// exista no es un error.
+func (c *Client) Delete(ctx context.Context) error {
+	resp, err := c.do(ctx, "DELETE", "/"+url.PathEscape(c.IndexName), "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("error borrando el índice %s: status code %d: %s", c.IndexName, resp.StatusCode, readError(resp))
+	}
+	c.mu.Lock()
+	c.ready = false
+	c.mu.Unlock()
+	return nil
+}
+
+// Index indexa los artículos en pedidos _bulk de Batch artículos y
+// devuelve cuántos quedaron indexados. Un artículo rechazado no detiene a
+// los demás: el error informa cuántos fallaron y el motivo del primero.
+func (c *Client) Index(ctx context.Context, articles []*article.Article) (int, error) {
+	if err := c.Ensure(ctx); err != nil {
+		return 0, err
+	}
+	indexed, failed := 0, 0
+	var first string
+	for start := 0; start < len(articles); start += c.Batch {
+		end := min(start+c.Batch, len(articles))
+		ok, bad, reason, err := c.bulk(ctx, articles[start:end])
+		indexed += ok
+		if err != nil {
+			return indexed, err
+		}
+		if bad > 0 && first == "" {
+			first = reason
+		}
+		failed += bad
+	}
+	if failed > 0 {
+		return indexed, fmt.Errorf("%d artículos rechazados por el índice %s (el primero: %s)", failed, c.IndexName, first)
+	}
+	return indexed, nil
+}
+
+// bulk envía un pedido _bulk y cuenta los documentos aceptados y rechazados.
+func (c *Client) bulk(ctx context.Context, articles []*article.Article) (ok, failed int, reason string, err error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, a := range articles {
+		action := map[string]map[string]string{"index": {"_index": c.IndexName, "_id": DocID(a.URL)}}
+		if err := enc.Encode(action); err != nil {
+			return 0, 0, "", err
+		}
+		if err := enc.Encode(c.document(a)); err != nil {
+			return 0, 0, "", err
+		}
+	}
+	resp, err := c.do(ctx, "POST", "/_bulk", "application/x-ndjson", &buf)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, "", fmt.Errorf("error indexando: status code %d: %s", resp.StatusCode, readError(resp))
+	}
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, 0, "", fmt.Errorf("error leyendo respuesta de _bulk: %w", err)
+	}
+	for _, item := range result.Items {
+		for _, r := range item {
+			if r.Status >= 300 {
+				failed++
+				if reason == "" {
+					reason = r.Error.Type + ": " + r.Error.Reason
+				}
+			} else {
+				ok++
+			}
+		}
+	}
+	return ok, failed, reason, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.URL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	switch {
+	case c.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+c.APIKey)
+	case c.Username != "":
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición a %s: %w", c.URL, err)
+	}
+	return resp, nil
+}
+
+// readError resume el cuerpo de una respuesta de error.
+func readError(resp *http.Response) string {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var e struct {
+		Error struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(data, &e) == nil && e.Error.Type != "" {
+		return e.Error.Type + ": " + e.Error.Reason
+	}
+	return strings.TrimSpace(string(data))
+}
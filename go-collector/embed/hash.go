@@ -0,0 +1,99 @@
+package embed
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// hashDim es la dimensión de los vectores del proveedor hash.
+const hashDim = 256
+
+// HashProvider calcula vectores por "feature hashing" de palabras y trigramas
+// de caracteres. No es un modelo semántico: dos textos se parecen si
+// comparten palabras o raíces, no si dicen lo mismo con otras palabras
+// ("paro" y "huelga" no se acercan). Funciona sin red ni API key y basta
+// para encontrar casi duplicados; para buscar por significado hace falta un
+// modelo (provider openai, que también sirve para un modelo local detrás de
+// un servidor compatible con OpenAI).
+type HashProvider struct{}
+
+func NewHashProvider() *HashProvider {
+	return &HashProvider{}
+}
+
+func (p *HashProvider) Name() string {
+	return "hash:256"
+}
+
+func (p *HashProvider) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i] = hashVector(t)
+	}
+	return out, nil
+}
+
+// Semantic indica si los vectores de p capturan el significado (un modelo)
+// o solo las palabras compartidas (HashProvider).
+func Semantic(p Provider) bool {
+	_, lexical := p.(*HashProvider)
+	return !lexical
+}
+
+func hashVector(text string) []float32 {
+	vec := make([]float32, hashDim)
+	add := func(feature string, weight float32) {
+		h := fnv.New32a()
+		h.Write([]byte(feature))
+		sum := h.Sum32()
+		// El bit alto decide el signo para que las colisiones se compensen.
+		sign := float32(1)
+		if sum&0x80000000 != 0 {
+			sign = -1
+		}
+		vec[sum%hashDim] += sign * weight
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		add("w:"+w, 1)
+		r := []rune(" " + w + " ")
+		for i := 0; i+3 <= len(r); i++ {
+			add("c:"+string(r[i:i+3]), 0.5)
+		}
+	}
+	return Normalize(vec)
+}
+
+// Normalize escala el vector a norma 1, así el coseno es el producto punto.
+func Normalize(vec []float32) []float32 {
+	var sum float64
+	for _, v := range vec {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return vec
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range vec {
+		vec[i] /= norm
+	}
+	return vec
+}
+
+// Cosine calcula la similitud coseno entre dos vectores normalizados.
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
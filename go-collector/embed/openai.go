@@ -0,0 +1,106 @@
+package embed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIProvider usa el endpoint /embeddings de la API de OpenAI o de cualquier
+// servidor compatible (text-embeddings-inference, Ollama, LocalAI...), que es la
+// forma recomendada de usar modelos locales ONNX sin enlazar runtimes en Go.
+type OpenAIProvider struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+	Client  *http.Client
+}
+
+type embeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func NewOpenAIProvider(baseURL, apiKey, model string) *OpenAIProvider {
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	if model == "" {
+		model = "text-embedding-3-small"
+	}
+	return &OpenAIProvider{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		APIKey:  apiKey,
+		Model:   model,
+		Client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}
+}
+
+func (p *OpenAIProvider) Name() string {
+	return "openai:" + p.Model
+}
+
+func (p *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	payload, err := json.Marshal(embeddingsRequest{Model: p.Model, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.BaseURL+"/embeddings", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+
+	var apiResp embeddingsResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("error parseando JSON: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg := string(body)
+		if apiResp.Error != nil {
+			msg = apiResp.Error.Message
+		}
+		return nil, fmt.Errorf("error HTTP: status code %d: %s", resp.StatusCode, msg)
+	}
+	if len(apiResp.Data) != len(texts) {
+		return nil, fmt.Errorf("se pidieron %d embeddings y llegaron %d", len(texts), len(apiResp.Data))
+	}
+
+	out := make([][]float32, len(texts))
+	for _, d := range apiResp.Data {
+		out[d.Index] = Normalize(d.Embedding)
+	}
+	return out, nil
+}
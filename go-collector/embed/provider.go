@@ -0,0 +1,29 @@
+package embed
+
+import (
+	"context"
+	"fmt"
+
+	"go-collector/config"
+)
+
+// Provider calcula vectores para textos: semánticos con un modelo, léxicos
+// con HashProvider (ver Semantic).
+type Provider interface {
+	// Name identifica el modelo; los vectores de modelos distintos no se mezclan.
+	Name() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// NewProvider crea el proveedor configurado.
+func NewProvider(cfg config.Embeddings) (Provider, error) {
+	switch cfg.Provider {
+	case "openai":
+		return NewOpenAIProvider(cfg.BaseURL, cfg.APIKey, cfg.Model), nil
+	case "hash", "local", "":
+		// "local" es el nombre anterior de hash.
+		return NewHashProvider(), nil
+	default:
+		return nil, fmt.Errorf("proveedor de embeddings desconocido: %s (use openai o hash)", cfg.Provider)
+	}
+}
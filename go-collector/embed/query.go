@@ -0,0 +1,46 @@
+package embed
+
+import (
+	"context"
+	"fmt"
+
+	"go-collector/article"
+	"go-collector/storage"
+)
+
+// Result es un artículo encontrado por similitud de vectores.
+type Result struct {
+	Article *article.Article
+	Score   float64
+}
+
+// Search calcula el vector de la consulta con el mismo proveedor usado para el
+// corpus y devuelve los k artículos más cercanos (omitiendo los retirados).
+func Search(ctx context.Context, store *storage.Store, provider Provider, text string, k int) ([]Result, error) {
+	vecs, err := provider.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, fmt.Errorf("error calculando vector de la consulta: %w", err)
+	}
+
+	// Se piden algunos de más por si hay artículos retirados entre los vecinos.
+	matches, err := Nearest(store, provider.Name(), vecs[0], k*2)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Result
+	for _, m := range matches {
+		a, err := store.GetByID(m.ArticleID)
+		if err != nil {
+			return nil, err
+		}
+		if a.Withdrawn() {
+			continue
+		}
+		out = append(out, Result{Article: a, Score: m.Score})
+		if len(out) == k {
+			break
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,150 @@
+package embed
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"go-collector/storage"
+)
+
+// Match es un artículo cercano a un vector de consulta.
+type Match struct {
+	ArticleID int64
+	Score     float64
+}
+
+// Pair son dos artículos casi duplicados según sus vectores.
+type Pair struct {
+	A, B  int64
+	Score float64
+}
+
+// Nearest devuelve los k artículos más similares al vector dado. Es una
+// búsqueda exacta que recorre todos los vectores del modelo (no hay índice
+// vectorial): alcanza para corpus de decenas de miles de artículos.
+func Nearest(store *storage.Store, model string, query []float32, k int) ([]Match, error) {
+	var matches []Match
+	err := store.EachEmbedding(model, func(id int64, vec []float32) error {
+		matches = append(matches, Match{ArticleID: id, Score: Cosine(query, vec)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if k > 0 && len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches, nil
+}
+
+// Parámetros de LSH para NearDuplicates: cada banda es una firma de
+// lshBits hiperplanos aleatorios; dos vectores son candidatos si coinciden
+// en alguna banda.
+const (
+	lshBits     = 8
+	lshMaxBands = 64
+	lshRecall   = 0.99 // probabilidad buscada de que un par en el umbral sea candidato
+	lshSeed     = 1
+)
+
+// NearDuplicates devuelve los pares de vectores con similitud >= threshold
+// (deduplicación por similitud), del más parecido al menos. En vez de
+// comparar todos los pares, agrupa los vectores con LSH de hiperplanos
+// aleatorios y compara solo los que caen juntos en alguna banda; la
+// similitud de cada candidato es exacta. Es aproximada en lo que encuentra:
+// con umbrales de 0,7 o más se pierde menos del 1% de los pares en el
+// umbral (y menos aún de los más parecidos); con umbrales bajos, algo más.
+// Los vectores del modelo se cargan en memoria.
+func NearDuplicates(store *storage.Store, model string, threshold float64) ([]Pair, error) {
+	var (
+		ids  []int64
+		vecs [][]float32
+	)
+	err := store.EachEmbedding(model, func(id int64, vec []float32) error {
+		ids = append(ids, id)
+		vecs = append(vecs, vec)
+		return nil
+	})
+	if err != nil || len(vecs) < 2 {
+		return nil, err
+	}
+
+	bands := lshBands(threshold)
+	planes := hyperplanes(bands*lshBits, len(vecs[0]))
+	buckets := make(map[uint32][]int)
+	for i, v := range vecs {
+		if len(v) != len(vecs[0]) {
+			continue
+		}
+		for b := range bands {
+			sig := uint32(b) << lshBits
+			for bit := range lshBits {
+				if dot(planes[b*lshBits+bit], v) >= 0 {
+					sig |= 1 << bit
+				}
+			}
+			buckets[sig] = append(buckets[sig], i)
+		}
+	}
+
+	seen := make(map[[2]int]bool)
+	var pairs []Pair
+	for _, bucket := range buckets {
+		for x := range bucket {
+			for y := x + 1; y < len(bucket); y++ {
+				i, j := bucket[x], bucket[y]
+				if seen[[2]int{i, j}] {
+					continue
+				}
+				seen[[2]int{i, j}] = true
+				if score := Cosine(vecs[i], vecs[j]); score >= threshold {
+					pairs = append(pairs, Pair{A: ids[i], B: ids[j], Score: score})
+				}
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Score != pairs[j].Score {
+			return pairs[i].Score > pairs[j].Score
+		}
+		return pairs[i].A < pairs[j].A || pairs[i].A == pairs[j].A && pairs[i].B < pairs[j].B
+	})
+	return pairs, nil
+}
+
+// lshBands es cuántas bandas hacen falta para que un par con similitud
+// threshold sea candidato con probabilidad lshRecall: un hiperplano
+// aleatorio separa dos vectores con probabilidad ángulo/π.
+func lshBands(threshold float64) int {
+	p := 1 - math.Acos(max(-1, min(1, threshold)))/math.Pi
+	band := math.Pow(p, lshBits)
+	if band >= 1 {
+		return 1
+	}
+	n := int(math.Ceil(math.Log(1-lshRecall) / math.Log(1-band)))
+	return max(1, min(n, lshMaxBands))
+}
+
+// hyperplanes son n vectores aleatorios de dimensión dim, siempre los mismos
+// (semilla fija), para que el resultado sea reproducible.
+func hyperplanes(n, dim int) [][]float32 {
+	rng := rand.New(rand.NewSource(lshSeed))
+	out := make([][]float32, n)
+	for i := range out {
+		out[i] = make([]float32, dim)
+		for j := range out[i] {
+			out[i][j] = float32(rng.NormFloat64())
+		}
+	}
+	return out
+}
+
+func dot(a, b []float32) float32 {
+	var s float32
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package embed
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"go-collector/article"
+	"go-collector/storage"
+)
+
+// NearDuplicates encuentra, comparando solo los candidatos de LSH, los
+// mismos pares que la comparación de todos contra todos.
+func TestNearDuplicates(t *testing.T) {
+	store, err := storage.Open(t.TempDir() + "/corpus.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	const dim, n, model = 64, 400, "prueba"
+	rng := rand.New(rand.NewSource(7))
+	random := func() []float32 {
+		v := make([]float32, dim)
+		for i := range v {
+			v[i] = float32(rng.NormFloat64())
+		}
+		return v
+	}
+	var ids []int64
+	var vecs [][]float32
+	for i := range n {
+		v := random()
+		if i%10 == 1 {
+			// Casi duplicado del anterior: el mismo vector con algo de ruido.
+			v = append([]float32(nil), vecs[i-1]...)
+			noise := random()
+			for j := range v {
+				v[j] += 0.04 * noise[j]
+			}
+		}
+		v = Normalize(v)
+		a := &article.Article{Source: "rss", URL: fmt.Sprintf("https://www.eltiempo.com/nota-%d", i), Title: "Nota", Published: time.Now()}
+		if err := store.SaveArticle(a); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SaveEmbedding(a.ID, model, v); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, a.ID)
+		vecs = append(vecs, v)
+	}
+
+	const threshold = 0.9
+	want := map[[2]int64]bool{}
+	for i := range vecs {
+		for j := i + 1; j < len(vecs); j++ {
+			if Cosine(vecs[i], vecs[j]) >= threshold {
+				want[[2]int64{ids[i], ids[j]}] = true
+			}
+		}
+	}
+	if len(want) < n/10 {
+		t.Fatalf("solo %d pares sobre el umbral; el caso no prueba nada", len(want))
+	}
+
+	pairs, err := NearDuplicates(store, model, threshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range pairs {
+		if !want[[2]int64{p.A, p.B}] {
+			t.Errorf("par %d ~ %d (%.3f) no supera el umbral", p.A, p.B, p.Score)
+		}
+	}
+	if len(pairs) != len(want) {
+		t.Errorf("%d pares, se esperaban %d", len(pairs), len(want))
+	}
+}
@@ -0,0 +1,73 @@
+package embed
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-collector/article"
+	"go-collector/storage"
+)
+
+// defaultBatchSize es la cantidad de textos enviados por llamada al proveedor.
+const defaultBatchSize = 64
+
+// Stage es la etapa de enriquecimiento que calcula los vectores de los artículos
+// que aún no los tienen.
+type Stage struct {
+	Provider  Provider
+	Store     *storage.Store
+	BatchSize int
+}
+
+func NewStage(provider Provider, store *storage.Store, batchSize int) *Stage {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return &Stage{Provider: provider, Store: store, BatchSize: batchSize}
+}
+
+// Run procesa todos los artículos pendientes y devuelve cuántos vectores guardó.
+func (s *Stage) Run(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		batch, err := s.Store.ArticlesWithoutEmbedding(s.Provider.Name(), s.BatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(batch) == 0 {
+			return total, nil
+		}
+
+		texts := make([]string, len(batch))
+		for i, a := range batch {
+			texts[i] = Text(a)
+		}
+		vectors, err := s.Provider.Embed(ctx, texts)
+		if err != nil {
+			return total, fmt.Errorf("error calculando embeddings: %w", err)
+		}
+		for i, a := range batch {
+			if err := s.Store.SaveEmbedding(a.ID, s.Provider.Name(), vectors[i]); err != nil {
+				return total, err
+			}
+		}
+		total += len(batch)
+	}
+}
+
+// Text es el texto que representa al artículo: título y resumen (o el inicio del
+// cuerpo si no hay resumen).
+func Text(a *article.Article) string {
+	summary := a.Summary
+	if summary == "" {
+		summary = a.Body
+	}
+	if r := []rune(summary); len(r) > 1000 {
+		summary = string(r[:1000])
+	}
+	return strings.TrimSpace(a.Title + ". " + summary)
+}
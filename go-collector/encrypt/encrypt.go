@@ -0,0 +1,144 @@
+// Package encrypt cifra en reposo el archivo de páginas crudas y los campos
+// sensibles del corpus (los IDs de autor de tuits) con AES-256-GCM, según el
+// plan de manejo de datos aprobado por el comité de ética.
+package encrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-collector/config"
+)
+
+// KeySize es el largo de la clave en bytes (AES-256).
+const KeySize = 32
+
+// magic marca los datos binarios cifrados; fieldPrefix los campos de texto.
+var magic = []byte("CENC1")
+
+const fieldPrefix = "enc:v1:"
+
+// ErrNoKey indica que hay datos cifrados pero no se configuró la clave.
+var ErrNoKey = errors.New("los datos están cifrados y no se configuró la clave (storage.encryption)")
+
+// Cipher cifra y descifra con una clave fija.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+func New(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("la clave de cifrado debe tener %d bytes, tiene %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// FromConfig carga la clave desde la variable de entorno o el archivo
+// configurados (en base64). Devuelve nil sin error si el cifrado no está activo.
+func FromConfig(cfg config.Encryption) (*Cipher, error) {
+	var encoded string
+	switch {
+	case cfg.KeyEnv != "":
+		encoded = os.Getenv(cfg.KeyEnv)
+		if encoded == "" {
+			return nil, fmt.Errorf("la variable %s con la clave de cifrado está vacía", cfg.KeyEnv)
+		}
+	case cfg.KeyFile != "":
+		data, err := os.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo clave de cifrado: %w", err)
+		}
+		encoded = string(data)
+	default:
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("la clave de cifrado no es base64 válido: %w", err)
+	}
+	return New(key)
+}
+
+// GenerateKey devuelve una clave nueva en base64, lista para guardar en un archivo.
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Encrypt devuelve magic + nonce + texto cifrado.
+func (c *Cipher) Encrypt(plain []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append(append([]byte{}, magic...), nonce...)
+	return c.aead.Seal(out, nonce, plain, magic), nil
+}
+
+// Decrypt descifra lo producido por Encrypt.
+func (c *Cipher) Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, errors.New("los datos no están cifrados")
+	}
+	data = data[len(magic):]
+	ns := c.aead.NonceSize()
+	if len(data) < ns {
+		return nil, errors.New("datos cifrados truncados")
+	}
+	plain, err := c.aead.Open(nil, data[:ns], data[ns:], magic)
+	if err != nil {
+		return nil, fmt.Errorf("error descifrando (¿clave equivocada?): %w", err)
+	}
+	return plain, nil
+}
+
+// IsEncrypted indica si data fue producido por Encrypt.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// EncryptString cifra un campo de texto; el resultado es texto imprimible.
+func (c *Cipher) EncryptString(s string) (string, error) {
+	if s == "" || strings.HasPrefix(s, fieldPrefix) {
+		return s, nil
+	}
+	data, err := c.Encrypt([]byte(s))
+	if err != nil {
+		return "", err
+	}
+	return fieldPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DecryptString descifra un campo; los campos sin cifrar se devuelven tal cual.
+func (c *Cipher) DecryptString(s string) (string, error) {
+	if !strings.HasPrefix(s, fieldPrefix) {
+		return s, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, fieldPrefix))
+	if err != nil {
+		return "", fmt.Errorf("campo cifrado inválido: %w", err)
+	}
+	plain, err := c.Decrypt(data)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,248 @@
+// Package entities extrae de los artículos las personas, organizaciones y
+// lugares que mencionan, y sus palabras clave frente al resto del corpus.
+//
+// Es un análisis liviano, sin modelos: las entidades son secuencias de
+// palabras con mayúscula inicial (admitiendo conectores como "de" en
+// "Universidad de Antioquia"), clasificadas por palabras indicativas y un
+// nomenclátor corto; las palabras clave salen de TF-IDF (ver Corpus). Alcanza
+// para ver qué nombres dominan el corpus, no para anotar texto.
+package entities
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-collector/lang"
+)
+
+// Tipos de entidad.
+const (
+	Person       = "person"
+	Organization = "organization"
+	Place        = "place"
+	Keyword      = "keyword"
+)
+
+// Kinds son los tipos de entidad nombrada, en el orden en que se informan.
+var Kinds = []string{Person, Organization, Place}
+
+// maxEntities es cuántas entidades se guardan por artículo.
+const maxEntities = 10
+
+// Entity es una entidad o palabra clave de un artículo. Score es el peso en
+// el artículo: menciones (las del título valen doble) o TF-IDF.
+type Entity struct {
+	Name  string  `json:"name"`
+	Kind  string  `json:"kind"`
+	Score float64 `json:"score"`
+}
+
+// connectors pueden ir dentro de un nombre propio, nunca al principio ni al
+// final.
+var connectors = map[string]bool{"de": true, "del": true, "la": true, "las": true, "los": true, "of": true, "the": true, "do": true, "da": true, "dos": true}
+
+// orgWords delatan una organización.
+var orgWords = wordSet(`universidad university ministerio ministry gobierno government partido party banco bank
+	corte court congreso congress senado senate cámara fiscalía procuraduría contraloría registraduría policía
+	police ejército army fuerzas fundación foundation corporación corporation compañía company empresa inc ltd
+	s.a. s.a.s. asociación association instituto institute comisión commission consejo council alcaldía
+	gobernación secretaría agencia agency sindicato union federación federation facultad college school colegio
+	hospital clínica tribunal organización organization naciones nations club grupo group`)
+
+// places es un nomenclátor mínimo para los lugares sin otra pista.
+var places = wordSet(`colombia bogotá medellín cali barranquilla cartagena bucaramanga pereira manizales antioquia
+	cundinamarca chocó caldas risaralda santander boyacá nariño cauca valle venezuela ecuador perú peru brasil brazil
+	méxico mexico argentina chile bolivia paraguay uruguay panamá panama cuba haití haiti caribe españa spain europa
+	europe francia france alemania germany italia italy portugal china japón japan rusia russia ucrania ukraine israel
+	gaza irán iran áfrica africa asia londres london parís paris madrid washington miami latinoamérica`)
+
+// placeWords preceden a un lugar ("en Medellín", "in London").
+var placeWords = wordSet(`en in desde from hacia to a`)
+
+func wordSet(s string) map[string]bool {
+	m := map[string]bool{}
+	for _, w := range strings.Fields(s) {
+		m[w] = true
+	}
+	return m
+}
+
+// Extract devuelve las entidades nombradas del artículo, de mayor a menor
+// peso. Las del título cuentan doble.
+func Extract(title, text string) []Entity {
+	type mention struct {
+		names map[string]int // forma -> veces, para guardar la más usada
+		kind  string
+		score float64
+	}
+	found := map[string]*mention{}
+	add := func(s string, weight float64) {
+		for _, c := range candidates(s) {
+			key := strings.ToLower(c.name)
+			m := found[key]
+			if m == nil {
+				m = &mention{names: map[string]int{}}
+				found[key] = m
+			}
+			m.names[c.name]++
+			m.score += weight
+			if c.kind != "" && (m.kind == "" || c.kind != Person && m.kind == Person) {
+				m.kind = c.kind
+			}
+		}
+	}
+	add(title, 2)
+	add(text, 1)
+
+	out := make([]Entity, 0, len(found))
+	for _, m := range found {
+		if m.kind == "" {
+			continue
+		}
+		name, best := "", 0
+		for n, count := range m.names {
+			if count > best || count == best && n < name {
+				name, best = n, count
+			}
+		}
+		out = append(out, Entity{Name: name, Kind: m.kind, Score: m.score})
+	}
+	sortEntities(out)
+	if len(out) > maxEntities {
+		out = out[:maxEntities]
+	}
+	return out
+}
+
+// candidate es una secuencia de palabras con mayúscula y su tipo ("" si no
+// se pudo decidir).
+type candidate struct {
+	name, kind string
+}
+
+// candidates recorre el texto oración por oración buscando nombres propios.
+func candidates(text string) []candidate {
+	var out []candidate
+	for _, sentence := range strings.FieldsFunc(text, func(r rune) bool {
+		return r == '.' || r == '!' || r == '?' || r == '\n' || r == '|'
+	}) {
+		words := strings.Fields(sentence)
+		for i := 0; i < len(words); {
+			w := clean(words[i])
+			if !capitalized(w) {
+				i++
+				continue
+			}
+			run := []string{w}
+			j := i + 1
+			for j < len(words) && !breaks(words[j-1]) && !tagged(words[j]) {
+				next := clean(words[j])
+				if capitalized(next) {
+					run = append(run, next)
+					j++
+					continue
+				}
+				if connectors[strings.ToLower(next)] && j+1 < len(words) && !breaks(words[j]) && !tagged(words[j+1]) && capitalized(clean(words[j+1])) {
+					run = append(run, next, clean(words[j+1]))
+					j += 2
+					continue
+				}
+				break
+			}
+			// Las palabras funcionales con mayúscula (inicio de oración) no
+			// son parte del nombre.
+			for len(run) > 0 && lang.IsStopword(run[0]) {
+				run, i = run[1:], i+1
+			}
+			if len(run) > 0 && (i > 0 || len(run) > 1 || acronym(run[0]) || places[strings.ToLower(run[0])]) {
+				prev := ""
+				if i > 0 {
+					prev = strings.ToLower(clean(words[i-1]))
+				}
+				out = append(out, candidate{name: strings.Join(run, " "), kind: classify(run, prev)})
+			}
+			i = j
+		}
+	}
+	return out
+}
+
+// classify decide el tipo de un nombre por sus palabras y la anterior.
+func classify(run []string, prev string) string {
+	for _, w := range run {
+		if orgWords[strings.ToLower(w)] {
+			return Organization
+		}
+	}
+	if len(run) == 1 && acronym(run[0]) {
+		return Organization
+	}
+	if places[strings.ToLower(strings.Join(run, " "))] || places[strings.ToLower(run[0])] && len(run) == 1 {
+		return Place
+	}
+	if placeWords[prev] && len(run) <= 2 {
+		return Place
+	}
+	if len(run) >= 2 && len(run) <= 4 {
+		for _, w := range run {
+			if connectors[strings.ToLower(w)] {
+				return ""
+			}
+		}
+		return Person
+	}
+	return ""
+}
+
+// clean quita la puntuación de los bordes y el posesivo inglés.
+func clean(w string) string {
+	w = strings.TrimFunc(w, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, suffix := range []string{"'s", "’s"} {
+		w = strings.TrimSuffix(w, suffix)
+	}
+	return w
+}
+
+// breaks indica si la palabra cierra un nombre: termina en una pausa (coma,
+// dos puntos) o es un posesivo ("Colombia's University").
+func breaks(raw string) bool {
+	if strings.HasSuffix(raw, "'s") || strings.HasSuffix(raw, "’s") {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(raw)
+	return r == ',' || r == ';' || r == ':' || r == ')' || r == '"' || r == '”'
+}
+
+// tagged indica si la palabra es un hashtag o una mención: va sola.
+func tagged(raw string) bool {
+	return strings.HasPrefix(raw, "#") || strings.HasPrefix(raw, "@")
+}
+
+func capitalized(w string) bool {
+	r, _ := utf8.DecodeRuneInString(w)
+	return unicode.IsUpper(r) && utf8.RuneCountInString(w) > 1
+}
+
+// acronym reconoce siglas como ONU, FARC o UdeA: más de una mayúscula.
+func acronym(w string) bool {
+	upper := 0
+	for _, r := range w {
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+	return upper >= 2 && utf8.RuneCountInString(w) <= 8
+}
+
+func sortEntities(es []Entity) {
+	sort.Slice(es, func(i, j int) bool {
+		if es[i].Score != es[j].Score {
+			return es[i].Score > es[j].Score
+		}
+		return es[i].Name < es[j].Name
+	})
+}
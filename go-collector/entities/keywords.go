@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-collector/lang"
+)
+
+// maxKeywords es cuántas palabras clave se guardan por artículo.
+const maxKeywords = 5
+
+// Corpus acumula en cuántos documentos aparece cada término, para pesar las
+// palabras de un artículo por lo poco comunes que son en el resto (TF-IDF).
+type Corpus struct {
+	docs int
+	df   map[string]int
+}
+
+// NewCorpus crea un corpus vacío.
+func NewCorpus() *Corpus {
+	return &Corpus{df: map[string]int{}}
+}
+
+// Add suma un documento al corpus.
+func (c *Corpus) Add(text string) {
+	c.docs++
+	seen := map[string]bool{}
+	for _, t := range Terms(text) {
+		if !seen[t] {
+			seen[t] = true
+			c.df[t]++
+		}
+	}
+}
+
+// Keywords devuelve las palabras clave del texto: las de mayor TF-IDF.
+func (c *Corpus) Keywords(text string) []Entity {
+	tf := map[string]int{}
+	for _, t := range Terms(text) {
+		tf[t]++
+	}
+	out := make([]Entity, 0, len(tf))
+	for t, n := range tf {
+		idf := math.Log(float64(c.docs+1) / float64(c.df[t]+1))
+		if idf <= 0 {
+			continue
+		}
+		out = append(out, Entity{Name: t, Kind: Keyword, Score: math.Round(float64(n)*idf*1000) / 1000})
+	}
+	sortEntities(out)
+	if len(out) > maxKeywords {
+		out = out[:maxKeywords]
+	}
+	return out
+}
+
+// Terms son las palabras del texto que pueden ser clave: en minúsculas, sin
+// palabras funcionales, números ni palabras de menos de 4 letras.
+func Terms(text string) []string {
+	var out []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if utf8.RuneCountInString(w) < 4 || lang.IsStopword(w) || strings.IndexFunc(w, unicode.IsLetter) < 0 {
+			continue
+		}
+		out = append(out, w)
+	}
+	return out
+}
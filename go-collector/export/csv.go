@@ -0,0 +1,106 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-collector/article"
+)
+
+// columns son las columnas disponibles para CSV y cómo se obtiene cada una.
+var columns = map[string]func(a *article.Article) string{
+	"id":               func(a *article.Article) string { return strconv.FormatInt(a.ID, 10) },
+	"source":           func(a *article.Article) string { return a.Source },
+	"url":              func(a *article.Article) string { return a.URL },
+	"title":            func(a *article.Article) string { return a.Title },
+	"title_translated": func(a *article.Article) string { return a.TitleTranslated },
+	"author":           func(a *article.Article) string { return a.Author },
+	"domain":           func(a *article.Article) string { return a.Domain },
+	"language":         func(a *article.Article) string { return a.Language },
+	"section":          func(a *article.Article) string { return a.Section },
+	"summary":          func(a *article.Article) string { return a.Summary },
+	"body":             func(a *article.Article) string { return a.Body },
+	"published":        func(a *article.Article) string { return formatTime(a.Published) },
+	"collected":        func(a *article.Article) string { return formatTime(a.Collected) },
+	"status":           func(a *article.Article) string { return a.Status },
+	"withdrawn_reason": func(a *article.Article) string { return a.WithdrawnReason },
+	"edition_group": func(a *article.Article) string {
+		if a.EditionGroup == 0 {
+			return ""
+		}
+		return strconv.FormatInt(a.EditionGroup, 10)
+	},
+	"relevance_score": func(a *article.Article) string {
+		if a.Explanation == nil {
+			return ""
+		}
+		return strconv.FormatFloat(a.Explanation.Score, 'f', -1, 64)
+	},
+}
+
+// DefaultColumns son las columnas del CSV si no se eligen otras: todo menos
+// el cuerpo, que suele ser demasiado largo para una planilla.
+var DefaultColumns = []string{"id", "source", "published", "language", "domain", "title", "author", "url"}
+
+// Columns devuelve las columnas disponibles para CSV.
+func Columns() []string {
+	out := make([]string, 0, len(columns))
+	for name := range columns {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// CSV escribe una fila por artículo, con encabezado.
+type CSV struct {
+	w      *csv.Writer
+	fields []func(a *article.Article) string
+}
+
+// NewCSV crea un writer CSV con las columnas de opts; el encabezado se
+// escribe de inmediato.
+func NewCSV(w io.Writer, opts Options) (Writer, error) {
+	names := opts.Columns
+	if len(names) == 0 {
+		names = DefaultColumns
+	}
+	c := &CSV{w: csv.NewWriter(w)}
+	for _, name := range names {
+		f, ok := columns[name]
+		if !ok {
+			return nil, fmt.Errorf("columna desconocida: %s (use %s)", name, strings.Join(Columns(), ", "))
+		}
+		c.fields = append(c.fields, f)
+	}
+	if err := c.w.Write(names); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *CSV) Write(a *article.Article) error {
+	row := make([]string, len(c.fields))
+	for i, f := range c.fields {
+		row[i] = f(a)
+	}
+	return c.w.Write(row)
+}
+
+func (c *CSV) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// formatTime usa RFC 3339, que pandas y las planillas reconocen; vacío si no hay fecha.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,74 @@
+// Package export escribe artículos en formatos para otras herramientas
+// (pandas, Excel, jq): JSON Lines, CSV con columnas a elección y JSON
+// indentado. Los writers escriben artículo por artículo, sin acumular el
+// corpus en memoria.
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-collector/article"
+)
+
+// Writer escribe artículos uno a uno. Close completa el formato (ej: cierra
+// el arreglo JSON o vacía el buffer del CSV); no cierra el io.Writer de
+// destino.
+type Writer interface {
+	Write(a *article.Article) error
+	Close() error
+}
+
+// Options ajusta los writers.
+type Options struct {
+	// Columns son las columnas del CSV, en orden (por defecto DefaultColumns).
+	// Los demás formatos las ignoran: escriben el artículo completo.
+	Columns []string
+}
+
+// Factory crea un writer sobre w.
+type Factory func(w io.Writer, opts Options) (Writer, error)
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{}
+)
+
+func init() {
+	Register("jsonl", NewJSONL)
+	Register("csv", NewCSV)
+	Register("json", NewJSON)
+}
+
+// Register agrega (o reemplaza) un formato.
+func Register(format string, f Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	factories[format] = f
+}
+
+// Formats devuelve los formatos registrados, en orden alfabético.
+func Formats() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]string, 0, len(factories))
+	for name := range factories {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// New crea el writer del formato pedido.
+func New(format string, w io.Writer, opts Options) (Writer, error) {
+	mu.RLock()
+	f, ok := factories[format]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("formato desconocido: %s (use %s)", format, strings.Join(Formats(), ", "))
+	}
+	return f(w, opts)
+}
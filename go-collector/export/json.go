@@ -0,0 +1,62 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"go-collector/article"
+)
+
+// JSONL escribe un artículo por línea (JSON Lines).
+type JSONL struct {
+	enc *json.Encoder
+}
+
+// NewJSONL crea un writer JSON Lines.
+func NewJSONL(w io.Writer, _ Options) (Writer, error) {
+	return &JSONL{enc: json.NewEncoder(w)}, nil
+}
+
+func (j *JSONL) Write(a *article.Article) error {
+	return j.enc.Encode(a)
+}
+
+func (j *JSONL) Close() error { return nil }
+
+// JSON escribe un arreglo JSON indentado, legible a mano. Los artículos se
+// escriben a medida que llegan: el arreglo queda completo al llamar a Close.
+type JSON struct {
+	w     io.Writer
+	count int
+}
+
+// NewJSON crea un writer de JSON indentado.
+func NewJSON(w io.Writer, _ Options) (Writer, error) {
+	return &JSON{w: w}, nil
+}
+
+func (j *JSON) Write(a *article.Article) error {
+	data, err := json.MarshalIndent(a, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	sep := ",\n  "
+	if j.count == 0 {
+		sep = "[\n  "
+	}
+	j.count++
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *JSON) Close() error {
+	end := "\n]\n"
+	if j.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
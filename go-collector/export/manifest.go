@@ -0,0 +1,45 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"go-collector/buildinfo"
+)
+
+// Manifest acompaña a una exportación a archivo (en <archivo>.manifest.json):
+// los formatos planos no tienen dónde llevar de qué recolector y con qué
+// filtros salieron los datos.
+type Manifest struct {
+	File      string          `json:"file"`
+	Format    string          `json:"format"`
+	Created   time.Time       `json:"created"`
+	Articles  int             `json:"articles"`
+	Filter    []string        `json:"filter,omitempty"` // flags de filtro usados, ej: "--lang=es"
+	Collector buildinfo.Stamp `json:"collector"`
+
+	// Excluded y Redacted son los artículos que quedaron fuera y los que
+	// salieron sin texto completo por los términos de uso de su fuente o
+	// medio (config publishers).
+	Excluded int `json:"excluded,omitempty"`
+	Redacted int `json:"redacted,omitempty"`
+}
+
+// ManifestPath es el archivo del manifiesto de la exportación path.
+func ManifestPath(path string) string {
+	return path + ".manifest.json"
+}
+
+// WriteManifest escribe el manifiesto junto al archivo exportado.
+func WriteManifest(m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(ManifestPath(m.File), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error escribiendo manifiesto: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+package extract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"go-collector/dates"
+)
+
+// Estrategias de extracción, en el orden en que se prueban.
+const (
+	StrategyRule        = "rule"        // la regla del dominio (config extract.domains)
+	StrategyStructured  = "structured"  // articleBody de los datos estructurados (JSON-LD)
+	StrategyReadability = "readability" // los párrafos largos de <article> o la página
+	StrategyDescription = "description" // solo la descripción de la página (último recurso)
+)
+
+// Umbrales de calidad. Una extracción con al menos MinScore se acepta sin
+// probar las estrategias siguientes; si ninguna llega, gana la de mayor
+// puntaje.
+const (
+	MinScore = 0.6
+	// expectedLength es el largo de una nota completa: con menos texto el
+	// puntaje baja en proporción.
+	expectedLength = 1500
+)
+
+// Pesos de cada componente del puntaje (suman 1).
+const (
+	weightLength      = 0.5
+	weightBoilerplate = 0.3
+	weightDate        = 0.1
+	weightAuthor      = 0.1
+)
+
+// boilerplate son frases de los bloques que no son la nota (publicidad,
+// suscripciones, créditos).
+var boilerplate = []string{
+	"lea también", "le puede interesar", "suscríbete", "suscribirse", "newsletter", "boletín",
+	"publicidad", "cookies", "derechos reservados", "all rights reserved", "©", "read more",
+	"subscribe", "sign up", "advertisement", "síguenos", "follow us", "comparte", "share this",
+}
+
+// Result es lo extraído de una página. Published queda en cero si la página
+// no tiene una fecha interpretable.
+type Result struct {
+	Text      string    `json:"text"`
+	Author    string    `json:"author,omitempty"`
+	Published time.Time `json:"published"`
+	// Rule es el dominio de la regla que se probó ("" si no hay).
+	Rule string `json:"rule,omitempty"`
+	// Strategy es la estrategia ganadora y Quality su evaluación.
+	Strategy string  `json:"strategy"`
+	Quality  Quality `json:"quality"`
+	// Attempts son todas las estrategias probadas, en orden, para depurar
+	// por qué ganó una y no otra.
+	Attempts []Attempt `json:"attempts"`
+}
+
+// Attempt es el resultado de una estrategia.
+type Attempt struct {
+	Strategy string  `json:"strategy"`
+	Length   int     `json:"length"`
+	Score    float64 `json:"score"`
+}
+
+// Quality es la evaluación de una extracción: Score combina el largo del
+// texto frente al de una nota completa, la proporción de párrafos que son
+// relleno y si se encontraron fecha y autor.
+type Quality struct {
+	Score       float64 `json:"score"`
+	Length      int     `json:"length"`
+	Boilerplate float64 `json:"boilerplate"`
+	HasDate     bool    `json:"has_date"`
+	HasAuthor   bool    `json:"has_author"`
+}
+
+// candidate es lo que propone una estrategia.
+type candidate struct {
+	strategy  string
+	text      string
+	author    string
+	published time.Time
+}
+
+// Extract extrae la página descargada de pageURL probando las estrategias en
+// orden: la regla del dominio (si hay, porque se escribió para ese medio),
+// los datos estructurados, la extracción genérica y, como último recurso, la
+// descripción. Gana la primera que llega a MinScore o, si ninguna, la de
+// mayor puntaje. El autor y la fecha que una estrategia no encuentra se
+// completan con los metadatos de la página.
+func (e *Extractor) Extract(pageURL string, html []byte) (*Result, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("error parseando HTML: %w", err)
+	}
+	res := &Result{}
+	var rule Rule
+	if u, err := url.Parse(pageURL); err == nil {
+		res.Rule, rule = e.match(u.Hostname())
+	}
+
+	// Lo que está en el encabezado y en los scripts se lee antes de
+	// quitarlos.
+	// El autor y la fecha de la regla valen para cualquier estrategia.
+	ruleAuthor, ruleDate := ruleMetadata(doc, rule)
+	author, published := ruleAuthor, ruleDate
+	if author == "" {
+		author = genericAuthor(doc)
+	}
+	if published.IsZero() {
+		published = genericDate(doc)
+	}
+	ld := structured(doc)
+	description := describe(doc)
+	doc.Find("script, style, nav, header, footer, aside, form").Remove()
+
+	var candidates []candidate
+	if rule.Body != "" {
+		candidates = append(candidates, candidate{strategy: StrategyRule, text: selectText(doc, rule.Body)})
+	}
+	candidates = append(candidates, ld,
+		candidate{strategy: StrategyReadability, text: paragraphs(doc)},
+		candidate{strategy: StrategyDescription, text: description})
+
+	best := -1
+	var bestQ Quality
+	for _, c := range candidates {
+		if c.author == "" || ruleAuthor != "" {
+			c.author = author
+		}
+		if c.published.IsZero() || !ruleDate.IsZero() {
+			c.published = published
+		}
+		q := Evaluate(c.text, c.author != "", !c.published.IsZero())
+		res.Attempts = append(res.Attempts, Attempt{Strategy: c.strategy, Length: q.Length, Score: q.Score})
+		if c.text != "" && (best < 0 || q.Score > bestQ.Score) {
+			best, bestQ = len(res.Attempts)-1, q
+			res.Text, res.Author, res.Published, res.Strategy, res.Quality = c.text, c.author, c.published, c.strategy, q
+		}
+		if best >= 0 && bestQ.Score >= MinScore {
+			break
+		}
+	}
+	if best < 0 {
+		res.Author, res.Published = author, published
+		res.Quality = Evaluate("", author != "", !published.IsZero())
+	}
+	return res, nil
+}
+
+// Evaluate puntúa una extracción (ver Quality).
+func Evaluate(text string, hasAuthor, hasDate bool) Quality {
+	q := Quality{Length: len([]rune(text)), HasAuthor: hasAuthor, HasDate: hasDate}
+	if text == "" {
+		return q
+	}
+	var paras, filler int
+	for _, p := range strings.Split(text, "\n\n") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		paras++
+		lower := strings.ToLower(p)
+		for _, b := range boilerplate {
+			if strings.Contains(lower, b) {
+				filler++
+				break
+			}
+		}
+	}
+	if paras > 0 {
+		q.Boilerplate = round(float64(filler) / float64(paras))
+	}
+	score := weightLength*math.Min(float64(q.Length)/expectedLength, 1) + weightBoilerplate*(1-q.Boilerplate)
+	if hasDate {
+		score += weightDate
+	}
+	if hasAuthor {
+		score += weightAuthor
+	}
+	q.Score = round(score)
+	return q
+}
+
+func round(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
+
+// ruleMetadata aplica los selectores de autor y fecha de la regla.
+func ruleMetadata(doc *goquery.Document, rule Rule) (author string, published time.Time) {
+	if rule.Author != "" {
+		author = clean(doc.Find(rule.Author).First().Text())
+	}
+	if rule.Date != "" {
+		published, _ = dates.Parse(dateValue(doc.Find(rule.Date).First()))
+	}
+	return author, published
+}
+
+// selectText une el texto de los elementos del selector.
+func selectText(doc *goquery.Document, sel string) string {
+	var parts []string
+	doc.Find(sel).Each(func(_ int, s *goquery.Selection) {
+		if text := clean(s.Text()); text != "" {
+			parts = append(parts, text)
+		}
+	})
+	return strings.Join(parts, "\n\n")
+}
+
+// structured lee la nota de los datos estructurados (JSON-LD de tipo
+// NewsArticle, Article o similar), que muchos medios publican completos.
+func structured(doc *goquery.Document) candidate {
+	c := candidate{strategy: StrategyStructured}
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		var data any
+		if json.Unmarshal([]byte(s.Text()), &data) != nil {
+			return true
+		}
+		if obj := findArticle(data); obj != nil {
+			body, _ := obj["articleBody"].(string)
+			var parts []string
+			for _, line := range strings.Split(body, "\n") {
+				if line = clean(line); line != "" {
+					parts = append(parts, line)
+				}
+			}
+			c.text = strings.Join(parts, "\n\n")
+			c.author = ldName(obj["author"])
+			if date, ok := obj["datePublished"].(string); ok {
+				c.published, _ = dates.Parse(date)
+			}
+			return false
+		}
+		return true
+	})
+	return c
+}
+
+// findArticle busca el objeto de tipo artículo en un JSON-LD (suelto, en una
+// lista o en @graph).
+func findArticle(data any) map[string]any {
+	switch v := data.(type) {
+	case []any:
+		for _, item := range v {
+			if obj := findArticle(item); obj != nil {
+				return obj
+			}
+		}
+	case map[string]any:
+		if isArticleType(v["@type"]) {
+			return v
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findArticle(graph)
+		}
+	}
+	return nil
+}
+
+func isArticleType(t any) bool {
+	switch v := t.(type) {
+	case string:
+		return strings.HasSuffix(v, "Article") || v == "BlogPosting" || v == "ReportageNewsArticle"
+	case []any:
+		for _, item := range v {
+			if isArticleType(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ldName es el nombre de un autor de JSON-LD: texto, objeto con name o lista.
+func ldName(v any) string {
+	switch a := v.(type) {
+	case string:
+		return clean(a)
+	case map[string]any:
+		name, _ := a["name"].(string)
+		return clean(name)
+	case []any:
+		var names []string
+		for _, item := range a {
+			if n := ldName(item); n != "" {
+				names = append(names, n)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+// describe es la descripción de la página (og:description o description).
+func describe(doc *goquery.Document) string {
+	for _, sel := range []string{`meta[property="og:description"]`, `meta[name="description"]`, `meta[name="twitter:description"]`} {
+		if v, ok := doc.Find(sel).First().Attr("content"); ok {
+			if v = clean(v); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package extract
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// minParagraphLen descarta párrafos cortos (créditos, botones, pies de foto).
+const minParagraphLen = 40
+
+// Text extrae el texto del cuerpo de un artículo a partir del HTML.
+// Prioriza el elemento <article>; si no existe, usa todos los párrafos de la página.
+func Text(html []byte) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return "", fmt.Errorf("error parseando HTML: %w", err)
+	}
+	doc.Find("script, style, nav, header, footer, aside, form").Remove()
+	return paragraphs(doc), nil
+}
+
+// paragraphs es la extracción genérica: los párrafos largos de <article> o,
+// si no existe, de toda la página.
+func paragraphs(doc *goquery.Document) string {
+	scope := doc.Find("article").First()
+	if scope.Length() == 0 {
+		scope = doc.Find("body")
+	}
+
+	var parts []string
+	scope.Find("p").Each(func(_ int, p *goquery.Selection) {
+		text := strings.Join(strings.Fields(p.Text()), " ")
+		if len(text) >= minParagraphLen {
+			parts = append(parts, text)
+		}
+	})
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,110 @@
+package extract
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+
+	"go-collector/dates"
+)
+
+// Rule son los selectores CSS que reemplazan la extracción genérica en un
+// dominio donde falla (ej: el cuerpo dividido en bloques sin <p>, el autor en
+// un enlace a su perfil). Un selector vacío usa la extracción genérica.
+type Rule struct {
+	Body   string
+	Author string
+	// Date apunta al elemento con la fecha de publicación; se lee su atributo
+	// datetime o content y si no, su texto.
+	Date string
+}
+
+// Check verifica que los selectores de la regla sean válidos.
+func (r Rule) Check() error {
+	for name, sel := range map[string]string{"body": r.Body, "author": r.Author, "date": r.Date} {
+		if err := CheckSelector(sel); err != nil {
+			return fmt.Errorf("selector %s inválido %q: %w", name, sel, err)
+		}
+	}
+	return nil
+}
+
+// CheckSelector verifica que sel sea un selector CSS válido; vacío lo es.
+// Las expresiones XPath no se admiten.
+func CheckSelector(sel string) error {
+	if sel == "" {
+		return nil
+	}
+	if strings.HasPrefix(sel, "/") {
+		return fmt.Errorf("parece XPath; use un selector CSS")
+	}
+	_, err := cascadia.ParseGroup(sel)
+	return err
+}
+
+// Extractor extrae el texto, el autor y la fecha de una página probando
+// estrategias en cadena (ver Extract). El valor cero no tiene reglas por
+// dominio.
+type Extractor struct {
+	// Rules: dominio -> regla. Aplica también a los subdominios; gana la
+	// entrada más específica.
+	Rules map[string]Rule
+}
+
+// match busca la regla más específica para host (ver Extractor.Rules).
+func (e *Extractor) match(host string) (string, Rule) {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	for host != "" {
+		if r, ok := e.Rules[host]; ok {
+			return host, r
+		}
+		_, rest, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+		host = rest
+	}
+	return "", Rule{}
+}
+
+// genericAuthor lee el autor de los metadatos habituales.
+func genericAuthor(doc *goquery.Document) string {
+	for _, sel := range []string{`meta[name="author"]`, `meta[property="article:author"]`, `[rel="author"]`, `[itemprop="author"]`} {
+		s := doc.Find(sel).First()
+		if v, ok := s.Attr("content"); ok && !strings.HasPrefix(v, "http") {
+			if v = clean(v); v != "" {
+				return v
+			}
+		} else if v := clean(s.Text()); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// genericDate lee la fecha de publicación de los metadatos habituales.
+func genericDate(doc *goquery.Document) time.Time {
+	for _, sel := range []string{`meta[property="article:published_time"]`, `meta[itemprop="datePublished"]`, `[itemprop="datePublished"]`, `time[datetime]`} {
+		if t, err := dates.Parse(dateValue(doc.Find(sel).First())); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dateValue es el atributo datetime o content del elemento, o su texto.
+func dateValue(s *goquery.Selection) string {
+	for _, attr := range []string{"datetime", "content"} {
+		if v, ok := s.Attr(attr); ok {
+			return v
+		}
+	}
+	return clean(s.Text())
+}
+
+func clean(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,115 @@
+package feeds
+
+import (
+	"fmt"
+	"strings"
+
+	"go-collector/config"
+)
+
+// catalog son las ediciones conocidas de los medios globales. Region vacía
+// significa edición general del idioma.
+var catalog = map[string][]config.FeedEdition{
+	"bbc": {
+		{Name: "BBC News - World", Language: "en", URL: "https://feeds.bbci.co.uk/news/world/rss.xml"},
+		{Name: "BBC News - Latin America", Language: "en", Region: "latam", URL: "https://feeds.bbci.co.uk/news/world/latin_america/rss.xml"},
+		{Name: "BBC Mundo", Language: "es", URL: "https://feeds.bbci.co.uk/mundo/rss.xml"},
+		{Name: "BBC Mundo - América Latina", Language: "es", Region: "latam", URL: "https://feeds.bbci.co.uk/mundo/america_latina/rss.xml"},
+		{Name: "BBC News Brasil", Language: "pt", URL: "https://feeds.bbci.co.uk/portuguese/rss.xml"},
+	},
+	"nyt": {
+		{Name: "NYT - World", Language: "en", URL: "https://rss.nytimes.com/services/xml/rss/nyt/World.xml"},
+		{Name: "NYT - Americas", Language: "en", Region: "latam", URL: "https://rss.nytimes.com/services/xml/rss/nyt/Americas.xml"},
+		{Name: "NYT en Español", Language: "es", URL: "https://www.nytimes.com/es/rss/"},
+	},
+	"guardian": {
+		{Name: "The Guardian - World", Language: "en", URL: "https://www.theguardian.com/world/rss"},
+		{Name: "The Guardian - Americas", Language: "en", Region: "latam", URL: "https://www.theguardian.com/world/americas/rss"},
+		{Name: "The Guardian - Colombia", Language: "en", Region: "co", URL: "https://www.theguardian.com/world/colombia/rss"},
+	},
+}
+
+// regionParents permite que una campaña de un país use la edición regional
+// cuando el medio no tiene una edición para ese país.
+var regionParents = map[string]string{
+	"co": "latam", "mx": "latam", "ar": "latam", "pe": "latam", "cl": "latam",
+	"ve": "latam", "ec": "latam", "br": "latam", "bo": "latam", "uy": "latam",
+}
+
+// Resolver elige los feeds de cada medio según el idioma y región de la campaña.
+type Resolver struct {
+	editions map[string][]config.FeedEdition
+}
+
+// NewResolver combina el catálogo incluido con las ediciones de la configuración.
+func NewResolver(cfg config.Feeds) *Resolver {
+	editions := make(map[string][]config.FeedEdition, len(catalog))
+	for outlet, list := range catalog {
+		editions[outlet] = list
+	}
+	for outlet, list := range cfg.Editions {
+		editions[strings.ToLower(outlet)] = list
+	}
+	return &Resolver{editions: editions}
+}
+
+// Resolve devuelve las ediciones de un medio para un idioma y región. Orden de preferencia:
+//  1. ediciones del idioma de la campaña: la general y la de su región (o región padre);
+//  2. si el medio no publica en ese idioma, las ediciones regionales en cualquier idioma
+//     (ej: Guardian Colombia en inglés para una campaña en español);
+//  3. la edición general en inglés.
+func (r *Resolver) Resolve(outlet, language, region string) ([]config.FeedEdition, error) {
+	list, ok := r.editions[strings.ToLower(outlet)]
+	if !ok {
+		return nil, fmt.Errorf("medio desconocido en el catálogo de feeds: %s", outlet)
+	}
+	language, region = strings.ToLower(language), strings.ToLower(region)
+
+	if chosen := pick(list, language, region); len(chosen) > 0 {
+		return chosen, nil
+	}
+	var regional []config.FeedEdition
+	for _, e := range list {
+		if e.Region != "" && matchesRegion(e.Region, region) {
+			regional = append(regional, e)
+		}
+	}
+	if len(regional) > 0 {
+		return regional, nil
+	}
+	if chosen := pick(list, "en", ""); len(chosen) > 0 {
+		return chosen, nil
+	}
+	return nil, fmt.Errorf("el medio %s no tiene ediciones para idioma %q", outlet, language)
+}
+
+// ResolveCampaign devuelve los feeds de todos los medios de la campaña.
+func (r *Resolver) ResolveCampaign(c config.Campaign) ([]config.FeedEdition, error) {
+	var all []config.FeedEdition
+	for _, outlet := range c.Outlets {
+		editions, err := r.Resolve(outlet, c.Language, c.Region)
+		if err != nil {
+			return nil, fmt.Errorf("campaña %s: %w", c.Name, err)
+		}
+		all = append(all, editions...)
+	}
+	return all, nil
+}
+
+// pick devuelve la edición general del idioma más la de la región, si existe.
+func pick(list []config.FeedEdition, language, region string) []config.FeedEdition {
+	var out []config.FeedEdition
+	for _, e := range list {
+		if e.Language != language {
+			continue
+		}
+		if e.Region == "" || matchesRegion(e.Region, region) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func matchesRegion(edition, campaign string) bool {
+	return campaign != "" && (edition == campaign || edition == regionParents[campaign])
+}
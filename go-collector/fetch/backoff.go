@@ -0,0 +1,137 @@
+package fetch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-collector/config"
+	"go-collector/storage"
+)
+
+// Valores por defecto del enfriamiento si la configuración no los define.
+const (
+	defaultBackoffBase = 15 * time.Minute
+	defaultBackoffMax  = 24 * time.Hour
+)
+
+// BackoffStore persiste el estado de enfriamiento por dominio entre corridas.
+type BackoffStore interface {
+	GetBackoff(domain string) (*storage.DomainBackoff, error)
+	SaveBackoff(b *storage.DomainBackoff) error
+}
+
+// CoolingDownError indica que no se hizo la petición porque el dominio está en enfriamiento.
+type CoolingDownError struct {
+	Domain string
+	Until  time.Time
+}
+
+func (e *CoolingDownError) Error() string {
+	return fmt.Sprintf("dominio %s en enfriamiento hasta %s", e.Domain, e.Until.Format("2006-01-02 15:04"))
+}
+
+// Backoff evita peticiones a dominios que nos respondieron 429/403 hasta que
+// termine su enfriamiento. Cada bloqueo consecutivo duplica la espera (o usa
+// Retry-After si el servidor lo indica) y una respuesta exitosa la reinicia.
+func Backoff(store BackoffStore, cfg config.Backoff) Middleware {
+	base, max := cfg.Base, cfg.Max
+	if base <= 0 {
+		base = defaultBackoffBase
+	}
+	if max <= 0 {
+		max = defaultBackoffMax
+	}
+	var mu sync.Mutex
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			domain := strings.TrimPrefix(strings.ToLower(req.URL.Hostname()), "www.")
+
+			mu.Lock()
+			state, err := store.GetBackoff(domain)
+			mu.Unlock()
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return nil, err
+			}
+			now := time.Now().UTC()
+			if state != nil && now.Before(state.CooldownUntil) {
+				return nil, &CoolingDownError{Domain: domain, Until: state.CooldownUntil}
+			}
+
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				return nil, err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusForbidden:
+				if state == nil {
+					state = &storage.DomainBackoff{Domain: domain}
+				}
+				state.Strikes++
+				state.TotalBlocks++
+				state.LastStatus = resp.StatusCode
+				state.LastBlockedAt = now
+
+				wait := retryAfter(resp.Header.Get("Retry-After"), now)
+				if wait <= 0 {
+					wait = base << (state.Strikes - 1)
+				}
+				if wait > max || wait <= 0 {
+					wait = max
+				}
+				state.CooldownUntil = now.Add(wait)
+				if err := store.SaveBackoff(state); err != nil {
+					resp.Body.Close()
+					return nil, err
+				}
+			case resp.StatusCode < 400 && state != nil && state.Strikes > 0:
+				state.Strikes = 0
+				if err := store.SaveBackoff(state); err != nil {
+					resp.Body.Close()
+					return nil, err
+				}
+			}
+			return resp, nil
+		})
+	}
+}
+
+// retryAfter interpreta el encabezado Retry-After (segundos o fecha HTTP).
+func retryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// ReportarDominiosBloqueados muestra los dominios que nos bloquean de forma recurrente.
+func ReportarDominiosBloqueados(blockers []*storage.DomainBackoff) {
+	fmt.Println("\n--- DOMINIOS QUE BLOQUEAN DE FORMA RECURRENTE ---")
+	if len(blockers) == 0 {
+		fmt.Println("Ningún dominio supera el umbral de bloqueos.")
+		return
+	}
+	now := time.Now().UTC()
+	for i, b := range blockers {
+		estado := "disponible"
+		if now.Before(b.CooldownUntil) {
+			estado = "en enfriamiento hasta " + b.CooldownUntil.Format("2006-01-02 15:04")
+		}
+		fmt.Printf("  %2d. %-30s %3d bloqueos (último: HTTP %d el %s) | %s\n",
+			i+1, b.Domain, b.TotalBlocks, b.LastStatus, b.LastBlockedAt.Format("2006-01-02 15:04"), estado)
+	}
+}
@@ -0,0 +1,177 @@
+package fetch
+
+import (
+	"bytes"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"go-collector/cachestat"
+)
+
+// cacheShards es en cuántas partes con su propio candado se reparte la
+// caché, para que las campañas en paralelo no se esperen entre sí.
+const cacheShards = 16
+
+// sweepEvery es cada cuántas escrituras en una parte se descartan sus
+// entradas vencidas (las que nadie vuelve a pedir no se borrarían nunca).
+const sweepEvery = 64
+
+// Cache guarda en memoria las respuestas 200 de peticiones GET durante TTL,
+// para que varias campañas que consultan lo mismo en la misma ronda hagan una
+// sola petición. Las credenciales forman parte de la clave: respuestas de
+// cuentas distintas no se mezclan. Es segura desde varias goroutines: las
+// peticiones iguales que llegan mientras otra está en curso esperan su
+// respuesta en vez de repetirla.
+type Cache struct {
+	TTL time.Duration
+
+	shards [cacheShards]cacheShard
+	flight singleflight.Group
+	stats  cachestat.Counter
+}
+
+type cacheShard struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	writes  int
+}
+
+type cacheEntry struct {
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// NewCache crea una caché vacía.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{TTL: ttl}
+}
+
+// Stats devuelve los aciertos y fallos acumulados.
+func (c *Cache) Stats() cachestat.Stats {
+	n := 0
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		n += len(s.entries)
+		s.mu.Unlock()
+	}
+	return c.stats.Snapshot(n)
+}
+
+// Middleware devuelve el middleware que responde desde la caché. Debe ir por
+// fuera de RateLimit para que los aciertos no gasten cupo.
+func (c *Cache) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodGet {
+				return next.RoundTrip(req)
+			}
+			key := cacheKey(req)
+			if e, ok := c.get(key); ok {
+				c.stats.Hit()
+				return e.response(req), nil
+			}
+
+			// La primera petición de la clave va al origen; las que llegan
+			// mientras tanto reciben su misma respuesta, sea 200 o no.
+			// shared de singleflight es true también para la que fue al
+			// origen, así que esa se marca aparte.
+			leader := false
+			v, err, _ := c.flight.Do(key, func() (any, error) {
+				leader = true
+				resp, err := next.RoundTrip(req)
+				if err != nil {
+					return nil, err
+				}
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				e := cacheEntry{status: resp.StatusCode, header: resp.Header.Clone(), body: body, expires: time.Now().Add(c.TTL)}
+				if resp.StatusCode == http.StatusOK {
+					c.put(key, e)
+				}
+				return e, nil
+			})
+			if leader {
+				c.stats.Miss()
+			} else {
+				c.stats.Shared()
+			}
+			if err != nil {
+				return nil, err
+			}
+			return v.(cacheEntry).response(req), nil
+		})
+	}
+}
+
+// response arma una respuesta propia de req con la entrada: cada una con su
+// copia de los encabezados y su lector del cuerpo.
+func (e cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(e.status),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+func (c *Cache) shard(key string) *cacheShard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return &c.shards[h.Sum32()%cacheShards]
+}
+
+func (c *Cache) get(key string) (cacheEntry, bool) {
+	s := c.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	if time.Now().After(e.expires) {
+		delete(s.entries, key)
+		c.stats.Evict(1)
+		return cacheEntry{}, false
+	}
+	return e, true
+}
+
+func (c *Cache) put(key string, e cacheEntry) {
+	s := c.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries == nil {
+		s.entries = make(map[string]cacheEntry)
+	}
+	s.entries[key] = e
+	if s.writes++; s.writes%sweepEvery == 0 {
+		now, n := time.Now(), 0
+		for k, old := range s.entries {
+			if now.After(old.expires) {
+				delete(s.entries, k)
+				n++
+			}
+		}
+		c.stats.Evict(n)
+	}
+}
+
+func cacheKey(req *http.Request) string {
+	return req.URL.String() + "\n" + req.Header.Get("Authorization") + "\n" + req.Header.Get("X-Api-Key")
+}
@@ -0,0 +1,164 @@
+package fetch
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// origin responde cada URL con un cuerpo propio y cuenta las peticiones
+// que le llegan; wait, si no es nil, retiene cada respuesta hasta cerrarse.
+type origin struct {
+	calls  atomic.Int64
+	status int
+	wait   chan struct{}
+}
+
+func (o *origin) RoundTrip(req *http.Request) (*http.Response, error) {
+	o.calls.Add(1)
+	if o.wait != nil {
+		<-o.wait
+	}
+	status := o.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"text/plain"}},
+		Body:       io.NopCloser(strings.NewReader("cuerpo de " + req.URL.String())),
+		Request:    req,
+	}, nil
+}
+
+func get(t *testing.T, rt http.RoundTripper, u string) string {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		t.Error(err)
+		return ""
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Error(err)
+		return ""
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Error(err)
+	}
+	return string(body)
+}
+
+// Las peticiones iguales que llegan a la vez hacen una sola al origen: la
+// primera va y las demás esperan su respuesta o la toman de la caché.
+func TestCacheConcurrentSameURL(t *testing.T) {
+	o := &origin{wait: make(chan struct{})}
+	c := NewCache(time.Minute)
+	rt := Chain(o, c.Middleware())
+
+	const n = 50
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if body := get(t, rt, "https://api.example.com/v1/search?q=udea"); body != "cuerpo de https://api.example.com/v1/search?q=udea" {
+				t.Errorf("cuerpo %q", body)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond) // que las demás lleguen mientras la primera espera
+	close(o.wait)
+	wg.Wait()
+
+	if calls := o.calls.Load(); calls != 1 {
+		t.Errorf("%d peticiones al origen, se esperaba 1", calls)
+	}
+	s := c.Stats()
+	if s.Misses != 1 || s.Hits+s.Shared != n-1 || s.Entries != 1 {
+		t.Errorf("estadísticas %+v, se esperaba 1 fallo, %d aciertos o compartidas y 1 entrada", s, n-1)
+	}
+}
+
+// Muchas URLs desde muchas goroutines: cada una va una vez al origen y
+// ninguna recibe la respuesta de otra, aunque compartan parte de la caché.
+func TestCacheConcurrentURLs(t *testing.T) {
+	o := &origin{}
+	c := NewCache(time.Minute)
+	rt := Chain(o, c.Middleware())
+
+	const urls, rounds = 200, 5
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range rounds {
+				for i := range urls {
+					u := fmt.Sprintf("https://api.example.com/v1/page/%d", (i+g*r)%urls)
+					if body := get(t, rt, u); body != "cuerpo de "+u {
+						t.Errorf("%s: cuerpo %q", u, body)
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls := o.calls.Load(); calls != urls {
+		t.Errorf("%d peticiones al origen, se esperaban %d", calls, urls)
+	}
+	if s := c.Stats(); s.Entries != urls || s.Lookups() != 8*rounds*urls {
+		t.Errorf("estadísticas %+v, se esperaban %d entradas y %d consultas", s, urls, 8*rounds*urls)
+	}
+}
+
+// Las credenciales son parte de la clave y las respuestas que no son 200 no
+// se guardan.
+func TestCacheKeyAndStatus(t *testing.T) {
+	o := &origin{}
+	rt := Chain(o, NewCache(time.Minute).Middleware())
+	for _, key := range []string{"a", "b", "a"} {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/v1/me", nil)
+		req.Header.Set("X-Api-Key", key)
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if calls := o.calls.Load(); calls != 2 {
+		t.Errorf("%d peticiones al origen con dos claves de API, se esperaban 2", calls)
+	}
+
+	failing := &origin{status: http.StatusTooManyRequests}
+	rt = Chain(failing, NewCache(time.Minute).Middleware())
+	get(t, rt, "https://api.example.com/v1/search")
+	get(t, rt, "https://api.example.com/v1/search")
+	if calls := failing.calls.Load(); calls != 2 {
+		t.Errorf("%d peticiones al origen tras un 429, se esperaban 2", calls)
+	}
+}
+
+func TestCacheExpires(t *testing.T) {
+	o := &origin{}
+	c := NewCache(time.Millisecond)
+	rt := Chain(o, c.Middleware())
+	get(t, rt, "https://api.example.com/v1/search")
+	time.Sleep(5 * time.Millisecond)
+	get(t, rt, "https://api.example.com/v1/search")
+	if calls := o.calls.Load(); calls != 2 {
+		t.Errorf("%d peticiones al origen tras vencer la entrada, se esperaban 2", calls)
+	}
+	if s := c.Stats(); s.Evicted != 1 {
+		t.Errorf("%d entradas descartadas, se esperaba 1", s.Evicted)
+	}
+}
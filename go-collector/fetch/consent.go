@@ -0,0 +1,62 @@
+package fetch
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+)
+
+// consentHosts son dominios a los que redirigen las plataformas de consentimiento.
+var consentHosts = []string{
+	"consent.google.com",
+	"consent.yahoo.com",
+	"consent.youtube.com",
+	"privacy-mgmt.com",
+	"cmp.",
+}
+
+// consentMarkers son huellas de las plataformas de gestión de consentimiento (CMP)
+// y de los textos típicos de los muros de cookies.
+var consentMarkers = [][]byte{
+	[]byte("sp_message_container"),
+	[]byte("didomi-popup"),
+	[]byte("onetrust-consent-sdk"),
+	[]byte("qc-cmp2-container"),
+	[]byte("consent-wall"),
+	[]byte("cookiewall"),
+	[]byte("before you continue"),
+	[]byte("antes de continuar"),
+	[]byte("we value your privacy"),
+	[]byte("valoramos tu privacidad"),
+}
+
+// minArticleParagraphs: una página con un marcador de consentimiento y menos
+// párrafos que esto se considera un muro y no un artículo con banner de cookies.
+const minArticleParagraphs = 5
+
+// IsConsentWall detecta si la página es un intersticial de consentimiento en
+// lugar del artículo. Muchos artículos reales incluyen el script del banner de
+// cookies, por eso además del marcador se exige que casi no haya contenido.
+func IsConsentWall(p *Page) bool {
+	if u, err := url.Parse(p.URL); err == nil {
+		host := u.Hostname()
+		for _, h := range consentHosts {
+			if strings.HasPrefix(host, h) || strings.HasSuffix(host, h) {
+				return true
+			}
+		}
+	}
+
+	lower := bytes.ToLower(p.Body)
+	marked := false
+	for _, m := range consentMarkers {
+		if bytes.Contains(lower, m) {
+			marked = true
+			break
+		}
+	}
+	if !marked {
+		return false
+	}
+	return bytes.Count(lower, []byte("<p")) < minArticleParagraphs
+}
@@ -0,0 +1,176 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"go-collector/cachestat"
+)
+
+// Resolver resuelve un nombre de host a sus direcciones; *net.Resolver lo
+// cumple.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// DNSCache guarda en memoria las direcciones de cada host durante TTL, para
+// que las fuentes y campañas que piden al mismo medio o API no lo resuelvan
+// en cada conexión. Los fallos no se guardan. Es segura desde varias
+// goroutines: las resoluciones del mismo host que llegan mientras otra está
+// en curso esperan su resultado.
+type DNSCache struct {
+	TTL      time.Duration
+	Resolver Resolver // nil = net.DefaultResolver
+
+	shards [cacheShards]dnsShard
+	flight singleflight.Group
+	stats  cachestat.Counter
+}
+
+type dnsShard struct {
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+	writes  int
+}
+
+type dnsEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// NewDNSCache crea una caché vacía que resuelve con net.DefaultResolver.
+func NewDNSCache(ttl time.Duration) *DNSCache {
+	return &DNSCache{TTL: ttl}
+}
+
+// Stats devuelve los aciertos y fallos acumulados.
+func (c *DNSCache) Stats() cachestat.Stats {
+	n := 0
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		n += len(s.entries)
+		s.mu.Unlock()
+	}
+	return c.stats.Snapshot(n)
+}
+
+// LookupHost devuelve las direcciones de host, desde la caché si están
+// vigentes. Una IP se devuelve tal cual, sin consultar ni contar.
+func (c *DNSCache) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+	if addrs, ok := c.get(host); ok {
+		c.stats.Hit()
+		return addrs, nil
+	}
+	leader := false
+	v, err, _ := c.flight.Do(host, func() (any, error) {
+		leader = true
+		r := c.Resolver
+		if r == nil {
+			r = net.DefaultResolver
+		}
+		// La resolución la comparten varias peticiones: no se corta porque
+		// se cancele la que llegó primero.
+		addrs, err := r.LookupHost(context.WithoutCancel(ctx), host)
+		if err != nil {
+			return nil, err
+		}
+		c.put(host, addrs)
+		return addrs, nil
+	})
+	if leader {
+		c.stats.Miss()
+	} else {
+		c.stats.Shared()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v.([]string), nil
+}
+
+// DialContext conecta a addr (host:puerto) resolviendo el host con la caché
+// y probando sus direcciones en orden hasta que una responda.
+func (c *DNSCache) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := c.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	d := net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	var errs []error
+	for _, ip := range addrs {
+		conn, err := d.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Transport devuelve una copia de http.DefaultTransport que resuelve con la
+// caché; se usa como base de la cadena de middlewares.
+func (c *DNSCache) Transport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.DialContext = c.DialContext
+	return t
+}
+
+func (c *DNSCache) shard(host string) *dnsShard {
+	h := fnv.New32a()
+	h.Write([]byte(host))
+	return &c.shards[h.Sum32()%cacheShards]
+}
+
+func (c *DNSCache) get(host string) ([]string, bool) {
+	s := c.shard(host)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[host]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(s.entries, host)
+		c.stats.Evict(1)
+		return nil, false
+	}
+	return e.addrs, true
+}
+
+func (c *DNSCache) put(host string, addrs []string) {
+	s := c.shard(host)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries == nil {
+		s.entries = make(map[string]dnsEntry)
+	}
+	s.entries[host] = dnsEntry{addrs: addrs, expires: time.Now().Add(c.TTL)}
+	if s.writes++; s.writes%sweepEvery == 0 {
+		now, n := time.Now(), 0
+		for k, old := range s.entries {
+			if now.After(old.expires) {
+				delete(s.entries, k)
+				n++
+			}
+		}
+		c.stats.Evict(n)
+	}
+}
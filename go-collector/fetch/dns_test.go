@@ -0,0 +1,147 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// resolver responde a cada host una dirección propia y cuenta las
+// consultas; wait, si no es nil, retiene cada una hasta cerrarse.
+type resolver struct {
+	calls atomic.Int64
+	wait  chan struct{}
+	fail  bool
+}
+
+func (r *resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	r.calls.Add(1)
+	if r.wait != nil {
+		<-r.wait
+	}
+	if r.fail {
+		return nil, errors.New("no such host")
+	}
+	return []string{"192.0.2." + fmt.Sprint(len(host))}, nil
+}
+
+// Las resoluciones del mismo host que llegan a la vez hacen una sola
+// consulta.
+func TestDNSCacheConcurrentSameHost(t *testing.T) {
+	r := &resolver{wait: make(chan struct{})}
+	c := &DNSCache{TTL: time.Minute, Resolver: r}
+
+	const n = 50
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			addrs, err := c.LookupHost(context.Background(), "www.udea.edu.co")
+			if err != nil || len(addrs) != 1 || addrs[0] != "192.0.2.15" {
+				t.Errorf("direcciones %v, error %v", addrs, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(r.wait)
+	wg.Wait()
+
+	if calls := r.calls.Load(); calls != 1 {
+		t.Errorf("%d consultas al resolvedor, se esperaba 1", calls)
+	}
+	if s := c.Stats(); s.Misses != 1 || s.Hits+s.Shared != n-1 || s.Entries != 1 {
+		t.Errorf("estadísticas %+v, se esperaba 1 fallo, %d aciertos o compartidas y 1 entrada", s, n-1)
+	}
+}
+
+// Muchos hosts desde muchas goroutines: cada uno se resuelve una vez y
+// recibe sus propias direcciones.
+func TestDNSCacheConcurrentHosts(t *testing.T) {
+	r := &resolver{}
+	c := &DNSCache{TTL: time.Minute, Resolver: r}
+
+	const hosts, rounds = 100, 5
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rounds * hosts {
+				host := fmt.Sprintf("medio%d.example.com", (i+g)%hosts)
+				addrs, err := c.LookupHost(context.Background(), host)
+				if err != nil || addrs[0] != "192.0.2."+fmt.Sprint(len(host)) {
+					t.Errorf("%s: direcciones %v, error %v", host, addrs, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls := r.calls.Load(); calls != hosts {
+		t.Errorf("%d consultas al resolvedor, se esperaban %d", calls, hosts)
+	}
+	if s := c.Stats(); s.Entries != hosts || s.Lookups() != 8*rounds*hosts {
+		t.Errorf("estadísticas %+v, se esperaban %d entradas y %d consultas", s, hosts, 8*rounds*hosts)
+	}
+}
+
+// Los fallos no se guardan, las IPs no se consultan y las entradas vencen.
+func TestDNSCacheFailuresAndExpiry(t *testing.T) {
+	failing := &resolver{fail: true}
+	c := &DNSCache{TTL: time.Minute, Resolver: failing}
+	for range 2 {
+		if _, err := c.LookupHost(context.Background(), "caido.example.com"); err == nil {
+			t.Error("se esperaba el error del resolvedor")
+		}
+	}
+	if calls := failing.calls.Load(); calls != 2 {
+		t.Errorf("%d consultas tras un fallo, se esperaban 2", calls)
+	}
+	if addrs, _ := c.LookupHost(context.Background(), "203.0.113.7"); len(addrs) != 1 || addrs[0] != "203.0.113.7" || failing.calls.Load() != 2 {
+		t.Errorf("una IP se resolvió a %v", addrs)
+	}
+
+	r := &resolver{}
+	c = &DNSCache{TTL: time.Millisecond, Resolver: r}
+	c.LookupHost(context.Background(), "www.udea.edu.co")
+	time.Sleep(5 * time.Millisecond)
+	c.LookupHost(context.Background(), "www.udea.edu.co")
+	if calls := r.calls.Load(); calls != 2 {
+		t.Errorf("%d consultas tras vencer la entrada, se esperaban 2", calls)
+	}
+	if s := c.Stats(); s.Evicted != 1 {
+		t.Errorf("%d entradas descartadas, se esperaba 1", s.Evicted)
+	}
+}
+
+// DialContext conecta a la dirección resuelta por la caché.
+func TestDNSCacheDial(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+	go func() {
+		if conn, err := ln.Accept(); err == nil {
+			conn.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	c := &DNSCache{TTL: time.Minute, Resolver: staticResolver{"127.0.0.1"}}
+	conn, err := c.DialContext(context.Background(), "tcp", net.JoinHostPort("medio.example.com", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+type staticResolver []string
+
+func (r staticResolver) LookupHost(context.Context, string) ([]string, error) { return r, nil }
@@ -0,0 +1,140 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-collector/config"
+)
+
+// maxBodySize limita el tamaño de página descargada (las notas no pesan más que esto).
+const maxBodySize = 5 << 20
+
+// Page es una página descargada.
+type Page struct {
+	URL         string // URL final, después de redirecciones
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	ConsentWall bool // el medio respondió con un muro de consentimiento de cookies
+}
+
+// Fetcher descarga páginas de artículos para extraer su texto completo.
+type Fetcher struct {
+	Client *http.Client
+
+	// ConsentCookies se envían solo al reintentar un dominio que respondió con
+	// un muro de consentimiento (dominio -> nombre -> valor).
+	ConsentCookies map[string]map[string]string
+}
+
+func NewFetcher() *Fetcher {
+	return &Fetcher{
+		Client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+	}
+}
+
+// NewFetcherFromConfig arma el descargador con las cookies de consentimiento y
+// las reglas por dominio de la configuración. Si backoff no es nil, los dominios
+// que nos bloquearon se respetan hasta que termine su enfriamiento.
+func NewFetcherFromConfig(cfg config.Fetch, backoff BackoffStore) *Fetcher {
+	f := NewFetcher()
+	f.ConsentCookies = cfg.ConsentCookies
+
+	mws := []Middleware{DomainRules(cfg.Domains)}
+	if backoff != nil {
+		mws = append(mws, Backoff(backoff, cfg.Backoff))
+	}
+	f.Client.Transport = Chain(http.DefaultTransport, mws...)
+	return f
+}
+
+// Fetch descarga la página. Si detecta un muro de consentimiento y hay cookies
+// configuradas para el dominio, reintenta una vez enviándolas.
+func (f *Fetcher) Fetch(ctx context.Context, pageURL string) (*Page, error) {
+	page, err := f.get(ctx, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !page.ConsentWall {
+		return page, nil
+	}
+
+	cookies := f.consentCookiesFor(pageURL)
+	if len(cookies) == 0 {
+		return page, nil
+	}
+	return f.get(ctx, pageURL, cookies)
+}
+
+func (f *Fetcher) get(ctx context.Context, pageURL string, cookies []*http.Cookie) (*Page, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "EthicalCrawler/1.0 (StudentResearch)")
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+
+	page := &Page{
+		URL:        resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}
+	page.ConsentWall = IsConsentWall(page)
+	return page, nil
+}
+
+// consentCookiesFor busca las cookies configuradas para el dominio o alguno de sus padres.
+func (f *Fetcher) consentCookiesFor(pageURL string) []*http.Cookie {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	domain, ok := matchDomain(u.Hostname(), f.ConsentCookies)
+	if !ok {
+		return nil
+	}
+
+	var cookies []*http.Cookie
+	for name, value := range f.ConsentCookies[domain] {
+		cookies = append(cookies, &http.Cookie{Name: name, Value: value})
+	}
+	return cookies
+}
+
+// matchDomain busca en m la entrada más específica que corresponde a host:
+// "noticias.udea.edu.co" coincide con "noticias.udea.edu.co" y luego con "udea.edu.co".
+func matchDomain[V any](host string, m map[string]V) (string, bool) {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	for {
+		if _, ok := m[host]; ok {
+			return host, true
+		}
+		i := strings.IndexByte(host, '.')
+		if i < 0 {
+			return "", false
+		}
+		host = host[i+1:]
+	}
+}
@@ -0,0 +1,52 @@
+package fetch
+
+import (
+	"net/http"
+
+	"go-collector/config"
+)
+
+// Middleware envuelve un RoundTripper para modificar peticiones o respuestas
+// antes de que lleguen al transporte HTTP.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc permite usar una función como http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Chain aplica los middlewares sobre base; el primero de la lista es el más externo.
+func Chain(base http.RoundTripper, mws ...Middleware) http.RoundTripper {
+	rt := base
+	for i := len(mws) - 1; i >= 0; i-- {
+		rt = mws[i](rt)
+	}
+	return rt
+}
+
+// DomainRules agrega a cada petición los encabezados y cookies configurados
+// para su dominio. Los encabezados configurados reemplazan los existentes
+// (ej: el User-Agent por defecto); las cookies se suman a las que ya tenga.
+func DomainRules(rules map[string]config.DomainRule) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			domain, ok := matchDomain(req.URL.Hostname(), rules)
+			if !ok {
+				return next.RoundTrip(req)
+			}
+			rule := rules[domain]
+
+			// Los RoundTripper no deben modificar la petición original.
+			req = req.Clone(req.Context())
+			for name, value := range rule.Headers {
+				req.Header.Set(name, value)
+			}
+			for name, value := range rule.Cookies {
+				req.AddCookie(&http.Cookie{Name: name, Value: value})
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package fetch
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"go-collector/config"
+)
+
+// RateLimit limita las peticiones con un balde de fichas: r.N cada r.Per, con
+// hasta burst seguidas (al menos 1) mientras sobre cupo. Cada llamada crea su
+// propio cupo: los transportes que lo comparten se reparten las peticiones.
+// Un cupo cero no limita.
+func RateLimit(r config.Rate, burst int) Middleware {
+	if !r.Limited() {
+		return func(next http.RoundTripper) http.RoundTripper { return next }
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	limiter := rate.NewLimiter(rate.Every(r.Per/time.Duration(r.N)), burst)
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			// Wait reserva la ficha antes de esperar: las peticiones
+			// concurrentes quedan en fila y no salen todas juntas. Si la espera
+			// pasaría del plazo de la petición, falla de una vez.
+			if err := limiter.Wait(req.Context()); err != nil {
+				if ctxErr := req.Context().Err(); ctxErr != nil {
+					return nil, ctxErr
+				}
+				return nil, err
+			}
+			return rt.RoundTrip(req)
+		})
+	}
+}
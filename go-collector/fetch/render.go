@@ -0,0 +1,364 @@
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/chromedp/chromedp"
+
+	"go-collector/config"
+	"go-collector/extract"
+	"go-collector/storage"
+)
+
+// defaultRenderTimeout es cuánto se espera una página renderizada si la
+// configuración no lo define.
+const defaultRenderTimeout = 30 * time.Second
+
+// defaultRenderConcurrency es cuántos navegadores corren a la vez si la
+// configuración no lo define: cada uno ocupa cientos de MB de memoria.
+const defaultRenderConcurrency = 2
+
+// renderSettle es cuánto se deja correr los scripts después del evento load,
+// para las notas que se cargan con pedidos posteriores; a lo sumo la mitad
+// del tiempo de la página.
+const renderSettle = 2 * time.Second
+
+// renderUserAgent es el agente con que el navegador se identifica, el mismo
+// de las descargas estáticas.
+const renderUserAgent = "EthicalCrawler/1.0 (StudentResearch)"
+
+// minStaticText es el texto visible por debajo del cual una página con
+// muchos scripts se considera armada en el navegador.
+const minStaticText = 500
+
+// Renderer descarga una página ejecutando su JavaScript. Close libera lo
+// que el renderizador haya creado (ej: los perfiles del navegador).
+type Renderer interface {
+	Render(ctx context.Context, pageURL string) (*Page, error)
+	Close() error
+}
+
+// Chrome renderiza con un Chrome o Chromium sin interfaz manejado por el
+// protocolo DevTools (chromedp): abre cada página en una pestaña, espera
+// que carguen sus scripts y devuelve el DOM resultante junto con el estado
+// HTTP y la URL final del documento. Es seguro usarlo desde varias
+// goroutines: a lo sumo MaxConcurrent navegadores corren a la vez y los
+// demás pedidos esperan un lugar. Cada lugar tiene su navegador y su perfil
+// (directorio de datos), que se reutilizan entre páginas para aprovechar la
+// caché y las cookies de consentimiento, y que Close cierra y borra.
+type Chrome struct {
+	Command string
+	// Args son banderas que se agregan a las del navegador (ej:
+	// --proxy-server=socks5://127.0.0.1:9050).
+	Args          []string
+	Timeout       time.Duration
+	MaxConcurrent int
+
+	once  sync.Once
+	slots chan *browser // lugares libres; un browser sin ctx no se abrió
+	mu    sync.Mutex
+	all   []*browser
+}
+
+// browser es un navegador abierto con su perfil.
+type browser struct {
+	dir    string
+	ctx    context.Context // contexto de chromedp del navegador; nil si no se abrió
+	cancel context.CancelFunc
+}
+
+// NewRenderer arma el renderizador de la configuración, o nil si no hay
+// navegador configurado.
+func NewRenderer(cfg config.Render) Renderer {
+	if cfg.Command == "" {
+		return nil
+	}
+	return &Chrome{Command: cfg.Command, Args: cfg.Args, Timeout: cfg.Timeout, MaxConcurrent: cfg.MaxConcurrent}
+}
+
+// acquire espera un lugar libre y devuelve su navegador, abriéndolo la
+// primera vez que se usa o si el anterior se cerró.
+func (c *Chrome) acquire(ctx context.Context) (*browser, error) {
+	c.once.Do(func() {
+		n := c.MaxConcurrent
+		if n <= 0 {
+			n = defaultRenderConcurrency
+		}
+		c.slots = make(chan *browser, n)
+		for range n {
+			b := &browser{}
+			c.all = append(c.all, b)
+			c.slots <- b
+		}
+	})
+	var b *browser
+	select {
+	case b = <-c.slots:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if b.ctx != nil && b.ctx.Err() == nil {
+		return b, nil
+	}
+	if err := c.open(b); err != nil {
+		c.slots <- b
+		return nil, err
+	}
+	return b, nil
+}
+
+// open abre el navegador del lugar; el perfil se crea la primera vez.
+func (c *Chrome) open(b *browser) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if b.cancel != nil {
+		b.cancel()
+	}
+	if b.dir == "" {
+		dir, err := os.MkdirTemp("", "collector-render-")
+		if err != nil {
+			return fmt.Errorf("error creando el perfil del navegador: %w", err)
+		}
+		b.dir = dir
+	}
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.ExecPath(c.Command),
+		chromedp.UserDataDir(b.dir),
+		chromedp.UserAgent(renderUserAgent),
+		chromedp.DisableGPU,
+		chromedp.Flag("mute-audio", true),
+	)
+	for _, arg := range c.Args {
+		name, value, ok := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if ok {
+			opts = append(opts, chromedp.Flag(name, value))
+		} else {
+			opts = append(opts, chromedp.Flag(name, true))
+		}
+	}
+	// El navegador vive más que cada pedido: no depende de su contexto.
+	alloc, cancelAlloc := chromedp.NewExecAllocator(context.Background(), opts...)
+	ctx, cancelBrowser := chromedp.NewContext(alloc)
+	cancel := func() {
+		cancelBrowser()
+		cancelAlloc()
+	}
+	if err := chromedp.Run(ctx); err != nil {
+		cancel()
+		b.ctx, b.cancel = nil, nil
+		return fmt.Errorf("error abriendo el navegador %s: %w", c.Command, err)
+	}
+	b.ctx, b.cancel = ctx, cancel
+	return nil
+}
+
+// Close cierra los navegadores y borra sus perfiles. Se llama cuando ya no
+// hay renderizados en curso; si después se vuelve a renderizar, se abren de
+// nuevo.
+func (c *Chrome) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var errs []error
+	for _, b := range c.all {
+		if b.cancel != nil {
+			b.cancel()
+			b.ctx, b.cancel = nil, nil
+		}
+		if b.dir != "" {
+			errs = append(errs, os.RemoveAll(b.dir))
+			b.dir = ""
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Render abre la página en una pestaña nueva y devuelve el DOM después de
+// correr sus scripts, con el estado HTTP, los encabezados y la URL final
+// (tras las redirecciones) del documento.
+func (c *Chrome) Render(ctx context.Context, pageURL string) (*Page, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultRenderTimeout
+	}
+	b, err := c.acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error renderizando %s: %w", pageURL, err)
+	}
+	defer func() { c.slots <- b }()
+
+	tab, closeTab := chromedp.NewContext(b.ctx)
+	defer closeTab()
+	tab, cancel := context.WithTimeout(tab, timeout)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	resp, err := chromedp.RunResponse(tab, chromedp.Navigate(pageURL))
+	if err == nil && resp == nil {
+		err = errors.New("el navegador no recibió el documento")
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("error renderizando %s: %w", pageURL, ctx.Err())
+		}
+		return nil, fmt.Errorf("error renderizando %s: %w", pageURL, err)
+	}
+	var html string
+	if err := chromedp.Run(tab,
+		chromedp.Sleep(min(renderSettle, timeout/2)),
+		chromedp.OuterHTML("html", &html, chromedp.ByQuery),
+	); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("error renderizando %s: %w", pageURL, ctx.Err())
+		}
+		return nil, fmt.Errorf("error leyendo el DOM de %s: %w", pageURL, err)
+	}
+	out := []byte(html)
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, errors.New("el navegador no devolvió la página " + pageURL)
+	}
+	if len(out) > maxBodySize {
+		out = out[:maxBodySize]
+	}
+	header := make(http.Header, len(resp.Headers))
+	for k, v := range resp.Headers {
+		header.Set(k, fmt.Sprint(v))
+	}
+	// El DOM volcado siempre es HTML en UTF-8, aunque el documento no lo fuera.
+	header.Set("Content-Type", "text/html; charset=utf-8")
+	final := resp.URL
+	if final == "" {
+		final = pageURL
+	}
+	page := &Page{
+		URL:        final,
+		StatusCode: int(resp.Status),
+		Header:     header,
+		Body:       out,
+	}
+	page.ConsentWall = IsConsentWall(page)
+	return page, nil
+}
+
+// RenderRecheck es cada cuánto un dominio que necesitó el navegador vuelve a
+// compararse con la descarga estática, por si el medio cambió.
+const RenderRecheck = 14 * 24 * time.Hour
+
+// RenderPaths recuerda por dominio si la descarga estática alcanza o hace
+// falta el navegador (storage.Store).
+type RenderPaths interface {
+	GetRenderPath(domain string) (*storage.RenderPath, error)
+	SaveRenderPath(p *storage.RenderPath) error
+}
+
+// Escalate vuelve a descargar con el navegador una página cuya versión
+// estática (body, extraída en res) no alcanza: depende de JavaScript o la
+// extracción no llega a extract.MinScore. Devuelve la versión con mejor
+// puntaje; si el navegador falla, la estática junto con el error. Sin
+// renderizador (r nil) devuelve la estática.
+//
+// Con paths, lo que resulta de comparar ambas versiones se recuerda por
+// dominio: los que necesitaron el navegador se renderizan aunque la versión
+// estática parezca alcanzar (hasta RenderRecheck después de la última
+// comparación), y los que funcionan sin él no se escalan por una nota corta.
+func Escalate(ctx context.Context, r Renderer, paths RenderPaths, e extract.Extractor, pageURL string, body []byte, res *extract.Result) ([]byte, *extract.Result, error) {
+	if r == nil {
+		return body, res, nil
+	}
+	domain := ""
+	if u, err := url.Parse(pageURL); err == nil {
+		domain = strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	}
+	var known *storage.RenderPath
+	if paths != nil && domain != "" {
+		p, err := paths.GetRenderPath(domain)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return body, res, err
+		}
+		known = p
+	}
+	remember := func(mode string, rendered float64) error {
+		if paths == nil || domain == "" {
+			return nil
+		}
+		return paths.SaveRenderPath(&storage.RenderPath{
+			Domain: domain, Mode: mode, StaticScore: res.Quality.Score, RenderScore: rendered, Checked: time.Now(),
+		})
+	}
+
+	knownRender := known != nil && known.Mode == storage.PathRender && time.Since(known.Checked) < RenderRecheck
+	knownStatic := known != nil && known.Mode == storage.PathStatic
+	weak := res.Quality.Score < extract.MinScore
+	if !knownRender && !JSDependent(&Page{Body: body}) && (!weak || knownStatic) {
+		if known == nil {
+			// Alcanza sin navegador: se recuerda solo si nunca se comparó,
+			// para no pisar lo que se aprendió de otras notas del dominio.
+			return body, res, remember(storage.PathStatic, 0)
+		}
+		return body, res, nil
+	}
+	page, err := r.Render(ctx, pageURL)
+	if err != nil {
+		// Sin navegador vale la descarga estática; el dominio no se marca
+		// hasta poder comparar.
+		return body, res, err
+	}
+	if page.StatusCode != http.StatusOK {
+		return body, res, fmt.Errorf("el navegador recibió el estado %d en %s", page.StatusCode, pageURL)
+	}
+	rres, err := e.Extract(page.URL, page.Body)
+	if err != nil {
+		return body, res, fmt.Errorf("error extrayendo %s: %w", pageURL, err)
+	}
+	if page.ConsentWall {
+		return body, res, nil
+	}
+	if rres.Quality.Score > res.Quality.Score {
+		return page.Body, rres, remember(storage.PathRender, rres.Quality.Score)
+	}
+	return body, res, remember(storage.PathStatic, rres.Quality.Score)
+}
+
+// mountPoints son los contenedores donde los frameworks de JavaScript arman
+// la página; vacíos en el HTML estático.
+var mountPoints = []string{"#root", "#app", "#__next", "#__nuxt", "[data-reactroot]", "app-root"}
+
+// JSDependent indica si la página descargada sin navegador depende de
+// JavaScript para mostrar la nota: casi no tiene texto visible y, o bien
+// pide activar JavaScript, o bien tiene un contenedor de framework vacío, o
+// bien es mayormente scripts.
+func JSDependent(p *Page) bool {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(p.Body))
+	if err != nil {
+		return false
+	}
+	scripts := 0
+	doc.Find("script").Each(func(_ int, s *goquery.Selection) {
+		scripts += len(s.Text())
+	})
+	noscript := strings.ToLower(doc.Find("noscript").Text())
+	doc.Find("script, style, noscript, template").Remove()
+	text := len(strings.Join(strings.Fields(doc.Find("body").Text()), " "))
+	if text >= minStaticText {
+		return false
+	}
+	if strings.Contains(noscript, "javascript") {
+		return true
+	}
+	for _, sel := range mountPoints {
+		if m := doc.Find(sel).First(); m.Length() > 0 && strings.TrimSpace(m.Text()) == "" {
+			return true
+		}
+	}
+	return scripts > 2*len(p.Body)/3
+}
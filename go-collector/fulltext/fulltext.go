@@ -0,0 +1,125 @@
+package fulltext
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"go-collector/article"
+	"go-collector/extract"
+	"go-collector/fetch"
+	"go-collector/rawarchive"
+	"go-collector/storage"
+)
+
+// Enricher descarga la página de cada artículo y guarda su texto completo.
+type Enricher struct {
+	Fetcher *fetch.Fetcher
+	Store   *storage.Store
+
+	// Archive, si no es nil, guarda además la página cruda de cada descarga.
+	Archive *rawarchive.Archive
+
+	// Extractor aplica las reglas por dominio (config extract.domains); el
+	// valor cero usa solo la extracción genérica.
+	Extractor extract.Extractor
+
+	// Renderer, si no es nil, es el navegador al que se escala cuando la
+	// descarga estática no alcanza (ver load).
+	Renderer fetch.Renderer
+}
+
+// NewEnricher crea un enriquecedor con la extracción genérica.
+func NewEnricher(fetcher *fetch.Fetcher, store *storage.Store) *Enricher {
+	return &Enricher{Fetcher: fetcher, Store: store}
+}
+
+// Enrich extrae el texto de un artículo. Si no se puede (muro de consentimiento,
+// página vacía, error HTTP) se marca el artículo con el motivo en ExtractionIssue.
+// El autor y la fecha de la página completan los que la fuente no dio.
+func (e *Enricher) Enrich(ctx context.Context, a *article.Article) error {
+	res, issue, err := e.extract(ctx, a)
+	if err != nil {
+		return err
+	}
+	a.Body, a.ExtractionIssue = res.Text, issue
+	if err := e.Store.UpdateBody(a.ID, res.Text, issue); err != nil {
+		return err
+	}
+	fill := a.Author == "" && res.Author != "" || a.Published.IsZero() && !res.Published.IsZero()
+	if !fill {
+		return nil
+	}
+	if a.Author == "" {
+		a.Author = res.Author
+	}
+	if a.Published.IsZero() {
+		a.Published = res.Published
+	}
+	return e.Store.FillMetadata(a)
+}
+
+func (e *Enricher) extract(ctx context.Context, a *article.Article) (*extract.Result, string, error) {
+	none := &extract.Result{}
+	page, res, err := e.load(ctx, a.URL)
+	if err != nil {
+		if ctx.Err() != nil {
+			// Cancelado: no es un problema del artículo, no se marca.
+			return nil, "", ctx.Err()
+		}
+		var xerr *extractError
+		if errors.As(err, &xerr) {
+			return nil, "", xerr.err
+		}
+		return none, article.IssueFetchFailed, nil
+	}
+	if err := e.archive(a.ID, page); err != nil {
+		return nil, "", err
+	}
+	if page.ConsentWall {
+		return none, article.IssueConsentWall, nil
+	}
+	if page.StatusCode != http.StatusOK {
+		return none, article.IssueFetchFailed, nil
+	}
+
+	if err := e.record(a.ID, res); err != nil {
+		return nil, "", err
+	}
+	switch {
+	case res.Text == "":
+		return res, article.IssueEmptyBody, nil
+	case res.Strategy == extract.StrategyDescription:
+		return res, article.IssueDescriptionOnly, nil
+	}
+	return res, "", nil
+}
+
+// record guarda qué estrategia de extracción ganó y con qué puntaje.
+func (e *Enricher) record(articleID int64, res *extract.Result) error {
+	x, err := storage.NewExtraction(articleID, res, time.Now())
+	if err != nil {
+		return err
+	}
+	return e.Store.SaveExtraction(x)
+}
+
+func (e *Enricher) archive(articleID int64, page *fetch.Page) error {
+	if e.Archive == nil {
+		return nil
+	}
+	sum, err := e.Archive.Put(page.Body)
+	if err != nil {
+		return err
+	}
+	return e.Store.SaveRawPayload(storage.RawPayload{
+		ArticleID:   articleID,
+		URL:         page.URL,
+		FetchedAt:   time.Now(),
+		StatusCode:  page.StatusCode,
+		ContentType: page.Header.Get("Content-Type"),
+		SHA256:      sum,
+		Size:        len(page.Body),
+	})
+}
@@ -0,0 +1,134 @@
+package fulltext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-collector/extract"
+	"go-collector/fetch"
+	"go-collector/storage"
+)
+
+// extractError es un error de la extracción (no de la descarga): no se
+// marca en el artículo sino que corta el enriquecimiento.
+type extractError struct{ err error }
+
+func (e *extractError) Error() string { return e.err.Error() }
+func (e *extractError) Unwrap() error { return e.err }
+
+// load descarga y extrae la página. Primero sin navegador; se escala al
+// Renderer solo si la página depende de JavaScript, o si la extracción no
+// llega a extract.MinScore en un dominio que no se sabe estático. Lo que
+// resulta de comparar ambos caminos se recuerda por dominio: los que
+// necesitan el navegador van directo a él (y cada fetch.RenderRecheck vuelven a
+// probar sin él), los que funcionan sin él no se escalan por una nota corta.
+// El resultado es nil si la página no sirve (muro de consentimiento, error
+// HTTP).
+func (e *Enricher) load(ctx context.Context, pageURL string) (*fetch.Page, *extract.Result, error) {
+	domain := ""
+	if u, err := url.Parse(pageURL); err == nil {
+		domain = strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	}
+	var known *storage.RenderPath
+	if e.Renderer != nil && domain != "" {
+		p, err := e.Store.GetRenderPath(domain)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, &extractError{err}
+		}
+		known = p
+	}
+
+	if known != nil && known.Mode == storage.PathRender && time.Since(known.Checked) < fetch.RenderRecheck {
+		page, res, err := e.render(ctx, pageURL)
+		if err == nil && res != nil {
+			return page, res, nil
+		}
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		// Si el navegador falla se intenta sin él, como cualquier dominio.
+		if err != nil {
+			log.Printf("render %s: %v", pageURL, err)
+		}
+	}
+
+	page, err := e.Fetcher.Fetch(ctx, pageURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := e.usable(page)
+	if err != nil || e.Renderer == nil || domain == "" {
+		return page, res, err
+	}
+	jsOnly := fetch.JSDependent(page)
+	weak := res == nil || res.Quality.Score < extract.MinScore
+	knownStatic := known != nil && known.Mode == storage.PathStatic
+	if !jsOnly && (!weak || knownStatic) {
+		if known == nil || known.Mode != storage.PathStatic {
+			return page, res, e.remember(domain, storage.PathStatic, score(res), 0)
+		}
+		return page, res, nil
+	}
+
+	rendered, rres, err := e.render(ctx, pageURL)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		// Sin navegador vale la descarga estática; el dominio no se marca
+		// hasta poder comparar.
+		log.Printf("render %s: %v", pageURL, err)
+		return page, res, nil
+	}
+	if score(rres) > score(res) {
+		return rendered, rres, e.remember(domain, storage.PathRender, score(res), score(rres))
+	}
+	return page, res, e.remember(domain, storage.PathStatic, score(res), score(rres))
+}
+
+// render descarga la página con el navegador y la extrae.
+func (e *Enricher) render(ctx context.Context, pageURL string) (*fetch.Page, *extract.Result, error) {
+	page, err := e.Renderer.Render(ctx, pageURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := e.usable(page)
+	return page, res, err
+}
+
+// usable extrae la página si sirve: nil sin error si es un muro de
+// consentimiento o no respondió 200.
+func (e *Enricher) usable(page *fetch.Page) (*extract.Result, error) {
+	if page.ConsentWall || page.StatusCode != http.StatusOK {
+		return nil, nil
+	}
+	res, err := e.Extractor.Extract(page.URL, page.Body)
+	if err != nil {
+		return nil, &extractError{fmt.Errorf("error extrayendo %s: %w", page.URL, err)}
+	}
+	return res, nil
+}
+
+func (e *Enricher) remember(domain, mode string, static, rendered float64) error {
+	err := e.Store.SaveRenderPath(&storage.RenderPath{
+		Domain: domain, Mode: mode, StaticScore: static, RenderScore: rendered, Checked: time.Now(),
+	})
+	if err != nil {
+		return &extractError{err}
+	}
+	return nil
+}
+
+// score es el puntaje de una extracción; 0 si la página no sirvió.
+func score(res *extract.Result) float64 {
+	if res == nil {
+		return 0
+	}
+	return res.Quality.Score
+}
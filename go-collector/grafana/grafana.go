@@ -0,0 +1,351 @@
+// Package grafana expone los agregados del corpus con la API del datasource
+// JSON de Grafana (simpod-json-datasource), que también puede consultar el
+// plugin Infinity apuntando a /query.
+package grafana
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go-collector/buildinfo"
+	"go-collector/storage"
+)
+
+// Métricas disponibles. Las de tipo tabla se devuelven como tabla aunque el
+// panel pida serie de tiempo.
+var metrics = []struct {
+	Name, Label string
+	Table       bool
+}{
+	{"articles", "Artículos por día", false},
+	{"articles_by_source", "Artículos por día y fuente", false},
+	{"articles_by_language", "Artículos por día e idioma", false},
+	{"withdrawn", "Artículos retirados por día", false},
+	{"daily_stats", "Agregados diarios (tabla)", true},
+	{"sources", "Totales por fuente (tabla)", true},
+	{"runs", "Rondas de recolección (tabla)", true},
+	{"source_holds", "Fuentes desactivadas (tabla)", true},
+}
+
+// refreshEvery limita cada cuánto se recalculan los agregados: un tablero con
+// varios paneles dispara varias consultas a la vez.
+const refreshEvery = time.Minute
+
+// Server atiende las consultas de Grafana.
+type Server struct {
+	Store *storage.Store
+	// Token, si no está vacío, se exige como "Authorization: Bearer <token>".
+	Token string
+	// Stamp va en los encabezados de todas las respuestas.
+	Stamp buildinfo.Stamp
+
+	mu          sync.Mutex
+	lastRefresh time.Time
+}
+
+// Handler devuelve las rutas del datasource: / (prueba de conexión), /metrics,
+// /search (API SimpleJSON anterior) y /query.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, "OK")
+	})
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	mux.HandleFunc("/search", s.handleSearch)
+	mux.HandleFunc("/query", s.handleQuery)
+	return s.auth(mux)
+}
+
+func (s *Server) auth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Stamp.SetHeaders(w.Header())
+		if s.Token != "" && !authorized(r, s.Token) {
+			http.Error(w, "no autorizado", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorized compara el encabezado con el token en tiempo constante, para
+// que la demora de la respuesta no revele cuánto del token se acertó.
+func authorized(r *http.Request, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) == 1
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	type option struct {
+		Label string `json:"label"`
+		Value string `json:"value"`
+	}
+	var out []option
+	for _, m := range metrics {
+		out = append(out, option{Label: m.Label, Value: m.Name})
+	}
+	writeJSON(w, out)
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	var out []string
+	for _, m := range metrics {
+		out = append(out, m.Name)
+	}
+	writeJSON(w, out)
+}
+
+// queryRequest es el cuerpo que envía Grafana a /query.
+type queryRequest struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	Targets []struct {
+		Target string `json:"target"`
+		RefID  string `json:"refId"`
+		Type   string `json:"type"`
+		Hide   bool   `json:"hide"`
+	} `json:"targets"`
+}
+
+type timeSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"` // [valor, milisegundos Unix]
+}
+
+type table struct {
+	Type    string   `json:"type"`
+	Columns []column `json:"columns"`
+	Rows    [][]any  `json:"rows"`
+}
+
+type column struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	var req queryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "consulta inválida: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Range.To.IsZero() {
+		req.Range.To = time.Now()
+	}
+	if req.Range.From.IsZero() {
+		req.Range.From = req.Range.To.AddDate(0, 0, -30)
+	}
+
+	if err := s.refresh(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	stats, err := s.Store.DailyStats(req.Range.From, req.Range.To)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	out := []any{}
+	for _, t := range req.Targets {
+		if t.Hide || t.Target == "" {
+			continue
+		}
+		switch t.Target {
+		case "articles":
+			out = append(out, series(stats, func(storage.DailyStat) string { return "articles" }, articles)...)
+		case "articles_by_source":
+			out = append(out, series(stats, func(st storage.DailyStat) string { return st.Source }, articles)...)
+		case "articles_by_language":
+			out = append(out, series(stats, func(st storage.DailyStat) string { return st.Language }, articles)...)
+		case "withdrawn":
+			out = append(out, series(stats, func(storage.DailyStat) string { return "withdrawn" }, withdrawn)...)
+		case "daily_stats":
+			out = append(out, dailyTable(stats))
+		case "sources":
+			out = append(out, sourcesTable(stats))
+		case "runs":
+			runs, err := s.Store.ListRuns(storage.RunFilter{Since: req.Range.From, Until: req.Range.To})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			out = append(out, runsTable(runs))
+		case "source_holds":
+			holds, err := s.Store.SourceHolds(time.Now())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			out = append(out, holdsTable(holds))
+		default:
+			http.Error(w, "métrica desconocida: "+t.Target, http.StatusBadRequest)
+			return
+		}
+	}
+	writeJSON(w, out)
+}
+
+// refresh recalcula los agregados de los últimos días si pasó refreshEvery
+// desde la última vez. Los días anteriores no cambian salvo por retiros, que
+// se recogen al recalcular todo el corpus una vez al arrancar.
+func (s *Server) refresh() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.lastRefresh) < refreshEvery {
+		return nil
+	}
+	from := time.Now().AddDate(0, 0, -2)
+	if s.lastRefresh.IsZero() {
+		from = time.Time{}
+	}
+	if err := s.Store.RefreshDailyStats(from); err != nil {
+		return err
+	}
+	s.lastRefresh = time.Now()
+	return nil
+}
+
+func articles(st storage.DailyStat) float64  { return float64(st.Articles) }
+func withdrawn(st storage.DailyStat) float64 { return float64(st.Withdrawn) }
+
+// series agrupa los agregados en una serie por clave, sumando por día.
+func series(stats []storage.DailyStat, key func(storage.DailyStat) string, value func(storage.DailyStat) float64) []any {
+	byKey := make(map[string]map[int64]float64)
+	for _, st := range stats {
+		k := key(st)
+		if k == "" {
+			k = "(desconocido)"
+		}
+		if byKey[k] == nil {
+			byKey[k] = make(map[int64]float64)
+		}
+		byKey[k][st.Day.UnixMilli()] += value(st)
+	}
+
+	keys := make([]string, 0, len(byKey))
+	for k := range byKey {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]any, 0, len(keys))
+	for _, k := range keys {
+		ts := timeSeries{Target: k, Datapoints: [][2]float64{}}
+		for ms, v := range byKey[k] {
+			ts.Datapoints = append(ts.Datapoints, [2]float64{v, float64(ms)})
+		}
+		sort.Slice(ts.Datapoints, func(i, j int) bool { return ts.Datapoints[i][1] < ts.Datapoints[j][1] })
+		out = append(out, ts)
+	}
+	return out
+}
+
+func dailyTable(stats []storage.DailyStat) table {
+	t := table{
+		Type: "table",
+		Columns: []column{
+			{"Día", "time"}, {"Fuente", "string"}, {"Idioma", "string"},
+			{"Artículos", "number"}, {"Retirados", "number"},
+		},
+		Rows: [][]any{},
+	}
+	for _, st := range stats {
+		t.Rows = append(t.Rows, []any{st.Day.UnixMilli(), st.Source, st.Language, st.Articles, st.Withdrawn})
+	}
+	return t
+}
+
+func sourcesTable(stats []storage.DailyStat) table {
+	type totals struct{ articles, withdrawn int }
+	bySource := make(map[string]*totals)
+	for _, st := range stats {
+		if bySource[st.Source] == nil {
+			bySource[st.Source] = &totals{}
+		}
+		bySource[st.Source].articles += st.Articles
+		bySource[st.Source].withdrawn += st.Withdrawn
+	}
+	sources := make([]string, 0, len(bySource))
+	for src := range bySource {
+		sources = append(sources, src)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		a, b := bySource[sources[i]], bySource[sources[j]]
+		if a.articles != b.articles {
+			return a.articles > b.articles
+		}
+		return sources[i] < sources[j]
+	})
+
+	t := table{
+		Type:    "table",
+		Columns: []column{{"Fuente", "string"}, {"Artículos", "number"}, {"Retirados", "number"}},
+		Rows:    [][]any{},
+	}
+	for _, src := range sources {
+		t.Rows = append(t.Rows, []any{src, bySource[src].articles, bySource[src].withdrawn})
+	}
+	return t
+}
+
+// runsTable lista las rondas del rango, las más recientes primero. Una ronda
+// sin fin es una que se interrumpió (o sigue corriendo).
+func runsTable(runs []storage.RunSummary) table {
+	t := table{
+		Type: "table",
+		Columns: []column{
+			{"Inicio", "time"}, {"Ronda", "number"}, {"Campaña", "string"}, {"Estado", "string"},
+			{"Duración (s)", "number"}, {"Fuentes", "number"}, {"Con error", "number"}, {"Omitidas", "number"},
+			{"Traídos", "number"}, {"Guardados", "number"}, {"Configuración", "string"}, {"Versión", "string"},
+			{"Error", "string"},
+		},
+		Rows: [][]any{},
+	}
+	for _, r := range runs {
+		t.Rows = append(t.Rows, []any{
+			r.Started.UnixMilli(), r.ID, r.Campaign, r.Status,
+			r.Duration().Seconds(), r.Sources, r.Failed, r.Skipped,
+			r.Fetched, r.Stored, r.ConfigHash, buildinfo.Recorded(r.Version, r.Commit, "").Label(), r.Err,
+		})
+	}
+	return t
+}
+
+// holdsTable lista las fuentes desactivadas en este momento, sin importar el
+// rango del panel. Hasta vacío es sin plazo.
+func holdsTable(holds []storage.SourceHold) table {
+	t := table{
+		Type: "table",
+		Columns: []column{
+			{"Fuente", "string"}, {"Desde", "time"}, {"Hasta", "time"}, {"Motivo", "string"}, {"Usuario", "string"},
+		},
+		Rows: [][]any{},
+	}
+	for _, h := range holds {
+		var until any
+		if !h.Until.IsZero() {
+			until = h.Until.UnixMilli()
+		}
+		t.Rows = append(t.Rows, []any{h.Source, h.Since.UnixMilli(), until, h.Reason, h.Actor})
+	}
+	return t
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
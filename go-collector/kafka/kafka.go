@@ -0,0 +1,328 @@
+// Package kafka publica en un tópico de Kafka los artículos que guarda cada
+// ronda, para que otros sistemas los consuman a medida que llegan. Habla el
+// protocolo de Kafka directamente (ver protocol.go), sin cliente externo: sin
+// TLS ni SASL, para brokers de la red propia.
+//
+// Cada artículo es un registro con la URL como clave (el mismo artículo cae
+// siempre en la misma partición, con el particionador por defecto de Kafka)
+// y como valor el mismo JSON que escribe output.jsonl; el encabezado source
+// lleva su fuente.
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"go-collector/article"
+	"go-collector/config"
+)
+
+const (
+	// DefaultTopic es el tópico si la configuración no lo define.
+	DefaultTopic = "collector-articles"
+	// clientID identifica al recolector en los logs del broker.
+	clientID = "go-collector"
+	// defaultTimeout acota cada conexión y pedido a un broker.
+	defaultTimeout = 30 * time.Second
+	// maxBatchBytes es el tamaño máximo de un lote, por debajo del
+	// message.max.bytes por defecto de los brokers (1 MB).
+	maxBatchBytes = 900 << 10
+	// attempts es cuántas veces se intenta publicar lo que el broker
+	// rechaza con un error transitorio (partición sin líder o que cambió).
+	attempts = 4
+	// retryDelay es la espera antes del primer reintento; se duplica en
+	// cada uno.
+	retryDelay = 500 * time.Millisecond
+)
+
+// Producer publica en un tópico.
+type Producer struct {
+	Brokers []string // host:puerto de arranque
+	Topic   string
+	Timeout time.Duration
+	// NoAuthor son las fuentes cuyo autor es un dato personal
+	// (storage.encryption.author_sources): no se publica.
+	NoAuthor map[string]bool
+	// Dial, si no es nil, reemplaza la conexión TCP a los brokers.
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	correlation atomic.Int32
+}
+
+// NewProducer arma el productor de la configuración, o nil si no hay
+// brokers configurados.
+func NewProducer(cfg config.Kafka, privateAuthors []string) (*Producer, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, nil
+	}
+	p := &Producer{Brokers: cfg.Brokers, Topic: cfg.Topic, Timeout: defaultTimeout, NoAuthor: make(map[string]bool)}
+	if p.Topic == "" {
+		p.Topic = DefaultTopic
+	}
+	for _, src := range privateAuthors {
+		p.NoAuthor[src] = true
+	}
+	return p, nil
+}
+
+// Publish publica los artículos y devuelve cuántos confirmó el broker (con
+// acks de todas las réplicas en sincronía). Lo que falla con un error
+// transitorio se reintenta; el error informa cuántos no se publicaron y el
+// motivo del primero.
+func (p *Producer) Publish(ctx context.Context, articles []*article.Article) (int, error) {
+	pending := make([]message, 0, len(articles))
+	for _, a := range articles {
+		m, err := p.message(a)
+		if err != nil {
+			return 0, err
+		}
+		pending = append(pending, m)
+	}
+
+	published, failed := 0, 0
+	var first error
+	delay := retryDelay
+	for attempt := 0; len(pending) > 0; attempt++ {
+		if attempt == attempts {
+			failed += len(pending)
+			break
+		}
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return published, ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+		md, err := p.metadata(ctx)
+		if err != nil {
+			return published, err
+		}
+		retry, bad, err := p.produce(ctx, md, pending)
+		published += len(pending) - len(retry) - bad
+		failed += bad
+		if err != nil && first == nil {
+			first = err
+		}
+		pending = retry
+	}
+	if failed > 0 {
+		return published, fmt.Errorf("%d artículos sin publicar en %s (el primero: %w)", failed, p.Topic, first)
+	}
+	return published, nil
+}
+
+// message arma el registro del artículo.
+func (p *Producer) message(a *article.Article) (message, error) {
+	if p.NoAuthor[a.Source] && a.Author != "" {
+		c := *a
+		c.Author = ""
+		a = &c
+	}
+	value, err := json.Marshal(a)
+	if err != nil {
+		return message{}, fmt.Errorf("error codificando %s: %w", a.URL, err)
+	}
+	return message{key: []byte(a.URL), value: value, headers: [][2]string{{"source", a.Source}}}, nil
+}
+
+// produce envía los mensajes a los líderes de sus particiones. Devuelve los
+// que conviene reintentar (error transitorio o partición sin líder) y cuántos
+// fallaron sin remedio, con el primer error.
+func (p *Producer) produce(ctx context.Context, md metadata, msgs []message) (retry []message, failed int, first error) {
+	note := func(err error) {
+		if first == nil {
+			first = err
+		}
+	}
+	n := len(md.partitions)
+	switch {
+	case md.err != 0 && !retriable(md.err):
+		return nil, len(msgs), kafkaError(p.Topic, -1, md.err)
+	case md.err != 0 || n == 0:
+		return msgs, 0, fmt.Errorf("tópico %s sin particiones disponibles", p.Topic)
+	}
+	leaders := make(map[int32]int32, n)
+	for _, part := range md.partitions {
+		leaders[part.id] = part.leader
+	}
+	byPartition := make(map[int32][]message)
+	for _, m := range msgs {
+		id := (murmur2(m.key) & 0x7fffffff) % int32(n)
+		byPartition[id] = append(byPartition[id], m)
+	}
+	for id, ms := range byPartition {
+		addr, ok := md.brokers[leaders[id]]
+		if !ok {
+			retry = append(retry, ms...)
+			note(fmt.Errorf("tópico %s: partición %d sin líder", p.Topic, id))
+			continue
+		}
+		for _, chunk := range chunks(ms) {
+			code, err := p.send(ctx, addr, id, chunk)
+			switch {
+			case err != nil:
+				// Sin respuesta del líder no se sabe si quedaron escritos:
+				// se reintentan, a lo sumo duplicados.
+				retry = append(retry, chunk...)
+				note(err)
+			case code == 0:
+			case retriable(code):
+				retry = append(retry, chunk...)
+				note(kafkaError(p.Topic, id, code))
+			default:
+				failed += len(chunk)
+				note(kafkaError(p.Topic, id, code))
+			}
+		}
+	}
+	return retry, failed, first
+}
+
+func kafkaError(topic string, partitionID int32, code int16) error {
+	if partitionID < 0 {
+		return fmt.Errorf("tópico %s: error de Kafka %d", topic, code)
+	}
+	return fmt.Errorf("tópico %s, partición %d: error de Kafka %d", topic, partitionID, code)
+}
+
+// chunks parte los mensajes en lotes de hasta maxBatchBytes.
+func chunks(msgs []message) [][]message {
+	var out [][]message
+	start, size := 0, 0
+	for i, m := range msgs {
+		n := len(m.key) + len(m.value) + 64
+		if i > start && size+n > maxBatchBytes {
+			out = append(out, msgs[start:i])
+			start, size = i, 0
+		}
+		size += n
+	}
+	return append(out, msgs[start:])
+}
+
+func retriable(code int16) bool {
+	return code == errLeaderNotAvailable || code == errNotLeaderForPartition || code == errUnknownTopicOrPartition
+}
+
+// metadata pide la metadata del tópico al primer broker de arranque que
+// responda.
+func (p *Producer) metadata(ctx context.Context) (metadata, error) {
+	var errs []error
+	for _, addr := range p.Brokers {
+		var md metadata
+		err := p.roundTrip(ctx, addr, apiMetadata, metadataVersion, metadataRequest(p.Topic), func(d *decoder) error {
+			var err error
+			md, err = parseMetadata(d, p.Topic)
+			return err
+		})
+		if err == nil {
+			return md, nil
+		}
+		if ctx.Err() != nil {
+			return md, ctx.Err()
+		}
+		errs = append(errs, err)
+	}
+	return metadata{}, fmt.Errorf("ningún broker de Kafka respondió: %w", errors.Join(errs...))
+}
+
+// send publica un lote en la partición y devuelve el código de error del
+// broker (0 si lo escribió).
+func (p *Producer) send(ctx context.Context, addr string, partitionID int32, msgs []message) (int16, error) {
+	body := produceRequest(p.Topic, map[int32][]byte{partitionID: recordBatch(msgs, time.Now())}, p.timeout())
+	var code int16
+	err := p.roundTrip(ctx, addr, apiProduce, produceVersion, body, func(d *decoder) error {
+		errs, err := parseProduce(d)
+		if err != nil {
+			return err
+		}
+		c, ok := errs[partitionID]
+		if !ok {
+			return fmt.Errorf("respuesta de Kafka sin la partición %d", partitionID)
+		}
+		code = c
+		return nil
+	})
+	return code, err
+}
+
+// roundTrip abre una conexión al broker, envía la petición y lee su
+// respuesta con parse.
+func (p *Producer) roundTrip(ctx context.Context, addr string, api, version int16, body []byte, parse func(*decoder) error) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout())
+	defer cancel()
+	dial := p.Dial
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	conn, err := dial(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("error conectando a %s: %w", addr, err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	correlation := p.correlation.Add(1)
+	if _, err := conn.Write(request(api, version, correlation, clientID, body)); err != nil {
+		return fmt.Errorf("error enviando a %s: %w", addr, err)
+	}
+	d, err := readResponse(conn, correlation)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%s: %w", addr, err)
+	}
+	return parse(d)
+}
+
+func (p *Producer) timeout() time.Duration {
+	if p.Timeout > 0 {
+		return p.Timeout
+	}
+	return defaultTimeout
+}
+
+// murmur2 es el hash del particionador por defecto de Kafka, para que los
+// artículos caigan en las mismas particiones que con los clientes oficiales.
+func murmur2(data []byte) int32 {
+	const (
+		seed = 0x9747b28c
+		m    = 0x5bd1e995
+		r    = 24
+	)
+	n := len(data)
+	h := uint32(seed) ^ uint32(n)
+	for i := 0; i+4 <= n; i += 4 {
+		k := uint32(data[i]) | uint32(data[i+1])<<8 | uint32(data[i+2])<<16 | uint32(data[i+3])<<24
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+	tail := n &^ 3
+	switch n % 4 {
+	case 3:
+		h ^= uint32(data[tail+2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(data[tail+1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(data[tail])
+		h *= m
+	}
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
@@ -0,0 +1,333 @@
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	"go-collector/article"
+)
+
+// Los valores de Utils.murmur2 del cliente de Java (UtilsTest).
+func TestMurmur2(t *testing.T) {
+	cases := map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+		"abc": 479470107,
+	}
+	for in, want := range cases {
+		if got := murmur2([]byte(in)); got != want {
+			t.Errorf("murmur2(%q) = %d, se esperaba %d", in, got, want)
+		}
+	}
+}
+
+// record es un registro que recibió el broker falso.
+type record struct {
+	partition int32
+	key       string
+	value     []byte
+	source    string
+}
+
+// broker es un broker de Kafka falso con un tópico de dos particiones, las
+// dos con líder en él. Las primeras leaderless respuestas a Metadata no
+// tienen líder, como recién creado el tópico.
+type broker struct {
+	t          *testing.T
+	ln         net.Listener
+	topic      string
+	leaderless int
+
+	mu       sync.Mutex
+	metadata int
+	records  []record
+}
+
+func newBroker(t *testing.T, topic string) *broker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &broker{t: t, ln: ln, topic: topic}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *broker) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		buf := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return
+		}
+		d := &decoder{buf: buf}
+		api, version, correlation := d.int16(), d.int16(), d.int32()
+		if client := d.string(); client != clientID {
+			b.t.Errorf("client id %q", client)
+		}
+		var body []byte
+		switch {
+		case api == apiMetadata && version == metadataVersion:
+			body = b.metadataResponse(d)
+		case api == apiProduce && version == produceVersion:
+			body = b.produceResponse(d)
+		default:
+			b.t.Errorf("petición inesperada: api %d v%d", api, version)
+			return
+		}
+		if d.err != nil {
+			b.t.Errorf("petición %d mal formada: %v", api, d.err)
+			return
+		}
+		var e encoder
+		e.int32(int32(4 + len(body)))
+		e.int32(correlation)
+		e.buf = append(e.buf, body...)
+		if _, err := conn.Write(e.buf); err != nil {
+			return
+		}
+	}
+}
+
+func (b *broker) metadataResponse(d *decoder) []byte {
+	for range d.array() {
+		if name := d.string(); name != b.topic {
+			b.t.Errorf("metadata de %q, se esperaba %q", name, b.topic)
+		}
+	}
+	b.mu.Lock()
+	b.metadata++
+	leader := int32(1)
+	if b.metadata <= b.leaderless {
+		leader = -1
+	}
+	b.mu.Unlock()
+
+	host, port, _ := net.SplitHostPort(b.ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	var e encoder
+	e.int32(1) // brokers
+	e.int32(1)
+	e.string(host)
+	e.int32(int32(p))
+	e.int16(-1) // rack
+	e.int32(1)  // controller
+	e.int32(1)  // tópicos
+	e.int16(0)
+	e.string(b.topic)
+	e.int8(0)
+	e.int32(2) // particiones
+	for id := range int32(2) {
+		code := int16(0)
+		if leader < 0 {
+			code = errLeaderNotAvailable
+		}
+		e.int16(code)
+		e.int32(id)
+		e.int32(leader)
+		e.int32(1) // réplicas
+		e.int32(1)
+		e.int32(1) // ISR
+		e.int32(1)
+	}
+	return e.buf
+}
+
+func (b *broker) produceResponse(d *decoder) []byte {
+	if txn := d.int16(); txn != -1 {
+		b.t.Errorf("transactional id de largo %d", txn)
+	}
+	if acks := d.int16(); acks != -1 {
+		b.t.Errorf("acks %d, se esperaba -1", acks)
+	}
+	d.int32() // timeout
+	var e encoder
+	e.int32(1)
+	e.string(b.topic)
+	for range d.array() {
+		if name := d.string(); name != b.topic {
+			b.t.Errorf("produce en %q", name)
+		}
+		n := d.array()
+		e.int32(int32(n))
+		for range n {
+			id := d.int32()
+			batch := d.take(int(d.int32()))
+			b.batch(id, batch)
+			e.int32(id)
+			e.int16(0)
+			e.int64(0)  // offset base
+			e.int64(-1) // hora de escritura
+		}
+	}
+	e.int32(0) // throttle
+	return e.buf
+}
+
+// batch decodifica un lote de registros v2 y verifica su CRC.
+func (b *broker) batch(partitionID int32, buf []byte) {
+	d := &decoder{buf: buf}
+	d.int64() // base offset
+	if n := d.int32(); int(n) != len(d.buf) {
+		b.t.Errorf("largo del lote %d, quedan %d bytes", n, len(d.buf))
+	}
+	d.int32() // epoch del líder
+	if magic := d.int8(); magic != 2 {
+		b.t.Errorf("magic %d", magic)
+	}
+	crc := uint32(d.int32())
+	if got := crc32.Checksum(d.buf, castagnoli); got != crc {
+		b.t.Errorf("CRC %08x, el lote da %08x", crc, got)
+	}
+	d.int16()         // atributos
+	last := d.int32() // último delta de offset
+	d.int64()
+	d.int64()
+	d.int64()
+	d.int16()
+	d.int32()
+	count := d.int32()
+	if last != count-1 {
+		b.t.Errorf("último delta %d con %d registros", last, count)
+	}
+	varint := func() int64 {
+		v, n := binary.Varint(d.buf)
+		if n <= 0 {
+			d.err = errShort
+			return 0
+		}
+		d.buf = d.buf[n:]
+		return v
+	}
+	varbytes := func() []byte {
+		n := varint()
+		if n < 0 {
+			return nil
+		}
+		return d.take(int(n))
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for range count {
+		varint() // largo
+		d.int8()
+		varint()
+		varint()
+		r := record{partition: partitionID, key: string(varbytes()), value: varbytes()}
+		for range varint() {
+			if k, v := string(varbytes()), string(varbytes()); k == "source" {
+				r.source = v
+			}
+		}
+		b.records = append(b.records, r)
+	}
+	if d.err != nil || len(d.buf) != 0 {
+		b.t.Errorf("lote mal formado: %v, %d bytes de más", d.err, len(d.buf))
+	}
+}
+
+// El productor espera a que el tópico tenga líder, reparte los artículos
+// entre las particiones por su URL y cada registro lleva el JSON del
+// artículo, sin el autor de las fuentes que lo protegen.
+func TestPublish(t *testing.T) {
+	b := newBroker(t, "notas")
+	b.leaderless = 1
+	p := &Producer{Brokers: []string{b.ln.Addr().String()}, Topic: "notas", NoAuthor: map[string]bool{"bluesky": true}}
+
+	var articles []*article.Article
+	for i := range 20 {
+		source := "rss"
+		if i%2 == 1 {
+			source = "bluesky"
+		}
+		articles = append(articles, &article.Article{
+			Source: source,
+			Title:  fmt.Sprintf("Nota %d", i),
+			URL:    fmt.Sprintf("https://www.udea.edu.co/nota/%d", i),
+			Author: "Ana Pérez",
+		})
+	}
+	n, err := p.Publish(context.Background(), articles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(articles) {
+		t.Errorf("%d publicados, se esperaban %d", n, len(articles))
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.metadata != 2 {
+		t.Errorf("%d pedidos de metadata, se esperaban 2 (uno sin líder)", b.metadata)
+	}
+
+	got := make(map[string]record)
+	for _, r := range b.records {
+		got[r.key] = r
+	}
+	if len(got) != len(articles) || len(b.records) != len(articles) {
+		t.Fatalf("%d registros (%d claves distintas), se esperaban %d", len(b.records), len(got), len(articles))
+	}
+	partitions := make(map[int32]bool)
+	for _, a := range articles {
+		r := got[a.URL]
+		if want := (murmur2([]byte(a.URL)) & 0x7fffffff) % 2; r.partition != want {
+			t.Errorf("%s en la partición %d, se esperaba %d", a.URL, r.partition, want)
+		}
+		partitions[r.partition] = true
+		var v article.Article
+		if err := json.Unmarshal(r.value, &v); err != nil {
+			t.Fatalf("%s: %v", a.URL, err)
+		}
+		if v.Title != a.Title || r.source != a.Source {
+			t.Errorf("%s: título %q y fuente %q", a.URL, v.Title, r.source)
+		}
+		if wantAuthor := map[bool]string{true: "", false: "Ana Pérez"}[a.Source == "bluesky"]; v.Author != wantAuthor {
+			t.Errorf("%s (%s): autor %q, se esperaba %q", a.URL, a.Source, v.Author, wantAuthor)
+		}
+	}
+	if len(partitions) != 2 {
+		t.Errorf("todos los artículos en una partición")
+	}
+	if articles[1].Author == "" {
+		t.Error("Publish modificó el artículo")
+	}
+}
+
+// Sin broker que responda, Publish falla sin publicar nada.
+func TestPublishUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	p := &Producer{Brokers: []string{addr}, Topic: "notas"}
+	n, err := p.Publish(context.Background(), []*article.Article{{Source: "rss", URL: "https://www.udea.edu.co/nota/1"}})
+	if err == nil || n != 0 {
+		t.Errorf("Publish = %d, %v; se esperaba un error", n, err)
+	}
+}
@@ -0,0 +1,319 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Lo mínimo del protocolo de Kafka para publicar: Metadata v1, para saber el
+// líder de cada partición, y Produce v3 con lotes de registros v2 (Kafka
+// 0.11 o posterior), sin compresión ni transacciones.
+
+const (
+	apiProduce  = 0
+	apiMetadata = 3
+
+	produceVersion  = 3
+	metadataVersion = 1
+)
+
+// Códigos de error de Kafka que se reintentan: la metadata todavía no tiene
+// líder (p. ej. el tópico se acaba de crear) o cambió.
+const (
+	errLeaderNotAvailable      = 5
+	errNotLeaderForPartition   = 6
+	errUnknownTopicOrPartition = 3
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// encoder arma un mensaje del protocolo (enteros big-endian, cadenas con
+// largo int16, bytes con largo int32).
+type encoder struct{ buf []byte }
+
+func (e *encoder) int8(v int8)   { e.buf = append(e.buf, byte(v)) }
+func (e *encoder) int16(v int16) { e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v)) }
+func (e *encoder) int32(v int32) { e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v)) }
+func (e *encoder) int64(v int64) { e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v)) }
+func (e *encoder) varint(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+func (e *encoder) string(s string) {
+	e.int16(int16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) bytes(b []byte) {
+	e.int32(int32(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// varbytes son bytes con largo varint, o nil con largo -1 (claves y valores
+// de los registros).
+func (e *encoder) varbytes(b []byte) {
+	if b == nil {
+		e.varint(-1)
+		return
+	}
+	e.varint(int64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// decoder lee una respuesta; el primer error queda en err y las lecturas
+// siguientes devuelven cero.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+var errShort = errors.New("respuesta de Kafka truncada")
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.buf) {
+		d.err = errShort
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) int8() int8 {
+	if b := d.take(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (d *decoder) int16() int16 {
+	if b := d.take(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *decoder) int32() int32 {
+	if b := d.take(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *decoder) int64() int64 {
+	if b := d.take(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+// string lee una cadena; una nula (largo -1) es "".
+func (d *decoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.take(int(n)))
+}
+
+// array lee el largo de un arreglo; uno nulo tiene 0 elementos.
+func (d *decoder) array() int {
+	n := d.int32()
+	if n < 0 {
+		return 0
+	}
+	if int(n) > len(d.buf) {
+		d.err = errShort
+		return 0
+	}
+	return int(n)
+}
+
+// message es un registro a publicar.
+type message struct {
+	key, value []byte
+	headers    [][2]string
+}
+
+// recordBatch codifica los mensajes en un lote de registros v2 con la hora
+// now. Su CRC-32C cubre desde los atributos hasta el final.
+func recordBatch(msgs []message, now time.Time) []byte {
+	ts := now.UnixMilli()
+	var recs encoder
+	for i, m := range msgs {
+		var r encoder
+		r.int8(0)          // atributos
+		r.varint(0)        // delta de timestamp
+		r.varint(int64(i)) // delta de offset
+		r.varbytes(m.key)
+		r.varbytes(m.value)
+		r.varint(int64(len(m.headers)))
+		for _, h := range m.headers {
+			r.varbytes([]byte(h[0]))
+			r.varbytes([]byte(h[1]))
+		}
+		recs.varint(int64(len(r.buf)))
+		recs.buf = append(recs.buf, r.buf...)
+	}
+
+	var tail encoder // lo que cubre el CRC
+	tail.int16(0)    // atributos: sin compresión, hora de creación
+	tail.int32(int32(len(msgs) - 1))
+	tail.int64(ts)
+	tail.int64(ts)
+	tail.int64(-1) // producer id
+	tail.int16(-1) // producer epoch
+	tail.int32(-1) // base sequence
+	tail.int32(int32(len(msgs)))
+	tail.buf = append(tail.buf, recs.buf...)
+
+	var b encoder
+	b.int64(0)                                // base offset
+	b.int32(int32(4 + 1 + 4 + len(tail.buf))) // largo desde el epoch del líder
+	b.int32(-1)                               // epoch del líder
+	b.int8(2)                                 // magic
+	b.int32(int32(crc32.Checksum(tail.buf, castagnoli)))
+	b.buf = append(b.buf, tail.buf...)
+	return b.buf
+}
+
+// request arma una petición con su encabezado v1 y el largo al frente.
+func request(api, version int16, correlation int32, clientID string, body []byte) []byte {
+	var e encoder
+	e.int32(0) // largo, se completa al final
+	e.int16(api)
+	e.int16(version)
+	e.int32(correlation)
+	e.string(clientID)
+	e.buf = append(e.buf, body...)
+	binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+	return e.buf
+}
+
+// maxResponse acota el largo de una respuesta, para no reservar memoria por
+// un largo corrupto.
+const maxResponse = 64 << 20
+
+// readResponse lee una respuesta, verifica su correlation id y devuelve el
+// cuerpo.
+func readResponse(r io.Reader, correlation int32) (*decoder, error) {
+	var head [8]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta de Kafka: %w", err)
+	}
+	size := int32(binary.BigEndian.Uint32(head[:4]))
+	if size < 4 || size > maxResponse {
+		return nil, fmt.Errorf("respuesta de Kafka inválida: largo %d", size)
+	}
+	if got := int32(binary.BigEndian.Uint32(head[4:])); got != correlation {
+		return nil, fmt.Errorf("respuesta de Kafka inválida: correlation id %d, se esperaba %d", got, correlation)
+	}
+	body := make([]byte, size-4)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta de Kafka: %w", err)
+	}
+	return &decoder{buf: body}, nil
+}
+
+// metadata es lo que interesa de la respuesta a Metadata para un tópico: la
+// dirección de cada broker y el líder de cada partición.
+type metadata struct {
+	brokers    map[int32]string
+	partitions []partition
+	err        int16 // error del tópico
+}
+
+type partition struct {
+	id, leader int32
+	err        int16
+}
+
+func metadataRequest(topic string) []byte {
+	var e encoder
+	e.int32(1)
+	e.string(topic)
+	return e.buf
+}
+
+func parseMetadata(d *decoder, topic string) (metadata, error) {
+	md := metadata{brokers: make(map[int32]string)}
+	for range d.array() {
+		id := d.int32()
+		host := d.string()
+		port := d.int32()
+		d.string() // rack
+		md.brokers[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.int32() // controller
+	found := false
+	for range d.array() {
+		errCode := d.int16()
+		name := d.string()
+		d.int8() // interno
+		var parts []partition
+		for range d.array() {
+			p := partition{err: d.int16(), id: d.int32(), leader: d.int32()}
+			for range d.array() {
+				d.int32() // réplicas
+			}
+			for range d.array() {
+				d.int32() // ISR
+			}
+			parts = append(parts, p)
+		}
+		if name == topic {
+			found = true
+			md.err, md.partitions = errCode, parts
+		}
+	}
+	if d.err != nil {
+		return md, d.err
+	}
+	if !found {
+		md.err = errUnknownTopicOrPartition
+	}
+	return md, nil
+}
+
+// produceRequest publica en el tópico un lote por partición, esperando la
+// confirmación de todas las réplicas en sincronía.
+func produceRequest(topic string, batches map[int32][]byte, timeout time.Duration) []byte {
+	var e encoder
+	e.int16(-1) // sin transacción
+	e.int16(-1) // acks: todas las réplicas en sincronía
+	e.int32(int32(timeout.Milliseconds()))
+	e.int32(1)
+	e.string(topic)
+	e.int32(int32(len(batches)))
+	for p, b := range batches {
+		e.int32(p)
+		e.bytes(b)
+	}
+	return e.buf
+}
+
+// parseProduce devuelve el error de cada partición de la respuesta (0 si se
+// publicó).
+func parseProduce(d *decoder) (map[int32]int16, error) {
+	errs := make(map[int32]int16)
+	for range d.array() {
+		d.string() // tópico
+		for range d.array() {
+			p := d.int32()
+			errs[p] = d.int16()
+			d.int64() // offset base
+			d.int64() // hora de escritura
+		}
+	}
+	d.int32() // throttle
+	return errs, d.err
+}
@@ -0,0 +1,126 @@
+// Package lang detecta el idioma de un texto para los artículos cuya fuente
+// no lo informa (NewsAPI, X, feeds RSS sin <language>).
+//
+// El modelo es deliberadamente simple: cuenta las palabras funcionales
+// (artículos, preposiciones, pronombres) de cada idioma, que en un texto
+// periodístico son muchas y casi no se comparten. Alcanza para distinguir los
+// idiomas del corpus con un titular y un resumen; lo que no se puede decidir
+// queda como Undetermined.
+package lang
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Undetermined es el código ISO 639 de idioma no determinado.
+const Undetermined = "und"
+
+// minScore es la evidencia mínima para decidir: con menos (un nombre propio,
+// un hashtag) el texto no tiene palabras funcionales.
+const minScore = 1
+
+// stopwords son las palabras funcionales más frecuentes de cada idioma. Las
+// que se escriben igual en varios (ej: "de", "la", "que") aparecen en todos
+// ellos y reparten su peso.
+var stopwords = map[string][]string{
+	"es": {"el", "la", "los", "las", "de", "del", "al", "a", "y", "en", "que", "por", "para", "con", "una", "un", "es", "su", "sus",
+		"se", "lo", "como", "más", "pero", "fue", "este", "esta", "entre", "sobre", "también", "ha", "han", "muy", "sin", "hasta",
+		"desde", "porque", "cuando", "según", "no"},
+	"en": {"the", "and", "of", "to", "in", "is", "that", "for", "on", "with", "as", "was", "by", "at", "from", "it", "its",
+		"his", "her", "are", "be", "this", "have", "has", "an", "a", "which", "were", "after", "over", "who", "will", "their",
+		"not", "been", "said", "into", "about", "new"},
+	"pt": {"o", "os", "a", "as", "de", "do", "da", "dos", "das", "no", "na", "nos", "nas", "em", "e", "que", "um", "uma", "com",
+		"não", "para", "ao", "pelo", "pela", "mais", "foi", "também", "seu", "sua", "é", "ele", "ela", "isso", "depois", "disse",
+		"está", "são", "se"},
+	"fr": {"le", "la", "les", "de", "des", "du", "et", "est", "un", "une", "dans", "en", "que", "pour", "pas", "sur", "au", "aux",
+		"avec", "qui", "il", "elle", "ce", "cette", "sont", "ont", "été", "mais", "ou", "leur", "selon", "après", "plus", "nous",
+		"vous", "à", "se"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "mit", "den", "dem", "des", "ein", "eine", "einen", "auf", "für", "in",
+		"von", "zu", "im", "sich", "auch", "wird", "wurde", "sind", "hat", "nach", "bei", "aus", "noch", "über", "dass", "oder"},
+	"it": {"il", "lo", "la", "le", "gli", "di", "della", "delle", "dei", "degli", "e", "che", "è", "per", "con", "non", "un",
+		"una", "nel", "nella", "in", "a", "sono", "alla", "al", "anche", "più", "ha", "dal", "dalla", "questo", "questa",
+		"stato", "dopo", "come", "ma", "tra", "sulla", "sul"},
+}
+
+// suffixes son terminaciones típicas de cada idioma; dan evidencia en los
+// titulares cortos, donde casi no hay palabras funcionales.
+var suffixes = map[string][]string{
+	"ción": {"es"}, "ciones": {"es"}, "mente": {"es", "it"}, "dad": {"es"},
+	"ção": {"pt"}, "ções": {"pt"}, "dade": {"pt"},
+	"tion": {"en", "fr"}, "ing": {"en"}, "ness": {"en"},
+	"eux": {"fr"}, "ité": {"fr"},
+	"ung": {"de"}, "keit": {"de"}, "heit": {"de"},
+	"zione": {"it"}, "zioni": {"it"}, "ità": {"it"},
+}
+
+var index = func() map[string][]string {
+	idx := map[string][]string{}
+	for code, words := range stopwords {
+		for _, w := range words {
+			idx[w] = append(idx[w], code)
+		}
+	}
+	return idx
+}()
+
+// Detect devuelve el código ISO 639-1 del idioma del texto, o Undetermined
+// si no tiene palabras funcionales o dos idiomas quedan empatados.
+func Detect(text string) string {
+	scores := map[string]float64{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		codes := index[w]
+		if len(codes) == 0 {
+			codes = suffixOf(w)
+		}
+		if len(codes) == 0 {
+			continue
+		}
+		// Una palabra compartida por varios idiomas reparte su peso.
+		for _, c := range codes {
+			scores[c] += 1 / float64(len(codes))
+		}
+	}
+	best, second := "", 0.0
+	for c, s := range scores {
+		switch {
+		case best == "" || s > scores[best]:
+			second, best = scores[best], c
+		case s > second:
+			second = s
+		}
+	}
+	if scores[best] < minScore || scores[best] == second {
+		return Undetermined
+	}
+	return best
+}
+
+// IsStopword indica si la palabra es funcional (artículo, preposición,
+// pronombre) en alguno de los idiomas conocidos.
+func IsStopword(w string) bool {
+	return len(index[strings.ToLower(w)]) > 0
+}
+
+// suffixOf devuelve los idiomas de la terminación de la palabra, si tiene una
+// típica.
+func suffixOf(w string) []string {
+	for suffix, codes := range suffixes {
+		if len(w) > len(suffix)+2 && strings.HasSuffix(w, suffix) {
+			return codes
+		}
+	}
+	return nil
+}
+
+// Normalize lleva un código de idioma de la fuente (ej: "en-US", "ES",
+// "pt_BR") a ISO 639-1 en minúsculas.
+func Normalize(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	return code
+}
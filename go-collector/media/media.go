@@ -0,0 +1,187 @@
+// Package media descarga los archivos asociados a los artículos (enclosures de
+// RSS, la imagen social de GDELT) y detecta su tipo real por los primeros
+// bytes: las fuentes suelen declararlo mal o no declararlo, y a veces la URL
+// devuelve una página de error en vez del archivo.
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-collector/crawler"
+)
+
+// sniffLen es cuántos bytes se miran para detectar el tipo, los mismos que
+// http.DetectContentType.
+const sniffLen = 512
+
+// DefaultMaxSize es el tamaño máximo de un archivo (un podcast largo entra).
+const DefaultMaxSize = 200 << 20
+
+// ErrRejected indica que lo descargado no es un archivo multimedia válido: se
+// corta la descarga en cuanto se detecta.
+var ErrRejected = errors.New("archivo rechazado")
+
+// signature es un tipo que http.DetectContentType no reconoce (o confunde).
+type signature struct {
+	typ   string
+	match func(b []byte) bool
+}
+
+var signatures = []signature{
+	{"image/svg+xml", isSVG},
+	// ISO BMFF: la marca de "ftyp" distingue imágenes y audio de video.
+	{"image/avif", ftyp("avif", "avis")},
+	{"image/heic", ftyp("heic", "heix", "mif1")},
+	{"audio/mp4", ftyp("M4A ", "M4B ")},
+	{"video/quicktime", ftyp("qt  ")},
+	// AAC en ADTS y MP3 sin etiqueta ID3 empiezan con la sincronía del frame.
+	{"audio/aac", func(b []byte) bool { return len(b) > 1 && b[0] == 0xFF && b[1]&0xF6 == 0xF0 }},
+	{"audio/mpeg", func(b []byte) bool { return len(b) > 1 && b[0] == 0xFF && b[1]&0xE0 == 0xE0 && b[1]&0x06 != 0 }},
+}
+
+func ftyp(brands ...string) func(b []byte) bool {
+	return func(b []byte) bool {
+		if len(b) < 12 || string(b[4:8]) != "ftyp" {
+			return false
+		}
+		for _, brand := range brands {
+			if string(b[8:12]) == brand {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func isSVG(b []byte) bool {
+	b = bytes.TrimLeft(b, "\ufeff \t\r\n")
+	return bytes.HasPrefix(b, []byte("<svg")) ||
+		(bytes.HasPrefix(b, []byte("<?xml")) && bytes.Contains(b, []byte("<svg")))
+}
+
+// Sniff devuelve el tipo MIME de un archivo por sus primeros bytes, o "" si
+// no es una imagen, audio, video o PDF reconocible.
+func Sniff(head []byte) string {
+	if len(head) > sniffLen {
+		head = head[:sniffLen]
+	}
+	for _, s := range signatures {
+		if s.match(head) {
+			return s.typ
+		}
+	}
+	t, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if isMedia(t) {
+		return t
+	}
+	return ""
+}
+
+func isMedia(t string) bool {
+	return strings.HasPrefix(t, "image/") || strings.HasPrefix(t, "audio/") || strings.HasPrefix(t, "video/") ||
+		t == "application/pdf" || t == "application/ogg"
+}
+
+// Resolve decide el tipo de un archivo a partir del declarado por la fuente y
+// sus primeros bytes: manda lo detectado, salvo que el declarado precise el
+// contenido de un contenedor ambiguo (audio en MP4 o WebM). Lo que no es
+// multimedia se rechaza con ErrRejected.
+func Resolve(declared string, head []byte) (string, error) {
+	declared = strings.ToLower(strings.TrimSpace(declared))
+	declared, _, _ = strings.Cut(declared, ";")
+	sniffed := Sniff(head)
+	if sniffed == "" {
+		if len(head) == 0 {
+			return "", fmt.Errorf("%w: archivo vacío", ErrRejected)
+		}
+		got, _, _ := strings.Cut(http.DetectContentType(head), ";")
+		if got == "text/html" {
+			return "", fmt.Errorf("%w: el servidor devolvió una página HTML, no un archivo", ErrRejected)
+		}
+		return "", fmt.Errorf("%w: contenido no reconocido (%s)", ErrRejected, got)
+	}
+	if strings.HasPrefix(declared, "audio/") && (sniffed == "video/mp4" || sniffed == "video/webm") {
+		return "audio/" + strings.TrimPrefix(sniffed, "video/"), nil
+	}
+	return sniffed, nil
+}
+
+// File es un archivo descargado.
+type File struct {
+	URL      string // URL final, después de redirecciones
+	Type     string // detectado (ver Resolve)
+	Declared string // el de la respuesta HTTP
+	Data     []byte
+}
+
+// Downloader descarga archivos multimedia.
+type Downloader struct {
+	Client *http.Client
+	// MaxSize es el tamaño máximo de un archivo; los más grandes se rechazan.
+	MaxSize int64
+}
+
+func NewDownloader() *Downloader {
+	return &Downloader{
+		Client:  &http.Client{Timeout: 2 * time.Minute},
+		MaxSize: DefaultMaxSize,
+	}
+}
+
+// Get descarga el archivo de url. declared es el tipo que declaró la fuente;
+// si está vacío se usa el de la respuesta. El tipo se decide con los primeros
+// bytes, antes de descargar el resto: lo que no es multimedia (una página de
+// error, un muro de pago) se rechaza sin bajarlo completo.
+func (d *Downloader) Get(ctx context.Context, url, declared string) (*File, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error descargando %s: http %d", url, resp.StatusCode)
+	}
+	if d.MaxSize > 0 && resp.ContentLength > d.MaxSize {
+		return nil, fmt.Errorf("%w: %d bytes, el máximo es %d", ErrRejected, resp.ContentLength, d.MaxSize)
+	}
+
+	f := &File{URL: resp.Request.URL.String(), Declared: resp.Header.Get("Content-Type")}
+	if declared == "" {
+		declared = f.Declared
+	}
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(resp.Body, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, fmt.Errorf("error leyendo %s: %w", url, err)
+	}
+	head = head[:n]
+	if f.Type, err = Resolve(declared, head); err != nil {
+		return nil, err
+	}
+
+	limit := d.MaxSize
+	if limit <= 0 {
+		limit = DefaultMaxSize
+	}
+	rest, err := io.ReadAll(io.LimitReader(resp.Body, limit-int64(n)+1))
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo %s: %w", url, err)
+	}
+	if int64(n+len(rest)) > limit {
+		return nil, fmt.Errorf("%w: supera el máximo de %d bytes", ErrRejected, limit)
+	}
+	f.Data = append(head, rest...)
+	return f, nil
+}
@@ -0,0 +1,135 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"math/bits"
+	"slices"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// ErrNotImage indica que el archivo no es una imagen que se pueda decodificar
+// (JPEG, PNG, GIF o WebP) para calcular su hash perceptual.
+var ErrNotImage = errors.New("no es una imagen decodificable")
+
+// DefaultDistance es la distancia máxima entre hashes perceptuales para
+// considerar dos imágenes la misma foto: cubre otro tamaño, otra compresión
+// o un recorte de bordes, no otra toma de la misma escena.
+const DefaultDistance = 8
+
+// phashSize es el lado de la imagen reducida sobre la que se calcula la DCT;
+// de ella se usan las lowFreq×lowFreq frecuencias más bajas.
+const (
+	phashSize = 32
+	lowFreq   = 8
+)
+
+// dctCos[u][x] = cos((2x+1)uπ / 2N), la base de la DCT-II.
+var dctCos = func() (c [phashSize][phashSize]float64) {
+	for u := range phashSize {
+		for x := range phashSize {
+			c[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * phashSize))
+		}
+	}
+	return c
+}()
+
+// PHash calcula el hash perceptual (pHash) de una imagen: la imagen reducida
+// a 32×32 en grises, su DCT y, de las 8×8 frecuencias más bajas, qué
+// coeficientes superan la mediana. Dos copias de la misma foto publicadas por
+// distintos medios (otro tamaño, otra compresión) quedan a pocos bits de
+// distancia (ver Distance).
+func PHash(data []byte) (uint64, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, ErrNotImage
+	}
+	small := image.NewGray(image.Rect(0, 0, phashSize, phashSize))
+	draw.ApproxBiLinear.Scale(small, small.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var px [phashSize][phashSize]float64
+	for y := range phashSize {
+		for x := range phashSize {
+			px[y][x] = float64(small.GrayAt(x, y).Y)
+		}
+	}
+	// DCT separable: primero por filas y después por columnas, solo de las
+	// frecuencias que se usan.
+	var rows [phashSize][lowFreq]float64
+	for y := range phashSize {
+		for u := range lowFreq {
+			sum := 0.0
+			for x := range phashSize {
+				sum += px[y][x] * dctCos[u][x]
+			}
+			rows[y][u] = sum
+		}
+	}
+	coeffs := make([]float64, 0, lowFreq*lowFreq)
+	for v := range lowFreq {
+		for u := range lowFreq {
+			sum := 0.0
+			for y := range phashSize {
+				sum += rows[y][u] * dctCos[v][y]
+			}
+			coeffs = append(coeffs, sum)
+		}
+	}
+
+	// El primer coeficiente (el brillo medio) no cuenta para la mediana: un
+	// cambio de brillo parejo no cambia la foto.
+	sorted := slices.Clone(coeffs[1:])
+	slices.Sort(sorted)
+	median := sorted[len(sorted)/2]
+	var hash uint64
+	for i, c := range coeffs {
+		if c > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash, nil
+}
+
+// Distance es la distancia de Hamming entre dos hashes perceptuales: cuántos
+// de sus 64 bits difieren.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Catalog son los hashes perceptuales de las imágenes ya guardadas, para
+// encontrar la copia de una imagen nueva.
+type Catalog struct {
+	entries []catalogEntry
+}
+
+type catalogEntry struct {
+	hash   uint64
+	sha256 string
+}
+
+// Add registra la imagen guardada bajo sha256 con su hash perceptual.
+func (c *Catalog) Add(hash uint64, sha256 string) {
+	c.entries = append(c.entries, catalogEntry{hash, sha256})
+}
+
+// Nearest devuelve la imagen registrada más parecida a hash si está a no más
+// de max bits, con su distancia.
+func (c *Catalog) Nearest(hash uint64, max int) (sha256 string, distance int, ok bool) {
+	distance = max + 1
+	for _, e := range c.entries {
+		if d := Distance(hash, e.hash); d < distance {
+			sha256, distance = e.sha256, d
+			if d == 0 {
+				break
+			}
+		}
+	}
+	return sha256, distance, distance <= max
+}
@@ -0,0 +1,244 @@
+// Package merge combina el corpus de una campaña en el de otra, para cuando
+// dos campañas se solaparon recolectando en namespaces separados: deduplica
+// contra lo ya guardado, copia los datos de cada artículo y el historial de
+// rondas con la campaña de destino, y arma un informe de lo que se hizo.
+package merge
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/dedup"
+	"go-collector/storage"
+)
+
+// maxListed es cuántos artículos colapsados se listan por campaña en el
+// informe; los demás solo se cuentan.
+const maxListed = 200
+
+// Report es el resultado de una combinación.
+type Report struct {
+	Into    string
+	At      time.Time
+	DryRun  bool
+	Sources []*SourceReport
+	// Dedup es lo que probó y encontró cada estrategia de deduplicación en
+	// toda la combinación.
+	Dedup []dedup.StrategyStats
+}
+
+// SourceReport es lo que aportó una campaña combinada.
+type SourceReport struct {
+	Campaign string
+	Path     string
+	Read     int // artículos del corpus de origen
+	Added    int // copiados como artículos nuevos
+	SameURL  int // ya estaban en el destino con la misma URL
+	// Collapsed son los que coincidieron con otro artículo por alguna
+	// estrategia de deduplicación (la misma historia con otra URL): quedan
+	// como procedencia del artículo del destino.
+	Collapsed []Collapse
+	Rows      map[string]int // filas copiadas por tabla (etiquetas, descargas...)
+	Conflicts map[string]int // filas que ya existían en el destino (ej: otra etiqueta)
+	Runs      int            // rondas copiadas y reasignadas a la campaña de destino
+}
+
+// Collapse es un artículo de origen que se fusionó con otro: del destino o
+// uno ya combinado de la misma campaña.
+type Collapse struct {
+	URL     string
+	Title   string
+	IntoID  int64
+	IntoURL string
+	// Strategy es la estrategia de deduplicación que encontró la coincidencia.
+	Strategy string
+}
+
+// Index carga en dd todos los artículos de dst, retirados incluidos.
+func Index(dd *dedup.Deduper, dst *storage.Store) error {
+	articles, err := dst.ListFiltered(storage.Filter{IncludeWithdrawn: true})
+	if err != nil {
+		return err
+	}
+	for _, a := range articles {
+		dd.Add(a)
+	}
+	return nil
+}
+
+// Into combina el corpus src de la campaña name en dst. dd es el índice de
+// duplicados del destino (ver Index) y se completa con lo que se copia, así
+// que sirve para combinar varias campañas en orden. into es el nombre de la
+// campaña de destino, con el que quedan las rondas copiadas.
+func Into(dst, src *storage.Store, dd *dedup.Deduper, name, into string) (*SourceReport, error) {
+	rep := &SourceReport{Campaign: name, Rows: make(map[string]int), Conflicts: make(map[string]int)}
+	articles, err := src.ListFiltered(storage.Filter{IncludeWithdrawn: true})
+	if err != nil {
+		return nil, err
+	}
+	rep.Read = len(articles)
+
+	// ids traduce los IDs de origen a los del destino, para las ediciones y
+	// el linaje de las rondas.
+	ids := make(map[int64]int64, len(articles))
+	groups := make(map[int64]int64) // ID nuevo -> grupo de ediciones de origen
+	for _, a := range articles {
+		oldID := a.ID
+		if m, strategy := dd.MatchBy(a); m != nil {
+			ids[oldID] = m.ID
+			if m.URL == a.URL {
+				rep.SameURL++
+			} else {
+				rep.Collapsed = append(rep.Collapsed, Collapse{URL: a.URL, Title: a.Title, IntoID: m.ID, IntoURL: m.URL, Strategy: strategy})
+				if err := dst.AddProvenance(m.ID, m.Source, m.URL, m.Collected); err != nil {
+					return nil, err
+				}
+				if err := dst.AddProvenance(m.ID, a.Source, a.URL, a.Collected); err != nil {
+					return nil, err
+				}
+			}
+			// Las etiquetas manuales valen también para el artículo fusionado;
+			// si el destino ya tiene una, se conserva y queda como conflicto.
+			if err := copyData(rep, src, dst, oldID, m.ID, "labels"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := add(dst, a); err != nil {
+			return nil, err
+		}
+		ids[oldID] = a.ID
+		if a.EditionGroup != 0 {
+			groups[a.ID] = a.EditionGroup
+		}
+		dd.Add(a)
+		rep.Added++
+		if err := copyData(rep, src, dst, oldID, a.ID, storage.ArticleTables...); err != nil {
+			return nil, err
+		}
+	}
+
+	// Los grupos de ediciones apuntan al primer artículo del grupo: se
+	// traducen una vez copiados todos. Si ese artículo se fusionó con uno del
+	// destino, el grupo pasa a ser el de ese artículo.
+	for id, group := range groups {
+		if newGroup, ok := ids[group]; ok {
+			if err := dst.SetEditionGroup(newGroup, id); err != nil {
+				return nil, err
+			}
+		} else if err := dst.SetEditionGroup(0, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if rep.Runs, err = src.CopyRuns(dst, into, ids); err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+// add guarda en dst una copia del artículo de origen con su estado de retiro,
+// texto extraído y evaluación de relevancia; a.ID queda con el ID nuevo.
+func add(dst *storage.Store, a *article.Article) error {
+	status, group := a.Status, a.EditionGroup
+	a.ID, a.Status, a.EditionGroup = 0, article.StatusActive, 0
+	if err := dst.SaveArticle(a); err != nil {
+		return err
+	}
+	a.Status, a.EditionGroup = status, group
+	if a.ExtractionIssue != "" {
+		if err := dst.UpdateBody(a.ID, a.Body, a.ExtractionIssue); err != nil {
+			return err
+		}
+	}
+	if a.Explanation != nil {
+		if err := dst.SetExplanation(a.ID, a.Explanation); err != nil {
+			return err
+		}
+	}
+	if a.Withdrawn() {
+		at := a.Collected
+		if a.WithdrawnAt != nil {
+			at = *a.WithdrawnAt
+		}
+		if err := dst.MarkWithdrawn(a.ID, a.WithdrawnReason, at); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyData(rep *SourceReport, src, dst *storage.Store, fromID, toID int64, tables ...string) error {
+	copied, skipped, err := src.CopyArticleData(dst, fromID, toID, tables...)
+	if err != nil {
+		return err
+	}
+	for t, n := range copied {
+		rep.Rows[t] += n
+	}
+	for t, n := range skipped {
+		rep.Conflicts[t] += n
+	}
+	return nil
+}
+
+// WriteMarkdown escribe el informe de la combinación.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Combinación de campañas en %s\n\n", r.Into)
+	fmt.Fprintf(&b, "Fecha: %s\n", r.At.Format("2006-01-02 15:04"))
+	if r.DryRun {
+		b.WriteString("\n**Simulación**: el corpus de destino no se modificó.\n")
+	}
+	b.WriteString("\n| Campaña | Leídos | Nuevos | Misma URL | Colapsados | Rondas |\n|---|---:|---:|---:|---:|---:|\n")
+	for _, s := range r.Sources {
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %d |\n", s.Campaign, s.Read, s.Added, s.SameURL, len(s.Collapsed), s.Runs)
+	}
+	if len(r.Dedup) > 0 {
+		fmt.Fprintf(&b, "\nDeduplicación (coincidencias de probados): %s\n", dedup.FormatStats(r.Dedup))
+	}
+	for _, s := range r.Sources {
+		fmt.Fprintf(&b, "\n## %s\n\nOrigen: `%s`\n", s.Campaign, s.Path)
+		if len(s.Rows) > 0 {
+			b.WriteString("\nFilas copiadas: ")
+			b.WriteString(counts(s.Rows))
+			b.WriteString("\n")
+		}
+		if len(s.Conflicts) > 0 {
+			b.WriteString("\nYa existían en el destino (se conservó la del destino): ")
+			b.WriteString(counts(s.Conflicts))
+			b.WriteString("\n")
+		}
+		if len(s.Collapsed) == 0 {
+			continue
+		}
+		b.WriteString("\nColapsados con otro artículo (del destino o ya combinado):\n\n")
+		for i, c := range s.Collapsed {
+			if i == maxListed {
+				fmt.Fprintf(&b, "- … y %d más\n", len(s.Collapsed)-maxListed)
+				break
+			}
+			fmt.Fprintf(&b, "- %s\n  %s → #%d %s (%s)\n", c.Title, c.URL, c.IntoID, c.IntoURL, c.Strategy)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// counts muestra un conteo por tabla ordenado por nombre ("labels: 3, ...").
+func counts(m map[string]int) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s: %d", k, m[k])
+	}
+	return strings.Join(parts, ", ")
+}
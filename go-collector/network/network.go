@@ -0,0 +1,184 @@
+// Package network arma la red de difusión de las publicaciones de X
+// (retweets, citas y respuestas) y la exporta como lista de aristas con
+// fecha, en CSV o GraphML, para analizarla en igraph o Gephi.
+package network
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"go-collector/article"
+)
+
+// Nodos de la red: las cuentas (quién difunde a quién) o los tweets.
+const (
+	Accounts = "accounts"
+	Tweets   = "tweets"
+)
+
+// Edge es una arista: Source retuiteó, citó o respondió (Kind) a Target en
+// Time. En la red de cuentas los nodos son nombres de usuario (o el ID del
+// tweet si X no informó el autor); en la de tweets, IDs de tweets.
+type Edge struct {
+	Source     string
+	Target     string
+	Kind       string
+	Time       time.Time
+	TweetID    string
+	RefTweetID string
+}
+
+// TweetID devuelve el ID del tweet de una URL de X (…/status/<id>), o "".
+func TweetID(url string) string {
+	_, id, ok := strings.Cut(url, "/status/")
+	if !ok {
+		return ""
+	}
+	id, _, _ = strings.Cut(id, "/")
+	id, _, _ = strings.Cut(id, "?")
+	return id
+}
+
+// Cascade devuelve las referencias conectadas con los tweets seeds, directa
+// o indirectamente y en cualquier sentido: los retweets de un tweet, las
+// citas de esas citas, las respuestas a ellas, lo que el tweet citó...
+func Cascade(refs []article.Reference, seeds []string) []article.Reference {
+	in := make(map[string]bool, len(seeds))
+	for _, id := range seeds {
+		in[id] = true
+	}
+	taken := make([]bool, len(refs))
+	for changed := true; changed; {
+		changed = false
+		for i, r := range refs {
+			if taken[i] || (!in[r.TweetID] && !in[r.RefTweetID]) {
+				continue
+			}
+			taken[i], changed = true, true
+			in[r.TweetID], in[r.RefTweetID] = true, true
+		}
+	}
+	var out []article.Reference
+	for i, r := range refs {
+		if taken[i] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Edges convierte las referencias en aristas entre cuentas o tweets (ver
+// Accounts y Tweets), en orden cronológico.
+func Edges(refs []article.Reference, nodes string) ([]Edge, error) {
+	if nodes != Accounts && nodes != Tweets {
+		return nil, fmt.Errorf("nodos desconocidos: %s (use %s o %s)", nodes, Accounts, Tweets)
+	}
+	out := make([]Edge, 0, len(refs))
+	for _, r := range refs {
+		e := Edge{Source: r.TweetID, Target: r.RefTweetID, Kind: r.Kind, Time: r.Created, TweetID: r.TweetID, RefTweetID: r.RefTweetID}
+		if nodes == Accounts {
+			if r.Author != "" {
+				e.Source = r.Author
+			}
+			if r.RefAuthor != "" {
+				e.Target = r.RefAuthor
+			}
+		}
+		out = append(out, e)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out, nil
+}
+
+// WriteCSV escribe la lista de aristas con encabezado (source, target, kind,
+// timestamp, tweet_id, ref_tweet_id); Gephi la importa como tabla de
+// aristas e igraph la lee con Graph.DataFrame.
+func WriteCSV(w io.Writer, edges []Edge) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"source", "target", "kind", "timestamp", "tweet_id", "ref_tweet_id"})
+	for _, e := range edges {
+		cw.Write([]string{e.Source, e.Target, e.Kind, formatTime(e.Time), e.TweetID, e.RefTweetID})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteGraphML escribe la red dirigida en GraphML, que leen igraph
+// (Graph.Read_GraphML) y Gephi sin conversión; las aristas llevan su tipo,
+// fecha y tweets.
+func WriteGraphML(w io.Writer, edges []Edge) error {
+	type data struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+	type node struct {
+		ID string `xml:"id,attr"`
+	}
+	type edge struct {
+		Source string `xml:"source,attr"`
+		Target string `xml:"target,attr"`
+		Data   []data `xml:"data"`
+	}
+	type key struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+	type graph struct {
+		EdgeDefault string `xml:"edgedefault,attr"`
+		Nodes       []node `xml:"node"`
+		Edges       []edge `xml:"edge"`
+	}
+	doc := struct {
+		XMLName xml.Name `xml:"graphml"`
+		XMLNS   string   `xml:"xmlns,attr"`
+		Keys    []key    `xml:"key"`
+		Graph   graph    `xml:"graph"`
+	}{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []key{
+			{"kind", "edge", "kind", "string"},
+			{"timestamp", "edge", "timestamp", "string"},
+			{"tweet_id", "edge", "tweet_id", "string"},
+			{"ref_tweet_id", "edge", "ref_tweet_id", "string"},
+		},
+		Graph: graph{EdgeDefault: "directed"},
+	}
+	seen := make(map[string]bool)
+	addNode := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			doc.Graph.Nodes = append(doc.Graph.Nodes, node{ID: id})
+		}
+	}
+	for _, e := range edges {
+		addNode(e.Source)
+		addNode(e.Target)
+		doc.Graph.Edges = append(doc.Graph.Edges, edge{Source: e.Source, Target: e.Target, Data: []data{
+			{"kind", e.Kind}, {"timestamp", formatTime(e.Time)}, {"tweet_id", e.TweetID}, {"ref_tweet_id", e.RefTweetID},
+		}})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,371 @@
+// Package notify avisa a webhooks (Slack, Discord, Teams u otros servicios)
+// y a canales de Telegram de los artículos que guarda cada ronda y cumplen
+// las reglas de cada notificación (config notifications): palabras clave,
+// fuentes, umbral de tono y puntaje de relevancia. Por notificación van los
+// mensajes de la ronda, con hasta batch artículos cada uno y al ritmo de su
+// cupo. Un envío fallido se reintenta y, si no llega, queda en el corpus
+// para reenviarlo (collector notifications resend).
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/time/rate"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/crawler"
+	"go-collector/relevance"
+	"go-collector/sentiment"
+	"go-collector/storage"
+)
+
+const (
+	// defaultRetries es cuántas veces se reintenta un envío fallido.
+	defaultRetries = 3
+	// retryDelay es la espera antes del primer reintento; se duplica en
+	// cada uno.
+	retryDelay = 2 * time.Second
+	// maxRetryAfter acota la espera que pide un servicio con Retry-After.
+	maxRetryAfter = time.Minute
+	// maxPerMessage es cuántos artículos van en un mensaje; Slack y Teams
+	// cortan los mensajes largos y Discord los rechaza.
+	maxPerMessage = 20
+	// telegramRate es el cupo por defecto en telegram: Telegram admite unos
+	// 20 mensajes por minuto en un mismo canal o grupo.
+	telegramRate = "20/m"
+)
+
+// Item es lo que recibe la plantilla de cada artículo (notifications
+// template): el artículo y su tono, nil si no hay léxico para su idioma.
+type Item struct {
+	*article.Article
+	Sentiment *sentiment.Score `json:"sentiment,omitempty"`
+}
+
+// executor es una plantilla compilada: text/template o, en telegram,
+// html/template, que escapa los campos del artículo.
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+// rule es una notificación configurada, lista para evaluar artículos.
+type rule struct {
+	config.Notification
+	keywords [][]string
+	sources  map[string]bool
+	tmpl     executor
+	batch    int
+	// limiter espacia los mensajes según el cupo; nil si no hay.
+	limiter *rate.Limiter
+	// scorer puntúa con las palabras clave los artículos que no traen el
+	// puntaje de una campaña, para min_score; nil sin palabras clave.
+	scorer *relevance.Filter
+}
+
+// Notifier envía las notificaciones configuradas.
+type Notifier struct {
+	rules  []*rule
+	Client *http.Client
+}
+
+// New prepara las notificaciones; nil si no hay ninguna. rel son las reglas
+// de supresión y pesos por fuente con las que se puntúan los artículos para
+// min_score.
+func New(cfg []config.Notification, rel config.Relevance) (*Notifier, error) {
+	if len(cfg) == 0 {
+		return nil, nil
+	}
+	n := &Notifier{Client: &http.Client{Timeout: 30 * time.Second}}
+	for _, c := range cfg {
+		r := &rule{Notification: c, batch: c.Batch}
+		if r.batch <= 0 || r.batch > maxPerMessage {
+			r.batch = maxPerMessage
+		}
+		spec := c.RateLimit
+		if spec == "" && c.Format == "telegram" {
+			spec = telegramRate
+		}
+		quota, err := config.ParseRate(spec)
+		if err != nil {
+			return nil, fmt.Errorf("notificación %s: %w", c.Name, err)
+		}
+		if quota.Limited() {
+			r.limiter = rate.NewLimiter(rate.Every(quota.Per/time.Duration(quota.N)), 1)
+		}
+		for _, k := range c.Keywords {
+			if words := tokens(k); len(words) > 0 {
+				r.keywords = append(r.keywords, words)
+			}
+		}
+		if len(c.Sources) > 0 {
+			r.sources = make(map[string]bool)
+			for _, s := range c.Sources {
+				r.sources[s] = true
+			}
+		}
+		if c.MinScore != nil && len(c.Keywords) > 0 {
+			if r.scorer, err = relevance.NewFilter(config.Query{Terms: c.Keywords}, rel); err != nil {
+				return nil, fmt.Errorf("notificación %s: %w", c.Name, err)
+			}
+		}
+		if c.Template != "" {
+			// Telegram rechaza el mensaje entero si un título trae < o &
+			// sin escapar.
+			if c.Format == "telegram" {
+				r.tmpl, err = htmltemplate.New(c.Name).Parse(c.Template)
+			} else {
+				r.tmpl, err = template.New(c.Name).Parse(c.Template)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("notificación %s: plantilla inválida: %w", c.Name, err)
+			}
+		}
+		n.rules = append(n.rules, r)
+	}
+	return n, nil
+}
+
+// match indica si el artículo cumple las reglas de r.
+func (r *rule) match(it Item) bool {
+	if r.sources != nil && !r.sources[it.Source] {
+		return false
+	}
+	if r.MinScore != nil {
+		exp := it.Explanation
+		if exp == nil && r.scorer != nil {
+			exp = r.scorer.Explain(it.Article)
+		}
+		if exp == nil || exp.Score < *r.MinScore {
+			return false
+		}
+	}
+	if r.MinSentiment != nil || r.MaxSentiment != nil {
+		if it.Sentiment == nil ||
+			(r.MinSentiment != nil && it.Sentiment.Value < *r.MinSentiment) ||
+			(r.MaxSentiment != nil && it.Sentiment.Value > *r.MaxSentiment) {
+			return false
+		}
+	}
+	if len(r.keywords) == 0 {
+		return true
+	}
+	words := tokens(it.Title + "\n" + it.Summary + "\n" + it.Body)
+	for _, k := range r.keywords {
+		if contains(words, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// Send manda a cada notificación los artículos que cumplen sus reglas. Las
+// reglas miran el artículo completo, pero el mensaje lleva solo lo que sus
+// términos de uso permiten redistribuir (ver storage.Store.Shareable). Un
+// mensaje que no llega después de los reintentos se registra en store y se
+// informa en w sin cortar los demás; solo se devuelven los errores del
+// registro.
+func (n *Notifier) Send(ctx context.Context, store *storage.Store, articles []*article.Article, w io.Writer) error {
+	var items, shared []Item
+	for _, a := range articles {
+		out, _ := store.Shareable(a)
+		if out == nil {
+			continue
+		}
+		it := Item{Article: a}
+		if s, ok := sentiment.Article(a); ok {
+			it.Sentiment = &s
+		}
+		items = append(items, it)
+		shared = append(shared, Item{Article: out, Sentiment: it.Sentiment})
+	}
+	for _, r := range n.rules {
+		var matched []Item
+		for i, it := range items {
+			if r.match(it) {
+				matched = append(matched, shared[i])
+			}
+		}
+		for start := 0; start < len(matched); start += r.batch {
+			batch := matched[start:min(start+r.batch, len(matched))]
+			payload, err := r.payload(batch)
+			if err != nil {
+				return err
+			}
+			attempts, err := n.post(ctx, r, payload)
+			if err == nil {
+				fmt.Fprintf(w, "Notificación %s: %d artículos enviados\n", r.Name, len(batch))
+				continue
+			}
+			f := &storage.NotificationFailure{
+				Notification: r.Name, Payload: payload, Articles: len(batch), Attempts: attempts,
+				Err: err.Error(), Failed: time.Now(),
+			}
+			if err := store.AddNotificationFailure(f); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "Notificación %s: no se pudo enviar (%v); queda para collector notifications resend (id %d)\n", r.Name, err, f.ID)
+		}
+	}
+	return nil
+}
+
+// Resend vuelve a enviar un mensaje que no llegó, con sus reintentos, y si
+// llega lo marca como reenviado.
+func (n *Notifier) Resend(ctx context.Context, store *storage.Store, f storage.NotificationFailure) error {
+	var r *rule
+	for _, c := range n.rules {
+		if c.Name == f.Notification {
+			r = c
+		}
+	}
+	if r == nil {
+		return fmt.Errorf("la notificación %s ya no está en la configuración", f.Notification)
+	}
+	if _, err := n.post(ctx, r, f.Payload); err != nil {
+		return err
+	}
+	return store.ResolveNotificationFailure(f.ID, time.Now())
+}
+
+// errPermanent marca las respuestas que no cambian al reintentar (un 4xx
+// distinto de 429: URL revocada, cuerpo rechazado).
+var errPermanent = errors.New("el servicio rechazó el mensaje")
+
+// post envía el cuerpo al webhook de r con hasta Retries reintentos,
+// esperando retryDelay antes del primero y el doble antes de cada
+// siguiente, o lo que pida el servicio con Retry-After. Cada intento espera
+// además su turno en el cupo de r. Devuelve cuántos intentos hizo.
+func (n *Notifier) post(ctx context.Context, r *rule, payload []byte) (int, error) {
+	endpoint := r.WebhookURL()
+	if endpoint == "" {
+		env := r.URLEnv
+		if r.Format == "telegram" {
+			env = r.BotTokenEnv()
+		}
+		return 0, fmt.Errorf("la notificación %s no tiene URL (¿falta la variable %s?)", r.Name, env)
+	}
+	retries := r.Retries
+	if retries <= 0 {
+		retries = defaultRetries
+	}
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		if r.limiter != nil {
+			if err := r.limiter.Wait(ctx); err != nil {
+				return attempt - 1, err
+			}
+		}
+		wait, err := n.postOnce(ctx, endpoint, payload)
+		if err == nil {
+			return attempt, nil
+		}
+		if errors.Is(err, errPermanent) || ctx.Err() != nil || attempt > retries {
+			return attempt, fmt.Errorf("%w (intentos: %d)", err, attempt)
+		}
+		if err := crawler.Sleep(ctx, max(wait, delay)); err != nil {
+			return attempt, err
+		}
+		delay *= 2
+	}
+}
+
+// postOnce hace un envío; si el servicio pide esperar (429 con
+// Retry-After), devuelve cuánto.
+func (n *Notifier) postOnce(ctx context.Context, endpoint string, payload []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", errPermanent, stripURL(err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return 0, stripURL(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		var wait time.Duration
+		secs, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64)
+		if err != nil {
+			// Telegram lo indica en el cuerpo.
+			var tg struct {
+				Parameters struct {
+					RetryAfter float64 `json:"retry_after"`
+				} `json:"parameters"`
+			}
+			if json.Unmarshal(body, &tg) == nil {
+				secs = tg.Parameters.RetryAfter
+			}
+		}
+		if secs > 0 {
+			wait = min(time.Duration(secs*float64(time.Second)), maxRetryAfter)
+		}
+		return wait, fmt.Errorf("%s", resp.Status)
+	case resp.StatusCode >= 500:
+		return 0, fmt.Errorf("%s", resp.Status)
+	default:
+		return 0, fmt.Errorf("%w: %s %s", errPermanent, resp.Status, strings.TrimSpace(string(body)))
+	}
+}
+
+// stripURL quita la URL de los errores de net/http: en telegram lleva el
+// token del bot, y el error queda en el corpus y en la consola.
+func stripURL(err error) error {
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		return fmt.Errorf("%s: %w", uerr.Op, uerr.Err)
+	}
+	return err
+}
+
+// tokens separa s en palabras en minúscula y sin tildes.
+func tokens(s string) []string {
+	return strings.FieldsFunc(fold(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// contains indica si la frase aparece en words, palabra por palabra.
+func contains(words, phrase []string) bool {
+outer:
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		for j, p := range phrase {
+			if words[i+j] != p {
+				continue outer
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// fold pasa a minúscula y quita tildes ("Bogotá" y "Bogota" son la misma palabra).
+func fold(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	out, _, err := transform.String(t, s)
+	if err != nil {
+		return strings.ToLower(s)
+	}
+	return strings.ToLower(out)
+}
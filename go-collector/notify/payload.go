@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+)
+
+const (
+	// discordLimit es el largo máximo del contenido de un mensaje de Discord.
+	discordLimit = 2000
+	// telegramLimit es el largo máximo del texto de un mensaje de Telegram,
+	// en unidades UTF-16.
+	telegramLimit = 4096
+)
+
+// payload arma el cuerpo del mensaje con los artículos según el formato de
+// la notificación.
+func (r *rule) payload(items []Item) ([]byte, error) {
+	if r.Format == "" || r.Format == "json" {
+		return json.Marshal(struct {
+			Notification string `json:"notification"`
+			Articles     []Item `json:"articles"`
+		}{r.Name, items})
+	}
+
+	title := fmt.Sprintf("%s: %d artículos nuevos", r.Name, len(items))
+	lines := make([]string, len(items))
+	for i, it := range items {
+		line, err := r.line(it)
+		if err != nil {
+			return nil, err
+		}
+		lines[i] = line
+	}
+	var body any
+	switch r.Format {
+	case "slack":
+		body = map[string]string{"text": "*" + slackEscape(title) + "*\n" + strings.Join(lines, "\n")}
+	case "discord":
+		content := "**" + title + "**\n" + strings.Join(lines, "\n")
+		if len(content) > discordLimit {
+			content = truncate(content, discordLimit-1) + "…"
+		}
+		body = map[string]string{"content": content}
+	case "telegram":
+		// En HTML no se puede cortar en cualquier parte: las líneas que no
+		// entran se resumen al final.
+		header := "<b>" + html.EscapeString(title) + "</b>"
+		text := header + "\n" + strings.Join(lines, "\n")
+		for n := len(lines) - 1; n > 0 && utf16Len(text) > telegramLimit; n-- {
+			text = header + "\n" + strings.Join(lines[:n], "\n") + fmt.Sprintf("\n… y %d más", len(lines)-n)
+		}
+		body = map[string]any{
+			"chat_id":    r.ChatID,
+			"text":       text,
+			"parse_mode": "HTML",
+			// Con un solo artículo, la vista previa del enlace es el aviso.
+			"disable_web_page_preview": len(items) > 1,
+		}
+	case "teams":
+		// MessageCard: el formato de los webhooks entrantes de Teams.
+		body = map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  title,
+			"title":    title,
+			"text":     strings.Join(lines, "\n\n"),
+		}
+	default:
+		return nil, fmt.Errorf("notificación %s: formato desconocido %q", r.Name, r.Format)
+	}
+	return json.Marshal(body)
+}
+
+// line es la línea del artículo en el mensaje: la plantilla configurada o
+// el título enlazado con la fuente y el tono, con los enlaces de cada
+// servicio.
+func (r *rule) line(it Item) (string, error) {
+	if r.tmpl != nil {
+		var b strings.Builder
+		if err := r.tmpl.Execute(&b, it); err != nil {
+			return "", fmt.Errorf("notificación %s: %w", r.Name, err)
+		}
+		return b.String(), nil
+	}
+	detail := it.Source
+	if it.Sentiment != nil {
+		detail += fmt.Sprintf(" · tono %s (%+.2f)", it.Sentiment.Label(), it.Sentiment.Value)
+	}
+	title := strings.TrimSpace(it.Title)
+	if title == "" {
+		title = it.URL
+	}
+	switch r.Format {
+	case "slack":
+		return fmt.Sprintf("• <%s|%s> — %s", it.URL, slackEscape(title), slackEscape(detail)), nil
+	case "discord":
+		// <> evita la vista previa de cada enlace.
+		return fmt.Sprintf("• [%s](<%s>) — %s", title, it.URL, detail), nil
+	case "telegram":
+		return fmt.Sprintf("• <a href=\"%s\">%s</a> — %s", html.EscapeString(it.URL), html.EscapeString(title), html.EscapeString(detail)), nil
+	default:
+		return fmt.Sprintf("- [%s](%s) — %s", title, it.URL, detail), nil
+	}
+}
+
+// slackEscape escapa los caracteres que Slack interpreta como control.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// utf16Len es el largo de s como lo cuenta Telegram.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// truncate corta s en a lo sumo n bytes sin partir un carácter.
+func truncate(s string, n int) string {
+	for n > 0 && n < len(s) && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,163 @@
+// Package progress informa el avance de una recolección: como eventos JSON
+// (uno por línea, para otras herramientas) o como barra legible en la terminal.
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tipos de evento.
+const (
+	SourceStarted  = "source_started"
+	PageFetched    = "page_fetched"
+	ArticlesStored = "articles_stored"
+	SourceDone     = "source_done"
+	// SourceSkipped es una fuente desactivada temporalmente que no se
+	// consultó; Message lleva el motivo.
+	SourceSkipped = "source_skipped"
+	// FeedDeferred es un feed RSS que no se leyó porque su intervalo
+	// adaptativo aún no vence; Message lleva el feed y su próxima lectura.
+	// Es un detalle: la barra no lo muestra, solo los eventos JSON.
+	FeedDeferred = "feed_deferred"
+	Error        = "error"
+	// Panic es un pánico recuperado en el crawler de una fuente; Stack lleva
+	// el stack para el reporte. La fuente falla y las demás siguen.
+	Panic = "panic"
+)
+
+// Event es un hito de la recolección. Los campos que no aplican van vacíos.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"`
+	Campaign string    `json:"campaign,omitempty"`
+	Source   string    `json:"source,omitempty"`
+	Page     int       `json:"page,omitempty"`
+	Count    int       `json:"count,omitempty"` // artículos en la página, o guardados
+	Total    int       `json:"total,omitempty"` // acumulado de la fuente, o total informado por la API
+	Message  string    `json:"message,omitempty"`
+	Stack    string    `json:"stack,omitempty"`
+}
+
+// Reporter recibe los eventos. Las implementaciones son seguras para usar
+// desde varias goroutines.
+type Reporter interface {
+	Emit(Event)
+}
+
+// Emit envía un evento a r, completando la hora; r puede ser nil.
+func Emit(r Reporter, e Event) {
+	if r == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	r.Emit(e)
+}
+
+// WithCampaign marca los eventos con el nombre de la campaña, para separar
+// las que corren en paralelo. Si r es nil devuelve nil.
+func WithCampaign(r Reporter, campaign string) Reporter {
+	if r == nil {
+		return nil
+	}
+	return campaignReporter{r, campaign}
+}
+
+type campaignReporter struct {
+	next     Reporter
+	campaign string
+}
+
+func (c campaignReporter) Emit(e Event) {
+	e.Campaign = c.campaign
+	c.next.Emit(e)
+}
+
+// New devuelve el reporter del modo pedido: "json", "bar" o "none". "auto"
+// usa la barra si w es una terminal y nada en otro caso.
+func New(mode string, w io.Writer) (Reporter, error) {
+	switch mode {
+	case "json":
+		return &JSON{w: w}, nil
+	case "bar":
+		return &Bar{w: w}, nil
+	case "none", "":
+		return nil, nil
+	case "auto":
+		if f, ok := w.(*os.File); ok {
+			if info, err := f.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+				return &Bar{w: w}, nil
+			}
+		}
+		return nil, nil
+	}
+	return nil, fmt.Errorf("modo de progreso desconocido: %s (use json, bar o none)", mode)
+}
+
+// JSON escribe cada evento como una línea JSON.
+type JSON struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (j *JSON) Emit(e Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	j.w.Write(append(data, '\n'))
+}
+
+// Bar muestra en una línea que se reescribe el avance de la fuente en curso;
+// cada fuente nueva deja la anterior en su propia línea. El resumen final lo
+// imprime el comando, así que al terminar la línea se borra.
+type Bar struct {
+	mu   sync.Mutex
+	w    io.Writer
+	last int
+}
+
+func (b *Bar) Emit(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if e.Campaign != "" {
+		e.Source = e.Campaign + "/" + e.Source
+	}
+	switch e.Type {
+	case SourceStarted:
+		if b.last > 0 {
+			fmt.Fprintln(b.w)
+			b.last = 0
+		}
+		b.line(fmt.Sprintf("%-10s consultando...", e.Source))
+	case PageFetched:
+		b.line(fmt.Sprintf("%-10s página %d · %d artículos", e.Source, e.Page, e.Total))
+	case ArticlesStored:
+		b.line(fmt.Sprintf("%-10s %d de %d guardados", e.Source, e.Count, e.Total))
+	case SourceDone, SourceSkipped, Error, Panic:
+		b.line("")
+	}
+}
+
+// line reescribe la línea actual, tapando lo que sobre de la anterior.
+func (b *Bar) line(s string) {
+	n := len([]rune(s))
+	pad := ""
+	if b.last > n {
+		pad = strings.Repeat(" ", b.last-n)
+	}
+	fmt.Fprintf(b.w, "\r%s%s", s, pad)
+	if s == "" {
+		fmt.Fprint(b.w, "\r")
+	}
+	b.last = n
+}
@@ -0,0 +1,176 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+
+	"go-collector/config"
+)
+
+// CompileX arma la consulta para la API de búsqueda de X: todas las variantes
+// unidas con OR, las frases entre comillas. El resultado va dentro de los
+// paréntesis que agrega BuscarTweets.
+func CompileX(q config.Query) string {
+	forms := Expand(q)
+	parts := make([]string, len(forms))
+	for i, f := range forms {
+		parts[i] = quoteIfPhrase(f)
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// CompileNews arma la consulta para las APIs de noticias (Guardian, NewsAPI):
+// términos y alias unidos con OR, las frases entre comillas. No incluye
+// hashtags ni formas sin espacios, que no aparecen en el texto de las notas.
+func CompileNews(q config.Query) string {
+	var parts []string
+	seen := make(map[string]bool)
+	for _, term := range append(append([]string{}, q.Terms...), q.Aliases...) {
+		term = cleanTerm(term)
+		key := strings.ToLower(term)
+		if term == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		parts = append(parts, quoteIfPhrase(term))
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// CompileGDELT es CompileNews entre paréntesis cuando hay más de un término,
+// como exige GDELT para los OR.
+func CompileGDELT(q config.Query) string {
+	s := CompileNews(q)
+	if strings.Contains(s, " OR ") {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// MastodonQuery separa lo que Mastodon busca por timeline de hashtag (siempre
+// disponible) de la búsqueda de texto completo (solo en instancias que la habilitan).
+type MastodonQuery struct {
+	Hashtags []string // sin '#', para /api/v1/timelines/tag/:hashtag
+	FullText []string // para /api/v2/search
+}
+
+// CompileMastodon arma las consultas de Mastodon.
+func CompileMastodon(q config.Query) MastodonQuery {
+	var mq MastodonQuery
+	for _, f := range Expand(q) {
+		if tag, ok := strings.CutPrefix(f, "#"); ok && isHashtag(tag) {
+			mq.Hashtags = append(mq.Hashtags, tag)
+			continue
+		}
+		mq.FullText = append(mq.FullText, quoteIfPhrase(f))
+	}
+	return mq
+}
+
+// String es la consulta en la sintaxis de sources.mastodon.query: todas las
+// formas unidas con OR, los hashtags con '#'.
+func (mq MastodonQuery) String() string {
+	var parts []string
+	for _, h := range mq.Hashtags {
+		parts = append(parts, "#"+h)
+	}
+	return strings.Join(append(parts, mq.FullText...), " OR ")
+}
+
+// ParseMastodon interpreta sources.mastodon.query: términos unidos con OR;
+// los que empiezan con '#' se consultan por la timeline del hashtag y los
+// demás por la búsqueda de texto completo.
+func ParseMastodon(s string) MastodonQuery {
+	var mq MastodonQuery
+	for _, part := range Alternatives(s) {
+		if tag, ok := strings.CutPrefix(part, "#"); ok && isHashtag(tag) {
+			mq.Hashtags = append(mq.Hashtags, tag)
+		} else {
+			mq.FullText = append(mq.FullText, part)
+		}
+	}
+	return mq
+}
+
+// isHashtag indica si tag (sin '#') sirve para la timeline de un hashtag:
+// solo letras y números (ej: no "Medellín-Antioquia", que se busca como texto).
+func isHashtag(tag string) bool {
+	return tag != "" && strings.IndexFunc(tag, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) < 0
+}
+
+// Alternatives separa una consulta de la forma "a OR b OR c" en sus
+// términos, para las fuentes que no admiten OR y hacen una búsqueda por
+// término (Mastodon, Bluesky). Un OR dentro de una frase entre comillas es
+// parte de la frase.
+func Alternatives(s string) []string {
+	var out []string
+	add := func(part string) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	start, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && strings.HasPrefix(s[i:], " OR "):
+			add(s[start:i])
+			start = i + len(" OR ")
+			i = start - 1
+		}
+	}
+	add(s[start:])
+	return out
+}
+
+// CompileBluesky arma las consultas para app.bsky.feed.searchPosts. La búsqueda
+// de Bluesky no soporta OR, así que se hace una consulta por variante.
+func CompileBluesky(q config.Query) []string {
+	forms := Expand(q)
+	out := make([]string, len(forms))
+	for i, f := range forms {
+		out[i] = quoteIfPhrase(f)
+	}
+	return out
+}
+
+// CompileYouTube arma la consulta para la búsqueda de YouTube, que une las
+// alternativas con '|': todas las variantes, las frases entre comillas.
+func CompileYouTube(q config.Query) string {
+	forms := Expand(q)
+	parts := make([]string, len(forms))
+	for i, f := range forms {
+		parts[i] = quoteIfPhrase(f)
+	}
+	return strings.Join(parts, "|")
+}
+
+// quoteIfPhrase pone entre comillas las frases y las palabras que las APIs
+// tomarían como operadores. s ya pasó por cleanTerm: no tiene comillas.
+func quoteIfPhrase(s string) string {
+	if strings.Contains(s, " ") || operators[s] {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// operators son las palabras reservadas de las sintaxis de búsqueda.
+var operators = map[string]bool{"OR": true, "AND": true, "NOT": true}
+
+// cleanTerm deja un término listo para armar consultas: sin comillas ni barras
+// invertidas (las APIs no admiten escapes dentro de una frase), sin caracteres de
+// control y con los espacios colapsados. Queda vacío si no tiene letras ni
+// números (ej: "#" o "-"), que ninguna API sabe buscar.
+func cleanTerm(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '"' || r == '“' || r == '”' || r == '\\' || unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+	if strings.IndexFunc(s, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+		return ""
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
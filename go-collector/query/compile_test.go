@@ -0,0 +1,83 @@
+package query
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"go-collector/config"
+)
+
+// FuzzCompile busca términos (comillas, barras, operadores, hashtags raros)
+// con los que los compiladores entran en pánico o arman consultas que las
+// APIs rechazarían: comillas sin cerrar, escapes, caracteres de control,
+// alternativas vacías o hashtags que no son un solo tramo de letras y números.
+//
+//	go test ./query -fuzz FuzzCompile
+func FuzzCompile(f *testing.F) {
+	for _, s := range [][2]string{
+		{"Universidad de Antioquia", "UdeA"},
+		{`"paro" estudiantil`, `\"escape\"`},
+		{"#", "-"},
+		{"a OR b", "NOT"},
+		{"#Medellín-Antioquia", "#con espacio"},
+		{"“comillas” tipográficas", "tab\there"},
+		{"", ""},
+	} {
+		f.Add(s[0], s[1])
+	}
+	f.Fuzz(func(t *testing.T, term, alias string) {
+		if !utf8.ValidString(term) || !utf8.ValidString(alias) {
+			t.Skip()
+		}
+		q := config.Query{Terms: []string{term}, Aliases: []string{alias}}
+
+		for name, s := range map[string]string{
+			"CompileX":       CompileX(q),
+			"CompileNews":    CompileNews(q),
+			"CompileGDELT":   CompileGDELT(q),
+			"CompileYouTube": CompileYouTube(q),
+		} {
+			checkQuery(t, name, q, s)
+		}
+		if s := CompileGDELT(q); strings.Contains(s, " OR ") && !strings.HasPrefix(s, "(") {
+			t.Fatalf("CompileGDELT(%q) = %q: OR sin paréntesis", q, s)
+		}
+		for _, s := range CompileBluesky(q) {
+			checkQuery(t, "CompileBluesky", q, s)
+			if s == "" {
+				t.Fatalf("CompileBluesky(%q): consulta vacía", q)
+			}
+		}
+
+		mq := CompileMastodon(q)
+		for _, h := range mq.Hashtags {
+			if !isHashtag(h) {
+				t.Fatalf("CompileMastodon(%q): hashtag inválido %q", q, h)
+			}
+		}
+		for _, s := range mq.FullText {
+			checkQuery(t, "CompileMastodon", q, s)
+		}
+		back := ParseMastodon(mq.String())
+		if !slices.Equal(back.Hashtags, mq.Hashtags) || !slices.Equal(back.FullText, mq.FullText) {
+			t.Fatalf("ParseMastodon(%q) = %q %q, se esperaba %q %q", mq.String(), back.Hashtags, back.FullText, mq.Hashtags, mq.FullText)
+		}
+	})
+}
+
+func checkQuery(t *testing.T, name string, q config.Query, s string) {
+	t.Helper()
+	switch {
+	case strings.Count(s, `"`)%2 != 0:
+		t.Fatalf("%s(%q) = %q: comillas sin cerrar", name, q, s)
+	case strings.Contains(s, `\`):
+		t.Fatalf("%s(%q) = %q: barra invertida", name, q, s)
+	case strings.IndexFunc(s, unicode.IsControl) >= 0:
+		t.Fatalf("%s(%q) = %q: caracteres de control", name, q, s)
+	case strings.Contains(s, `""`):
+		t.Fatalf("%s(%q) = %q: frase vacía", name, q, s)
+	}
+}
@@ -0,0 +1,101 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"go-collector/config"
+)
+
+// Variants son las formas en que se menciona un término en redes sociales.
+type Variants struct {
+	Phrase   string   // forma original, ej: "Universidad de Antioquia"
+	Hashtags []string // ej: #UniversidadDeAntioquia, #UdeA
+	Joined   []string // sin espacios, ej: UniversidadDeAntioquia
+}
+
+// Generate produce las variantes de un término: hashtags en CamelCase con y sin
+// tildes, y la forma sin espacios (como se escribe en menciones y URLs).
+func Generate(term string) Variants {
+	v := Variants{Phrase: strings.TrimSpace(term)}
+	words := strings.Fields(v.Phrase)
+	if len(words) == 0 {
+		return v
+	}
+
+	camel := camelCase(words)
+	seen := make(map[string]bool)
+	for _, form := range []string{camel, stripAccents(camel)} {
+		if form == "" || seen[form] {
+			continue
+		}
+		seen[form] = true
+		v.Hashtags = append(v.Hashtags, "#"+form)
+		if len(words) > 1 {
+			v.Joined = append(v.Joined, form)
+		}
+	}
+	return v
+}
+
+// Expand devuelve todas las formas de búsqueda de la consulta, sin repetidos:
+// frases y alias tal cual, más hashtags y formas sin espacios de cada uno.
+func Expand(q config.Query) []string {
+	var out []string
+	seen := make(map[string]bool)
+	add := func(s string) {
+		key := strings.ToLower(s)
+		if s != "" && !seen[key] {
+			seen[key] = true
+			out = append(out, s)
+		}
+	}
+
+	for _, term := range append(append([]string{}, q.Terms...), q.Aliases...) {
+		v := Generate(cleanTerm(term))
+		add(v.Phrase)
+		for _, j := range v.Joined {
+			add(j)
+		}
+		for _, h := range v.Hashtags {
+			add(h)
+		}
+	}
+	return out
+}
+
+// camelCase une las palabras con mayúscula inicial, respetando siglas:
+// "Universidad de Antioquia" -> "UniversidadDeAntioquia", "UdeA" -> "UdeA".
+func camelCase(words []string) string {
+	var b strings.Builder
+	for _, w := range words {
+		// Los hashtags solo admiten letras y números: "Medellín-Antioquia"
+		// queda "MedellínAntioquia".
+		w = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, w)
+		if w == "" {
+			continue
+		}
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
+
+func stripAccents(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	out, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return out
+}
@@ -0,0 +1,420 @@
+// Package rawarchive guarda las páginas descargadas tal como llegaron, para
+// poder volver a extraerlas o verificarlas sin pedirlas otra vez al medio.
+//
+// Los archivos se direccionan por su SHA-256 y se guardan comprimidos en
+// <dir>/ab/cd/<hash>.gz (gzip) o, con la compresión activa (ver
+// EnableCompression), en <hash>.zst con zstd y el diccionario entrenado con
+// el propio archivo, que queda en <dir>/dicts. Una página idéntica
+// descargada dos veces ocupa espacio una sola vez.
+package rawarchive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-collector/compress"
+	"go-collector/encrypt"
+)
+
+// dictDir es el subdirectorio de los diccionarios.
+const dictDir = "dicts"
+
+// Archive es el directorio de páginas crudas.
+type Archive struct {
+	Dir string
+
+	// Cipher, si no es nil, cifra las páginas nuevas. Las ya guardadas sin
+	// cifrar se siguen pudiendo leer.
+	Cipher *encrypt.Cipher
+
+	// Encoder, si no es nil, comprime las páginas nuevas con zstd en lugar
+	// de gzip (ver EnableCompression).
+	Encoder *compress.Encoder
+
+	dictsOnce sync.Once
+	dictsErr  error
+}
+
+func New(dir string) *Archive {
+	return &Archive{Dir: dir}
+}
+
+// Sum devuelve el hash con el que se guardaría data.
+func Sum(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+// Path devuelve la ruta del archivo de un hash: la de zstd si la página se
+// guardó así y si no la de gzip.
+func (a *Archive) Path(sum string) string {
+	if p := a.path(sum, ".zst"); exists(p) {
+		return p
+	}
+	return a.path(sum, ".gz")
+}
+
+func (a *Archive) path(sum, ext string) string {
+	return filepath.Join(a.Dir, sum[:2], sum[2:4], sum+ext)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Put guarda data si no estaba y devuelve su hash.
+func (a *Archive) Put(data []byte) (string, error) {
+	sum := Sum(data)
+	if exists(a.path(sum, ".zst")) || exists(a.path(sum, ".gz")) {
+		return sum, nil
+	}
+	ext := ".gz"
+	if a.Encoder != nil {
+		ext = ".zst"
+	}
+	if err := a.write(a.path(sum, ext), data); err != nil {
+		return "", err
+	}
+	return sum, nil
+}
+
+// write comprime según la extensión de path, cifra si hay clave y guarda.
+func (a *Archive) write(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creando directorio del archivo crudo: %w", err)
+	}
+	var stored []byte
+	if filepath.Ext(path) == ".zst" {
+		stored = a.Encoder.Encode(data)
+	} else {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		stored = buf.Bytes()
+	}
+	if a.Cipher != nil {
+		var err error
+		if stored, err = a.Cipher.Encrypt(stored); err != nil {
+			return fmt.Errorf("error cifrando página cruda: %w", err)
+		}
+	}
+	// Se escribe a un temporal y se renombra para no dejar archivos a medias.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, stored, 0o644); err != nil {
+		return fmt.Errorf("error guardando página cruda: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error guardando página cruda: %w", err)
+	}
+	return nil
+}
+
+// Get lee, descifra si hace falta y descomprime el contenido de un hash.
+func (a *Archive) Get(sum string) ([]byte, error) {
+	data, err := a.read(a.Path(sum))
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo página cruda %s: %w", sum, err)
+	}
+	return data, nil
+}
+
+func (a *Archive) read(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if encrypt.IsEncrypted(data) {
+		if a.Cipher == nil {
+			return nil, encrypt.ErrNoKey
+		}
+		if data, err = a.Cipher.Decrypt(data); err != nil {
+			return nil, err
+		}
+	}
+	if filepath.Ext(path) == ".zst" {
+		if err := a.loadDicts(); err != nil {
+			return nil, err
+		}
+		return compress.Decode(data)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(zr)
+}
+
+// pages recorre los archivos de páginas (no los diccionarios ni los
+// temporales).
+func (a *Archive) pages(fn func(path string) error) error {
+	return filepath.WalkDir(a.Dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == filepath.Join(a.Dir, dictDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".gz" && ext != ".zst" {
+			return nil
+		}
+		return fn(path)
+	})
+}
+
+// EncryptAll cifra las páginas guardadas antes de activar el cifrado.
+// Devuelve cuántos archivos cifró.
+func (a *Archive) EncryptAll() (int, error) {
+	if a.Cipher == nil {
+		return 0, fmt.Errorf("el archivo crudo no tiene clave de cifrado")
+	}
+	n := 0
+	err := a.pages(func(path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if encrypt.IsEncrypted(data) {
+			return nil
+		}
+		enc, err := a.Cipher.Encrypt(data)
+		if err != nil {
+			return err
+		}
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, enc, 0o644); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		return n, fmt.Errorf("error cifrando el archivo crudo: %w", err)
+	}
+	return n, nil
+}
+
+// EnableCompression activa zstd para las páginas nuevas, con el nivel
+// indicado y el último diccionario entrenado (sin diccionario si no hay
+// ninguno).
+func (a *Archive) EnableCompression(level string) error {
+	dicts, err := a.Dicts()
+	if err != nil {
+		return err
+	}
+	var d *compress.Dict
+	if len(dicts) > 0 {
+		d = dicts[len(dicts)-1]
+	}
+	enc, err := compress.NewEncoder(level, d)
+	if err != nil {
+		return err
+	}
+	a.Encoder = enc
+	return nil
+}
+
+// Dicts devuelve los diccionarios del archivo, del más viejo al más nuevo.
+func (a *Archive) Dicts() ([]*compress.Dict, error) {
+	files, err := os.ReadDir(filepath.Join(a.Dir, dictDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo los diccionarios del archivo crudo: %w", err)
+	}
+	// Los nombres empiezan con la fecha: en orden alfabético, el último es
+	// el más nuevo.
+	var names []string
+	for _, f := range files {
+		if filepath.Ext(f.Name()) == ".dict" {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+	var out []*compress.Dict
+	for _, name := range names {
+		_, idPart, _ := strings.Cut(strings.TrimSuffix(name, ".dict"), "-")
+		id, err := strconv.ParseUint(idPart, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("diccionario con nombre inválido: %s", name)
+		}
+		data, err := os.ReadFile(filepath.Join(a.Dir, dictDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo el diccionario %s: %w", name, err)
+		}
+		out = append(out, &compress.Dict{ID: uint32(id), Data: data})
+	}
+	return out, nil
+}
+
+// loadDicts registra una vez los diccionarios del archivo para leer las
+// páginas guardadas con zstd.
+func (a *Archive) loadDicts() error {
+	a.dictsOnce.Do(func() {
+		var dicts []*compress.Dict
+		dicts, a.dictsErr = a.Dicts()
+		for _, d := range dicts {
+			compress.Register(d)
+		}
+	})
+	return a.dictsErr
+}
+
+// AddDict guarda un diccionario nuevo; desde el próximo EnableCompression
+// es el que se usa.
+func (a *Archive) AddDict(d *compress.Dict) error {
+	dir := filepath.Join(a.Dir, dictDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("error creando el directorio de diccionarios: %w", err)
+	}
+	name := fmt.Sprintf("%s-%d.dict", time.Now().UTC().Format("20060102T150405"), d.ID)
+	if err := os.WriteFile(filepath.Join(dir, name), d.Data, 0o644); err != nil {
+		return fmt.Errorf("error guardando el diccionario: %w", err)
+	}
+	compress.Register(d)
+	return nil
+}
+
+// Samples devuelve hasta n páginas al azar, para entrenar un diccionario o
+// medir la compresión.
+func (a *Archive) Samples(n int) ([][]byte, error) {
+	var paths []string
+	if err := a.pages(func(path string) error {
+		paths = append(paths, path)
+		return nil
+	}); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("error recorriendo el archivo crudo: %w", err)
+	}
+	rand.Shuffle(len(paths), func(i, j int) { paths[i], paths[j] = paths[j], paths[i] })
+	var out [][]byte
+	for _, p := range paths[:min(n, len(paths))] {
+		data, err := a.read(p)
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo página cruda %s: %w", filepath.Base(p), err)
+		}
+		out = append(out, data)
+	}
+	return out, nil
+}
+
+// Usage es lo que ocupan las páginas del archivo según su formato.
+type Usage struct {
+	Gzip, Zstd           int   // archivos
+	GzipBytes, ZstdBytes int64 // bytes en disco
+}
+
+// Usage mide lo que ocupa el archivo.
+func (a *Archive) Usage() (Usage, error) {
+	var u Usage
+	err := a.pages(func(path string) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if filepath.Ext(path) == ".zst" {
+			u.Zstd++
+			u.ZstdBytes += info.Size()
+		} else {
+			u.Gzip++
+			u.GzipBytes += info.Size()
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return u, fmt.Errorf("error recorriendo el archivo crudo: %w", err)
+	}
+	return u, nil
+}
+
+// Recompress vuelve a guardar las páginas con la compresión actual: con
+// Encoder, en zstd con su diccionario; sin él, en gzip. progress, si no es
+// nil, recibe cuántos archivos lleva revisados. Devuelve cuántos cambió y
+// los bytes en disco antes y después.
+func (a *Archive) Recompress(progress func(done int)) (changed int, before, after int64, err error) {
+	ext := ".gz"
+	if a.Encoder != nil {
+		ext = ".zst"
+	}
+	done := 0
+	err = a.pages(func(path string) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		before += info.Size()
+		done++
+		if progress != nil {
+			progress(done)
+		}
+		current := filepath.Ext(path)
+		// Un zstd con el diccionario actual ya está como debe.
+		if current == ext && (ext == ".gz" || a.usesCurrentDict(path)) {
+			after += info.Size()
+			return nil
+		}
+		data, err := a.read(path)
+		if err != nil {
+			return fmt.Errorf("error leyendo %s: %w", filepath.Base(path), err)
+		}
+		target := strings.TrimSuffix(path, current) + ext
+		if err := a.write(target, data); err != nil {
+			return err
+		}
+		if target != path {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+		if info, err := os.Stat(target); err == nil {
+			after += info.Size()
+		}
+		changed++
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return changed, before, after, fmt.Errorf("error recomprimiendo el archivo crudo: %w", err)
+	}
+	return changed, before, after, nil
+}
+
+// usesCurrentDict indica si la página en path se comprimió con el
+// diccionario del Encoder (o sin diccionario, si el Encoder no tiene).
+func (a *Archive) usesCurrentDict(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil || encrypt.IsEncrypted(data) {
+		// Cifrada no se puede saber sin descifrar: se recomprime.
+		return false
+	}
+	var want uint32
+	if a.Encoder.Dict != nil {
+		want = a.Encoder.Dict.ID
+	}
+	id, ok := compress.DictID(data)
+	return ok && id == want
+}
@@ -0,0 +1,47 @@
+package relevance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"go-collector/article"
+)
+
+// auditRecord es una línea del archivo de auditoría de selección del corpus.
+type auditRecord struct {
+	ID          int64                `json:"id"`
+	Source      string               `json:"source"`
+	URL         string               `json:"url"`
+	Title       string               `json:"title"`
+	Published   time.Time            `json:"published"`
+	Explanation *article.Explanation `json:"explanation"`
+}
+
+// Audit escribe en formato JSON Lines cada artículo con la explicación de su
+// puntaje, para documentar exactamente cómo se seleccionó el corpus.
+type Audit struct {
+	enc *json.Encoder
+	f   *Filter
+}
+
+// NewAudit prepara la auditoría en w. Los artículos guardados sin
+// explicación se evalúan con f; si f es nil, salen con la explicación en
+// null.
+func NewAudit(w io.Writer, f *Filter) *Audit {
+	return &Audit{enc: json.NewEncoder(w), f: f}
+}
+
+// Write agrega a a la auditoría.
+func (au *Audit) Write(a *article.Article) error {
+	exp := a.Explanation
+	if exp == nil && au.f != nil {
+		exp = au.f.Explain(a)
+	}
+	rec := auditRecord{ID: a.ID, Source: a.Source, URL: a.URL, Title: a.Title, Published: a.Published, Explanation: exp}
+	if err := au.enc.Encode(rec); err != nil {
+		return fmt.Errorf("error escribiendo auditoría: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package relevance
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"go-collector/article"
+	"go-collector/config"
+	"go-collector/query"
+	"go-collector/sentiment"
+)
+
+// defaultWindow es la distancia (en palabras) que se usa si la regla no define Window.
+const defaultWindow = 8
+
+// Result es la evaluación de un artículo.
+type Result struct {
+	Relevant bool
+	Matched  []string // términos con al menos una mención válida
+	RuleHits []string // reglas de supresión que se activaron
+}
+
+type rule struct {
+	name    string
+	term    []string
+	context map[string]bool
+	window  int
+	pattern *regexp.Regexp
+}
+
+// Filter decide si un artículo menciona realmente la entidad monitoreada,
+// descartando menciones en contextos negativos configurados.
+type Filter struct {
+	terms [][]string // cada término tokenizado
+	names []string   // forma original de cada término
+	rules []rule
+
+	weights map[string]float64
+
+	mu   sync.Mutex
+	hits map[string]int
+}
+
+// NewFilter compila el filtro a partir de la consulta de la campaña (incluyendo
+// sus variantes) y de las reglas de supresión y pesos por fuente.
+func NewFilter(q config.Query, cfg config.Relevance) (*Filter, error) {
+	f := &Filter{hits: make(map[string]int), weights: cfg.SourceWeights}
+	rules := cfg.Suppress
+	seen := make(map[string]bool)
+	for _, term := range query.Expand(q) {
+		// "#UdeA" y "UdeA" se tokenizan igual: basta con uno.
+		tokens := tokenize(strings.TrimPrefix(term, "#"))
+		key := strings.Join(tokens, " ")
+		if len(tokens) == 0 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		f.terms = append(f.terms, tokens)
+		f.names = append(f.names, term)
+	}
+
+	for i, r := range rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("regla_%d", i+1)
+		}
+		compiled := rule{name: name, term: tokenize(r.Term), window: r.Window}
+		if compiled.window <= 0 {
+			compiled.window = defaultWindow
+		}
+		if len(r.Context) > 0 {
+			compiled.context = make(map[string]bool)
+			for _, c := range r.Context {
+				for _, tok := range tokenize(c) {
+					compiled.context[tok] = true
+				}
+			}
+		}
+		if r.Pattern != "" {
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("regla %s: expresión regular inválida: %w", name, err)
+			}
+			compiled.pattern = re
+		}
+		if compiled.pattern == nil && (len(compiled.term) == 0 || compiled.context == nil) {
+			return nil, fmt.Errorf("regla %s: debe definir pattern, o term y context", name)
+		}
+		f.rules = append(f.rules, compiled)
+	}
+	return f, nil
+}
+
+// Evaluate revisa título, resumen y cuerpo del artículo.
+func (f *Filter) Evaluate(a *article.Article) Result {
+	text := strings.Join([]string{a.Title, a.Summary, a.Body}, "\n")
+	tokens := tokenize(text)
+
+	var res Result
+	hit := make(map[string]bool)
+
+	for _, r := range f.rules {
+		if r.pattern != nil && r.pattern.MatchString(text) {
+			hit[r.name] = true
+		}
+	}
+
+	for i, term := range f.terms {
+		valid := false
+		for _, pos := range occurrences(tokens, term) {
+			suppressed := false
+			for _, r := range f.rules {
+				if r.context != nil && sameTokens(term, r.term) && inContext(tokens, pos, len(term), r) {
+					hit[r.name] = true
+					suppressed = true
+				}
+			}
+			if !suppressed {
+				valid = true
+			}
+		}
+		if valid {
+			res.Matched = append(res.Matched, f.names[i])
+		}
+	}
+
+	for name := range hit {
+		res.RuleHits = append(res.RuleHits, name)
+	}
+	sort.Strings(res.RuleHits)
+
+	patternHit := false
+	for _, r := range f.rules {
+		if r.pattern != nil && hit[r.name] {
+			patternHit = true
+		}
+	}
+	res.Relevant = len(res.Matched) > 0 && !patternHit
+
+	f.mu.Lock()
+	for name := range hit {
+		f.hits[name]++
+	}
+	f.mu.Unlock()
+	return res
+}
+
+// Explain evalúa el artículo y devuelve los componentes del puntaje, listos
+// para guardarse junto al artículo y exportarse en la auditoría (ver Audit),
+// con el tono del artículo si hay léxico para su idioma.
+// Score = peso de la fuente × términos con mención válida (0 si no es relevante).
+func (f *Filter) Explain(a *article.Article) *article.Explanation {
+	res := f.Evaluate(a)
+	weight := 1.0
+	if w, ok := f.weights[a.Source]; ok {
+		weight = w
+	}
+
+	exp := &article.Explanation{
+		Relevant:     res.Relevant,
+		MatchedTerms: res.Matched,
+		RuleHits:     res.RuleHits,
+		SourceWeight: weight,
+	}
+	if exp.MatchedTerms == nil {
+		exp.MatchedTerms = []string{}
+	}
+	if exp.RuleHits == nil {
+		exp.RuleHits = []string{}
+	}
+	if res.Relevant {
+		exp.Score = weight * float64(len(res.Matched))
+	}
+	if s, ok := sentiment.Article(a); ok {
+		exp.Sentiment = &s.Value
+	}
+	return exp
+}
+
+// RuleHit es cuántas veces se activó una regla de supresión.
+type RuleHit struct {
+	Rule string
+	Hits int
+}
+
+// RuleHits devuelve cuántas veces se activó cada regla desde que se creó el
+// filtro, en el orden de la configuración (las que no se activaron, en
+// cero): sirve para ajustar las reglas.
+func (f *Filter) RuleHits() []RuleHit {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]RuleHit, len(f.rules))
+	for i, r := range f.rules {
+		out[i] = RuleHit{Rule: r.name, Hits: f.hits[r.name]}
+	}
+	return out
+}
+
+// HasTerms indica si el filtro tiene términos que buscar: sin ellos ningún
+// artículo es relevante y solo sirven las reglas con pattern.
+func (f *Filter) HasTerms() bool {
+	return len(f.terms) > 0
+}
+
+// inContext indica si alguna palabra de contexto de la regla está a menos de
+// window palabras de la mención que empieza en pos.
+func inContext(tokens []string, pos, length int, r rule) bool {
+	from := max(0, pos-r.window)
+	to := min(len(tokens), pos+length+r.window)
+	for i := from; i < to; i++ {
+		if i >= pos && i < pos+length {
+			continue
+		}
+		if r.context[tokens[i]] {
+			return true
+		}
+	}
+	return false
+}
+
+func occurrences(tokens, term []string) []int {
+	var out []int
+	for i := 0; i+len(term) <= len(tokens); i++ {
+		if sameTokens(tokens[i:i+len(term)], term) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func sameTokens(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize separa en palabras en minúscula y sin tildes.
+func tokenize(s string) []string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	if folded, _, err := transform.String(t, s); err == nil {
+		s = folded
+	}
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,71 @@
+package report
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"image"
+	"image/png"
+	"slices"
+
+	"go-collector/chart"
+	"go-collector/config"
+	"go-collector/storage"
+)
+
+// buildCharts arma los gráficos del reporte desde la tabla de agregados
+// diarios. El filtro de texto no aplica a los agregados: los gráficos muestran
+// el volumen de las fuentes e idioma filtrados.
+func buildCharts(store *storage.Store, data *Data, filters config.ReportFilters) ([]chart.Chart, error) {
+	if err := store.RefreshDailyStats(data.From); err != nil {
+		return nil, err
+	}
+	all, err := store.DailyStats(data.From, data.To)
+	if err != nil {
+		return nil, err
+	}
+	var stats []storage.DailyStat
+	for _, st := range all {
+		if len(filters.Sources) > 0 && !slices.Contains(filters.Sources, st.Source) {
+			continue
+		}
+		if filters.Language != "" && st.Language != filters.Language {
+			continue
+		}
+		stats = append(stats, st)
+	}
+
+	return []chart.Chart{
+		&chart.TimeSeries{Title: "Artículos por día", Series: chart.VolumeBySource(stats)},
+		&chart.Bar{Title: "Artículos por fuente", Items: chart.TotalsBy(stats, "source", 8)},
+		&chart.Pie{Title: "Artículos por idioma", Items: chart.TotalsBy(stats, "language", 6)},
+	}, nil
+}
+
+func chartsSVG(charts []chart.Chart) ([]htmltemplate.HTML, error) {
+	var out []htmltemplate.HTML
+	for _, c := range charts {
+		var buf bytes.Buffer
+		if err := c.WriteSVG(&buf); err != nil {
+			return nil, err
+		}
+		// El SVG lo generamos nosotros con los textos escapados.
+		out = append(out, htmltemplate.HTML(buf.String()))
+	}
+	return out, nil
+}
+
+func chartsImages(charts []chart.Chart) ([]image.Image, error) {
+	var out []image.Image
+	for _, c := range charts {
+		var buf bytes.Buffer
+		if err := c.WritePNG(&buf); err != nil {
+			return nil, err
+		}
+		img, err := png.Decode(&buf)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, img)
+	}
+	return out, nil
+}
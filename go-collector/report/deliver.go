@@ -0,0 +1,90 @@
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-collector/config"
+)
+
+// Deliver envía el reporte a todos sus destinos. Un destino fallido no impide
+// intentar los siguientes; se devuelve el primer error.
+func Deliver(out *Output, destinations []config.ReportDestination, smtpCfg config.SMTP, now time.Time) error {
+	var firstErr error
+	for _, d := range destinations {
+		var err error
+		switch d.Type {
+		case "file":
+			err = deliverFile(out, d.Path, now)
+		case "email":
+			err = deliverEmail(out, d.To, smtpCfg, now)
+		default:
+			err = fmt.Errorf("tipo de destino desconocido: %s (use file o email)", d.Type)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("reporte %s, destino %s: %w", out.Name, d.Type, err)
+		}
+	}
+	return firstErr
+}
+
+func deliverFile(out *Output, pattern string, now time.Time) error {
+	if pattern == "" {
+		pattern = "reports/{name}-{date}.{ext}"
+	}
+	path := strings.NewReplacer("{name}", out.Name, "{date}", now.Format("2006-01-02"), "{ext}", out.Extension).Replace(pattern)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, out.Data, 0o644)
+}
+
+// deliverEmail envía los reportes HTML/Markdown en el cuerpo del correo y los
+// PDF como adjunto.
+func deliverEmail(out *Output, to []string, cfg config.SMTP, now time.Time) error {
+	if cfg.Host == "" || cfg.From == "" {
+		return fmt.Errorf("falta configurar smtp.host y smtp.from")
+	}
+	if len(to) == 0 {
+		return fmt.Errorf("el destino email no tiene destinatarios")
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+
+	subject := fmt.Sprintf("%s - %s", out.Name, now.Format("2006-01-02"))
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\n",
+		cfg.From, strings.Join(to, ", "), mime.QEncoding.Encode("utf-8", subject))
+
+	if out.Extension == "pdf" {
+		boundary := fmt.Sprintf("collector-%d", now.UnixNano())
+		fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", boundary)
+		fmt.Fprintf(&msg, "--%s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\nSe adjunta el reporte %s (%d artículos).\r\n", boundary, out.Name, out.Articles)
+		fmt.Fprintf(&msg, "--%s\r\nContent-Type: application/pdf\r\nContent-Transfer-Encoding: base64\r\nContent-Disposition: attachment; filename=\"%s-%s.pdf\"\r\n\r\n",
+			boundary, out.Name, now.Format("2006-01-02"))
+		enc := base64.StdEncoding.EncodeToString(out.Data)
+		for len(enc) > 76 {
+			msg.WriteString(enc[:76] + "\r\n")
+			enc = enc[76:]
+		}
+		fmt.Fprintf(&msg, "%s\r\n--%s--\r\n", enc, boundary)
+	} else {
+		fmt.Fprintf(&msg, "Content-Type: %s\r\n\r\n", out.ContentType)
+		msg.Write(out.Data)
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return smtp.SendMail(fmt.Sprintf("%s:%d", cfg.Host, port), auth, cfg.From, to, msg.Bytes())
+}
@@ -0,0 +1,182 @@
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+)
+
+// Generador mínimo de PDF: solo texto en Helvetica sobre páginas A4, suficiente
+// para los reportes mensuales sin depender de herramientas externas.
+const (
+	pdfPageWidth    = 595 // A4 en puntos
+	pdfPageHeight   = 842
+	pdfMargin       = 50
+	pdfFontSize     = 10
+	pdfLineHeight   = 14
+	pdfCharsPerLine = 95
+)
+
+// WritePDF escribe las líneas de texto como documento PDF, con saltos de página
+// automáticos. Los títulos Markdown (#) se muestran en negrita. Las imágenes
+// (los gráficos del reporte) van después del texto, dos por página.
+func WritePDF(w io.Writer, title string, lines []string, images ...image.Image) error {
+	perPage := (pdfPageHeight - 2*pdfMargin) / pdfLineHeight
+	var wrapped []string
+	for _, l := range lines {
+		wrapped = append(wrapped, wrap(l, pdfCharsPerLine)...)
+	}
+	var pages [][]string
+	for len(wrapped) > 0 {
+		n := min(perPage, len(wrapped))
+		pages = append(pages, wrapped[:n])
+		wrapped = wrapped[n:]
+	}
+	if len(pages) == 0 {
+		pages = [][]string{{""}}
+	}
+
+	// Objetos: 1 catálogo, 2 árbol de páginas, 3 y 4 fuentes, 5 info; luego
+	// las imágenes y por cada página su objeto página y su contenido.
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // árbol de páginas, se completa al final
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Title (%s) /Producer (go-collector) >>", pdfEscape(title)),
+	}
+	var kids []string
+	addPage := func(content, xobjects string) {
+		page := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >>%s >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, xobjects, page+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content),
+		)
+	}
+
+	for _, page := range pages {
+		var content bytes.Buffer
+		y := pdfPageHeight - pdfMargin
+		for _, line := range page {
+			font := "F1"
+			if strings.HasPrefix(line, "#") {
+				font, line = "F2", strings.TrimLeft(line, "# ")
+			}
+			fmt.Fprintf(&content, "BT /%s %d Tf %d %d Td (%s) Tj ET\n", font, pdfFontSize, pdfMargin, y, pdfEscape(line))
+			y -= pdfLineHeight
+		}
+		addPage(content.String(), "")
+	}
+
+	for i := 0; i < len(images); i += 2 {
+		var content bytes.Buffer
+		var xobjects []string
+		top := float64(pdfPageHeight - pdfMargin)
+		for j, img := range images[i:min(i+2, len(images))] {
+			obj, err := pdfImage(img)
+			if err != nil {
+				return err
+			}
+			objects = append(objects, obj)
+			name := fmt.Sprintf("Im%d", j)
+			xobjects = append(xobjects, fmt.Sprintf("/%s %d 0 R", name, len(objects)))
+
+			// Escala al ancho útil de la página conservando la proporción.
+			b := img.Bounds()
+			w := float64(pdfPageWidth - 2*pdfMargin)
+			h := w * float64(b.Dy()) / float64(b.Dx())
+			top -= h
+			fmt.Fprintf(&content, "q %.1f 0 0 %.1f %d %.1f cm /%s Do Q\n", w, h, pdfMargin, top, name)
+			top -= 20
+		}
+		addPage(content.String(), " /XObject << "+strings.Join(xobjects, " ")+" >>")
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// pdfImage codifica la imagen como RGB comprimido con Flate.
+func pdfImage(img image.Image) (string, error) {
+	b := img.Bounds()
+	var data bytes.Buffer
+	zw := zlib.NewWriter(&data)
+	row := make([]byte, 0, 3*b.Dx())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row = row[:0]
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			row = append(row, byte(r>>8), byte(g>>8), byte(bl>>8))
+		}
+		if _, err := zw.Write(row); err != nil {
+			return "", err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream",
+		b.Dx(), b.Dy(), data.Len(), data.Bytes()), nil
+}
+
+// pdfEscape convierte a WinAnsi (Latin-1 para tildes y eñes) y escapa los
+// caracteres especiales de las cadenas PDF.
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 128:
+			b.WriteRune(r)
+		case r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+func wrap(line string, width int) []string {
+	runes := []rune(line)
+	if len(runes) <= width {
+		return []string{line}
+	}
+	var out []string
+	for len(runes) > width {
+		cut := width
+		for i := width; i > width/2; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		out = append(out, string(runes[:cut]))
+		runes = []rune(strings.TrimLeft(string(runes[cut:]), " "))
+	}
+	return append(out, string(runes))
+}
@@ -0,0 +1,159 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"image"
+	"sort"
+	"strings"
+	"time"
+
+	"go-collector/accounts"
+	"go-collector/article"
+	"go-collector/buildinfo"
+	"go-collector/chart"
+	"go-collector/config"
+	"go-collector/storage"
+)
+
+// Output es un reporte generado, listo para enviarse a sus destinos.
+type Output struct {
+	Name        string
+	Extension   string // html, md o pdf
+	ContentType string
+	Data        []byte
+	Articles    int
+}
+
+// KeyValue es una estructura auxiliar para ordenar mapas.
+type KeyValue struct {
+	Key   string
+	Value int
+}
+
+// Data es lo que reciben las plantillas.
+type Data struct {
+	Name       string
+	From, To   time.Time
+	Generated  time.Time
+	Total      int // artículos del período
+	BySource   []KeyValue
+	ByLanguage []KeyValue
+
+	// Articles son los artículos del período; solo la plantilla digest los
+	// lista y solo para ella se cargan.
+	Articles []*article.Article
+
+	// Charts son los gráficos en SVG; solo la plantilla summary los incluye.
+	Charts []htmltemplate.HTML
+	// Accounts es la evolución de las cuentas monitoreadas en el período
+	// (collector accounts); solo la plantilla summary la incluye.
+	Accounts []accounts.Series
+
+	// Collector identifica el recolector y la configuración; va al pie.
+	Collector buildinfo.Stamp
+}
+
+// Generate arma el reporte con los artículos del período que termina en now;
+// stamp va al pie del reporte. La plantilla summary solo muestra totales: se
+// cuentan por bloques en paralelo (ver storage.CountFiltered) sin cargar los
+// artículos; opts controla esos lectores y el progreso.
+func Generate(ctx context.Context, def config.Report, store *storage.Store, stamp buildinfo.Stamp, now time.Time, opts storage.ScanOptions) (*Output, error) {
+	from, err := def.PeriodStart(now)
+	if err != nil {
+		return nil, fmt.Errorf("reporte %s: %w", def.Name, err)
+	}
+	tmpl := def.Template
+	if tmpl == "" {
+		tmpl = "digest"
+	}
+
+	filter := storage.Filter{
+		From:     from,
+		To:       now,
+		Sources:  def.Filters.Sources,
+		Language: def.Filters.Language,
+		Query:    def.Filters.Query,
+	}
+	data := &Data{Name: def.Name, From: from, To: now, Generated: time.Now(), Collector: stamp}
+	if tmpl == "summary" {
+		counts, err := store.CountFiltered(ctx, filter, opts)
+		if err != nil {
+			return nil, fmt.Errorf("reporte %s: %w", def.Name, err)
+		}
+		data.Total = counts.Total
+		data.BySource, data.ByLanguage = sortedCounts(counts.BySource), sortedCounts(counts.ByLanguage)
+	} else {
+		if data.Articles, err = store.ListFiltered(filter); err != nil {
+			return nil, err
+		}
+		sources, languages := make(map[string]int), make(map[string]int)
+		for _, a := range data.Articles {
+			sources[a.Source]++
+			languages[a.Language]++
+		}
+		data.Total = len(data.Articles)
+		data.BySource, data.ByLanguage = sortedCounts(sources), sortedCounts(languages)
+	}
+
+	out := &Output{Name: def.Name, Articles: data.Total}
+
+	var charts []chart.Chart
+	if tmpl == "summary" {
+		if charts, err = buildCharts(store, data, def.Filters); err != nil {
+			return nil, fmt.Errorf("reporte %s: %w", def.Name, err)
+		}
+		snaps, err := store.AccountSnapshots(from, now)
+		if err != nil {
+			return nil, fmt.Errorf("reporte %s: %w", def.Name, err)
+		}
+		data.Accounts = accounts.Group(snaps)
+	}
+
+	var buf bytes.Buffer
+	switch def.Format {
+	case "html", "":
+		if data.Charts, err = chartsSVG(charts); err == nil {
+			err = renderHTML(&buf, tmpl, data)
+		}
+		out.Extension, out.ContentType = "html", "text/html; charset=utf-8"
+	case "markdown", "md":
+		err = renderMarkdown(&buf, tmpl, data)
+		out.Extension, out.ContentType = "md", "text/markdown; charset=utf-8"
+	case "pdf":
+		var md bytes.Buffer
+		var images []image.Image
+		if err = renderMarkdown(&md, tmpl, data); err == nil {
+			if images, err = chartsImages(charts); err == nil {
+				err = WritePDF(&buf, def.Name, strings.Split(md.String(), "\n"), images...)
+			}
+		}
+		out.Extension, out.ContentType = "pdf", "application/pdf"
+	default:
+		return nil, fmt.Errorf("reporte %s: formato desconocido %q (use html, markdown o pdf)", def.Name, def.Format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reporte %s: %w", def.Name, err)
+	}
+	out.Data = buf.Bytes()
+	return out, nil
+}
+
+func sortedCounts(m map[string]int) []KeyValue {
+	var kvList []KeyValue
+	for k, v := range m {
+		if k == "" {
+			k = "(desconocido)"
+		}
+		kvList = append(kvList, KeyValue{k, v})
+	}
+	sort.Slice(kvList, func(i, j int) bool {
+		if kvList[i].Value != kvList[j].Value {
+			return kvList[i].Value > kvList[j].Value
+		}
+		return kvList[i].Key < kvList[j].Key
+	})
+	return kvList
+}
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go-collector/buildinfo"
+	"go-collector/config"
+	"go-collector/schedule"
+	"go-collector/storage"
+)
+
+// Scheduler ejecuta los reportes según su propio horario, independiente del de
+// la recolección. La última ejecución de cada reporte se guarda en la base de
+// datos para no repetir envíos si el proceso se reinicia. Los reportes y el
+// servidor SMTP se leen de Config en cada revisión, así el daemon aplica una
+// configuración recargada sin reiniciar.
+type Scheduler struct {
+	Store  *storage.Store
+	Config *config.Live
+
+	// Scan controla los lectores en paralelo y el progreso de los reportes
+	// que solo cuentan (ver Generate).
+	Scan storage.ScanOptions
+}
+
+// Entry es un reporte con su horario interpretado.
+type Entry struct {
+	Report  config.Report
+	Cron    *schedule.Cron
+	LastRun time.Time
+	NextRun time.Time
+}
+
+// Entries interpreta los horarios y calcula la próxima ejecución de cada reporte.
+func (s *Scheduler) Entries(now time.Time) ([]Entry, error) {
+	var out []Entry
+	for _, r := range s.Config.Get().Reports {
+		c, err := schedule.Parse(r.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("reporte %s: %w", r.Name, err)
+		}
+		last, err := s.Store.LastReportRun(r.Name)
+		if err != nil {
+			return nil, err
+		}
+		e := Entry{Report: r, Cron: c, LastRun: last}
+		if last.IsZero() {
+			// Nunca corrió: su primera ejecución es el próximo horario desde ahora.
+			e.NextRun = c.Next(now)
+		} else {
+			e.NextRun = c.Next(last.In(now.Location()))
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// RunDue ejecuta los reportes cuya próxima ejecución ya pasó. Los reportes
+// que nunca corrieron (nuevos al iniciar o agregados en una recarga) se
+// registran para que su primera ejecución sea en el siguiente horario y no de
+// inmediato.
+func (s *Scheduler) RunDue(now time.Time) error {
+	entries, err := s.Entries(now)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.LastRun.IsZero() {
+			if err := s.Store.RecordReportRun(e.Report.Name, now, "ok", "registrado por el daemon"); err != nil {
+				return err
+			}
+			continue
+		}
+		if e.NextRun.After(now) {
+			continue
+		}
+		if err := s.Run(e.Report, now); err != nil {
+			log.Printf("error en reporte %s: %v", e.Report.Name, err)
+		}
+	}
+	return nil
+}
+
+// Run genera y entrega un reporte, registrando el resultado.
+func (s *Scheduler) Run(r config.Report, now time.Time) error {
+	cfg := s.Config.Get()
+	out, err := Generate(context.Background(), r, s.Store, buildinfo.NewStamp(cfg.Hash()), now, s.Scan)
+	if err == nil {
+		err = Deliver(out, r.Destinations, cfg.SMTP, now)
+	}
+	status, detail := "ok", ""
+	if err != nil {
+		status, detail = "error", err.Error()
+	} else {
+		detail = fmt.Sprintf("%d artículos", out.Articles)
+	}
+	if rerr := s.Store.RecordReportRun(r.Name, now, status, detail); rerr != nil {
+		return rerr
+	}
+	return err
+}
+
+// Daemon revisa cada minuto qué reportes corresponden, hasta que stop se cierre.
+func (s *Scheduler) Daemon(stop <-chan struct{}) error {
+	if err := s.RunDue(time.Now()); err != nil {
+		return err
+	}
+	if err := s.LogEntries(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case now := <-ticker.C:
+			if err := s.RunDue(now); err != nil {
+				log.Printf("error revisando reportes: %v", err)
+			}
+		}
+	}
+}
+
+// LogEntries registra en el log la próxima ejecución de cada reporte.
+func (s *Scheduler) LogEntries() error {
+	entries, err := s.Entries(time.Now())
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		log.Printf("reporte %s (%s): próxima ejecución %s", e.Report.Name, e.Cron, e.NextRun.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package report
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"text/template"
+)
+
+// Plantillas incluidas: "digest" lista los artículos del período en orden de
+// publicación; "summary" muestra los agregados por fuente e idioma, la
+// evolución de las cuentas monitoreadas y, en HTML y PDF, los gráficos de
+// volumen.
+
+const markdownDigest = `# {{.Name}}
+
+Período: {{.From.Format "2006-01-02 15:04"}} a {{.To.Format "2006-01-02 15:04"}} · {{.Total}} artículos
+
+{{range .Articles}}- **{{.Title}}** ({{.Source}}, {{.Published.Format "2006-01-02"}})
+  {{.URL}}
+{{else}}No hubo artículos en el período.
+{{end}}
+Generado por {{.Collector}}
+`
+
+const markdownSummary = `# {{.Name}}
+
+Período: {{.From.Format "2006-01-02"}} a {{.To.Format "2006-01-02"}}
+
+Total de artículos: {{.Total}}
+
+## Por fuente
+
+| Fuente | Artículos |
+|---|---:|
+{{range .BySource}}| {{.Key}} | {{.Value}} |
+{{end}}
+## Por idioma
+
+| Idioma | Artículos |
+|---|---:|
+{{range .ByLanguage}}| {{.Key}} | {{.Value}} |
+{{end}}{{if .Accounts}}
+## Cuentas
+
+| Cuenta | Seguidores | Cambio | Siguiendo | Publicaciones |
+|---|---:|---:|---:|---:|
+{{range .Accounts}}| {{.Platform}}/{{.Handle}} | {{.Last.Followers}} | {{printf "%+d" .FollowersDelta}} | {{.Last.Following}} | {{.Last.Posts}} |
+{{end}}{{range $s := .Accounts}}{{range .BioChanges}}
+Biografía de {{$s.Platform}}/{{$s.Handle}} cambiada el {{.Day.Format "2006-01-02"}}: {{.To}}
+{{end}}{{end}}{{end}}
+Generado por {{.Collector}}
+`
+
+const htmlDigest = `<!DOCTYPE html>
+<html lang="es"><head><meta charset="utf-8"><title>{{.Name}}</title>
+<style>body{font-family:sans-serif;max-width:800px;margin:2em auto;color:#222}li{margin:.5em 0}.meta{color:#666;font-size:.85em}</style>
+</head><body>
+<h1>{{.Name}}</h1>
+<p class="meta">Período: {{.From.Format "2006-01-02 15:04"}} a {{.To.Format "2006-01-02 15:04"}} · {{.Total}} artículos</p>
+<ul>
+{{range .Articles}}<li><a href="{{.URL}}">{{.Title}}</a><br><span class="meta">{{.Source}} · {{.Published.Format "2006-01-02 15:04"}}</span></li>
+{{else}}<li>No hubo artículos en el período.</li>
+{{end}}</ul>
+<p class="meta">Generado por {{.Collector}}</p>
+</body></html>
+`
+
+const htmlSummary = `<!DOCTYPE html>
+<html lang="es"><head><meta charset="utf-8"><title>{{.Name}}</title>
+<style>body{font-family:sans-serif;max-width:800px;margin:2em auto;color:#222}table{border-collapse:collapse}td,th{border:1px solid #ccc;padding:.3em .8em}.meta{color:#666;font-size:.85em}</style>
+</head><body>
+<h1>{{.Name}}</h1>
+<p class="meta">Período: {{.From.Format "2006-01-02"}} a {{.To.Format "2006-01-02"}}</p>
+<p>Total de artículos: <strong>{{.Total}}</strong></p>
+{{range .Charts}}<figure>{{.}}</figure>
+{{end}}<h2>Por fuente</h2>
+<table><tr><th>Fuente</th><th>Artículos</th></tr>
+{{range .BySource}}<tr><td>{{.Key}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>
+<h2>Por idioma</h2>
+<table><tr><th>Idioma</th><th>Artículos</th></tr>
+{{range .ByLanguage}}<tr><td>{{.Key}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{if .Accounts}}<h2>Cuentas</h2>
+<table><tr><th>Cuenta</th><th>Seguidores</th><th>Cambio</th><th>Siguiendo</th><th>Publicaciones</th></tr>
+{{range .Accounts}}<tr><td>{{.Platform}}/{{.Handle}}</td><td>{{.Last.Followers}}</td><td>{{printf "%+d" .FollowersDelta}}</td><td>{{.Last.Following}}</td><td>{{.Last.Posts}}</td></tr>
+{{end}}</table>
+{{range $s := .Accounts}}{{range .BioChanges}}<p>Biografía de {{$s.Platform}}/{{$s.Handle}} cambiada el {{.Day.Format "2006-01-02"}}: {{.To}}</p>
+{{end}}{{end}}{{end}}<p class="meta">Generado por {{.Collector}}</p>
+</body></html>
+`
+
+var (
+	markdownTemplates = map[string]*template.Template{
+		"digest":  template.Must(template.New("digest").Parse(markdownDigest)),
+		"summary": template.Must(template.New("summary").Parse(markdownSummary)),
+	}
+	htmlTemplates = map[string]*htmltemplate.Template{
+		"digest":  htmltemplate.Must(htmltemplate.New("digest").Parse(htmlDigest)),
+		"summary": htmltemplate.Must(htmltemplate.New("summary").Parse(htmlSummary)),
+	}
+)
+
+func renderMarkdown(w io.Writer, name string, data *Data) error {
+	t, ok := markdownTemplates[name]
+	if !ok {
+		return errUnknownTemplate(name)
+	}
+	return t.Execute(w, data)
+}
+
+func renderHTML(w io.Writer, name string, data *Data) error {
+	t, ok := htmlTemplates[name]
+	if !ok {
+		return errUnknownTemplate(name)
+	}
+	return t.Execute(w, data)
+}
+
+type errUnknownTemplate string
+
+func (e errUnknownTemplate) Error() string {
+	return "plantilla desconocida: " + string(e) + " (use digest o summary)"
+}
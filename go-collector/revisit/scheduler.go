@@ -0,0 +1,215 @@
+package revisit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/extract"
+	"go-collector/sitemap"
+	"go-collector/storage"
+)
+
+// maxPageSize limita la página descargada en una revisita.
+const maxPageSize = 5 << 20
+
+// Policy define cada cuánto revisitar un artículo según su edad y según qué tan
+// seguido cambian las notas de su dominio.
+type Policy struct {
+	// Intervalos base según la edad del artículo: las notas recientes (historias
+	// en desarrollo) cambian mucho más que las antiguas.
+	FreshInterval  time.Duration // edad < FreshAge
+	RecentInterval time.Duration // edad < RecentAge
+	OldInterval    time.Duration // resto
+	FreshAge       time.Duration
+	RecentAge      time.Duration
+
+	// MaxAge: a partir de esta edad solo se revisita si el sitemap indica cambios.
+	MaxAge time.Duration
+
+	// Umbrales de tasa de cambio del dominio: por encima de HighChangeRate el
+	// intervalo se reduce a la mitad, por debajo de LowChangeRate se duplica.
+	HighChangeRate float64
+	LowChangeRate  float64
+}
+
+// DefaultPolicy reemplaza el re-fetch ciego cada N días.
+func DefaultPolicy() Policy {
+	return Policy{
+		FreshInterval:  6 * time.Hour,
+		RecentInterval: 24 * time.Hour,
+		OldInterval:    7 * 24 * time.Hour,
+		FreshAge:       48 * time.Hour,
+		RecentAge:      7 * 24 * time.Hour,
+		MaxAge:         30 * 24 * time.Hour,
+		HighChangeRate: 0.3,
+		LowChangeRate:  0.05,
+	}
+}
+
+// Revisit es una revisita planificada.
+type Revisit struct {
+	Article *article.Article
+	Due     time.Time
+	Reason  string
+}
+
+// Scheduler decide qué artículos revisitar usando el lastmod de los sitemaps y
+// el historial de actualizaciones por dominio guardado en la base de datos.
+type Scheduler struct {
+	Store  *storage.Store
+	Client *http.Client
+	Policy Policy
+	// Extractor saca el texto de la nota, que es lo que se compara entre
+	// revisitas: el HTML cambia en cada descarga (publicidad, notas
+	// relacionadas, tokens) aunque la nota sea la misma.
+	Extractor extract.Extractor
+}
+
+func NewScheduler(store *storage.Store) *Scheduler {
+	return &Scheduler{
+		Store: store,
+		Client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+		Policy: DefaultPolicy(),
+	}
+}
+
+// Plan calcula las revisitas pendientes a la fecha now. entries son las entradas
+// de los sitemaps de noticias ya descargados (pueden ser nil).
+func (s *Scheduler) Plan(now time.Time, entries []sitemap.Entry) ([]Revisit, error) {
+	articles, err := s.Store.ListActive()
+	if err != nil {
+		return nil, err
+	}
+	domainStats, err := s.Store.DomainUpdateStats()
+	if err != nil {
+		return nil, err
+	}
+
+	lastmods := make(map[string]time.Time, len(entries))
+	for _, e := range entries {
+		lastmods[e.Loc] = e.Modified()
+	}
+
+	var plan []Revisit
+	for _, a := range articles {
+		last, err := s.Store.LastFetch(a.ID)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, err
+		}
+		var lastFetched time.Time
+		if last != nil {
+			lastFetched = last.FetchedAt
+		}
+
+		due, reason, ok := s.next(now, a, lastFetched, lastmods[a.URL], domainStats[a.Domain].ChangeRate())
+		if ok && !due.After(now) {
+			plan = append(plan, Revisit{Article: a, Due: due, Reason: reason})
+		}
+	}
+
+	sort.Slice(plan, func(i, j int) bool { return plan[i].Due.Before(plan[j].Due) })
+	return plan, nil
+}
+
+// next calcula la próxima revisita de un artículo. ok es false si no debe revisitarse más.
+func (s *Scheduler) next(now time.Time, a *article.Article, lastFetched, lastmod time.Time, changeRate float64) (time.Time, string, bool) {
+	if lastFetched.IsZero() {
+		return now, "sin descargas previas", true
+	}
+	// El sitemap declara una modificación posterior a nuestra última descarga.
+	if !lastmod.IsZero() && lastmod.After(lastFetched) {
+		return lastmod, "lastmod del sitemap posterior a la última descarga", true
+	}
+
+	published := a.Published
+	if published.IsZero() {
+		published = a.Collected
+	}
+	age := now.Sub(published)
+	if age > s.Policy.MaxAge {
+		return time.Time{}, "", false
+	}
+
+	interval := s.Policy.OldInterval
+	switch {
+	case age < s.Policy.FreshAge:
+		interval = s.Policy.FreshInterval
+	case age < s.Policy.RecentAge:
+		interval = s.Policy.RecentInterval
+	}
+
+	reason := fmt.Sprintf("intervalo %s por edad", interval)
+	switch {
+	case changeRate >= s.Policy.HighChangeRate:
+		interval /= 2
+		reason = fmt.Sprintf("intervalo %s (dominio cambia seguido: %.0f%%)", interval, changeRate*100)
+	case changeRate < s.Policy.LowChangeRate:
+		interval *= 2
+		reason = fmt.Sprintf("intervalo %s (dominio casi no cambia: %.0f%%)", interval, changeRate*100)
+	}
+	return lastFetched.Add(interval), reason, true
+}
+
+// Run descarga los artículos planificados y registra si su contenido cambió.
+// Si se cancela ctx se detiene y devuelve el error.
+func (s *Scheduler) Run(ctx context.Context, plan []Revisit) (changed int, err error) {
+	for _, r := range plan {
+		if err := ctx.Err(); err != nil {
+			return changed, err
+		}
+		hash, err := s.textHash(ctx, r.Article.URL)
+		if err != nil {
+			fmt.Printf("  Error revisitando %s: %v\n", r.Article.URL, err)
+			continue
+		}
+		ok, err := s.Store.RecordFetch(r.Article.ID, hash, time.Now().UTC())
+		if err != nil {
+			return changed, err
+		}
+		if ok {
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// textHash descarga la página y devuelve el hash de su texto extraído, con
+// los espacios normalizados.
+func (s *Scheduler) textHash(ctx context.Context, u string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "EthicalCrawler/1.0 (StudentResearch)")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error HTTP: status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return "", fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	res, err := s.Extractor.Extract(resp.Request.URL.String(), body)
+	if err != nil {
+		return "", fmt.Errorf("error extrayendo el texto: %w", err)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(res.Text), " ")))
+	return hex.EncodeToString(sum[:]), nil
+}
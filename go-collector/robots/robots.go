@@ -0,0 +1,305 @@
+// Package robots lee el robots.txt de los medios (RFC 9309) para que las
+// fuentes que descargan sus páginas (sitemap, scrape) no pidan las que el
+// medio excluye, y guarda cada uno en una caché que comparten todas las
+// fuentes y campañas del proceso.
+package robots
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"go-collector/cachestat"
+	"go-collector/crawler"
+)
+
+// maxSize es cuánto del robots.txt se lee; el RFC pide al menos 500 KiB.
+const maxSize = 500 << 10
+
+// Rules son las reglas de un robots.txt para un agente.
+type Rules struct {
+	rules []rule
+	// Sitemaps son las URLs de las líneas Sitemap:, que valen para todos
+	// los agentes.
+	Sitemaps []string
+}
+
+type rule struct {
+	allow   bool
+	length  int
+	pattern *regexp.Regexp
+}
+
+// AllowAll no excluye nada: lo que vale cuando el medio no tiene robots.txt.
+var AllowAll = &Rules{}
+
+// DisallowAll excluye todo: lo que vale mientras el robots.txt no responde.
+var DisallowAll = &Rules{rules: []rule{{allow: false, length: 1, pattern: regexp.MustCompile(`^/`)}}}
+
+// Parse lee el robots.txt con las reglas de agent: las de los grupos que lo
+// nombran (sin distinguir mayúsculas) o, si ninguno lo hace, las de los
+// grupos de *. Las líneas que no se entienden se ignoran.
+func Parse(body []byte, agent string) *Rules {
+	agent = strings.ToLower(agent)
+	r := &Rules{}
+	var mine, star []rule
+	var agents []string
+	inRules := false
+	sc := bufio.NewScanner(bytes.NewReader(body))
+	sc.Buffer(make([]byte, 0, 64<<10), maxSize)
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			// Un user-agent después de reglas empieza un grupo nuevo.
+			if inRules {
+				agents, inRules = nil, false
+			}
+			agents = append(agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue // "Disallow:" vacío no excluye nada
+			}
+			ru := rule{allow: key == "allow", length: len(value), pattern: compile(value)}
+			for _, a := range agents {
+				switch a {
+				case agent:
+					mine = append(mine, ru)
+				case "*":
+					star = append(star, ru)
+				}
+			}
+		case "sitemap":
+			if value != "" {
+				r.Sitemaps = append(r.Sitemaps, value)
+			}
+		}
+	}
+	r.rules = star
+	if mine != nil {
+		r.rules = mine
+	}
+	return r
+}
+
+// compile traduce un patrón de robots.txt (* cualquier texto, $ al final
+// ancla) a una expresión regular sobre la ruta.
+func compile(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// Allowed indica si las reglas permiten la ruta (con su consulta): decide la
+// regla más larga que coincide y, a igual largo, Allow.
+func (r *Rules) Allowed(path string) bool {
+	if path == "/robots.txt" {
+		return true
+	}
+	best, allowed := -1, true
+	for _, ru := range r.rules {
+		if ru.length < best || (ru.length == best && !ru.allow) || !ru.pattern.MatchString(path) {
+			continue
+		}
+		best, allowed = ru.length, ru.allow
+	}
+	return allowed
+}
+
+// cacheShards es en cuántas partes con su propio candado se reparte la
+// caché, para que las fuentes en paralelo no se esperen entre sí.
+const cacheShards = 16
+
+// unreachableTTL es cuánto se guarda un robots.txt que no respondió (error
+// de red o 5xx): mientras tanto el medio se da por excluido, pero se vuelve
+// a intentar antes de TTL para que una caída breve no pierda la ronda.
+const unreachableTTL = 5 * time.Minute
+
+// Cache guarda en memoria las reglas del robots.txt de cada origen
+// (esquema y host) durante TTL. Es segura desde varias goroutines: las
+// consultas del mismo origen que llegan mientras se descarga su robots.txt
+// esperan esa descarga en vez de repetirla.
+type Cache struct {
+	TTL  time.Duration
+	HTTP *http.Client
+
+	agent   string
+	shards  [cacheShards]shard
+	flight  singleflight.Group
+	stats   cachestat.Counter
+	blocked atomic.Int64
+}
+
+type shard struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	rules   *Rules
+	expires time.Time
+}
+
+// NewCache crea una caché vacía para el agente del recolector (el producto
+// de crawler.UserAgent).
+func NewCache(ttl time.Duration) *Cache {
+	agent, _, _ := strings.Cut(crawler.UserAgent, "/")
+	return &Cache{TTL: ttl, HTTP: &http.Client{Timeout: 15 * time.Second}, agent: agent}
+}
+
+// Stats devuelve los aciertos y fallos acumulados, por origen consultado.
+func (c *Cache) Stats() cachestat.Stats {
+	n := 0
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		n += len(s.entries)
+		s.mu.Unlock()
+	}
+	return c.stats.Snapshot(n)
+}
+
+// Blocked es cuántas páginas se dejaron de pedir porque su robots.txt las
+// excluye.
+func (c *Cache) Blocked() int64 { return c.blocked.Load() }
+
+// Allowed indica si el robots.txt del origen de pageURL permite pedirla. Si
+// ctx se cancela mientras se descarga, devuelve false con su error.
+func (c *Cache) Allowed(ctx context.Context, pageURL string) (bool, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return false, err
+	}
+	r, err := c.Rules(ctx, u.Scheme+"://"+u.Host)
+	if err != nil {
+		return false, err
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	if !r.Allowed(path) {
+		c.blocked.Add(1)
+		return false, nil
+	}
+	return true, nil
+}
+
+// Rules devuelve las reglas del origen (ej: https://www.udea.edu.co),
+// desde la caché si están vigentes. Un robots.txt que responde 4xx no
+// excluye nada; uno que no responde excluye todo (ver unreachableTTL).
+func (c *Cache) Rules(ctx context.Context, origin string) (*Rules, error) {
+	if r, ok := c.get(origin); ok {
+		c.stats.Hit()
+		return r, nil
+	}
+	// La descarga la comparten varias consultas: no se corta porque se
+	// cancele la que llegó primero, pero cada una deja de esperarla si se
+	// cancela la suya.
+	var leader atomic.Bool
+	ch := c.flight.DoChan(origin, func() (any, error) {
+		leader.Store(true)
+		r, ttl := c.download(context.WithoutCancel(ctx), origin)
+		c.put(origin, r, ttl)
+		return r, nil
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if leader.Load() {
+			c.stats.Miss()
+		} else {
+			c.stats.Shared()
+		}
+		return res.Val.(*Rules), nil
+	}
+}
+
+// download pide el robots.txt del origen y devuelve sus reglas y cuánto
+// guardarlas.
+func (c *Cache) download(ctx context.Context, origin string) (*Rules, time.Duration) {
+	body, status, err := c.fetch(ctx, origin+"/robots.txt")
+	switch {
+	case err != nil || status >= 500:
+		return DisallowAll, min(c.TTL, unreachableTTL)
+	case status != http.StatusOK:
+		return AllowAll, c.TTL
+	}
+	return Parse(body, c.agent), c.TTL
+}
+
+func (c *Cache) fetch(ctx context.Context, u string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
+	if err != nil {
+		return nil, 0, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
+
+func (c *Cache) shard(origin string) *shard {
+	h := fnv.New32a()
+	h.Write([]byte(origin))
+	return &c.shards[h.Sum32()%cacheShards]
+}
+
+func (c *Cache) get(origin string) (*Rules, bool) {
+	s := c.shard(origin)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[origin]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(s.entries, origin)
+		c.stats.Evict(1)
+		return nil, false
+	}
+	return e.rules, true
+}
+
+func (c *Cache) put(origin string, r *Rules, ttl time.Duration) {
+	s := c.shard(origin)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries == nil {
+		s.entries = make(map[string]entry)
+	}
+	s.entries[origin] = entry{rules: r, expires: time.Now().Add(ttl)}
+}
@@ -0,0 +1,187 @@
+package robots
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const robotsTxt = `# comentario
+User-agent: *
+Disallow: /privado/
+Allow: /privado/publico
+Disallow: /*.pdf$
+
+User-agent: OtroBot
+User-agent: ethicalcrawler
+Disallow: /buscar
+Allow: /buscar/noticias
+
+Sitemap: https://www.udea.edu.co/sitemap-news.xml
+`
+
+func TestParse(t *testing.T) {
+	r := Parse([]byte(robotsTxt), "EthicalCrawler")
+	for path, want := range map[string]bool{
+		"/":                      true,
+		"/buscar?q=udea":         false,
+		"/buscar/noticias/2024":  true,
+		"/privado/x":             true, // el grupo propio reemplaza al de *
+		"/robots.txt":            true,
+		"/noticias/informe.pdf":  true,
+		"/noticias/nota-1.html":  true,
+		"/buscar/noticiasviejas": true,
+	} {
+		if got := r.Allowed(path); got != want {
+			t.Errorf("%s: permitido %v, se esperaba %v", path, got, want)
+		}
+	}
+	if len(r.Sitemaps) != 1 || r.Sitemaps[0] != "https://www.udea.edu.co/sitemap-news.xml" {
+		t.Errorf("sitemaps %v", r.Sitemaps)
+	}
+
+	star := Parse([]byte(robotsTxt), "SinGrupo")
+	for path, want := range map[string]bool{
+		"/privado/x":             false,
+		"/privado/publico/nota":  true, // la regla más larga decide
+		"/noticias/informe.pdf":  false,
+		"/noticias/informe.pdf1": true,
+		"/buscar":                true,
+	} {
+		if got := star.Allowed(path); got != want {
+			t.Errorf("*: %s: permitido %v, se esperaba %v", path, got, want)
+		}
+	}
+}
+
+// origin sirve el robots.txt de cada host según status y cuenta las
+// peticiones; wait, si no es nil, retiene cada respuesta hasta cerrarse.
+type origin struct {
+	calls  atomic.Int64
+	status int
+	wait   chan struct{}
+}
+
+func (o *origin) RoundTrip(req *http.Request) (*http.Response, error) {
+	o.calls.Add(1)
+	if o.wait != nil {
+		<-o.wait
+	}
+	status := o.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(robotsTxt)),
+		Request:    req,
+	}, nil
+}
+
+func newCache(o *origin, ttl time.Duration) *Cache {
+	c := NewCache(ttl)
+	c.HTTP.Transport = o
+	return c
+}
+
+// Las páginas de un mismo medio pedidas a la vez descargan su robots.txt
+// una sola vez.
+func TestCacheConcurrentSameOrigin(t *testing.T) {
+	o := &origin{wait: make(chan struct{})}
+	c := newCache(o, time.Minute)
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u := fmt.Sprintf("https://www.udea.edu.co/buscar?q=%d", i)
+			if ok, err := c.Allowed(context.Background(), u); ok || err != nil {
+				t.Errorf("%s: permitido %v, error %v", u, ok, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(o.wait)
+	wg.Wait()
+
+	if calls := o.calls.Load(); calls != 1 {
+		t.Errorf("%d descargas del robots.txt, se esperaba 1", calls)
+	}
+	if s := c.Stats(); s.Misses != 1 || s.Hits+s.Shared != n-1 || s.Entries != 1 {
+		t.Errorf("estadísticas %+v, se esperaba 1 fallo, %d aciertos o compartidas y 1 entrada", s, n-1)
+	}
+	if b := c.Blocked(); b != n {
+		t.Errorf("%d páginas bloqueadas, se esperaban %d", b, n)
+	}
+}
+
+// Muchos medios desde muchas goroutines: cada robots.txt se descarga una
+// vez.
+func TestCacheConcurrentOrigins(t *testing.T) {
+	o := &origin{}
+	c := newCache(o, time.Minute)
+
+	const hosts, rounds = 50, 5
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rounds * hosts {
+				u := fmt.Sprintf("https://medio%d.example.com/noticias/%d", (i+g)%hosts, i)
+				if ok, err := c.Allowed(context.Background(), u); !ok || err != nil {
+					t.Errorf("%s: permitido %v, error %v", u, ok, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls := o.calls.Load(); calls != hosts {
+		t.Errorf("%d descargas, se esperaban %d", calls, hosts)
+	}
+	if s := c.Stats(); s.Entries != hosts || s.Lookups() != 8*rounds*hosts {
+		t.Errorf("estadísticas %+v, se esperaban %d entradas y %d consultas", s, hosts, 8*rounds*hosts)
+	}
+}
+
+// Sin robots.txt (4xx) todo se permite; si no responde (5xx), nada, y se
+// vuelve a pedir cuando vence.
+func TestCacheStatus(t *testing.T) {
+	missing := newCache(&origin{status: http.StatusNotFound}, time.Minute)
+	if ok, _ := missing.Allowed(context.Background(), "https://www.udea.edu.co/buscar"); !ok {
+		t.Error("un robots.txt inexistente no debe excluir nada")
+	}
+
+	o := &origin{status: http.StatusServiceUnavailable}
+	down := newCache(o, time.Millisecond)
+	if ok, _ := down.Allowed(context.Background(), "https://www.udea.edu.co/noticias"); ok {
+		t.Error("un robots.txt que no responde debe excluir todo")
+	}
+	time.Sleep(5 * time.Millisecond)
+	down.Allowed(context.Background(), "https://www.udea.edu.co/noticias")
+	if calls := o.calls.Load(); calls != 2 {
+		t.Errorf("%d descargas tras vencer la entrada, se esperaban 2", calls)
+	}
+}
+
+// Una consulta cancelada deja de esperar la descarga en curso.
+func TestCacheCanceled(t *testing.T) {
+	o := &origin{wait: make(chan struct{})}
+	defer close(o.wait)
+	c := newCache(o, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Allowed(ctx, "https://www.udea.edu.co/noticias"); err != context.DeadlineExceeded {
+		t.Errorf("error %v, se esperaba %v", err, context.DeadlineExceeded)
+	}
+}
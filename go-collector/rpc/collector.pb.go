@@ -0,0 +1,773 @@
+// API gRPC del recolector (collector grpc): iniciar rondas de recolección,
+// seguir su estado y recibir los artículos a medida que se guardan, sin
+// invocar el binario. El código Go de este paquete se genera con
+// protoc-gen-go y protoc-gen-go-grpc:
+//
+//   protoc --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative rpc/collector.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: rpc/collector.proto
+
+package rpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TriggerCollectionRequest elige qué consultar, como las opciones de
+// collector collect. Los campos vacíos dejan lo de la configuración.
+type TriggerCollectionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// source consulta solo esa fuente (aunque esté desactivada); vacío, las
+	// activadas.
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Query  string `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	// from y to son el rango de publicación (AAAA-MM-DD o lapso como 7d).
+	From      string   `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To        string   `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Languages []string `protobuf:"bytes,5,rep,name=languages,proto3" json:"languages,omitempty"`
+	// campaign es el nombre con que la ronda queda en el historial.
+	Campaign      string `protobuf:"bytes,6,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerCollectionRequest) Reset() {
+	*x = TriggerCollectionRequest{}
+	mi := &file_rpc_collector_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerCollectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerCollectionRequest) ProtoMessage() {}
+
+func (x *TriggerCollectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_collector_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerCollectionRequest.ProtoReflect.Descriptor instead.
+func (*TriggerCollectionRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_collector_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TriggerCollectionRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *TriggerCollectionRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *TriggerCollectionRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *TriggerCollectionRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *TriggerCollectionRequest) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *TriggerCollectionRequest) GetCampaign() string {
+	if x != nil {
+		return x.Campaign
+	}
+	return ""
+}
+
+type TriggerCollectionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         int64                  `protobuf:"varint,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerCollectionResponse) Reset() {
+	*x = TriggerCollectionResponse{}
+	mi := &file_rpc_collector_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerCollectionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerCollectionResponse) ProtoMessage() {}
+
+func (x *TriggerCollectionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_collector_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerCollectionResponse.ProtoReflect.Descriptor instead.
+func (*TriggerCollectionResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_collector_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TriggerCollectionResponse) GetRunId() int64 {
+	if x != nil {
+		return x.RunId
+	}
+	return 0
+}
+
+type GetRunStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         int64                  `protobuf:"varint,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRunStatusRequest) Reset() {
+	*x = GetRunStatusRequest{}
+	mi := &file_rpc_collector_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRunStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunStatusRequest) ProtoMessage() {}
+
+func (x *GetRunStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_collector_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetRunStatusRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_collector_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetRunStatusRequest) GetRunId() int64 {
+	if x != nil {
+		return x.RunId
+	}
+	return 0
+}
+
+// RunStatus es una ronda del historial (collector runs).
+type RunStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	RunId int64                  `protobuf:"varint,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	// status es running, ok, partial o failed.
+	Status  string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Started *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=started,proto3" json:"started,omitempty"`
+	// finished no viene mientras la ronda corre.
+	Finished      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=finished,proto3" json:"finished,omitempty"`
+	Campaign      string                 `protobuf:"bytes,5,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Sources       []*SourceStatus        `protobuf:"bytes,7,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunStatus) Reset() {
+	*x = RunStatus{}
+	mi := &file_rpc_collector_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunStatus) ProtoMessage() {}
+
+func (x *RunStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_collector_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunStatus.ProtoReflect.Descriptor instead.
+func (*RunStatus) Descriptor() ([]byte, []int) {
+	return file_rpc_collector_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RunStatus) GetRunId() int64 {
+	if x != nil {
+		return x.RunId
+	}
+	return 0
+}
+
+func (x *RunStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RunStatus) GetStarted() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Started
+	}
+	return nil
+}
+
+func (x *RunStatus) GetFinished() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Finished
+	}
+	return nil
+}
+
+func (x *RunStatus) GetCampaign() string {
+	if x != nil {
+		return x.Campaign
+	}
+	return ""
+}
+
+func (x *RunStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *RunStatus) GetSources() []*SourceStatus {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+// SourceStatus es el resultado de una fuente en la ronda.
+type SourceStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Fetched       int32                  `protobuf:"varint,2,opt,name=fetched,proto3" json:"fetched,omitempty"`
+	Stored        int32                  `protobuf:"varint,3,opt,name=stored,proto3" json:"stored,omitempty"`
+	Duplicates    int32                  `protobuf:"varint,4,opt,name=duplicates,proto3" json:"duplicates,omitempty"`
+	Partial       bool                   `protobuf:"varint,5,opt,name=partial,proto3" json:"partial,omitempty"`
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Skipped       bool                   `protobuf:"varint,7,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SourceStatus) Reset() {
+	*x = SourceStatus{}
+	mi := &file_rpc_collector_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SourceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SourceStatus) ProtoMessage() {}
+
+func (x *SourceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_collector_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SourceStatus.ProtoReflect.Descriptor instead.
+func (*SourceStatus) Descriptor() ([]byte, []int) {
+	return file_rpc_collector_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SourceStatus) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *SourceStatus) GetFetched() int32 {
+	if x != nil {
+		return x.Fetched
+	}
+	return 0
+}
+
+func (x *SourceStatus) GetStored() int32 {
+	if x != nil {
+		return x.Stored
+	}
+	return 0
+}
+
+func (x *SourceStatus) GetDuplicates() int32 {
+	if x != nil {
+		return x.Duplicates
+	}
+	return 0
+}
+
+func (x *SourceStatus) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+func (x *SourceStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SourceStatus) GetSkipped() bool {
+	if x != nil {
+		return x.Skipped
+	}
+	return false
+}
+
+// StreamArticlesRequest filtra los artículos como GET /articles de
+// collector serve.
+type StreamArticlesRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	RunId    int64                  `protobuf:"varint,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	Sources  []string               `protobuf:"bytes,2,rep,name=sources,proto3" json:"sources,omitempty"`
+	Language string                 `protobuf:"bytes,3,opt,name=language,proto3" json:"language,omitempty"`
+	// query exige todas sus palabras en el título, resumen o cuerpo.
+	Query string                 `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
+	From  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`
+	To    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=to,proto3" json:"to,omitempty"`
+	// limit corta el envío; cero no limita.
+	Limit int32 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	// include_withdrawn incluye los artículos retirados.
+	IncludeWithdrawn bool `protobuf:"varint,8,opt,name=include_withdrawn,json=includeWithdrawn,proto3" json:"include_withdrawn,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *StreamArticlesRequest) Reset() {
+	*x = StreamArticlesRequest{}
+	mi := &file_rpc_collector_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamArticlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamArticlesRequest) ProtoMessage() {}
+
+func (x *StreamArticlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_collector_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamArticlesRequest.ProtoReflect.Descriptor instead.
+func (*StreamArticlesRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_collector_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StreamArticlesRequest) GetRunId() int64 {
+	if x != nil {
+		return x.RunId
+	}
+	return 0
+}
+
+func (x *StreamArticlesRequest) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *StreamArticlesRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *StreamArticlesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *StreamArticlesRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *StreamArticlesRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *StreamArticlesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *StreamArticlesRequest) GetIncludeWithdrawn() bool {
+	if x != nil {
+		return x.IncludeWithdrawn
+	}
+	return false
+}
+
+type Article struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Author        string                 `protobuf:"bytes,5,opt,name=author,proto3" json:"author,omitempty"`
+	Domain        string                 `protobuf:"bytes,6,opt,name=domain,proto3" json:"domain,omitempty"`
+	Language      string                 `protobuf:"bytes,7,opt,name=language,proto3" json:"language,omitempty"`
+	Section       string                 `protobuf:"bytes,8,opt,name=section,proto3" json:"section,omitempty"`
+	Summary       string                 `protobuf:"bytes,9,opt,name=summary,proto3" json:"summary,omitempty"`
+	Body          string                 `protobuf:"bytes,10,opt,name=body,proto3" json:"body,omitempty"`
+	Published     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=published,proto3" json:"published,omitempty"`
+	Collected     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=collected,proto3" json:"collected,omitempty"`
+	Status        string                 `protobuf:"bytes,13,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Article) Reset() {
+	*x = Article{}
+	mi := &file_rpc_collector_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Article) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Article) ProtoMessage() {}
+
+func (x *Article) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_collector_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Article.ProtoReflect.Descriptor instead.
+func (*Article) Descriptor() ([]byte, []int) {
+	return file_rpc_collector_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Article) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Article) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Article) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Article) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Article) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Article) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Article) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Article) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *Article) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Article) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Article) GetPublished() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Published
+	}
+	return nil
+}
+
+func (x *Article) GetCollected() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Collected
+	}
+	return nil
+}
+
+func (x *Article) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+var File_rpc_collector_proto protoreflect.FileDescriptor
+
+const file_rpc_collector_proto_rawDesc = "" +
+	"\n" +
+	"\x13rpc/collector.proto\x12\fcollector.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa6\x01\n" +
+	"\x18TriggerCollectionRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\x12\x1c\n" +
+	"\tlanguages\x18\x05 \x03(\tR\tlanguages\x12\x1a\n" +
+	"\bcampaign\x18\x06 \x01(\tR\bcampaign\"2\n" +
+	"\x19TriggerCollectionResponse\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\x03R\x05runId\",\n" +
+	"\x13GetRunStatusRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\x03R\x05runId\"\x90\x02\n" +
+	"\tRunStatus\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\x03R\x05runId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x124\n" +
+	"\astarted\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\astarted\x126\n" +
+	"\bfinished\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bfinished\x12\x1a\n" +
+	"\bcampaign\x18\x05 \x01(\tR\bcampaign\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x124\n" +
+	"\asources\x18\a \x03(\v2\x1a.collector.v1.SourceStatusR\asources\"\xc2\x01\n" +
+	"\fSourceStatus\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x18\n" +
+	"\afetched\x18\x02 \x01(\x05R\afetched\x12\x16\n" +
+	"\x06stored\x18\x03 \x01(\x05R\x06stored\x12\x1e\n" +
+	"\n" +
+	"duplicates\x18\x04 \x01(\x05R\n" +
+	"duplicates\x12\x18\n" +
+	"\apartial\x18\x05 \x01(\bR\apartial\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x18\n" +
+	"\askipped\x18\a \x01(\bR\askipped\"\x99\x02\n" +
+	"\x15StreamArticlesRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\x03R\x05runId\x12\x18\n" +
+	"\asources\x18\x02 \x03(\tR\asources\x12\x1a\n" +
+	"\blanguage\x18\x03 \x01(\tR\blanguage\x12\x14\n" +
+	"\x05query\x18\x04 \x01(\tR\x05query\x12.\n" +
+	"\x04from\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\x12+\n" +
+	"\x11include_withdrawn\x18\b \x01(\bR\x10includeWithdrawn\"\xf9\x02\n" +
+	"\aArticle\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x16\n" +
+	"\x06author\x18\x05 \x01(\tR\x06author\x12\x16\n" +
+	"\x06domain\x18\x06 \x01(\tR\x06domain\x12\x1a\n" +
+	"\blanguage\x18\a \x01(\tR\blanguage\x12\x18\n" +
+	"\asection\x18\b \x01(\tR\asection\x12\x18\n" +
+	"\asummary\x18\t \x01(\tR\asummary\x12\x12\n" +
+	"\x04body\x18\n" +
+	" \x01(\tR\x04body\x128\n" +
+	"\tpublished\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tpublished\x128\n" +
+	"\tcollected\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcollected\x12\x16\n" +
+	"\x06status\x18\r \x01(\tR\x06status2\x8d\x02\n" +
+	"\tCollector\x12d\n" +
+	"\x11TriggerCollection\x12&.collector.v1.TriggerCollectionRequest\x1a'.collector.v1.TriggerCollectionResponse\x12J\n" +
+	"\fGetRunStatus\x12!.collector.v1.GetRunStatusRequest\x1a\x17.collector.v1.RunStatus\x12N\n" +
+	"\x0eStreamArticles\x12#.collector.v1.StreamArticlesRequest\x1a\x15.collector.v1.Article0\x01B\x12Z\x10go-collector/rpcb\x06proto3"
+
+var (
+	file_rpc_collector_proto_rawDescOnce sync.Once
+	file_rpc_collector_proto_rawDescData []byte
+)
+
+func file_rpc_collector_proto_rawDescGZIP() []byte {
+	file_rpc_collector_proto_rawDescOnce.Do(func() {
+		file_rpc_collector_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_collector_proto_rawDesc), len(file_rpc_collector_proto_rawDesc)))
+	})
+	return file_rpc_collector_proto_rawDescData
+}
+
+var file_rpc_collector_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_rpc_collector_proto_goTypes = []any{
+	(*TriggerCollectionRequest)(nil),  // 0: collector.v1.TriggerCollectionRequest
+	(*TriggerCollectionResponse)(nil), // 1: collector.v1.TriggerCollectionResponse
+	(*GetRunStatusRequest)(nil),       // 2: collector.v1.GetRunStatusRequest
+	(*RunStatus)(nil),                 // 3: collector.v1.RunStatus
+	(*SourceStatus)(nil),              // 4: collector.v1.SourceStatus
+	(*StreamArticlesRequest)(nil),     // 5: collector.v1.StreamArticlesRequest
+	(*Article)(nil),                   // 6: collector.v1.Article
+	(*timestamppb.Timestamp)(nil),     // 7: google.protobuf.Timestamp
+}
+var file_rpc_collector_proto_depIdxs = []int32{
+	7,  // 0: collector.v1.RunStatus.started:type_name -> google.protobuf.Timestamp
+	7,  // 1: collector.v1.RunStatus.finished:type_name -> google.protobuf.Timestamp
+	4,  // 2: collector.v1.RunStatus.sources:type_name -> collector.v1.SourceStatus
+	7,  // 3: collector.v1.StreamArticlesRequest.from:type_name -> google.protobuf.Timestamp
+	7,  // 4: collector.v1.StreamArticlesRequest.to:type_name -> google.protobuf.Timestamp
+	7,  // 5: collector.v1.Article.published:type_name -> google.protobuf.Timestamp
+	7,  // 6: collector.v1.Article.collected:type_name -> google.protobuf.Timestamp
+	0,  // 7: collector.v1.Collector.TriggerCollection:input_type -> collector.v1.TriggerCollectionRequest
+	2,  // 8: collector.v1.Collector.GetRunStatus:input_type -> collector.v1.GetRunStatusRequest
+	5,  // 9: collector.v1.Collector.StreamArticles:input_type -> collector.v1.StreamArticlesRequest
+	1,  // 10: collector.v1.Collector.TriggerCollection:output_type -> collector.v1.TriggerCollectionResponse
+	3,  // 11: collector.v1.Collector.GetRunStatus:output_type -> collector.v1.RunStatus
+	6,  // 12: collector.v1.Collector.StreamArticles:output_type -> collector.v1.Article
+	10, // [10:13] is the sub-list for method output_type
+	7,  // [7:10] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_rpc_collector_proto_init() }
+func file_rpc_collector_proto_init() {
+	if File_rpc_collector_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_collector_proto_rawDesc), len(file_rpc_collector_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rpc_collector_proto_goTypes,
+		DependencyIndexes: file_rpc_collector_proto_depIdxs,
+		MessageInfos:      file_rpc_collector_proto_msgTypes,
+	}.Build()
+	File_rpc_collector_proto = out.File
+	file_rpc_collector_proto_goTypes = nil
+	file_rpc_collector_proto_depIdxs = nil
+}
@@ -0,0 +1,223 @@
+// API gRPC del recolector (collector grpc): iniciar rondas de recolección,
+// seguir su estado y recibir los artículos a medida que se guardan, sin
+// invocar el binario. El código Go de este paquete se genera con
+// protoc-gen-go y protoc-gen-go-grpc:
+//
+//   protoc --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative rpc/collector.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: rpc/collector.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Collector_TriggerCollection_FullMethodName = "/collector.v1.Collector/TriggerCollection"
+	Collector_GetRunStatus_FullMethodName      = "/collector.v1.Collector/GetRunStatus"
+	Collector_StreamArticles_FullMethodName    = "/collector.v1.Collector/StreamArticles"
+)
+
+// CollectorClient is the client API for Collector service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CollectorClient interface {
+	// TriggerCollection inicia una ronda con la configuración del servidor y
+	// devuelve su id sin esperar a que termine. Hay una ronda a la vez: si ya
+	// hay una en curso, responde FAILED_PRECONDITION.
+	TriggerCollection(ctx context.Context, in *TriggerCollectionRequest, opts ...grpc.CallOption) (*TriggerCollectionResponse, error)
+	// GetRunStatus devuelve el estado de una ronda (también las iniciadas con
+	// collector collect) con el resultado de cada fuente.
+	GetRunStatus(ctx context.Context, in *GetRunStatusRequest, opts ...grpc.CallOption) (*RunStatus, error)
+	// StreamArticles envía los artículos que cumplen el filtro. Con run_id,
+	// los que trajo esa ronda, a medida que se guardan, hasta que termina.
+	StreamArticles(ctx context.Context, in *StreamArticlesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Article], error)
+}
+
+type collectorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCollectorClient(cc grpc.ClientConnInterface) CollectorClient {
+	return &collectorClient{cc}
+}
+
+func (c *collectorClient) TriggerCollection(ctx context.Context, in *TriggerCollectionRequest, opts ...grpc.CallOption) (*TriggerCollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TriggerCollectionResponse)
+	err := c.cc.Invoke(ctx, Collector_TriggerCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *collectorClient) GetRunStatus(ctx context.Context, in *GetRunStatusRequest, opts ...grpc.CallOption) (*RunStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RunStatus)
+	err := c.cc.Invoke(ctx, Collector_GetRunStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *collectorClient) StreamArticles(ctx context.Context, in *StreamArticlesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Article], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Collector_ServiceDesc.Streams[0], Collector_StreamArticles_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamArticlesRequest, Article]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Collector_StreamArticlesClient = grpc.ServerStreamingClient[Article]
+
+// CollectorServer is the server API for Collector service.
+// All implementations must embed UnimplementedCollectorServer
+// for forward compatibility.
+type CollectorServer interface {
+	// TriggerCollection inicia una ronda con la configuración del servidor y
+	// devuelve su id sin esperar a que termine. Hay una ronda a la vez: si ya
+	// hay una en curso, responde FAILED_PRECONDITION.
+	TriggerCollection(context.Context, *TriggerCollectionRequest) (*TriggerCollectionResponse, error)
+	// GetRunStatus devuelve el estado de una ronda (también las iniciadas con
+	// collector collect) con el resultado de cada fuente.
+	GetRunStatus(context.Context, *GetRunStatusRequest) (*RunStatus, error)
+	// StreamArticles envía los artículos que cumplen el filtro. Con run_id,
+	// los que trajo esa ronda, a medida que se guardan, hasta que termina.
+	StreamArticles(*StreamArticlesRequest, grpc.ServerStreamingServer[Article]) error
+	mustEmbedUnimplementedCollectorServer()
+}
+
+// UnimplementedCollectorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCollectorServer struct{}
+
+func (UnimplementedCollectorServer) TriggerCollection(context.Context, *TriggerCollectionRequest) (*TriggerCollectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerCollection not implemented")
+}
+func (UnimplementedCollectorServer) GetRunStatus(context.Context, *GetRunStatusRequest) (*RunStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRunStatus not implemented")
+}
+func (UnimplementedCollectorServer) StreamArticles(*StreamArticlesRequest, grpc.ServerStreamingServer[Article]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamArticles not implemented")
+}
+func (UnimplementedCollectorServer) mustEmbedUnimplementedCollectorServer() {}
+func (UnimplementedCollectorServer) testEmbeddedByValue()                   {}
+
+// UnsafeCollectorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CollectorServer will
+// result in compilation errors.
+type UnsafeCollectorServer interface {
+	mustEmbedUnimplementedCollectorServer()
+}
+
+func RegisterCollectorServer(s grpc.ServiceRegistrar, srv CollectorServer) {
+	// If the following call pancis, it indicates UnimplementedCollectorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Collector_ServiceDesc, srv)
+}
+
+func _Collector_TriggerCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollectorServer).TriggerCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Collector_TriggerCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollectorServer).TriggerCollection(ctx, req.(*TriggerCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Collector_GetRunStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRunStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollectorServer).GetRunStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Collector_GetRunStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollectorServer).GetRunStatus(ctx, req.(*GetRunStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Collector_StreamArticles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamArticlesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CollectorServer).StreamArticles(m, &grpc.GenericServerStream[StreamArticlesRequest, Article]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Collector_StreamArticlesServer = grpc.ServerStreamingServer[Article]
+
+// Collector_ServiceDesc is the grpc.ServiceDesc for Collector service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Collector_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "collector.v1.Collector",
+	HandlerType: (*CollectorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "TriggerCollection",
+			Handler:    _Collector_TriggerCollection_Handler,
+		},
+		{
+			MethodName: "GetRunStatus",
+			Handler:    _Collector_GetRunStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamArticles",
+			Handler:       _Collector_StreamArticles_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rpc/collector.proto",
+}
@@ -0,0 +1,258 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-collector/article"
+	"go-collector/storage"
+)
+
+// Errores que Start puede devolver (envueltos) para que el cliente reciba
+// el código gRPC que corresponde.
+var (
+	// ErrBusy indica que ya hay una ronda en curso.
+	ErrBusy = errors.New("ya hay una ronda de recolección en curso")
+	// ErrInvalid indica un pedido que no se puede cumplir: fuente
+	// desconocida, rango inválido.
+	ErrInvalid = errors.New("pedido inválido")
+)
+
+// streamPage es cuántos artículos se leen por consulta en StreamArticles.
+const streamPage = 500
+
+// Server implementa el servicio Collector sobre el corpus.
+type Server struct {
+	UnimplementedCollectorServer
+
+	Store *storage.Store
+	// Start registra y lanza una ronda de recolección y devuelve su id sin
+	// esperar a que termine; lo provee collector grpc, que tiene la
+	// configuración y los crawlers.
+	Start func(req *TriggerCollectionRequest) (int64, error)
+	// Token, si no está vacío, se exige en los metadatos como
+	// "authorization: Bearer <token>".
+	Token string
+	// Poll es cada cuánto StreamArticles busca los artículos nuevos de una
+	// ronda en curso; en cero, un segundo.
+	Poll time.Duration
+}
+
+// NewGRPCServer crea el servidor gRPC con el servicio registrado y, si hay
+// Token, la verificación del token en cada llamada.
+func (s *Server) NewGRPCServer() *grpc.Server {
+	g := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+			if err := s.authorize(ctx); err != nil {
+				return nil, err
+			}
+			return next(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, next grpc.StreamHandler) error {
+			if err := s.authorize(ss.Context()); err != nil {
+				return err
+			}
+			return next(srv, ss)
+		}),
+	)
+	RegisterCollectorServer(g, s)
+	return g
+}
+
+func (s *Server) authorize(ctx context.Context) error {
+	if s.Token == "" {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if v == "Bearer "+s.Token {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "no autorizado")
+}
+
+// TriggerCollection inicia una ronda con Start.
+func (s *Server) TriggerCollection(_ context.Context, req *TriggerCollectionRequest) (*TriggerCollectionResponse, error) {
+	id, err := s.Start(req)
+	switch {
+	case errors.Is(err, ErrBusy):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, ErrInvalid):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &TriggerCollectionResponse{RunId: id}, nil
+}
+
+// GetRunStatus devuelve la ronda del historial con el detalle por fuente.
+func (s *Server) GetRunStatus(_ context.Context, req *GetRunStatusRequest) (*RunStatus, error) {
+	run, err := s.run(req.RunId)
+	if err != nil {
+		return nil, err
+	}
+	out := &RunStatus{
+		RunId: run.ID, Status: run.Status, Started: timestamp(run.Started), Finished: timestamp(run.Finished),
+		Campaign: run.Campaign, Error: run.Err,
+	}
+	for _, src := range run.Sources {
+		out.Sources = append(out.Sources, &SourceStatus{
+			Source: src.Source, Fetched: int32(src.Fetched), Stored: int32(src.Stored), Duplicates: int32(src.Duplicates),
+			Partial: src.Partial, Error: src.Err, Skipped: src.Skipped,
+		})
+	}
+	return out, nil
+}
+
+func (s *Server) run(id int64) (*storage.Run, error) {
+	run, err := s.Store.GetRun(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "no existe la ronda %d", id)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return run, nil
+}
+
+// StreamArticles envía los artículos del filtro o, con run_id, los de la
+// ronda a medida que se guardan.
+func (s *Server) StreamArticles(req *StreamArticlesRequest, stream grpc.ServerStreamingServer[Article]) error {
+	f := storage.Filter{
+		Sources: req.Sources, Language: req.Language, Query: req.Query, IncludeWithdrawn: req.IncludeWithdrawn,
+	}
+	if req.From != nil {
+		f.From = req.From.AsTime()
+	}
+	if req.To != nil {
+		f.To = req.To.AsTime()
+	}
+	if req.Limit < 0 {
+		return status.Error(codes.InvalidArgument, "limit no puede ser negativo")
+	}
+	var err error
+	if req.RunId != 0 {
+		err = s.streamRun(stream, req.RunId, f, int(req.Limit))
+	} else {
+		err = s.streamFiltered(stream, f, int(req.Limit))
+	}
+	if _, ok := status.FromError(err); !ok && err != nil {
+		err = status.Error(codes.Internal, err.Error())
+	}
+	return err
+}
+
+// streamFiltered envía los artículos del corpus que cumplen f y que sus
+// términos de uso permiten redistribuir, de a páginas: la conexión con la
+// base no queda ocupada mientras el cliente lee, así una ronda en curso puede
+// seguir guardando.
+func (s *Server) streamFiltered(stream grpc.ServerStreamingServer[Article], f storage.Filter, limit int) error {
+	sent, read := 0, 0
+	for {
+		f.Limit, f.Offset = streamPage, read
+		page, err := s.Store.ListFiltered(f)
+		if err != nil {
+			return err
+		}
+		shared, _ := s.Store.ShareAll(page)
+		for _, a := range shared {
+			if err := stream.Send(toArticle(a)); err != nil {
+				return err
+			}
+			if sent++; sent == limit {
+				return nil
+			}
+		}
+		read += len(page)
+		if len(page) < f.Limit {
+			return nil
+		}
+	}
+}
+
+// streamRun envía los artículos de la ronda id que cumplen las fuentes, el
+// idioma y el estado de f, con lo que sus términos de uso permiten
+// redistribuir, en el orden en que se guardan, hasta que la ronda termina. Un artículo que la ronda trajo de varias fuentes va una
+// vez.
+func (s *Server) streamRun(stream grpc.ServerStreamingServer[Article], id int64, f storage.Filter, limit int) error {
+	if _, err := s.run(id); err != nil {
+		return err
+	}
+	poll := s.Poll
+	if poll <= 0 {
+		poll = time.Second
+	}
+	sources := map[string]bool{}
+	for _, src := range f.Sources {
+		sources[src] = true
+	}
+	seen := map[int64]bool{}
+	var mark int64
+	for {
+		// El estado se lee antes que los artículos: si ya terminó, esta
+		// pasada trae los últimos.
+		run, err := s.run(id)
+		if err != nil {
+			return err
+		}
+		var ids []int64
+		if ids, mark, err = s.Store.RunArticlesSince(id, mark); err != nil {
+			return err
+		}
+		for _, aid := range ids {
+			if seen[aid] {
+				continue
+			}
+			seen[aid] = true
+			a, err := s.Store.GetByID(aid)
+			if err != nil {
+				return err
+			}
+			if (len(sources) > 0 && !sources[a.Source]) || (f.Language != "" && a.Language != f.Language) ||
+				(!f.IncludeWithdrawn && a.Status != article.StatusActive) {
+				continue
+			}
+			if a, _ = s.Store.Shareable(a); a == nil {
+				continue
+			}
+			if err := stream.Send(toArticle(a)); err != nil {
+				return err
+			}
+			if limit--; limit == 0 {
+				return nil
+			}
+		}
+		if run.Status != storage.RunRunning {
+			return nil
+		}
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-time.After(poll):
+		}
+	}
+}
+
+func toArticle(a *article.Article) *Article {
+	return &Article{
+		Id: a.ID, Source: a.Source, Url: a.URL, Title: a.Title, Author: a.Author, Domain: a.Domain,
+		Language: a.Language, Section: a.Section, Summary: a.Summary, Body: a.Body,
+		Published: timestamp(a.Published), Collected: timestamp(a.Collected), Status: a.Status,
+	}
+}
+
+// timestamp convierte t; una fecha en cero no se envía.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,74 @@
+// Package seed trae paquetes de medios incluidos en el binario para que una
+// instalación nueva empiece con buena cobertura (collector init --seed): por
+// medio, su dominio, sus feeds y de dónde es.
+package seed
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed *.yaml
+var packs embed.FS
+
+// Pack es un paquete de medios.
+type Pack struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Country     string   `yaml:"country"` // ISO 3166-1 alfa-2, ej: co
+	Outlets     []Outlet `yaml:"outlets"`
+}
+
+// Outlet es un medio del paquete.
+type Outlet struct {
+	// Key es el nombre del medio en feeds.editions y en los outlets de las
+	// campañas.
+	Key    string `yaml:"key"`
+	Name   string `yaml:"name"`
+	Domain string `yaml:"domain"`
+	// Scope es nacional o regional; Department y City dicen de dónde es.
+	Scope      string   `yaml:"scope"`
+	Department string   `yaml:"department"`
+	City       string   `yaml:"city"`
+	Language   string   `yaml:"language"` // ISO 639-1
+	Feeds      []string `yaml:"feeds"`
+	Sitemaps   []string `yaml:"sitemaps"`
+}
+
+// Place es de dónde es el medio para mostrarlo: "nacional" o la ciudad y el
+// departamento.
+func (o Outlet) Place() string {
+	if o.Scope != "regional" {
+		return o.Scope
+	}
+	return strings.TrimPrefix(o.City+", "+o.Department, ", ")
+}
+
+// Names devuelve los paquetes incluidos.
+func Names() []string {
+	files, _ := fs.Glob(packs, "*.yaml")
+	var out []string
+	for _, f := range files {
+		out = append(out, strings.TrimSuffix(f, ".yaml"))
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Load lee el paquete name.
+func Load(name string) (*Pack, error) {
+	data, err := packs.ReadFile(name + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("paquete desconocido: %s (use %s)", name, strings.Join(Names(), ", "))
+	}
+	var p Pack
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("error leyendo el paquete %s: %w", name, err)
+	}
+	return &p, nil
+}
@@ -0,0 +1,63 @@
+package sentiment
+
+// lexicons: idioma -> palabra -> polaridad (-3 a 3). Son palabras de
+// cobertura de prensa (universidades, gobierno, orden público), no de
+// reseñas: "investigación" no está porque en el corpus casi siempre es
+// académica.
+var lexicons = map[string]map[string]float64{
+	"es": {
+		// Positivas.
+		"logro": 2, "logra": 2, "logró": 2, "avance": 2, "avanza": 1.5, "éxito": 3, "exitoso": 2.5, "exitosa": 2.5,
+		"gana": 2, "ganó": 2, "premio": 2.5, "premiado": 2.5, "premiada": 2.5, "reconocimiento": 2, "reconocida": 1.5,
+		"reconocido": 1.5, "acuerdo": 1.5, "crecimiento": 1.5, "crece": 1.5, "mejora": 2, "mejoró": 2, "beneficio": 1.5,
+		"beneficia": 1.5, "apoyo": 1.5, "apoya": 1.5, "celebra": 2, "celebración": 2, "lidera": 1.5, "liderazgo": 1.5,
+		"innovación": 2, "innovador": 2, "récord": 1.5, "aprobado": 1, "aprueba": 1, "oportunidad": 1.5,
+		"oportunidades": 1.5, "destaca": 1.5, "destacado": 1.5, "orgullo": 2.5, "calidad": 1, "excelencia": 2.5,
+		"acreditación": 2, "beca": 1.5, "becas": 1.5, "inaugura": 1.5, "solución": 1.5, "soluciones": 1.5,
+		"fortalece": 1.5, "gratuidad": 1, "paz": 2, "esperanza": 2, "progreso": 2, "positivo": 2, "positiva": 2,
+		"histórico": 1, "alianza": 1.5, "recupera": 1.5, "recuperación": 1.5, "aumento": 0.5, "bienestar": 2,
+		// Negativas.
+		"crisis": -2.5, "paro": -2, "protesta": -1.5, "protestas": -1.5, "muerte": -3, "muertos": -3, "muere": -3,
+		"violencia": -3, "violento": -2.5, "recorte": -2, "recortes": -2, "denuncia": -1.5, "denuncian": -1.5,
+		"corrupción": -3, "escándalo": -2.5, "amenaza": -2.5, "amenazas": -2.5, "ataque": -3, "conflicto": -2,
+		"déficit": -2, "cierre": -1.5, "fracaso": -2.5, "caída": -1.5, "cae": -1, "pérdida": -2, "pérdidas": -2,
+		"accidente": -2, "asesinato": -3, "asesinado": -3, "huelga": -1.5, "bloqueo": -1.5, "bloqueos": -1.5,
+		"renuncia": -1, "deuda": -1.5, "polémica": -1.5, "rechazo": -1.5, "rechaza": -1.5, "acusación": -2,
+		"acusado": -2, "grave": -2, "riesgo": -1.5, "disturbios": -2.5, "enfrentamientos": -2.5, "heridos": -2.5,
+		"problema": -1.5, "problemas": -1.5, "falla": -1.5, "fallas": -1.5, "irregularidades": -2, "sanción": -1.5,
+		"investigado": -1.5, "suspendido": -1, "suspende": -1, "cancelado": -1, "cancela": -1, "desfinanciación": -2.5,
+		"desfinanciamiento": -2.5, "dengue": -1, "epidemia": -2.5, "emergencia": -2, "tragedia": -3, "preocupación": -1.5,
+		"negativo": -2, "negativa": -2, "robo": -2.5, "fraude": -3, "desplazamiento": -2, "inseguridad": -2,
+	},
+	"en": {
+		// Positivas.
+		"achievement": 2, "achieves": 2, "success": 3, "successful": 2.5, "wins": 2, "won": 2, "award": 2.5,
+		"awarded": 2.5, "recognition": 2, "recognized": 1.5, "agreement": 1.5, "deal": 1, "growth": 1.5, "grows": 1.5,
+		"improve": 2, "improves": 2, "improvement": 2, "benefit": 1.5, "benefits": 1.5, "support": 1.5, "supports": 1.5,
+		"celebrates": 2, "celebration": 2, "leads": 1.5, "leading": 1, "innovation": 2, "innovative": 2, "record": 1,
+		"approved": 1, "opportunity": 1.5, "opportunities": 1.5, "excellence": 2.5, "scholarship": 1.5,
+		"scholarships": 1.5, "boost": 1.5, "breakthrough": 2.5, "peace": 2, "hope": 2, "progress": 2, "positive": 2,
+		"historic": 1, "partnership": 1.5, "recovery": 1.5, "praised": 2, "proud": 2.5, "thrive": 2, "welcome": 1.5,
+		// Negativas.
+		"crisis": -2.5, "strike": -1.5, "protest": -1.5, "protests": -1.5, "death": -3, "dead": -3, "dies": -3,
+		"killed": -3, "violence": -3, "violent": -2.5, "cuts": -2, "cut": -1, "corruption": -3, "scandal": -2.5,
+		"threat": -2.5, "threats": -2.5, "attack": -3, "conflict": -2, "deficit": -2, "closure": -1.5, "failure": -2.5,
+		"fails": -2, "decline": -1.5, "loss": -2, "losses": -2, "accident": -2, "murder": -3, "blockade": -1.5,
+		"resigns": -1, "debt": -1.5, "controversy": -1.5, "rejects": -1.5, "accused": -2, "serious": -1.5,
+		"risk": -1.5, "riots": -2.5, "clashes": -2.5, "injured": -2.5, "problem": -1.5, "problems": -1.5,
+		"fraud": -3, "emergency": -2, "tragedy": -3, "concern": -1.5, "concerns": -1.5, "negative": -2,
+		"outbreak": -2, "epidemic": -2.5, "displacement": -2, "insecurity": -2, "underfunded": -2, "shortage": -2,
+	},
+}
+
+// negations invierten la polaridad de las palabras que siguen.
+var negations = map[string]bool{
+	"no": true, "ni": true, "nunca": true, "jamás": true, "sin": true, "tampoco": true,
+	"not": true, "never": true, "without": true, "no-one": true, "nor": true,
+}
+
+// intensifiers refuerzan la palabra siguiente.
+var intensifiers = map[string]float64{
+	"muy": 1.3, "más": 1.2, "gran": 1.3, "grave": 1.2, "total": 1.2, "enorme": 1.4, "sumamente": 1.4,
+	"very": 1.3, "more": 1.2, "huge": 1.4, "major": 1.3, "extremely": 1.4, "deeply": 1.3,
+}
@@ -0,0 +1,83 @@
+package sentiment
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"go-collector/storage"
+)
+
+// Períodos de agregación del informe.
+const (
+	ByDay   = "day"
+	ByWeek  = "week"
+	ByMonth = "month"
+)
+
+// Period es el tono agregado de los artículos publicados en un período.
+type Period struct {
+	Period   string  `json:"period"` // 2024-05-14, 2024-W20 o 2024-05
+	Articles int     `json:"articles"`
+	Mean     float64 `json:"mean"`
+	Positive int     `json:"positive"`
+	Negative int     `json:"negative"`
+	Neutral  int     `json:"neutral"`
+}
+
+// Label es la etiqueta del tono promedio del período.
+func (p Period) Label() string {
+	return Label(p.Mean)
+}
+
+// Series agrupa el tono de los artículos por período, en orden. points debe
+// venir ordenado por publicación (ver storage.SentimentPoints).
+func Series(points []storage.SentimentPoint, by string) ([]Period, error) {
+	key, err := periodKey(by)
+	if err != nil {
+		return nil, err
+	}
+	var out []Period
+	sum := 0.0
+	flush := func() {
+		if n := len(out); n > 0 {
+			out[n-1].Mean = math.Round(sum/float64(out[n-1].Articles)*1000) / 1000
+		}
+	}
+	for _, p := range points {
+		k := key(p.Published)
+		if len(out) == 0 || out[len(out)-1].Period != k {
+			flush()
+			out = append(out, Period{Period: k})
+			sum = 0
+		}
+		cur := &out[len(out)-1]
+		cur.Articles++
+		sum += p.Score
+		switch Label(p.Score) {
+		case Positive:
+			cur.Positive++
+		case Negative:
+			cur.Negative++
+		default:
+			cur.Neutral++
+		}
+	}
+	flush()
+	return out, nil
+}
+
+func periodKey(by string) (func(time.Time) string, error) {
+	switch by {
+	case ByDay:
+		return func(t time.Time) string { return t.UTC().Format("2006-01-02") }, nil
+	case ByWeek:
+		return func(t time.Time) string {
+			y, w := t.UTC().ISOWeek()
+			return fmt.Sprintf("%d-W%02d", y, w)
+		}, nil
+	case ByMonth:
+		return func(t time.Time) string { return t.UTC().Format("2006-01") }, nil
+	}
+	return nil, fmt.Errorf("período desconocido: %s (use day, week o month)", by)
+}
@@ -0,0 +1,163 @@
+// Package sentiment puntúa el tono de los artículos (positivo o negativo)
+// con léxicos en español e inglés, para seguir cómo cambia la cobertura de
+// un tema en el tiempo.
+//
+// Es un puntaje por léxico, al estilo de VADER: suma la polaridad de las
+// palabras conocidas, invierte las que siguen a una negación y refuerza las
+// que siguen a un intensificador. No entiende ironía ni a quién se refiere el
+// tono; sirve para tendencias sobre muchos artículos, no para juzgar uno.
+package sentiment
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"go-collector/article"
+)
+
+// Etiquetas de un puntaje.
+const (
+	Positive = "positive"
+	Negative = "negative"
+	Neutral  = "neutral"
+)
+
+const (
+	// neutralBand es el margen alrededor de cero que se considera neutro.
+	neutralBand = 0.05
+	// alpha suaviza la normalización: con pocas palabras el puntaje no se
+	// va a los extremos.
+	alpha = 15
+	// negationScope es cuántas palabras después de una negación se invierten.
+	negationScope = 3
+	// negationFactor invierte y atenúa ("no es un éxito" no es un fracaso).
+	negationFactor = -0.74
+	// titleWeight es el peso del título frente al resumen y el cuerpo.
+	titleWeight = 0.4
+)
+
+// Score es el tono de un artículo: Value va de -1 (negativo) a 1 (positivo).
+// Positive y Negative cuentan las palabras del léxico encontradas.
+type Score struct {
+	Value    float64 `json:"value"`
+	Positive int     `json:"positive"`
+	Negative int     `json:"negative"`
+	Lexicon  string  `json:"lexicon"`
+}
+
+// Label es la etiqueta del puntaje.
+func (s Score) Label() string {
+	return Label(s.Value)
+}
+
+// Label etiqueta un puntaje (o un promedio de puntajes).
+func Label(v float64) string {
+	switch {
+	case v > neutralBand:
+		return Positive
+	case v < -neutralBand:
+		return Negative
+	}
+	return Neutral
+}
+
+// Supported indica si hay léxico para el idioma.
+func Supported(language string) bool {
+	_, ok := lexicons[language]
+	return ok
+}
+
+// Article puntúa el artículo con el léxico de su idioma; ok es false si no
+// hay léxico para él.
+func Article(a *article.Article) (s Score, ok bool) {
+	lex, ok := lexicons[a.Language]
+	if !ok {
+		return Score{}, false
+	}
+	title := text(lex, a.Title)
+	rest := text(lex, a.Summary+"\n"+a.Body)
+	s = Score{Lexicon: a.Language, Positive: title.positive + rest.positive, Negative: title.negative + rest.negative}
+	switch {
+	case title.words == 0:
+		s.Value = rest.value
+	case rest.words == 0:
+		s.Value = title.value
+	default:
+		s.Value = titleWeight*title.value + (1-titleWeight)*rest.value
+	}
+	s.Value = math.Round(s.Value*1000) / 1000
+	return s, true
+}
+
+// Text puntúa un texto suelto en el idioma indicado.
+func Text(language, s string) (Score, bool) {
+	lex, ok := lexicons[language]
+	if !ok {
+		return Score{}, false
+	}
+	t := text(lex, s)
+	return Score{Value: math.Round(t.value*1000) / 1000, Positive: t.positive, Negative: t.negative, Lexicon: language}, true
+}
+
+type tally struct {
+	value              float64
+	words              int // palabras del léxico
+	positive, negative int
+}
+
+func text(lex map[string]float64, s string) tally {
+	var t tally
+	sum := 0.0
+	negated, boost := 0, 1.0
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-'
+	}) {
+		if negations[w] {
+			negated = negationScope
+			continue
+		}
+		if f, ok := intensifiers[w]; ok {
+			if _, inLex := lex[w]; !inLex {
+				boost = f
+				continue
+			}
+		}
+		if p, ok := polarity(lex, w); ok {
+			p *= boost
+			if negated > 0 {
+				p *= negationFactor
+			}
+			sum += p
+			t.words++
+			if p > 0 {
+				t.positive++
+			} else if p < 0 {
+				t.negative++
+			}
+		}
+		boost = 1
+		if negated > 0 {
+			negated--
+		}
+	}
+	if t.words > 0 {
+		t.value = sum / math.Sqrt(sum*sum+alpha)
+	}
+	return t
+}
+
+// polarity busca la palabra en el léxico, también sin la terminación plural.
+func polarity(lex map[string]float64, w string) (float64, bool) {
+	if p, ok := lex[w]; ok {
+		return p, true
+	}
+	for _, suffix := range []string{"es", "s"} {
+		if base, found := strings.CutSuffix(w, suffix); found && len(base) > 3 {
+			if p, ok := lex[base]; ok {
+				return p, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,249 @@
+// Package shortlink expande los enlaces acortados (t.co, bit.ly...) siguiendo
+// sus redirecciones hasta el destino real, para que la deduplicación, las
+// estadísticas por dominio y el filtro de relevancia vean el medio enlazado y
+// no el acortador.
+package shortlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"go-collector/cachestat"
+	"go-collector/storage"
+)
+
+// DefaultMaxHops es cuántas redirecciones se siguen si no se indica otra cosa.
+const DefaultMaxHops = 5
+
+// retryAfter es cuánto se recuerda un enlace que no se pudo expandir antes
+// de volver a intentarlo (el acortador pudo estar caído).
+const retryAfter = 24 * time.Hour
+
+// Hosts son los acortadores conocidos.
+var Hosts = []string{
+	"t.co", "bit.ly", "bitly.com", "j.mp", "ow.ly", "buff.ly", "dlvr.it", "trib.al", "tinyurl.com",
+	"goo.gl", "is.gd", "lnkd.in", "fb.me", "cutt.ly", "rebrand.ly", "shorturl.at", "tiny.cc", "t.ly",
+}
+
+// ErrTooManyHops indica que el enlace redirige más veces de lo permitido.
+var ErrTooManyHops = errors.New("demasiadas redirecciones")
+
+// Cache guarda las expansiones entre rondas (storage.Store).
+type Cache interface {
+	GetShortLink(url string) (*storage.ShortLink, error)
+	SaveShortLink(l *storage.ShortLink) error
+}
+
+// Expander expande enlaces acortados. Es seguro usarlo desde varias
+// goroutines; cada enlace se pide una sola vez por Expander, aunque lo
+// pidan varias a la vez.
+type Expander struct {
+	// Client hace las peticiones; no debe seguir redirecciones por su cuenta
+	// (NewExpander lo configura así).
+	Client  *http.Client
+	MaxHops int
+	// Cache, si no es nil, evita volver a pedir los enlaces ya expandidos.
+	Cache Cache
+
+	hosts  map[string]bool
+	mu     sync.Mutex
+	seen   map[string]string
+	flight singleflight.Group
+	stats  cachestat.Counter
+}
+
+// NewExpander crea un expansor con los acortadores conocidos más extra.
+func NewExpander(cache Cache, extra ...string) *Expander {
+	e := &Expander{
+		Client: &http.Client{
+			Timeout: 15 * time.Second,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		MaxHops: DefaultMaxHops,
+		Cache:   cache,
+		hosts:   map[string]bool{},
+		seen:    map[string]string{},
+	}
+	for _, h := range append(Hosts, extra...) {
+		e.hosts[strings.ToLower(strings.TrimPrefix(h, "www."))] = true
+	}
+	return e
+}
+
+// IsShort indica si el enlace es de un acortador.
+func (e *Expander) IsShort(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return e.hosts[strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")]
+}
+
+// Expand devuelve el destino del enlace: sigue sus redirecciones hasta salir
+// de los acortadores (sin pedir la página de destino). Un enlace que no es de
+// un acortador se devuelve tal cual.
+func (e *Expander) Expand(ctx context.Context, raw string) (string, error) {
+	if !e.IsShort(raw) {
+		return raw, nil
+	}
+	e.mu.Lock()
+	target, ok := e.seen[raw]
+	e.mu.Unlock()
+	if ok {
+		e.stats.Hit()
+		return target, nil
+	}
+	leader := false
+	v, err, _ := e.flight.Do(raw, func() (any, error) {
+		leader = true
+		return e.expand(ctx, raw)
+	})
+	if !leader {
+		e.stats.Shared()
+	}
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// Stats devuelve los aciertos y fallos acumulados de la memoria de enlaces:
+// los aciertos son los ya expandidos en esta corrida o guardados en Cache, y
+// los fallos los que hubo que seguir.
+func (e *Expander) Stats() cachestat.Stats {
+	e.mu.Lock()
+	n := len(e.seen)
+	e.mu.Unlock()
+	return e.stats.Snapshot(n)
+}
+
+// expand busca el enlace en Cache o, si no está (o falló hace más de
+// retryAfter), sigue sus redirecciones.
+func (e *Expander) expand(ctx context.Context, raw string) (string, error) {
+	if e.Cache != nil {
+		l, err := e.Cache.GetShortLink(raw)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return "", err
+		}
+		if l != nil && (l.Err == "" || time.Since(l.Resolved) < retryAfter) {
+			e.stats.Hit()
+			if l.Err != "" {
+				return "", errors.New(l.Err)
+			}
+			e.remember(raw, l.Target)
+			return l.Target, nil
+		}
+	}
+
+	e.stats.Miss()
+	target, err := e.follow(ctx, raw)
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if e.Cache != nil {
+		l := &storage.ShortLink{URL: raw, Target: target, Resolved: time.Now()}
+		if err != nil {
+			l.Err = err.Error()
+		}
+		if err := e.Cache.SaveShortLink(l); err != nil {
+			return "", err
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	e.remember(raw, target)
+	return target, nil
+}
+
+func (e *Expander) remember(raw, target string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.seen[raw] = target
+}
+
+// follow pide el enlace y cada redirección mientras siga en un acortador.
+func (e *Expander) follow(ctx context.Context, raw string) (string, error) {
+	hops := e.MaxHops
+	if hops <= 0 {
+		hops = DefaultMaxHops
+	}
+	current := raw
+	for i := 0; i < hops; i++ {
+		next, err := e.location(ctx, current)
+		if err != nil {
+			return "", err
+		}
+		if next == "" {
+			return "", fmt.Errorf("el acortador no redirige %s", current)
+		}
+		if !e.IsShort(next) {
+			return next, nil
+		}
+		current = next
+	}
+	return "", fmt.Errorf("%w (más de %d) desde %s", ErrTooManyHops, hops, raw)
+}
+
+// location devuelve a dónde redirige u ("" si no redirige). Se intenta con
+// HEAD y, si el acortador no lo acepta, con GET.
+func (e *Expander) location(ctx context.Context, u string) (string, error) {
+	var resp *http.Response
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, u, nil)
+		if err != nil {
+			return "", fmt.Errorf("error creando petición a %s: %w", u, err)
+		}
+		if resp, err = e.Client.Do(req); err != nil {
+			return "", fmt.Errorf("error consultando %s: %w", u, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed && resp.StatusCode != http.StatusNotImplemented {
+			break
+		}
+	}
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		if resp.StatusCode >= 400 {
+			return "", fmt.Errorf("%s respondió %s", u, resp.Status)
+		}
+		return "", nil
+	}
+	loc, err := resp.Location()
+	if err != nil {
+		return "", fmt.Errorf("redirección sin destino en %s: %w", u, err)
+	}
+	return loc.String(), nil
+}
+
+// linkRe encuentra los enlaces en un texto.
+var linkRe = regexp.MustCompile(`https?://[^\s<>"')\]]+`)
+
+// ExpandText reemplaza los enlaces acortados del texto por su destino. Los
+// que no se pueden expandir quedan como estaban; errs cuenta esos casos.
+func (e *Expander) ExpandText(ctx context.Context, text string) (out string, errs int) {
+	out = linkRe.ReplaceAllStringFunc(text, func(match string) string {
+		// La puntuación final es de la oración, no del enlace.
+		link := strings.TrimRight(match, ".,;:!?")
+		if !e.IsShort(link) || ctx.Err() != nil {
+			return match
+		}
+		target, err := e.Expand(ctx, link)
+		if err != nil {
+			errs++
+			return match
+		}
+		return target + match[len(link):]
+	})
+	return out, errs
+}
@@ -0,0 +1,154 @@
+package shortlink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-collector/storage"
+)
+
+// shortener responde como un acortador: cada https://bit.ly/<id> redirige a
+// la nota <id> de un medio. Cuenta las peticiones por enlace y, si wait no es
+// nil, retiene cada respuesta hasta cerrarse.
+type shortener struct {
+	mu    sync.Mutex
+	calls map[string]int
+	wait  chan struct{}
+}
+
+func (s *shortener) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	s.calls[req.URL.String()]++
+	s.mu.Unlock()
+	if s.wait != nil {
+		<-s.wait
+	}
+	return &http.Response{
+		StatusCode: http.StatusMovedPermanently,
+		Header:     http.Header{"Location": {target(strings.TrimPrefix(req.URL.Path, "/"))}},
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
+
+func target(id string) string { return "https://www.eltiempo.com/nota-" + id }
+
+// memoryCache es un Cache en memoria que cuenta lo que se guarda.
+type memoryCache struct {
+	mu    sync.Mutex
+	links map[string]storage.ShortLink
+	saves atomic.Int64
+}
+
+func (c *memoryCache) GetShortLink(url string) (*storage.ShortLink, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	l, ok := c.links[url]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &l, nil
+}
+
+func (c *memoryCache) SaveShortLink(l *storage.ShortLink) error {
+	c.saves.Add(1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.links[l.URL] = *l
+	return nil
+}
+
+func newExpander(s *shortener, cache Cache) *Expander {
+	e := NewExpander(cache)
+	e.Client.Transport = s
+	return e
+}
+
+// Muchas goroutines que piden el mismo enlace a la vez lo siguen una sola
+// vez: las demás esperan el resultado de la primera.
+func TestExpandConcurrentSameLink(t *testing.T) {
+	s := &shortener{calls: map[string]int{}, wait: make(chan struct{})}
+	cache := &memoryCache{links: map[string]storage.ShortLink{}}
+	e := newExpander(s, cache)
+
+	const n = 50
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := e.Expand(context.Background(), "https://bit.ly/abc")
+			if err != nil || got != target("abc") {
+				t.Errorf("Expand = %q, %v", got, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond) // que las demás lleguen mientras la primera espera
+	close(s.wait)
+	wg.Wait()
+
+	if calls := s.calls["https://bit.ly/abc"]; calls != 1 {
+		t.Errorf("%d peticiones al acortador, se esperaba 1", calls)
+	}
+	if saves := cache.saves.Load(); saves != 1 {
+		t.Errorf("%d enlaces guardados, se esperaba 1", saves)
+	}
+	st := e.Stats()
+	if st.Misses != 1 || st.Hits+st.Shared != n-1 || st.Entries != 1 {
+		t.Errorf("estadísticas %+v, se esperaba 1 fallo, %d aciertos o compartidas y 1 entrada", st, n-1)
+	}
+}
+
+// Muchos enlaces desde muchas goroutines, también dentro de textos: cada
+// uno se sigue una vez y cada texto recibe sus propios destinos.
+func TestExpandConcurrentLinks(t *testing.T) {
+	s := &shortener{calls: map[string]int{}}
+	cache := &memoryCache{links: map[string]storage.ShortLink{}}
+	e := newExpander(s, cache)
+
+	const links, workers = 100, 8
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range links {
+				id := fmt.Sprint((i + w*13) % links)
+				text := "Lea la nota: https://bit.ly/" + id + "."
+				out, errs := e.ExpandText(context.Background(), text)
+				if want := "Lea la nota: " + target(id) + "."; errs != 0 || out != want {
+					t.Errorf("ExpandText = %q (%d errores), se esperaba %q", out, errs, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for link, calls := range s.calls {
+		if calls != 1 {
+			t.Errorf("%s: %d peticiones al acortador, se esperaba 1", link, calls)
+		}
+	}
+	if len(s.calls) != links || cache.saves.Load() != links {
+		t.Errorf("%d enlaces pedidos y %d guardados, se esperaban %d", len(s.calls), cache.saves.Load(), links)
+	}
+	if st := e.Stats(); st.Entries != links || st.Lookups() != workers*links {
+		t.Errorf("estadísticas %+v, se esperaban %d entradas y %d consultas", st, links, workers*links)
+	}
+
+	// Otro Expander con la misma Cache no vuelve a pedir ninguno.
+	again := newExpander(&shortener{calls: map[string]int{}}, cache)
+	if got, err := again.Expand(context.Background(), "https://bit.ly/7"); err != nil || got != target("7") {
+		t.Errorf("Expand desde la caché = %q, %v", got, err)
+	}
+	if st := again.Stats(); st.Hits != 1 || st.Misses != 0 {
+		t.Errorf("estadísticas %+v, se esperaba 1 acierto desde la caché", st)
+	}
+}
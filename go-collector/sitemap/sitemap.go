@@ -0,0 +1,267 @@
+package sitemap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"go-collector/article"
+	"go-collector/crawler"
+	"go-collector/editions"
+	"go-collector/extract"
+	"go-collector/robots"
+)
+
+// URLSet mapea un sitemap de URLs, incluyendo la extensión de Google News.
+type URLSet struct {
+	URLs []Entry `xml:"url"`
+}
+
+// Entry es una URL del sitemap con sus fechas de modificación/publicación.
+type Entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+	News    struct {
+		Publication struct {
+			Name     string `xml:"name"`
+			Language string `xml:"language"`
+		} `xml:"publication"`
+		PublicationDate string `xml:"publication_date"`
+		Title           string `xml:"title"`
+		Keywords        string `xml:"keywords"`
+	} `xml:"news"`
+}
+
+// Index mapea un sitemap índice que apunta a otros sitemaps.
+type Index struct {
+	Sitemaps []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"sitemap"`
+}
+
+// Client descarga y parsea sitemaps.
+type Client struct {
+	HTTP *http.Client
+	// Robots, si no es nil, da el robots.txt de cada dominio en Discover
+	// (desde su caché) en vez de descargarlo.
+	Robots *robots.Cache
+}
+
+func NewClient() *Client {
+	return &Client{
+		HTTP: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// maxIndexDepth es cuántos niveles de índices se siguen: los medios usan
+// uno, a veces dos (un índice por año que apunta a uno por mes).
+const maxIndexDepth = 3
+
+// Fetch descarga un sitemap y devuelve todas sus entradas. Si es un índice,
+// descarga cada sitemap hijo, hasta maxIndexDepth niveles de índices y sin
+// repetir uno ya leído (hay índices que se incluyen a sí mismos).
+func (c *Client) Fetch(ctx context.Context, sitemapURL string) ([]Entry, error) {
+	return c.FetchSince(ctx, sitemapURL, time.Time{})
+}
+
+// FetchSince es Fetch sin los sitemaps hijos cuyo lastmod es anterior a
+// since: los medios parten el archivo por mes o por día, y así no se
+// descarga entero. Since en cero no descarta ninguno.
+func (c *Client) FetchSince(ctx context.Context, sitemapURL string, since time.Time) ([]Entry, error) {
+	return c.fetch(ctx, sitemapURL, since, 0, make(map[string]bool))
+}
+
+// fetch es FetchSince en el nivel depth de índices; seen son los sitemaps
+// ya leídos.
+func (c *Client) fetch(ctx context.Context, sitemapURL string, since time.Time, depth int, seen map[string]bool) ([]Entry, error) {
+	seen[sitemapURL] = true
+	body, err := c.get(ctx, sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.Contains(string(body[:min(len(body), 1024)]), "<sitemapindex") {
+		if depth >= maxIndexDepth {
+			return nil, fmt.Errorf("índices de sitemap anidados más de %d niveles en %s", maxIndexDepth, sitemapURL)
+		}
+		var idx Index
+		if err := xml.Unmarshal(body, &idx); err != nil {
+			return nil, fmt.Errorf("error parseando índice de sitemap: %w", err)
+		}
+		var all []Entry
+		for _, sm := range idx.Sitemaps {
+			loc := strings.TrimSpace(sm.Loc)
+			if seen[loc] {
+				continue
+			}
+			if mod := parseW3CDate(sm.LastMod); !since.IsZero() && !mod.IsZero() && mod.Before(since) {
+				continue
+			}
+			entries, err := c.fetch(ctx, loc, since, depth+1, seen)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, entries...)
+		}
+		return all, nil
+	}
+
+	var set URLSet
+	if err := xml.Unmarshal(body, &set); err != nil {
+		return nil, fmt.Errorf("error parseando sitemap: %w", err)
+	}
+	return set.URLs, nil
+}
+
+// Discover busca los sitemaps del medio en las líneas Sitemap: de su
+// robots.txt; si no declara ninguno, prueba /sitemap.xml. Cuando hay
+// sitemaps de noticias (news en la URL), que traen título y fecha de
+// publicación, se usan solo esos.
+func (c *Client) Discover(ctx context.Context, domain string) ([]string, error) {
+	base := "https://" + domain
+	var declared []string
+	if c.Robots != nil {
+		r, err := c.Robots.Rules(ctx, base)
+		if err != nil {
+			return nil, err
+		}
+		declared = r.Sitemaps
+	} else {
+		body, err := c.get(ctx, base+"/robots.txt")
+		if err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		declared = robots.Parse(body, "").Sitemaps
+	}
+	var all, news []string
+	for _, u := range declared {
+		all = append(all, u)
+		if strings.Contains(strings.ToLower(u), "news") {
+			news = append(news, u)
+		}
+	}
+	switch {
+	case len(news) > 0:
+		return news, nil
+	case len(all) > 0:
+		return all, nil
+	}
+	return []string{base + "/sitemap.xml"}, nil
+}
+
+func (c *Client) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "EthicalCrawler/1.0 (StudentResearch)")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error HTTP: status code %d en %s", resp.StatusCode, u)
+	}
+	return body, nil
+}
+
+// Modified devuelve la fecha de última modificación de la entrada: lastmod si
+// existe, si no la fecha de publicación de la extensión news.
+func (e Entry) Modified() time.Time {
+	if t := parseW3CDate(e.LastMod); !t.IsZero() {
+		return t
+	}
+	return parseW3CDate(e.News.PublicationDate)
+}
+
+// Published devuelve la fecha de publicación declarada en news:publication_date.
+func (e Entry) Published() time.Time {
+	return parseW3CDate(e.News.PublicationDate)
+}
+
+// parseW3CDate admite las variantes del formato W3C que usan los sitemaps.
+func parseW3CDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// Normalize convierte la entrada al modelo común del corpus con la página
+// descargada y el texto que se extrajo de ella. El título y la fecha del
+// sitemap de noticias tienen prioridad sobre los de la página; sin fecha de
+// publicación en ninguno de los dos queda el lastmod.
+func (e Entry) Normalize(page []byte, res *extract.Result) *article.Article {
+	a := &article.Article{
+		Source:    "sitemap",
+		URL:       strings.TrimSpace(e.Loc),
+		Title:     strings.TrimSpace(e.News.Title),
+		Author:    res.Author,
+		Body:      res.Text,
+		Published: e.Published(),
+		Language:  language(e.News.Publication.Language),
+	}
+	a.Domain = crawler.Domain(a.URL)
+	if doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page)); err == nil {
+		if a.Title == "" {
+			a.Title = strings.TrimSpace(doc.Find(`meta[property="og:title"]`).First().AttrOr("content", ""))
+		}
+		if a.Title == "" {
+			a.Title = strings.TrimSpace(doc.Find("title").First().Text())
+		}
+		a.Summary = strings.TrimSpace(doc.Find(`meta[property="og:description"], meta[name="description"]`).First().AttrOr("content", ""))
+		if a.Language == "" {
+			a.Language = language(doc.Find("html").AttrOr("lang", ""))
+		}
+		links := editions.DocumentLinks(a.URL, doc)
+		a.Canonical, a.Alternates = links.Canonical, links.Alternates
+	}
+	if a.Published.IsZero() {
+		a.Published = res.Published
+	}
+	if a.Published.IsZero() {
+		a.Published = e.Modified()
+	}
+	if res.Strategy != "" {
+		a.Extraction = res
+	}
+	switch {
+	case res.Text == "":
+		a.ExtractionIssue = article.IssueEmptyBody
+	case res.Strategy == extract.StrategyDescription:
+		a.ExtractionIssue = article.IssueDescriptionOnly
+	}
+	return a
+}
+
+// language reduce un idioma a ISO 639-1 ("es-CO" -> es).
+func language(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(s, "_-"); i > 0 {
+		s = s[:i]
+	}
+	if len(s) != 2 {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,189 @@
+package split
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go-collector/buildinfo"
+	"go-collector/storage"
+)
+
+// Nombres de las particiones, en el orden de Ratios.
+var Names = [3]string{"train", "dev", "test"}
+
+// Options controla la partición del corpus etiquetado.
+type Options struct {
+	Ratios [3]float64 // proporciones train/dev/test; deben sumar 1
+	Seed   int64      // semilla fija para que la partición sea reproducible
+	// Collector identifica el binario y la configuración; va al manifiesto.
+	Collector buildinfo.Stamp
+}
+
+// Manifest documenta la partición para reproducir el experimento.
+type Manifest struct {
+	CreatedAt time.Time                 `json:"created_at"`
+	Seed      int64                     `json:"seed"`
+	Ratios    [3]float64                `json:"ratios"`
+	Total     int                       `json:"total"`
+	Counts    map[string]int            `json:"counts"`
+	Strata    map[string]map[string]int `json:"strata"` // "fuente|etiqueta" -> partición -> cantidad
+	Files     map[string]string         `json:"files"`  // archivo -> sha256
+	Collector buildinfo.Stamp           `json:"collector"`
+	// Excluded y Redacted cuentan los artículos etiquetados que los
+	// términos de uso dejaron fuera o sin el texto completo.
+	Excluded int `json:"excluded,omitempty"`
+	Redacted int `json:"redacted,omitempty"`
+}
+
+// record es una línea de los archivos de cada partición.
+type record struct {
+	ID       int64  `json:"id"`
+	Source   string `json:"source"`
+	Language string `json:"language"`
+	Title    string `json:"title"`
+	Summary  string `json:"summary,omitempty"`
+	Body     string `json:"body,omitempty"`
+	URL      string `json:"url"`
+	Label    string `json:"label"`
+}
+
+// Split reparte los artículos en train/dev/test estratificando por fuente y
+// etiqueta: cada estrato se baraja con la semilla y se corta según las proporciones.
+func Split(items []storage.LabeledArticle, opts Options) ([3][]storage.LabeledArticle, map[string]map[string]int, error) {
+	var out [3][]storage.LabeledArticle
+	if err := validate(opts.Ratios); err != nil {
+		return out, nil, err
+	}
+
+	strata := make(map[string][]storage.LabeledArticle)
+	for _, it := range items {
+		key := it.Article.Source + "|" + it.Label
+		strata[key] = append(strata[key], it)
+	}
+	// Se recorren los estratos en orden fijo para que la semilla produzca siempre lo mismo.
+	keys := make([]string, 0, len(strata))
+	for k := range strata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	counts := make(map[string]map[string]int, len(keys))
+	for _, key := range keys {
+		group := strata[key]
+		rng.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+
+		n := len(group)
+		nTrain := int(math.Round(float64(n) * opts.Ratios[0]))
+		nDev := int(math.Round(float64(n) * opts.Ratios[1]))
+		if nTrain+nDev > n {
+			nDev = n - nTrain
+		}
+		cuts := [3][]storage.LabeledArticle{group[:nTrain], group[nTrain : nTrain+nDev], group[nTrain+nDev:]}
+
+		counts[key] = make(map[string]int, 3)
+		for i, part := range cuts {
+			out[i] = append(out[i], part...)
+			counts[key][Names[i]] = len(part)
+		}
+	}
+	return out, counts, nil
+}
+
+// Export escribe en dir train.jsonl, dev.jsonl, test.jsonl y manifest.json
+// con los artículos etiquetados de store, con lo que sus términos de uso
+// permiten redistribuir (ver storage.Store.Shareable).
+func Export(store *storage.Store, dir string, opts Options) (*Manifest, error) {
+	labeled, err := store.LabeledArticles()
+	if err != nil {
+		return nil, err
+	}
+	if len(labeled) == 0 {
+		return nil, fmt.Errorf("no hay artículos etiquetados (use \"collector labels import\")")
+	}
+	var sharing storage.Sharing
+	items := make([]storage.LabeledArticle, 0, len(labeled))
+	for _, it := range labeled {
+		if it.Article = sharing.Add(store.Shareable(it.Article)); it.Article != nil {
+			items = append(items, it)
+		}
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("los términos de uso excluyen los %d artículos etiquetados", len(labeled))
+	}
+	parts, strata, err := Split(items, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creando directorio %s: %w", dir, err)
+	}
+
+	m := &Manifest{
+		CreatedAt: time.Now().UTC(),
+		Seed:      opts.Seed,
+		Ratios:    opts.Ratios,
+		Total:     len(items),
+		Counts:    make(map[string]int),
+		Strata:    strata,
+		Files:     make(map[string]string),
+		Collector: opts.Collector,
+		Excluded:  sharing.Excluded,
+		Redacted:  sharing.Redacted,
+	}
+	for i, part := range parts {
+		name := Names[i] + ".jsonl"
+		sum, err := writePart(filepath.Join(dir, name), part)
+		if err != nil {
+			return nil, err
+		}
+		m.Counts[Names[i]] = len(part)
+		m.Files[name] = sum
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), data, 0o644); err != nil {
+		return nil, fmt.Errorf("error escribiendo manifiesto: %w", err)
+	}
+	return m, nil
+}
+
+// writePart escribe una partición en JSON Lines y devuelve su sha256.
+func writePart(path string, items []storage.LabeledArticle) (string, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("error creando %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	enc := json.NewEncoder(io.MultiWriter(f, h))
+	for _, it := range items {
+		a := it.Article
+		rec := record{ID: a.ID, Source: a.Source, Language: a.Language, Title: a.Title, Summary: a.Summary, Body: a.Body, URL: a.URL, Label: it.Label}
+		if err := enc.Encode(rec); err != nil {
+			return "", fmt.Errorf("error escribiendo %s: %w", path, err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), f.Close()
+}
+
+func validate(r [3]float64) error {
+	sum := r[0] + r[1] + r[2]
+	if r[0] < 0 || r[1] < 0 || r[2] < 0 || math.Abs(sum-1) > 1e-6 {
+		return fmt.Errorf("las proporciones deben ser no negativas y sumar 1 (suman %.3f)", sum)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package split
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-collector/article"
+	"go-collector/storage"
+)
+
+// Las particiones llevan solo lo que los términos de uso permiten
+// redistribuir, y el manifiesto cuenta lo que quedó fuera.
+func TestExportTerms(t *testing.T) {
+	store, err := storage.Open(t.TempDir() + "/corpus.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	err = store.SyncPublishers([]storage.Publisher{
+		{Kind: "domain", Name: "eltiempo.com", TOS: "restricted", Text: storage.TextStore},
+		{Kind: "domain", Name: "semana.com", TOS: "restricted", Text: storage.TextShare, Exclude: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	published := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	for _, a := range []*article.Article{
+		{Source: "rss", Domain: "www.eltiempo.com", URL: "https://www.eltiempo.com/educacion/matriculas-udea", Title: "La UdeA abre matrículas", Body: "Texto completo de El Tiempo.", Published: published},
+		{Source: "rss", Domain: "www.semana.com", URL: "https://www.semana.com/nacion/paro-udea", Title: "Paro en la UdeA", Body: "Texto completo de Semana.", Published: published},
+		{Source: "rss", Domain: "udea.edu.co", URL: "https://www.udea.edu.co/noticias/calendario", Title: "Calendario académico", Body: "Texto completo de la UdeA.", Published: published},
+	} {
+		if err := store.SaveArticle(a); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SetLabel(a.ID, "educacion", "ana"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := t.TempDir()
+	m, err := Export(store, dir, Options{Ratios: [3]float64{1, 0, 0}, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Total != 2 || m.Excluded != 1 || m.Redacted != 1 {
+		t.Errorf("manifiesto: %d artículos, %d excluidos, %d sin texto; se esperaban 2, 1 y 1", m.Total, m.Excluded, m.Redacted)
+	}
+
+	got := map[string]string{}
+	for _, name := range Names {
+		f, err := os.Open(filepath.Join(dir, name+".jsonl"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			var rec record
+			if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+				t.Fatal(err)
+			}
+			got[rec.URL] = rec.Body
+		}
+		f.Close()
+	}
+	if _, ok := got["https://www.semana.com/nacion/paro-udea"]; ok {
+		t.Error("la partición incluye el artículo de un medio excluido")
+	}
+	if body := got["https://www.eltiempo.com/educacion/matriculas-udea"]; body != "" {
+		t.Errorf("la partición incluye el texto de un medio que solo permite guardarlo: %q", body)
+	}
+	if body := got["https://www.udea.edu.co/noticias/calendario"]; body != "Texto completo de la UdeA." {
+		t.Errorf("texto del medio sin términos = %q", body)
+	}
+}
@@ -0,0 +1,112 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"go-collector/corpus"
+	"go-collector/storage"
+)
+
+// Description son las estadísticas del corpus listas para una publicación.
+type Description struct {
+	*storage.CorpusSummary
+}
+
+// KeyValue es una estructura auxiliar para ordenar mapas.
+type KeyValue struct {
+	Key   string
+	Value int
+}
+
+// Describe calcula las estadísticas del corpus (SQLite o en memoria).
+func Describe(store corpus.Store) (*Description, error) {
+	sum, err := store.Summary()
+	if err != nil {
+		return nil, err
+	}
+	return &Description{sum}, nil
+}
+
+// DedupRate es la fracción de artículos que son ediciones repetidas de otra historia.
+func (d *Description) DedupRate() float64 {
+	if d.Total == 0 {
+		return 0
+	}
+	return float64(d.Duplicates) / float64(d.Total)
+}
+
+// ExtractionRate es la fracción de extracciones de texto exitosas.
+func (d *Description) ExtractionRate() float64 {
+	if d.ExtractionAttempts == 0 {
+		return 0
+	}
+	return float64(d.ExtractionOK) / float64(d.ExtractionAttempts)
+}
+
+// rows arma las filas (métrica, valor) comunes a todos los formatos.
+func (d *Description) rows() [][2]string {
+	coverage := "-"
+	if !d.First.IsZero() {
+		coverage = fmt.Sprintf("%s – %s", d.First.Format("2006-01-02"), d.Last.Format("2006-01-02"))
+	}
+	out := [][2]string{
+		{"Artículos", fmt.Sprintf("%d", d.Total)},
+		{"Cobertura de fechas", coverage},
+		{"Artículos retirados", fmt.Sprintf("%d", d.Withdrawn)},
+		{"Longitud media (palabras)", fmt.Sprintf("%.1f", d.MeanWords)},
+		{"Tasa de duplicados", fmt.Sprintf("%.1f%%", d.DedupRate()*100)},
+		{"Extracción exitosa", fmt.Sprintf("%.1f%% (%d/%d)", d.ExtractionRate()*100, d.ExtractionOK, d.ExtractionAttempts)},
+	}
+	for _, kv := range sorted(d.BySource) {
+		out = append(out, [2]string{"Fuente: " + kv.Key, fmt.Sprintf("%d", kv.Value)})
+	}
+	for _, kv := range sorted(d.ByLanguage) {
+		out = append(out, [2]string{"Idioma: " + kv.Key, fmt.Sprintf("%d", kv.Value)})
+	}
+	return out
+}
+
+// WriteMarkdown escribe la tabla en formato Markdown.
+func (d *Description) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Métrica | Valor |\n|---|---:|\n")
+	for _, r := range d.rows() {
+		fmt.Fprintf(&b, "| %s | %s |\n", r[0], r[1])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteLaTeX escribe la tabla como un entorno tabular de LaTeX (booktabs).
+func (d *Description) WriteLaTeX(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("\\begin{table}[ht]\n\\centering\n\\begin{tabular}{lr}\n\\toprule\nMétrica & Valor \\\\\n\\midrule\n")
+	for _, r := range d.rows() {
+		fmt.Fprintf(&b, "%s & %s \\\\\n", latexEscape(r[0]), latexEscape(r[1]))
+	}
+	b.WriteString("\\bottomrule\n\\end{tabular}\n\\caption{Estadísticas del corpus}\n\\label{tab:corpus}\n\\end{table}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func latexEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\textbackslash{}`, `&`, `\&`, `%`, `\%`, `$`, `\$`, `#`, `\#`, `_`, `\_`, `{`, `\{`, `}`, `\}`, `–`, `--`)
+	return r.Replace(s)
+}
+
+func sorted(m map[string]int) []KeyValue {
+	var kvList []KeyValue
+	for k, v := range m {
+		kvList = append(kvList, KeyValue{k, v})
+	}
+	sort.Slice(kvList, func(i, j int) bool {
+		if kvList[i].Value != kvList[j].Value {
+			return kvList[i].Value > kvList[j].Value
+		}
+		return kvList[i].Key < kvList[j].Key
+	})
+	return kvList
+}
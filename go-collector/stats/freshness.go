@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"go-collector/storage"
+)
+
+// SourceLag resume cuánto tarda una fuente en entregar los artículos desde su
+// publicación. Los cuantiles se expresan en horas.
+type SourceLag struct {
+	Source   string  `json:"source"`
+	Articles int     `json:"articles"`
+	P25      float64 `json:"p25_hours"`
+	Median   float64 `json:"median_hours"`
+	P75      float64 `json:"p75_hours"`
+	P90      float64 `json:"p90_hours"`
+	// First es en cuántos de los artículos que trajo también otra fuente
+	// esta fue la primera en entregarlo.
+	First  int `json:"first"`
+	Shared int `json:"shared"`
+	// Ahead son los artículos entregados antes de su fecha de publicación
+	// (zonas horarias mal declaradas o fechas solo con día): cuentan con
+	// demora cero.
+	Ahead int `json:"ahead"`
+}
+
+// Freshness agrupa las entregas por fuente, de la más rápida (menor mediana)
+// a la más lenta.
+func Freshness(sightings []storage.Sighting) []SourceLag {
+	lags := make(map[string][]time.Duration)
+	ahead := make(map[string]int)
+	byArticle := make(map[int64][]storage.Sighting)
+	for _, v := range sightings {
+		lag := v.Seen.Sub(v.Published)
+		if lag < 0 {
+			ahead[v.Source]++
+			lag = 0
+		}
+		lags[v.Source] = append(lags[v.Source], lag)
+		byArticle[v.ArticleID] = append(byArticle[v.ArticleID], v)
+	}
+
+	first := make(map[string]int)
+	shared := make(map[string]int)
+	for _, vs := range byArticle {
+		if len(vs) < 2 {
+			continue
+		}
+		earliest := vs[0]
+		for _, v := range vs {
+			shared[v.Source]++
+			if v.Seen.Before(earliest.Seen) {
+				earliest = v
+			}
+		}
+		first[earliest.Source]++
+	}
+
+	out := make([]SourceLag, 0, len(lags))
+	for src, ds := range lags {
+		slices.Sort(ds)
+		out = append(out, SourceLag{
+			Source: src, Articles: len(ds),
+			P25: hours(quantile(ds, 0.25)), Median: hours(quantile(ds, 0.5)),
+			P75: hours(quantile(ds, 0.75)), P90: hours(quantile(ds, 0.9)),
+			First: first[src], Shared: shared[src], Ahead: ahead[src],
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Median != out[j].Median {
+			return out[i].Median < out[j].Median
+		}
+		return out[i].Source < out[j].Source
+	})
+	return out
+}
+
+// quantile interpola linealmente el cuantil q de ds, ya ordenado.
+func quantile(ds []time.Duration, q float64) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	pos := q * float64(len(ds)-1)
+	i := int(pos)
+	if i+1 >= len(ds) {
+		return ds[len(ds)-1]
+	}
+	return ds[i] + time.Duration(float64(ds[i+1]-ds[i])*(pos-float64(i)))
+}
+
+func hours(d time.Duration) float64 {
+	return d.Hours()
+}
@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"go-collector/entities"
+	"go-collector/storage"
+)
+
+// Tendencias de entidades: las más mencionadas en la última semana del
+// corpus, frente a la semana anterior.
+const (
+	TrendWindow = 7 * 24 * time.Hour
+	trendLimit  = 5
+)
+
+// Overview es el estado del corpus de un vistazo: totales, cobertura, reparto
+// por fuente e idioma, las entidades en tendencia (ver collector entities) y
+// la última ronda de recolección. Es lo que muestra
+// "collector stats" y lo que devuelve /stats en "collector serve".
+type Overview struct {
+	Total      int            `json:"total"`
+	Withdrawn  int            `json:"withdrawn"`
+	First      *time.Time     `json:"first_published,omitempty"`
+	Last       *time.Time     `json:"last_published,omitempty"`
+	BySource   map[string]int `json:"by_source"`
+	ByLanguage map[string]int `json:"by_language"`
+	LastRun    *RunInfo       `json:"last_run,omitempty"`
+	// Trending son, por tipo de entidad, las más mencionadas en los
+	// TrendWindow anteriores a Last.
+	Trending map[string][]storage.EntityTrend `json:"trending,omitempty"`
+}
+
+// RunInfo resume una ronda de recolección.
+type RunInfo struct {
+	ID       int64      `json:"id"`
+	Started  time.Time  `json:"started"`
+	Finished *time.Time `json:"finished,omitempty"`
+	Campaign string     `json:"campaign,omitempty"`
+	Status   string     `json:"status"`
+	Fetched  int        `json:"fetched"`
+	Stored   int        `json:"stored"`
+	Failed   int        `json:"failed_sources"`
+	Skipped  int        `json:"skipped_sources,omitempty"`
+}
+
+// NewRunInfo convierte el resumen de una ronda del historial.
+func NewRunInfo(r storage.RunSummary) *RunInfo {
+	info := &RunInfo{
+		ID: r.ID, Started: r.Started, Campaign: r.Campaign, Status: r.Status,
+		Fetched: r.Fetched, Stored: r.Stored, Failed: r.Failed, Skipped: r.Skipped,
+	}
+	if !r.Finished.IsZero() {
+		info.Finished = &r.Finished
+	}
+	return info
+}
+
+// Summarize calcula el estado del corpus; opts controla los lectores en
+// paralelo y el progreso del recorrido (ver storage.SummaryScan).
+func Summarize(ctx context.Context, store *storage.Store, opts storage.ScanOptions) (*Overview, error) {
+	sum, err := store.SummaryScan(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	o := &Overview{
+		Total: sum.Total, Withdrawn: sum.Withdrawn,
+		BySource: sum.BySource, ByLanguage: sum.ByLanguage,
+	}
+	if !sum.First.IsZero() {
+		o.First, o.Last = &sum.First, &sum.Last
+		trends, err := store.EntityTrends(entities.Kinds, sum.Last, TrendWindow, trendLimit)
+		if err != nil {
+			return nil, err
+		}
+		for kind, t := range trends {
+			if o.Trending == nil {
+				o.Trending = map[string][]storage.EntityTrend{}
+			}
+			o.Trending[kind] = t
+		}
+	}
+	runs, err := store.ListRuns(storage.RunFilter{Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) > 0 {
+		o.LastRun = NewRunInfo(runs[0])
+	}
+	return o, nil
+}
+
+// Sources devuelve el reparto por fuente, de mayor a menor.
+func (o *Overview) Sources() []KeyValue { return sorted(o.BySource) }
+
+// Languages devuelve el reparto por idioma, de mayor a menor.
+func (o *Overview) Languages() []KeyValue { return sorted(o.ByLanguage) }
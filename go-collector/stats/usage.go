@@ -0,0 +1,46 @@
+package stats
+
+import (
+	"go-collector/config"
+	"go-collector/storage"
+)
+
+// SourceCost es el uso de una fuente en un mes con su costo estimado según la
+// tarifa configurada (cost). Cost es nil en las fuentes sin tarifa.
+type SourceCost struct {
+	Month      string   `json:"month"`
+	Source     string   `json:"source"`
+	Rounds     int      `json:"rounds"`
+	Requests   int      `json:"requests"`
+	Fetched    int      `json:"fetched"`
+	Stored     int      `json:"stored"`
+	PerRequest float64  `json:"articles_per_request"`
+	Cost       *float64 `json:"cost,omitempty"`
+	Currency   string   `json:"currency,omitempty"`
+	// PerStored es el costo de cada artículo que llegó al corpus, la cifra
+	// para comparar fuentes pagas entre sí.
+	PerStored *float64 `json:"cost_per_stored,omitempty"`
+	// Included son las peticiones que cubre el cargo fijo del plan.
+	Included int `json:"included,omitempty"`
+}
+
+// Costs estima el costo de cada mes y fuente con las tarifas de sources.
+func Costs(usage []storage.SourceUsage, sources *config.Sources) []SourceCost {
+	out := make([]SourceCost, 0, len(usage))
+	for _, u := range usage {
+		c := SourceCost{
+			Month: u.Month, Source: u.Source, Rounds: u.Rounds,
+			Requests: u.Requests, Fetched: u.Fetched, Stored: u.Stored, PerRequest: u.PerRequest(),
+		}
+		if src := sources.Get(u.Source); src != nil && src.Cost != nil {
+			cost := src.Cost.Estimate(u.Requests)
+			c.Cost, c.Currency, c.Included = &cost, src.Cost.Unit(), src.Cost.Included
+			if u.Stored > 0 {
+				per := cost / float64(u.Stored)
+				c.PerStored = &per
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// AccountSnapshot es el estado de una cuenta monitoreada en un día (ver
+// collector accounts): la última consulta del día reemplaza a las previas.
+type AccountSnapshot struct {
+	Platform  string
+	Handle    string
+	Day       time.Time // el día en UTC, a las 00:00
+	Name      string
+	Bio       string
+	Followers int
+	Following int
+	Posts     int
+	Taken     time.Time
+}
+
+// SaveAccountSnapshot guarda (o reemplaza) el estado del día de la cuenta.
+func (s *Store) SaveAccountSnapshot(a AccountSnapshot) error {
+	_, err := s.db.Exec(`
+		INSERT INTO account_snapshots (platform, handle, day, name, bio, followers, following, posts, taken_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(platform, handle, day) DO UPDATE SET
+			name = excluded.name, bio = excluded.bio, followers = excluded.followers,
+			following = excluded.following, posts = excluded.posts, taken_at = excluded.taken_at`,
+		a.Platform, a.Handle, a.Day.UTC().Format("2006-01-02"), a.Name, a.Bio, a.Followers, a.Following, a.Posts, formatTime(a.Taken))
+	if err != nil {
+		return fmt.Errorf("error guardando el estado de %s/%s: %w", a.Platform, a.Handle, err)
+	}
+	return nil
+}
+
+// AccountSnapshots devuelve los estados registrados entre since y until
+// (días inclusive; en cero no restringen), por cuenta y en orden
+// cronológico.
+func (s *Store) AccountSnapshots(since, until time.Time) ([]AccountSnapshot, error) {
+	query := `SELECT platform, handle, day, name, bio, followers, following, posts, taken_at FROM account_snapshots WHERE 1 = 1`
+	var args []any
+	if !since.IsZero() {
+		query += ` AND day >= ?`
+		args = append(args, since.UTC().Format("2006-01-02"))
+	}
+	if !until.IsZero() {
+		query += ` AND day <= ?`
+		args = append(args, until.UTC().Format("2006-01-02"))
+	}
+	rows, err := s.db.Query(query+` ORDER BY platform, handle, day`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando el estado de las cuentas: %w", err)
+	}
+	defer rows.Close()
+
+	var out []AccountSnapshot
+	for rows.Next() {
+		var a AccountSnapshot
+		var day, taken string
+		if err := rows.Scan(&a.Platform, &a.Handle, &day, &a.Name, &a.Bio, &a.Followers, &a.Following, &a.Posts, &taken); err != nil {
+			return nil, err
+		}
+		a.Day, _ = time.Parse("2006-01-02", day)
+		a.Taken = parseTime(taken)
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go-collector/article"
+)
+
+// SetAnnotations reemplaza los temas, entidades, hashtags y menciones que la
+// plataforma informó para el artículo: cada recolección que lo vuelve a
+// traer los actualiza.
+func (s *Store) SetAnnotations(articleID int64, annotations []article.Annotation) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error guardando anotaciones del artículo %d: %w", articleID, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM article_annotations WHERE article_id = ?`, articleID); err != nil {
+		return fmt.Errorf("error guardando anotaciones del artículo %d: %w", articleID, err)
+	}
+	for _, a := range annotations {
+		if _, err := tx.Exec(`
+			INSERT OR IGNORE INTO article_annotations (article_id, kind, domain, domain_id, name, entity_id, score)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			articleID, a.Kind, a.Domain, a.DomainID, a.Name, a.ID, a.Score); err != nil {
+			return fmt.Errorf("error guardando anotaciones del artículo %d: %w", articleID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Annotations devuelve las anotaciones del artículo por clase.
+func (s *Store) Annotations(articleID int64) ([]article.Annotation, error) {
+	rows, err := s.db.Query(`
+		SELECT kind, domain, domain_id, name, entity_id, score FROM article_annotations
+		WHERE article_id = ? ORDER BY kind, domain, name`, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando anotaciones del artículo %d: %w", articleID, err)
+	}
+	defer rows.Close()
+
+	var out []article.Annotation
+	for rows.Next() {
+		var a article.Annotation
+		if err := rows.Scan(&a.Kind, &a.Domain, &a.DomainID, &a.Name, &a.ID, &a.Score); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// SetGeo guarda (o reemplaza) el lugar con que se etiquetó el artículo.
+func (s *Store) SetGeo(articleID int64, g *article.Geo) error {
+	_, err := s.db.Exec(`
+		INSERT INTO article_geo (article_id, place_id, place, country, lat, lon) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(article_id) DO UPDATE SET
+			place_id = excluded.place_id, place = excluded.place, country = excluded.country,
+			lat = excluded.lat, lon = excluded.lon`,
+		articleID, g.PlaceID, g.Place, g.Country, g.Lat, g.Lon)
+	if err != nil {
+		return fmt.Errorf("error guardando el lugar del artículo %d: %w", articleID, err)
+	}
+	return nil
+}
+
+// Geo devuelve el lugar del artículo, o ErrNotFound si no lo tiene.
+func (s *Store) Geo(articleID int64) (*article.Geo, error) {
+	g := &article.Geo{}
+	err := s.db.QueryRow(`SELECT place_id, place, country, lat, lon FROM article_geo WHERE article_id = ?`, articleID).
+		Scan(&g.PlaceID, &g.Place, &g.Country, &g.Lat, &g.Lon)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo el lugar del artículo %d: %w", articleID, err)
+	}
+	return g, nil
+}
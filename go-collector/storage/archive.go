@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// ArchiveCheck es el estado de un artículo en el Internet Archive (ver
+// collector wayback): si tiene una captura y cuál es la más reciente.
+type ArchiveCheck struct {
+	ArticleID   int64
+	URL         string
+	Archived    bool
+	SnapshotURL string
+	SnapshotAt  time.Time // marca de tiempo de la captura
+	Checked     time.Time
+	// SaveRequested es cuándo se pidió capturarla con Save Page Now; cero si
+	// nunca se pidió.
+	SaveRequested time.Time
+	Err           string // por qué no se pudo consultar
+}
+
+// SaveArchiveCheck guarda (o reemplaza) el estado de archivo del artículo.
+func (s *Store) SaveArchiveCheck(c ArchiveCheck) error {
+	_, err := s.db.Exec(`
+		INSERT INTO archive_checks (article_id, url, archived, snapshot_url, snapshot_at, checked_at, save_requested_at, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(article_id) DO UPDATE SET
+			url = excluded.url, archived = excluded.archived,
+			snapshot_url = excluded.snapshot_url, snapshot_at = excluded.snapshot_at,
+			checked_at = excluded.checked_at, save_requested_at = excluded.save_requested_at,
+			error = excluded.error`,
+		c.ArticleID, c.URL, c.Archived, c.SnapshotURL, formatTime(c.SnapshotAt), formatTime(c.Checked), formatTime(c.SaveRequested), c.Err)
+	if err != nil {
+		return fmt.Errorf("error guardando el estado de archivo del artículo %d: %w", c.ArticleID, err)
+	}
+	return nil
+}
+
+// ArchiveChecks devuelve el estado de archivo de los artículos ya
+// consultados, por ID de artículo.
+func (s *Store) ArchiveChecks() (map[int64]ArchiveCheck, error) {
+	rows, err := s.db.Query(`
+		SELECT article_id, url, archived, snapshot_url, snapshot_at, checked_at, save_requested_at, error
+		FROM archive_checks`)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando el estado de archivo: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[int64]ArchiveCheck)
+	for rows.Next() {
+		var c ArchiveCheck
+		var snapshotAt, checked, requested string
+		if err := rows.Scan(&c.ArticleID, &c.URL, &c.Archived, &c.SnapshotURL, &snapshotAt, &checked, &requested, &c.Err); err != nil {
+			return nil, err
+		}
+		c.SnapshotAt, c.Checked, c.SaveRequested = parseTime(snapshotAt), parseTime(checked), parseTime(requested)
+		out[c.ArticleID] = c
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,474 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-collector/article"
+)
+
+const articleColumns = `id, source, url, title, author, domain, language, section, summary, body,
+	published, collected, status, withdrawn_at, withdrawn_reason, extraction_issue, edition_group, explanation, title_translated`
+
+// SaveArticle inserta el artículo o actualiza sus metadatos si la URL ya existe.
+// El estado de retiro no se toca aquí: solo MarkWithdrawn puede cambiarlo.
+func (s *Store) SaveArticle(a *article.Article) error {
+	if a.Collected.IsZero() {
+		a.Collected = time.Now().UTC()
+	}
+	if a.Status == "" {
+		a.Status = article.StatusActive
+	}
+
+	author, err := s.encryptAuthor(a.Source, a.Author)
+	if err != nil {
+		return err
+	}
+	a.Body = s.storedBody(a.Source, a.Domain, a.Body)
+
+	var published string
+	err = s.db.QueryRow(`
+		INSERT INTO articles (source, url, title, author, domain, language, section, summary, body, published, collected, status, title_translated, extraction_issue)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(url) DO UPDATE SET
+			title = excluded.title,
+			title_translated = CASE WHEN excluded.title_translated != '' THEN excluded.title_translated ELSE articles.title_translated END,
+			author = excluded.author,
+			domain = excluded.domain,
+			language = excluded.language,
+			section = excluded.section,
+			summary = excluded.summary,
+			body = CASE WHEN excluded.body != '' THEN excluded.body ELSE articles.body END,
+			extraction_issue = CASE WHEN excluded.body != '' OR excluded.extraction_issue != '' THEN excluded.extraction_issue ELSE articles.extraction_issue END,
+			published = CASE WHEN ? AND articles.published != '' THEN articles.published ELSE excluded.published END
+		RETURNING id, published`,
+		a.Source, a.URL, a.Title, author, a.Domain, a.Language, a.Section, a.Summary, s.bodyValue(a.Body),
+		formatTime(a.Published), formatTime(a.Collected), a.Status, a.TitleTranslated, a.ExtractionIssue,
+		// Una fecha corregida es la de descarga: la de la primera vez que se
+		// vio el artículo se conserva en las rondas siguientes.
+		a.DateIssue != "",
+	).Scan(&a.ID, &published)
+	if err != nil {
+		return fmt.Errorf("error guardando artículo %s: %w", a.URL, err)
+	}
+	if a.DateIssue != "" {
+		a.Published = parseTime(published)
+	}
+	return nil
+}
+
+// GetByURL busca un artículo por su URL exacta.
+func (s *Store) GetByURL(url string) (*article.Article, error) {
+	row := s.db.QueryRow(`SELECT `+articleColumns+` FROM articles WHERE url = ?`, url)
+	a, err := s.scanArticle(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return a, err
+}
+
+// GetByID busca un artículo por su ID.
+func (s *Store) GetByID(id int64) (*article.Article, error) {
+	row := s.db.QueryRow(`SELECT `+articleColumns+` FROM articles WHERE id = ?`, id)
+	a, err := s.scanArticle(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return a, err
+}
+
+// ListActive devuelve los artículos que no han sido marcados como retirados.
+func (s *Store) ListActive() ([]*article.Article, error) {
+	rows, err := s.db.Query(`SELECT `+articleColumns+` FROM articles WHERE status = ? ORDER BY id`, article.StatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("error listando artículos: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*article.Article
+	for rows.Next() {
+		a, err := s.scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// ListPublishedBetween devuelve los artículos activos publicados en [from, to].
+func (s *Store) ListPublishedBetween(from, to time.Time) ([]*article.Article, error) {
+	rows, err := s.db.Query(`SELECT `+articleColumns+` FROM articles
+		WHERE status = ? AND published >= ? AND published <= ? ORDER BY published`,
+		article.StatusActive, formatTime(from), formatTime(to))
+	if err != nil {
+		return nil, fmt.Errorf("error listando artículos por fecha: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*article.Article
+	for rows.Next() {
+		a, err := s.scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// Filter restringe las consultas de artículos. Los campos vacíos no filtran.
+type Filter struct {
+	From, To time.Time
+	Sources  []string
+	Language string
+	Query    string // todas las palabras deben aparecer en título, resumen o cuerpo
+	Limit    int
+	Offset   int // artículos que se saltan, para paginar junto con Limit
+	// IncludeWithdrawn incluye los artículos retirados; si no, solo los activos.
+	IncludeWithdrawn bool
+}
+
+// ListFiltered devuelve los artículos que cumplen el filtro, ordenados por publicación.
+func (s *Store) ListFiltered(f Filter) ([]*article.Article, error) {
+	var out []*article.Article
+	err := s.EachFiltered(f, func(a *article.Article) error {
+		out = append(out, a)
+		return nil
+	})
+	return out, err
+}
+
+// EachFiltered llama a fn con cada artículo que cumple el filtro, en orden de
+// publicación, sin cargarlos todos en memoria (para exportar el corpus
+// completo). fn no puede usar el Store: la conexión está ocupada mientras se
+// recorren las filas. Si fn devuelve un error, se corta y se devuelve ese error.
+func (s *Store) EachFiltered(f Filter, fn func(*article.Article) error) error {
+	where, args := f.where()
+	q := `SELECT ` + articleColumns + ` FROM articles`
+	if len(where) > 0 {
+		q += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	// El ID desempata las notas de la misma fecha: sin él, las páginas de
+	// Limit y Offset pueden repetir o saltarse artículos.
+	q += ` ORDER BY published, id`
+	if f.Limit > 0 || f.Offset > 0 {
+		// En SQLite OFFSET exige LIMIT; -1 no limita.
+		limit := f.Limit
+		if limit <= 0 {
+			limit = -1
+		}
+		q += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, max(f.Offset, 0))
+	}
+
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return fmt.Errorf("error listando artículos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a, err := s.scanArticle(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(a); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// where arma las condiciones SQL del filtro (sin Limit ni Offset) y sus argumentos.
+func (f Filter) where() ([]string, []any) {
+	var where []string
+	var args []any
+	if !f.IncludeWithdrawn {
+		where = append(where, "status = ?")
+		args = append(args, article.StatusActive)
+	}
+	if !f.From.IsZero() {
+		where = append(where, "published >= ?")
+		args = append(args, formatTime(f.From))
+	}
+	if !f.To.IsZero() {
+		where = append(where, "published <= ?")
+		args = append(args, formatTime(f.To))
+	}
+	if len(f.Sources) > 0 {
+		where = append(where, "source IN (?"+strings.Repeat(", ?", len(f.Sources)-1)+")")
+		for _, src := range f.Sources {
+			args = append(args, src)
+		}
+	}
+	if f.Language != "" {
+		where = append(where, "language = ?")
+		args = append(args, f.Language)
+	}
+	for _, word := range strings.Fields(f.Query) {
+		where = append(where, "(title LIKE ? OR summary LIKE ? OR unzstd(body) LIKE ?)")
+		pattern := "%" + word + "%"
+		args = append(args, pattern, pattern, pattern)
+	}
+	return where, args
+}
+
+// SearchText busca artículos activos cuyo título, resumen o cuerpo contengan
+// todas las palabras de la consulta (sin distinguir mayúsculas), más recientes primero.
+func (s *Store) SearchText(q string, limit int) ([]*article.Article, error) {
+	where := []string{"status = ?"}
+	args := []any{article.StatusActive}
+	for _, word := range strings.Fields(q) {
+		where = append(where, "(title LIKE ? OR summary LIKE ? OR unzstd(body) LIKE ?)")
+		pattern := "%" + word + "%"
+		args = append(args, pattern, pattern, pattern)
+	}
+	args = append(args, limit)
+
+	rows, err := s.db.Query(`SELECT `+articleColumns+` FROM articles WHERE `+strings.Join(where, " AND ")+
+		` ORDER BY published DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error buscando artículos: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*article.Article
+	for rows.Next() {
+		a, err := s.scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// ListByEditionGroup devuelve los artículos de un grupo de ediciones (historia).
+func (s *Store) ListByEditionGroup(group int64) ([]*article.Article, error) {
+	rows, err := s.db.Query(`SELECT `+articleColumns+` FROM articles
+		WHERE edition_group = ? OR id = ? ORDER BY published`, group, group)
+	if err != nil {
+		return nil, fmt.Errorf("error listando grupo de ediciones %d: %w", group, err)
+	}
+	defer rows.Close()
+
+	var out []*article.Article
+	for rows.Next() {
+		a, err := s.scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// SetEditionGroup asigna el grupo de ediciones a los artículos indicados.
+func (s *Store) SetEditionGroup(group int64, ids ...int64) error {
+	for _, id := range ids {
+		if _, err := s.db.Exec(`UPDATE articles SET edition_group = ? WHERE id = ?`, group, id); err != nil {
+			return fmt.Errorf("error enlazando ediciones: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReassignEditionGroup mueve todos los artículos del grupo from al grupo to.
+func (s *Store) ReassignEditionGroup(from, to int64) error {
+	if _, err := s.db.Exec(`UPDATE articles SET edition_group = ? WHERE edition_group = ?`, to, from); err != nil {
+		return fmt.Errorf("error reasignando grupo de ediciones: %w", err)
+	}
+	return nil
+}
+
+// MarkWithdrawn marca un artículo como retirado sin borrarlo, guardando la fecha
+// de detección y el motivo. Si ya estaba retirado se conserva la fecha original.
+func (s *Store) MarkWithdrawn(id int64, reason string, detectedAt time.Time) error {
+	res, err := s.db.Exec(`
+		UPDATE articles SET status = ?, withdrawn_at = ?, withdrawn_reason = ?
+		WHERE id = ? AND status != ?`,
+		article.StatusWithdrawn, formatTime(detectedAt), reason, id, article.StatusWithdrawn)
+	if err != nil {
+		return fmt.Errorf("error marcando artículo %d como retirado: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		var exists int
+		if err := s.db.QueryRow(`SELECT COUNT(*) FROM articles WHERE id = ?`, id).Scan(&exists); err != nil {
+			return err
+		}
+		if exists == 0 {
+			return ErrNotFound
+		}
+	}
+	return nil
+}
+
+// UpdateBody guarda el texto completo extraído del artículo, o el motivo por el
+// que no se pudo extraer (issue vacío si la extracción fue exitosa).
+func (s *Store) UpdateBody(id int64, body, issue string) error {
+	if body != "" && len(s.Publishers()) > 0 {
+		var source, domain string
+		if err := s.db.QueryRow(`SELECT source, domain FROM articles WHERE id = ?`, id).Scan(&source, &domain); err != nil {
+			return fmt.Errorf("error leyendo artículo %d: %w", id, err)
+		}
+		body = s.storedBody(source, domain, body)
+	}
+	_, err := s.db.Exec(`UPDATE articles SET body = ?, extraction_issue = ? WHERE id = ?`, s.bodyValue(body), issue, id)
+	if err != nil {
+		return fmt.Errorf("error guardando texto del artículo %d: %w", id, err)
+	}
+	return nil
+}
+
+// ArticlesWithoutBody devuelve, en orden de ID, los artículos activos sin
+// texto completo que no se intentaron extraer; con retry también los que
+// quedaron marcados con un ExtractionIssue.
+func (s *Store) ArticlesWithoutBody(retry bool) ([]*article.Article, error) {
+	where := `status = ? AND body = ''`
+	if !retry {
+		where += ` AND extraction_issue = ''`
+	}
+	rows, err := s.db.Query(`SELECT `+articleColumns+` FROM articles WHERE `+where+` ORDER BY id`, article.StatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando artículos sin texto: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*article.Article
+	for rows.Next() {
+		a, err := s.scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// ArticlesWithoutLanguage devuelve, en orden de ID, los artículos sin idioma
+// o con un código que no es ISO 639-1 en minúsculas (ej: "en-US"); con
+// undetermined también los que quedaron como "und".
+func (s *Store) ArticlesWithoutLanguage(undetermined bool) ([]*article.Article, error) {
+	where := `language = '' OR language GLOB '*[-_A-Z ]*'`
+	if undetermined {
+		where += ` OR language = 'und'`
+	}
+	rows, err := s.db.Query(`SELECT ` + articleColumns + ` FROM articles WHERE ` + where + ` ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando artículos sin idioma: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*article.Article
+	for rows.Next() {
+		a, err := s.scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// SetLanguage guarda el idioma (ISO 639-1) del artículo.
+func (s *Store) SetLanguage(id int64, code string) error {
+	if _, err := s.db.Exec(`UPDATE articles SET language = ? WHERE id = ?`, code, id); err != nil {
+		return fmt.Errorf("error guardando idioma del artículo %d: %w", id, err)
+	}
+	return nil
+}
+
+// FillMetadata guarda el autor y la fecha del artículo donde la fuente no
+// los dio (ej: extraídos de la página); no reemplaza los que ya tenía.
+func (s *Store) FillMetadata(a *article.Article) error {
+	author, err := s.encryptAuthor(a.Source, a.Author)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+		UPDATE articles SET
+			author = CASE WHEN author = '' THEN ? ELSE author END,
+			published = CASE WHEN published = '' THEN ? ELSE published END
+		WHERE id = ?`, author, formatTime(a.Published), a.ID)
+	if err != nil {
+		return fmt.Errorf("error guardando autor y fecha del artículo %d: %w", a.ID, err)
+	}
+	return nil
+}
+
+// SetExplanation guarda la evaluación del filtro de relevancia del artículo.
+func (s *Store) SetExplanation(id int64, exp *article.Explanation) error {
+	data, err := json.Marshal(exp)
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.Exec(`UPDATE articles SET explanation = ? WHERE id = ?`, string(data), id); err != nil {
+		return fmt.Errorf("error guardando explicación del artículo %d: %w", id, err)
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// prefixScanner lee columnas extra antes de las del artículo en consultas con JOIN.
+type prefixScanner struct {
+	sc    scanner
+	extra []any
+}
+
+func (p prefixScanner) Scan(dest ...any) error {
+	return p.sc.Scan(append(append([]any{}, p.extra...), dest...)...)
+}
+
+// prefixed califica las columnas del artículo con el alias de la tabla ("a.id, a.source...").
+func prefixed(alias, columns string) string {
+	parts := strings.Split(columns, ",")
+	for i, c := range parts {
+		parts[i] = alias + "." + strings.TrimSpace(c)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (s *Store) scanArticle(sc scanner) (*article.Article, error) {
+	var (
+		a                    article.Article
+		published, collected string
+		withdrawnAt          sql.NullString
+		explanation          string
+		body                 []byte
+	)
+	err := sc.Scan(&a.ID, &a.Source, &a.URL, &a.Title, &a.Author, &a.Domain, &a.Language, &a.Section,
+		&a.Summary, &body, &published, &collected, &a.Status, &withdrawnAt, &a.WithdrawnReason, &a.ExtractionIssue, &a.EditionGroup, &explanation,
+		&a.TitleTranslated)
+	if err != nil {
+		return nil, err
+	}
+	if a.Body, err = decodeBody(body); err != nil {
+		return nil, fmt.Errorf("error leyendo el cuerpo del artículo %d: %w", a.ID, err)
+	}
+	if s.fields != nil {
+		if a.Author, err = s.fields.DecryptString(a.Author); err != nil {
+			return nil, fmt.Errorf("error descifrando autor del artículo %d: %w", a.ID, err)
+		}
+	}
+	a.Published = parseTime(published)
+	a.Collected = parseTime(collected)
+	if withdrawnAt.Valid && withdrawnAt.String != "" {
+		t := parseTime(withdrawnAt.String)
+		a.WithdrawnAt = &t
+	}
+	if explanation != "" {
+		a.Explanation = new(article.Explanation)
+		if err := json.Unmarshal([]byte(explanation), a.Explanation); err != nil {
+			return nil, fmt.Errorf("error leyendo explicación del artículo %d: %w", a.ID, err)
+		}
+	}
+	return &a, nil
+}
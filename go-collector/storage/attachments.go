@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"go-collector/article"
+)
+
+// Attachment es un archivo asociado a un artículo (ver article.Media). Se
+// registra al guardar el artículo y se descarga después (collector media): el
+// contenido queda en el archivo crudo bajo SHA256, con el tipo detectado en
+// sus primeros bytes.
+type Attachment struct {
+	ArticleID    int64
+	URL          string
+	DeclaredType string // el que declaró la fuente
+	Type         string // el detectado al descargar
+	Size         int64
+	SHA256       string
+	Fetched      time.Time // cero si no se intentó descargar
+	Err          string    // por qué se rechazó o falló la descarga
+
+	// PHash es el hash perceptual de las imágenes (media.PHash, en hex).
+	// Shared indica que la imagen era casi idéntica a una ya guardada: no se
+	// guardó otra copia y SHA256 es el de esa.
+	PHash  string
+	Shared bool
+}
+
+// AddAttachments registra los archivos del artículo como pendientes de
+// descarga. Los ya registrados no se modifican.
+func (s *Store) AddAttachments(articleID int64, media []article.Media) error {
+	for _, m := range media {
+		_, err := s.db.Exec(`
+			INSERT INTO attachments (article_id, url, declared_type) VALUES (?, ?, ?)
+			ON CONFLICT(article_id, url) DO NOTHING`,
+			articleID, m.URL, m.Type)
+		if err != nil {
+			return fmt.Errorf("error registrando adjunto del artículo %d: %w", articleID, err)
+		}
+	}
+	return nil
+}
+
+// PendingAttachments devuelve hasta limit adjuntos sin descargar (limit <= 0:
+// todos); con failed, también los que fallaron o se rechazaron.
+func (s *Store) PendingAttachments(limit int, failed bool) ([]Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE fetched_at = ''`
+	if failed {
+		query += ` OR error != ''`
+	}
+	query += ` ORDER BY article_id, url`
+	var args []any
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	return s.attachments(query, args...)
+}
+
+// Attachments devuelve los adjuntos del artículo.
+func (s *Store) Attachments(articleID int64) ([]Attachment, error) {
+	return s.attachments(`SELECT `+attachmentColumns+` FROM attachments WHERE article_id = ? ORDER BY url`, articleID)
+}
+
+const attachmentColumns = `article_id, url, declared_type, type, size, sha256, fetched_at, error, phash, shared`
+
+func (s *Store) attachments(query string, args ...any) ([]Attachment, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando adjuntos: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Attachment
+	for rows.Next() {
+		var a Attachment
+		var fetched string
+		var shared int
+		if err := rows.Scan(&a.ArticleID, &a.URL, &a.DeclaredType, &a.Type, &a.Size, &a.SHA256, &fetched, &a.Err, &a.PHash, &shared); err != nil {
+			return nil, err
+		}
+		a.Fetched, a.Shared = parseTime(fetched), shared != 0
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// UpdateAttachment guarda el resultado de la descarga de a.
+func (s *Store) UpdateAttachment(a Attachment) error {
+	_, err := s.db.Exec(`
+		UPDATE attachments SET type = ?, size = ?, sha256 = ?, fetched_at = ?, error = ?, phash = ?, shared = ?
+		WHERE article_id = ? AND url = ?`,
+		a.Type, a.Size, a.SHA256, formatTime(a.Fetched), a.Err, a.PHash, boolInt(a.Shared), a.ArticleID, a.URL)
+	if err != nil {
+		return fmt.Errorf("error actualizando adjunto %s: %w", a.URL, err)
+	}
+	return nil
+}
+
+// ImageHashes devuelve el hash perceptual de cada imagen guardada en el
+// archivo crudo (SHA256 -> PHash), sin las que reutilizan la copia de otra.
+func (s *Store) ImageHashes() (map[string]string, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT sha256, phash FROM attachments WHERE phash != '' AND shared = 0 AND error = ''`)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando adjuntos: %w", err)
+	}
+	defer rows.Close()
+	out := make(map[string]string)
+	for rows.Next() {
+		var sha, phash string
+		if err := rows.Scan(&sha, &phash); err != nil {
+			return nil, err
+		}
+		out[sha] = phash
+	}
+	return out, rows.Err()
+}
+
+// SharedImage es una imagen guardada una vez y usada por varios artículos
+// (copias idénticas o casi idénticas, ver Attachment.Shared).
+type SharedImage struct {
+	SHA256   string
+	Articles int
+	URLs     int // URLs distintas que la sirvieron
+}
+
+// SharedImages devuelve las imágenes usadas por más de un artículo, de la más
+// repetida a la menos, hasta limit (limit <= 0: todas).
+func (s *Store) SharedImages(limit int) ([]SharedImage, error) {
+	query := `
+		SELECT sha256, COUNT(DISTINCT article_id), COUNT(DISTINCT url) FROM attachments
+		WHERE sha256 != '' AND type LIKE 'image/%'
+		GROUP BY sha256 HAVING COUNT(DISTINCT article_id) > 1
+		ORDER BY 2 DESC, sha256`
+	var args []any
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando adjuntos: %w", err)
+	}
+	defer rows.Close()
+	var out []SharedImage
+	for rows.Next() {
+		var im SharedImage
+		if err := rows.Scan(&im.SHA256, &im.Articles, &im.URLs); err != nil {
+			return nil, err
+		}
+		out = append(out, im)
+	}
+	return out, rows.Err()
+}
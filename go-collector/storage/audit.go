@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Acciones registradas en la bitácora de auditoría.
+const (
+	AuditConfigChange  = "config.change"
+	AuditImport        = "import"
+	AuditRestore       = "restore"
+	AuditBackup        = "backup"
+	AuditEncrypt       = "encrypt"
+	AuditMigrate       = "migrate-store"
+	AuditMerge         = "merge-campaigns"
+	AuditSourceEnable  = "source.enable"
+	AuditSourceDisable = "source.disable"
+)
+
+// AuditEntry es un registro de la bitácora: quién hizo qué, cuándo y sobre qué.
+// La tabla es de solo anexado: los triggers rechazan modificaciones y borrados.
+type AuditEntry struct {
+	ID     int64
+	At     time.Time
+	Actor  string
+	Action string
+	Target string
+	Detail string
+}
+
+// Audit agrega un registro a la bitácora. Si At es cero se usa la hora actual.
+func (s *Store) Audit(e AuditEntry) error {
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	_, err := s.db.Exec(`INSERT INTO audit_log (at, actor, action, target, detail) VALUES (?, ?, ?, ?, ?)`,
+		formatTime(e.At), e.Actor, e.Action, e.Target, e.Detail)
+	if err != nil {
+		return fmt.Errorf("error registrando auditoría (%s): %w", e.Action, err)
+	}
+	return nil
+}
+
+// AuditFilter restringe la consulta de la bitácora; los campos vacíos no filtran.
+type AuditFilter struct {
+	Action string
+	Actor  string
+	Since  time.Time
+	Limit  int
+}
+
+// AuditLog devuelve los registros más recientes primero.
+func (s *Store) AuditLog(f AuditFilter) ([]AuditEntry, error) {
+	query := `SELECT id, at, actor, action, target, detail FROM audit_log WHERE 1 = 1`
+	var args []any
+	if f.Action != "" {
+		query += ` AND action = ?`
+		args = append(args, f.Action)
+	}
+	if f.Actor != "" {
+		query += ` AND actor = ?`
+		args = append(args, f.Actor)
+	}
+	if !f.Since.IsZero() {
+		query += ` AND at >= ?`
+		args = append(args, formatTime(f.Since))
+	}
+	query += ` ORDER BY id DESC`
+	if f.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, f.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando auditoría: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		var at string
+		if err := rows.Scan(&e.ID, &at, &e.Actor, &e.Action, &e.Target, &e.Detail); err != nil {
+			return nil, err
+		}
+		e.At = parseTime(at)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// LastAudit devuelve el registro más reciente de una acción, o ErrNotFound.
+func (s *Store) LastAudit(action string) (*AuditEntry, error) {
+	e := &AuditEntry{}
+	var at string
+	err := s.db.QueryRow(`
+		SELECT id, at, actor, action, target, detail FROM audit_log
+		WHERE action = ? ORDER BY id DESC LIMIT 1`, action,
+	).Scan(&e.ID, &at, &e.Actor, &e.Action, &e.Target, &e.Detail)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error consultando auditoría: %w", err)
+	}
+	e.At = parseTime(at)
+	return e, nil
+}
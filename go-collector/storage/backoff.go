@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DomainBackoff es el estado de enfriamiento de un dominio que nos bloqueó (429/403).
+// Se persiste para que las corridas nocturnas respeten los bloqueos del día.
+type DomainBackoff struct {
+	Domain        string
+	LastStatus    int
+	LastBlockedAt time.Time
+	CooldownUntil time.Time
+	Strikes       int // bloqueos consecutivos, se reinicia con una respuesta exitosa
+	TotalBlocks   int // bloqueos históricos, nunca se reinicia
+}
+
+// GetBackoff devuelve el estado de un dominio, o ErrNotFound si nunca nos bloqueó.
+func (s *Store) GetBackoff(domain string) (*DomainBackoff, error) {
+	row := s.db.QueryRow(`
+		SELECT domain, last_status, last_blocked_at, cooldown_until, strikes, total_blocks
+		FROM domain_backoff WHERE domain = ?`, domain)
+	b, err := scanBackoff(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return b, err
+}
+
+// SaveBackoff guarda (o reemplaza) el estado de un dominio.
+func (s *Store) SaveBackoff(b *DomainBackoff) error {
+	_, err := s.db.Exec(`
+		INSERT INTO domain_backoff (domain, last_status, last_blocked_at, cooldown_until, strikes, total_blocks)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(domain) DO UPDATE SET
+			last_status = excluded.last_status,
+			last_blocked_at = excluded.last_blocked_at,
+			cooldown_until = excluded.cooldown_until,
+			strikes = excluded.strikes,
+			total_blocks = excluded.total_blocks`,
+		b.Domain, b.LastStatus, formatTime(b.LastBlockedAt), formatTime(b.CooldownUntil), b.Strikes, b.TotalBlocks)
+	if err != nil {
+		return fmt.Errorf("error guardando backoff de %s: %w", b.Domain, err)
+	}
+	return nil
+}
+
+// ChronicBlockers devuelve los dominios con al menos minBlocks bloqueos históricos,
+// de más a menos bloqueos.
+func (s *Store) ChronicBlockers(minBlocks int) ([]*DomainBackoff, error) {
+	rows, err := s.db.Query(`
+		SELECT domain, last_status, last_blocked_at, cooldown_until, strikes, total_blocks
+		FROM domain_backoff WHERE total_blocks >= ? ORDER BY total_blocks DESC, domain`, minBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("error listando dominios bloqueados: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*DomainBackoff
+	for rows.Next() {
+		b, err := scanBackoff(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+func scanBackoff(sc scanner) (*DomainBackoff, error) {
+	var (
+		b                  DomainBackoff
+		lastBlocked, until string
+	)
+	if err := sc.Scan(&b.Domain, &b.LastStatus, &lastBlocked, &until, &b.Strikes, &b.TotalBlocks); err != nil {
+		return nil, err
+	}
+	b.LastBlockedAt = parseTime(lastBlocked)
+	b.CooldownUntil = parseTime(until)
+	return &b, nil
+}
@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"modernc.org/sqlite"
+
+	"go-collector/compress"
+)
+
+// minCompressedBody es el largo desde el que se comprime un cuerpo: en los
+// más cortos el encabezado de zstd se come lo que se ahorra.
+const minCompressedBody = 256
+
+// recompressBatch es cuántos artículos se reescriben por transacción en
+// RecompressBodies.
+const recompressBatch = 500
+
+func init() {
+	// unzstd(body) devuelve el texto de un cuerpo comprimido (y cualquier
+	// otro valor tal cual), para las consultas que buscan en el cuerpo. Los
+	// diccionarios se registran al abrir cada corpus.
+	err := sqlite.RegisterDeterministicScalarFunction("unzstd", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		b, ok := args[0].([]byte)
+		if !ok || !compress.IsCompressed(b) {
+			return args[0], nil
+		}
+		text, err := compress.Decode(b)
+		if err != nil {
+			return nil, err
+		}
+		return string(text), nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// BodyDict es un diccionario entrenado con los cuerpos del corpus.
+type BodyDict struct {
+	compress.Dict
+	Samples int // cuerpos con los que se entrenó
+	Created time.Time
+}
+
+// CompressBodies activa la compresión de los cuerpos que se guardan, con el
+// nivel indicado y el último diccionario entrenado (sin diccionario si no
+// hay ninguno). Los cuerpos ya guardados no cambian hasta RecompressBodies.
+func (s *Store) CompressBodies(level string) error {
+	dicts, err := s.BodyDicts()
+	if err != nil {
+		return err
+	}
+	var d *compress.Dict
+	if len(dicts) > 0 {
+		d = &dicts[len(dicts)-1].Dict
+	}
+	enc, err := compress.NewEncoder(level, d)
+	if err != nil {
+		return err
+	}
+	s.bodies = enc
+	return nil
+}
+
+// BodyEncoder es el compresor de los cuerpos; nil si la compresión no está
+// activa.
+func (s *Store) BodyEncoder() *compress.Encoder {
+	return s.bodies
+}
+
+// loadBodyDicts registra los diccionarios del corpus para poder leer los
+// cuerpos comprimidos con cualquiera de ellos.
+func (s *Store) loadBodyDicts() error {
+	dicts, err := s.BodyDicts()
+	if err != nil {
+		return err
+	}
+	for _, d := range dicts {
+		compress.Register(&d.Dict)
+	}
+	return nil
+}
+
+// AddBodyDict guarda un diccionario nuevo; desde el próximo CompressBodies
+// es el que se usa.
+func (s *Store) AddBodyDict(d *compress.Dict, samples int) error {
+	_, err := s.db.Exec(`INSERT OR REPLACE INTO body_dicts (id, dict, samples, created_at) VALUES (?, ?, ?, ?)`,
+		int64(d.ID), d.Data, samples, formatTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("error guardando el diccionario %d: %w", d.ID, err)
+	}
+	compress.Register(d)
+	return nil
+}
+
+// BodyDicts devuelve los diccionarios del corpus, del más viejo al más nuevo.
+func (s *Store) BodyDicts() ([]BodyDict, error) {
+	rows, err := s.db.Query(`SELECT id, dict, samples, created_at FROM body_dicts ORDER BY created_at, rowid`)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo diccionarios: %w", err)
+	}
+	defer rows.Close()
+	var out []BodyDict
+	for rows.Next() {
+		var (
+			d       BodyDict
+			id      int64
+			created string
+		)
+		if err := rows.Scan(&id, &d.Data, &d.Samples, &created); err != nil {
+			return nil, err
+		}
+		d.ID, d.Created = uint32(id), parseTime(created)
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// BodySamples devuelve el texto de hasta n cuerpos al azar, para entrenar
+// un diccionario o medir la compresión.
+func (s *Store) BodySamples(n int) ([][]byte, error) {
+	rows, err := s.db.Query(`SELECT body FROM articles WHERE body != '' ORDER BY RANDOM() LIMIT ?`, n)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo cuerpos: %w", err)
+	}
+	defer rows.Close()
+	var out [][]byte
+	for rows.Next() {
+		var body []byte
+		if err := rows.Scan(&body); err != nil {
+			return nil, err
+		}
+		if body, err = compress.Decode(body); err != nil {
+			return nil, err
+		}
+		out = append(out, body)
+	}
+	return out, rows.Err()
+}
+
+// bodyValue es el valor con el que se guarda body: comprimido si la
+// compresión está activa y conviene, o el texto tal cual.
+func (s *Store) bodyValue(body string) any {
+	if s.bodies == nil || len(body) < minCompressedBody {
+		return body
+	}
+	if c := s.bodies.Encode([]byte(body)); len(c) < len(body) {
+		return c
+	}
+	return body
+}
+
+// decodeBody devuelve el texto de un cuerpo leído de la base.
+func decodeBody(raw []byte) (string, error) {
+	text, err := compress.Decode(raw)
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
+
+// BodyStorage es lo que ocupan los cuerpos en el corpus.
+type BodyStorage struct {
+	Articles   int   // artículos con cuerpo
+	Compressed int   // de ellos, guardados comprimidos
+	Stored     int64 // bytes guardados
+	Text       int64 // bytes del texto sin comprimir
+}
+
+// BodyStorage mide lo que ocupan los cuerpos guardados.
+func (s *Store) BodyStorage() (BodyStorage, error) {
+	var b BodyStorage
+	err := s.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(typeof(body) = 'blob'), 0),
+			COALESCE(SUM(LENGTH(CAST(body AS BLOB))), 0), COALESCE(SUM(LENGTH(CAST(unzstd(body) AS BLOB))), 0)
+		FROM articles WHERE body != ''`).Scan(&b.Articles, &b.Compressed, &b.Stored, &b.Text)
+	if err != nil {
+		return b, fmt.Errorf("error midiendo los cuerpos: %w", err)
+	}
+	return b, nil
+}
+
+// RecompressBodies vuelve a guardar los cuerpos con la compresión actual
+// (ver CompressBodies): comprime los guardados en texto o con un
+// diccionario anterior y, sin compresión activa, los deja en texto.
+// progress, si no es nil, recibe cuántos artículos lleva revisados y el
+// total. Devuelve cuántos cambió y los bytes antes y después.
+func (s *Store) RecompressBodies(ctx context.Context, progress func(done, total int)) (changed int, before, after int64, err error) {
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM articles WHERE body != ''`).Scan(&total); err != nil {
+		return 0, 0, 0, fmt.Errorf("error contando los cuerpos: %w", err)
+	}
+	type row struct {
+		id   int64
+		body any
+	}
+	var last int64
+	done := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return changed, before, after, err
+		}
+		rows, err := s.db.QueryContext(ctx, `SELECT id, body FROM articles WHERE id > ? AND body != '' ORDER BY id LIMIT ?`, last, recompressBatch)
+		if err != nil {
+			return changed, before, after, fmt.Errorf("error leyendo los cuerpos: %w", err)
+		}
+		var todo []row
+		n := 0
+		for rows.Next() {
+			var (
+				id  int64
+				raw []byte
+			)
+			if err := rows.Scan(&id, &raw); err != nil {
+				rows.Close()
+				return changed, before, after, err
+			}
+			n++
+			last = id
+			text, err := decodeBody(raw)
+			if err != nil {
+				rows.Close()
+				return changed, before, after, fmt.Errorf("error leyendo el cuerpo del artículo %d: %w", id, err)
+			}
+			v := s.bodyValue(text)
+			stored := raw
+			if c, ok := v.([]byte); ok {
+				stored = c
+			} else if compress.IsCompressed(raw) {
+				stored = []byte(text)
+			}
+			before += int64(len(raw))
+			after += int64(len(stored))
+			if string(stored) != string(raw) {
+				todo = append(todo, row{id, v})
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return changed, before, after, err
+		}
+		if n == 0 {
+			return changed, before, after, nil
+		}
+
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return changed, before, after, err
+		}
+		for _, r := range todo {
+			if _, err := tx.Exec(`UPDATE articles SET body = ? WHERE id = ?`, r.body, r.id); err != nil {
+				tx.Rollback()
+				return changed, before, after, fmt.Errorf("error guardando el cuerpo del artículo %d: %w", r.id, err)
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return changed, before, after, fmt.Errorf("error guardando los cuerpos: %w", err)
+		}
+		changed += len(todo)
+		done += n
+		if progress != nil {
+			progress(done, total)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FeedDates es la calidad de las fechas de un feed (o de una consulta, en
+// las fuentes sin feeds) de una fuente: cuántos artículos trajo, cuántos con
+// fecha sin interpretar, futura o imposible (ver dates.Check). Example es
+// una de las fechas con problemas tal como vino. Rounds es cuántas rondas
+// suma (en DateQuality).
+type FeedDates struct {
+	Source   string
+	Feed     string
+	Items    int
+	Unparsed int
+	Future   int
+	Bogus    int
+	Example  string
+	Rounds   int
+}
+
+// Problems es cuántos artículos no trajeron una fecha de publicación válida.
+func (f FeedDates) Problems() int {
+	return f.Unparsed + f.Future + f.Bogus
+}
+
+// AddFeedDates registra la calidad de las fechas de los feeds de una fuente
+// en la ronda id.
+func (s *Store) AddFeedDates(id int64, feeds []FeedDates) error {
+	for _, f := range feeds {
+		_, err := s.db.Exec(`
+			INSERT INTO feed_dates (run_id, source, feed, items, unparsed, future, bogus, example)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(run_id, source, feed) DO UPDATE SET
+				items = items + excluded.items, unparsed = unparsed + excluded.unparsed,
+				future = future + excluded.future, bogus = bogus + excluded.bogus,
+				example = CASE WHEN example != '' THEN example ELSE excluded.example END`,
+			id, f.Source, f.Feed, f.Items, f.Unparsed, f.Future, f.Bogus, f.Example)
+		if err != nil {
+			return fmt.Errorf("error registrando las fechas de %s en la ronda %d: %w", f.Feed, id, err)
+		}
+	}
+	return nil
+}
+
+// DateQuality suma por fuente y feed la calidad de las fechas de las rondas
+// que empezaron desde since (todas si está en cero), solo de sources si no
+// está vacía. Los feeds con más problemas van primero.
+func (s *Store) DateQuality(since time.Time, sources []string) ([]FeedDates, error) {
+	query := `
+		SELECT d.source, d.feed, SUM(d.items), SUM(d.unparsed), SUM(d.future), SUM(d.bogus),
+			MAX(d.example), COUNT(*)
+		FROM feed_dates d JOIN runs r ON r.id = d.run_id
+		WHERE r.started_at >= ?`
+	args := []any{formatTime(since)}
+	if len(sources) > 0 {
+		query += ` AND d.source IN (?` + strings.Repeat(", ?", len(sources)-1) + `)`
+		for _, src := range sources {
+			args = append(args, strings.TrimSpace(src))
+		}
+	}
+	query += `
+		GROUP BY d.source, d.feed
+		ORDER BY SUM(d.unparsed) + SUM(d.future) + SUM(d.bogus) DESC, d.source, d.feed`
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo la calidad de las fechas: %w", err)
+	}
+	defer rows.Close()
+	var out []FeedDates
+	for rows.Next() {
+		var f FeedDates
+		if err := rows.Scan(&f.Source, &f.Feed, &f.Items, &f.Unparsed, &f.Future, &f.Bogus, &f.Example, &f.Rounds); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+// SetDateIssue registra que la fecha de publicación del artículo se corrigió
+// (ver article.Article.DateIssue) y cuál era la original. Queda la de la
+// primera vez que se corrigió.
+func (s *Store) SetDateIssue(articleID int64, issue string, original time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT INTO article_dates (article_id, issue, original) VALUES (?, ?, ?)
+		ON CONFLICT(article_id) DO NOTHING`,
+		articleID, issue, formatTime(original))
+	if err != nil {
+		return fmt.Errorf("error guardando la fecha original del artículo %d: %w", articleID, err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"go-collector/article"
+)
+
+// Los vectores se guardan como BLOB (float32 little-endian), sin índice
+// vectorial: sqlite-vec es una extensión en C que el driver de SQLite en Go
+// puro no carga. La búsqueda de vecinos recorre los vectores en Go (ver
+// embed.Nearest y embed.NearDuplicates), lo que alcanza para corpus de
+// decenas de miles de artículos; más allá hace falta un índice externo.
+
+// SaveEmbedding guarda (o reemplaza) el vector de un artículo para un modelo.
+func (s *Store) SaveEmbedding(articleID int64, model string, vec []float32) error {
+	_, err := s.db.Exec(`
+		INSERT INTO embeddings (article_id, model, dim, vector) VALUES (?, ?, ?, ?)
+		ON CONFLICT(article_id, model) DO UPDATE SET dim = excluded.dim, vector = excluded.vector`,
+		articleID, model, len(vec), encodeVector(vec))
+	if err != nil {
+		return fmt.Errorf("error guardando embedding del artículo %d: %w", articleID, err)
+	}
+	return nil
+}
+
+// ArticlesWithoutEmbedding devuelve hasta limit artículos activos sin vector para el modelo.
+func (s *Store) ArticlesWithoutEmbedding(model string, limit int) ([]*article.Article, error) {
+	rows, err := s.db.Query(`SELECT `+articleColumns+` FROM articles
+		WHERE status = ? AND id NOT IN (SELECT article_id FROM embeddings WHERE model = ?)
+		ORDER BY id LIMIT ?`, article.StatusActive, model, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listando artículos sin embedding: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*article.Article
+	for rows.Next() {
+		a, err := s.scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// EachEmbedding recorre los vectores de un modelo sin cargarlos todos a la vez.
+func (s *Store) EachEmbedding(model string, fn func(articleID int64, vec []float32) error) error {
+	rows, err := s.db.Query(`SELECT article_id, vector FROM embeddings WHERE model = ? ORDER BY article_id`, model)
+	if err != nil {
+		return fmt.Errorf("error leyendo embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id   int64
+			blob []byte
+		)
+		if err := rows.Scan(&id, &blob); err != nil {
+			return err
+		}
+		if err := fn(id, decodeVector(blob)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func encodeVector(vec []float32) []byte {
+	buf := make([]byte, 4*len(vec))
+	for i, v := range vec {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+func decodeVector(buf []byte) []float32 {
+	vec := make([]float32, len(buf)/4)
+	for i := range vec {
+		vec[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return vec
+}
@@ -0,0 +1,132 @@
+package storage
+
+import "fmt"
+
+// FieldCipher cifra campos de texto (implementado por encrypt.Cipher).
+type FieldCipher interface {
+	EncryptString(string) (string, error)
+	DecryptString(string) (string, error)
+}
+
+// EncryptAuthors activa el cifrado del autor de los artículos de las fuentes
+// indicadas. Los autores se descifran al leer; sin la clave, un Store los
+// devuelve cifrados.
+func (s *Store) EncryptAuthors(c FieldCipher, sources []string) {
+	s.fields = c
+	s.authorSources = make(map[string]bool, len(sources))
+	for _, src := range sources {
+		s.authorSources[src] = true
+	}
+}
+
+func (s *Store) encryptAuthor(source, author string) (string, error) {
+	if s.fields == nil || !s.authorSources[source] {
+		return author, nil
+	}
+	enc, err := s.fields.EncryptString(author)
+	if err != nil {
+		return "", fmt.Errorf("error cifrando autor: %w", err)
+	}
+	return enc, nil
+}
+
+// EncryptExistingAuthors cifra los autores guardados antes de activar el
+// cifrado, los de los artículos y los de sus referencias (retweets, citas y
+// respuestas). Devuelve cuántas filas cambió.
+func (s *Store) EncryptExistingAuthors() (int, error) {
+	if s.fields == nil {
+		return 0, fmt.Errorf("el cifrado de autores no está activo")
+	}
+	type pending struct {
+		id     int64
+		author string
+	}
+	var todo []pending
+	for src := range s.authorSources {
+		rows, err := s.db.Query(`SELECT id, author FROM articles WHERE source = ? AND author != ''`, src)
+		if err != nil {
+			return 0, fmt.Errorf("error leyendo autores de %s: %w", src, err)
+		}
+		for rows.Next() {
+			var p pending
+			if err := rows.Scan(&p.id, &p.author); err != nil {
+				rows.Close()
+				return 0, err
+			}
+			todo = append(todo, p)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	changed := 0
+	for _, p := range todo {
+		enc, err := s.fields.EncryptString(p.author)
+		if err != nil {
+			return changed, fmt.Errorf("error cifrando autor: %w", err)
+		}
+		if enc == p.author {
+			continue
+		}
+		if _, err := s.db.Exec(`UPDATE articles SET author = ? WHERE id = ?`, enc, p.id); err != nil {
+			return changed, fmt.Errorf("error cifrando autor del artículo %d: %w", p.id, err)
+		}
+		changed++
+	}
+	refs, err := s.encryptExistingReferences()
+	return changed + refs, err
+}
+
+// encryptExistingReferences cifra los autores de las referencias de los
+// artículos de las fuentes con cifrado. Devuelve cuántas cambió.
+func (s *Store) encryptExistingReferences() (int, error) {
+	type pending struct {
+		tweetID, kind, refTweetID string
+		author, refAuthor         string
+	}
+	var todo []pending
+	for src := range s.authorSources {
+		rows, err := s.db.Query(`
+			SELECT r.tweet_id, r.kind, r.ref_tweet_id, r.author, r.ref_author
+			FROM tweet_references r JOIN articles a ON a.id = r.article_id
+			WHERE a.source = ? AND (r.author != '' OR r.ref_author != '')`, src)
+		if err != nil {
+			return 0, fmt.Errorf("error leyendo autores de referencias de %s: %w", src, err)
+		}
+		for rows.Next() {
+			var p pending
+			if err := rows.Scan(&p.tweetID, &p.kind, &p.refTweetID, &p.author, &p.refAuthor); err != nil {
+				rows.Close()
+				return 0, err
+			}
+			todo = append(todo, p)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	changed := 0
+	for _, p := range todo {
+		author, err := s.fields.EncryptString(p.author)
+		if err != nil {
+			return changed, fmt.Errorf("error cifrando autor: %w", err)
+		}
+		refAuthor, err := s.fields.EncryptString(p.refAuthor)
+		if err != nil {
+			return changed, fmt.Errorf("error cifrando autor: %w", err)
+		}
+		if author == p.author && refAuthor == p.refAuthor {
+			continue
+		}
+		if _, err := s.db.Exec(`UPDATE tweet_references SET author = ?, ref_author = ? WHERE tweet_id = ? AND kind = ? AND ref_tweet_id = ?`,
+			author, refAuthor, p.tweetID, p.kind, p.refTweetID); err != nil {
+			return changed, fmt.Errorf("error cifrando autores de la referencia del tweet %s: %w", p.tweetID, err)
+		}
+		changed++
+	}
+	return changed, nil
+}
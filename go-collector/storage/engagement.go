@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-collector/article"
+)
+
+// SetEngagement guarda (o reemplaza) la interacción del artículo observada
+// en observed: cada recolección que lo vuelve a traer la actualiza.
+func (s *Store) SetEngagement(articleID int64, e *article.Engagement, observed time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT INTO article_engagement (article_id, likes, reposts, replies, quotes, views, observed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(article_id) DO UPDATE SET
+			likes = excluded.likes, reposts = excluded.reposts, replies = excluded.replies,
+			quotes = excluded.quotes, views = excluded.views, observed_at = excluded.observed_at`,
+		articleID, e.Likes, e.Reposts, e.Replies, e.Quotes, e.Views, formatTime(observed))
+	if err != nil {
+		return fmt.Errorf("error guardando la interacción del artículo %d: %w", articleID, err)
+	}
+	return nil
+}
+
+// Engagement devuelve la última interacción observada del artículo y cuándo,
+// o ErrNotFound si la fuente no la informa.
+func (s *Store) Engagement(articleID int64) (*article.Engagement, time.Time, error) {
+	e := &article.Engagement{}
+	var observed string
+	err := s.db.QueryRow(`SELECT likes, reposts, replies, quotes, views, observed_at FROM article_engagement WHERE article_id = ?`, articleID).
+		Scan(&e.Likes, &e.Reposts, &e.Replies, &e.Quotes, &e.Views, &observed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, time.Time{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("error leyendo la interacción del artículo %d: %w", articleID, err)
+	}
+	return e, parseTime(observed), nil
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// ArticleEntity es una entidad (persona, organización, lugar) o palabra clave
+// de un artículo, con su peso en él (ver collector entities).
+type ArticleEntity struct {
+	ArticleID int64
+	Name      string
+	Kind      string
+	Score     float64
+}
+
+// SetEntities reemplaza las entidades del artículo.
+func (s *Store) SetEntities(articleID int64, entities []ArticleEntity) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error guardando entidades del artículo %d: %w", articleID, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM article_entities WHERE article_id = ?`, articleID); err != nil {
+		return fmt.Errorf("error guardando entidades del artículo %d: %w", articleID, err)
+	}
+	for _, e := range entities {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO article_entities (article_id, name, kind, score) VALUES (?, ?, ?, ?)`,
+			articleID, e.Name, e.Kind, e.Score); err != nil {
+			return fmt.Errorf("error guardando entidades del artículo %d: %w", articleID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Entities devuelve las entidades del artículo, de mayor a menor peso.
+func (s *Store) Entities(articleID int64) ([]ArticleEntity, error) {
+	rows, err := s.db.Query(`SELECT name, kind, score FROM article_entities WHERE article_id = ? ORDER BY score DESC, name`, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando entidades del artículo %d: %w", articleID, err)
+	}
+	defer rows.Close()
+
+	var out []ArticleEntity
+	for rows.Next() {
+		e := ArticleEntity{ArticleID: articleID}
+		if err := rows.Scan(&e.Name, &e.Kind, &e.Score); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// ArticlesWithEntities devuelve los IDs de los artículos ya analizados.
+func (s *Store) ArticlesWithEntities() (map[int64]bool, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT article_id FROM article_entities`)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando artículos analizados: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[int64]bool{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+// EntityTrend es una entidad con los artículos activos que la mencionan en
+// un período y en el anterior de igual duración.
+type EntityTrend struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Articles int    `json:"articles"`
+	Previous int    `json:"previous"`
+}
+
+// EntityTrends devuelve, por tipo, las limit entidades mencionadas en más
+// artículos publicados en los window anteriores a end, con su conteo del
+// período previo para ver cuáles suben.
+func (s *Store) EntityTrends(kinds []string, end time.Time, window time.Duration, limit int) (map[string][]EntityTrend, error) {
+	start, prev := end.Add(-window), end.Add(-2*window)
+	out := map[string][]EntityTrend{}
+	for _, kind := range kinds {
+		rows, err := s.db.Query(`
+			SELECT e.name,
+				COUNT(DISTINCT CASE WHEN a.published > ? THEN a.id END) AS recent,
+				COUNT(DISTINCT CASE WHEN a.published <= ? THEN a.id END) AS previous
+			FROM article_entities e JOIN articles a ON a.id = e.article_id
+			WHERE e.kind = ? AND a.status = 'active' AND a.published > ? AND a.published <= ?
+			GROUP BY e.name HAVING recent > 0
+			ORDER BY recent DESC, previous, e.name
+			LIMIT ?`,
+			formatTime(start), formatTime(start), kind, formatTime(prev), formatTime(end), limit)
+		if err != nil {
+			return nil, fmt.Errorf("error consultando tendencias de entidades: %w", err)
+		}
+		for rows.Next() {
+			t := EntityTrend{Kind: kind}
+			if err := rows.Scan(&t.Name, &t.Articles, &t.Previous); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			out[kind] = append(out[kind], t)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
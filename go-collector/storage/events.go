@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-collector/article"
+)
+
+// SetEvent guarda el evento al que pertenece el artículo, observado en
+// observed. Un evento que llega solo con su URI (sus datos no se pudieron
+// pedir) no borra el título, el resumen ni la fecha ya guardados.
+func (s *Store) SetEvent(articleID int64, e *article.Event, observed time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT INTO article_events (article_id, event_uri, title, summary, event_date, articles, observed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(article_id) DO UPDATE SET
+			event_uri = excluded.event_uri,
+			title = CASE WHEN excluded.title != '' THEN excluded.title ELSE article_events.title END,
+			summary = CASE WHEN excluded.summary != '' THEN excluded.summary ELSE article_events.summary END,
+			event_date = CASE WHEN excluded.event_date != '' THEN excluded.event_date ELSE article_events.event_date END,
+			articles = MAX(excluded.articles, article_events.articles),
+			observed_at = excluded.observed_at`,
+		articleID, e.URI, e.Title, e.Summary, e.Date, e.Articles, formatTime(observed))
+	if err != nil {
+		return fmt.Errorf("error guardando el evento del artículo %d: %w", articleID, err)
+	}
+	return nil
+}
+
+// EventCoverage es un evento con la cobertura que llegó al corpus: Stored
+// artículos activos de Domains medios, en Languages, publicados entre First y
+// Last. Event.Articles es el total del evento en la fuente.
+type EventCoverage struct {
+	article.Event
+	Stored    int       `json:"stored"`
+	Domains   int       `json:"domains"`
+	Languages []string  `json:"languages"`
+	First     time.Time `json:"first"`
+	Last      time.Time `json:"last"`
+}
+
+// EventFilter restringe la consulta de eventos a los artículos publicados en
+// [Since, Until] (límites en cero no restringen) y, con URI, a ese evento.
+// MinStored descarta los eventos con menos artículos en el corpus.
+type EventFilter struct {
+	URI       string
+	Since     time.Time
+	Until     time.Time
+	MinStored int
+	Limit     int
+}
+
+// Events devuelve los eventos con artículos en el corpus, los de más
+// cobertura primero. Los datos de cada evento son los de la observación más
+// reciente que los trajo.
+func (s *Store) Events(f EventFilter) ([]EventCoverage, error) {
+	where := []string{"a.status = 'active'"}
+	var args []any
+	if f.URI != "" {
+		where, args = append(where, "e.event_uri = ?"), append(args, f.URI)
+	}
+	if !f.Since.IsZero() {
+		where, args = append(where, "a.published >= ?"), append(args, formatTime(f.Since))
+	}
+	if !f.Until.IsZero() {
+		where, args = append(where, "a.published <= ?"), append(args, formatTime(f.Until))
+	}
+	query := `
+		SELECT g.event_uri, i.title, i.summary, i.event_date, i.articles,
+			g.stored, g.domains, COALESCE(g.languages, ''), COALESCE(g.first, ''), COALESCE(g.last, '')
+		FROM (
+			SELECT e.event_uri, COUNT(*) AS stored, COUNT(DISTINCT a.domain) AS domains,
+				GROUP_CONCAT(DISTINCT NULLIF(a.language, '')) AS languages,
+				MIN(NULLIF(a.published, '')) AS first, MAX(NULLIF(a.published, '')) AS last
+			FROM article_events e JOIN articles a ON a.id = e.article_id
+			WHERE ` + strings.Join(where, " AND ") + `
+			GROUP BY e.event_uri
+			HAVING COUNT(*) >= ?
+		) g
+		JOIN article_events i ON i.article_id = (
+			SELECT article_id FROM article_events WHERE event_uri = g.event_uri
+			ORDER BY title != '' DESC, observed_at DESC LIMIT 1)
+		ORDER BY g.stored DESC, g.event_uri`
+	args = append(args, max(f.MinStored, 1))
+	if f.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, f.Limit)
+	}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando eventos: %w", err)
+	}
+	defer rows.Close()
+
+	var out []EventCoverage
+	for rows.Next() {
+		var e EventCoverage
+		var languages, first, last string
+		if err := rows.Scan(&e.URI, &e.Title, &e.Summary, &e.Date, &e.Articles,
+			&e.Stored, &e.Domains, &languages, &first, &last); err != nil {
+			return nil, fmt.Errorf("error leyendo evento: %w", err)
+		}
+		if languages != "" {
+			e.Languages = strings.Split(languages, ",")
+		}
+		e.First, e.Last = parseTime(first), parseTime(last)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// EventArticles devuelve los artículos activos de un evento, del primero
+// publicado al último.
+func (s *Store) EventArticles(uri string) ([]*article.Article, error) {
+	rows, err := s.db.Query(`SELECT `+articleColumns+` FROM articles
+		WHERE status = 'active' AND id IN (SELECT article_id FROM article_events WHERE event_uri = ?) ORDER BY published, id`, uri)
+	if err != nil {
+		return nil, fmt.Errorf("error listando artículos del evento %s: %w", uri, err)
+	}
+	defer rows.Close()
+
+	var out []*article.Article
+	for rows.Next() {
+		a, err := s.scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
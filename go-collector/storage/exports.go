@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecordExportRun registra una ejecución de una exportación programada;
+// detail lleva el archivo subido o el error.
+func (s *Store) RecordExportRun(name string, at time.Time, status, detail string) error {
+	_, err := s.db.Exec(`INSERT INTO export_runs (name, ran_at, status, detail) VALUES (?, ?, ?, ?)`,
+		name, formatTime(at), status, detail)
+	if err != nil {
+		return fmt.Errorf("error registrando ejecución de la exportación %s: %w", name, err)
+	}
+	return nil
+}
+
+// LastExportRun devuelve la última ejecución exitosa de la exportación (cero
+// si nunca corrió).
+func (s *Store) LastExportRun(name string) (time.Time, error) {
+	var last string
+	err := s.db.QueryRow(`SELECT COALESCE(MAX(ran_at), '') FROM export_runs WHERE name = ? AND status = 'ok'`, name).Scan(&last)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error leyendo última ejecución de la exportación %s: %w", name, err)
+	}
+	return parseTime(last), nil
+}
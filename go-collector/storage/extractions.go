@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-collector/extract"
+)
+
+// Extraction registra cómo se extrajo el texto completo de un artículo: la
+// estrategia que ganó, su evaluación y todas las que se probaron (JSON), para
+// depurar los medios donde la extracción sale mal.
+type Extraction struct {
+	ArticleID   int64
+	Strategy    string
+	Score       float64
+	Length      int
+	Boilerplate float64
+	HasDate     bool
+	HasAuthor   bool
+	Attempts    string
+	Extracted   time.Time
+}
+
+// NewExtraction arma el registro de la extracción res del artículo.
+func NewExtraction(articleID int64, res *extract.Result, at time.Time) (Extraction, error) {
+	attempts, err := json.Marshal(res.Attempts)
+	if err != nil {
+		return Extraction{}, err
+	}
+	q := res.Quality
+	return Extraction{
+		ArticleID: articleID, Strategy: res.Strategy, Score: q.Score, Length: q.Length,
+		Boilerplate: q.Boilerplate, HasDate: q.HasDate, HasAuthor: q.HasAuthor,
+		Attempts: string(attempts), Extracted: at,
+	}, nil
+}
+
+// SaveExtraction guarda (o reemplaza) la extracción del artículo.
+func (s *Store) SaveExtraction(e Extraction) error {
+	_, err := s.db.Exec(`
+		INSERT INTO extractions (article_id, strategy, score, length, boilerplate, has_date, has_author, attempts, extracted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(article_id) DO UPDATE SET
+			strategy = excluded.strategy, score = excluded.score, length = excluded.length,
+			boilerplate = excluded.boilerplate, has_date = excluded.has_date, has_author = excluded.has_author,
+			attempts = excluded.attempts, extracted_at = excluded.extracted_at`,
+		e.ArticleID, e.Strategy, e.Score, e.Length, e.Boilerplate, e.HasDate, e.HasAuthor, e.Attempts, formatTime(e.Extracted))
+	if err != nil {
+		return fmt.Errorf("error guardando la extracción del artículo %d: %w", e.ArticleID, err)
+	}
+	return nil
+}
+
+// GetExtraction devuelve la extracción del artículo, o ErrNotFound si no se
+// extrajo.
+func (s *Store) GetExtraction(articleID int64) (*Extraction, error) {
+	e := &Extraction{ArticleID: articleID}
+	var extracted string
+	err := s.db.QueryRow(`
+		SELECT strategy, score, length, boilerplate, has_date, has_author, attempts, extracted_at
+		FROM extractions WHERE article_id = ?`, articleID,
+	).Scan(&e.Strategy, &e.Score, &e.Length, &e.Boilerplate, &e.HasDate, &e.HasAuthor, &e.Attempts, &extracted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo la extracción del artículo %d: %w", articleID, err)
+	}
+	e.Extracted = parseTime(extracted)
+	return e, nil
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RunFailure es una consulta que falló en una ronda, con lo necesario para
+// repetirla (collector retry-failures): la consulta, los idiomas y el rango
+// efectivos de la ronda.
+type RunFailure struct {
+	RunID  int64
+	Source string
+	// Item es la parte de la fuente que falló (la URL de un feed RSS); vacío
+	// si falló la consulta completa.
+	Item      string
+	Query     string
+	Languages []string
+	From      time.Time // cero: el rango por defecto de la fuente
+	To        time.Time
+	Err       string
+	// RetriedBy es la ronda que la reintentó con éxito; cero si sigue
+	// pendiente.
+	RetriedBy int64
+}
+
+// AddRunFailure registra una falla de la ronda f.RunID.
+func (s *Store) AddRunFailure(f RunFailure) error {
+	_, err := s.db.Exec(`
+		INSERT INTO run_failures (run_id, source, item, query, languages, from_at, to_at, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(run_id, source, item) DO UPDATE SET error = excluded.error`,
+		f.RunID, f.Source, f.Item, f.Query, strings.Join(f.Languages, ","), formatTime(f.From), formatTime(f.To), f.Err)
+	if err != nil {
+		return fmt.Errorf("error registrando la falla de %s en la ronda %d: %w", f.Source, f.RunID, err)
+	}
+	return nil
+}
+
+// RunFailures devuelve las fallas de la ronda, por fuente.
+func (s *Store) RunFailures(runID int64) ([]RunFailure, error) {
+	rows, err := s.db.Query(`
+		SELECT source, item, query, languages, from_at, to_at, error, retried_by
+		FROM run_failures WHERE run_id = ? ORDER BY source, item`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando las fallas de la ronda %d: %w", runID, err)
+	}
+	defer rows.Close()
+
+	var out []RunFailure
+	for rows.Next() {
+		f := RunFailure{RunID: runID}
+		var languages, from, to string
+		if err := rows.Scan(&f.Source, &f.Item, &f.Query, &languages, &from, &to, &f.Err, &f.RetriedBy); err != nil {
+			return nil, err
+		}
+		if languages != "" {
+			f.Languages = strings.Split(languages, ",")
+		}
+		f.From, f.To = parseTime(from), parseTime(to)
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+// ResolveFailures marca como reintentadas por la ronda by las fallas
+// pendientes de source en la ronda runID, salvo los ítems de still, que
+// volvieron a fallar.
+func (s *Store) ResolveFailures(runID int64, source string, still []string, by int64) error {
+	query := `UPDATE run_failures SET retried_by = ? WHERE run_id = ? AND source = ? AND retried_by = 0`
+	args := []any{by, runID, source}
+	if len(still) > 0 {
+		query += ` AND item NOT IN (?` + strings.Repeat(", ?", len(still)-1) + `)`
+		for _, item := range still {
+			args = append(args, item)
+		}
+	}
+	if _, err := s.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("error actualizando las fallas de la ronda %d: %w", runID, err)
+	}
+	return nil
+}
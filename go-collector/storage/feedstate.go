@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FeedState es lo que se recuerda de un feed RSS entre rondas: los
+// validadores HTTP para pedirlo condicionalmente y la entrada más reciente
+// que trajo, para procesar solo las posteriores. Con frecuencia adaptativa
+// (sources.rss.adaptive), también su ritmo de entradas nuevas y cuándo toca
+// volver a leerlo.
+type FeedState struct {
+	URL          string
+	ETag         string
+	LastModified string    // tal como lo envió el servidor
+	Newest       time.Time // publicación más reciente vista
+	Polled       time.Time
+	// YieldItems y YieldHours son las entradas nuevas y las horas entre
+	// lecturas, acumuladas con decaimiento de una semana de vida media: su cociente
+	// es el ritmo reciente del feed.
+	YieldItems float64
+	YieldHours float64
+	// Next es cuándo toca leerlo de nuevo; cero, en la próxima ronda.
+	Next time.Time
+}
+
+// GetFeedState devuelve el estado del feed, o ErrNotFound si nunca se leyó.
+func (s *Store) GetFeedState(url string) (*FeedState, error) {
+	f := &FeedState{URL: url}
+	var newest, polled, next string
+	err := s.db.QueryRow(`
+		SELECT etag, last_modified, newest, polled_at, yield_items, yield_hours, next_poll FROM feed_states WHERE url = ?`, url,
+	).Scan(&f.ETag, &f.LastModified, &newest, &polled, &f.YieldItems, &f.YieldHours, &next)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo el estado del feed %s: %w", url, err)
+	}
+	f.Newest, f.Polled, f.Next = parseTime(newest), parseTime(polled), parseTime(next)
+	return f, nil
+}
+
+// SaveFeedState guarda (o reemplaza) el estado de un feed.
+func (s *Store) SaveFeedState(f *FeedState) error {
+	_, err := s.db.Exec(`
+		INSERT INTO feed_states (url, etag, last_modified, newest, polled_at, yield_items, yield_hours, next_poll)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(url) DO UPDATE SET
+			etag = excluded.etag,
+			last_modified = excluded.last_modified,
+			newest = excluded.newest,
+			polled_at = excluded.polled_at,
+			yield_items = excluded.yield_items,
+			yield_hours = excluded.yield_hours,
+			next_poll = excluded.next_poll`,
+		f.URL, f.ETag, f.LastModified, formatTime(f.Newest), formatTime(f.Polled), f.YieldItems, f.YieldHours, formatTime(f.Next))
+	if err != nil {
+		return fmt.Errorf("error guardando el estado del feed %s: %w", f.URL, err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PageFetch es una descarga registrada de la página de un artículo.
+type PageFetch struct {
+	ArticleID   int64
+	FetchedAt   time.Time
+	ContentHash string
+	Changed     bool
+}
+
+// DomainUpdateStats resume cuántas revisitas a un dominio encontraron cambios.
+type DomainUpdateStats struct {
+	Domain  string
+	Fetches int
+	Changes int
+}
+
+// ChangeRate es la fracción de revisitas que encontraron el contenido modificado.
+func (d DomainUpdateStats) ChangeRate() float64 {
+	if d.Fetches == 0 {
+		return 0
+	}
+	return float64(d.Changes) / float64(d.Fetches)
+}
+
+// RecordFetch registra una descarga de la página y devuelve si el contenido
+// cambió respecto a la descarga anterior. La primera descarga nunca cuenta como cambio.
+func (s *Store) RecordFetch(articleID int64, contentHash string, at time.Time) (bool, error) {
+	last, err := s.LastFetch(articleID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return false, err
+	}
+	changed := last != nil && last.ContentHash != contentHash
+
+	_, err = s.db.Exec(`INSERT INTO page_fetches (article_id, fetched_at, content_hash, changed) VALUES (?, ?, ?, ?)`,
+		articleID, formatTime(at), contentHash, changed)
+	if err != nil {
+		return false, fmt.Errorf("error registrando descarga del artículo %d: %w", articleID, err)
+	}
+	return changed, nil
+}
+
+// LastFetch devuelve la descarga más reciente del artículo.
+func (s *Store) LastFetch(articleID int64) (*PageFetch, error) {
+	var (
+		f         PageFetch
+		fetchedAt string
+	)
+	err := s.db.QueryRow(`
+		SELECT article_id, fetched_at, content_hash, changed FROM page_fetches
+		WHERE article_id = ? ORDER BY fetched_at DESC LIMIT 1`, articleID,
+	).Scan(&f.ArticleID, &fetchedAt, &f.ContentHash, &f.Changed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	f.FetchedAt = parseTime(fetchedAt)
+	return &f, nil
+}
+
+// DomainUpdateStats calcula, por dominio, cuántas revisitas detectaron cambios.
+// Solo cuenta revisitas (no la primera descarga de cada artículo).
+func (s *Store) DomainUpdateStats() (map[string]DomainUpdateStats, error) {
+	rows, err := s.db.Query(`
+		SELECT a.domain, COUNT(*), SUM(f.changed)
+		FROM page_fetches f JOIN articles a ON a.id = f.article_id
+		WHERE f.fetched_at > (SELECT MIN(fetched_at) FROM page_fetches WHERE article_id = f.article_id)
+		GROUP BY a.domain`)
+	if err != nil {
+		return nil, fmt.Errorf("error calculando frecuencia de actualización: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make(map[string]DomainUpdateStats)
+	for rows.Next() {
+		var d DomainUpdateStats
+		if err := rows.Scan(&d.Domain, &d.Fetches, &d.Changes); err != nil {
+			return nil, err
+		}
+		stats[d.Domain] = d
+	}
+	return stats, rows.Err()
+}
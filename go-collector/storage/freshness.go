@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Sighting es la primera vez que una fuente entregó un artículo, junto a su
+// fecha de publicación.
+type Sighting struct {
+	ArticleID int64
+	Source    string
+	Published time.Time
+	Seen      time.Time
+}
+
+// Sightings devuelve, por artículo activo con fecha de publicación en
+// [from, to] (límites en cero no restringen) y por fuente que lo trajo, el
+// momento más temprano en que esa fuente lo entregó: su recolección, una
+// procedencia registrada al deduplicar o el inicio de una ronda que lo trajo.
+// Se omiten los artículos con la fecha corregida. sources limita las fuentes (todas si no hay).
+func (s *Store) Sightings(from, to time.Time, sources []string) ([]Sighting, error) {
+	// Las fechas corregidas (article_dates) son la de descarga: no miden
+	// ninguna demora.
+	where := []string{"a.status = 'active'", "a.published != ''", "v.seen != ''",
+		"a.id NOT IN (SELECT article_id FROM article_dates)"}
+	var args []any
+	if !from.IsZero() {
+		where, args = append(where, "a.published >= ?"), append(args, formatTime(from))
+	}
+	if !to.IsZero() {
+		where, args = append(where, "a.published <= ?"), append(args, formatTime(to))
+	}
+	if len(sources) > 0 {
+		where = append(where, "v.source IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(sources)), ", ")+")")
+		for _, src := range sources {
+			args = append(args, src)
+		}
+	}
+	rows, err := s.db.Query(`
+		SELECT v.article_id, v.source, a.published, MIN(v.seen)
+		FROM (
+			SELECT id AS article_id, source, collected AS seen FROM articles
+			UNION ALL
+			SELECT article_id, source, seen_at FROM article_sources
+			UNION ALL
+			SELECT l.article_id, l.source, r.started_at FROM article_runs l JOIN runs r ON r.id = l.run_id
+		) v JOIN articles a ON a.id = v.article_id
+		WHERE `+strings.Join(where, " AND ")+`
+		GROUP BY v.article_id, v.source
+		ORDER BY v.source, v.article_id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando cuándo llegó cada artículo: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Sighting
+	for rows.Next() {
+		var v Sighting
+		var published, seen string
+		if err := rows.Scan(&v.ArticleID, &v.Source, &published, &seen); err != nil {
+			return nil, err
+		}
+		v.Published, v.Seen = parseTime(published), parseTime(seen)
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SourceHold es la desactivación temporal de una fuente (mantenimiento de la
+// API, credencial revocada...) sin tocar la configuración: mientras esté
+// vigente las rondas la omiten y lo registran. Until cero es sin plazo, hasta
+// reactivarla.
+type SourceHold struct {
+	Source string
+	Reason string
+	Actor  string
+	Since  time.Time
+	Until  time.Time
+}
+
+// Active indica si la desactivación sigue vigente en now.
+func (h *SourceHold) Active(now time.Time) bool {
+	return h.Until.IsZero() || now.Before(h.Until)
+}
+
+// Describe es el motivo con el plazo, tal como queda en el informe de la
+// ronda.
+func (h *SourceHold) Describe() string {
+	s := "desactivada"
+	if h.Reason != "" {
+		s += ": " + h.Reason
+	}
+	if !h.Until.IsZero() {
+		s += " (hasta " + h.Until.Local().Format("2006-01-02 15:04") + ")"
+	}
+	return s
+}
+
+// HoldSource desactiva una fuente, o reemplaza el motivo y el plazo si ya lo
+// estaba.
+func (s *Store) HoldSource(h SourceHold) error {
+	if h.Since.IsZero() {
+		h.Since = time.Now()
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO source_holds (source, reason, actor, held_at, held_until) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(source) DO UPDATE SET
+			reason = excluded.reason,
+			actor = excluded.actor,
+			held_at = excluded.held_at,
+			held_until = excluded.held_until`,
+		h.Source, h.Reason, h.Actor, formatTime(h.Since), formatTime(h.Until))
+	if err != nil {
+		return fmt.Errorf("error desactivando la fuente %s: %w", h.Source, err)
+	}
+	return nil
+}
+
+// ReleaseSource reactiva una fuente; ErrNotFound si no estaba desactivada.
+func (s *Store) ReleaseSource(source string) error {
+	res, err := s.db.Exec(`DELETE FROM source_holds WHERE source = ?`, source)
+	if err != nil {
+		return fmt.Errorf("error reactivando la fuente %s: %w", source, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SourceHold devuelve la desactivación vigente de la fuente en now, o
+// ErrNotFound si está activa. Las vencidas no cuentan, aunque sigan en la
+// tabla hasta la próxima desactivación o reactivación.
+func (s *Store) SourceHold(source string, now time.Time) (*SourceHold, error) {
+	h := &SourceHold{Source: source}
+	var since, until string
+	err := s.db.QueryRow(`
+		SELECT reason, actor, held_at, held_until FROM source_holds WHERE source = ?`, source,
+	).Scan(&h.Reason, &h.Actor, &since, &until)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo la desactivación de %s: %w", source, err)
+	}
+	h.Since, h.Until = parseTime(since), parseTime(until)
+	if !h.Active(now) {
+		return nil, ErrNotFound
+	}
+	return h, nil
+}
+
+// SourceHolds devuelve las desactivaciones vigentes en now, por fuente.
+func (s *Store) SourceHolds(now time.Time) ([]SourceHold, error) {
+	rows, err := s.db.Query(`
+		SELECT source, reason, actor, held_at, held_until FROM source_holds ORDER BY source`)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando fuentes desactivadas: %w", err)
+	}
+	defer rows.Close()
+
+	var out []SourceHold
+	for rows.Next() {
+		var h SourceHold
+		var since, until string
+		if err := rows.Scan(&h.Source, &h.Reason, &h.Actor, &since, &until); err != nil {
+			return nil, fmt.Errorf("error leyendo fuente desactivada: %w", err)
+		}
+		h.Since, h.Until = parseTime(since), parseTime(until)
+		if h.Active(now) {
+			out = append(out, h)
+		}
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"go-collector/article"
+)
+
+// LabeledArticle es un artículo con su etiqueta manual (para experimentos de ML).
+type LabeledArticle struct {
+	Article   *article.Article
+	Label     string
+	Annotator string
+}
+
+// SetLabel asigna (o reemplaza) la etiqueta de un artículo.
+func (s *Store) SetLabel(articleID int64, label, annotator string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO labels (article_id, label, annotator, labeled_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(article_id) DO UPDATE SET
+			label = excluded.label, annotator = excluded.annotator, labeled_at = excluded.labeled_at`,
+		articleID, label, annotator, formatTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("error etiquetando artículo %d: %w", articleID, err)
+	}
+	return nil
+}
+
+// LabeledArticles devuelve todos los artículos etiquetados, ordenados por ID.
+func (s *Store) LabeledArticles() ([]LabeledArticle, error) {
+	rows, err := s.db.Query(`
+		SELECT l.label, l.annotator, ` + prefixed("a", articleColumns) + `
+		FROM labels l JOIN articles a ON a.id = l.article_id
+		ORDER BY a.id`)
+	if err != nil {
+		return nil, fmt.Errorf("error listando artículos etiquetados: %w", err)
+	}
+	defer rows.Close()
+
+	var out []LabeledArticle
+	for rows.Next() {
+		var la LabeledArticle
+		a, err := s.scanArticle(prefixScanner{rows, []any{&la.Label, &la.Annotator}})
+		if err != nil {
+			return nil, err
+		}
+		la.Article = a
+		out = append(out, la)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArticleTables son las tablas con datos propios de cada artículo que
+// CopyArticleData sabe copiar entre corpus.
+var ArticleTables = []string{"labels", "page_fetches", "raw_payloads", "embeddings", "article_sources", "attachments",
+	"related_media", "article_entities", "extractions",
+	"article_sentiment", "article_engagement", "article_events", "archive_checks",
+	"warc_records", "article_annotations", "article_geo", "tweet_references", "article_dates"}
+
+// CopyArticleData copia a dst las filas de tables (ver ArticleTables) del
+// artículo fromID de este corpus, como filas del artículo toID de dst. Las
+// filas que chocan con una existente (ej: una etiqueta ya puesta en dst) no
+// se copian: devuelve, por tabla, cuántas se copiaron y cuántas se omitieron.
+func (s *Store) CopyArticleData(dst *Store, fromID, toID int64, tables ...string) (copied, skipped map[string]int, err error) {
+	copied, skipped = make(map[string]int), make(map[string]int)
+	for _, table := range tables {
+		columns, err := s.tableColumns(table)
+		if err != nil {
+			return copied, skipped, err
+		}
+		rows, err := s.queryRows(`SELECT `+strings.Join(columns, ", ")+` FROM `+table+` WHERE article_id = ? ORDER BY rowid`, len(columns), fromID)
+		if err != nil {
+			return copied, skipped, fmt.Errorf("error leyendo %s del artículo %d: %w", table, fromID, err)
+		}
+		insert := `INSERT OR IGNORE INTO ` + table + ` (` + strings.Join(columns, ", ") + `) VALUES (?` + strings.Repeat(", ?", len(columns)-1) + `)`
+		for _, row := range rows {
+			for i, col := range columns {
+				if col == "article_id" {
+					row[i] = toID
+				}
+			}
+			res, err := dst.db.Exec(insert, row...)
+			if err != nil {
+				return copied, skipped, fmt.Errorf("error copiando %s del artículo %d: %w", table, fromID, err)
+			}
+			if n, _ := res.RowsAffected(); n > 0 {
+				copied[table]++
+			} else {
+				skipped[table]++
+			}
+		}
+	}
+	return copied, skipped, nil
+}
+
+// CopyRuns copia a dst el historial de rondas de este corpus (rondas, fuentes,
+// calidad de fechas, fallas y linaje) con IDs nuevos y la campaña cambiada a campaign. ids traduce los
+// IDs de artículos de este corpus a los de dst; el linaje de artículos que no
+// están en ids no se copia. Devuelve cuántas rondas se copiaron.
+func (s *Store) CopyRuns(dst *Store, campaign string, ids map[int64]int64) (int, error) {
+	runs, err := s.queryRows(`SELECT id, started_at, finished_at, config_hash, status, error, retry_of, version, vcs_commit FROM runs ORDER BY id`, 9)
+	if err != nil {
+		return 0, fmt.Errorf("error leyendo rondas: %w", err)
+	}
+	// runIDs traduce los IDs de rondas, para los reintentos (que siempre son
+	// posteriores a la ronda que reintentan).
+	runIDs := make(map[int64]int64, len(runs))
+	for _, r := range runs {
+		res, err := dst.db.Exec(`
+			INSERT INTO runs (started_at, finished_at, campaign, config_hash, status, error, retry_of, version, vcs_commit)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, r[1], r[2], campaign, r[3], r[4], r[5], runIDs[r[6].(int64)], r[7], r[8])
+		if err != nil {
+			return 0, fmt.Errorf("error copiando ronda %v: %w", r[0], err)
+		}
+		runID, err := res.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
+		runIDs[r[0].(int64)] = runID
+
+		sources, err := s.queryRows(`
+			SELECT source, fetched, stored, duplicates, partial, error, bad_dates, skipped FROM run_sources
+			WHERE run_id = ? ORDER BY rowid`, 8, r[0])
+		if err != nil {
+			return 0, fmt.Errorf("error leyendo la ronda %v: %w", r[0], err)
+		}
+		for _, src := range sources {
+			_, err := dst.db.Exec(`
+				INSERT INTO run_sources (run_id, source, fetched, stored, duplicates, partial, error, bad_dates, skipped)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, append([]any{runID}, src...)...)
+			if err != nil {
+				return 0, fmt.Errorf("error copiando la ronda %v: %w", r[0], err)
+			}
+		}
+
+		feeds, err := s.queryRows(`
+			SELECT source, feed, items, unparsed, future, bogus, example FROM feed_dates
+			WHERE run_id = ?`, 7, r[0])
+		if err != nil {
+			return 0, fmt.Errorf("error leyendo la ronda %v: %w", r[0], err)
+		}
+		for _, f := range feeds {
+			_, err := dst.db.Exec(`
+				INSERT INTO feed_dates (run_id, source, feed, items, unparsed, future, bogus, example)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, append([]any{runID}, f...)...)
+			if err != nil {
+				return 0, fmt.Errorf("error copiando la ronda %v: %w", r[0], err)
+			}
+		}
+
+		requests, err := s.queryRows(`SELECT source, requests FROM run_requests WHERE run_id = ?`, 2, r[0])
+		if err != nil {
+			return 0, fmt.Errorf("error leyendo la ronda %v: %w", r[0], err)
+		}
+		for _, req := range requests {
+			if err := dst.AddRunRequests(runID, req[0].(string), int(req[1].(int64))); err != nil {
+				return 0, err
+			}
+		}
+
+		lineage, err := s.queryRows(`SELECT article_id, source, url, request FROM article_runs WHERE run_id = ?`, 4, r[0])
+		if err != nil {
+			return 0, fmt.Errorf("error leyendo linaje de la ronda %v: %w", r[0], err)
+		}
+		for _, l := range lineage {
+			id, ok := ids[l[0].(int64)]
+			if !ok {
+				continue
+			}
+			if err := dst.AddLineage(runID, id, l[1].(string), l[2].(string), l[3].(string)); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	failures, err := s.queryRows(`
+		SELECT run_id, source, item, query, languages, from_at, to_at, error, retried_by
+		FROM run_failures ORDER BY run_id`, 9)
+	if err != nil {
+		return 0, fmt.Errorf("error leyendo fallas de rondas: %w", err)
+	}
+	for _, f := range failures {
+		f[0], f[8] = runIDs[f[0].(int64)], runIDs[f[8].(int64)]
+		_, err := dst.db.Exec(`
+			INSERT INTO run_failures (run_id, source, item, query, languages, from_at, to_at, error, retried_by)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, f...)
+		if err != nil {
+			return 0, fmt.Errorf("error copiando fallas de rondas: %w", err)
+		}
+	}
+	return len(runs), nil
+}
+
+// queryRows lee todas las filas de una consulta de n columnas. Se leen
+// completas antes de escribir en otro corpus: este tiene una sola conexión.
+func (s *Store) queryRows(query string, n int, args ...any) ([][]any, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out [][]any
+	for rows.Next() {
+		vals := make([]any, n)
+		ptrs := make([]any, n)
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		out = append(out, vals)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// NotificationFailure es un mensaje a un webhook que no llegó después de
+// todos sus reintentos (ver el paquete notify): queda con su cuerpo tal cual
+// para reenviarlo (collector notifications resend). La URL no se guarda,
+// porque suele llevar el secreto: se toma de la notificación configurada.
+type NotificationFailure struct {
+	ID           int64
+	Notification string
+	Payload      []byte
+	Articles     int
+	Attempts     int
+	Err          string
+	Failed       time.Time
+	// Resent es cuándo se reenvió con éxito; cero si sigue pendiente.
+	Resent time.Time
+}
+
+// AddNotificationFailure registra un mensaje que no llegó.
+func (s *Store) AddNotificationFailure(f *NotificationFailure) error {
+	res, err := s.db.Exec(`
+		INSERT INTO notification_failures (notification, payload, articles, attempts, error, failed_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		f.Notification, string(f.Payload), f.Articles, f.Attempts, f.Err, formatTime(f.Failed))
+	if err != nil {
+		return fmt.Errorf("error registrando la notificación fallida %s: %w", f.Notification, err)
+	}
+	f.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// NotificationFailures devuelve los mensajes que no llegaron, del más viejo
+// al más nuevo; con all, también los ya reenviados.
+func (s *Store) NotificationFailures(all bool) ([]NotificationFailure, error) {
+	q := `SELECT id, notification, payload, articles, attempts, error, failed_at, resent_at FROM notification_failures`
+	if !all {
+		q += ` WHERE resent_at = ''`
+	}
+	rows, err := s.db.Query(q + ` ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando notificaciones fallidas: %w", err)
+	}
+	defer rows.Close()
+	var out []NotificationFailure
+	for rows.Next() {
+		var f NotificationFailure
+		var payload, failed, resent string
+		if err := rows.Scan(&f.ID, &f.Notification, &payload, &f.Articles, &f.Attempts, &f.Err, &failed, &resent); err != nil {
+			return nil, err
+		}
+		f.Payload, f.Failed, f.Resent = []byte(payload), parseTime(failed), parseTime(resent)
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+// ResolveNotificationFailure marca el mensaje id como reenviado en at.
+func (s *Store) ResolveNotificationFailure(id int64, at time.Time) error {
+	if _, err := s.db.Exec(`UPDATE notification_failures SET resent_at = ? WHERE id = ?`, formatTime(at), id); err != nil {
+		return fmt.Errorf("error actualizando la notificación fallida %d: %w", id, err)
+	}
+	return nil
+}
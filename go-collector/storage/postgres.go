@@ -0,0 +1,653 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"go-collector/article"
+)
+
+// pgSchema es el esquema equivalente en Postgres para migrar un corpus local
+// al servidor compartido. Las fechas siguen como texto en el mismo formato,
+// así las consultas del corpus funcionan igual en ambos motores. raw_payloads
+// lleva además el contenido de la página, que en SQLite está en el archivo crudo.
+var pgSchema = []string{
+	`CREATE TABLE IF NOT EXISTS articles (
+		id               BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+		source           TEXT NOT NULL,
+		url              TEXT NOT NULL UNIQUE,
+		title            TEXT NOT NULL DEFAULT '',
+		author           TEXT NOT NULL DEFAULT '',
+		domain           TEXT NOT NULL DEFAULT '',
+		language         TEXT NOT NULL DEFAULT '',
+		section          TEXT NOT NULL DEFAULT '',
+		summary          TEXT NOT NULL DEFAULT '',
+		body             TEXT NOT NULL DEFAULT '',
+		published        TEXT NOT NULL DEFAULT '',
+		collected        TEXT NOT NULL DEFAULT '',
+		status           TEXT NOT NULL DEFAULT 'active',
+		withdrawn_at     TEXT,
+		withdrawn_reason TEXT NOT NULL DEFAULT '',
+		extraction_issue TEXT NOT NULL DEFAULT '',
+		edition_group    BIGINT NOT NULL DEFAULT 0,
+		explanation      TEXT NOT NULL DEFAULT '',
+		title_translated TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_articles_status ON articles(status)`,
+	`CREATE INDEX IF NOT EXISTS idx_articles_published ON articles(published)`,
+	`CREATE TABLE IF NOT EXISTS page_fetches (
+		article_id   BIGINT NOT NULL REFERENCES articles(id),
+		fetched_at   TEXT NOT NULL,
+		content_hash TEXT NOT NULL,
+		changed      INTEGER NOT NULL DEFAULT 0
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_page_fetches_article ON page_fetches(article_id, fetched_at)`,
+	`CREATE TABLE IF NOT EXISTS domain_backoff (
+		domain          TEXT PRIMARY KEY,
+		last_status     INTEGER NOT NULL DEFAULT 0,
+		last_blocked_at TEXT NOT NULL DEFAULT '',
+		cooldown_until  TEXT NOT NULL DEFAULT '',
+		strikes         INTEGER NOT NULL DEFAULT 0,
+		total_blocks    INTEGER NOT NULL DEFAULT 0
+	)`,
+	`CREATE TABLE IF NOT EXISTS labels (
+		article_id BIGINT PRIMARY KEY REFERENCES articles(id),
+		label      TEXT NOT NULL,
+		annotator  TEXT NOT NULL DEFAULT '',
+		labeled_at TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS embeddings (
+		article_id BIGINT NOT NULL REFERENCES articles(id),
+		model      TEXT NOT NULL,
+		dim        INTEGER NOT NULL,
+		vector     BYTEA NOT NULL,
+		PRIMARY KEY (article_id, model)
+	)`,
+	`CREATE TABLE IF NOT EXISTS report_runs (
+		name   TEXT NOT NULL,
+		ran_at TEXT NOT NULL,
+		status TEXT NOT NULL,
+		detail TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_report_runs_name ON report_runs(name, ran_at)`,
+	`CREATE TABLE IF NOT EXISTS daily_stats (
+		day       TEXT NOT NULL,
+		source    TEXT NOT NULL,
+		language  TEXT NOT NULL,
+		articles  INTEGER NOT NULL,
+		withdrawn INTEGER NOT NULL,
+		PRIMARY KEY (day, source, language)
+	)`,
+	`CREATE TABLE IF NOT EXISTS raw_payloads (
+		article_id   BIGINT NOT NULL REFERENCES articles(id),
+		url          TEXT NOT NULL,
+		fetched_at   TEXT NOT NULL,
+		status_code  INTEGER NOT NULL,
+		content_type TEXT NOT NULL DEFAULT '',
+		sha256       TEXT NOT NULL,
+		size         INTEGER NOT NULL,
+		body         BYTEA
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_raw_payloads_article ON raw_payloads(article_id, fetched_at)`,
+	`CREATE TABLE IF NOT EXISTS audit_log (
+		id     BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+		at     TEXT NOT NULL,
+		actor  TEXT NOT NULL,
+		action TEXT NOT NULL,
+		target TEXT NOT NULL DEFAULT '',
+		detail TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_audit_log_action ON audit_log(action, at)`,
+	`CREATE OR REPLACE RULE audit_log_no_update AS ON UPDATE TO audit_log DO INSTEAD NOTHING`,
+	`CREATE OR REPLACE RULE audit_log_no_delete AS ON DELETE TO audit_log DO INSTEAD NOTHING`,
+	`CREATE TABLE IF NOT EXISTS article_sources (
+		article_id BIGINT NOT NULL REFERENCES articles(id),
+		source     TEXT NOT NULL,
+		url        TEXT NOT NULL,
+		seen_at    TEXT NOT NULL,
+		PRIMARY KEY (source, url)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_article_sources_article ON article_sources(article_id)`,
+	`CREATE TABLE IF NOT EXISTS runs (
+		id          BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+		started_at  TEXT NOT NULL,
+		finished_at TEXT NOT NULL DEFAULT '',
+		campaign    TEXT NOT NULL DEFAULT '',
+		config_hash TEXT NOT NULL DEFAULT '',
+		version     TEXT NOT NULL DEFAULT '',
+		vcs_commit  TEXT NOT NULL DEFAULT '',
+		status      TEXT NOT NULL DEFAULT 'running',
+		error       TEXT NOT NULL DEFAULT '',
+		retry_of    BIGINT NOT NULL DEFAULT 0
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_runs_started ON runs(started_at)`,
+	`CREATE TABLE IF NOT EXISTS run_sources (
+		run_id     BIGINT NOT NULL REFERENCES runs(id),
+		source     TEXT NOT NULL,
+		fetched    INTEGER NOT NULL DEFAULT 0,
+		stored     INTEGER NOT NULL DEFAULT 0,
+		duplicates INTEGER NOT NULL DEFAULT 0,
+		partial    INTEGER NOT NULL DEFAULT 0,
+		error      TEXT NOT NULL DEFAULT '',
+		bad_dates  INTEGER NOT NULL DEFAULT 0,
+		skipped    INTEGER NOT NULL DEFAULT 0
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_run_sources_run ON run_sources(run_id)`,
+	`CREATE TABLE IF NOT EXISTS article_runs (
+		article_id BIGINT NOT NULL REFERENCES articles(id),
+		run_id     BIGINT NOT NULL REFERENCES runs(id),
+		source     TEXT NOT NULL,
+		url        TEXT NOT NULL,
+		request    TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (run_id, source, url)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_article_runs_article ON article_runs(article_id)`,
+	`CREATE TABLE IF NOT EXISTS source_watermarks (
+		scope      TEXT NOT NULL,
+		source     TEXT NOT NULL,
+		query      TEXT NOT NULL,
+		published  TEXT NOT NULL,
+		url        TEXT NOT NULL DEFAULT '',
+		updated_at TEXT NOT NULL,
+		PRIMARY KEY (scope, source)
+	)`,
+	`CREATE TABLE IF NOT EXISTS run_failures (
+		run_id     BIGINT NOT NULL REFERENCES runs(id),
+		source     TEXT NOT NULL,
+		item       TEXT NOT NULL DEFAULT '',
+		query      TEXT NOT NULL DEFAULT '',
+		languages  TEXT NOT NULL DEFAULT '',
+		from_at    TEXT NOT NULL DEFAULT '',
+		to_at      TEXT NOT NULL,
+		error      TEXT NOT NULL DEFAULT '',
+		retried_by BIGINT NOT NULL DEFAULT 0,
+		PRIMARY KEY (run_id, source, item)
+	)`,
+	`CREATE TABLE IF NOT EXISTS attachments (
+		article_id    BIGINT NOT NULL REFERENCES articles(id),
+		url           TEXT NOT NULL,
+		declared_type TEXT NOT NULL DEFAULT '',
+		type          TEXT NOT NULL DEFAULT '',
+		size          BIGINT NOT NULL DEFAULT 0,
+		sha256        TEXT NOT NULL DEFAULT '',
+		fetched_at    TEXT NOT NULL DEFAULT '',
+		error         TEXT NOT NULL DEFAULT '',
+		phash         TEXT NOT NULL DEFAULT '',
+		shared        INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (article_id, url)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_attachments_pending ON attachments(fetched_at)`,
+	`CREATE INDEX IF NOT EXISTS idx_attachments_sha256 ON attachments(sha256)`,
+	`CREATE TABLE IF NOT EXISTS feed_states (
+		url           TEXT PRIMARY KEY,
+		etag          TEXT NOT NULL DEFAULT '',
+		last_modified TEXT NOT NULL DEFAULT '',
+		newest        TEXT NOT NULL DEFAULT '',
+		polled_at     TEXT NOT NULL,
+		yield_items   DOUBLE PRECISION NOT NULL DEFAULT 0,
+		yield_hours   DOUBLE PRECISION NOT NULL DEFAULT 0,
+		next_poll     TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE TABLE IF NOT EXISTS related_media (
+		article_id       BIGINT NOT NULL REFERENCES articles(id),
+		url              TEXT NOT NULL,
+		provider         TEXT NOT NULL,
+		video_id         TEXT NOT NULL,
+		title            TEXT NOT NULL DEFAULT '',
+		channel          TEXT NOT NULL DEFAULT '',
+		channel_url      TEXT NOT NULL DEFAULT '',
+		thumbnail        TEXT NOT NULL DEFAULT '',
+		duration_seconds INTEGER NOT NULL DEFAULT 0,
+		fetched_at       TEXT NOT NULL,
+		error            TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (article_id, url)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_related_media_video ON related_media(provider, video_id)`,
+	`CREATE TABLE IF NOT EXISTS article_entities (
+		article_id BIGINT NOT NULL REFERENCES articles(id),
+		name       TEXT NOT NULL,
+		kind       TEXT NOT NULL,
+		score      DOUBLE PRECISION NOT NULL DEFAULT 0,
+		PRIMARY KEY (article_id, name, kind)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_article_entities_kind ON article_entities(kind, name)`,
+	`CREATE TABLE IF NOT EXISTS extractions (
+		article_id   BIGINT PRIMARY KEY REFERENCES articles(id),
+		strategy     TEXT NOT NULL,
+		score        DOUBLE PRECISION NOT NULL DEFAULT 0,
+		length       INTEGER NOT NULL DEFAULT 0,
+		boilerplate  DOUBLE PRECISION NOT NULL DEFAULT 0,
+		has_date     INTEGER NOT NULL DEFAULT 0,
+		has_author   INTEGER NOT NULL DEFAULT 0,
+		attempts     TEXT NOT NULL DEFAULT '[]',
+		extracted_at TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_extractions_strategy ON extractions(strategy)`,
+	`CREATE TABLE IF NOT EXISTS article_sentiment (
+		article_id BIGINT PRIMARY KEY REFERENCES articles(id),
+		score      DOUBLE PRECISION NOT NULL,
+		positive   INTEGER NOT NULL DEFAULT 0,
+		negative   INTEGER NOT NULL DEFAULT 0,
+		lexicon    TEXT NOT NULL,
+		scored_at  TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS render_paths (
+		domain       TEXT PRIMARY KEY,
+		mode         TEXT NOT NULL,
+		static_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+		render_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+		checked_at   TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS article_engagement (
+		article_id  BIGINT PRIMARY KEY REFERENCES articles(id),
+		likes       INTEGER NOT NULL DEFAULT 0,
+		reposts     INTEGER NOT NULL DEFAULT 0,
+		replies     INTEGER NOT NULL DEFAULT 0,
+		quotes      INTEGER NOT NULL DEFAULT 0,
+		views       BIGINT NOT NULL DEFAULT 0,
+		observed_at TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS short_links (
+		url         TEXT PRIMARY KEY,
+		target      TEXT NOT NULL DEFAULT '',
+		resolved_at TEXT NOT NULL,
+		error       TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE TABLE IF NOT EXISTS source_holds (
+		source     TEXT PRIMARY KEY,
+		reason     TEXT NOT NULL DEFAULT '',
+		actor      TEXT NOT NULL DEFAULT '',
+		held_at    TEXT NOT NULL,
+		held_until TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE TABLE IF NOT EXISTS run_requests (
+		run_id   BIGINT NOT NULL REFERENCES runs(id),
+		source   TEXT NOT NULL,
+		requests BIGINT NOT NULL,
+		PRIMARY KEY (run_id, source)
+	)`,
+	`CREATE TABLE IF NOT EXISTS article_events (
+		article_id  BIGINT PRIMARY KEY REFERENCES articles(id),
+		event_uri   TEXT NOT NULL,
+		title       TEXT NOT NULL DEFAULT '',
+		summary     TEXT NOT NULL DEFAULT '',
+		event_date  TEXT NOT NULL DEFAULT '',
+		articles    BIGINT NOT NULL DEFAULT 0,
+		observed_at TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_article_events_uri ON article_events(event_uri)`,
+	`CREATE TABLE IF NOT EXISTS archive_checks (
+		article_id        BIGINT PRIMARY KEY REFERENCES articles(id),
+		url               TEXT NOT NULL,
+		archived          INTEGER NOT NULL DEFAULT 0,
+		snapshot_url      TEXT NOT NULL DEFAULT '',
+		snapshot_at       TEXT NOT NULL DEFAULT '',
+		checked_at        TEXT NOT NULL,
+		save_requested_at TEXT NOT NULL DEFAULT '',
+		error             TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE TABLE IF NOT EXISTS warc_records (
+		article_id    BIGINT PRIMARY KEY REFERENCES articles(id),
+		crawl         TEXT NOT NULL,
+		filename      TEXT NOT NULL,
+		record_offset BIGINT NOT NULL,
+		record_length BIGINT NOT NULL,
+		digest        TEXT NOT NULL DEFAULT '',
+		captured_at   TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS article_annotations (
+		article_id BIGINT NOT NULL REFERENCES articles(id),
+		kind       TEXT NOT NULL,
+		domain     TEXT NOT NULL DEFAULT '',
+		domain_id  TEXT NOT NULL DEFAULT '',
+		name       TEXT NOT NULL,
+		entity_id  TEXT NOT NULL DEFAULT '',
+		score      DOUBLE PRECISION NOT NULL DEFAULT 0,
+		PRIMARY KEY (article_id, kind, domain, name)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_article_annotations_name ON article_annotations(kind, name)`,
+	`CREATE TABLE IF NOT EXISTS article_geo (
+		article_id BIGINT PRIMARY KEY REFERENCES articles(id),
+		place_id   TEXT NOT NULL DEFAULT '',
+		place      TEXT NOT NULL DEFAULT '',
+		country    TEXT NOT NULL DEFAULT '',
+		lat        DOUBLE PRECISION,
+		lon        DOUBLE PRECISION
+	)`,
+	`CREATE TABLE IF NOT EXISTS tweet_references (
+		article_id   BIGINT NOT NULL REFERENCES articles(id),
+		tweet_id     TEXT NOT NULL,
+		author       TEXT NOT NULL DEFAULT '',
+		kind         TEXT NOT NULL,
+		ref_tweet_id TEXT NOT NULL,
+		ref_author   TEXT NOT NULL DEFAULT '',
+		created_at   TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (tweet_id, kind, ref_tweet_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_tweet_references_ref ON tweet_references(ref_tweet_id)`,
+	`CREATE TABLE IF NOT EXISTS account_snapshots (
+		platform  TEXT NOT NULL,
+		handle    TEXT NOT NULL,
+		day       TEXT NOT NULL,
+		name      TEXT NOT NULL DEFAULT '',
+		bio       TEXT NOT NULL DEFAULT '',
+		followers INTEGER NOT NULL DEFAULT 0,
+		following INTEGER NOT NULL DEFAULT 0,
+		posts     INTEGER NOT NULL DEFAULT 0,
+		taken_at  TEXT NOT NULL,
+		PRIMARY KEY (platform, handle, day)
+	)`,
+	`CREATE TABLE IF NOT EXISTS export_runs (
+		name   TEXT NOT NULL,
+		ran_at TEXT NOT NULL,
+		status TEXT NOT NULL,
+		detail TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_export_runs_name ON export_runs(name, ran_at)`,
+	`CREATE TABLE IF NOT EXISTS publishers (
+		kind       TEXT NOT NULL,
+		name       TEXT NOT NULL,
+		tos        TEXT NOT NULL DEFAULT '',
+		text       TEXT NOT NULL DEFAULT 'share',
+		exclude    INTEGER NOT NULL DEFAULT 0,
+		license    TEXT NOT NULL DEFAULT '',
+		updated_at TEXT NOT NULL,
+		PRIMARY KEY (kind, name)
+	)`,
+	`CREATE TABLE IF NOT EXISTS feed_dates (
+		run_id   BIGINT NOT NULL REFERENCES runs(id),
+		source   TEXT NOT NULL,
+		feed     TEXT NOT NULL,
+		items    BIGINT NOT NULL,
+		unparsed BIGINT NOT NULL DEFAULT 0,
+		future   BIGINT NOT NULL DEFAULT 0,
+		bogus    BIGINT NOT NULL DEFAULT 0,
+		example  TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (run_id, source, feed)
+	)`,
+	`CREATE TABLE IF NOT EXISTS article_dates (
+		article_id BIGINT PRIMARY KEY REFERENCES articles(id),
+		issue      TEXT NOT NULL,
+		original   TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS keyword_trends (
+		term     TEXT PRIMARY KEY,
+		recent   DOUBLE PRECISION NOT NULL,
+		baseline DOUBLE PRECISION NOT NULL,
+		updated  TEXT NOT NULL,
+		first    TEXT NOT NULL,
+		articles BIGINT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS keyword_trend_articles (
+		article_id BIGINT PRIMARY KEY REFERENCES articles(id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS notification_failures (
+		id           BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+		notification TEXT NOT NULL,
+		payload      TEXT NOT NULL,
+		articles     BIGINT NOT NULL,
+		attempts     BIGINT NOT NULL,
+		error        TEXT NOT NULL,
+		failed_at    TEXT NOT NULL,
+		resent_at    TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_notification_failures_pending ON notification_failures(resent_at, id)`,
+}
+
+// copyTables en orden de dependencias: articles primero por las referencias.
+// body_dicts no se copia: los cuerpos llegan descomprimidos.
+var copyTables = []string{
+	"articles", "page_fetches", "domain_backoff", "labels",
+	"embeddings", "report_runs", "daily_stats", "raw_payloads", "audit_log",
+	"article_sources", "runs", "run_sources", "article_runs", "source_watermarks",
+	"run_failures", "attachments", "feed_states",
+	"related_media", "short_links", "article_entities",
+	"extractions", "article_sentiment", "render_paths", "article_engagement",
+	"source_holds", "run_requests", "article_events", "archive_checks",
+	"warc_records", "article_annotations", "article_geo", "tweet_references",
+	"account_snapshots", "export_runs", "publishers", "feed_dates", "article_dates",
+	"keyword_trends", "keyword_trend_articles", "notification_failures",
+}
+
+// TableCopy es el resultado de copiar una tabla, con los conteos de verificación.
+type TableCopy struct {
+	Table  string
+	Source int64 // filas en SQLite
+	Target int64 // filas en Postgres después de copiar
+}
+
+func (c TableCopy) OK() bool { return c.Source == c.Target }
+
+// CopyOptions ajusta la copia a Postgres.
+type CopyOptions struct {
+	// BatchSize es cuántas filas se insertan por transacción (1000 por defecto).
+	BatchSize int
+	// Payload, si no es nil, devuelve el contenido de una página cruda por su
+	// hash, que se copia a raw_payloads.body.
+	Payload func(sha256 string) ([]byte, error)
+	// Progress, si no es nil, se llama después de cada lote.
+	Progress func(table string, copied int64)
+}
+
+// CopyToPostgres copia el corpus a una base Postgres vacía y verifica que los
+// conteos coincidan. Se niega a copiar sobre tablas con datos para no mezclar
+// corpus; el esquema se crea si no existe. Los lotes ya copiados quedan en el
+// destino si la copia falla a mitad: hay que vaciarlo antes de reintentar.
+func (s *Store) CopyToPostgres(dst *sql.DB, opts CopyOptions) ([]TableCopy, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	if err := EnsurePostgresSchema(dst); err != nil {
+		return nil, err
+	}
+	for _, table := range copyTables {
+		var n int64
+		if err := dst.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n); err != nil {
+			return nil, fmt.Errorf("error revisando %s en Postgres: %w", table, err)
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("la tabla %s en Postgres ya tiene %d filas; la copia requiere un destino vacío", table, n)
+		}
+	}
+
+	var results []TableCopy
+	for _, table := range copyTables {
+		if err := s.copyTable(dst, table, opts); err != nil {
+			return results, err
+		}
+		res := TableCopy{Table: table}
+		if err := s.db.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&res.Source); err != nil {
+			return results, err
+		}
+		if err := dst.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&res.Target); err != nil {
+			return results, err
+		}
+		results = append(results, res)
+	}
+
+	// Los IDs se copiaron explícitos: las secuencias deben seguir desde el mayor.
+	for _, table := range []string{"articles", "audit_log", "runs", "notification_failures"} {
+		_, err := dst.Exec(`SELECT setval(pg_get_serial_sequence('` + table + `', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM ` + table)
+		if err != nil {
+			return results, fmt.Errorf("error ajustando la secuencia de %s: %w", table, err)
+		}
+	}
+	return results, nil
+}
+
+func (s *Store) copyTable(dst *sql.DB, table string, opts CopyOptions) error {
+	columns, err := s.tableColumns(table)
+	if err != nil {
+		return err
+	}
+	selected := append([]string{}, columns...)
+	if table == "articles" {
+		// Los cuerpos comprimidos van a Postgres como texto.
+		for i, c := range selected {
+			if c == "body" {
+				selected[i] = "unzstd(body)"
+			}
+		}
+	}
+	rows, err := s.db.Query(`SELECT ` + strings.Join(selected, ", ") + ` FROM ` + table + ` ORDER BY rowid`)
+	if err != nil {
+		return fmt.Errorf("error leyendo %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	withBody := table == "raw_payloads" && opts.Payload != nil
+	shaIndex := -1
+	insertCols := columns
+	if withBody {
+		for i, c := range columns {
+			if c == "sha256" {
+				shaIndex = i
+			}
+		}
+		insertCols = append(append([]string{}, columns...), "body")
+	}
+	placeholders := make([]string, len(insertCols))
+	for i := range placeholders {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	insert := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s)`, table, strings.Join(insertCols, ", "), strings.Join(placeholders, ", "))
+
+	var (
+		tx     *sql.Tx
+		stmt   *sql.Stmt
+		copied int64
+	)
+	flush := func() error {
+		if tx == nil {
+			return nil
+		}
+		stmt.Close()
+		err := tx.Commit()
+		tx, stmt = nil, nil
+		if err == nil && opts.Progress != nil {
+			opts.Progress(table, copied)
+		}
+		return err
+	}
+
+	for rows.Next() {
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		if withBody {
+			sum, _ := values[shaIndex].(string)
+			body, err := opts.Payload(sum)
+			if err != nil {
+				return err
+			}
+			values = append(values, body)
+		}
+
+		if tx == nil {
+			if tx, err = dst.Begin(); err != nil {
+				return fmt.Errorf("error copiando %s: %w", table, err)
+			}
+			if stmt, err = tx.Prepare(insert); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("error copiando %s: %w", table, err)
+			}
+		}
+		if _, err := stmt.Exec(values...); err != nil {
+			stmt.Close()
+			tx.Rollback()
+			return fmt.Errorf("error copiando fila de %s: %w", table, err)
+		}
+		copied++
+		if copied%int64(opts.BatchSize) == 0 {
+			if err := flush(); err != nil {
+				return fmt.Errorf("error copiando %s: %w", table, err)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return fmt.Errorf("error copiando %s: %w", table, err)
+	}
+	return nil
+}
+
+// tableColumns devuelve las columnas de una tabla SQLite en orden.
+func (s *Store) tableColumns(table string) ([]string, error) {
+	rows, err := s.db.Query(`SELECT name FROM pragma_table_info(?) ORDER BY cid`, table)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo columnas de %s: %w", table, err)
+	}
+	defer rows.Close()
+	var cols []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		cols = append(cols, name)
+	}
+	return cols, rows.Err()
+}
+
+// EnsurePostgresSchema crea el esquema en Postgres si no existe.
+func EnsurePostgresSchema(dst *sql.DB) error {
+	for _, stmt := range pgSchema {
+		if _, err := dst.Exec(stmt); err != nil {
+			return fmt.Errorf("error creando esquema en Postgres: %w", err)
+		}
+	}
+	return nil
+}
+
+// MirrorToPostgres replica artículos recién guardados en una base Postgres
+// compartida, con la misma lógica de actualización que SaveArticle (el cuerpo
+// no se pisa con uno vacío) y el mismo cifrado del autor. Los IDs son los de
+// Postgres: la réplica se identifica por URL.
+func (s *Store) MirrorToPostgres(dst *sql.DB, articles []*article.Article) error {
+	tx, err := dst.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción en Postgres: %w", err)
+	}
+	defer tx.Rollback()
+	for _, a := range articles {
+		author, err := s.encryptAuthor(a.Source, a.Author)
+		if err != nil {
+			return err
+		}
+		status := a.Status
+		if status == "" {
+			status = article.StatusActive
+		}
+		_, err = tx.Exec(`
+			INSERT INTO articles (source, url, title, author, domain, language, section, summary, body, published, collected, status, title_translated, extraction_issue)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			ON CONFLICT (url) DO UPDATE SET
+				title = excluded.title,
+				title_translated = CASE WHEN excluded.title_translated != '' THEN excluded.title_translated ELSE articles.title_translated END,
+				author = excluded.author,
+				domain = excluded.domain,
+				language = excluded.language,
+				section = excluded.section,
+				summary = excluded.summary,
+				body = CASE WHEN excluded.body != '' THEN excluded.body ELSE articles.body END,
+				extraction_issue = CASE WHEN excluded.body != '' OR excluded.extraction_issue != '' THEN excluded.extraction_issue ELSE articles.extraction_issue END,
+				published = excluded.published`,
+			a.Source, a.URL, a.Title, author, a.Domain, a.Language, a.Section, a.Summary, a.Body,
+			formatTime(a.Published), formatTime(a.Collected), status, a.TitleTranslated, a.ExtractionIssue)
+		if err != nil {
+			return fmt.Errorf("error replicando artículo %s en Postgres: %w", a.URL, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando réplica en Postgres: %w", err)
+	}
+	return nil
+}
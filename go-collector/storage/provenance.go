@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Provenance es una aparición de un artículo en una fuente. Cuando la
+// deduplicación colapsa la misma historia traída por varias fuentes, el
+// artículo guardado conserva aquí todas las URLs por las que llegó.
+type Provenance struct {
+	Source string
+	URL    string
+	SeenAt time.Time
+}
+
+// AddProvenance registra que el artículo apareció en source con esa URL. Si
+// la aparición ya estaba registrada no se modifica.
+func (s *Store) AddProvenance(articleID int64, source, url string, at time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT INTO article_sources (article_id, source, url, seen_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(source, url) DO NOTHING`,
+		articleID, source, url, formatTime(at))
+	if err != nil {
+		return fmt.Errorf("error registrando procedencia del artículo %d: %w", articleID, err)
+	}
+	return nil
+}
+
+// Provenance devuelve las apariciones registradas del artículo, la primera
+// primero.
+func (s *Store) Provenance(articleID int64) ([]Provenance, error) {
+	rows, err := s.db.Query(`SELECT source, url, seen_at FROM article_sources
+		WHERE article_id = ? ORDER BY seen_at, source`, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo procedencia del artículo %d: %w", articleID, err)
+	}
+	defer rows.Close()
+
+	var out []Provenance
+	for rows.Next() {
+		var p Provenance
+		var seen string
+		if err := rows.Scan(&p.Source, &p.URL, &seen); err != nil {
+			return nil, err
+		}
+		p.SeenAt = parseTime(seen)
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
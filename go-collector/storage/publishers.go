@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-collector/article"
+)
+
+// Qué permiten los términos de uso hacer con el texto completo.
+const (
+	TextShare = "share" // guardarlo y redistribuirlo
+	TextStore = "store" // guardarlo para el análisis, no redistribuirlo
+	TextNone  = "none"  // ni siquiera guardarlo
+)
+
+// Publisher son los términos de uso de una fuente (Kind "source", ej: x) o
+// de un medio (Kind "domain", ej: eltiempo.com, con sus subdominios).
+type Publisher struct {
+	Kind    string
+	Name    string
+	TOS     string // categoría: open, research, api o restricted
+	Text    string // TextShare, TextStore o TextNone
+	Exclude bool   // los artículos no se exportan
+	License string // ej: "CC BY 4.0" o la URL de los términos
+	Updated time.Time
+}
+
+// SyncPublishers deja en la tabla publishers exactamente los términos dados
+// (los de la configuración). Updated cambia solo en los que cambiaron, así la
+// tabla registra desde cuándo rige cada uno.
+func (s *Store) SyncPublishers(list []Publisher) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	keep := make(map[[2]string]bool, len(list))
+	now := formatTime(time.Now())
+	for _, p := range list {
+		keep[[2]string{p.Kind, p.Name}] = true
+		_, err := tx.Exec(`
+			INSERT INTO publishers (kind, name, tos, text, exclude, license, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(kind, name) DO UPDATE SET
+				tos = excluded.tos, text = excluded.text, exclude = excluded.exclude,
+				license = excluded.license, updated_at = excluded.updated_at
+			WHERE tos != excluded.tos OR text != excluded.text OR exclude != excluded.exclude OR license != excluded.license`,
+			p.Kind, p.Name, p.TOS, p.Text, boolInt(p.Exclude), p.License, now)
+		if err != nil {
+			return fmt.Errorf("error guardando los términos de %s: %w", p.Name, err)
+		}
+	}
+	for _, p := range s.Publishers() {
+		if keep[[2]string{p.Kind, p.Name}] {
+			continue
+		}
+		if _, err := tx.Exec(`DELETE FROM publishers WHERE kind = ? AND name = ?`, p.Kind, p.Name); err != nil {
+			return fmt.Errorf("error quitando los términos de %s: %w", p.Name, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return s.loadPublishers()
+}
+
+// Publishers devuelve los términos registrados, por tipo y nombre.
+func (s *Store) Publishers() []Publisher {
+	s.publishersMu.RLock()
+	defer s.publishersMu.RUnlock()
+	return s.publishers
+}
+
+func (s *Store) loadPublishers() error {
+	rows, err := s.db.Query(`SELECT kind, name, tos, text, exclude, license, updated_at FROM publishers ORDER BY kind, name`)
+	if err != nil {
+		return fmt.Errorf("error leyendo los términos de uso: %w", err)
+	}
+	defer rows.Close()
+	var out []Publisher
+	for rows.Next() {
+		var p Publisher
+		var exclude int
+		var updated string
+		if err := rows.Scan(&p.Kind, &p.Name, &p.TOS, &p.Text, &exclude, &p.License, &updated); err != nil {
+			return err
+		}
+		p.Exclude, p.Updated = exclude != 0, parseTime(updated)
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	s.publishersMu.Lock()
+	s.publishers = out
+	s.publishersMu.Unlock()
+	return nil
+}
+
+// Terms combina los términos que alcanzan a un artículo de source publicado
+// en domain: rige el más restrictivo. Sin términos registrados, el texto se
+// puede compartir.
+func (s *Store) Terms(source, domain string) Publisher {
+	out := Publisher{Text: TextShare}
+	domain = strings.TrimPrefix(strings.ToLower(domain), "www.")
+	for _, p := range s.Publishers() {
+		match := p.Kind == "source" && p.Name == source ||
+			p.Kind == "domain" && (domain == p.Name || strings.HasSuffix(domain, "."+p.Name))
+		if !match {
+			continue
+		}
+		if textRank[p.Text] > textRank[out.Text] {
+			out.Text = p.Text
+		}
+		out.Exclude = out.Exclude || p.Exclude
+		if out.Kind == "" || p.Kind == "domain" {
+			// El medio es más específico que la fuente por la que llegó.
+			out.Kind, out.Name, out.TOS, out.License = p.Kind, p.Name, p.TOS, p.License
+		}
+	}
+	return out
+}
+
+var textRank = map[string]int{TextShare: 0, TextStore: 1, TextNone: 2}
+
+// Redistribute deja en a solo lo que los términos permiten redistribuir: sin
+// el texto completo si no es TextShare. Devuelve false si el artículo no se
+// puede redistribuir en absoluto, y si se quitó el texto.
+func (p Publisher) Redistribute(a *article.Article) (keep, redacted bool) {
+	if p.Exclude {
+		return false, false
+	}
+	if p.Text != TextShare && a.Body != "" {
+		a.Body = ""
+		return true, true
+	}
+	return true, false
+}
+
+// Sharing cuenta lo que los términos de uso dejaron fuera de una salida.
+type Sharing struct {
+	Excluded int // artículos que no se pueden redistribuir
+	Redacted int // artículos que salieron sin el texto completo
+}
+
+// Shareable es lo que se puede redistribuir de a según sus términos (ver
+// Terms y Publisher.Redistribute): nil si nada, una copia sin el texto
+// completo si no es TextShare. a no cambia. Todo lo que sale del corpus
+// (export, API, gRPC, índice, JSONL, notificaciones...) pasa por acá.
+func (s *Store) Shareable(a *article.Article) (out *article.Article, redacted bool) {
+	c := *a
+	keep, redacted := s.Terms(a.Source, a.Domain).Redistribute(&c)
+	if !keep {
+		return nil, false
+	}
+	if !redacted {
+		return a, false
+	}
+	return &c, true
+}
+
+// ShareAll aplica Shareable a cada artículo y devuelve los que se pueden
+// redistribuir, en el mismo orden.
+func (s *Store) ShareAll(articles []*article.Article) ([]*article.Article, Sharing) {
+	var sh Sharing
+	out := make([]*article.Article, 0, len(articles))
+	for _, a := range articles {
+		if a = sh.Add(s.Shareable(a)); a != nil {
+			out = append(out, a)
+		}
+	}
+	return out, sh
+}
+
+// EachShareable es EachFiltered con los términos de uso aplicados: fn
+// recibe solo lo que se puede redistribuir de cada artículo (ver Shareable).
+func (s *Store) EachShareable(f Filter, fn func(*article.Article) error) (Sharing, error) {
+	var sh Sharing
+	err := s.EachFiltered(f, func(a *article.Article) error {
+		if a = sh.Add(s.Shareable(a)); a == nil {
+			return nil
+		}
+		return fn(a)
+	})
+	return sh, err
+}
+
+// Add cuenta en sh un resultado de Shareable y devuelve el artículo, para
+// encadenarlos: if a = sh.Add(store.Shareable(a)); a != nil {...}.
+func (sh *Sharing) Add(a *article.Article, redacted bool) *article.Article {
+	switch {
+	case a == nil:
+		sh.Excluded++
+	case redacted:
+		sh.Redacted++
+	}
+	return a
+}
+
+// storedBody es el texto que se guarda del artículo: nada si sus términos
+// no permiten guardarlo.
+func (s *Store) storedBody(source, domain, body string) string {
+	if body != "" && s.Terms(source, domain).Text == TextNone {
+		return ""
+	}
+	return body
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
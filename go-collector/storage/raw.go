@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RawPayload es el registro de una página cruda guardada en el archivo
+// (rawarchive); el contenido se busca allí por su hash.
+type RawPayload struct {
+	ArticleID   int64
+	URL         string
+	FetchedAt   time.Time
+	StatusCode  int
+	ContentType string
+	SHA256      string
+	Size        int
+}
+
+// SaveRawPayload registra una descarga cruda de un artículo.
+func (s *Store) SaveRawPayload(p RawPayload) error {
+	_, err := s.db.Exec(`
+		INSERT INTO raw_payloads (article_id, url, fetched_at, status_code, content_type, sha256, size)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		p.ArticleID, p.URL, formatTime(p.FetchedAt), p.StatusCode, p.ContentType, p.SHA256, p.Size)
+	if err != nil {
+		return fmt.Errorf("error registrando página cruda de %s: %w", p.URL, err)
+	}
+	return nil
+}
+
+// LatestRawPayload devuelve la última descarga cruda de un artículo.
+func (s *Store) LatestRawPayload(articleID int64) (*RawPayload, error) {
+	p := &RawPayload{}
+	var fetched string
+	err := s.db.QueryRow(`
+		SELECT article_id, url, fetched_at, status_code, content_type, sha256, size
+		FROM raw_payloads WHERE article_id = ? ORDER BY fetched_at DESC LIMIT 1`, articleID,
+	).Scan(&p.ArticleID, &p.URL, &fetched, &p.StatusCode, &p.ContentType, &p.SHA256, &p.Size)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo página cruda del artículo %d: %w", articleID, err)
+	}
+	p.FetchedAt = parseTime(fetched)
+	return p, nil
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"go-collector/article"
+)
+
+// AddReferences guarda los retweets, citas y respuestas del artículo. Una
+// referencia ya guardada (el mismo tweet hacia el mismo tweet) no se repite.
+// Los autores se cifran como el del artículo (ver EncryptAuthors).
+func (s *Store) AddReferences(articleID int64, refs []article.Reference) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error guardando referencias del artículo %d: %w", articleID, err)
+	}
+	defer tx.Rollback()
+
+	var source string
+	if err := tx.QueryRow(`SELECT source FROM articles WHERE id = ?`, articleID).Scan(&source); err != nil {
+		return fmt.Errorf("error guardando referencias del artículo %d: %w", articleID, err)
+	}
+	for _, r := range refs {
+		author, err := s.encryptAuthor(source, r.Author)
+		if err != nil {
+			return err
+		}
+		refAuthor, err := s.encryptAuthor(source, r.RefAuthor)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`
+			INSERT OR IGNORE INTO tweet_references (article_id, tweet_id, author, kind, ref_tweet_id, ref_author, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			articleID, r.TweetID, author, r.Kind, r.RefTweetID, refAuthor, formatTime(r.Created)); err != nil {
+			return fmt.Errorf("error guardando referencias del artículo %d: %w", articleID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// References devuelve las referencias hechas en [since, until] (límites en
+// cero no restringen), en orden cronológico.
+func (s *Store) References(since, until time.Time) ([]article.Reference, error) {
+	query := `SELECT tweet_id, author, kind, ref_tweet_id, ref_author, created_at FROM tweet_references WHERE 1 = 1`
+	var args []any
+	if !since.IsZero() {
+		query += ` AND created_at >= ?`
+		args = append(args, formatTime(since))
+	}
+	if !until.IsZero() {
+		query += ` AND created_at <= ?`
+		args = append(args, formatTime(until))
+	}
+	rows, err := s.db.Query(query+` ORDER BY created_at, tweet_id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando referencias: %w", err)
+	}
+	defer rows.Close()
+
+	var out []article.Reference
+	for rows.Next() {
+		var r article.Reference
+		var created string
+		if err := rows.Scan(&r.TweetID, &r.Author, &r.Kind, &r.RefTweetID, &r.RefAuthor, &created); err != nil {
+			return nil, err
+		}
+		if s.fields != nil {
+			if r.Author, err = s.fields.DecryptString(r.Author); err != nil {
+				return nil, fmt.Errorf("error descifrando autor del tweet %s: %w", r.TweetID, err)
+			}
+			if r.RefAuthor, err = s.fields.DecryptString(r.RefAuthor); err != nil {
+				return nil, fmt.Errorf("error descifrando autor referido por el tweet %s: %w", r.TweetID, err)
+			}
+		}
+		r.Created = parseTime(created)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-collector/article"
+)
+
+// RelatedMedia es un video enlazado desde un artículo (YouTube, Vimeo), con
+// los datos que dio su plataforma (ver collector unfurl).
+type RelatedMedia struct {
+	ArticleID  int64
+	URL        string // canónica del video
+	Provider   string
+	VideoID    string
+	Title      string
+	Channel    string
+	ChannelURL string
+	Thumbnail  string
+	Duration   time.Duration // cero si la plataforma no la informó
+	Fetched    time.Time
+	Err        string // por qué no se pudo consultar (video privado o borrado)
+}
+
+// SaveRelatedMedia guarda (o reemplaza) un video enlazado.
+func (s *Store) SaveRelatedMedia(m RelatedMedia) error {
+	_, err := s.db.Exec(`
+		INSERT INTO related_media (article_id, url, provider, video_id, title, channel, channel_url, thumbnail, duration_seconds, fetched_at, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(article_id, url) DO UPDATE SET
+			title = excluded.title, channel = excluded.channel, channel_url = excluded.channel_url,
+			thumbnail = excluded.thumbnail, duration_seconds = excluded.duration_seconds,
+			fetched_at = excluded.fetched_at, error = excluded.error`,
+		m.ArticleID, m.URL, m.Provider, m.VideoID, m.Title, m.Channel, m.ChannelURL, m.Thumbnail,
+		int64(m.Duration/time.Second), formatTime(m.Fetched), m.Err)
+	if err != nil {
+		return fmt.Errorf("error guardando el video %s del artículo %d: %w", m.URL, m.ArticleID, err)
+	}
+	return nil
+}
+
+// RelatedMediaOf devuelve los videos enlazados desde el artículo.
+func (s *Store) RelatedMediaOf(articleID int64) ([]RelatedMedia, error) {
+	rows, err := s.db.Query(`
+		SELECT url, provider, video_id, title, channel, channel_url, thumbnail, duration_seconds, fetched_at, error
+		FROM related_media WHERE article_id = ? ORDER BY url`, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando videos del artículo %d: %w", articleID, err)
+	}
+	defer rows.Close()
+
+	var out []RelatedMedia
+	for rows.Next() {
+		m := RelatedMedia{ArticleID: articleID}
+		var seconds int64
+		var fetched string
+		if err := rows.Scan(&m.URL, &m.Provider, &m.VideoID, &m.Title, &m.Channel, &m.ChannelURL, &m.Thumbnail, &seconds, &fetched, &m.Err); err != nil {
+			return nil, err
+		}
+		m.Duration, m.Fetched = time.Duration(seconds)*time.Second, parseTime(fetched)
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// ArticlesMentioning devuelve los artículos cuya URL, resumen o cuerpo
+// contienen alguno de los textos (ej: dominios de video), en orden de ID.
+func (s *Store) ArticlesMentioning(texts ...string) ([]*article.Article, error) {
+	var where []string
+	var args []any
+	for _, t := range texts {
+		where = append(where, "url LIKE ? OR summary LIKE ? OR unzstd(body) LIKE ?")
+		pattern := "%" + t + "%"
+		args = append(args, pattern, pattern, pattern)
+	}
+	rows, err := s.db.Query(`SELECT `+articleColumns+` FROM articles WHERE `+strings.Join(where, " OR ")+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error buscando artículos: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*article.Article
+	for rows.Next() {
+		a, err := s.scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
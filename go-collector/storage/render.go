@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Caminos de descarga que se recuerdan por dominio.
+const (
+	PathStatic = "static" // la descarga sin navegador extrae bien
+	PathRender = "render" // hace falta el navegador sin interfaz
+)
+
+// RenderPath es lo que se aprendió de un dominio al comparar la descarga
+// estática con la renderizada: qué camino funciona y con qué puntaje de
+// extracción salió cada uno la última vez que se compararon.
+type RenderPath struct {
+	Domain      string
+	Mode        string
+	StaticScore float64
+	RenderScore float64
+	Checked     time.Time
+}
+
+// GetRenderPath devuelve el camino recordado del dominio, o ErrNotFound si
+// nunca se comparó.
+func (s *Store) GetRenderPath(domain string) (*RenderPath, error) {
+	p := &RenderPath{Domain: domain}
+	var checked string
+	err := s.db.QueryRow(`SELECT mode, static_score, render_score, checked_at FROM render_paths WHERE domain = ?`, domain).
+		Scan(&p.Mode, &p.StaticScore, &p.RenderScore, &checked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo el camino de %s: %w", domain, err)
+	}
+	p.Checked = parseTime(checked)
+	return p, nil
+}
+
+// SaveRenderPath guarda (o reemplaza) el camino de un dominio.
+func (s *Store) SaveRenderPath(p *RenderPath) error {
+	_, err := s.db.Exec(`
+		INSERT INTO render_paths (domain, mode, static_score, render_score, checked_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(domain) DO UPDATE SET
+			mode = excluded.mode,
+			static_score = excluded.static_score,
+			render_score = excluded.render_score,
+			checked_at = excluded.checked_at`,
+		p.Domain, p.Mode, p.StaticScore, p.RenderScore, formatTime(p.Checked))
+	if err != nil {
+		return fmt.Errorf("error guardando el camino de %s: %w", p.Domain, err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecordReportRun registra una ejecución de un reporte programado.
+func (s *Store) RecordReportRun(name string, at time.Time, status, detail string) error {
+	_, err := s.db.Exec(`INSERT INTO report_runs (name, ran_at, status, detail) VALUES (?, ?, ?, ?)`,
+		name, formatTime(at), status, detail)
+	if err != nil {
+		return fmt.Errorf("error registrando ejecución del reporte %s: %w", name, err)
+	}
+	return nil
+}
+
+// LastReportRun devuelve la última ejecución exitosa del reporte (cero si nunca corrió).
+func (s *Store) LastReportRun(name string) (time.Time, error) {
+	var last string
+	err := s.db.QueryRow(`SELECT COALESCE(MAX(ran_at), '') FROM report_runs WHERE name = ? AND status = 'ok'`, name).Scan(&last)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error leyendo última ejecución del reporte %s: %w", name, err)
+	}
+	return parseTime(last), nil
+}
@@ -0,0 +1,280 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Estados de una ronda de recolección. Una ronda que quedó en RunRunning sin
+// terminar se interrumpió (el proceso murió o se cortó la máquina).
+const (
+	RunRunning = "running"
+	RunOK      = "ok"
+	RunPartial = "partial" // alguna fuente falló o quedó a medias
+	RunFailed  = "failed"
+)
+
+// Run es una ronda de recolección: cuándo corrió, con qué configuración y
+// qué trajo cada fuente.
+type Run struct {
+	ID       int64
+	Started  time.Time
+	Finished time.Time // cero mientras corre
+	Campaign string    // vacío fuera de una campaña
+	// ConfigHash es el hash de la configuración efectiva (config.Config.Hash).
+	ConfigHash string
+	// Version y Commit identifican el recolector que corrió la ronda (ver
+	// buildinfo); vacíos en rondas anteriores a que se registraran.
+	Version string
+	Commit  string
+	Status  string
+	Err     string
+	// RetryOf es la ronda cuyas fallas reintentó esta (collector
+	// retry-failures); cero en una ronda normal.
+	RetryOf int64
+	Sources []RunSource // solo en GetRun
+}
+
+// Duration es cuánto duró la ronda; cero si no terminó.
+func (r *Run) Duration() time.Duration {
+	if r.Finished.IsZero() {
+		return 0
+	}
+	return r.Finished.Sub(r.Started)
+}
+
+// RunSource es el resultado de una fuente en una ronda.
+type RunSource struct {
+	Source     string
+	Fetched    int // artículos traídos de la fuente
+	Stored     int // artículos guardados en el corpus
+	Duplicates int // colapsados con uno ya guardado
+	Partial    bool
+	Err        string
+	BadDates   int // artículos con fecha que no se pudo interpretar
+	// Skipped indica que la fuente no se consultó porque estaba desactivada
+	// temporalmente (ver SourceHold); Err lleva el motivo.
+	Skipped bool
+}
+
+// StartRun registra el comienzo de una ronda y completa r.ID.
+func (s *Store) StartRun(r *Run) error {
+	if r.Status == "" {
+		r.Status = RunRunning
+	}
+	res, err := s.db.Exec(`INSERT INTO runs (started_at, campaign, config_hash, version, vcs_commit, status, retry_of) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		formatTime(r.Started), r.Campaign, r.ConfigHash, r.Version, r.Commit, r.Status, r.RetryOf)
+	if err != nil {
+		return fmt.Errorf("error registrando la ronda: %w", err)
+	}
+	r.ID, err = res.LastInsertId()
+	return err
+}
+
+// AddRunSource registra el resultado de una fuente en la ronda id.
+func (s *Store) AddRunSource(id int64, src RunSource) error {
+	_, err := s.db.Exec(`
+		INSERT INTO run_sources (run_id, source, fetched, stored, duplicates, partial, error, bad_dates, skipped)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, src.Source, src.Fetched, src.Stored, src.Duplicates, src.Partial, src.Err, src.BadDates, src.Skipped)
+	if err != nil {
+		return fmt.Errorf("error registrando la fuente %s en la ronda %d: %w", src.Source, id, err)
+	}
+	return nil
+}
+
+// FinishRun registra el final de la ronda id con su estado.
+func (s *Store) FinishRun(id int64, finished time.Time, status, errMsg string) error {
+	_, err := s.db.Exec(`UPDATE runs SET finished_at = ?, status = ?, error = ? WHERE id = ?`,
+		formatTime(finished), status, errMsg, id)
+	if err != nil {
+		return fmt.Errorf("error cerrando la ronda %d: %w", id, err)
+	}
+	return nil
+}
+
+// RunFilter restringe el listado de rondas; los campos vacíos no filtran.
+type RunFilter struct {
+	Since    time.Time
+	Until    time.Time
+	Status   string
+	Campaign string
+	Limit    int
+}
+
+// RunSummary es una ronda con los totales de sus fuentes, para listados.
+type RunSummary struct {
+	Run
+	Sources int // fuentes de la ronda, incluidas las omitidas
+	Failed  int // fuentes con error
+	Skipped int // fuentes omitidas por estar desactivadas
+	Fetched int
+	Stored  int
+}
+
+// ListRuns devuelve las rondas más recientes primero.
+func (s *Store) ListRuns(f RunFilter) ([]RunSummary, error) {
+	query := `
+		SELECT r.id, r.started_at, r.finished_at, r.campaign, r.config_hash, r.version, r.vcs_commit, r.status, r.error, r.retry_of,
+			COUNT(rs.source), COALESCE(SUM(CASE WHEN rs.error != '' AND rs.skipped = 0 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(rs.skipped), 0), COALESCE(SUM(rs.fetched), 0), COALESCE(SUM(rs.stored), 0)
+		FROM runs r LEFT JOIN run_sources rs ON rs.run_id = r.id
+		WHERE 1 = 1`
+	var args []any
+	if !f.Since.IsZero() {
+		query += ` AND r.started_at >= ?`
+		args = append(args, formatTime(f.Since))
+	}
+	if !f.Until.IsZero() {
+		query += ` AND r.started_at <= ?`
+		args = append(args, formatTime(f.Until))
+	}
+	if f.Status != "" {
+		query += ` AND r.status = ?`
+		args = append(args, f.Status)
+	}
+	if f.Campaign != "" {
+		query += ` AND r.campaign = ?`
+		args = append(args, f.Campaign)
+	}
+	query += ` GROUP BY r.id ORDER BY r.id DESC`
+	if f.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, f.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando rondas: %w", err)
+	}
+	defer rows.Close()
+
+	var out []RunSummary
+	for rows.Next() {
+		var r RunSummary
+		var started, finished string
+		if err := rows.Scan(&r.ID, &started, &finished, &r.Campaign, &r.ConfigHash, &r.Version, &r.Commit, &r.Status, &r.Err, &r.RetryOf,
+			&r.Sources, &r.Failed, &r.Skipped, &r.Fetched, &r.Stored); err != nil {
+			return nil, err
+		}
+		r.Started, r.Finished = parseTime(started), parseTime(finished)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// GetRun devuelve una ronda con el detalle por fuente, o ErrNotFound.
+func (s *Store) GetRun(id int64) (*Run, error) {
+	r := &Run{}
+	var started, finished string
+	err := s.db.QueryRow(`
+		SELECT id, started_at, finished_at, campaign, config_hash, version, vcs_commit, status, error, retry_of
+		FROM runs WHERE id = ?`, id,
+	).Scan(&r.ID, &started, &finished, &r.Campaign, &r.ConfigHash, &r.Version, &r.Commit, &r.Status, &r.Err, &r.RetryOf)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error consultando la ronda %d: %w", id, err)
+	}
+	r.Started, r.Finished = parseTime(started), parseTime(finished)
+
+	rows, err := s.db.Query(`
+		SELECT source, fetched, stored, duplicates, partial, error, bad_dates, skipped
+		FROM run_sources WHERE run_id = ? ORDER BY source`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando la ronda %d: %w", id, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var src RunSource
+		if err := rows.Scan(&src.Source, &src.Fetched, &src.Stored, &src.Duplicates, &src.Partial, &src.Err, &src.BadDates, &src.Skipped); err != nil {
+			return nil, err
+		}
+		r.Sources = append(r.Sources, src)
+	}
+	return r, rows.Err()
+}
+
+// Lineage vincula un artículo con la ronda que lo trajo y la consulta a la
+// fuente (Request) que lo devolvió. Un duplicado colapsado queda vinculado al
+// artículo guardado con su propia fuente y URL.
+type Lineage struct {
+	RunID      int64
+	ArticleID  int64
+	Source     string
+	URL        string
+	Request    string
+	RunStarted time.Time // inicio de la ronda
+	Title      string    // título del artículo guardado
+}
+
+// AddLineage registra que la ronda runID trajo el artículo articleID desde
+// source con esa URL. Si ya estaba registrado no se modifica.
+func (s *Store) AddLineage(runID, articleID int64, source, url, request string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO article_runs (article_id, run_id, source, url, request) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(run_id, source, url) DO NOTHING`,
+		articleID, runID, source, url, request)
+	if err != nil {
+		return fmt.Errorf("error registrando linaje del artículo %d: %w", articleID, err)
+	}
+	return nil
+}
+
+// RunArticles devuelve los artículos que trajo la ronda, por fuente.
+func (s *Store) RunArticles(runID int64) ([]Lineage, error) {
+	return s.lineage(`l.run_id = ? ORDER BY l.source, l.request, l.article_id`, runID)
+}
+
+// ArticleRuns devuelve las rondas que trajeron el artículo, la primera primero.
+func (s *Store) ArticleRuns(articleID int64) ([]Lineage, error) {
+	return s.lineage(`l.article_id = ? ORDER BY l.run_id, l.source`, articleID)
+}
+
+func (s *Store) lineage(where string, arg any) ([]Lineage, error) {
+	rows, err := s.db.Query(`
+		SELECT l.run_id, l.article_id, l.source, l.url, l.request, r.started_at, a.title
+		FROM article_runs l
+		JOIN runs r ON r.id = l.run_id
+		JOIN articles a ON a.id = l.article_id
+		WHERE `+where, arg)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando linaje: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Lineage
+	for rows.Next() {
+		var l Lineage
+		var started string
+		if err := rows.Scan(&l.RunID, &l.ArticleID, &l.Source, &l.URL, &l.Request, &started, &l.Title); err != nil {
+			return nil, err
+		}
+		l.RunStarted = parseTime(started)
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+// RunArticlesSince devuelve, en el orden en que se guardaron, los artículos
+// que la ronda registró después de la marca after (cero: desde el
+// principio), con la marca para la siguiente consulta. Sirve para seguir una
+// ronda en curso.
+func (s *Store) RunArticlesSince(runID, after int64) (ids []int64, last int64, err error) {
+	rows, err := s.db.Query(`SELECT rowid, article_id FROM article_runs WHERE run_id = ? AND rowid > ? ORDER BY rowid`, runID, after)
+	if err != nil {
+		return nil, after, fmt.Errorf("error consultando los artículos de la ronda %d: %w", runID, err)
+	}
+	defer rows.Close()
+	last = after
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&last, &id); err != nil {
+			return nil, after, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, last, rows.Err()
+}
@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// scanChunk es cuántos ids recorre cada bloque de un recorrido por bloques:
+// bloques chicos reparten mejor el trabajo entre lectores y avisan el
+// progreso más seguido.
+const scanChunk = 50_000
+
+// ScanOptions controla los recorridos del corpus por bloques (SummaryScan y
+// CountFiltered). Los campos en cero toman los valores por defecto.
+type ScanOptions struct {
+	// Workers es cuántos bloques se leen a la vez, cada uno con su propia
+	// conexión de solo lectura; en cero, uno por CPU.
+	Workers int
+	// Progress, si no es nil, se llama al terminar cada bloque con los
+	// bloques terminados y el total. Nunca se llama dos veces a la vez.
+	Progress func(done, total int)
+}
+
+// FilterCounts son los artículos que cumplen un filtro contados por fuente e
+// idioma, tal como están guardados (el idioma puede ser vacío).
+type FilterCounts struct {
+	Total      int
+	BySource   map[string]int
+	ByLanguage map[string]int
+}
+
+func newFilterCounts() *FilterCounts {
+	return &FilterCounts{BySource: make(map[string]int), ByLanguage: make(map[string]int)}
+}
+
+// uriEscaper escapa lo que en una URI file: de SQLite no es parte de la ruta.
+var uriEscaper = strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23")
+
+// readers devuelve las conexiones con las que leer workers bloques a la vez:
+// SQLite admite varios lectores aunque escriba una sola conexión, así que se
+// abre aparte la misma base en solo lectura (el llamador la cierra si no es
+// s.db). Una base en memoria no se comparte entre conexiones: se lee con la
+// del Store, de a un bloque.
+func (s *Store) readers(workers int) (*sql.DB, int, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers == 1 || s.path == "" || s.path == ":memory:" || strings.HasPrefix(s.path, "file:") {
+		return s.db, 1, nil
+	}
+	db, err := sql.Open("sqlite", "file:"+uriEscaper.Replace(s.path)+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, 0, fmt.Errorf("error abriendo lectores de la base de datos: %w", err)
+	}
+	db.SetMaxOpenConns(workers)
+	return db, workers, nil
+}
+
+// scanChunks divide los ids de artículos en [lo, hi] en bloques de scanChunk
+// y llama a fn con cada uno, hasta opts.Workers a la vez. fn puede llamarse
+// desde varios goroutines: el llamador protege lo que acumula. Al primer
+// error se cancelan los bloques pendientes.
+func (s *Store) scanChunks(ctx context.Context, lo, hi int64, opts ScanOptions, fn func(ctx context.Context, db *sql.DB, lo, hi int64) error) error {
+	if hi < lo {
+		return nil
+	}
+	db, workers, err := s.readers(opts.Workers)
+	if err != nil {
+		return err
+	}
+	if db != s.db {
+		defer db.Close()
+	}
+
+	total := int((hi-lo)/scanChunk + 1)
+	var mu sync.Mutex
+	done := 0
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(workers)
+	for start := lo; start <= hi; start += scanChunk {
+		end := min(start+scanChunk-1, hi)
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(ctx, db, start, end); err != nil {
+				return err
+			}
+			if opts.Progress != nil {
+				mu.Lock()
+				done++
+				opts.Progress(done, total)
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// idRange devuelve el menor y el mayor id de los artículos que cumplen where;
+// hi < lo si no hay ninguno.
+func (s *Store) idRange(ctx context.Context, where []string, args []any) (lo, hi int64, err error) {
+	q := `SELECT COALESCE(MIN(id), 0), COALESCE(MAX(id), -1) FROM articles`
+	if len(where) > 0 {
+		q += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	if err := s.db.QueryRowContext(ctx, q, args...).Scan(&lo, &hi); err != nil {
+		return 0, 0, fmt.Errorf("error leyendo el rango de artículos: %w", err)
+	}
+	return lo, hi, nil
+}
+
+// countChunk cuenta por fuente e idioma los artículos con id en [lo, hi] que
+// cumplen where.
+func countChunk(ctx context.Context, db *sql.DB, where []string, args []any, lo, hi int64) (*FilterCounts, error) {
+	where = append([]string{"id BETWEEN ? AND ?"}, where...)
+	args = append([]any{lo, hi}, args...)
+	rows, err := db.QueryContext(ctx, `SELECT source, language, COUNT(*) FROM articles
+		WHERE `+strings.Join(where, " AND ")+` GROUP BY source, language`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error contando artículos: %w", err)
+	}
+	defer rows.Close()
+	c := newFilterCounts()
+	for rows.Next() {
+		var source, language string
+		var n int
+		if err := rows.Scan(&source, &language, &n); err != nil {
+			return nil, err
+		}
+		c.Total += n
+		c.BySource[source] += n
+		c.ByLanguage[language] += n
+	}
+	return c, rows.Err()
+}
+
+func (c *FilterCounts) add(o *FilterCounts) {
+	c.Total += o.Total
+	for k, n := range o.BySource {
+		c.BySource[k] += n
+	}
+	for k, n := range o.ByLanguage {
+		c.ByLanguage[k] += n
+	}
+}
+
+// CountFiltered cuenta por fuente e idioma los artículos que cumplen el
+// filtro (Limit y Offset no cuentan) sin cargarlos: recorre los ids por
+// bloques con consultas de agregación, varios a la vez. Es lo que usan los
+// reportes que solo muestran totales, para que un período de millones de
+// artículos no pase por memoria.
+func (s *Store) CountFiltered(ctx context.Context, f Filter, opts ScanOptions) (*FilterCounts, error) {
+	// El rango de ids se acota con las fechas, que tienen índice.
+	bounds, boundArgs := Filter{From: f.From, To: f.To, IncludeWithdrawn: true}.where()
+	lo, hi, err := s.idRange(ctx, bounds, boundArgs)
+	if err != nil {
+		return nil, err
+	}
+	where, args := f.where()
+	out := newFilterCounts()
+	var mu sync.Mutex
+	err = s.scanChunks(ctx, lo, hi, opts, func(ctx context.Context, db *sql.DB, lo, hi int64) error {
+		c, err := countChunk(ctx, db, where, args, lo, hi)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		out.add(c)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SummaryScan calcula los agregados del corpus como Summary, recorriendo los
+// ids por bloques con varios lectores a la vez y avisando el progreso.
+func (s *Store) SummaryScan(ctx context.Context, opts ScanOptions) (*CorpusSummary, error) {
+	lo, hi, err := s.idRange(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	sum := &CorpusSummary{BySource: make(map[string]int), ByLanguage: make(map[string]int)}
+	counts := newFilterCounts()
+	var first, last string
+	var words int64
+	var mu sync.Mutex
+	err = s.scanChunks(ctx, lo, hi, opts, func(ctx context.Context, db *sql.DB, lo, hi int64) error {
+		var part CorpusSummary
+		var partFirst, partLast string
+		var partWords int64
+		// Palabras aproximadas: espacios + 1 sobre el cuerpo sin espacios repetidos en los extremos.
+		err := db.QueryRowContext(ctx, `
+			SELECT COALESCE(SUM(status = 'withdrawn'), 0),
+				COALESCE(MIN(NULLIF(published, '')), ''),
+				COALESCE(MAX(NULLIF(published, '')), ''),
+				COALESCE(SUM(edition_group != 0 AND edition_group != id), 0),
+				COALESCE(SUM(body != '' OR extraction_issue != ''), 0),
+				COALESCE(SUM(body != ''), 0),
+				COALESCE(SUM(CASE WHEN body != ''
+					THEN LENGTH(TRIM(unzstd(body))) - LENGTH(REPLACE(TRIM(unzstd(body)), ' ', '')) + 1 END), 0)
+			FROM articles WHERE id BETWEEN ? AND ?`, lo, hi,
+		).Scan(&part.Withdrawn, &partFirst, &partLast, &part.Duplicates, &part.ExtractionAttempts, &part.ExtractionOK, &partWords)
+		if err != nil {
+			return fmt.Errorf("error calculando resumen del corpus: %w", err)
+		}
+		c, err := countChunk(ctx, db, nil, nil, lo, hi)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		sum.Withdrawn += part.Withdrawn
+		sum.Duplicates += part.Duplicates
+		sum.ExtractionAttempts += part.ExtractionAttempts
+		sum.ExtractionOK += part.ExtractionOK
+		words += partWords
+		if partFirst != "" && (first == "" || partFirst < first) {
+			first = partFirst
+		}
+		if partLast > last {
+			last = partLast
+		}
+		counts.add(c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sum.Total = counts.Total
+	sum.First, sum.Last = parseTime(first), parseTime(last)
+	for k, n := range counts.BySource {
+		sum.BySource[orUnknown(k)] += n
+	}
+	for k, n := range counts.ByLanguage {
+		sum.ByLanguage[orUnknown(k)] += n
+	}
+	if sum.ExtractionOK > 0 {
+		sum.MeanWords = float64(words) / float64(sum.ExtractionOK)
+	}
+	return sum, nil
+}
+
+func orUnknown(key string) string {
+	if key == "" {
+		return "(desconocido)"
+	}
+	return key
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Sentiment es el tono de un artículo según el léxico de su idioma (ver
+// package sentiment): Score va de -1 (negativo) a 1 (positivo).
+type Sentiment struct {
+	ArticleID int64
+	Score     float64
+	Positive  int
+	Negative  int
+	Lexicon   string
+	Scored    time.Time
+}
+
+// SetSentiment guarda (o reemplaza) el tono del artículo.
+func (s *Store) SetSentiment(st Sentiment) error {
+	_, err := s.db.Exec(`
+		INSERT INTO article_sentiment (article_id, score, positive, negative, lexicon, scored_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(article_id) DO UPDATE SET
+			score = excluded.score, positive = excluded.positive, negative = excluded.negative,
+			lexicon = excluded.lexicon, scored_at = excluded.scored_at`,
+		st.ArticleID, st.Score, st.Positive, st.Negative, st.Lexicon, formatTime(st.Scored))
+	if err != nil {
+		return fmt.Errorf("error guardando el tono del artículo %d: %w", st.ArticleID, err)
+	}
+	return nil
+}
+
+// ArticlesWithSentiment devuelve los IDs de los artículos ya puntuados.
+func (s *Store) ArticlesWithSentiment() (map[int64]bool, error) {
+	rows, err := s.db.Query(`SELECT article_id FROM article_sentiment`)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando artículos puntuados: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[int64]bool{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+// SentimentPoint es el tono de un artículo activo con su fecha y fuente.
+type SentimentPoint struct {
+	ArticleID int64
+	Source    string
+	Published time.Time
+	Score     float64
+}
+
+// SentimentPoints devuelve el tono de los artículos activos publicados en
+// [from, to] (límites en cero no restringen), de las fuentes indicadas (todas
+// si no hay), ordenados por publicación.
+func (s *Store) SentimentPoints(from, to time.Time, sources []string) ([]SentimentPoint, error) {
+	where := []string{"a.status = 'active'", "a.published != ''"}
+	var args []any
+	if !from.IsZero() {
+		where, args = append(where, "a.published >= ?"), append(args, formatTime(from))
+	}
+	if !to.IsZero() {
+		where, args = append(where, "a.published <= ?"), append(args, formatTime(to))
+	}
+	if len(sources) > 0 {
+		where = append(where, "a.source IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(sources)), ", ")+")")
+		for _, src := range sources {
+			args = append(args, src)
+		}
+	}
+	rows, err := s.db.Query(`
+		SELECT a.id, a.source, a.published, st.score
+		FROM article_sentiment st JOIN articles a ON a.id = st.article_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY a.published, a.id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando el tono de los artículos: %w", err)
+	}
+	defer rows.Close()
+
+	var out []SentimentPoint
+	for rows.Next() {
+		var p SentimentPoint
+		var published string
+		if err := rows.Scan(&p.ArticleID, &p.Source, &published, &p.Score); err != nil {
+			return nil, err
+		}
+		p.Published = parseTime(published)
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
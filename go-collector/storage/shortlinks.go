@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ShortLink es la expansión guardada de un enlace acortado (t.co, bit.ly):
+// su destino, o por qué no se pudo seguir.
+type ShortLink struct {
+	URL      string
+	Target   string
+	Resolved time.Time
+	Err      string
+}
+
+// GetShortLink devuelve la expansión del enlace, o ErrNotFound si nunca se
+// intentó.
+func (s *Store) GetShortLink(url string) (*ShortLink, error) {
+	l := &ShortLink{URL: url}
+	var resolved string
+	err := s.db.QueryRow(`SELECT target, resolved_at, error FROM short_links WHERE url = ?`, url).
+		Scan(&l.Target, &resolved, &l.Err)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo el enlace %s: %w", url, err)
+	}
+	l.Resolved = parseTime(resolved)
+	return l, nil
+}
+
+// SaveShortLink guarda (o reemplaza) la expansión de un enlace.
+func (s *Store) SaveShortLink(l *ShortLink) error {
+	_, err := s.db.Exec(`
+		INSERT INTO short_links (url, target, resolved_at, error) VALUES (?, ?, ?, ?)
+		ON CONFLICT(url) DO UPDATE SET
+			target = excluded.target,
+			resolved_at = excluded.resolved_at,
+			error = excluded.error`,
+		l.URL, l.Target, formatTime(l.Resolved), l.Err)
+	if err != nil {
+		return fmt.Errorf("error guardando el enlace %s: %w", l.URL, err)
+	}
+	return nil
+}
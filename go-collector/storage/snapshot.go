@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+)
+
+// Snapshot escribe una copia consistente de la base en path con VACUUM INTO,
+// sin detener las escrituras de otros procesos más allá de la copia misma.
+func (s *Store) Snapshot(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("el destino del snapshot ya existe: %s", path)
+	}
+	if _, err := s.db.Exec(`VACUUM INTO ?`, path); err != nil {
+		return fmt.Errorf("error creando snapshot de la base de datos: %w", err)
+	}
+	return nil
+}
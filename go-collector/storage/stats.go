@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CorpusSummary son los agregados básicos del corpus.
+type CorpusSummary struct {
+	Total      int
+	Withdrawn  int
+	First      time.Time // publicación más antigua
+	Last       time.Time // publicación más reciente
+	BySource   map[string]int
+	ByLanguage map[string]int
+
+	// MeanWords es el promedio de palabras del cuerpo entre los artículos con texto.
+	MeanWords float64
+
+	// Duplicates son artículos enlazados a un grupo de ediciones que no son el
+	// primero del grupo (se contarían dos veces sin la deduplicación).
+	Duplicates int
+
+	// ExtractionAttempts son los artículos a los que se intentó extraer el texto;
+	// ExtractionOK los que quedaron con cuerpo.
+	ExtractionAttempts int
+	ExtractionOK       int
+}
+
+// Summary calcula los agregados del corpus con consultas de agregación, sin
+// cargar los artículos en memoria (ver SummaryScan).
+func (s *Store) Summary() (*CorpusSummary, error) {
+	return s.SummaryScan(context.Background(), ScanOptions{})
+}
+
+// GroupStat son los artículos que comparten el valor de una columna (una
+// fuente, una sección): cuántos hay, cuántos están retirados, sus fechas de
+// publicación extremas y la última recolección.
+type GroupStat struct {
+	Key           string
+	Articles      int
+	Withdrawn     int
+	First, Last   time.Time
+	LastCollected time.Time
+}
+
+// groupColumns son las columnas por las que se puede agrupar en StatsBy.
+var groupColumns = map[string]bool{"source": true, "section": true, "language": true}
+
+// StatsBy agrupa por column (source, section o language) los artículos que
+// cumplen el filtro, retirados incluidos (se cuentan en Withdrawn), con los
+// grupos de más artículos primero. Limit y Offset paginan los grupos.
+func (s *Store) StatsBy(column string, f Filter) ([]GroupStat, error) {
+	if !groupColumns[column] {
+		return nil, fmt.Errorf("no se puede agrupar por %q (use source, section o language)", column)
+	}
+	f.IncludeWithdrawn = true
+	where, args := f.where()
+	q := `SELECT ` + column + `, COUNT(*), COALESCE(SUM(status = 'withdrawn'), 0),
+			COALESCE(MIN(NULLIF(published, '')), ''), COALESCE(MAX(NULLIF(published, '')), ''),
+			MAX(collected)
+		FROM articles`
+	if len(where) > 0 {
+		q += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	q += ` GROUP BY ` + column + ` ORDER BY COUNT(*) DESC, ` + column
+	if f.Limit > 0 || f.Offset > 0 {
+		limit := f.Limit
+		if limit <= 0 {
+			limit = -1
+		}
+		q += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, max(f.Offset, 0))
+	}
+
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error agrupando artículos por %s: %w", column, err)
+	}
+	defer rows.Close()
+	var out []GroupStat
+	for rows.Next() {
+		var g GroupStat
+		var first, last, collected string
+		if err := rows.Scan(&g.Key, &g.Articles, &g.Withdrawn, &first, &last, &collected); err != nil {
+			return nil, err
+		}
+		g.First, g.Last, g.LastCollected = parseTime(first), parseTime(last), parseTime(collected)
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,553 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"go-collector/compress"
+)
+
+// timeLayout es el formato con el que se guardan las fechas (UTC, orden lexicográfico = cronológico).
+const timeLayout = "2006-01-02T15:04:05Z"
+
+// ErrNotFound se devuelve cuando una consulta no encuentra el registro pedido.
+var ErrNotFound = errors.New("registro no encontrado")
+
+// Store encapsula el acceso a la base de datos del corpus.
+type Store struct {
+	db *sql.DB
+	// path es la ruta con la que se abrió, para los lectores en paralelo
+	// (ver scanChunks).
+	path string
+
+	// fields cifra el autor de los artículos de authorSources (ver EncryptAuthors).
+	fields        FieldCipher
+	authorSources map[string]bool
+
+	// bodies comprime los cuerpos que se guardan (ver CompressBodies).
+	bodies *compress.Encoder
+
+	// publishers son los términos de uso registrados (ver SyncPublishers);
+	// un daemon los cambia al recargar la configuración.
+	publishersMu sync.RWMutex
+	publishers   []Publisher
+}
+
+// migrations se aplican en orden; cada posición corresponde a una versión del esquema.
+// Nunca se modifica una migración existente: los cambios se agregan al final.
+var migrations = []string{
+	`CREATE TABLE articles (
+		id               INTEGER PRIMARY KEY AUTOINCREMENT,
+		source           TEXT NOT NULL,
+		url              TEXT NOT NULL UNIQUE,
+		title            TEXT NOT NULL DEFAULT '',
+		author           TEXT NOT NULL DEFAULT '',
+		domain           TEXT NOT NULL DEFAULT '',
+		language         TEXT NOT NULL DEFAULT '',
+		section          TEXT NOT NULL DEFAULT '',
+		summary          TEXT NOT NULL DEFAULT '',
+		body             TEXT NOT NULL DEFAULT '',
+		published        TEXT NOT NULL DEFAULT '',
+		collected        TEXT NOT NULL DEFAULT '',
+		status           TEXT NOT NULL DEFAULT 'active',
+		withdrawn_at     TEXT,
+		withdrawn_reason TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX idx_articles_status ON articles(status);`,
+
+	`CREATE TABLE page_fetches (
+		article_id   INTEGER NOT NULL REFERENCES articles(id),
+		fetched_at   TEXT NOT NULL,
+		content_hash TEXT NOT NULL,
+		changed      INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX idx_page_fetches_article ON page_fetches(article_id, fetched_at);`,
+
+	`ALTER TABLE articles ADD COLUMN extraction_issue TEXT NOT NULL DEFAULT '';`,
+
+	`CREATE TABLE domain_backoff (
+		domain          TEXT PRIMARY KEY,
+		last_status     INTEGER NOT NULL DEFAULT 0,
+		last_blocked_at TEXT NOT NULL DEFAULT '',
+		cooldown_until  TEXT NOT NULL DEFAULT '',
+		strikes         INTEGER NOT NULL DEFAULT 0,
+		total_blocks    INTEGER NOT NULL DEFAULT 0
+	);`,
+
+	`ALTER TABLE articles ADD COLUMN edition_group INTEGER NOT NULL DEFAULT 0;
+	CREATE INDEX idx_articles_published ON articles(published);`,
+
+	`ALTER TABLE articles ADD COLUMN explanation TEXT NOT NULL DEFAULT '';`,
+
+	`CREATE TABLE labels (
+		article_id INTEGER PRIMARY KEY REFERENCES articles(id),
+		label      TEXT NOT NULL,
+		annotator  TEXT NOT NULL DEFAULT '',
+		labeled_at TEXT NOT NULL
+	);`,
+
+	`CREATE TABLE embeddings (
+		article_id INTEGER NOT NULL REFERENCES articles(id),
+		model      TEXT NOT NULL,
+		dim        INTEGER NOT NULL,
+		vector     BLOB NOT NULL,
+		PRIMARY KEY (article_id, model)
+	);`,
+
+	`CREATE TABLE report_runs (
+		name   TEXT NOT NULL,
+		ran_at TEXT NOT NULL,
+		status TEXT NOT NULL,
+		detail TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX idx_report_runs_name ON report_runs(name, ran_at);`,
+
+	`CREATE TABLE daily_stats (
+		day       TEXT NOT NULL,
+		source    TEXT NOT NULL,
+		language  TEXT NOT NULL,
+		articles  INTEGER NOT NULL,
+		withdrawn INTEGER NOT NULL,
+		PRIMARY KEY (day, source, language)
+	);`,
+
+	`CREATE TABLE raw_payloads (
+		article_id   INTEGER NOT NULL REFERENCES articles(id),
+		url          TEXT NOT NULL,
+		fetched_at   TEXT NOT NULL,
+		status_code  INTEGER NOT NULL,
+		content_type TEXT NOT NULL DEFAULT '',
+		sha256       TEXT NOT NULL,
+		size         INTEGER NOT NULL
+	);
+	CREATE INDEX idx_raw_payloads_article ON raw_payloads(article_id, fetched_at);`,
+
+	`CREATE TABLE audit_log (
+		id     INTEGER PRIMARY KEY AUTOINCREMENT,
+		at     TEXT NOT NULL,
+		actor  TEXT NOT NULL,
+		action TEXT NOT NULL,
+		target TEXT NOT NULL DEFAULT '',
+		detail TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX idx_audit_log_action ON audit_log(action, at);
+	CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+	BEGIN SELECT RAISE(ABORT, 'audit_log es de solo anexado'); END;
+	CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+	BEGIN SELECT RAISE(ABORT, 'audit_log es de solo anexado'); END;`,
+
+	`CREATE TABLE article_sources (
+		article_id INTEGER NOT NULL REFERENCES articles(id),
+		source     TEXT NOT NULL,
+		url        TEXT NOT NULL,
+		seen_at    TEXT NOT NULL,
+		PRIMARY KEY (source, url)
+	);
+	CREATE INDEX idx_article_sources_article ON article_sources(article_id);`,
+
+	`CREATE TABLE runs (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		started_at  TEXT NOT NULL,
+		finished_at TEXT NOT NULL DEFAULT '',
+		campaign    TEXT NOT NULL DEFAULT '',
+		config_hash TEXT NOT NULL DEFAULT '',
+		status      TEXT NOT NULL DEFAULT 'running',
+		error       TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX idx_runs_started ON runs(started_at);
+	CREATE TABLE run_sources (
+		run_id     INTEGER NOT NULL REFERENCES runs(id),
+		source     TEXT NOT NULL,
+		fetched    INTEGER NOT NULL DEFAULT 0,
+		stored     INTEGER NOT NULL DEFAULT 0,
+		duplicates INTEGER NOT NULL DEFAULT 0,
+		partial    INTEGER NOT NULL DEFAULT 0,
+		error      TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX idx_run_sources_run ON run_sources(run_id);`,
+
+	`CREATE TABLE article_runs (
+		article_id INTEGER NOT NULL REFERENCES articles(id),
+		run_id     INTEGER NOT NULL REFERENCES runs(id),
+		source     TEXT NOT NULL,
+		url        TEXT NOT NULL,
+		request    TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (run_id, source, url)
+	);
+	CREATE INDEX idx_article_runs_article ON article_runs(article_id);`,
+
+	`CREATE TABLE source_watermarks (
+		scope      TEXT NOT NULL,
+		source     TEXT NOT NULL,
+		query      TEXT NOT NULL,
+		published  TEXT NOT NULL,
+		url        TEXT NOT NULL DEFAULT '',
+		updated_at TEXT NOT NULL,
+		PRIMARY KEY (scope, source)
+	);`,
+
+	`ALTER TABLE runs ADD COLUMN retry_of INTEGER NOT NULL DEFAULT 0;
+	CREATE TABLE run_failures (
+		run_id     INTEGER NOT NULL REFERENCES runs(id),
+		source     TEXT NOT NULL,
+		item       TEXT NOT NULL DEFAULT '',
+		query      TEXT NOT NULL DEFAULT '',
+		languages  TEXT NOT NULL DEFAULT '',
+		from_at    TEXT NOT NULL DEFAULT '',
+		to_at      TEXT NOT NULL,
+		error      TEXT NOT NULL DEFAULT '',
+		retried_by INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (run_id, source, item)
+	);`,
+
+	`CREATE TABLE attachments (
+		article_id    INTEGER NOT NULL REFERENCES articles(id),
+		url           TEXT NOT NULL,
+		declared_type TEXT NOT NULL DEFAULT '',
+		type          TEXT NOT NULL DEFAULT '',
+		size          INTEGER NOT NULL DEFAULT 0,
+		sha256        TEXT NOT NULL DEFAULT '',
+		fetched_at    TEXT NOT NULL DEFAULT '',
+		error         TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (article_id, url)
+	);
+	CREATE INDEX idx_attachments_pending ON attachments(fetched_at);`,
+
+	`CREATE TABLE feed_states (
+		url           TEXT PRIMARY KEY,
+		etag          TEXT NOT NULL DEFAULT '',
+		last_modified TEXT NOT NULL DEFAULT '',
+		newest        TEXT NOT NULL DEFAULT '',
+		polled_at     TEXT NOT NULL
+	);`,
+
+	`ALTER TABLE run_sources ADD COLUMN bad_dates INTEGER NOT NULL DEFAULT 0;`,
+
+	`CREATE TABLE related_media (
+		article_id       INTEGER NOT NULL REFERENCES articles(id),
+		url              TEXT NOT NULL,
+		provider         TEXT NOT NULL,
+		video_id         TEXT NOT NULL,
+		title            TEXT NOT NULL DEFAULT '',
+		channel          TEXT NOT NULL DEFAULT '',
+		channel_url      TEXT NOT NULL DEFAULT '',
+		thumbnail        TEXT NOT NULL DEFAULT '',
+		duration_seconds INTEGER NOT NULL DEFAULT 0,
+		fetched_at       TEXT NOT NULL,
+		error            TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (article_id, url)
+	);
+	CREATE INDEX idx_related_media_video ON related_media(provider, video_id);`,
+
+	`CREATE TABLE short_links (
+		url         TEXT PRIMARY KEY,
+		target      TEXT NOT NULL DEFAULT '',
+		resolved_at TEXT NOT NULL,
+		error       TEXT NOT NULL DEFAULT ''
+	);`,
+
+	`CREATE TABLE article_entities (
+		article_id INTEGER NOT NULL REFERENCES articles(id),
+		name       TEXT NOT NULL,
+		kind       TEXT NOT NULL,
+		score      REAL NOT NULL DEFAULT 0,
+		PRIMARY KEY (article_id, name, kind)
+	);
+	CREATE INDEX idx_article_entities_kind ON article_entities(kind, name);`,
+
+	`CREATE TABLE extractions (
+		article_id   INTEGER PRIMARY KEY REFERENCES articles(id),
+		strategy     TEXT NOT NULL,
+		score        REAL NOT NULL DEFAULT 0,
+		length       INTEGER NOT NULL DEFAULT 0,
+		boilerplate  REAL NOT NULL DEFAULT 0,
+		has_date     INTEGER NOT NULL DEFAULT 0,
+		has_author   INTEGER NOT NULL DEFAULT 0,
+		attempts     TEXT NOT NULL DEFAULT '[]',
+		extracted_at TEXT NOT NULL
+	);
+	CREATE INDEX idx_extractions_strategy ON extractions(strategy);`,
+
+	`CREATE TABLE article_sentiment (
+		article_id INTEGER PRIMARY KEY REFERENCES articles(id),
+		score      REAL NOT NULL,
+		positive   INTEGER NOT NULL DEFAULT 0,
+		negative   INTEGER NOT NULL DEFAULT 0,
+		lexicon    TEXT NOT NULL,
+		scored_at  TEXT NOT NULL
+	);`,
+
+	`CREATE TABLE render_paths (
+		domain       TEXT PRIMARY KEY,
+		mode         TEXT NOT NULL,
+		static_score REAL NOT NULL DEFAULT 0,
+		render_score REAL NOT NULL DEFAULT 0,
+		checked_at   TEXT NOT NULL
+	);`,
+
+	`CREATE TABLE article_engagement (
+		article_id  INTEGER PRIMARY KEY REFERENCES articles(id),
+		likes       INTEGER NOT NULL DEFAULT 0,
+		reposts     INTEGER NOT NULL DEFAULT 0,
+		replies     INTEGER NOT NULL DEFAULT 0,
+		quotes      INTEGER NOT NULL DEFAULT 0,
+		observed_at TEXT NOT NULL
+	);`,
+
+	`ALTER TABLE article_engagement ADD COLUMN views INTEGER NOT NULL DEFAULT 0;`,
+
+	`ALTER TABLE runs ADD COLUMN version TEXT NOT NULL DEFAULT '';
+	ALTER TABLE runs ADD COLUMN vcs_commit TEXT NOT NULL DEFAULT '';`,
+
+	`CREATE TABLE source_holds (
+		source     TEXT PRIMARY KEY,
+		reason     TEXT NOT NULL DEFAULT '',
+		actor      TEXT NOT NULL DEFAULT '',
+		held_at    TEXT NOT NULL,
+		held_until TEXT NOT NULL DEFAULT ''
+	);
+	ALTER TABLE run_sources ADD COLUMN skipped INTEGER NOT NULL DEFAULT 0;`,
+
+	`CREATE TABLE run_requests (
+		run_id   INTEGER NOT NULL REFERENCES runs(id),
+		source   TEXT NOT NULL,
+		requests INTEGER NOT NULL,
+		PRIMARY KEY (run_id, source)
+	);`,
+
+	`CREATE TABLE article_events (
+		article_id  INTEGER PRIMARY KEY REFERENCES articles(id),
+		event_uri   TEXT NOT NULL,
+		title       TEXT NOT NULL DEFAULT '',
+		summary     TEXT NOT NULL DEFAULT '',
+		event_date  TEXT NOT NULL DEFAULT '',
+		articles    INTEGER NOT NULL DEFAULT 0,
+		observed_at TEXT NOT NULL
+	);
+	CREATE INDEX idx_article_events_uri ON article_events(event_uri);`,
+
+	`ALTER TABLE articles ADD COLUMN title_translated TEXT NOT NULL DEFAULT '';`,
+
+	`CREATE TABLE archive_checks (
+		article_id        INTEGER PRIMARY KEY REFERENCES articles(id),
+		url               TEXT NOT NULL,
+		archived          INTEGER NOT NULL DEFAULT 0,
+		snapshot_url      TEXT NOT NULL DEFAULT '',
+		snapshot_at       TEXT NOT NULL DEFAULT '',
+		checked_at        TEXT NOT NULL,
+		save_requested_at TEXT NOT NULL DEFAULT '',
+		error             TEXT NOT NULL DEFAULT ''
+	);`,
+
+	`CREATE TABLE warc_records (
+		article_id    INTEGER PRIMARY KEY REFERENCES articles(id),
+		crawl         TEXT NOT NULL,
+		filename      TEXT NOT NULL,
+		record_offset INTEGER NOT NULL,
+		record_length INTEGER NOT NULL,
+		digest        TEXT NOT NULL DEFAULT '',
+		captured_at   TEXT NOT NULL
+	);`,
+
+	`CREATE TABLE article_annotations (
+		article_id INTEGER NOT NULL REFERENCES articles(id),
+		kind       TEXT NOT NULL,
+		domain     TEXT NOT NULL DEFAULT '',
+		domain_id  TEXT NOT NULL DEFAULT '',
+		name       TEXT NOT NULL,
+		entity_id  TEXT NOT NULL DEFAULT '',
+		score      REAL NOT NULL DEFAULT 0,
+		PRIMARY KEY (article_id, kind, domain, name)
+	);
+	CREATE INDEX idx_article_annotations_name ON article_annotations(kind, name);
+	CREATE TABLE article_geo (
+		article_id INTEGER PRIMARY KEY REFERENCES articles(id),
+		place_id   TEXT NOT NULL DEFAULT '',
+		place      TEXT NOT NULL DEFAULT '',
+		country    TEXT NOT NULL DEFAULT '',
+		lat        REAL,
+		lon        REAL
+	);`,
+
+	`CREATE TABLE tweet_references (
+		article_id   INTEGER NOT NULL REFERENCES articles(id),
+		tweet_id     TEXT NOT NULL,
+		author       TEXT NOT NULL DEFAULT '',
+		kind         TEXT NOT NULL,
+		ref_tweet_id TEXT NOT NULL,
+		ref_author   TEXT NOT NULL DEFAULT '',
+		created_at   TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (tweet_id, kind, ref_tweet_id)
+	);
+	CREATE INDEX idx_tweet_references_ref ON tweet_references(ref_tweet_id);`,
+
+	`CREATE TABLE account_snapshots (
+		platform  TEXT NOT NULL,
+		handle    TEXT NOT NULL,
+		day       TEXT NOT NULL,
+		name      TEXT NOT NULL DEFAULT '',
+		bio       TEXT NOT NULL DEFAULT '',
+		followers INTEGER NOT NULL DEFAULT 0,
+		following INTEGER NOT NULL DEFAULT 0,
+		posts     INTEGER NOT NULL DEFAULT 0,
+		taken_at  TEXT NOT NULL,
+		PRIMARY KEY (platform, handle, day)
+	);`,
+
+	`CREATE TABLE export_runs (
+		name   TEXT NOT NULL,
+		ran_at TEXT NOT NULL,
+		status TEXT NOT NULL,
+		detail TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX idx_export_runs_name ON export_runs(name, ran_at);`,
+
+	`CREATE TABLE publishers (
+		kind       TEXT NOT NULL,
+		name       TEXT NOT NULL,
+		tos        TEXT NOT NULL DEFAULT '',
+		text       TEXT NOT NULL DEFAULT 'share',
+		exclude    INTEGER NOT NULL DEFAULT 0,
+		license    TEXT NOT NULL DEFAULT '',
+		updated_at TEXT NOT NULL,
+		PRIMARY KEY (kind, name)
+	);`,
+
+	`CREATE TABLE feed_dates (
+		run_id   INTEGER NOT NULL REFERENCES runs(id),
+		source   TEXT NOT NULL,
+		feed     TEXT NOT NULL,
+		items    INTEGER NOT NULL,
+		unparsed INTEGER NOT NULL DEFAULT 0,
+		future   INTEGER NOT NULL DEFAULT 0,
+		bogus    INTEGER NOT NULL DEFAULT 0,
+		example  TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (run_id, source, feed)
+	);
+	CREATE TABLE article_dates (
+		article_id INTEGER PRIMARY KEY REFERENCES articles(id),
+		issue      TEXT NOT NULL,
+		original   TEXT NOT NULL
+	);`,
+
+	`ALTER TABLE attachments ADD COLUMN phash TEXT NOT NULL DEFAULT '';
+	ALTER TABLE attachments ADD COLUMN shared INTEGER NOT NULL DEFAULT 0;
+	CREATE INDEX idx_attachments_sha256 ON attachments(sha256);`,
+
+	`CREATE TABLE keyword_trends (
+		term     TEXT PRIMARY KEY,
+		recent   REAL NOT NULL,
+		baseline REAL NOT NULL,
+		updated  TEXT NOT NULL,
+		first    TEXT NOT NULL,
+		articles INTEGER NOT NULL
+	);
+	CREATE TABLE keyword_trend_articles (
+		article_id INTEGER PRIMARY KEY REFERENCES articles(id)
+	);`,
+
+	`ALTER TABLE feed_states ADD COLUMN yield_items REAL NOT NULL DEFAULT 0;
+	ALTER TABLE feed_states ADD COLUMN yield_hours REAL NOT NULL DEFAULT 0;
+	ALTER TABLE feed_states ADD COLUMN next_poll TEXT NOT NULL DEFAULT '';`,
+
+	`CREATE TABLE notification_failures (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		notification TEXT NOT NULL,
+		payload      TEXT NOT NULL,
+		articles     INTEGER NOT NULL,
+		attempts     INTEGER NOT NULL,
+		error        TEXT NOT NULL,
+		failed_at    TEXT NOT NULL,
+		resent_at    TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX idx_notification_failures_pending ON notification_failures(resent_at, id);`,
+
+	`CREATE TABLE body_dicts (
+		id         INTEGER PRIMARY KEY,
+		dict       BLOB NOT NULL,
+		samples    INTEGER NOT NULL,
+		created_at TEXT NOT NULL
+	);`,
+}
+
+// Open abre (o crea) la base de datos SQLite en path y aplica las migraciones pendientes.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo base de datos: %w", err)
+	}
+	// SQLite no admite escrituras concurrentes; una sola conexión evita errores de bloqueo.
+	db.SetMaxOpenConns(1)
+
+	s := &Store{db: db, path: path}
+	err = s.migrate()
+	if err == nil {
+		err = s.loadPublishers()
+	}
+	if err == nil {
+		err = s.loadBodyDicts()
+	}
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close cierra la conexión con la base de datos.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) migrate() error {
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)`); err != nil {
+		return fmt.Errorf("error creando tabla de versiones: %w", err)
+	}
+
+	var version int
+	if err := s.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version); err != nil {
+		return fmt.Errorf("error leyendo versión del esquema: %w", err)
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error aplicando migración %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_version (version) VALUES (?)`, i+1); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(timeLayout)
+}
+
+func parseTime(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TrendTerm es el puntaje de tendencia de un término tal como quedó en su
+// última actualización (ver el paquete trends): Recent y Baseline decaen
+// desde Updated; First es su aparición más antigua y Articles en cuántos
+// artículos apareció en total.
+type TrendTerm struct {
+	Term     string
+	Recent   float64
+	Baseline float64
+	Updated  time.Time
+	First    time.Time
+	Articles int
+}
+
+// AddTrendArticle suma a las tendencias los términos del artículo id: carga
+// sus puntajes, los pasa por add y los guarda, todo en una transacción.
+// Cada artículo se suma una sola vez: si ya estaba, no cambia nada y
+// devuelve false.
+func (s *Store) AddTrendArticle(id int64, terms []string, add func(*TrendTerm)) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("error actualizando tendencias del artículo %d: %w", id, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`INSERT OR IGNORE INTO keyword_trend_articles (article_id) VALUES (?)`, id)
+	if err != nil {
+		return false, fmt.Errorf("error actualizando tendencias del artículo %d: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+	for _, term := range terms {
+		t := TrendTerm{Term: term}
+		var updated, first string
+		err := tx.QueryRow(`SELECT recent, baseline, updated, first, articles FROM keyword_trends WHERE term = ?`, term).
+			Scan(&t.Recent, &t.Baseline, &updated, &first, &t.Articles)
+		switch {
+		case err == nil:
+			t.Updated, t.First = parseTime(updated), parseTime(first)
+		case !errors.Is(err, sql.ErrNoRows):
+			return false, fmt.Errorf("error leyendo la tendencia de %q: %w", term, err)
+		}
+		add(&t)
+		_, err = tx.Exec(`
+			INSERT INTO keyword_trends (term, recent, baseline, updated, first, articles) VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(term) DO UPDATE SET recent = excluded.recent, baseline = excluded.baseline,
+				updated = excluded.updated, first = excluded.first, articles = excluded.articles`,
+			t.Term, t.Recent, t.Baseline, formatTime(t.Updated), formatTime(t.First), t.Articles)
+		if err != nil {
+			return false, fmt.Errorf("error guardando la tendencia de %q: %w", term, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error actualizando tendencias del artículo %d: %w", id, err)
+	}
+	return true, nil
+}
+
+// TrendTerms devuelve los puntajes de los términos que aparecieron en al
+// menos minArticles artículos.
+func (s *Store) TrendTerms(minArticles int) ([]TrendTerm, error) {
+	rows, err := s.db.Query(`SELECT term, recent, baseline, updated, first, articles FROM keyword_trends
+		WHERE articles >= ? ORDER BY term`, minArticles)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando tendencias: %w", err)
+	}
+	defer rows.Close()
+	var out []TrendTerm
+	for rows.Next() {
+		var t TrendTerm
+		var updated, first string
+		if err := rows.Scan(&t.Term, &t.Recent, &t.Baseline, &updated, &first, &t.Articles); err != nil {
+			return nil, err
+		}
+		t.Updated, t.First = parseTime(updated), parseTime(first)
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// ResetTrends borra los puntajes y qué artículos se sumaron, para volver a
+// calcularlos desde el corpus.
+func (s *Store) ResetTrends() error {
+	if _, err := s.db.Exec(`DELETE FROM keyword_trends; DELETE FROM keyword_trend_articles;`); err != nil {
+		return fmt.Errorf("error borrando tendencias: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AddRunRequests registra cuántas peticiones se hicieron a la API de una
+// fuente en la ronda id. Una fuente puede sumar peticiones a otra: las de su
+// reemplazo (fallback) van a nombre del reemplazo.
+func (s *Store) AddRunRequests(id int64, source string, requests int) error {
+	_, err := s.db.Exec(`
+		INSERT INTO run_requests (run_id, source, requests) VALUES (?, ?, ?)
+		ON CONFLICT(run_id, source) DO UPDATE SET requests = requests + excluded.requests`,
+		id, source, requests)
+	if err != nil {
+		return fmt.Errorf("error registrando las peticiones de %s en la ronda %d: %w", source, id, err)
+	}
+	return nil
+}
+
+// SourceUsage es el uso de una fuente en un mes (AAAA-MM, en UTC): las
+// peticiones a su API y los artículos que trajeron las rondas que empezaron
+// en él. Rounds son las rondas en que se la consultó.
+type SourceUsage struct {
+	Month    string
+	Source   string
+	Rounds   int
+	Requests int
+	Fetched  int
+	Stored   int
+}
+
+// PerRequest son los artículos traídos por petición; cero sin peticiones
+// registradas.
+func (u SourceUsage) PerRequest() float64 {
+	if u.Requests == 0 {
+		return 0
+	}
+	return float64(u.Fetched) / float64(u.Requests)
+}
+
+// MonthlyUsage devuelve el uso por mes y fuente de las rondas que empezaron
+// en [since, until) (límites en cero no restringen), ordenado por mes y
+// fuente. Las rondas anteriores a que se contaran las peticiones aportan solo
+// artículos.
+func (s *Store) MonthlyUsage(since, until time.Time) ([]SourceUsage, error) {
+	where, args := "1 = 1", []any{}
+	if !since.IsZero() {
+		where, args = where+" AND r.started_at >= ?", append(args, formatTime(since))
+	}
+	if !until.IsZero() {
+		where, args = where+" AND r.started_at < ?", append(args, formatTime(until))
+	}
+
+	type key struct{ month, source string }
+	byKey := make(map[key]*SourceUsage)
+	get := func(k key) *SourceUsage {
+		if byKey[k] == nil {
+			byKey[k] = &SourceUsage{Month: k.month, Source: k.source}
+		}
+		return byKey[k]
+	}
+
+	rows, err := s.db.Query(`
+		SELECT substr(r.started_at, 1, 7), q.source, COUNT(*), SUM(q.requests)
+		FROM run_requests q JOIN runs r ON r.id = q.run_id
+		WHERE `+where+` GROUP BY 1, 2`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando peticiones por mes: %w", err)
+	}
+	for rows.Next() {
+		var k key
+		var rounds, requests int
+		if err := rows.Scan(&k.month, &k.source, &rounds, &requests); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error leyendo peticiones por mes: %w", err)
+		}
+		u := get(k)
+		u.Rounds, u.Requests = rounds, requests
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.db.Query(`
+		SELECT substr(r.started_at, 1, 7), rs.source, COUNT(*), SUM(rs.fetched), SUM(rs.stored)
+		FROM run_sources rs JOIN runs r ON r.id = rs.run_id
+		WHERE rs.skipped = 0 AND `+where+` GROUP BY 1, 2`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando artículos por mes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var k key
+		var rounds, fetched, stored int
+		if err := rows.Scan(&k.month, &k.source, &rounds, &fetched, &stored); err != nil {
+			return nil, fmt.Errorf("error leyendo artículos por mes: %w", err)
+		}
+		u := get(k)
+		u.Rounds, u.Fetched, u.Stored = max(u.Rounds, rounds), fetched, stored
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]SourceUsage, 0, len(byKey))
+	for _, u := range byKey {
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Month != out[j].Month {
+			return out[i].Month < out[j].Month
+		}
+		return out[i].Source < out[j].Source
+	})
+	return out, nil
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go-collector/article"
+)
+
+// SetWARCRecord guarda (o reemplaza) la ubicación de la captura del artículo
+// en Common Crawl: una captura más reciente reemplaza a la anterior.
+func (s *Store) SetWARCRecord(articleID int64, w *article.WARCRecord) error {
+	_, err := s.db.Exec(`
+		INSERT INTO warc_records (article_id, crawl, filename, record_offset, record_length, digest, captured_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(article_id) DO UPDATE SET
+			crawl = excluded.crawl, filename = excluded.filename,
+			record_offset = excluded.record_offset, record_length = excluded.record_length,
+			digest = excluded.digest, captured_at = excluded.captured_at
+		WHERE excluded.captured_at >= warc_records.captured_at`,
+		articleID, w.Crawl, w.Filename, w.Offset, w.Length, w.Digest, formatTime(w.Captured))
+	if err != nil {
+		return fmt.Errorf("error guardando el registro WARC del artículo %d: %w", articleID, err)
+	}
+	return nil
+}
+
+// WARCRecord devuelve la ubicación de la captura del artículo en Common
+// Crawl, o ErrNotFound si no llegó por esa fuente.
+func (s *Store) WARCRecord(articleID int64) (*article.WARCRecord, error) {
+	w := &article.WARCRecord{}
+	var captured string
+	err := s.db.QueryRow(`SELECT crawl, filename, record_offset, record_length, digest, captured_at FROM warc_records WHERE article_id = ?`, articleID).
+		Scan(&w.Crawl, &w.Filename, &w.Offset, &w.Length, &w.Digest, &captured)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo el registro WARC del artículo %d: %w", articleID, err)
+	}
+	w.Captured = parseTime(captured)
+	return w, nil
+}
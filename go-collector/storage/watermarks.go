@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Watermark es la publicación más reciente que trajo una fuente (la marca
+// de agua): las rondas incrementales piden solo lo posterior. Scope separa
+// las campañas que comparten corpus ("" fuera de una campaña) y Query es la
+// consulta con la que se alcanzó: con otra consulta la marca no vale.
+type Watermark struct {
+	Scope     string
+	Source    string
+	Query     string
+	Published time.Time
+	URL       string // el artículo que fijó la marca
+	UpdatedAt time.Time
+}
+
+// Watermark devuelve la marca de agua de la fuente, o ErrNotFound.
+func (s *Store) Watermark(scope, source string) (*Watermark, error) {
+	w := &Watermark{Scope: scope, Source: source}
+	var published, updated string
+	err := s.db.QueryRow(`
+		SELECT query, published, url, updated_at FROM source_watermarks
+		WHERE scope = ? AND source = ?`, scope, source,
+	).Scan(&w.Query, &published, &w.URL, &updated)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo la marca de agua de %s: %w", source, err)
+	}
+	w.Published, w.UpdatedAt = parseTime(published), parseTime(updated)
+	return w, nil
+}
+
+// AdvanceWatermark guarda la marca de agua si es posterior a la registrada o
+// si cambió la consulta. Devuelve si se movió.
+func (s *Store) AdvanceWatermark(w Watermark) (bool, error) {
+	if w.UpdatedAt.IsZero() {
+		w.UpdatedAt = time.Now()
+	}
+	cur, err := s.Watermark(w.Scope, w.Source)
+	if err != nil && err != ErrNotFound {
+		return false, err
+	}
+	if cur != nil && cur.Query == w.Query && !w.Published.After(cur.Published) {
+		return false, nil
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO source_watermarks (scope, source, query, published, url, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(scope, source) DO UPDATE SET
+			query = excluded.query, published = excluded.published,
+			url = excluded.url, updated_at = excluded.updated_at`,
+		w.Scope, w.Source, w.Query, formatTime(w.Published), w.URL, formatTime(w.UpdatedAt))
+	if err != nil {
+		return false, fmt.Errorf("error guardando la marca de agua de %s: %w", w.Source, err)
+	}
+	return true, nil
+}
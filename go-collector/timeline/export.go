@@ -0,0 +1,72 @@
+package timeline
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+)
+
+// WriteJSON exporta la línea de tiempo en JSON.
+func (t *Timeline) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t)
+}
+
+var htmlTemplate = template.Must(template.New("timeline").Funcs(template.FuncMap{
+	"barWidth": func(count, max int) int {
+		if max == 0 {
+			return 0
+		}
+		return count * 300 / max
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<title>Línea de tiempo: {{.T.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 900px; margin: 2em auto; color: #222; }
+.day { display: flex; align-items: center; font-size: 0.85em; }
+.day span.date { width: 7em; }
+.bar { background: #4a7ab5; height: 0.9em; margin-right: 0.5em; }
+.spike .bar { background: #c0392b; }
+.spike .date { font-weight: bold; }
+ol.items li { margin: 0.6em 0; }
+.meta { color: #666; font-size: 0.85em; }
+.withdrawn { text-decoration: line-through; }
+</style>
+</head>
+<body>
+<h1>Línea de tiempo: {{.T.Title}}</h1>
+<p class="meta">{{len .T.Items}} publicaciones entre {{.T.From.Format "2006-01-02"}} y {{.T.To.Format "2006-01-02"}}.
+Los días en rojo son picos de volumen.</p>
+
+<h2>Volumen diario</h2>
+{{range .T.Days}}<div class="day{{if .Spike}} spike{{end}}"><span class="date">{{.Date}}</span><div class="bar" style="width: {{barWidth .Count $.Max}}px"></div>{{.Count}}</div>
+{{end}}
+
+<h2>Publicaciones</h2>
+<ol class="items">
+{{range .T.Items}}<li{{if .Withdrawn}} class="withdrawn"{{end}}><a href="{{.URL}}">{{.Title}}</a><br>
+<span class="meta">{{.Published.Format "2006-01-02 15:04"}} UTC · {{.Source}} · #{{.ID}}</span></li>
+{{end}}</ol>
+{{with .T.Collector}}<p class="meta">Generado por {{.}}</p>
+{{end}}</body>
+</html>
+`))
+
+// WriteHTML exporta la línea de tiempo como página HTML autocontenida, para
+// adjuntarla a los reportes de incidentes.
+func (t *Timeline) WriteHTML(w io.Writer) error {
+	max := 0
+	for _, d := range t.Days {
+		if d.Count > max {
+			max = d.Count
+		}
+	}
+	return htmlTemplate.Execute(w, struct {
+		T   *Timeline
+		Max int
+	}{t, max})
+}
@@ -0,0 +1,108 @@
+package timeline
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"go-collector/article"
+	"go-collector/buildinfo"
+)
+
+// minSpike es el mínimo de publicaciones en un día para considerarlo pico,
+// así un día con 2 notas en una historia pequeña no se marca como pico.
+const minSpike = 3
+
+// Item es una publicación (artículo o tweet) dentro de la línea de tiempo.
+type Item struct {
+	ID        int64     `json:"id"`
+	Published time.Time `json:"published"`
+	Source    string    `json:"source"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Withdrawn bool      `json:"withdrawn,omitempty"`
+}
+
+// Day es el volumen de publicaciones de un día.
+type Day struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+	Spike bool   `json:"spike"`
+}
+
+// Timeline es la cronología de una historia o entidad.
+type Timeline struct {
+	Title string    `json:"title"`
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+	Items []Item    `json:"items"`
+	Days  []Day     `json:"days"`
+	// Collector identifica el recolector que la generó; lo completa quien
+	// exporta.
+	Collector *buildinfo.Stamp `json:"collector,omitempty"`
+}
+
+// Build ordena las publicaciones cronológicamente, cuenta el volumen diario
+// (incluyendo días sin publicaciones) y marca como pico los días que superan
+// la media en más de dos desviaciones estándar.
+func Build(title string, articles []*article.Article) *Timeline {
+	t := &Timeline{Title: title}
+	for _, a := range articles {
+		if a.Published.IsZero() {
+			continue
+		}
+		t.Items = append(t.Items, Item{
+			ID: a.ID, Published: a.Published.UTC(), Source: a.Source, Title: a.Title, URL: a.URL, Withdrawn: a.Withdrawn(),
+		})
+	}
+	if len(t.Items) == 0 {
+		return t
+	}
+	sort.SliceStable(t.Items, func(i, j int) bool { return t.Items[i].Published.Before(t.Items[j].Published) })
+	t.From, t.To = t.Items[0].Published, t.Items[len(t.Items)-1].Published
+
+	counts := make(map[string]int)
+	for _, it := range t.Items {
+		counts[it.Published.Format("2006-01-02")]++
+	}
+	first := t.From.Truncate(24 * time.Hour)
+	for d := first; !d.After(t.To); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		t.Days = append(t.Days, Day{Date: key, Count: counts[key]})
+	}
+
+	mean, std := meanStd(t.Days)
+	for i := range t.Days {
+		c := float64(t.Days[i].Count)
+		t.Days[i].Spike = t.Days[i].Count >= minSpike && c > mean+2*std
+	}
+	return t
+}
+
+// Spikes devuelve los días marcados como pico.
+func (t *Timeline) Spikes() []Day {
+	var out []Day
+	for _, d := range t.Days {
+		if d.Spike {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func meanStd(days []Day) (float64, float64) {
+	if len(days) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, d := range days {
+		sum += float64(d.Count)
+	}
+	mean := sum / float64(len(days))
+	var sq float64
+	for _, d := range days {
+		diff := float64(d.Count) - mean
+		sq += diff * diff
+	}
+	return mean, math.Sqrt(sq / float64(len(days)))
+}
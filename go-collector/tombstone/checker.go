@@ -0,0 +1,155 @@
+package tombstone
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/storage"
+)
+
+// Motivos de retiro que se guardan junto al artículo.
+const (
+	ReasonNotFound  = "http_404"
+	ReasonGone      = "http_410"
+	ReasonRetracted = "retracted"
+	ReasonHomepage  = "redirect_homepage"
+)
+
+// retractionMarkers son frases con las que los medios suelen reemplazar una nota retirada.
+var retractionMarkers = []string{
+	"este artículo ha sido retirado",
+	"esta nota fue retirada",
+	"este contenido ya no está disponible",
+	"this article has been removed",
+	"this article has been withdrawn",
+	"this article was retracted",
+	"this content is no longer available",
+}
+
+// Checker revisa artículos ya recolectados para detectar si el medio los retiró.
+type Checker struct {
+	Client *http.Client
+	Store  *storage.Store
+}
+
+// Result es el resultado de revisar un artículo.
+type Result struct {
+	Article   *article.Article
+	Withdrawn bool
+	Reason    string
+	Err       error
+}
+
+func NewChecker(store *storage.Store) *Checker {
+	return &Checker{
+		Client: &http.Client{
+			Timeout: 20 * time.Second,
+		},
+		Store: store,
+	}
+}
+
+// Check descarga nuevamente la URL del artículo y decide si fue retirado.
+// Errores de red y respuestas 5xx no cuentan como retiro: pueden ser transitorios.
+func (c *Checker) Check(ctx context.Context, a *article.Article) (bool, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", a.URL, nil)
+	if err != nil {
+		return false, "", err
+	}
+	req.Header.Set("User-Agent", "EthicalCrawler/1.0 (StudentResearch)")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return false, "", fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return true, ReasonNotFound, nil
+	case http.StatusGone:
+		return true, ReasonGone, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, "", fmt.Errorf("error HTTP: status code %d", resp.StatusCode)
+	}
+
+	// Una nota que redirige a la portada del medio casi siempre fue despublicada.
+	if redirectedToHomepage(a.URL, resp.Request.URL) {
+		return true, ReasonHomepage, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+	if err != nil {
+		return false, "", fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	text := strings.ToLower(string(body))
+	for _, marker := range retractionMarkers {
+		if strings.Contains(text, marker) {
+			return true, ReasonRetracted, nil
+		}
+	}
+	return false, "", nil
+}
+
+// CheckAll revisa todos los artículos activos y marca como retirados los que correspondan.
+// Los artículos nunca se borran; se conserva la metadata y la fecha de detección.
+// Si se cancela ctx se devuelve lo revisado hasta ese momento junto con el error.
+func (c *Checker) CheckAll(ctx context.Context) ([]Result, error) {
+	articles, err := c.Store.ListActive()
+	if err != nil {
+		return nil, err
+	}
+	return c.CheckArticles(ctx, articles)
+}
+
+// CheckArticles es CheckAll sobre los artículos indicados.
+func (c *Checker) CheckArticles(ctx context.Context, articles []*article.Article) ([]Result, error) {
+	var results []Result
+	for _, a := range articles {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		withdrawn, reason, err := c.Check(ctx, a)
+		res := Result{Article: a, Withdrawn: withdrawn, Reason: reason, Err: err}
+		if withdrawn {
+			if err := c.Store.MarkWithdrawn(a.ID, reason, time.Now().UTC()); err != nil {
+				res.Err = err
+			}
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// ExplorarRetirados muestra un resumen de la revisión.
+func ExplorarRetirados(results []Result) {
+	fmt.Println("\n--- REVISIÓN DE ARTÍCULOS RETIRADOS ---")
+	retirados, fallidos := 0, 0
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			fallidos++
+		case r.Withdrawn:
+			retirados++
+			fmt.Printf("  Retirado (%s): %s\n", r.Reason, r.Article.URL)
+		}
+	}
+	fmt.Printf("\nRevisados: %d | Retirados: %d | Sin verificar (errores): %d\n", len(results), retirados, fallidos)
+}
+
+func redirectedToHomepage(original string, final *url.URL) bool {
+	orig, err := url.Parse(original)
+	if err != nil || final == nil {
+		return false
+	}
+	origPath := strings.Trim(orig.Path, "/")
+	finalPath := strings.Trim(final.Path, "/")
+	return origPath != "" && finalPath == ""
+}
@@ -0,0 +1,174 @@
+// Package trends mantiene, a medida que se guardan artículos, dos puntajes
+// por palabra clave que decaen exponencialmente con el tiempo: uno corto
+// (RecentHalfLife, la última semana) y uno de base (BaselineHalfLife, el
+// último mes). Comparar las dos tasas dice qué términos suben y cuáles bajan
+// sin recorrer el corpus (collector trends, GET /trends).
+package trends
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"go-collector/article"
+	"go-collector/entities"
+	"go-collector/storage"
+)
+
+// Vidas medias de los puntajes: una aparición vale la mitad al cabo de ese
+// lapso. Con la corta, la última semana pesa casi todo el puntaje.
+const (
+	RecentHalfLife   = 84 * time.Hour // 3,5 días
+	BaselineHalfLife = 28 * 24 * time.Hour
+)
+
+// Terms son los términos de un artículo que cuentan para las tendencias: las
+// palabras que pueden ser clave de su título y resumen, una vez cada una.
+func Terms(a *article.Article) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, t := range entities.Terms(a.Title + "\n" + a.Summary) {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// when es el momento del artículo para las tendencias: su publicación o, si
+// no la tiene, su recolección.
+func when(a *article.Article) time.Time {
+	if !a.Published.IsZero() {
+		return a.Published
+	}
+	return a.Collected
+}
+
+// decay es cuánto vale al cabo de d una aparición con vida media half.
+func decay(d, half time.Duration) float64 {
+	if d <= 0 {
+		return 1
+	}
+	return math.Exp2(-float64(d) / float64(half))
+}
+
+// Add suma al puntaje una aparición del término en at. Los puntajes quedan
+// referidos a la aparición más reciente: una anterior (un artículo que llegó
+// tarde) suma ya decaída.
+func Add(t *storage.TrendTerm, at time.Time) {
+	if t.Updated.IsZero() {
+		t.Updated = at
+	}
+	if t.First.IsZero() || at.Before(t.First) {
+		t.First = at
+	}
+	if at.After(t.Updated) {
+		d := at.Sub(t.Updated)
+		t.Recent *= decay(d, RecentHalfLife)
+		t.Baseline *= decay(d, BaselineHalfLife)
+		t.Updated = at
+	}
+	d := t.Updated.Sub(at)
+	t.Recent += decay(d, RecentHalfLife)
+	t.Baseline += decay(d, BaselineHalfLife)
+	t.Articles++
+}
+
+// Ingest suma los términos del artículo guardado a las tendencias; false si
+// ya estaba sumado (un artículo que vuelve en otra ronda no cuenta dos
+// veces).
+func Ingest(store *storage.Store, a *article.Article) (bool, error) {
+	at := when(a)
+	return store.AddTrendArticle(a.ID, Terms(a), func(t *storage.TrendTerm) { Add(t, at) })
+}
+
+// rate convierte un puntaje con vida media half, acumulado durante span, en
+// apariciones por día: con una tasa constante de r por día el puntaje llega
+// a r·half/ln 2·(1 − 2^(−span/half)).
+func rate(score float64, half, span time.Duration) float64 {
+	return score * math.Ln2 / half.Hours() * 24 / (1 - decay(span, half))
+}
+
+// Trend es un término con sus apariciones por día en la última semana y en
+// el último mes (ponderadas por el decaimiento) y el cambio entre ambas: 1
+// es un término estable, más sube y menos baja.
+type Trend struct {
+	Term     string  `json:"term"`
+	Week     float64 `json:"week_per_day"`
+	Baseline float64 `json:"baseline_per_day"`
+	Change   float64 `json:"change"`
+	Articles int     `json:"articles"`
+}
+
+// Options limita los términos de Compute. Los campos en cero toman los
+// valores por defecto.
+type Options struct {
+	// MinScore es el puntaje mínimo, en apariciones ponderadas, del período
+	// en que el término sube (la semana) o baja (el mes); evita que una o dos
+	// menciones sueltas encabecen la lista. Por defecto 3.
+	MinScore float64
+	// Limit es cuántos términos devolver de cada lista. Por defecto 15.
+	Limit int
+}
+
+// Compute devuelve, a now, los términos que más suben y los que más bajan
+// en la última semana frente al último mes.
+func Compute(terms []storage.TrendTerm, now time.Time, opts Options) (rising, falling []Trend) {
+	if opts.MinScore <= 0 {
+		opts.MinScore = 3
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 15
+	}
+	// Con menos historia que la vida media de base, su puntaje no llegó a lo
+	// que sumaría una tasa constante: las tasas se corrigen por el lapso que
+	// cubren las tendencias, si no todo término parecería subir.
+	var start time.Time
+	for _, t := range terms {
+		if start.IsZero() || t.First.Before(start) {
+			start = t.First
+		}
+	}
+	span := max(now.Sub(start), time.Hour)
+
+	for _, t := range terms {
+		d := now.Sub(t.Updated)
+		recent := t.Recent * decay(d, RecentHalfLife)
+		baseline := t.Baseline * decay(d, BaselineHalfLife)
+		if baseline <= 0 {
+			continue
+		}
+		tr := Trend{
+			Term: t.Term, Articles: t.Articles,
+			Week:     rate(recent, RecentHalfLife, span),
+			Baseline: rate(baseline, BaselineHalfLife, span),
+		}
+		tr.Change = tr.Week / tr.Baseline
+		switch {
+		case tr.Change > 1 && recent >= opts.MinScore:
+			rising = append(rising, tr)
+		case tr.Change < 1 && baseline >= opts.MinScore:
+			falling = append(falling, tr)
+		}
+	}
+	sort.Slice(rising, func(i, j int) bool {
+		if rising[i].Change != rising[j].Change {
+			return rising[i].Change > rising[j].Change
+		}
+		return rising[i].Week > rising[j].Week
+	})
+	sort.Slice(falling, func(i, j int) bool {
+		if falling[i].Change != falling[j].Change {
+			return falling[i].Change < falling[j].Change
+		}
+		return falling[i].Baseline > falling[j].Baseline
+	})
+	if len(rising) > opts.Limit {
+		rising = rising[:opts.Limit]
+	}
+	if len(falling) > opts.Limit {
+		falling = falling[:opts.Limit]
+	}
+	return rising, falling
+}
@@ -0,0 +1,155 @@
+// Package unfurl reconoce enlaces a videos de YouTube y Vimeo en los
+// artículos y tweets, y obtiene por oEmbed su título, canal y duración.
+package unfurl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"go-collector/crawler"
+)
+
+// Plataformas reconocidas.
+const (
+	YouTube = "youtube"
+	Vimeo   = "vimeo"
+)
+
+// Link es un enlace a un video, con su URL canónica.
+type Link struct {
+	URL      string // canónica: https://www.youtube.com/watch?v=<id> o https://vimeo.com/<id>
+	Provider string
+	ID       string
+}
+
+var (
+	youtubeRe = regexp.MustCompile(`(?i)(?:https?://)?(?:www\.|m\.|music\.)?(?:youtube\.com/(?:watch\?(?:[^\s"'<>]*&)?v=|shorts/|embed/|live/)|youtu\.be/)([A-Za-z0-9_-]{11})`)
+	vimeoRe   = regexp.MustCompile(`(?i)(?:https?://)?(?:www\.|player\.)?vimeo\.com/(?:video/)?(\d{6,})`)
+	// durationRe es la duración que la página de YouTube publica en sus
+	// metadatos: oEmbed no la informa.
+	durationRe = regexp.MustCompile(`itemprop="duration" content="(PT[0-9HMS.]+)"`)
+	isoRe      = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?$`)
+)
+
+// Find devuelve los videos enlazados en los textos, sin repetir.
+func Find(texts ...string) []Link {
+	var out []Link
+	seen := make(map[string]bool)
+	add := func(l Link) {
+		if !seen[l.URL] {
+			seen[l.URL] = true
+			out = append(out, l)
+		}
+	}
+	for _, text := range texts {
+		for _, m := range youtubeRe.FindAllStringSubmatch(text, -1) {
+			add(Link{URL: "https://www.youtube.com/watch?v=" + m[1], Provider: YouTube, ID: m[1]})
+		}
+		for _, m := range vimeoRe.FindAllStringSubmatch(text, -1) {
+			add(Link{URL: "https://vimeo.com/" + m[1], Provider: Vimeo, ID: m[1]})
+		}
+	}
+	return out
+}
+
+// Video son los datos de un video.
+type Video struct {
+	Title      string
+	Channel    string
+	ChannelURL string
+	Thumbnail  string
+	Duration   time.Duration // cero si no se pudo obtener
+}
+
+// Client consulta los endpoints oEmbed.
+type Client struct {
+	HTTP *http.Client
+	// Endpoints oEmbed por plataforma; se pueden reemplazar para pruebas.
+	YouTubeEndpoint string
+	VimeoEndpoint   string
+}
+
+// NewClient crea un cliente con los endpoints públicos de cada plataforma.
+func NewClient() *Client {
+	return &Client{
+		HTTP:            &http.Client{Timeout: 20 * time.Second},
+		YouTubeEndpoint: "https://www.youtube.com/oembed",
+		VimeoEndpoint:   "https://vimeo.com/api/oembed.json",
+	}
+}
+
+// oembed es la respuesta oEmbed; duration solo la informa Vimeo.
+type oembed struct {
+	Title        string  `json:"title"`
+	AuthorName   string  `json:"author_name"`
+	AuthorURL    string  `json:"author_url"`
+	ThumbnailURL string  `json:"thumbnail_url"`
+	Duration     float64 `json:"duration"`
+}
+
+// Unfurl obtiene los datos del video. La duración de YouTube se lee de la
+// página del video; si no aparece queda en cero, sin error.
+func (c *Client) Unfurl(ctx context.Context, l Link) (*Video, error) {
+	endpoint := c.YouTubeEndpoint
+	if l.Provider == Vimeo {
+		endpoint = c.VimeoEndpoint
+	}
+	body, err := c.get(ctx, endpoint+"?format=json&url="+url.QueryEscape(l.URL))
+	if err != nil {
+		return nil, err
+	}
+	var o oembed
+	if err := json.Unmarshal(body, &o); err != nil {
+		return nil, fmt.Errorf("error decodificando oEmbed de %s: %w", l.URL, err)
+	}
+	v := &Video{
+		Title: o.Title, Channel: o.AuthorName, ChannelURL: o.AuthorURL,
+		Thumbnail: o.ThumbnailURL, Duration: time.Duration(o.Duration * float64(time.Second)),
+	}
+	if l.Provider == YouTube {
+		if page, err := c.get(ctx, l.URL); err == nil {
+			if m := durationRe.FindSubmatch(page); m != nil {
+				v.Duration = ParseISODuration(string(m[1]))
+			}
+		}
+	}
+	return v, nil
+}
+
+func (c *Client) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// 401/403/404: el video es privado, se borró o no admite inserción.
+		return nil, fmt.Errorf("error consultando %s: http %d", u, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+}
+
+// ParseISODuration interpreta una duración ISO 8601 de horas, minutos y
+// segundos (PT1H2M3S); cero si no tiene ese formato.
+func ParseISODuration(s string) time.Duration {
+	m := isoRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	h, _ := strconv.Atoi(m[1])
+	min, _ := strconv.Atoi(m[2])
+	sec, _ := strconv.ParseFloat(m[3], 64)
+	return time.Duration(h)*time.Hour + time.Duration(min)*time.Minute + time.Duration(sec*float64(time.Second))
+}
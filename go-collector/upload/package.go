@@ -0,0 +1,154 @@
+package upload
+
+import (
+	"archive/zip"
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-collector/article"
+	"go-collector/buildinfo"
+	"go-collector/config"
+	"go-collector/export"
+	"go-collector/storage"
+)
+
+// Package es el paquete de una exportación, listo para subir.
+type Package struct {
+	Path     string // archivo local
+	Name     string // nombre en el servidor: <exportación>-<fecha>.zip
+	Articles int
+	// Excluded y Redacted cuentan lo que los términos de uso dejaron fuera
+	// (ver export.Manifest).
+	Excluded int
+	Redacted int
+	Size     int64
+	SHA256   string
+}
+
+// Build arma en dir el paquete de la exportación e: un zip con los artículos
+// en su formato (<exportación>.<formato>) y manifest.json, el mismo
+// manifiesto que collector export deja junto al archivo. Como collector
+// export, aplica los términos de uso registrados en el corpus.
+func Build(store *storage.Store, e config.Export, stamp buildinfo.Stamp, now time.Time, dir string) (*Package, error) {
+	format := e.Format
+	if format == "" {
+		format = "jsonl"
+	}
+	from, err := e.PeriodStart(now)
+	if err != nil {
+		return nil, fmt.Errorf("exportación %s: %w", e.Name, err)
+	}
+	filter := storage.Filter{
+		From:     from,
+		Sources:  e.Filters.Sources,
+		Language: e.Filters.Language,
+		Query:    e.Filters.Query,
+	}
+	if !from.IsZero() {
+		filter.To = now
+	}
+
+	pkg := &Package{Name: fmt.Sprintf("%s-%s.zip", e.Name, now.Format("2006-01-02"))}
+	pkg.Path = filepath.Join(dir, pkg.Name)
+	f, err := os.Create(pkg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error creando el paquete: %w", err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	data := e.Name + "." + format
+	zf, err := zw.CreateHeader(&zip.FileHeader{Name: data, Method: zip.Deflate, Modified: now})
+	if err != nil {
+		return nil, err
+	}
+	buf := bufio.NewWriter(zf)
+	w, err := export.New(format, buf, export.Options{})
+	if err != nil {
+		return nil, err
+	}
+	sharing, err := store.EachShareable(filter, func(a *article.Article) error {
+		pkg.Articles++
+		return w.Write(a)
+	})
+	pkg.Excluded, pkg.Redacted = sharing.Excluded, sharing.Redacted
+	if err == nil {
+		err = w.Close()
+	}
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error exportando: %w", err)
+	}
+
+	m, err := json.MarshalIndent(&export.Manifest{
+		File:      data,
+		Format:    format,
+		Created:   now.UTC(),
+		Articles:  pkg.Articles,
+		Excluded:  pkg.Excluded,
+		Redacted:  pkg.Redacted,
+		Filter:    filterFlags(filter),
+		Collector: stamp,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	mf, err := zw.CreateHeader(&zip.FileHeader{Name: "manifest.json", Method: zip.Deflate, Modified: now})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := mf.Write(append(m, '\n')); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("error escribiendo el paquete: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("error escribiendo el paquete: %w", err)
+	}
+	return pkg, pkg.checksum()
+}
+
+// checksum completa el tamaño y el hash del paquete, que van en el aviso
+// para que el receptor verifique la copia.
+func (p *Package) checksum() error {
+	f, err := os.Open(p.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if p.Size, err = io.Copy(h, f); err != nil {
+		return fmt.Errorf("error leyendo el paquete: %w", err)
+	}
+	p.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return nil
+}
+
+// filterFlags describe el filtro con los flags equivalentes de collector
+// export, como en su manifiesto.
+func filterFlags(f storage.Filter) []string {
+	var out []string
+	if !f.From.IsZero() {
+		out = append(out, "--from="+f.From.Format("2006-01-02"), "--to="+f.To.Format("2006-01-02"))
+	}
+	if len(f.Sources) > 0 {
+		out = append(out, "--source="+strings.Join(f.Sources, ","))
+	}
+	if f.Language != "" {
+		out = append(out, "--lang="+f.Language)
+	}
+	if f.Query != "" {
+		out = append(out, "--query="+f.Query)
+	}
+	return out
+}
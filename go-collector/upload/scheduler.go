@@ -0,0 +1,193 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"go-collector/buildinfo"
+	"go-collector/config"
+	"go-collector/schedule"
+	"go-collector/storage"
+)
+
+// Scheduler ejecuta las exportaciones según su horario, como report.Scheduler
+// los reportes: la última ejecución exitosa queda en la base para no repetir
+// subidas si el proceso se reinicia, y las exportaciones y el servidor SMTP
+// se leen de Config en cada revisión.
+type Scheduler struct {
+	Store  *storage.Store
+	Config *config.Live
+}
+
+// Entry es una exportación con su horario interpretado.
+type Entry struct {
+	Export  config.Export
+	Cron    *schedule.Cron
+	LastRun time.Time
+	NextRun time.Time
+}
+
+// Entries interpreta los horarios y calcula la próxima ejecución de cada
+// exportación.
+func (s *Scheduler) Entries(now time.Time) ([]Entry, error) {
+	var out []Entry
+	for _, e := range s.Config.Get().Exports {
+		c, err := schedule.Parse(e.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("exportación %s: %w", e.Name, err)
+		}
+		last, err := s.Store.LastExportRun(e.Name)
+		if err != nil {
+			return nil, err
+		}
+		entry := Entry{Export: e, Cron: c, LastRun: last}
+		if last.IsZero() {
+			entry.NextRun = c.Next(now)
+		} else {
+			entry.NextRun = c.Next(last.In(now.Location()))
+		}
+		out = append(out, entry)
+	}
+	return out, nil
+}
+
+// RunDue ejecuta las exportaciones cuya próxima ejecución ya pasó. Las que
+// nunca corrieron se registran para que la primera sea en el siguiente
+// horario y no de inmediato.
+func (s *Scheduler) RunDue(ctx context.Context, now time.Time) error {
+	entries, err := s.Entries(now)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if e.LastRun.IsZero() {
+			if err := s.Store.RecordExportRun(e.Export.Name, now, "ok", "registrada por el daemon"); err != nil {
+				return err
+			}
+			continue
+		}
+		if e.NextRun.After(now) {
+			continue
+		}
+		if err := s.Run(ctx, e.Export, now); err != nil {
+			log.Printf("error en exportación %s: %v", e.Export.Name, err)
+		}
+	}
+	return nil
+}
+
+// Run arma el paquete, lo sube con reintentos y avisa a Notify, registrando
+// el resultado. Un aviso que no se pudo enviar no invalida la subida: se
+// informa en el log.
+func (s *Scheduler) Run(ctx context.Context, e config.Export, now time.Time) error {
+	cfg := s.Config.Get()
+	remote, pkg, err := s.send(ctx, e, cfg, now)
+	if ctx.Err() != nil {
+		// Cancelado: no es un fallo de la exportación, se reintenta en la
+		// próxima revisión.
+		return ctx.Err()
+	}
+	status, detail := "ok", ""
+	if err != nil {
+		status, detail = "error", err.Error()
+	} else {
+		detail = fmt.Sprintf("%s (%d artículos)", remote, pkg.Articles)
+		if nerr := notify(cfg.SMTP, e, remote, pkg, now); nerr != nil {
+			log.Printf("exportación %s: error enviando el aviso: %v", e.Name, nerr)
+		}
+	}
+	if rerr := s.Store.RecordExportRun(e.Name, now, status, detail); rerr != nil {
+		return rerr
+	}
+	return err
+}
+
+// send arma el paquete en un directorio temporal y lo sube.
+func (s *Scheduler) send(ctx context.Context, e config.Export, cfg *config.Config, now time.Time) (string, *Package, error) {
+	up, err := New(e.Upload)
+	if err != nil {
+		return "", nil, err
+	}
+	dir, err := os.MkdirTemp("", "collector-export-")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.RemoveAll(dir)
+	pkg, err := Build(s.Store, e, buildinfo.NewStamp(cfg.Hash()), now, dir)
+	if err != nil {
+		return "", nil, err
+	}
+	remote, err := Send(ctx, up, pkg.Path, pkg.Name, e.Upload.Retries)
+	return remote, pkg, err
+}
+
+// Daemon revisa cada minuto qué exportaciones corresponden, hasta que se
+// cancele ctx.
+func (s *Scheduler) Daemon(ctx context.Context) error {
+	if err := s.RunDue(ctx, time.Now()); err != nil {
+		return err
+	}
+	if err := s.LogEntries(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			if err := s.RunDue(ctx, now); err != nil {
+				log.Printf("error revisando exportaciones: %v", err)
+			}
+		}
+	}
+}
+
+// LogEntries registra en el log la próxima ejecución de cada exportación.
+func (s *Scheduler) LogEntries() error {
+	entries, err := s.Entries(time.Now())
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		log.Printf("exportación %s (%s): próxima ejecución %s", e.Export.Name, e.Cron, e.NextRun.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+// notify avisa por correo a los destinatarios de la exportación que el
+// paquete llegó, con su tamaño y hash para verificarlo.
+func notify(cfg config.SMTP, e config.Export, remote string, pkg *Package, now time.Time) error {
+	if len(e.Notify) == 0 {
+		return nil
+	}
+	if cfg.Host == "" || cfg.From == "" {
+		return fmt.Errorf("falta configurar smtp.host y smtp.from")
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+	subject := fmt.Sprintf("Exportación %s - %s", e.Name, now.Format("2006-01-02"))
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n",
+		cfg.From, strings.Join(e.Notify, ", "), mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Se subió el paquete %s.\r\n\r\nArtículos: %d\r\nTamaño: %d bytes\r\nSHA-256: %s\r\n", remote, pkg.Articles, pkg.Size, pkg.SHA256)
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return smtp.SendMail(fmt.Sprintf("%s:%d", cfg.Host, port), auth, cfg.From, e.Notify, []byte(msg.String()))
+}
@@ -0,0 +1,79 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// SFTP sube con el cliente sftp de OpenSSH en modo batch: se autentica con
+// clave (KeyFile, el agente o ~/.ssh) y verifica el host contra known_hosts,
+// sin pedir nada por la terminal.
+type SFTP struct {
+	Command string // por defecto sftp
+	User    string
+	Host    string
+	Port    string
+	Dir     string
+	KeyFile string
+}
+
+// NewSFTP arma el destino de una URL sftp://usuario@host[:puerto]/directorio.
+func NewSFTP(u *url.URL, keyFile string) *SFTP {
+	s := &SFTP{Command: "sftp", Host: u.Hostname(), Port: u.Port(), Dir: u.Path, KeyFile: keyFile}
+	if u.User != nil {
+		s.User = u.User.Username()
+	}
+	if s.Dir == "" {
+		s.Dir = "."
+	}
+	return s
+}
+
+// Upload crea el directorio si falta, sube el archivo como name.part y lo
+// renombra a name (reemplazando uno anterior del mismo nombre).
+func (s *SFTP) Upload(ctx context.Context, local, name string) (string, error) {
+	args := []string{"-b", "-", "-o", "BatchMode=yes"}
+	if s.Port != "" {
+		args = append(args, "-P", s.Port)
+	}
+	if s.KeyFile != "" {
+		args = append(args, "-i", s.KeyFile)
+	}
+	args = append(args, s.User+"@"+s.Host)
+
+	remote := path.Join(s.Dir, name)
+	// El prefijo - ignora el error del comando: el directorio puede existir
+	// y el archivo no.
+	batch := fmt.Sprintf("-mkdir %s\nput %s %s\n-rm %s\nrename %s %s\n",
+		quote(s.Dir), quote(local), quote(remote+".part"), quote(remote), quote(remote+".part"), quote(remote))
+	cmd := exec.CommandContext(ctx, s.Command, args...)
+	cmd.Stdin = strings.NewReader(batch)
+	var out bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if msg := lastLine(out.String()); msg != "" {
+			return "", fmt.Errorf("error en sftp: %w (%s)", err, msg)
+		}
+		return "", fmt.Errorf("error en sftp: %w", err)
+	}
+	return fmt.Sprintf("sftp://%s@%s%s", s.User, s.Host, path.Join("/", remote)), nil
+}
+
+// quote encierra una ruta entre comillas para el modo batch de sftp.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// lastLine es la última línea no vacía de la salida: la del error.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
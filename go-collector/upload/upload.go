@@ -0,0 +1,70 @@
+// Package upload entrega exportaciones programadas del corpus al servidor de
+// otro equipo (ej: el archivo de la universidad): arma el paquete, lo sube
+// por SFTP o WebDAV con reintentos y avisa por correo cuando llegó.
+//
+// La subida se hace con un nombre temporal (.part) que se renombra al final,
+// así el receptor nunca ve un paquete a medio subir.
+package upload
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go-collector/config"
+	"go-collector/crawler"
+)
+
+const (
+	// defaultRetries es cuántas veces se reintenta una subida fallida.
+	defaultRetries = 3
+	// retryDelay es la espera antes del primer reintento; se duplica en
+	// cada uno.
+	retryDelay = 30 * time.Second
+)
+
+// Uploader deja un archivo local en el directorio remoto con el nombre
+// dado y devuelve su ubicación.
+type Uploader interface {
+	Upload(ctx context.Context, local, name string) (string, error)
+}
+
+// New arma el Uploader del destino según el esquema de su URL.
+func New(cfg config.Upload) (Uploader, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("URL de destino inválida: %q", cfg.URL)
+	}
+	switch u.Scheme {
+	case "sftp":
+		return NewSFTP(u, cfg.KeyFile), nil
+	case "https", "http":
+		return NewWebDAV(u, cfg.Username, cfg.PasswordEnv)
+	default:
+		return nil, fmt.Errorf("esquema de destino desconocido: %s (use sftp o https)", u.Scheme)
+	}
+}
+
+// Send sube el archivo con hasta retries reintentos (0 usa defaultRetries),
+// esperando retryDelay antes del primero y el doble antes de cada siguiente.
+// Devuelve la ubicación remota, o el error del último intento.
+func Send(ctx context.Context, up Uploader, local, name string, retries int) (string, error) {
+	if retries <= 0 {
+		retries = defaultRetries
+	}
+	delay := retryDelay
+	for attempt := 0; ; attempt++ {
+		remote, err := up.Upload(ctx, local, name)
+		if err == nil {
+			return remote, nil
+		}
+		if ctx.Err() != nil || attempt == retries {
+			return "", fmt.Errorf("error subiendo %s (%d intentos): %w", name, attempt+1, err)
+		}
+		if err := crawler.Sleep(ctx, delay); err != nil {
+			return "", err
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,133 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go-collector/crawler"
+)
+
+// WebDAV sube con PUT al directorio de la URL y renombra con MOVE.
+type WebDAV struct {
+	Client   *http.Client
+	Dir      *url.URL
+	Username string
+	Password string
+}
+
+// NewWebDAV arma el destino del directorio u; la contraseña se lee de la
+// variable passwordEnv.
+func NewWebDAV(u *url.URL, username, passwordEnv string) (*WebDAV, error) {
+	d := &WebDAV{Client: &http.Client{Timeout: 30 * time.Minute}, Dir: u, Username: username}
+	if passwordEnv != "" {
+		if d.Password = os.Getenv(passwordEnv); d.Password == "" {
+			return nil, fmt.Errorf("el destino WebDAV requiere credencial: defina %s", passwordEnv)
+		}
+	}
+	if !strings.HasSuffix(d.Dir.Path, "/") {
+		dir := *d.Dir
+		dir.Path += "/"
+		d.Dir = &dir
+	}
+	return d, nil
+}
+
+// Upload sube el archivo como name.part y lo mueve a name, reemplazando uno
+// anterior. Si el directorio no existe (409) lo crea con MKCOL.
+func (d *WebDAV) Upload(ctx context.Context, local, name string) (string, error) {
+	target := d.Dir.JoinPath(name).String()
+	part := target + ".part"
+	status, err := d.put(ctx, local, part)
+	if err == nil && status == http.StatusConflict {
+		if err = d.mkcol(ctx); err == nil {
+			status, err = d.put(ctx, local, part)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusCreated && status != http.StatusNoContent && status != http.StatusOK {
+		return "", fmt.Errorf("error HTTP: status code %d subiendo %s", status, part)
+	}
+
+	req, err := d.request(ctx, "MOVE", part, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Destination", target)
+	req.Header.Set("Overwrite", "T")
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error en petición: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return "", fmt.Errorf("error HTTP: status code %d renombrando %s", resp.StatusCode, part)
+	}
+	return target, nil
+}
+
+// put sube el archivo a dst y devuelve el estado de la respuesta.
+func (d *WebDAV) put(ctx context.Context, local, dst string) (int, error) {
+	f, err := os.Open(local)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	req, err := d.request(ctx, "PUT", dst, f)
+	if err != nil {
+		return 0, err
+	}
+	req.ContentLength = st.Size()
+	req.Header.Set("Content-Type", "application/zip")
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error en petición: %w", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// mkcol crea el directorio de destino (no sus padres).
+func (d *WebDAV) mkcol(ctx context.Context) error {
+	req, err := d.request(ctx, "MKCOL", d.Dir.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error en petición: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusMethodNotAllowed {
+		return fmt.Errorf("error HTTP: status code %d creando %s", resp.StatusCode, d.Dir)
+	}
+	return nil
+}
+
+func (d *WebDAV) request(ctx context.Context, method, target string, body *os.File) (*http.Request, error) {
+	var req *http.Request
+	var err error
+	if body != nil {
+		req, err = http.NewRequestWithContext(ctx, method, target, body)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, method, target, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	if d.Username != "" {
+		req.SetBasicAuth(d.Username, d.Password)
+	}
+	return req, nil
+}
@@ -0,0 +1,130 @@
+// Package wayback consulta en el Internet Archive si una página está
+// archivada (API CDX) y pide archivarla con Save Page Now, para que el corpus
+// se pueda verificar aunque el medio retire la nota.
+package wayback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"go-collector/crawler"
+)
+
+// timestampLayout es el formato de las marcas de tiempo del archivo
+// (20231005120000, en UTC).
+const timestampLayout = "20060102150405"
+
+// Snapshot es una captura de una página en el archivo.
+type Snapshot struct {
+	URL       string // https://web.archive.org/web/<marca>/<url original>
+	Timestamp time.Time
+}
+
+// Client consulta la API CDX y Save Page Now.
+type Client struct {
+	HTTP *http.Client
+	// Endpoints del archivo; se pueden reemplazar para pruebas.
+	CDXEndpoint  string
+	SaveEndpoint string
+	WebPrefix    string
+}
+
+// NewClient crea un cliente con los endpoints públicos del Internet Archive.
+// Save Page Now tarda: el plazo es más largo que el de otras consultas.
+func NewClient() *Client {
+	return &Client{
+		HTTP:         &http.Client{Timeout: 90 * time.Second},
+		CDXEndpoint:  "https://web.archive.org/cdx/search/cdx",
+		SaveEndpoint: "https://web.archive.org/save/",
+		WebPrefix:    "https://web.archive.org/web/",
+	}
+}
+
+// Latest devuelve la captura más reciente de la página que respondió 200, o
+// nil si no está archivada.
+func (c *Client) Latest(ctx context.Context, pageURL string) (*Snapshot, error) {
+	params := url.Values{}
+	params.Set("url", pageURL)
+	params.Set("output", "json")
+	params.Set("fl", "timestamp,original")
+	params.Set("filter", "statuscode:200")
+	// limit=-1 pide la última captura; fastLatest evita recorrer el índice.
+	params.Set("limit", "-1")
+	params.Set("fastLatest", "true")
+	req, err := http.NewRequestWithContext(ctx, "GET", c.CDXEndpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error de la API CDX (HTTP %d): %s", resp.StatusCode, crawler.Preview(body))
+	}
+	// La respuesta es una tabla: la primera fila son los nombres de las
+	// columnas. Sin capturas llega vacía ([] o nada).
+	var rows [][]string
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &rows); err != nil {
+			return nil, fmt.Errorf("error parseando JSON: %w. Respuesta recibida:\n%s", err, crawler.Preview(body))
+		}
+	}
+	if len(rows) < 2 || len(rows[len(rows)-1]) < 2 {
+		return nil, nil
+	}
+	last := rows[len(rows)-1]
+	return c.snapshot(last[0], last[1]), nil
+}
+
+// webPathRe reconoce la marca de tiempo en la dirección de una captura.
+var webPathRe = regexp.MustCompile(`/web/(\d{14})/`)
+
+// Save pide al archivo que capture la página ahora. Si la respuesta dice qué
+// captura se hizo la devuelve; si no (la captura sigue en curso), devuelve
+// nil sin error: el pedido quedó hecho y una consulta posterior la mostrará.
+// El archivo limita los pedidos anónimos; un 429 se devuelve como error.
+func (c *Client) Save(ctx context.Context, pageURL string) (*Snapshot, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.SaveEndpoint+pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", crawler.UserAgent)
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error en petición: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error de Save Page Now (HTTP %d): %s", resp.StatusCode, crawler.Preview(body))
+	}
+	// La captura aparece en Content-Location o en la dirección final tras
+	// las redirecciones, según la versión del servicio.
+	for _, loc := range []string{resp.Header.Get("Content-Location"), resp.Request.URL.Path} {
+		if m := webPathRe.FindStringSubmatch(loc); m != nil {
+			return c.snapshot(m[1], pageURL), nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) snapshot(timestamp, original string) *Snapshot {
+	s := &Snapshot{URL: c.WebPrefix + timestamp + "/" + original}
+	if t, err := time.Parse(timestampLayout, timestamp); err == nil {
+		s.Timestamp = t
+	}
+	return s
+}